@@ -11,6 +11,7 @@ import (
 	"sort"
 
 	"go-hep.org/x/hep/fastjet"
+	"go-hep.org/x/hep/fastjet/cone"
 	"go-hep.org/x/hep/fmom"
 	"go-hep.org/x/hep/fwk"
 )
@@ -25,6 +26,7 @@ type FastJetFinder struct {
 
 	jetDef           fastjet.JetDefinition
 	jetAlg           fastjet.JetAlgorithm
+	plugin           string
 	paramR           float64
 	jetPtMin         float64
 	coneRadius       float64
@@ -72,19 +74,48 @@ func (tsk *FastJetFinder) Configure(ctx fwk.Context) error {
 		return err
 	}
 
-	if tsk.jetAlg != fastjet.AntiKtAlgorithm {
-		return fmt.Errorf("fastjet-finder: only implemented for AntiKt")
-	}
-
 	if tsk.areaAlg != 0 {
 		return fmt.Errorf("fastjet-finder: only implemented with *NO* area-definition")
 	}
 
-	tsk.jetDef = fastjet.NewJetDefinition(tsk.jetAlg, tsk.paramR, fastjet.EScheme, fastjet.BestStrategy)
+	switch tsk.jetAlg {
+	case fastjet.AntiKtAlgorithm:
+		tsk.jetDef = fastjet.NewJetDefinition(tsk.jetAlg, tsk.paramR, fastjet.EScheme, fastjet.BestStrategy)
+	case fastjet.PluginAlgorithm:
+		plugin, err := tsk.newPlugin()
+		if err != nil {
+			return err
+		}
+		tsk.jetDef = fastjet.NewJetDefinitionPlugin(plugin)
+	default:
+		return fmt.Errorf("fastjet-finder: only implemented for AntiKt and plugins")
+	}
 
 	return err
 }
 
+// newPlugin returns the jet plugin named by the Plugin property.
+// The cone plugins are configured with the cone properties of the task.
+func (tsk *FastJetFinder) newPlugin() (fastjet.Plugin, error) {
+	switch tsk.plugin {
+	case "SISCone":
+		return cone.NewSISCone(tsk.coneRadius, tsk.overlapThreshold, tsk.maxIters, tsk.jetPtMin), nil
+	case "CDFMidPoint":
+		return cone.NewCDFMidPoint(
+			tsk.coneRadius, tsk.overlapThreshold, tsk.seedThreshold,
+			tsk.coneAreaFraction, tsk.maxPairSize, tsk.maxIters,
+		), nil
+	case "ATLASCone":
+		return cone.NewATLASCone(tsk.coneRadius, tsk.overlapThreshold, tsk.seedThreshold), nil
+	default:
+		plugin, err := fastjet.GetPlugin(tsk.plugin)
+		if err != nil {
+			return nil, fmt.Errorf("fastjet-finder: could not find plugin: %w", err)
+		}
+		return plugin, nil
+	}
+}
+
 func (tsk *FastJetFinder) StartTask(ctx fwk.Context) error {
 	var err error
 
@@ -212,6 +243,7 @@ func newFastJetFinder(typ, name string, mgr fwk.App) (fwk.Component, error) {
 		rho:    "/fads/fastjet/rho",
 
 		jetAlg:           fastjet.AntiKtAlgorithm,
+		plugin:           "SISCone",
 		paramR:           0.5,
 		jetPtMin:         10.0,
 		coneRadius:       0.5,
@@ -261,6 +293,11 @@ func newFastJetFinder(typ, name string, mgr fwk.App) (fwk.Component, error) {
 		return nil, err
 	}
 
+	err = tsk.DeclProp("Plugin", &tsk.plugin)
+	if err != nil {
+		return nil, err
+	}
+
 	err = tsk.DeclProp("ParameterR", &tsk.paramR)
 	if err != nil {
 		return nil, err
@@ -5,16 +5,12 @@
 package fastjet_test
 
 import (
-	"bufio"
-	"fmt"
 	"math"
-	"os"
 	"sort"
-	"strconv"
-	"strings"
 	"testing"
 
 	"go-hep.org/x/hep/fastjet"
+	"go-hep.org/x/hep/fastjet/internal/fjtest"
 	"gonum.org/v1/gonum/floats"
 )
 
@@ -250,7 +246,7 @@ func TestInclusiveJetAlgorithms(t *testing.T) {
 			t.Parallel()
 
 			test := test
-			particles, err := loadParticles(test.input)
+			particles, err := fjtest.LoadParticles(test.input)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -267,7 +263,7 @@ func TestInclusiveJetAlgorithms(t *testing.T) {
 
 			sort.Sort(fastjet.ByPt(jets))
 
-			want, err := loadRef("testdata/" + test.name + ".ref")
+			want, err := fjtest.LoadRef("testdata/" + test.name + ".ref")
 			if err != nil {
 				t.Fatalf("error reading reference file: %v", err)
 			}
@@ -318,7 +314,7 @@ func TestExclusiveJetAlgorithms(t *testing.T) {
 			t.Parallel()
 
 			test := test
-			particles, err := loadParticles(test.input)
+			particles, err := fjtest.LoadParticles(test.input)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -335,7 +331,7 @@ func TestExclusiveJetAlgorithms(t *testing.T) {
 
 			sort.Sort(fastjet.ByPt(jets))
 
-			want, err := loadRef("testdata/" + test.name + ".ref")
+			want, err := fjtest.LoadRef("testdata/" + test.name + ".ref")
 			if err != nil {
 				t.Fatalf("error reading reference file: %v", err)
 			}
@@ -364,74 +360,6 @@ func TestExclusiveJetAlgorithms(t *testing.T) {
 	}
 }
 
-func loadParticles(name string) ([]fastjet.Jet, error) {
-	f, err := os.Open(name)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	var particles []fastjet.Jet
-	scan := bufio.NewScanner(f)
-	for scan.Scan() {
-		txt := scan.Text()
-		toks := make([]string, 0, 4)
-		for _, tok := range strings.Split(txt, " ") {
-			if tok != "" {
-				toks = append(toks, tok)
-			}
-		}
-		px, err := strconv.ParseFloat(toks[0], 64)
-		if err != nil {
-			return nil, err
-		}
-		py, err := strconv.ParseFloat(toks[1], 64)
-		if err != nil {
-			return nil, err
-		}
-		pz, err := strconv.ParseFloat(toks[2], 64)
-		if err != nil {
-			return nil, err
-		}
-		e, err := strconv.ParseFloat(toks[3], 64)
-		if err != nil {
-			return nil, err
-		}
-		particles = append(particles, fastjet.NewJet(px, py, pz, e))
-	}
-	err = scan.Err()
-	if err != nil {
-		return nil, err
-	}
-
-	return particles, nil
-}
-
-func loadRef(name string) ([][3]float64, error) {
-	f, err := os.Open(name)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	var refs [][3]float64
-	scan := bufio.NewScanner(f)
-	for scan.Scan() {
-		var i int
-		var ref [3]float64
-		_, err = fmt.Sscanf(scan.Text(), "%5d %f %f %f", &i, &ref[0], &ref[1], &ref[2])
-		if err != nil {
-			return nil, err
-		}
-		refs = append(refs, ref)
-	}
-	err = scan.Err()
-	if err != nil {
-		return nil, err
-	}
-	return refs, nil
-}
-
 const twoPi = 2 * math.Pi
 
 func angle0to2Pi(v float64) float64 {
@@ -4,4 +4,230 @@
 
 package fastjet
 
-type AreaDefinition struct{}
+import (
+	"fmt"
+	"math"
+
+	"go-hep.org/x/hep/fmom"
+	"golang.org/x/exp/rand"
+)
+
+// AreaType defines the kind of area computed for jets.
+type AreaType int
+
+const (
+	InvalidArea AreaType = iota
+
+	// ActiveArea computes jet areas from the number of ghosts (infinitely
+	// soft particles) clustered into each jet, when a dense coverage of
+	// ghosts is added to the event.
+	ActiveArea
+
+	// ActiveAreaExplicitGhosts is the same as ActiveArea, but the ghosts
+	// are kept in the clustering and are visible as jets constituents.
+	// Pure ghost jets are also returned.
+	ActiveAreaExplicitGhosts
+
+	// OneGhostPassiveArea computes jet areas by adding ghosts one at a time
+	// to the event, and recording which jet they end up in.
+	OneGhostPassiveArea
+
+	// PassiveArea computes passive jet areas: for the kt algorithm, the
+	// passive area is the Voronoi area. For other algorithms, the passive
+	// area is computed as OneGhostPassiveArea does.
+	PassiveArea
+
+	// VoronoiArea computes jet areas as the sum of the areas of the Voronoi
+	// cells of their constituents, intersected with circles of radius
+	// R times the effective R factor.
+	VoronoiArea
+)
+
+func (t AreaType) String() string {
+	switch t {
+	case InvalidArea:
+		return "invalid"
+	case ActiveArea:
+		return "active area"
+	case ActiveAreaExplicitGhosts:
+		return "active area (explicit ghosts)"
+	case OneGhostPassiveArea:
+		return "passive area (one ghost)"
+	case PassiveArea:
+		return "passive area"
+	case VoronoiArea:
+		return "Voronoi area"
+	default:
+		panic(fmt.Errorf("fastjet: invalid AreaType (%d)", int(t)))
+	}
+}
+
+// GhostedAreaSpec describes how ghosts are laid out for ghost-based
+// area computations.
+//
+// Ghosts are placed on a grid in rapidity and azimuth, with rapidities
+// between -GhostMaxRap and +GhostMaxRap, and cells of area GhostArea.
+// Their positions and transverse momenta are randomly scattered around
+// the grid values and around MeanGhostPt.
+type GhostedAreaSpec struct {
+	GhostMaxRap float64 // maximum rapidity of the ghosts
+	Repeat      int     // number of times the ghosted clustering is repeated
+	GhostArea   float64 // area of each ghost
+	GridScatter float64 // fractional random fluctuation of the ghosts positions
+	PtScatter   float64 // fractional random fluctuation of the ghosts pt
+	MeanGhostPt float64 // mean transverse momentum of the ghosts
+	Seed        uint64  // seed of the random numbers generator
+}
+
+// NewGhostedAreaSpec returns a new ghosted area specification with ghosts
+// up to the provided maximum rapidity, and the same defaults than FastJet.
+func NewGhostedAreaSpec(maxrap float64) GhostedAreaSpec {
+	return GhostedAreaSpec{
+		GhostMaxRap: maxrap,
+		Repeat:      1,
+		GhostArea:   0.01,
+		GridScatter: 1.0,
+		PtScatter:   0.1,
+		MeanGhostPt: 1e-100,
+		Seed:        1,
+	}
+}
+
+// grid returns the number of ghosts rows in rapidity (on each side of 0),
+// the number of ghosts columns in azimuth, and the actual extent of each
+// ghost cell.
+func (spec GhostedAreaSpec) grid() (nrap, nphi int, drap, dphi float64) {
+	drap = math.Sqrt(spec.GhostArea)
+	dphi = drap
+	nphi = int(math.Ceil(2 * math.Pi / dphi))
+	dphi = 2 * math.Pi / float64(nphi)
+	nrap = int(math.Ceil(spec.GhostMaxRap / drap))
+	drap = spec.GhostMaxRap / float64(nrap)
+	return nrap, nphi, drap, dphi
+}
+
+// ActualGhostArea returns the actual area of each ghost, once the grid
+// has been adjusted to the maximum rapidity and to the 2pi azimuthal range.
+func (spec GhostedAreaSpec) ActualGhostArea() float64 {
+	_, _, drap, dphi := spec.grid()
+	return drap * dphi
+}
+
+// NumGhosts returns the number of ghosts added to an event.
+func (spec GhostedAreaSpec) NumGhosts() int {
+	nrap, nphi, _, _ := spec.grid()
+	return (2*nrap + 1) * nphi
+}
+
+// ghosts returns a new set of ghosts, using the provided random numbers
+// generator.
+func (spec GhostedAreaSpec) ghosts(rnd *rand.Rand) []Jet {
+	var (
+		nrap, nphi, drap, dphi = spec.grid()
+		ghosts                 = make([]Jet, 0, spec.NumGhosts())
+	)
+	for irap := -nrap; irap <= nrap; irap++ {
+		for iphi := 0; iphi < nphi; iphi++ {
+			phi := (float64(iphi)+0.5)*dphi + dphi*(rnd.Float64()-0.5)*spec.GridScatter
+			rap := float64(irap)*drap + drap*(rnd.Float64()-0.5)*spec.GridScatter
+			pt := spec.MeanGhostPt * (1 + (rnd.Float64()-0.5)*spec.PtScatter)
+			ghosts = append(ghosts, newJetPtYPhiM(pt, rap, phi, 0))
+		}
+	}
+	return ghosts
+}
+
+// VoronoiAreaSpec describes the Voronoi area computation.
+type VoronoiAreaSpec struct {
+	// EffectiveRFact is the factor applied to the jet radius to define
+	// the circles with which Voronoi cells are intersected.
+	EffectiveRFact float64
+}
+
+// NewVoronoiAreaSpec returns a new Voronoi area specification.
+func NewVoronoiAreaSpec(rfact float64) VoronoiAreaSpec {
+	return VoronoiAreaSpec{EffectiveRFact: rfact}
+}
+
+// AreaDefinition describes how jet areas are computed.
+type AreaDefinition struct {
+	typ     AreaType
+	ghost   GhostedAreaSpec
+	voronoi VoronoiAreaSpec
+}
+
+// NewAreaDefinition returns a new ghost-based area definition.
+func NewAreaDefinition(typ AreaType, spec GhostedAreaSpec) AreaDefinition {
+	switch typ {
+	case ActiveArea, ActiveAreaExplicitGhosts, OneGhostPassiveArea, PassiveArea:
+	default:
+		panic(fmt.Errorf("fastjet: invalid area type for a ghosted area definition (%v)", typ))
+	}
+	return AreaDefinition{
+		typ:     typ,
+		ghost:   spec,
+		voronoi: NewVoronoiAreaSpec(1),
+	}
+}
+
+// NewVoronoiAreaDefinition returns a new Voronoi area definition.
+func NewVoronoiAreaDefinition(spec VoronoiAreaSpec) AreaDefinition {
+	return AreaDefinition{
+		typ:     VoronoiArea,
+		voronoi: spec,
+	}
+}
+
+// AreaType returns the kind of area of this definition.
+func (def AreaDefinition) AreaType() AreaType {
+	return def.typ
+}
+
+// GhostSpec returns the ghosts specification of this definition.
+func (def AreaDefinition) GhostSpec() GhostedAreaSpec {
+	return def.ghost
+}
+
+// VoronoiSpec returns the Voronoi specification of this definition.
+func (def AreaDefinition) VoronoiSpec() VoronoiAreaSpec {
+	return def.voronoi
+}
+
+// Description returns a string description of this area definition.
+func (def AreaDefinition) Description() string {
+	switch def.typ {
+	case VoronoiArea:
+		return fmt.Sprintf("Voronoi area with effective_Rfact = %v", def.voronoi.EffectiveRFact)
+	default:
+		return fmt.Sprintf(
+			"%v with ghosts of area %v (had requested %v), placed up to y = %v, scattered wrt to perfect grid by (rel) %v, mean_ghost_pt = %v, rel pt_scatter = %v, n repetitions of ghost distributions = %v",
+			def.typ, def.ghost.ActualGhostArea(), def.ghost.GhostArea,
+			def.ghost.GhostMaxRap, def.ghost.GridScatter, def.ghost.MeanGhostPt,
+			def.ghost.PtScatter, def.ghost.Repeat,
+		)
+	}
+}
+
+// newJetPtYPhiM returns a new jet from its transverse momentum, rapidity,
+// azimuth and mass.
+func newJetPtYPhiM(pt, y, phi, m float64) Jet {
+	mt := math.Sqrt(pt*pt + m*m)
+	return NewJet(
+		pt*math.Cos(phi),
+		pt*math.Sin(phi),
+		mt*math.Sinh(y),
+		mt*math.Cosh(y),
+	)
+}
+
+// area4 returns the 4-vector area of a ghost of area a, at the position
+// of the jet.
+func area4(jet *Jet, a float64) fmom.PxPyPzE {
+	pt := jet.Pt()
+	if pt == 0 {
+		ghost := newJetPtYPhiM(a, jet.Rapidity(), jet.Phi(), 0)
+		return ghost.PxPyPzE
+	}
+	f := a / pt
+	return fmom.NewPxPyPzE(f*jet.Px(), f*jet.Py(), f*jet.Pz(), f*jet.E())
+}
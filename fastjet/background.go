@@ -0,0 +1,265 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fastjet
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// BackgroundEstimator estimates the density of the uniform background
+// (pileup, underlying event) of an event.
+type BackgroundEstimator interface {
+	// SetParticles sets the particles of the event from which the
+	// background is estimated.
+	SetParticles(particles []Jet) error
+
+	// Rho returns the background transverse momentum density per unit area.
+	Rho() float64
+
+	// Sigma returns the fluctuations of the background transverse momentum
+	// density, per square-root of unit area.
+	Sigma() float64
+
+	// Description returns a string description of the estimator.
+	Description() string
+}
+
+var (
+	_ BackgroundEstimator = (*JetMedianBackgroundEstimator)(nil)
+	_ BackgroundEstimator = (*GridMedianBackgroundEstimator)(nil)
+)
+
+// JetMedianBackgroundEstimator estimates the background density from the
+// median of the pt/area of the jets of an event that pass a selector.
+//
+// Jets are clustered with a jet definition and an area definition.
+// The kt or Cambridge/Aachen algorithms with active areas are the usual
+// choice.
+type JetMedianBackgroundEstimator struct {
+	sel  Selector
+	def  JetDefinition
+	area AreaDefinition
+
+	rho      float64
+	sigma    float64
+	meanArea float64
+	njets    int
+	nempty   float64
+}
+
+// NewJetMedianBackgroundEstimator returns a new background estimator,
+// using the median of the jets that pass the selector, as clustered
+// with the provided jet and area definitions.
+func NewJetMedianBackgroundEstimator(sel Selector, def JetDefinition, area AreaDefinition) *JetMedianBackgroundEstimator {
+	return &JetMedianBackgroundEstimator{
+		sel:  sel,
+		def:  def,
+		area: area,
+	}
+}
+
+// SetParticles clusters the particles and estimates the background from
+// the resulting jets.
+func (bkg *JetMedianBackgroundEstimator) SetParticles(particles []Jet) error {
+	csa, err := NewClusterSequenceArea(particles, bkg.def, bkg.area)
+	if err != nil {
+		return fmt.Errorf("fastjet: could not cluster particles: %w", err)
+	}
+	return bkg.SetClusterSequence(csa)
+}
+
+// SetClusterSequence estimates the background from the inclusive jets of
+// an already clustered event.
+func (bkg *JetMedianBackgroundEstimator) SetClusterSequence(csa *ClusterSequenceArea) error {
+	jets, err := csa.InclusiveJets(0)
+	if err != nil {
+		return fmt.Errorf("fastjet: could not retrieve inclusive jets: %w", err)
+	}
+
+	var (
+		vs    = make([]float64, 0, len(jets))
+		area  = 0.0
+		empty = 0.0
+	)
+	jets = bkg.sel.Select(jets)
+	for i := range jets {
+		jet := &jets[i]
+		area4 := jet.Area4()
+		v := area4.Pt()
+		if v <= 0 {
+			continue
+		}
+		vs = append(vs, jet.Pt()/v)
+		area += v
+	}
+
+	if !csa.HasExplicitGhosts() {
+		empty, err = csa.NumEmptyJets(bkg.sel)
+		if err != nil {
+			return fmt.Errorf("fastjet: could not compute number of empty jets: %w", err)
+		}
+	}
+
+	bkg.njets = len(vs)
+	bkg.nempty = empty
+	if len(vs) == 0 {
+		bkg.rho = 0
+		bkg.sigma = 0
+		bkg.meanArea = 0
+		return nil
+	}
+
+	bkg.meanArea = area / float64(len(vs))
+	bkg.rho, bkg.sigma = medianAndStdDev(vs, empty)
+	bkg.sigma *= math.Sqrt(bkg.meanArea)
+
+	return nil
+}
+
+// Rho returns the background transverse momentum density per unit area.
+func (bkg *JetMedianBackgroundEstimator) Rho() float64 { return bkg.rho }
+
+// Sigma returns the fluctuations of the background transverse momentum
+// density, per square-root of unit area.
+func (bkg *JetMedianBackgroundEstimator) Sigma() float64 { return bkg.sigma }
+
+// MeanArea returns the mean area of the jets used to estimate the background.
+func (bkg *JetMedianBackgroundEstimator) MeanArea() float64 { return bkg.meanArea }
+
+// NumJetsUsed returns the number of jets used to estimate the background.
+func (bkg *JetMedianBackgroundEstimator) NumJetsUsed() int { return bkg.njets }
+
+// NumEmptyJets returns the number of empty jets taken into account to
+// estimate the background.
+func (bkg *JetMedianBackgroundEstimator) NumEmptyJets() float64 { return bkg.nempty }
+
+// Description returns a string description of the estimator.
+func (bkg *JetMedianBackgroundEstimator) Description() string {
+	return fmt.Sprintf(
+		"JetMedianBackgroundEstimator, using %s with %s and selecting jets with %s",
+		bkg.def.Description(), bkg.area.Description(), bkg.sel.Description(),
+	)
+}
+
+// GridMedianBackgroundEstimator estimates the background density from the
+// median of the pt/area of the cells of a rapidity-azimuth grid.
+type GridMedianBackgroundEstimator struct {
+	ymax float64
+	ny   int
+	dy   float64
+	nphi int
+	dphi float64
+
+	rho   float64
+	sigma float64
+}
+
+// NewGridMedianBackgroundEstimator returns a new background estimator,
+// using a grid of cells covering |rapidity| < ymax, with cells of size
+// close to the requested spacing in rapidity and azimuth.
+func NewGridMedianBackgroundEstimator(ymax, spacing float64) *GridMedianBackgroundEstimator {
+	if ymax <= 0 || spacing <= 0 {
+		panic(fmt.Errorf("fastjet: invalid grid (ymax=%v, spacing=%v)", ymax, spacing))
+	}
+	ny := imax(1, int(2*ymax/spacing+0.5))
+	nphi := imax(1, int(2*math.Pi/spacing+0.5))
+	return &GridMedianBackgroundEstimator{
+		ymax: ymax,
+		ny:   ny,
+		dy:   2 * ymax / float64(ny),
+		nphi: nphi,
+		dphi: 2 * math.Pi / float64(nphi),
+	}
+}
+
+// SetParticles estimates the background from the provided particles.
+func (bkg *GridMedianBackgroundEstimator) SetParticles(particles []Jet) error {
+	pts := make([]float64, bkg.ny*bkg.nphi)
+	for i := range particles {
+		p := &particles[i]
+		idx := bkg.cell(p)
+		if idx < 0 {
+			continue
+		}
+		pts[idx] += p.Pt()
+	}
+
+	area := bkg.CellArea()
+	for i := range pts {
+		pts[i] /= area
+	}
+	bkg.rho, bkg.sigma = medianAndStdDev(pts, 0)
+	bkg.sigma *= math.Sqrt(area)
+
+	return nil
+}
+
+// cell returns the index of the cell containing the jet, or -1 if the jet
+// is outside the grid.
+func (bkg *GridMedianBackgroundEstimator) cell(jet *Jet) int {
+	rap := jet.Rapidity()
+	if rap < -bkg.ymax || rap >= bkg.ymax {
+		return -1
+	}
+	phi := jet.Phi()
+	if phi < 0 {
+		phi += 2 * math.Pi
+	}
+	iy := imin(int((rap+bkg.ymax)/bkg.dy), bkg.ny-1)
+	iphi := imin(int(phi/bkg.dphi), bkg.nphi-1)
+	return iy*bkg.nphi + iphi
+}
+
+// CellArea returns the area of each cell of the grid.
+func (bkg *GridMedianBackgroundEstimator) CellArea() float64 {
+	return bkg.dy * bkg.dphi
+}
+
+// Rho returns the background transverse momentum density per unit area.
+func (bkg *GridMedianBackgroundEstimator) Rho() float64 { return bkg.rho }
+
+// Sigma returns the fluctuations of the background transverse momentum
+// density, per square-root of unit area.
+func (bkg *GridMedianBackgroundEstimator) Sigma() float64 { return bkg.sigma }
+
+// Description returns a string description of the estimator.
+func (bkg *GridMedianBackgroundEstimator) Description() string {
+	return fmt.Sprintf(
+		"GridMedianBackgroundEstimator, with %d cells of size %v x %v in rapidity and azimuth, up to |rap| = %v",
+		bkg.ny*bkg.nphi, bkg.dy, bkg.dphi, bkg.ymax,
+	)
+}
+
+// medianAndStdDev returns the median of the values, and an estimate of
+// their standard deviation from the distance between the median and the
+// 16th percentile, assuming a gaussian distribution.
+// nempty additional values are assumed to be zero.
+func medianAndStdDev(vs []float64, nempty float64) (median, stddev float64) {
+	var (
+		sorted = make([]float64, len(vs))
+		n      = len(sorted)
+		ntot   = float64(n) + nempty
+		posn   = [2]float64{0.5, (1 - 0.6827) / 2}
+		res    [2]float64
+	)
+	copy(sorted, vs)
+	sort.Float64s(sorted)
+
+	for i, p := range posn {
+		pos := (ntot-1)*p - nempty
+		if pos < 0 || n < 2 {
+			continue
+		}
+		ipos := int(pos)
+		if ipos+1 > n-1 {
+			ipos = n - 2
+			pos = float64(n - 1)
+		}
+		res[i] = sorted[ipos]*(float64(ipos+1)-pos) + sorted[ipos+1]*(pos-float64(ipos))
+	}
+	return res[0], res[0] - res[1]
+}
@@ -0,0 +1,197 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fastjet_test
+
+import (
+	"math"
+	"testing"
+
+	"go-hep.org/x/hep/fastjet"
+	"go-hep.org/x/hep/fastjet/internal/fjtest"
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/floats/scalar"
+)
+
+// uniformEvent returns n particles uniformly distributed in |y| < ymax,
+// with a mean transverse momentum of 1.
+func uniformEvent(n int, ymax float64, seed uint64) []fastjet.Jet {
+	rnd := rand.New(rand.NewSource(seed))
+	ps := make([]fastjet.Jet, n)
+	for i := range ps {
+		var (
+			pt  = 0.5 + rnd.Float64()
+			y   = ymax * (2*rnd.Float64() - 1)
+			phi = 2 * math.Pi * rnd.Float64()
+		)
+		ps[i] = fjtest.NewJetPtYPhi(pt, y, phi)
+	}
+	return ps
+}
+
+func TestBackgroundEstimator(t *testing.T) {
+	const (
+		n    = 4000
+		ymax = 5.0
+	)
+	var (
+		particles = uniformEvent(n, ymax, 1234)
+		want      = n / (2 * ymax * 2 * math.Pi)
+		sigma     = math.Sqrt(want * 13 / 12) // <pt^2> = 13/12
+		ghosts    = fastjet.NewGhostedAreaSpec(ymax)
+	)
+
+	for _, tc := range []struct {
+		name string
+		bkg  fastjet.BackgroundEstimator
+	}{
+		{
+			name: "grid",
+			bkg:  fastjet.NewGridMedianBackgroundEstimator(4, 0.55),
+		},
+		{
+			name: "jet-median-kt-active",
+			bkg: fastjet.NewJetMedianBackgroundEstimator(
+				fastjet.SelectorAbsRapMax(4),
+				fastjet.NewJetDefinition(fastjet.KtAlgorithm, 0.4, fastjet.EScheme, fastjet.BestStrategy),
+				fastjet.NewAreaDefinition(fastjet.ActiveArea, ghosts),
+			),
+		},
+		{
+			name: "jet-median-kt-explicit-ghosts",
+			bkg: fastjet.NewJetMedianBackgroundEstimator(
+				fastjet.SelectorAbsRapMax(4),
+				fastjet.NewJetDefinition(fastjet.KtAlgorithm, 0.4, fastjet.EScheme, fastjet.BestStrategy),
+				fastjet.NewAreaDefinition(fastjet.ActiveAreaExplicitGhosts, ghosts),
+			),
+		},
+		{
+			name: "jet-median-kt-voronoi",
+			bkg: fastjet.NewJetMedianBackgroundEstimator(
+				fastjet.SelectorAbsRapMax(4),
+				fastjet.NewJetDefinition(fastjet.KtAlgorithm, 0.4, fastjet.EScheme, fastjet.BestStrategy),
+				fastjet.NewVoronoiAreaDefinition(fastjet.NewVoronoiAreaSpec(0.9)),
+			),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.bkg.SetParticles(particles)
+			if err != nil {
+				t.Fatalf("could not estimate background: %+v", err)
+			}
+			if got := tc.bkg.Rho(); !scalar.EqualWithinRel(got, want, 0.05) {
+				t.Fatalf("invalid rho: got=%v, want=%v", got, want)
+			}
+			if got, want := tc.bkg.Sigma(), sigma; !scalar.EqualWithinRel(got, want, 0.3) {
+				t.Fatalf("invalid sigma: got=%v, want=%v", got, want)
+			}
+		})
+	}
+}
+
+func TestBackgroundEstimatorEmptyJets(t *testing.T) {
+	// a sparse event, where many jets are only made of ghosts.
+	var (
+		particles = uniformEvent(100, 5, 1234)
+		sel       = fastjet.SelectorAbsRapMax(4)
+		def       = fastjet.NewJetDefinition(fastjet.KtAlgorithm, 0.4, fastjet.EScheme, fastjet.BestStrategy)
+		ghosts    = fastjet.NewGhostedAreaSpec(5)
+		implicit  = fastjet.NewJetMedianBackgroundEstimator(sel, def, fastjet.NewAreaDefinition(fastjet.ActiveArea, ghosts))
+		explicit  = fastjet.NewJetMedianBackgroundEstimator(sel, def, fastjet.NewAreaDefinition(fastjet.ActiveAreaExplicitGhosts, ghosts))
+	)
+
+	for _, bkg := range []*fastjet.JetMedianBackgroundEstimator{implicit, explicit} {
+		err := bkg.SetParticles(particles)
+		if err != nil {
+			t.Fatalf("could not estimate background: %+v", err)
+		}
+	}
+
+	if implicit.NumEmptyJets() == 0 {
+		t.Fatalf("expected empty jets")
+	}
+	if got, want := explicit.NumEmptyJets(), 0.0; got != want {
+		t.Fatalf("invalid number of empty jets with explicit ghosts: got=%v, want=%v", got, want)
+	}
+	if got, want := implicit.NumJetsUsed()+int(implicit.NumEmptyJets()), explicit.NumJetsUsed(); got != want {
+		t.Fatalf("invalid number of jets: got=%d, want=%d", got, want)
+	}
+	if got, want := implicit.Rho(), explicit.Rho(); !scalar.EqualWithinAbs(got, want, 1e-12) {
+		t.Fatalf("invalid rho: implicit=%v, explicit=%v", got, want)
+	}
+	if got, want := implicit.Sigma(), explicit.Sigma(); !scalar.EqualWithinAbs(got, want, 1e-12) {
+		t.Fatalf("invalid sigma: implicit=%v, explicit=%v", got, want)
+	}
+}
+
+func TestSubtractor(t *testing.T) {
+	const (
+		ptHard  = 100.0
+		rapHard = 0.5
+		phiHard = 1.0
+	)
+	var (
+		particles = append(uniformEvent(4000, 5, 42), fjtest.NewJetPtYPhi(ptHard, rapHard, phiHard))
+		ghosts    = fastjet.NewGhostedAreaSpec(5)
+		bkg       = fastjet.NewGridMedianBackgroundEstimator(4, 0.55)
+		def       = fastjet.NewJetDefinition(fastjet.AntiKtAlgorithm, 0.4, fastjet.EScheme, fastjet.BestStrategy)
+		area      = fastjet.NewAreaDefinition(fastjet.ActiveArea, ghosts)
+	)
+
+	err := bkg.SetParticles(particles)
+	if err != nil {
+		t.Fatalf("could not estimate background: %+v", err)
+	}
+
+	csa, err := fastjet.NewClusterSequenceArea(particles, def, area)
+	if err != nil {
+		t.Fatalf("could not run clustering: %+v", err)
+	}
+
+	jets, err := csa.InclusiveJets(50)
+	if err != nil {
+		t.Fatalf("could not retrieve inclusive jets: %+v", err)
+	}
+	if got, want := len(jets), 1; got != want {
+		t.Fatalf("invalid number of jets: got=%d, want=%d", got, want)
+	}
+	jet := &jets[0]
+
+	sub := fastjet.NewSubtractor(bkg)
+	if got, want := sub.Rho(), bkg.Rho(); got != want {
+		t.Fatalf("invalid rho: got=%v, want=%v", got, want)
+	}
+
+	o, err := sub.Subtract(jet)
+	if err != nil {
+		t.Fatalf("could not subtract jet: %+v", err)
+	}
+	if got, want := o.Pt(), jet.Pt()-bkg.Rho()*jet.Area(); !scalar.EqualWithinAbs(got, want, 0.05*jet.Pt()) {
+		t.Fatalf("invalid subtracted pt: got=%v, want=%v", got, want)
+	}
+	if got, want := o.Pt(), ptHard; !scalar.EqualWithinAbs(got, want, 3*bkg.Sigma()*math.Sqrt(jet.Area())) {
+		t.Fatalf("invalid subtracted pt: got=%v, want=%v", got, want)
+	}
+	if got, want := o.Area(), jet.Area(); got != want {
+		t.Fatalf("invalid subtracted jet area: got=%v, want=%v", got, want)
+	}
+	if got, want := len(o.Constituents()), len(jet.Constituents()); got != want {
+		t.Fatalf("invalid subtracted jet constituents: got=%d, want=%d", got, want)
+	}
+
+	// jets softer than the background are subtracted to zero.
+	soft, err := fastjet.NewSubtractorRho(1e6).Subtract(jet)
+	if err != nil {
+		t.Fatalf("could not subtract jet: %+v", err)
+	}
+	if got, want := soft.E(), 0.0; got != want {
+		t.Fatalf("invalid subtracted energy: got=%v, want=%v", got, want)
+	}
+
+	// jets without area can not be subtracted.
+	_, err = sub.Subtract(&particles[0])
+	if err == nil {
+		t.Fatalf("expected an error subtracting a jet without area")
+	}
+}
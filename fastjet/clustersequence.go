@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 
 	"go-hep.org/x/hep/fmom"
 )
@@ -130,6 +131,44 @@ func (cs *ClusterSequence) ExclusiveJetsUpTo(njets int) ([]Jet, error) {
 	return ljets, err
 }
 
+// ExclusiveJetsYcut returns the exclusive jets that would have been
+// obtained running the algorithm in exclusive mode with dcut = ycut*Q^2,
+// where Q is the total energy of the event.
+func (cs *ClusterSequence) ExclusiveJetsYcut(ycut float64) ([]Jet, error) {
+	return cs.ExclusiveJets(ycut * cs.qtot * cs.qtot)
+}
+
+// ExclusiveDmerge returns the distance dmin corresponding to the
+// recombination that went from njets+1 to njets jets.
+func (cs *ClusterSequence) ExclusiveDmerge(njets int) float64 {
+	if njets >= cs.initn {
+		return 0
+	}
+	return cs.history[2*cs.initn-njets-1].dij
+}
+
+// ExclusiveDmergeMax returns the maximum of the distances dmin encountered
+// during all recombinations up to the one that led to an njets-jets final
+// state.
+func (cs *ClusterSequence) ExclusiveDmergeMax(njets int) float64 {
+	if njets >= cs.initn {
+		return 0
+	}
+	return cs.history[2*cs.initn-njets-1].maxdij
+}
+
+// ExclusiveYmerge returns ExclusiveDmerge, normalized by Q^2, where Q is
+// the total energy of the event.
+func (cs *ClusterSequence) ExclusiveYmerge(njets int) float64 {
+	return cs.ExclusiveDmerge(njets) / (cs.qtot * cs.qtot)
+}
+
+// ExclusiveYmergeMax returns ExclusiveDmergeMax, normalized by Q^2, where
+// Q is the total energy of the event.
+func (cs *ClusterSequence) ExclusiveYmergeMax(njets int) float64 {
+	return cs.ExclusiveDmergeMax(njets) / (cs.qtot * cs.qtot)
+}
+
 func (cs *ClusterSequence) InclusiveJets(ptmin float64) ([]Jet, error) {
 	var err error
 	dcut := ptmin * ptmin
@@ -226,6 +265,13 @@ func (cs *ClusterSequence) run() error {
 		return nil
 	}
 
+	if cs.alg == PluginAlgorithm {
+		if cs.def.plugin == nil {
+			return fmt.Errorf("fastjet: no plugin for jet algorithm (%d)", int(cs.alg))
+		}
+		return cs.def.plugin.RunClustering(cs)
+	}
+
 	run := cs.runN3Dumb
 
 	switch cs.strategy {
@@ -233,6 +279,28 @@ func (cs *ClusterSequence) run() error {
 		run = cs.runNlnN
 	case N3DumbStrategy:
 		run = cs.runN3Dumb
+	case N2PlainStrategy:
+		run = cs.runN2Plain
+	case N2TiledStrategy, N2PoorTiledStrategy:
+		run = cs.runN2Tiled
+	case N2MinHeapTiledStrategy:
+		run = cs.runN2MinHeapTiled
+	case BestStrategy:
+		switch cs.bestStrategy() {
+		case N2PlainStrategy:
+			run = cs.runN2Plain
+		case N2TiledStrategy:
+			run = cs.runN2Tiled
+		case N2MinHeapTiledStrategy:
+			run = cs.runN2MinHeapTiled
+		}
+	}
+
+	switch cs.strategy {
+	case N2PlainStrategy, N2TiledStrategy, N2PoorTiledStrategy, N2MinHeapTiledStrategy:
+		if !isPPAlgorithm(cs.alg) {
+			return fmt.Errorf("fastjet: strategy %v not supported for jet algorithm (%d)", cs.strategy, int(cs.alg))
+		}
 	}
 
 	err := run()
@@ -243,11 +311,165 @@ func (cs *ClusterSequence) run() error {
 	return nil
 }
 
+// bestStrategy returns the strategy expected to be the fastest for the
+// number of particles and the radius of the clustering.
+//
+// bestStrategy is an approximation of FastJet's selection: it keeps
+// FastJet's threshold for N2Plain, but uses a fixed multiplicity threshold
+// between N2Tiled and N2MinHeapTiled where FastJet uses R-dependent ones,
+// and never selects the NlnN strategies, which are not implemented.
+// Algorithms other than the hadron-collider ones always use N3Dumb.
+func (cs *ClusterSequence) bestStrategy() Strategy {
+	if !isPPAlgorithm(cs.alg) {
+		return N3DumbStrategy
+	}
+
+	var (
+		n = len(cs.jets)
+		r = math.Max(cs.r, 0.1)
+	)
+	switch {
+	case n <= 30 || float64(n) <= 39/(r+0.6):
+		return N2PlainStrategy
+	case n <= 450:
+		return N2TiledStrategy
+	default:
+		return N2MinHeapTiledStrategy
+	}
+}
+
 // Constituents retrieves the list of constituents of a given jet
 func (cs *ClusterSequence) Constituents(jet *Jet) ([]Jet, error) {
 	return cs.addConstituents(jet)
 }
 
+// Parents returns the two parents of a jet in the clustering history,
+// the one with the largest transverse momentum first.
+// Parents returns false if the jet is an original particle.
+func (cs *ClusterSequence) Parents(jet *Jet) (p1, p2 Jet, ok bool) {
+	if jet.hidx < 0 || jet.hidx >= len(cs.history) {
+		return p1, p2, false
+	}
+	hh := cs.history[jet.hidx]
+	if hh.parent1 < 0 || hh.parent2 < 0 {
+		return p1, p2, false
+	}
+	p1 = cs.jets[cs.history[hh.parent1].jet]
+	p2 = cs.jets[cs.history[hh.parent2].jet]
+	if p1.Pt2() < p2.Pt2() {
+		p1, p2 = p2, p1
+	}
+	return p1, p2, true
+}
+
+// Child returns the jet resulting from the recombination of the provided
+// jet with another one.
+// Child returns false if the jet was not recombined with another jet.
+func (cs *ClusterSequence) Child(jet *Jet) (Jet, bool) {
+	if jet.hidx < 0 || jet.hidx >= len(cs.history) {
+		return Jet{}, false
+	}
+	child := cs.history[jet.hidx].child
+	if child < 0 || cs.history[child].jet < 0 {
+		return Jet{}, false
+	}
+	return cs.jets[cs.history[child].jet], true
+}
+
+// Partner returns the jet with which the provided jet was recombined.
+// Partner returns false if the jet was not recombined with another jet.
+func (cs *ClusterSequence) Partner(jet *Jet) (Jet, bool) {
+	if jet.hidx < 0 || jet.hidx >= len(cs.history) {
+		return Jet{}, false
+	}
+	child := cs.history[jet.hidx].child
+	if child < 0 {
+		return Jet{}, false
+	}
+	hh := cs.history[child]
+	partner := hh.parent1
+	if partner == jet.hidx {
+		partner = hh.parent2
+	}
+	if partner < 0 {
+		return Jet{}, false
+	}
+	return cs.jets[cs.history[partner].jet], true
+}
+
+// ExclusiveSubjets returns the subjets of the jet that would have been
+// obtained running the algorithm in exclusive mode with the given dcut.
+func (cs *ClusterSequence) ExclusiveSubjets(jet *Jet, dcut float64) ([]Jet, error) {
+	sub, err := cs.subhist(jet, dcut, 0)
+	if err != nil {
+		return nil, err
+	}
+	return cs.subjets(sub), nil
+}
+
+// NumExclusiveSubjets returns the number of subjets of the jet that would
+// have been obtained running the algorithm in exclusive mode with the
+// given dcut.
+func (cs *ClusterSequence) NumExclusiveSubjets(jet *Jet, dcut float64) (int, error) {
+	sub, err := cs.subhist(jet, dcut, 0)
+	if err != nil {
+		return 0, err
+	}
+	return len(sub), nil
+}
+
+// ExclusiveSubjetsUpTo returns the (at most) nsub subjets of the jet
+// obtained by undoing its last clustering steps.
+func (cs *ClusterSequence) ExclusiveSubjetsUpTo(jet *Jet, nsub int) ([]Jet, error) {
+	if nsub < 0 {
+		return nil, fmt.Errorf("fastjet: requested negative number of subjets (%d)", nsub)
+	}
+	if nsub == 0 {
+		return nil, nil
+	}
+	sub, err := cs.subhist(jet, math.Inf(-1), nsub)
+	if err != nil {
+		return nil, err
+	}
+	return cs.subjets(sub), nil
+}
+
+// subhist returns the history elements of the subjets of the jet, undoing
+// the clustering steps of the jet until they are all resolved at dcut or
+// until there are maxjet of them (if maxjet is positive).
+func (cs *ClusterSequence) subhist(jet *Jet, dcut float64, maxjet int) ([]int, error) {
+	if jet.hidx < 0 || jet.hidx >= len(cs.history) || cs.history[jet.hidx].jet < 0 {
+		return nil, fmt.Errorf("fastjet: jet is not part of this cluster sequence")
+	}
+
+	sub := []int{jet.hidx}
+	for njet := 1; njet != maxjet; njet++ {
+		// find the element closest to the end of the clustering sequence.
+		last := 0
+		for i := range sub {
+			if sub[i] > sub[last] {
+				last = i
+			}
+		}
+		hh := cs.history[sub[last]]
+		if hh.parent1 < 0 || hh.maxdij <= dcut {
+			break
+		}
+		sub[last] = hh.parent1
+		sub = append(sub, hh.parent2)
+	}
+	sort.Ints(sub)
+	return sub, nil
+}
+
+func (cs *ClusterSequence) subjets(sub []int) []Jet {
+	jets := make([]Jet, len(sub))
+	for i, h := range sub {
+		jets[i] = cs.jets[cs.history[h].jet]
+	}
+	return jets
+}
+
 func (cs *ClusterSequence) addConstituents(jet *Jet) ([]Jet, error) {
 	var err error
 	var subjets []Jet
@@ -344,6 +566,30 @@ func (cs *ClusterSequence) setStructure(j *Jet) {
 	j.structure = cs.structure
 }
 
+// Jets returns the initial particles and the jets created during the
+// clustering, indexed by the order in which they were created.
+// The returned slice must not be modified.
+func (cs *ClusterSequence) Jets() []Jet {
+	return cs.jets
+}
+
+// PluginRecordIJ records the recombination of the i-th and j-th jets with
+// the distance dij.
+// PluginRecordIJ returns the index of the newly created jet.
+//
+// PluginRecordIJ is meant to be used by plugins.
+func (cs *ClusterSequence) PluginRecordIJ(i, j int, dij float64) (int, error) {
+	return cs.ijRecombinationStep(i, j, dij)
+}
+
+// PluginRecordIB records the recombination of the i-th jet with the beam
+// with the distance dib, making it a final inclusive jet.
+//
+// PluginRecordIB is meant to be used by plugins.
+func (cs *ClusterSequence) PluginRecordIB(i int, dib float64) error {
+	return cs.ibRecombinationStep(i, dib)
+}
+
 // do_ij_recombination_step
 func (cs *ClusterSequence) ijRecombinationStep(i, j int, dij float64) (int, error) {
 
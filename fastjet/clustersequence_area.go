@@ -4,44 +4,617 @@
 
 package fastjet
 
+import (
+	"fmt"
+	"math"
+
+	"go-hep.org/x/hep/fmom"
+	"golang.org/x/exp/rand"
+)
+
+// ClusterSequenceArea is a cluster sequence that also computes the
+// areas of jets, according to an area definition.
+//
+// Jets returned by a ClusterSequenceArea carry their area information,
+// accessible via Jet.Area, Jet.AreaErr and Jet.Area4.
 type ClusterSequenceArea struct {
 	cs   *ClusterSequence
 	area AreaDefinition
+
+	areas  []float64      // area of each history element
+	errs   []float64      // uncertainty on the area of each history element
+	area4s []fmom.PxPyPzE // 4-vector area of each history element
+	ghosts []bool         // whether each history element is only made of ghosts
+	empty  []ghostJet     // pure ghost jets, for ghosted areas
+	nrep   int            // number of repetitions of the ghosted clustering
+	ghost  bool           // whether areas are computed with ghosts
+}
+
+var (
+	_ JetAreaStructure = (*ClusterSequenceArea)(nil)
+)
+
+// ghostJet is an inclusive jet only made of ghosts.
+type ghostJet struct {
+	jet  Jet
+	area float64
+}
+
+func newGhostJet(jet *Jet, area float64) ghostJet {
+	// only keep the kinematics of the jet, so the ghosted cluster
+	// sequence can be garbage collected.
+	return ghostJet{
+		jet:  NewJet(jet.Px(), jet.Py(), jet.Pz(), jet.E()),
+		area: area,
+	}
 }
 
+// NewClusterSequenceArea runs the clustering of the provided particles
+// with the given jet definition, and computes the area of the resulting
+// jets according to the area definition.
 func NewClusterSequenceArea(jets []Jet, def JetDefinition, area AreaDefinition) (*ClusterSequenceArea, error) {
-	cs, err := NewClusterSequence(jets, def)
+	csa := &ClusterSequenceArea{
+		area: area,
+	}
+
+	var err error
+	switch area.typ {
+	case ActiveArea:
+		err = csa.runActive(jets, def)
+	case ActiveAreaExplicitGhosts:
+		err = csa.runExplicitGhosts(jets, def)
+	case OneGhostPassiveArea:
+		err = csa.runOneGhostPassive(jets, def)
+	case PassiveArea:
+		switch def.Algorithm() {
+		case KtAlgorithm:
+			// for the kt algorithm, the passive area is the Voronoi area
+			// with an effective R factor of 1.
+			err = csa.runVoronoi(jets, def, 1)
+		case CambridgeAlgorithm:
+			// use a variant of the Cambridge algorithm that prevents
+			// ghosts from clustering among themselves.
+			def = NewJetDefinitionExtra(
+				CambridgeForPassiveAlgorithm, def.R(), def.RecombinationScheme(),
+				def.Strategy(), math.Sqrt(area.ghost.MeanGhostPt),
+			)
+			err = csa.runActive(jets, def)
+		case AntiKtAlgorithm:
+			// for the anti-kt algorithm, passive and active areas are
+			// identical.
+			err = csa.runActive(jets, def)
+		default:
+			err = csa.runOneGhostPassive(jets, def)
+		}
+	case VoronoiArea:
+		err = csa.runVoronoi(jets, def, area.voronoi.EffectiveRFact)
+	default:
+		err = fmt.Errorf("fastjet: invalid area type (%d)", int(area.typ))
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	csa := ClusterSequenceArea{
-		cs:   cs,
-		area: area,
+	// make sure jets carry the area information.
+	csa.cs.structure = csa
+	for i := range csa.cs.jets {
+		csa.cs.jets[i].structure = csa
 	}
-	return &csa, nil
+
+	return csa, nil
 }
 
+// AreaDefinition returns the area definition used to compute jet areas.
+func (csa *ClusterSequenceArea) AreaDefinition() AreaDefinition {
+	return csa.area
+}
+
+// JetDefinition returns the jet definition used for the clustering.
+func (csa *ClusterSequenceArea) JetDefinition() JetDefinition {
+	return csa.cs.def
+}
+
+// HasExplicitGhosts returns whether ghosts are part of the clustering
+// and of the jets constituents.
+func (csa *ClusterSequenceArea) HasExplicitGhosts() bool {
+	return csa.area.typ == ActiveAreaExplicitGhosts
+}
+
+// Constituents retrieves the list of constituents of a given jet.
+func (csa *ClusterSequenceArea) Constituents(jet *Jet) ([]Jet, error) {
+	return csa.cs.Constituents(jet)
+}
+
+// Area returns the scalar area of the jet.
 func (csa *ClusterSequenceArea) Area(jet *Jet) float64 {
-	panic("not implemented")
+	if !csa.valid(jet) {
+		return 0
+	}
+	return csa.areas[jet.hidx]
 }
 
+// AreaErr returns the uncertainty on the area of the jet.
+// The uncertainty is estimated from the spread of the areas over the
+// repetitions of the ghosted clustering, and is zero otherwise.
 func (csa *ClusterSequenceArea) AreaErr(jet *Jet) float64 {
-	panic("not implemented")
+	if !csa.valid(jet) {
+		return 0
+	}
+	return csa.errs[jet.hidx]
 }
 
+// Area4 returns the 4-vector area of the jet.
+func (csa *ClusterSequenceArea) Area4(jet *Jet) fmom.PxPyPzE {
+	if !csa.valid(jet) {
+		return fmom.PxPyPzE{}
+	}
+	return csa.area4s[jet.hidx]
+}
+
+// IsPureGhost returns whether the jet is only made of ghosts.
+// This can only be the case with explicit ghosts.
+func (csa *ClusterSequenceArea) IsPureGhost(jet *Jet) bool {
+	if !csa.valid(jet) || csa.ghosts == nil {
+		return false
+	}
+	return csa.ghosts[jet.hidx]
+}
+
+func (csa *ClusterSequenceArea) valid(jet *Jet) bool {
+	return 0 <= jet.hidx && jet.hidx < len(csa.areas)
+}
+
+// EmptyArea returns the area, within the provided selector, that is not
+// covered by jets.
+//
+// For ghosted areas, this is the area of the jets only made of ghosts.
+// Otherwise, this is the area of the selector minus the area of the
+// inclusive jets that pass the selector.
+func (csa *ClusterSequenceArea) EmptyArea(sel Selector) (float64, error) {
+	if !sel.AppliesJetByJet() {
+		return 0, fmt.Errorf("fastjet: could not compute empty area: selector %q does not apply jet by jet", sel.Description())
+	}
+	if csa.ghost {
+		area := 0.0
+		for i := range csa.empty {
+			if sel.Pass(&csa.empty[i].jet) {
+				area += csa.empty[i].area
+			}
+		}
+		return area / float64(csa.nrep), nil
+	}
+
+	area, err := sel.Area()
+	if err != nil {
+		return 0, fmt.Errorf("fastjet: could not compute empty area: %w", err)
+	}
+	jets, err := csa.cs.InclusiveJets(0)
+	if err != nil {
+		return 0, fmt.Errorf("fastjet: could not compute empty area: %w", err)
+	}
+	for i := range jets {
+		if sel.Pass(&jets[i]) {
+			area -= csa.Area(&jets[i])
+		}
+	}
+	return area, nil
+}
+
+// NumEmptyJets returns the number of empty jets, within the provided
+// selector.
+//
+// For ghosted areas, this is the number of jets only made of ghosts.
+// Otherwise, this is the empty area divided by the typical area of an
+// empty jet, 0.55*pi*R^2.
+func (csa *ClusterSequenceArea) NumEmptyJets(sel Selector) (float64, error) {
+	if !sel.AppliesJetByJet() {
+		return 0, fmt.Errorf("fastjet: could not compute number of empty jets: selector %q does not apply jet by jet", sel.Description())
+	}
+	if csa.ghost {
+		n := 0
+		for i := range csa.empty {
+			if sel.Pass(&csa.empty[i].jet) {
+				n++
+			}
+		}
+		return float64(n) / float64(csa.nrep), nil
+	}
+
+	area, err := csa.EmptyArea(sel)
+	if err != nil {
+		return 0, err
+	}
+	r := csa.cs.r
+	return area / (0.55 * math.Pi * r * r), nil
+}
+
+// NumExclusiveJets returns the number of exclusive jets that would have been obtained
+// running the algorithm in exclusive mode with the given dcut
 func (csa *ClusterSequenceArea) NumExclusiveJets(dcut float64) int {
-	panic("not implemented")
+	return csa.cs.NumExclusiveJets(dcut)
 }
 
-func (cs *ClusterSequenceArea) ExclusiveJets(dcut float64) ([]Jet, error) {
-	panic("not implemented")
+func (csa *ClusterSequenceArea) ExclusiveJets(dcut float64) ([]Jet, error) {
+	return csa.cs.ExclusiveJets(dcut)
 }
 
-func (cs *ClusterSequenceArea) ExclusiveJetsUpTo(njets int) ([]Jet, error) {
-	panic("not implemented")
+func (csa *ClusterSequenceArea) ExclusiveJetsUpTo(njets int) ([]Jet, error) {
+	return csa.cs.ExclusiveJetsUpTo(njets)
 }
 
 func (csa *ClusterSequenceArea) InclusiveJets(ptmin float64) ([]Jet, error) {
-	panic("not implemented")
+	return csa.cs.InclusiveJets(ptmin)
+}
+
+// ExclusiveSubjets returns the subjets of the jet that would have been
+// obtained running the algorithm in exclusive mode with the given dcut.
+func (csa *ClusterSequenceArea) ExclusiveSubjets(jet *Jet, dcut float64) ([]Jet, error) {
+	return csa.cs.ExclusiveSubjets(jet, dcut)
+}
+
+// ExclusiveSubjetsUpTo returns the (at most) nsub subjets of the jet
+// obtained by undoing its last clustering steps.
+func (csa *ClusterSequenceArea) ExclusiveSubjetsUpTo(jet *Jet, nsub int) ([]Jet, error) {
+	return csa.cs.ExclusiveSubjetsUpTo(jet, nsub)
+}
+
+// runActive computes active areas, clustering the particles together with
+// ghosts, and transferring the ghosts content of the ghosted jets to the
+// jets made of the same particles, clustered without ghosts.
+func (csa *ClusterSequenceArea) runActive(particles []Jet, def JetDefinition) error {
+	var err error
+	csa.cs, err = NewClusterSequence(particles, def)
+	if err != nil {
+		return err
+	}
+
+	var (
+		spec  = csa.area.ghost
+		rnd   = rand.New(rand.NewSource(spec.Seed))
+		nreal = len(particles)
+		nhist = len(csa.cs.history)
+		keys  = realKeys(csa.cs, nreal)
+		index = make(map[realKey]int, nhist)
+		rep   = make([]float64, nhist)
+		rep4  = make([]fmom.PxPyPzE, nhist)
+	)
+
+	for i, hh := range csa.cs.history {
+		if hh.parent2 == beamJetIndex {
+			continue
+		}
+		index[keys[i]] = i
+	}
+
+	csa.ghost = true
+	csa.nrep = imax(1, spec.Repeat)
+	csa.areas = make([]float64, nhist)
+	csa.errs = make([]float64, nhist)
+	csa.area4s = make([]fmom.PxPyPzE, nhist)
+
+	for irep := 0; irep < csa.nrep; irep++ {
+		gcs, err := clusterWithGhosts(particles, spec.ghosts(rnd), def)
+		if err != nil {
+			return err
+		}
+		var (
+			gkeys      = realKeys(gcs, nreal)
+			gas, ga4s  = ghostAreas(gcs, nreal, spec.ActualGhostArea())
+			inexistent = -1.0
+		)
+		for i := range rep {
+			rep[i] = inexistent
+			rep4[i] = fmom.PxPyPzE{}
+		}
+
+		// the ghosted history element with the largest ghost content is
+		// the one that corresponds to the history element without ghosts.
+		for i, hh := range gcs.history {
+			key := gkeys[i]
+			if key.n == 0 {
+				if hh.parent2 == beamJetIndex {
+					ip := hh.parent1
+					csa.empty = append(csa.empty, newGhostJet(
+						&gcs.jets[gcs.history[ip].jet], gas[ip],
+					))
+				}
+				continue
+			}
+			if hh.parent2 == beamJetIndex {
+				continue
+			}
+			j, ok := index[key]
+			if !ok {
+				continue
+			}
+			if gas[i] > rep[j] {
+				rep[j] = gas[i]
+				rep4[j] = ga4s[i]
+			}
+		}
+
+		for i, v := range rep {
+			if v == inexistent {
+				continue
+			}
+			csa.areas[i] += v
+			csa.errs[i] += v * v
+			csa.area4s[i] = addP4(csa.area4s[i], rep4[i])
+		}
+	}
+
+	csa.average()
+	return nil
+}
+
+// runExplicitGhosts computes active areas, keeping the ghosts as part of
+// the clustering.
+func (csa *ClusterSequenceArea) runExplicitGhosts(particles []Jet, def JetDefinition) error {
+	var (
+		err   error
+		spec  = csa.area.ghost
+		rnd   = rand.New(rand.NewSource(spec.Seed))
+		nreal = len(particles)
+	)
+
+	csa.cs, err = clusterWithGhosts(particles, spec.ghosts(rnd), def)
+	if err != nil {
+		return err
+	}
+
+	keys := realKeys(csa.cs, nreal)
+	csa.ghost = true
+	csa.nrep = 1
+	csa.areas, csa.area4s = ghostAreas(csa.cs, nreal, spec.ActualGhostArea())
+	csa.errs = make([]float64, len(csa.areas))
+	csa.ghosts = make([]bool, len(keys))
+	for i, key := range keys {
+		csa.ghosts[i] = key.n == 0
+	}
+
+	for _, hh := range csa.cs.history {
+		if hh.parent2 != beamJetIndex || keys[hh.parent1].n != 0 {
+			continue
+		}
+		ip := hh.parent1
+		csa.empty = append(csa.empty, newGhostJet(
+			&csa.cs.jets[csa.cs.history[ip].jet], csa.areas[ip],
+		))
+	}
+
+	return nil
+}
+
+// runOneGhostPassive computes passive areas, adding ghosts one at a time
+// to the event, and recording in which jet each ghost ends up.
+func (csa *ClusterSequenceArea) runOneGhostPassive(particles []Jet, def JetDefinition) error {
+	var err error
+	csa.cs, err = NewClusterSequence(particles, def)
+	if err != nil {
+		return err
+	}
+
+	var (
+		spec  = csa.area.ghost
+		rnd   = rand.New(rand.NewSource(spec.Seed))
+		nreal = len(particles)
+		nhist = len(csa.cs.history)
+		keys  = realKeys(csa.cs, nreal)
+		index = make(map[realKey]int, nhist)
+		rep   = make([]float64, nhist)
+		rep4  = make([]fmom.PxPyPzE, nhist)
+		seen  = make([]int, nhist)
+		garea = spec.ActualGhostArea()
+	)
+
+	for i, hh := range csa.cs.history {
+		if hh.parent2 == beamJetIndex {
+			continue
+		}
+		index[keys[i]] = i
+	}
+
+	csa.ghost = true
+	csa.nrep = imax(1, spec.Repeat)
+	csa.areas = make([]float64, nhist)
+	csa.errs = make([]float64, nhist)
+	csa.area4s = make([]fmom.PxPyPzE, nhist)
+
+	ighost := 0
+	for irep := 0; irep < csa.nrep; irep++ {
+		for i := range rep {
+			rep[i] = 0
+			rep4[i] = fmom.PxPyPzE{}
+		}
+		for _, ghost := range spec.ghosts(rnd) {
+			ighost++
+			gcs, err := clusterWithGhosts(particles, []Jet{ghost}, def)
+			if err != nil {
+				return err
+			}
+			var (
+				gkeys  = realKeys(gcs, nreal)
+				gas, _ = ghostAreas(gcs, nreal, garea)
+				ghost4 = area4(&ghost, garea)
+			)
+			for i, hh := range gcs.history {
+				if gas[i] == 0 {
+					// no ghost in this history element.
+					continue
+				}
+				if gkeys[i].n == 0 {
+					if hh.parent2 == beamJetIndex {
+						csa.empty = append(csa.empty, newGhostJet(&ghost, garea))
+					}
+					continue
+				}
+				if hh.parent2 == beamJetIndex {
+					continue
+				}
+				j, ok := index[gkeys[i]]
+				if !ok || seen[j] == ighost {
+					continue
+				}
+				seen[j] = ighost
+				rep[j] += garea
+				rep4[j] = addP4(rep4[j], ghost4)
+			}
+		}
+
+		for i, v := range rep {
+			csa.areas[i] += v
+			csa.errs[i] += v * v
+			csa.area4s[i] = addP4(csa.area4s[i], rep4[i])
+		}
+	}
+
+	csa.average()
+	return nil
+}
+
+// average computes the mean areas and their uncertainties from the sums of
+// areas and squared areas accumulated over the repetitions of the ghosted
+// clustering.
+func (csa *ClusterSequenceArea) average() {
+	n := float64(csa.nrep)
+	for i, hh := range csa.cs.history {
+		if hh.parent2 == beamJetIndex {
+			// beam recombination steps carry the area of the jet.
+			ip := hh.parent1
+			csa.areas[i] = csa.areas[ip]
+			csa.errs[i] = csa.errs[ip]
+			csa.area4s[i] = csa.area4s[ip]
+			continue
+		}
+		mean := csa.areas[i] / n
+		csa.areas[i] = mean
+		csa.errs[i] = math.Sqrt(math.Max(0, csa.errs[i]/n-mean*mean))
+		csa.area4s[i] = scaleP4(1/n, csa.area4s[i])
+	}
+}
+
+// runVoronoi computes Voronoi areas: the area of a jet is the sum of the
+// areas of the Voronoi cells of its constituents, each cell being
+// intersected with a circle of radius rfact*R.
+func (csa *ClusterSequenceArea) runVoronoi(particles []Jet, def JetDefinition, rfact float64) error {
+	var err error
+	csa.cs, err = NewClusterSequence(particles, def)
+	if err != nil {
+		return err
+	}
+
+	var (
+		cs    = csa.cs
+		nhist = len(cs.history)
+		cells = voronoiAreas(cs.jets[:cs.initn], rfact*cs.r)
+	)
+
+	csa.areas = make([]float64, nhist)
+	csa.errs = make([]float64, nhist)
+	csa.area4s = make([]fmom.PxPyPzE, nhist)
+	for i, v := range cells {
+		csa.areas[i] = v
+		csa.area4s[i] = area4(&cs.jets[i], v)
+	}
+	sumOverHistory(cs, csa.areas, csa.area4s)
+
+	return nil
+}
+
+// clusterWithGhosts clusters the particles together with the ghosts.
+// Ghosts are placed after the particles.
+func clusterWithGhosts(particles, ghosts []Jet, def JetDefinition) (*ClusterSequence, error) {
+	jets := make([]Jet, 0, len(particles)+len(ghosts))
+	jets = append(jets, particles...)
+	jets = append(jets, ghosts...)
+	return NewClusterSequence(jets, def)
+}
+
+// ghostAreas returns the scalar and 4-vector areas of each history element
+// of a cluster sequence, where all initial particles beyond nreal are ghosts
+// of the given area.
+func ghostAreas(cs *ClusterSequence, nreal int, area float64) ([]float64, []fmom.PxPyPzE) {
+	var (
+		areas  = make([]float64, len(cs.history))
+		area4s = make([]fmom.PxPyPzE, len(cs.history))
+	)
+	for i := nreal; i < cs.initn; i++ {
+		areas[i] = area
+		area4s[i] = area4(&cs.jets[i], area)
+	}
+	sumOverHistory(cs, areas, area4s)
+	return areas, area4s
+}
+
+// sumOverHistory fills the areas of the history elements resulting from
+// a recombination, from the areas of the initial particles.
+func sumOverHistory(cs *ClusterSequence, areas []float64, area4s []fmom.PxPyPzE) {
+	for i := cs.initn; i < len(cs.history); i++ {
+		hh := cs.history[i]
+		areas[i] = areas[hh.parent1]
+		area4s[i] = area4s[hh.parent1]
+		if hh.parent2 >= 0 {
+			areas[i] += areas[hh.parent2]
+			area4s[i] = addP4(area4s[i], area4s[hh.parent2])
+		}
+	}
+}
+
+// realKey identifies the set of real (non-ghost) particles contained in a
+// history element.
+type realKey struct {
+	n    int    // number of real particles
+	min  int    // smallest index of the real particles
+	hash uint64 // order-independent hash of the indices of the real particles
+}
+
+// realKeys returns the keys of all the history elements of a cluster
+// sequence, where all initial particles beyond nreal are ghosts.
+func realKeys(cs *ClusterSequence, nreal int) []realKey {
+	keys := make([]realKey, len(cs.history))
+	for i := 0; i < cs.initn; i++ {
+		switch {
+		case i < nreal:
+			keys[i] = realKey{n: 1, min: i, hash: splitmix64(uint64(i))}
+		default:
+			keys[i] = realKey{min: -1}
+		}
+	}
+	for i := cs.initn; i < len(cs.history); i++ {
+		hh := cs.history[i]
+		key := keys[hh.parent1]
+		if hh.parent2 >= 0 {
+			o := keys[hh.parent2]
+			switch {
+			case key.min < 0:
+				key.min = o.min
+			case o.min >= 0 && o.min < key.min:
+				key.min = o.min
+			}
+			key.n += o.n
+			key.hash += o.hash
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+func addP4(p1, p2 fmom.PxPyPzE) fmom.PxPyPzE {
+	return fmom.NewPxPyPzE(
+		p1.Px()+p2.Px(),
+		p1.Py()+p2.Py(),
+		p1.Pz()+p2.Pz(),
+		p1.E()+p2.E(),
+	)
+}
+
+func scaleP4(f float64, p fmom.PxPyPzE) fmom.PxPyPzE {
+	return fmom.NewPxPyPzE(f*p.Px(), f*p.Py(), f*p.Pz(), f*p.E())
 }
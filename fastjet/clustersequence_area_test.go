@@ -4,22 +4,24 @@
 
 package fastjet_test
 
-/* FIXME
-
 import (
 	"bufio"
 	"fmt"
+	"math"
 	"os"
 	"sort"
-	"strings"
 	"testing"
 
 	"go-hep.org/x/hep/fastjet"
+	"go-hep.org/x/hep/fastjet/internal/fjtest"
 	"gonum.org/v1/gonum/floats/scalar"
 )
 
 func TestClusterSequenceArea(t *testing.T) {
-	const tol = 1e-6
+	var (
+		active  = fastjet.NewAreaDefinition(fastjet.ActiveArea, fastjet.NewGhostedAreaSpec(6))
+		passive = fastjet.NewAreaDefinition(fastjet.PassiveArea, fastjet.NewGhostedAreaSpec(6))
+	)
 
 	for _, test := range []struct {
 		input string
@@ -27,6 +29,7 @@ func TestClusterSequenceArea(t *testing.T) {
 		def   fastjet.JetDefinition
 		area  fastjet.AreaDefinition
 		ptmin float64
+		tol   float64 // tolerance on the jet areas
 	}{
 		{
 			input: "testdata/single-pp-event.dat",
@@ -34,8 +37,9 @@ func TestClusterSequenceArea(t *testing.T) {
 			def: fastjet.NewJetDefinition(
 				fastjet.KtAlgorithm, 1.0, fastjet.EScheme, fastjet.BestStrategy,
 			),
-			area:  fastjet.AreaDefinition{}, // ghost-area, active-area
+			area:  active,
 			ptmin: 5.0,
+			tol:   0.3, // kt active areas fluctuate with the ghosts positions
 		},
 		{
 			input: "testdata/single-pp-event.dat",
@@ -43,8 +47,9 @@ func TestClusterSequenceArea(t *testing.T) {
 			def: fastjet.NewJetDefinition(
 				fastjet.KtAlgorithm, 1.0, fastjet.EScheme, fastjet.BestStrategy,
 			),
-			area:  fastjet.AreaDefinition{}, // ghost-area, passive-area
+			area:  passive,
 			ptmin: 5.0,
+			tol:   1e-3, // passive kt areas are Voronoi areas
 		},
 		{
 			input: "testdata/single-pp-event.dat",
@@ -52,8 +57,9 @@ func TestClusterSequenceArea(t *testing.T) {
 			def: fastjet.NewJetDefinition(
 				fastjet.AntiKtAlgorithm, 1.0, fastjet.EScheme, fastjet.BestStrategy,
 			),
-			area:  fastjet.AreaDefinition{}, // ghost-area, active-area
+			area:  active,
 			ptmin: 5.0,
+			tol:   0.1,
 		},
 		{
 			input: "testdata/single-pp-event.dat",
@@ -61,23 +67,17 @@ func TestClusterSequenceArea(t *testing.T) {
 			def: fastjet.NewJetDefinition(
 				fastjet.AntiKtAlgorithm, 1.0, fastjet.EScheme, fastjet.BestStrategy,
 			),
-			area:  fastjet.AreaDefinition{}, // ghost-area, passive-area
+			area:  passive,
 			ptmin: 5.0,
+			tol:   0.1,
 		},
 	} {
+		test := test
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
 
-			// TODO
-			if strings.Contains(test.name, "passive") {
-				t.Skipf("passive area: not implemented")
-			}
-			// TODO
-			if strings.Contains(test.name, "active") {
-				t.Skipf("active area: not implemented")
-			}
 			test := test
-			particles, err := loadParticles(test.input)
+			particles, err := fjtest.LoadParticles(test.input)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -117,9 +117,16 @@ func TestClusterSequenceArea(t *testing.T) {
 				area := csa.Area(jet)
 				areaErr := csa.AreaErr(jet)
 
-				got := []float64{rap, phi, pt, area, areaErr}
-				if !scalar.EqualApprox(got, ref, tol) {
-					t.Errorf("#%d\ngot= %v\nwant=%v", i, got, ref)
+				if !scalar.EqualWithinAbs(rap, ref[0], 1e-5) ||
+					!scalar.EqualWithinAbs(phi, ref[1], 1e-5) ||
+					!scalar.EqualWithinAbs(pt, ref[2], 1e-3) {
+					t.Errorf("#%d: invalid jet kinematics:\ngot= %v\nwant=%v", i, []float64{rap, phi, pt}, ref[:3])
+				}
+				if !scalar.EqualWithinAbs(area, ref[3], test.tol) {
+					t.Errorf("#%d: invalid jet area: got=%v, want=%v", i, area, ref[3])
+				}
+				if !scalar.EqualWithinAbs(areaErr, ref[4], test.tol) {
+					t.Errorf("#%d: invalid jet area error: got=%v, want=%v", i, areaErr, ref[4])
 				}
 			}
 		})
@@ -127,6 +134,186 @@ func TestClusterSequenceArea(t *testing.T) {
 
 }
 
+func TestJetAreaSingleParticle(t *testing.T) {
+	const (
+		r   = 0.6
+		rap = 0.5
+		phi = 1.0
+	)
+	var (
+		coarse = fastjet.NewGhostedAreaSpec(2)
+		def    = fastjet.NewJetDefinition(fastjet.AntiKtAlgorithm, r, fastjet.EScheme, fastjet.BestStrategy)
+		want   = math.Pi * r * r
+	)
+	coarse.GhostArea = 0.02
+
+	for _, tc := range []struct {
+		area fastjet.AreaDefinition
+		tol  float64
+	}{
+		{
+			area: fastjet.NewAreaDefinition(fastjet.ActiveArea, fastjet.NewGhostedAreaSpec(3)),
+			tol:  0.05,
+		},
+		{
+			area: fastjet.NewAreaDefinition(fastjet.ActiveAreaExplicitGhosts, fastjet.NewGhostedAreaSpec(3)),
+			tol:  0.05,
+		},
+		{
+			area: fastjet.NewAreaDefinition(fastjet.PassiveArea, fastjet.NewGhostedAreaSpec(3)),
+			tol:  0.05,
+		},
+		{
+			area: fastjet.NewAreaDefinition(fastjet.OneGhostPassiveArea, coarse),
+			tol:  0.1,
+		},
+		{
+			area: fastjet.NewVoronoiAreaDefinition(fastjet.NewVoronoiAreaSpec(1)),
+			tol:  1e-12,
+		},
+	} {
+		t.Run(tc.area.AreaType().String(), func(t *testing.T) {
+			particles := []fastjet.Jet{fjtest.NewJetPtYPhi(100, rap, phi)}
+			csa, err := fastjet.NewClusterSequenceArea(particles, def, tc.area)
+			if err != nil {
+				t.Fatalf("could not run clustering: %+v", err)
+			}
+
+			jets, err := csa.InclusiveJets(1)
+			if err != nil {
+				t.Fatalf("could not retrieve inclusive jets: %+v", err)
+			}
+			if got, want := len(jets), 1; got != want {
+				t.Fatalf("invalid number of jets: got=%d, want=%d", got, want)
+			}
+
+			jet := &jets[0]
+			if !jet.HasArea() {
+				t.Fatalf("jet has no area")
+			}
+			if jet.IsPureGhost() {
+				t.Fatalf("hard jet is a pure ghost jet")
+			}
+			if got := jet.Area(); !scalar.EqualWithinAbs(got, want, tc.tol) {
+				t.Fatalf("invalid area: got=%v, want=%v", got, want)
+			}
+			if got, want := jet.Area(), csa.Area(jet); got != want {
+				t.Fatalf("invalid area: jet=%v, csa=%v", got, want)
+			}
+
+			// the 4-vector area is aligned with the jet.
+			area4 := jet.Area4()
+			if got, want := area4.Pt(), jet.Area(); got > want*(1+1e-12) || got < 0.95*want {
+				t.Fatalf("invalid 4-vector area pt: got=%v, area=%v", got, want)
+			}
+			if got, want := area4.Rapidity(), rap; !scalar.EqualWithinAbs(got, want, 0.05) {
+				t.Fatalf("invalid 4-vector area rapidity: got=%v, want=%v", got, want)
+			}
+			if got, want := area4.Phi(), phi; !scalar.EqualWithinAbs(got, want, 0.05) {
+				t.Fatalf("invalid 4-vector area phi: got=%v, want=%v", got, want)
+			}
+		})
+	}
+}
+
+func TestJetAreaExplicitGhosts(t *testing.T) {
+	particles, err := fjtest.LoadParticles("testdata/single-pp-event.dat")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		spec = fastjet.NewGhostedAreaSpec(5)
+		def  = fastjet.NewJetDefinition(fastjet.KtAlgorithm, 0.6, fastjet.EScheme, fastjet.BestStrategy)
+		area = fastjet.NewAreaDefinition(fastjet.ActiveAreaExplicitGhosts, spec)
+	)
+
+	csa, err := fastjet.NewClusterSequenceArea(particles, def, area)
+	if err != nil {
+		t.Fatalf("could not run clustering: %+v", err)
+	}
+	if !csa.HasExplicitGhosts() {
+		t.Fatalf("expected explicit ghosts")
+	}
+
+	jets, err := csa.InclusiveJets(0)
+	if err != nil {
+		t.Fatalf("could not retrieve inclusive jets: %+v", err)
+	}
+
+	var (
+		total  = 0.0
+		empty  = 0.0
+		nempty = 0
+		nconst = 0
+	)
+	for i := range jets {
+		jet := &jets[i]
+		total += jet.Area()
+		nconst += len(jet.Constituents())
+		if jet.IsPureGhost() {
+			empty += jet.Area()
+			nempty++
+		}
+	}
+
+	if got, want := nconst, len(particles)+spec.NumGhosts(); got != want {
+		t.Fatalf("invalid number of constituents: got=%d, want=%d", got, want)
+	}
+	if got, want := total, float64(spec.NumGhosts())*spec.ActualGhostArea(); !scalar.EqualWithinRel(got, want, 1e-12) {
+		t.Fatalf("invalid total area: got=%v, want=%v", got, want)
+	}
+	if nempty == 0 {
+		t.Fatalf("expected pure ghost jets")
+	}
+
+	all := fastjet.SelectorAbsRapMax(10)
+	got, err := csa.EmptyArea(all)
+	if err != nil {
+		t.Fatalf("could not compute empty area: %+v", err)
+	}
+	if !scalar.EqualWithinRel(got, empty, 1e-12) {
+		t.Fatalf("invalid empty area: got=%v, want=%v", got, empty)
+	}
+
+	n, err := csa.NumEmptyJets(all)
+	if err != nil {
+		t.Fatalf("could not compute number of empty jets: %+v", err)
+	}
+	if got, want := n, float64(nempty); got != want {
+		t.Fatalf("invalid number of empty jets: got=%v, want=%v", got, want)
+	}
+}
+
+func TestVoronoiEmptyArea(t *testing.T) {
+	const r = 0.6
+	var (
+		particles = []fastjet.Jet{fjtest.NewJetPtYPhi(100, 0.5, 1), fjtest.NewJetPtYPhi(50, 3, 1)}
+		def       = fastjet.NewJetDefinition(fastjet.AntiKtAlgorithm, r, fastjet.EScheme, fastjet.BestStrategy)
+		area      = fastjet.NewVoronoiAreaDefinition(fastjet.NewVoronoiAreaSpec(0.9))
+		sel       = fastjet.SelectorAbsRapMax(2)
+	)
+
+	csa, err := fastjet.NewClusterSequenceArea(particles, def, area)
+	if err != nil {
+		t.Fatalf("could not run clustering: %+v", err)
+	}
+
+	got, err := csa.EmptyArea(sel)
+	if err != nil {
+		t.Fatalf("could not compute empty area: %+v", err)
+	}
+	want := 2*math.Pi*4 - math.Pi*(0.9*r)*(0.9*r)
+	if !scalar.EqualWithinRel(got, want, 1e-12) {
+		t.Fatalf("invalid empty area: got=%v, want=%v", got, want)
+	}
+
+	_, err = csa.EmptyArea(fastjet.SelectorPtMin(5))
+	if err == nil {
+		t.Fatalf("expected an error for a selector without area")
+	}
+}
+
 func loadRefAreas(name string) ([][5]float64, error) {
 	f, err := os.Open(name)
 	if err != nil {
@@ -151,4 +338,3 @@ func loadRefAreas(name string) ([][5]float64, error) {
 	}
 	return refs, nil
 }
-*/
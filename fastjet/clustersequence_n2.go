@@ -0,0 +1,176 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fastjet
+
+import (
+	"math"
+)
+
+// briefJet holds the minimal information about a jet needed by the
+// nearest-neighbours based clustering strategies.
+type briefJet struct {
+	eta    float64 // rapidity
+	phi    float64
+	kt2    float64 // jet scale for the clustering algorithm
+	nnDist float64 // distance to the nearest neighbour
+	nn     int     // index of the nearest neighbour (-1 if none)
+	idx    int     // index of the jet in the cluster sequence
+}
+
+func (cs *ClusterSequence) setBriefJet(bj *briefJet, idx int) {
+	jet := &cs.jets[idx]
+	bj.eta = jet.Rapidity()
+	bj.phi = jet.Phi()
+	bj.kt2 = cs.jetScaleForAlgorithm(jet)
+	bj.nnDist = cs.r2
+	bj.nn = -1
+	bj.idx = idx
+}
+
+// briefDist returns the squared cylinder distance between two jets.
+func briefDist(a, b *briefJet) float64 {
+	dphi := math.Pi - math.Abs(math.Pi-math.Abs(a.phi-b.phi))
+	deta := a.eta - b.eta
+	return dphi*dphi + deta*deta
+}
+
+// briefDiJ returns the distance of a jet to its nearest neighbour (or to
+// the beam), multiplied by R^2.
+func briefDiJ(bjs []briefJet, i int) float64 {
+	jet := &bjs[i]
+	kt2 := jet.kt2
+	if jet.nn >= 0 && bjs[jet.nn].kt2 < kt2 {
+		kt2 = bjs[jet.nn].kt2
+	}
+	return jet.nnDist * kt2
+}
+
+// runN2Plain runs the clustering with a plain O(N^2) strategy, caching
+// the nearest neighbour of each jet, as FastJet's simple N2 clustering.
+func (cs *ClusterSequence) runN2Plain() error {
+	var (
+		n   = len(cs.jets)
+		bjs = make([]briefJet, n)
+		dij = make([]float64, n)
+	)
+
+	for i := range bjs {
+		cs.setBriefJet(&bjs[i], i)
+	}
+
+	// set up the nearest neighbours.
+	for i := 1; i < n; i++ {
+		ii := &bjs[i]
+		for j := 0; j < i; j++ {
+			jj := &bjs[j]
+			dist := briefDist(ii, jj)
+			if dist < ii.nnDist {
+				ii.nnDist = dist
+				ii.nn = j
+			}
+			if dist < jj.nnDist {
+				jj.nnDist = dist
+				jj.nn = i
+			}
+		}
+	}
+	for i := range dij {
+		dij[i] = briefDiJ(bjs, i)
+	}
+
+	for ; n > 0; n-- {
+		// find the minimum of the dij.
+		ia := 0
+		dmin := dij[0]
+		for i := 1; i < n; i++ {
+			if dij[i] < dmin {
+				dmin = dij[i]
+				ia = i
+			}
+		}
+		dmin *= cs.invR2
+
+		ib := bjs[ia].nn
+		if ib >= 0 {
+			// make sure ib < ia, so that if ia is the tail, the new jet
+			// stored in ib is put in a position that has a future.
+			if ia < ib {
+				ia, ib = ib, ia
+			}
+			k, err := cs.ijRecombinationStep(bjs[ia].idx, bjs[ib].idx, dmin)
+			if err != nil {
+				return err
+			}
+			cs.setBriefJet(&bjs[ib], k)
+		} else {
+			err := cs.ibRecombinationStep(bjs[ia].idx, dmin)
+			if err != nil {
+				return err
+			}
+		}
+
+		// remove ia from the table, moving the tail in its place.
+		tail := n - 1
+		bjs[ia] = bjs[tail]
+		dij[ia] = dij[tail]
+
+		// update the nearest neighbours.
+		for i := 0; i < tail; i++ {
+			ii := &bjs[i]
+			if ii.nn == ia || (ib >= 0 && ii.nn == ib) {
+				cs.setNN(bjs[:tail], i)
+				dij[i] = briefDiJ(bjs, i)
+			}
+			if ib >= 0 {
+				dist := briefDist(ii, &bjs[ib])
+				if dist < ii.nnDist && i != ib {
+					ii.nnDist = dist
+					ii.nn = ib
+					dij[i] = briefDiJ(bjs, i)
+				}
+				if dist < bjs[ib].nnDist && i != ib {
+					bjs[ib].nnDist = dist
+					bjs[ib].nn = i
+				}
+			}
+			if ii.nn == tail {
+				ii.nn = ia
+			}
+		}
+		if ib >= 0 {
+			dij[ib] = briefDiJ(bjs, ib)
+		}
+	}
+
+	return nil
+}
+
+// setNN sets the nearest neighbour of the i-th jet, among all the jets.
+func (cs *ClusterSequence) setNN(bjs []briefJet, i int) {
+	jet := &bjs[i]
+	jet.nnDist = cs.r2
+	jet.nn = -1
+	for j := range bjs {
+		if j == i {
+			continue
+		}
+		dist := briefDist(jet, &bjs[j])
+		if dist < jet.nnDist {
+			jet.nnDist = dist
+			jet.nn = j
+		}
+	}
+}
+
+// isPPAlgorithm returns whether the algorithm is a hadron-collider
+// (longitudinally invariant) algorithm.
+func isPPAlgorithm(alg JetAlgorithm) bool {
+	switch alg {
+	case KtAlgorithm, CambridgeAlgorithm, AntiKtAlgorithm, GenKtAlgorithm,
+		CambridgeForPassiveAlgorithm:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,319 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fastjet_test
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+
+	"go-hep.org/x/hep/fastjet"
+	"go-hep.org/x/hep/fastjet/internal/fjtest"
+)
+
+func TestStrategies(t *testing.T) {
+	pp, err := fjtest.LoadParticles("testdata/single-pp-event.dat")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, evt := range []struct {
+		name       string
+		particles  []fastjet.Jet
+		strategies []fastjet.Strategy
+	}{
+		{
+			name:      "pp",
+			particles: pp,
+			strategies: []fastjet.Strategy{
+				fastjet.N3DumbStrategy,
+				fastjet.N2PlainStrategy,
+				fastjet.N2TiledStrategy,
+				fastjet.N2PoorTiledStrategy,
+				fastjet.N2MinHeapTiledStrategy,
+				fastjet.BestStrategy,
+			},
+		},
+		{
+			name:      "uniform",
+			particles: uniformEvent(2000, 6, 42),
+			strategies: []fastjet.Strategy{
+				fastjet.N2PlainStrategy,
+				fastjet.N2TiledStrategy,
+				fastjet.N2MinHeapTiledStrategy,
+				fastjet.BestStrategy,
+			},
+		},
+	} {
+		for _, alg := range []struct {
+			name  string
+			alg   fastjet.JetAlgorithm
+			extra float64
+		}{
+			{name: "kt", alg: fastjet.KtAlgorithm},
+			{name: "cam", alg: fastjet.CambridgeAlgorithm},
+			{name: "antikt", alg: fastjet.AntiKtAlgorithm},
+			{name: "genkt", alg: fastjet.GenKtAlgorithm, extra: 0.5},
+		} {
+			for _, r := range []float64{0.4, 1.0} {
+				name := fmt.Sprintf("%s-%s-r%v", evt.name, alg.name, r)
+				t.Run(name, func(t *testing.T) {
+					var want []fastjet.Jet
+					for i, strategy := range evt.strategies {
+						def := fastjet.NewJetDefinitionExtra(alg.alg, r, fastjet.EScheme, strategy, alg.extra)
+						cs, err := fastjet.NewClusterSequence(evt.particles, def)
+						if err != nil {
+							t.Fatalf("could not run clustering with %v: %+v", strategy, err)
+						}
+						incl, err := cs.InclusiveJets(0)
+						if err != nil {
+							t.Fatalf("could not retrieve inclusive jets with %v: %+v", strategy, err)
+						}
+						// jets are sorted, as the order of beam recombinations
+						// with equal distances (e.g. for Cambridge/Aachen)
+						// depends on the strategy.
+						sort.Sort(fastjet.ByPt(incl))
+						got := incl
+						if alg.alg != fastjet.CambridgeAlgorithm {
+							// exclusive jets also depend on the order of
+							// the beam recombinations.
+							excl, err := cs.ExclusiveJetsUpTo(10)
+							if err != nil {
+								t.Fatalf("could not retrieve exclusive jets with %v: %+v", strategy, err)
+							}
+							sort.Sort(fastjet.ByPt(excl))
+							got = append(got, excl...)
+						}
+						if i == 0 {
+							want = got
+							continue
+						}
+						if len(got) != len(want) {
+							t.Fatalf("%v: invalid number of jets: got=%d, want=%d", strategy, len(got), len(want))
+						}
+						for j := range got {
+							if got[j].PxPyPzE != want[j].PxPyPzE {
+								t.Fatalf("%v: invalid jet #%d:\ngot= %v\nwant=%v", strategy, j, got[j].PxPyPzE, want[j].PxPyPzE)
+							}
+						}
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestStrategyNotSupported(t *testing.T) {
+	particles, err := fjtest.LoadParticles("testdata/single-ee-event.dat")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, strategy := range []fastjet.Strategy{
+		fastjet.N2PlainStrategy,
+		fastjet.N2TiledStrategy,
+		fastjet.N2MinHeapTiledStrategy,
+	} {
+		def := fastjet.NewJetDefinitionExtra(fastjet.EeKtAlgorithm, 1, fastjet.EScheme, strategy, 1)
+		_, err := fastjet.NewClusterSequence(particles, def)
+		if err == nil {
+			t.Fatalf("%v: expected an error", strategy)
+		}
+	}
+}
+
+func BenchmarkStrategies(b *testing.B) {
+	particles := uniformEvent(2000, 5, 42)
+	for _, strategy := range []fastjet.Strategy{
+		fastjet.N2PlainStrategy,
+		fastjet.N2TiledStrategy,
+		fastjet.N2MinHeapTiledStrategy,
+	} {
+		def := fastjet.NewJetDefinition(fastjet.AntiKtAlgorithm, 0.4, fastjet.EScheme, strategy)
+		b.Run(strategy.String(), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, err := fastjet.NewClusterSequence(particles, def)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestParents(t *testing.T) {
+	particles := []fastjet.Jet{
+		fjtest.NewJetPtYPhi(100, 0, 0),
+		fjtest.NewJetPtYPhi(50, 0.5, 0),
+		fjtest.NewJetPtYPhi(10, 0, 0.8),
+	}
+	def := fastjet.NewJetDefinition(fastjet.CambridgeAlgorithm, 1.0, fastjet.EScheme, fastjet.BestStrategy)
+	cs, err := fastjet.NewClusterSequence(particles, def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jets, err := cs.InclusiveJets(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(jets), 1; got != want {
+		t.Fatalf("invalid number of jets: got=%d, want=%d", got, want)
+	}
+
+	p1, p2, ok := cs.Parents(&jets[0])
+	if !ok {
+		t.Fatalf("jet should have parents")
+	}
+	if got, want := p1.Pt(), 150.0; math.Abs(got-want) > 1e-6 {
+		t.Fatalf("invalid first parent pt: got=%v, want=%v", got, want)
+	}
+	if got, want := p2.Pt(), 10.0; math.Abs(got-want) > 1e-6 {
+		t.Fatalf("invalid second parent pt: got=%v, want=%v", got, want)
+	}
+
+	p11, p12, ok := cs.Parents(&p1)
+	if !ok {
+		t.Fatalf("first parent should have parents")
+	}
+	if got, want := p11.Pt(), 100.0; math.Abs(got-want) > 1e-6 {
+		t.Fatalf("invalid pt: got=%v, want=%v", got, want)
+	}
+	if got, want := p12.Pt(), 50.0; math.Abs(got-want) > 1e-6 {
+		t.Fatalf("invalid pt: got=%v, want=%v", got, want)
+	}
+
+	if _, _, ok := cs.Parents(&p2); ok {
+		t.Fatalf("original particle should not have parents")
+	}
+}
+
+func TestChildPartner(t *testing.T) {
+	particles := []fastjet.Jet{
+		fjtest.NewJetPtYPhi(100, 0, 0),
+		fjtest.NewJetPtYPhi(50, 0.5, 0),
+		fjtest.NewJetPtYPhi(10, 0, 0.8),
+	}
+	def := fastjet.NewJetDefinition(fastjet.CambridgeAlgorithm, 1.0, fastjet.EScheme, fastjet.BestStrategy)
+	cs, err := fastjet.NewClusterSequence(particles, def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jets, err := cs.InclusiveJets(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(jets), 1; got != want {
+		t.Fatalf("invalid number of jets: got=%d, want=%d", got, want)
+	}
+
+	if _, ok := cs.Child(&jets[0]); ok {
+		t.Fatalf("final jet should not have a child")
+	}
+	if _, ok := cs.Partner(&jets[0]); ok {
+		t.Fatalf("final jet should not have a partner")
+	}
+
+	p1, p2, ok := cs.Parents(&jets[0])
+	if !ok {
+		t.Fatalf("jet should have parents")
+	}
+	child, ok := cs.Child(&p2)
+	if !ok {
+		t.Fatalf("parent should have a child")
+	}
+	if got, want := child.Pt(), jets[0].Pt(); math.Abs(got-want) > 1e-6 {
+		t.Fatalf("invalid child pt: got=%v, want=%v", got, want)
+	}
+	partner, ok := cs.Partner(&p2)
+	if !ok {
+		t.Fatalf("parent should have a partner")
+	}
+	if got, want := partner.Pt(), p1.Pt(); math.Abs(got-want) > 1e-6 {
+		t.Fatalf("invalid partner pt: got=%v, want=%v", got, want)
+	}
+}
+
+func TestExclusiveSubjets(t *testing.T) {
+	const tol = 1e-6
+
+	ee, err := fjtest.LoadParticles("testdata/single-ee-event.dat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// remove the particles along the beam axis so that, with a large
+	// radius, all the particles are clustered into one jet.
+	ee = fastjet.SelectorPtMin(1e-6).Select(ee)
+	def := fastjet.NewJetDefinition(fastjet.KtAlgorithm, 100, fastjet.EScheme, fastjet.BestStrategy)
+	cs, err := fastjet.NewClusterSequence(ee, def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jets, err := cs.InclusiveJets(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(jets), 1; got != want {
+		t.Fatalf("invalid number of jets: got=%d, want=%d", got, want)
+	}
+
+	for _, n := range []int{1, 2, 3, 4, 6} {
+		t.Run(fmt.Sprintf("n=%d", n), func(t *testing.T) {
+			want, err := cs.ExclusiveJetsUpTo(n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := cs.ExclusiveSubjetsUpTo(&jets[0], n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("invalid number of subjets: got=%d, want=%d", len(got), len(want))
+			}
+			sort.Sort(fastjet.ByE(got))
+			sort.Sort(fastjet.ByE(want))
+			for i := range got {
+				if math.Abs(got[i].E()-want[i].E()) > tol {
+					t.Fatalf("subjet[%d]: invalid energy: got=%v, want=%v", i, got[i].E(), want[i].E())
+				}
+			}
+
+			// the subjets obtained with the merging scale of the n-th
+			// clustering step must match.
+			dcut := cs.ExclusiveDmerge(n)
+			subs, err := cs.ExclusiveSubjets(&jets[0], dcut)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(subs), n; got != want {
+				t.Fatalf("invalid number of subjets for dcut=%v: got=%d, want=%d", dcut, got, want)
+			}
+			nsub, err := cs.NumExclusiveSubjets(&jets[0], dcut)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := nsub, n; got != want {
+				t.Fatalf("invalid number of subjets: got=%d, want=%d", got, want)
+			}
+
+			if n < 2 {
+				return
+			}
+			// any ycut between two consecutive merging scales yields n jets.
+			ycut := math.Sqrt(cs.ExclusiveYmerge(n) * cs.ExclusiveYmerge(n-1))
+			ejets, err := cs.ExclusiveJetsYcut(ycut)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(ejets), n; got != want {
+				t.Fatalf("invalid number of jets for ycut=%v: got=%d, want=%d", ycut, got, want)
+			}
+		})
+	}
+
+	if got, want := cs.ExclusiveDmergeMax(2), cs.ExclusiveDmerge(2); got < want {
+		t.Fatalf("invalid dmerge-max: got=%v < %v", got, want)
+	}
+}
@@ -0,0 +1,406 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fastjet
+
+import (
+	"math"
+
+	"go-hep.org/x/hep/fastjet/internal/heap"
+)
+
+// tiledJet is a briefJet registered in a tile of the rapidity-azimuth plane.
+type tiledJet struct {
+	briefJet
+	tile int // index of the tile holding this jet
+	prev int // previous jet in the tile (-1 if none)
+	next int // next jet in the tile (-1 if none)
+}
+
+// tile is a cell of the rapidity-azimuth plane, holding a linked list of jets.
+type tile struct {
+	head int // first jet in the tile (-1 if empty)
+
+	// neighbours holds the index of this tile, followed by the indices of
+	// the surrounding tiles.
+	// Tiles from neighbours[rh:] are on the "right-hand" side of this tile.
+	neighbours []int
+	rh         int
+
+	tagged bool
+}
+
+// tiling is a tiling of the rapidity-azimuth plane, with tiles of size
+// larger than R, so that the nearest neighbour of a jet (within R) is
+// always in the same tile or in one of its surrounding tiles.
+type tiling struct {
+	etaMin  float64
+	etaMax  float64
+	ietaMin int
+	ietaMax int
+	nphi    int
+	dEta    float64
+	dPhi    float64
+
+	tiles []tile
+}
+
+func (cs *ClusterSequence) newTiling() *tiling {
+	const maxrap = 7.0
+
+	var (
+		size = math.Max(0.1, cs.r)
+		nphi = imax(3, int(math.Floor(2*math.Pi/size)))
+		tl   = tiling{
+			dEta: size,
+			nphi: nphi,
+			dPhi: 2 * math.Pi / float64(nphi),
+		}
+	)
+
+	// always include zero rapidity in the tiling region.
+	for i := range cs.jets {
+		eta := cs.jets[i].Rapidity()
+		if math.Abs(eta) < maxrap {
+			tl.etaMin = math.Min(tl.etaMin, eta)
+			tl.etaMax = math.Max(tl.etaMax, eta)
+		}
+	}
+	tl.ietaMin = int(math.Floor(tl.etaMin / tl.dEta))
+	tl.ietaMax = int(math.Floor(tl.etaMax / tl.dEta))
+	tl.etaMin = float64(tl.ietaMin) * tl.dEta
+	tl.etaMax = float64(tl.ietaMax) * tl.dEta
+
+	neta := tl.ietaMax - tl.ietaMin + 1
+	tl.tiles = make([]tile, neta*nphi)
+	for ieta := 0; ieta < neta; ieta++ {
+		for iphi := 0; iphi < nphi; iphi++ {
+			var (
+				t     = &tl.tiles[tl.index(ieta, iphi)]
+				left  = (iphi + nphi - 1) % nphi
+				right = (iphi + 1) % nphi
+			)
+			t.head = -1
+			t.neighbours = make([]int, 0, 9)
+			t.neighbours = append(t.neighbours, tl.index(ieta, iphi))
+			if ieta > 0 {
+				t.neighbours = append(t.neighbours,
+					tl.index(ieta-1, left),
+					tl.index(ieta-1, iphi),
+					tl.index(ieta-1, right),
+				)
+			}
+			t.neighbours = append(t.neighbours, tl.index(ieta, left))
+			t.rh = len(t.neighbours)
+			t.neighbours = append(t.neighbours, tl.index(ieta, right))
+			if ieta < neta-1 {
+				t.neighbours = append(t.neighbours,
+					tl.index(ieta+1, left),
+					tl.index(ieta+1, iphi),
+					tl.index(ieta+1, right),
+				)
+			}
+		}
+	}
+	return &tl
+}
+
+func (tl *tiling) index(ieta, iphi int) int {
+	return ieta*tl.nphi + iphi
+}
+
+// tileOf returns the index of the tile containing the (eta, phi) point.
+func (tl *tiling) tileOf(eta, phi float64) int {
+	var (
+		ieta int
+		neta = tl.ietaMax - tl.ietaMin
+	)
+	switch {
+	case eta <= tl.etaMin:
+		ieta = 0
+	case eta >= tl.etaMax:
+		ieta = neta
+	default:
+		ieta = imin(int((eta-tl.etaMin)/tl.dEta), neta)
+	}
+	iphi := int((phi+2*math.Pi)/tl.dPhi) % tl.nphi
+	return tl.index(ieta, iphi)
+}
+
+// add registers the i-th jet in its tile.
+func (tl *tiling) add(tjs []tiledJet, i int) {
+	jet := &tjs[i]
+	jet.tile = tl.tileOf(jet.eta, jet.phi)
+	t := &tl.tiles[jet.tile]
+	jet.prev = -1
+	jet.next = t.head
+	if jet.next >= 0 {
+		tjs[jet.next].prev = i
+	}
+	t.head = i
+}
+
+// remove removes the i-th jet from its tile.
+func (tl *tiling) remove(tjs []tiledJet, i int) {
+	jet := &tjs[i]
+	switch {
+	case jet.prev < 0:
+		tl.tiles[jet.tile].head = jet.next
+	default:
+		tjs[jet.prev].next = jet.next
+	}
+	if jet.next >= 0 {
+		tjs[jet.next].prev = jet.prev
+	}
+}
+
+// addNeighbours adds the tile and its surrounding tiles to the union of
+// tiles, if they are not already part of it.
+func (tl *tiling) addNeighbours(union []int, it int) []int {
+	for _, i := range tl.tiles[it].neighbours {
+		t := &tl.tiles[i]
+		if t.tagged {
+			continue
+		}
+		t.tagged = true
+		union = append(union, i)
+	}
+	return union
+}
+
+// dijTable holds the distances of jets to their nearest neighbours (or
+// to the beam) and gives access to the smallest one.
+type dijTable interface {
+	min() (i int, dij float64)
+	set(i int, dij float64)
+	remove(i int)
+}
+
+// dijArray is a dijTable that scans all distances to find the smallest one.
+type dijArray struct {
+	dij []float64 // distances, packed in the first n entries
+	jet []int     // jet associated with each distance
+	pos []int     // position of each jet in the distances table
+	n   int
+}
+
+func newDijArray(vs []float64) *dijArray {
+	tbl := &dijArray{
+		dij: make([]float64, len(vs)),
+		jet: make([]int, len(vs)),
+		pos: make([]int, len(vs)),
+		n:   len(vs),
+	}
+	copy(tbl.dij, vs)
+	for i := range vs {
+		tbl.jet[i] = i
+		tbl.pos[i] = i
+	}
+	return tbl
+}
+
+func (tbl *dijArray) min() (int, float64) {
+	imin := 0
+	dmin := tbl.dij[0]
+	for i, v := range tbl.dij[1:tbl.n] {
+		if v < dmin {
+			dmin = v
+			imin = i + 1
+		}
+	}
+	return tbl.jet[imin], dmin
+}
+
+func (tbl *dijArray) set(i int, dij float64) {
+	tbl.dij[tbl.pos[i]] = dij
+}
+
+func (tbl *dijArray) remove(i int) {
+	var (
+		pos  = tbl.pos[i]
+		last = tbl.n - 1
+	)
+	tbl.dij[pos] = tbl.dij[last]
+	tbl.jet[pos] = tbl.jet[last]
+	tbl.pos[tbl.jet[pos]] = pos
+	tbl.n--
+}
+
+// dijHeap is a dijTable that keeps the distances in a min-heap.
+type dijHeap struct {
+	h *heap.MinHeap
+}
+
+func newDijHeap(vs []float64) *dijHeap {
+	return &dijHeap{h: heap.NewMinHeap(vs)}
+}
+
+func (tbl *dijHeap) min() (int, float64)    { return tbl.h.MinLoc(), tbl.h.MinValue() }
+func (tbl *dijHeap) set(i int, dij float64) { tbl.h.Update(i, dij) }
+func (tbl *dijHeap) remove(i int)           { tbl.h.Remove(i) }
+
+// runN2Tiled runs the clustering with a tiled O(N^2) strategy.
+func (cs *ClusterSequence) runN2Tiled() error {
+	return cs.runTiled(func(vs []float64) dijTable { return newDijArray(vs) })
+}
+
+// runN2MinHeapTiled runs the clustering with a tiled O(N^2) strategy,
+// where the smallest distance is found with a min-heap.
+func (cs *ClusterSequence) runN2MinHeapTiled() error {
+	return cs.runTiled(func(vs []float64) dijTable { return newDijHeap(vs) })
+}
+
+// runTiled runs the clustering, restricting the nearest neighbours searches
+// to the surrounding tiles of a tiling of the rapidity-azimuth plane, as
+// FastJet's tiled N2 clustering.
+func (cs *ClusterSequence) runTiled(newTable func(vs []float64) dijTable) error {
+	var (
+		n     = len(cs.jets)
+		tl    = cs.newTiling()
+		tjs   = make([]tiledJet, n)
+		union = make([]int, 0, 3*9)
+	)
+
+	for i := range tjs {
+		cs.setBriefJet(&tjs[i].briefJet, i)
+		tl.add(tjs, i)
+	}
+
+	// set up the nearest neighbours.
+	for it := range tl.tiles {
+		t := &tl.tiles[it]
+		for i := t.head; i >= 0; i = tjs[i].next {
+			for j := t.head; j != i; j = tjs[j].next {
+				tiledNN(tjs, i, j)
+			}
+		}
+		for _, rt := range t.neighbours[t.rh:] {
+			for i := t.head; i >= 0; i = tjs[i].next {
+				for j := tl.tiles[rt].head; j >= 0; j = tjs[j].next {
+					tiledNN(tjs, i, j)
+				}
+			}
+		}
+	}
+
+	dij := make([]float64, n)
+	for i := range dij {
+		dij[i] = tiledDiJ(tjs, i)
+	}
+	tbl := newTable(dij)
+
+	for ; n > 0; n-- {
+		ia, dmin := tbl.min()
+		dmin *= cs.invR2
+
+		var (
+			ib      = tjs[ia].nn
+			oldTile = -1
+		)
+		switch {
+		case ib >= 0:
+			k, err := cs.ijRecombinationStep(tjs[ia].idx, tjs[ib].idx, dmin)
+			if err != nil {
+				return err
+			}
+			// what was jet B now becomes the new jet.
+			tl.remove(tjs, ia)
+			oldTile = tjs[ib].tile
+			tl.remove(tjs, ib)
+			cs.setBriefJet(&tjs[ib].briefJet, k)
+			tl.add(tjs, ib)
+		default:
+			err := cs.ibRecombinationStep(tjs[ia].idx, dmin)
+			if err != nil {
+				return err
+			}
+			tl.remove(tjs, ia)
+		}
+		tbl.remove(ia)
+
+		// establish the set of tiles over which nearest neighbours
+		// need to be updated.
+		union = tl.addNeighbours(union[:0], tjs[ia].tile)
+		if ib >= 0 {
+			union = tl.addNeighbours(union, tjs[ib].tile)
+			union = tl.addNeighbours(union, oldTile)
+		}
+
+		for _, it := range union {
+			t := &tl.tiles[it]
+			t.tagged = false
+			for i := t.head; i >= 0; i = tjs[i].next {
+				jet := &tjs[i]
+				// see if jet I had jet A or jet B as nearest neighbour.
+				// if so, recompute its nearest neighbour.
+				if jet.nn == ia || (ib >= 0 && jet.nn == ib) {
+					jet.nnDist = cs.r2
+					jet.nn = -1
+					for _, nt := range t.neighbours {
+						for j := tl.tiles[nt].head; j >= 0; j = tjs[j].next {
+							if j == i {
+								continue
+							}
+							dist := briefDist(&jet.briefJet, &tjs[j].briefJet)
+							if dist < jet.nnDist {
+								jet.nnDist = dist
+								jet.nn = j
+							}
+						}
+					}
+					tbl.set(i, tiledDiJ(tjs, i))
+				}
+
+				// check whether the new jet B is closer than the current
+				// nearest neighbour of jet I.
+				if ib >= 0 && i != ib {
+					jb := &tjs[ib]
+					dist := briefDist(&jet.briefJet, &jb.briefJet)
+					if dist < jet.nnDist {
+						jet.nnDist = dist
+						jet.nn = ib
+						tbl.set(i, tiledDiJ(tjs, i))
+					}
+					if dist < jb.nnDist {
+						jb.nnDist = dist
+						jb.nn = i
+					}
+				}
+			}
+		}
+		if ib >= 0 {
+			tbl.set(ib, tiledDiJ(tjs, ib))
+		}
+	}
+
+	return nil
+}
+
+// tiledNN updates the nearest neighbours of the i-th and j-th jets.
+func tiledNN(tjs []tiledJet, i, j int) {
+	var (
+		ji   = &tjs[i]
+		jj   = &tjs[j]
+		dist = briefDist(&ji.briefJet, &jj.briefJet)
+	)
+	if dist < ji.nnDist {
+		ji.nnDist = dist
+		ji.nn = j
+	}
+	if dist < jj.nnDist {
+		jj.nnDist = dist
+		jj.nn = i
+	}
+}
+
+// tiledDiJ returns the distance of a jet to its nearest neighbour (or to
+// the beam), multiplied by R^2.
+func tiledDiJ(tjs []tiledJet, i int) float64 {
+	jet := &tjs[i]
+	kt2 := jet.kt2
+	if jet.nn >= 0 && tjs[jet.nn].kt2 < kt2 {
+		kt2 = tjs[jet.nn].kt2
+	}
+	return jet.nnDist * kt2
+}
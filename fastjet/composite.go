@@ -0,0 +1,48 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fastjet
+
+import (
+	"go-hep.org/x/hep/fmom"
+)
+
+// compositeStructure is the structure of a jet made of several pieces.
+type compositeStructure struct {
+	pieces []Jet
+}
+
+// Join returns a jet made of the provided pieces.
+// The 4-momentum of the jet is the sum of the 4-momenta of its pieces and
+// its constituents are the constituents of all its pieces.
+func Join(pieces ...Jet) Jet {
+	var p4 fmom.PxPyPzE
+	for i := range pieces {
+		p4 = addP4(p4, pieces[i].PxPyPzE)
+	}
+	jet := NewJet(p4.Px(), p4.Py(), p4.Pz(), p4.E())
+	jet.structure = compositeStructure{
+		pieces: append([]Jet(nil), pieces...),
+	}
+	return jet
+}
+
+// Constituents returns the constituents of all the pieces of the jet.
+// Pieces without clustering information are their own constituent.
+func (cj compositeStructure) Constituents(jet *Jet) ([]Jet, error) {
+	var o []Jet
+	for i := range cj.pieces {
+		piece := &cj.pieces[i]
+		if piece.structure == nil {
+			o = append(o, *piece)
+			continue
+		}
+		sub, err := piece.structure.Constituents(piece)
+		if err != nil {
+			return nil, err
+		}
+		o = append(o, sub...)
+	}
+	return o, nil
+}
@@ -0,0 +1,70 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fastjet_test
+
+import (
+	"math"
+	"testing"
+
+	"go-hep.org/x/hep/fastjet"
+	"go-hep.org/x/hep/fastjet/internal/fjtest"
+)
+
+func TestJoin(t *testing.T) {
+	particles := []fastjet.Jet{
+		fjtest.NewJetPtYPhi(100, 0, 0),
+		fjtest.NewJetPtYPhi(50, 0.2, 0.1),
+		fjtest.NewJetPtYPhi(20, 2.0, 2.0),
+		fjtest.NewJetPtYPhi(10, 2.2, 2.1),
+	}
+	def := fastjet.NewJetDefinition(fastjet.AntiKtAlgorithm, 0.4, fastjet.EScheme, fastjet.BestStrategy)
+	cs, err := fastjet.NewClusterSequence(particles, def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jets, err := cs.InclusiveJets(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(jets), 2; got != want {
+		t.Fatalf("invalid number of jets: got=%d, want=%d", got, want)
+	}
+
+	extra := fjtest.NewJetPtYPhi(5, -1, -1)
+	jet := fastjet.Join(jets[0], jets[1], extra)
+
+	var px, py, pz, e float64
+	for _, p := range append(particles, extra) {
+		px += p.Px()
+		py += p.Py()
+		pz += p.Pz()
+		e += p.E()
+	}
+	for _, v := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"px", jet.Px(), px},
+		{"py", jet.Py(), py},
+		{"pz", jet.Pz(), pz},
+		{"e", jet.E(), e},
+	} {
+		if math.Abs(v.got-v.want) > 1e-9 {
+			t.Fatalf("invalid %s: got=%v, want=%v", v.name, v.got, v.want)
+		}
+	}
+
+	if got, want := len(jet.Constituents()), len(particles)+1; got != want {
+		t.Fatalf("invalid number of constituents: got=%d, want=%d", got, want)
+	}
+
+	empty := fastjet.Join()
+	if got, want := empty.E(), 0.0; got != want {
+		t.Fatalf("invalid empty jet energy: got=%v, want=%v", got, want)
+	}
+	if got, want := len(empty.Constituents()), 0; got != want {
+		t.Fatalf("invalid number of constituents: got=%d, want=%d", got, want)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cone
+
+import (
+	"fmt"
+
+	"go-hep.org/x/hep/fastjet"
+)
+
+// atlasMaxIters is the maximal number of iterations of an ATLAS cone.
+const atlasMaxIters = 100
+
+// ATLASCone is the ATLAS iterative cone jet algorithm.
+//
+// Stable cones are found by iterating cones from seed particles, by
+// decreasing transverse momentum.
+// The stable cones then go through a split-merge procedure, ordered by
+// transverse momentum.
+//
+// The algorithm is neither infrared nor collinear safe.
+type ATLASCone struct {
+	r    float64
+	f    float64
+	seed float64
+}
+
+// NewATLASCone returns a new ATLAS cone plugin, with a cone radius r,
+// an overlap threshold f for the split-merge step and a seed threshold on
+// the transverse momentum of the seed particles.
+func NewATLASCone(r, f, seed float64) *ATLASCone {
+	return &ATLASCone{
+		r:    r,
+		f:    f,
+		seed: seed,
+	}
+}
+
+// Description returns a string description of the plugin.
+func (ac *ATLASCone) Description() string {
+	return fmt.Sprintf(
+		"ATLASCone plugin with R = %v, seed threshold = %v, overlap threshold f = %v",
+		ac.r, ac.seed, ac.f,
+	)
+}
+
+// R returns the radius of the cones.
+func (ac *ATLASCone) R() float64 {
+	return ac.r
+}
+
+// RunClustering clusters the particles of the builder.
+func (ac *ATLASCone) RunClustering(builder fastjet.Builder) error {
+	pb, err := pluginBuilder(builder)
+	if err != nil {
+		return err
+	}
+
+	var (
+		evt   = newEvent(pb.Jets())
+		parts = indices(len(evt.jets))
+		cones []protojet
+		seen  = make(contentSet)
+	)
+
+	for _, i := range evt.seeds(ac.seed) {
+		pj, ok := evt.iterate(parts, evt.y[i], evt.phi[i], ac.r, atlasMaxIters)
+		if !ok || !seen.add(pj.hash, pj.content) {
+			continue
+		}
+		cones = append(cones, pj)
+	}
+
+	jets := evt.splitMerge(cones, ac.f, 0, scalePt)
+	return record(pb, jets)
+}
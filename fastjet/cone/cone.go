@@ -0,0 +1,386 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package cone implements cone jet algorithms as fastjet plugins:
+//   - SISCone, the seedless infrared-safe cone algorithm,
+//   - the CDF MidPoint cone algorithm,
+//   - the ATLAS iterative cone algorithm.
+//
+// The plugins are registered with default parameters under the "SISCone",
+// "CDFMidPoint" and "ATLASCone" names.
+package cone // import "go-hep.org/x/hep/fastjet/cone"
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"go-hep.org/x/hep/fastjet"
+)
+
+func init() {
+	fastjet.Register("SISCone", NewSISCone(0.7, 0.75, 0, 0))
+	fastjet.Register("CDFMidPoint", NewCDFMidPoint(0.7, 0.5, 1, 1, 2, 100))
+	fastjet.Register("ATLASCone", NewATLASCone(0.7, 0.5, 2))
+}
+
+var (
+	_ fastjet.Plugin = (*SISCone)(nil)
+	_ fastjet.Plugin = (*CDFMidPoint)(nil)
+	_ fastjet.Plugin = (*ATLASCone)(nil)
+)
+
+// event holds the kinematics of the particles to cluster.
+type event struct {
+	jets []fastjet.Jet
+	y    []float64
+	phi  []float64
+	pt   []float64
+	hash []uint64 // random bits identifying each particle, to bucket protojet contents
+}
+
+func newEvent(jets []fastjet.Jet) *event {
+	evt := &event{
+		jets: jets,
+		y:    make([]float64, len(jets)),
+		phi:  make([]float64, len(jets)),
+		pt:   make([]float64, len(jets)),
+		hash: make([]uint64, len(jets)),
+	}
+	for i := range jets {
+		jet := &jets[i]
+		evt.y[i] = jet.Rapidity()
+		evt.phi[i] = jet.Phi()
+		evt.pt[i] = jet.Pt()
+		evt.hash[i] = splitmix64(uint64(i) + 1)
+	}
+	return evt
+}
+
+// dist2 returns the squared rapidity-azimuth distance between the i-th
+// particle and the (y, phi) point.
+func (evt *event) dist2(i int, y, phi float64) float64 {
+	dy := evt.y[i] - y
+	dphi := deltaPhi(evt.phi[i], phi)
+	return dy*dy + dphi*dphi
+}
+
+// inCone returns the particles, among the provided ones, within a distance
+// r of the (y, phi) point.
+func (evt *event) inCone(parts []int, y, phi, r float64) []int {
+	var (
+		r2 = r * r
+		o  []int
+	)
+	for _, i := range parts {
+		if evt.dist2(i, y, phi) < r2 {
+			o = append(o, i)
+		}
+	}
+	return o
+}
+
+// protojet is a candidate jet, made of a set of particles.
+type protojet struct {
+	content []int // indices of the particles, in increasing order
+	hash    uint64
+
+	px, py, pz, e float64
+
+	y       float64
+	phi     float64
+	pt      float64
+	ptTilde float64 // scalar sum of the transverse momenta of the particles
+}
+
+func (evt *event) newProtojet(content []int) protojet {
+	pj := protojet{content: content}
+	sort.Ints(pj.content)
+	for _, i := range content {
+		p := &evt.jets[i]
+		pj.hash ^= evt.hash[i]
+		pj.px += p.Px()
+		pj.py += p.Py()
+		pj.pz += p.Pz()
+		pj.e += p.E()
+		pj.ptTilde += evt.pt[i]
+	}
+	jet := fastjet.NewJet(pj.px, pj.py, pj.pz, pj.e)
+	pj.y = jet.Rapidity()
+	pj.phi = jet.Phi()
+	pj.pt = jet.Pt()
+	return pj
+}
+
+// iterate iterates a cone of radius r, starting from the (y, phi) point,
+// until the axis of the cone matches the 4-momentum of its content.
+// iterate returns false if the cone is empty or if it did not converge
+// within maxIters iterations.
+func (evt *event) iterate(parts []int, y, phi, r float64, maxIters int) (protojet, bool) {
+	var prev protojet
+	for it := 0; it < maxIters; it++ {
+		content := evt.inCone(parts, y, phi, r)
+		if len(content) == 0 {
+			return prev, false
+		}
+		pj := evt.newProtojet(content)
+		if it > 0 && pj.hash == prev.hash && equalContent(pj.content, prev.content) {
+			return pj, true
+		}
+		prev = pj
+		y, phi = pj.y, pj.phi
+	}
+	return prev, false
+}
+
+// scaleFunc returns the variable used to order protojets and to compute
+// their overlaps during the split-merge step.
+type scaleFunc func(pj *protojet) float64
+
+func scalePt(pj *protojet) float64      { return pj.pt }
+func scalePtTilde(pj *protojet) float64 { return pj.ptTilde }
+
+// splitMerge runs the split-merge procedure on the provided protojets,
+// with the overlap threshold f, and returns the final jets.
+//
+// The hardest protojet is compared with the next hardest one it overlaps
+// with. If the overlap carries more than a fraction f of the scale of the
+// softer protojet, both protojets are merged. Otherwise, the shared
+// particles are assigned to the protojet with the closest axis.
+// A protojet without any overlap becomes a final jet.
+// Protojets with a transverse momentum below ptmin are discarded.
+func (evt *event) splitMerge(cones []protojet, f, ptmin float64, scale scaleFunc) []protojet {
+	var (
+		cands = make([]protojet, 0, len(cones))
+		seen  = make(contentSet, len(cones))
+		jets  []protojet
+	)
+	add := func(pj protojet) {
+		if len(pj.content) == 0 || pj.pt < ptmin || !seen.add(pj.hash, pj.content) {
+			return
+		}
+		cands = append(cands, pj)
+	}
+	for _, pj := range cones {
+		add(pj)
+	}
+
+	for len(cands) > 0 {
+		sort.SliceStable(cands, func(i, j int) bool {
+			return scale(&cands[i]) > scale(&cands[j])
+		})
+
+		var (
+			j1     = cands[0]
+			k      = -1
+			shared []int
+		)
+		for i := 1; i < len(cands); i++ {
+			shared = overlap(j1.content, cands[i].content)
+			if len(shared) > 0 {
+				k = i
+				break
+			}
+		}
+		if k < 0 {
+			jets = append(jets, j1)
+			seen.remove(j1.hash, j1.content)
+			cands = cands[1:]
+			continue
+		}
+
+		j2 := cands[k]
+		seen.remove(j1.hash, j1.content)
+		seen.remove(j2.hash, j2.content)
+		cands = append(cands[1:k], cands[k+1:]...)
+
+		ov := evt.newProtojet(shared)
+		if scale(&ov) > f*scale(&j2) {
+			add(evt.newProtojet(union(j1.content, j2.content)))
+			continue
+		}
+
+		// toJ1 holds whether a shared particle is closer to the first protojet.
+		toJ1 := make(map[int]bool, len(shared))
+		for _, i := range shared {
+			toJ1[i] = evt.dist2(i, j1.y, j1.phi) <= evt.dist2(i, j2.y, j2.phi)
+		}
+		var c1, c2 []int
+		for _, i := range j1.content {
+			if v, ok := toJ1[i]; !ok || v {
+				c1 = append(c1, i)
+			}
+		}
+		for _, i := range j2.content {
+			if v, ok := toJ1[i]; !ok || !v {
+				c2 = append(c2, i)
+			}
+		}
+		add(evt.newProtojet(c1))
+		add(evt.newProtojet(c2))
+	}
+
+	return jets
+}
+
+// pluginBuilder returns the builder as a fastjet.PluginBuilder.
+func pluginBuilder(builder fastjet.Builder) (fastjet.PluginBuilder, error) {
+	pb, ok := builder.(fastjet.PluginBuilder)
+	if !ok {
+		return nil, fmt.Errorf("cone: builder %T does not implement fastjet.PluginBuilder", builder)
+	}
+	return pb, nil
+}
+
+// record records the recombination steps of the final jets with the
+// builder.
+func record(builder fastjet.PluginBuilder, jets []protojet) error {
+	for _, jet := range jets {
+		var (
+			k   = jet.content[0]
+			err error
+		)
+		for _, i := range jet.content[1:] {
+			k, err = builder.PluginRecordIJ(k, i, 0)
+			if err != nil {
+				return fmt.Errorf("cone: could not record recombination: %w", err)
+			}
+		}
+		err = builder.PluginRecordIB(k, jet.pt*jet.pt)
+		if err != nil {
+			return fmt.Errorf("cone: could not record recombination: %w", err)
+		}
+	}
+	return nil
+}
+
+// overlap returns the particles shared by the two sorted sets.
+func overlap(a, b []int) []int {
+	var o []int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			o = append(o, a[i])
+			i++
+			j++
+		}
+	}
+	return o
+}
+
+// union returns the particles of the two sorted sets.
+func union(a, b []int) []int {
+	o := make([]int, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			o = append(o, a[i])
+			i++
+		case a[i] > b[j]:
+			o = append(o, b[j])
+			j++
+		default:
+			o = append(o, a[i])
+			i++
+			j++
+		}
+	}
+	o = append(o, a[i:]...)
+	o = append(o, b[j:]...)
+	return o
+}
+
+// seeds returns the particles with a transverse momentum above the
+// threshold, by decreasing transverse momentum.
+func (evt *event) seeds(threshold float64) []int {
+	var o []int
+	for i, pt := range evt.pt {
+		if pt >= threshold {
+			o = append(o, i)
+		}
+	}
+	sort.SliceStable(o, func(i, j int) bool {
+		return evt.pt[o[i]] > evt.pt[o[j]]
+	})
+	return o
+}
+
+// contentSet is a set of protojet contents.
+// Contents are bucketed by their hash and compared element by element.
+type contentSet map[uint64][][]int
+
+// add adds the sorted content with the provided hash to the set.
+// add returns false if the content was already in the set.
+func (set contentSet) add(hash uint64, content []int) bool {
+	for _, c := range set[hash] {
+		if equalContent(c, content) {
+			return false
+		}
+	}
+	set[hash] = append(set[hash], content)
+	return true
+}
+
+// remove removes the sorted content with the provided hash from the set.
+func (set contentSet) remove(hash uint64, content []int) {
+	bucket := set[hash]
+	for i, c := range bucket {
+		if !equalContent(c, content) {
+			continue
+		}
+		bucket = append(bucket[:i], bucket[i+1:]...)
+		break
+	}
+	if len(bucket) == 0 {
+		delete(set, hash)
+		return
+	}
+	set[hash] = bucket
+}
+
+// equalContent returns whether the 2 sorted contents are the same.
+func equalContent(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// indices returns the indices of the n particles.
+func indices(n int) []int {
+	o := make([]int, n)
+	for i := range o {
+		o[i] = i
+	}
+	return o
+}
+
+// deltaPhi returns phi1-phi2, in the [-pi, pi] range.
+func deltaPhi(phi1, phi2 float64) float64 {
+	dphi := math.Mod(phi1-phi2, 2*math.Pi)
+	switch {
+	case dphi > math.Pi:
+		dphi -= 2 * math.Pi
+	case dphi < -math.Pi:
+		dphi += 2 * math.Pi
+	}
+	return dphi
+}
+
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
@@ -0,0 +1,299 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cone_test
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"go-hep.org/x/hep/fastjet"
+	"go-hep.org/x/hep/fastjet/cone"
+	"go-hep.org/x/hep/fastjet/internal/fjtest"
+	"golang.org/x/exp/rand"
+)
+
+func TestPlugins(t *testing.T) {
+	particles, err := fjtest.LoadParticles("../testdata/single-pp-event.dat")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"SISCone", "CDFMidPoint", "ATLASCone"} {
+		t.Run(name, func(t *testing.T) {
+			plugin, err := fastjet.GetPlugin(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			def := fastjet.NewJetDefinitionPlugin(plugin)
+			if got, want := def.Algorithm(), fastjet.PluginAlgorithm; got != want {
+				t.Fatalf("invalid algorithm: got=%v, want=%v", got, want)
+			}
+			if got, want := def.Description(), plugin.Description(); got != want {
+				t.Fatalf("invalid description: got=%q, want=%q", got, want)
+			}
+
+			cs, err := fastjet.NewClusterSequence(particles, def)
+			if err != nil {
+				t.Fatalf("could not cluster event: %+v", err)
+			}
+			jets, err := cs.InclusiveJets(5)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(jets) == 0 {
+				t.Fatalf("no jet found")
+			}
+
+			used := make(map[[4]float64]bool)
+			for i := range jets {
+				jet := &jets[i]
+				var px, py, pz, e float64
+				for _, c := range jet.Constituents() {
+					key := [4]float64{c.Px(), c.Py(), c.Pz(), c.E()}
+					if used[key] {
+						t.Fatalf("particle %v used in more than one jet", key)
+					}
+					used[key] = true
+					px += c.Px()
+					py += c.Py()
+					pz += c.Pz()
+					e += c.E()
+				}
+				for _, v := range []struct {
+					name      string
+					got, want float64
+				}{
+					{"px", jet.Px(), px},
+					{"py", jet.Py(), py},
+					{"pz", jet.Pz(), pz},
+					{"e", jet.E(), e},
+				} {
+					if math.Abs(v.got-v.want) > 1e-6*math.Max(1, math.Abs(v.want)) {
+						t.Fatalf("jet #%d: invalid %s: got=%v, want=%v", i, v.name, v.got, v.want)
+					}
+				}
+				dr := math.Sqrt(fastjet.Distance(jet, &jet.Constituents()[0]))
+				if dr > 2*plugin.R() {
+					t.Fatalf("jet #%d: constituent too far from jet axis (dR=%v)", i, dr)
+				}
+			}
+		})
+	}
+}
+
+func TestPluginsReference(t *testing.T) {
+	particles, err := fjtest.LoadParticles("../testdata/single-pp-event.dat")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the two hard jets of the event are well isolated: cone algorithms
+	// and anti-kt, with the same radius, find them with the same particles
+	// up to the edges of the jets.
+	want, err := fjtest.LoadRef("../testdata/antikt_r0.7_escheme_best.ref")
+	if err != nil {
+		t.Fatalf("could not load reference jets: %+v", err)
+	}
+	want = want[:2]
+
+	for _, tc := range []struct {
+		name   string
+		plugin fastjet.Plugin
+	}{
+		{"SISCone", cone.NewSISCone(0.7, 0.5, 0, 0)},
+		{"CDFMidPoint", cone.NewCDFMidPoint(0.7, 0.5, 1, 1, 2, 100)},
+		{"ATLASCone", cone.NewATLASCone(0.7, 0.5, 1)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cs, err := fastjet.NewClusterSequence(particles, fastjet.NewJetDefinitionPlugin(tc.plugin))
+			if err != nil {
+				t.Fatalf("could not cluster event: %+v", err)
+			}
+			jets, err := cs.InclusiveJets(100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sort.Sort(fastjet.ByPt(jets))
+
+			if got, want := len(jets), len(want); got != want {
+				t.Fatalf("invalid number of jets: got=%d, want=%d", got, want)
+			}
+			for i, ref := range want {
+				var (
+					jet  = &jets[i]
+					y    = jet.Rapidity()
+					dphi = math.Remainder(jet.Phi()-ref[1], 2*math.Pi)
+					pt   = jet.Pt()
+				)
+				if math.Abs(y-ref[0]) > 0.01 || math.Abs(dphi) > 0.01 || math.Abs(pt-ref[2]) > 0.01*ref[2] {
+					t.Fatalf("jet #%d: got=(%v, %v, %v), want=%v", i, y, jet.Phi(), pt, ref)
+				}
+			}
+		})
+	}
+}
+
+// builder is a fastjet.Builder that does not implement fastjet.PluginBuilder.
+type builder struct{}
+
+func (builder) InclusiveJets(ptmin float64) ([]fastjet.Jet, error)   { return nil, nil }
+func (builder) Constituents(jet *fastjet.Jet) ([]fastjet.Jet, error) { return nil, nil }
+
+func TestPluginsBuilder(t *testing.T) {
+	for _, name := range []string{"SISCone", "CDFMidPoint", "ATLASCone"} {
+		plugin, err := fastjet.GetPlugin(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = plugin.RunClustering(builder{})
+		if err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestSplitMerge(t *testing.T) {
+	particles := []fastjet.Jet{
+		fjtest.NewJetPtYPhi(100, 0, 0),
+		fjtest.NewJetPtYPhi(100, 0.9, 0),
+		fjtest.NewJetPtYPhi(1, 0.45, 0),
+		fjtest.NewJetPtYPhi(50, -2, 3),
+	}
+
+	for _, tc := range []struct {
+		name   string
+		plugin fastjet.Plugin
+		pts    []float64
+	}{
+		{"siscone", cone.NewSISCone(0.7, 0.75, 0, 0), []float64{201, 50}},
+		{"midpoint", cone.NewCDFMidPoint(0.7, 0.5, 2, 1, 2, 100), []float64{201, 50}},
+		{"atlas", cone.NewATLASCone(0.7, 0.5, 2), []float64{101, 100, 50}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			jets := clusterPlugin(t, particles, tc.plugin)
+			if got, want := len(jets), len(tc.pts); got != want {
+				t.Fatalf("invalid number of jets: got=%d, want=%d", got, want)
+			}
+			for i := range jets {
+				if got, want := jets[i].Pt(), tc.pts[i]; math.Abs(got-want) > 1e-6 {
+					t.Fatalf("jet #%d: invalid pt: got=%v, want=%v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSISConeSimple(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		particles []fastjet.Jet
+		pts       []float64
+	}{
+		{
+			name:      "empty",
+			particles: nil,
+			pts:       nil,
+		},
+		{
+			name:      "single",
+			particles: []fastjet.Jet{fjtest.NewJetPtYPhi(10, 0, 0)},
+			pts:       []float64{10},
+		},
+		{
+			name: "two-close",
+			particles: []fastjet.Jet{
+				fjtest.NewJetPtYPhi(10, 0, 0),
+				fjtest.NewJetPtYPhi(5, 0.3, 0),
+			},
+			pts: []float64{15},
+		},
+		{
+			name: "two-far",
+			particles: []fastjet.Jet{
+				fjtest.NewJetPtYPhi(10, 0, 0),
+				fjtest.NewJetPtYPhi(5, 1.5, 0),
+			},
+			pts: []float64{10, 5},
+		},
+		{
+			name: "phi-periodicity",
+			particles: []fastjet.Jet{
+				fjtest.NewJetPtYPhi(10, 0, math.Pi-0.1),
+				fjtest.NewJetPtYPhi(5, 0, -math.Pi+0.1),
+			},
+			pts: []float64{15},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			jets := clusterPlugin(t, tc.particles, cone.NewSISCone(0.7, 0.75, 0, 0))
+			if got, want := len(jets), len(tc.pts); got != want {
+				t.Fatalf("invalid number of jets: got=%d, want=%d", got, want)
+			}
+			for i := range jets {
+				if got, want := jets[i].Pt(), tc.pts[i]; math.Abs(got-want) > 0.1 {
+					t.Fatalf("jet #%d: invalid pt: got=%v, want=%v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSISConeInfraredSafety(t *testing.T) {
+	particles, err := fjtest.LoadParticles("../testdata/single-pp-event.dat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := cone.NewSISCone(0.7, 0.75, 0, 0)
+	ref := clusterPlugin(t, particles, sc)
+
+	rnd := rand.New(rand.NewSource(1234))
+	for i := 0; i < 3; i++ {
+		soft := append([]fastjet.Jet(nil), particles...)
+		for j := 0; j < 20; j++ {
+			soft = append(soft, fjtest.NewJetPtYPhi(
+				1e-8*rnd.Float64(),
+				6*rnd.Float64()-3,
+				2*math.Pi*rnd.Float64(),
+			))
+		}
+		jets := clusterPlugin(t, soft, sc)
+
+		var got, want []float64
+		for _, jet := range jets {
+			if jet.Pt() > 5 {
+				got = append(got, jet.Pt())
+			}
+		}
+		for _, jet := range ref {
+			if jet.Pt() > 5 {
+				want = append(want, jet.Pt())
+			}
+		}
+		if len(got) != len(want) {
+			t.Fatalf("trial #%d: invalid number of hard jets: got=%d, want=%d", i, len(got), len(want))
+		}
+		for j := range got {
+			if math.Abs(got[j]-want[j]) > 1e-6 {
+				t.Fatalf("trial #%d: jet #%d: invalid pt: got=%v, want=%v", i, j, got[j], want[j])
+			}
+		}
+	}
+}
+
+func clusterPlugin(t *testing.T, particles []fastjet.Jet, plugin fastjet.Plugin) []fastjet.Jet {
+	t.Helper()
+
+	cs, err := fastjet.NewClusterSequence(particles, fastjet.NewJetDefinitionPlugin(plugin))
+	if err != nil {
+		t.Fatalf("could not cluster event: %+v", err)
+	}
+	jets, err := cs.InclusiveJets(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Sort(fastjet.ByPt(jets))
+	return jets
+}
@@ -0,0 +1,149 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cone
+
+import (
+	"fmt"
+	"math"
+
+	"go-hep.org/x/hep/fastjet"
+)
+
+// CDFMidPoint is the CDF Run II MidPoint cone jet algorithm.
+//
+// Stable cones are found by iterating cones from seed particles, and then
+// from the midpoints of pairs (or larger sets) of nearby stable cones.
+// The stable cones then go through a split-merge procedure, ordered by
+// transverse momentum.
+//
+// The algorithm is not infrared safe.
+//
+// See:
+//   - G. C. Blazey et al., hep-ex/0005012
+type CDFMidPoint struct {
+	r            float64
+	f            float64
+	seed         float64
+	areaFraction float64
+	maxPairSize  int
+	maxIters     int
+}
+
+// NewCDFMidPoint returns a new CDF MidPoint plugin, with a cone radius r,
+// an overlap threshold f for the split-merge step and a seed threshold
+// on the transverse momentum of the seed particles.
+//
+// Stable cones are searched with a radius r*sqrt(areaFraction), and then
+// expanded to the radius r (areaFraction = 1 for the standard algorithm).
+// Midpoints are computed for sets of up to maxPairSize stable cones and
+// cones are iterated up to maxIters times.
+func NewCDFMidPoint(r, f, seed, areaFraction float64, maxPairSize, maxIters int) *CDFMidPoint {
+	return &CDFMidPoint{
+		r:            r,
+		f:            f,
+		seed:         seed,
+		areaFraction: areaFraction,
+		maxPairSize:  maxPairSize,
+		maxIters:     maxIters,
+	}
+}
+
+// Description returns a string description of the plugin.
+func (mp *CDFMidPoint) Description() string {
+	return fmt.Sprintf(
+		"CDF MidPoint jet algorithm, with seed_threshold = %v, cone_radius = %v, cone_area_fraction = %v, max_pair_size = %d, max_iterations = %d, overlap_threshold = %v",
+		mp.seed, mp.r, mp.areaFraction, mp.maxPairSize, mp.maxIters, mp.f,
+	)
+}
+
+// R returns the radius of the cones.
+func (mp *CDFMidPoint) R() float64 {
+	return mp.r
+}
+
+// RunClustering clusters the particles of the builder.
+func (mp *CDFMidPoint) RunClustering(builder fastjet.Builder) error {
+	pb, err := pluginBuilder(builder)
+	if err != nil {
+		return err
+	}
+
+	var (
+		evt     = newEvent(pb.Jets())
+		parts   = indices(len(evt.jets))
+		search  = mp.r * math.Sqrt(mp.areaFraction)
+		cones   []protojet
+		seen    = make(contentSet)
+		iterate = func(y, phi float64) {
+			pj, ok := evt.iterate(parts, y, phi, search, mp.maxIters)
+			if !ok || !seen.add(pj.hash, pj.content) {
+				return
+			}
+			cones = append(cones, pj)
+		}
+	)
+
+	for _, i := range evt.seeds(mp.seed) {
+		iterate(evt.y[i], evt.phi[i])
+	}
+
+	for _, set := range midpoints(cones, 2*mp.r, mp.maxPairSize) {
+		var px, py, pz, e float64
+		for _, i := range set {
+			px += cones[i].px
+			py += cones[i].py
+			pz += cones[i].pz
+			e += cones[i].e
+		}
+		mid := fastjet.NewJet(px, py, pz, e)
+		iterate(mid.Rapidity(), mid.Phi())
+	}
+
+	if mp.areaFraction != 1 {
+		for i, pj := range cones {
+			cones[i] = evt.newProtojet(evt.inCone(parts, pj.y, pj.phi, mp.r))
+		}
+	}
+
+	jets := evt.splitMerge(cones, mp.f, 0, scalePt)
+	return record(pb, jets)
+}
+
+// midpoints returns the sets of 2 up to n cones whose axes are all within
+// a distance dmax from each other.
+func midpoints(cones []protojet, dmax float64, n int) [][]int {
+	var (
+		dmax2 = dmax * dmax
+		sets  [][]int
+		cur   []int
+		near  = func(i, j int) bool {
+			dy := cones[i].y - cones[j].y
+			dphi := deltaPhi(cones[i].phi, cones[j].phi)
+			return dy*dy+dphi*dphi < dmax2
+		}
+		loop func(beg int)
+	)
+	loop = func(beg int) {
+		if len(cur) >= 2 {
+			sets = append(sets, append([]int(nil), cur...))
+		}
+		if len(cur) == n {
+			return
+		}
+	next:
+		for i := beg; i < len(cones); i++ {
+			for _, j := range cur {
+				if !near(i, j) {
+					continue next
+				}
+			}
+			cur = append(cur, i)
+			loop(i + 1)
+			cur = cur[:len(cur)-1]
+		}
+	}
+	loop(0)
+	return sets
+}
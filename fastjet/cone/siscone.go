@@ -0,0 +1,297 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cone
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"go-hep.org/x/hep/fastjet"
+)
+
+// SISCone is the Seedless Infrared-Safe cone jet algorithm.
+//
+// All the stable cones of radius R are found, without seeds: every cone
+// can be moved in the rapidity-azimuth plane until two particles lie on
+// its circumference, so the candidate cones are enumerated from the pairs
+// of particles (and from each particle alone).
+// A cone is stable if its axis matches the 4-momentum of its content.
+//
+// Particles that are not part of any stable cone are used to search for
+// new stable cones in additional passes.
+// The stable cones then go through a split-merge procedure, ordered by
+// the scalar sum of the transverse momenta of their particles.
+//
+// See:
+//   - G. P. Salam, G. Soyez, arXiv:0704.0292
+type SISCone struct {
+	r     float64
+	f     float64
+	npass int
+	ptmin float64
+}
+
+// NewSISCone returns a new SISCone plugin, with a cone radius r and an
+// overlap threshold f for the split-merge step.
+// npass is the maximal number of passes to find stable cones (0 to run
+// passes until no new stable cone is found) and ptmin is the minimal
+// transverse momentum of the protojets entering the split-merge step.
+func NewSISCone(r, f float64, npass int, ptmin float64) *SISCone {
+	return &SISCone{
+		r:     r,
+		f:     f,
+		npass: npass,
+		ptmin: ptmin,
+	}
+}
+
+// Description returns a string description of the plugin.
+func (sc *SISCone) Description() string {
+	return fmt.Sprintf(
+		"SISCone jet algorithm with cone_radius = %v, overlap_threshold = %v, n_pass_max = %d, protojet_ptmin = %v, split_merge_scale = pttilde",
+		sc.r, sc.f, sc.npass, sc.ptmin,
+	)
+}
+
+// R returns the radius of the cones.
+func (sc *SISCone) R() float64 {
+	return sc.r
+}
+
+// RunClustering clusters the particles of the builder.
+func (sc *SISCone) RunClustering(builder fastjet.Builder) error {
+	pb, err := pluginBuilder(builder)
+	if err != nil {
+		return err
+	}
+
+	var (
+		evt   = newEvent(pb.Jets())
+		parts = indices(len(evt.jets))
+		cones []protojet
+		seen  = make(contentSet)
+	)
+
+	for pass := 0; len(parts) > 0 && (sc.npass <= 0 || pass < sc.npass); pass++ {
+		found := 0
+		used := make(map[int]bool)
+		for _, pj := range evt.stableCones(parts, sc.r) {
+			for _, i := range pj.content {
+				used[i] = true
+			}
+			if !seen.add(pj.hash, pj.content) {
+				continue
+			}
+			cones = append(cones, pj)
+			found++
+		}
+		if found == 0 {
+			break
+		}
+
+		left := parts[:0]
+		for _, i := range parts {
+			if !used[i] {
+				left = append(left, i)
+			}
+		}
+		parts = left
+	}
+
+	jets := evt.splitMerge(cones, sc.f, sc.ptmin, scalePtTilde)
+	return record(pb, jets)
+}
+
+// stableCones returns the stable cones of radius r made of the provided
+// particles.
+//
+// As in SISCone, the candidate cones are enumerated by rotating a circle of
+// radius r around each particle (the parent) it goes through.
+// The content of the circle only changes when the circle goes through one
+// of the particles within 2r of the parent (the children).
+// Sorting these crossings by angle, the contents of all the candidate cones
+// of a parent are updated incrementally, for a total cost of O(N² log N).
+// Only the candidates whose axis is consistent with their edge particles
+// go through the full O(N) stability check.
+func (evt *event) stableCones(parts []int, r float64) []protojet {
+	var (
+		r2     = r * r
+		cones  []protojet
+		tested = make(contentSet)
+	)
+
+	check := func(hash uint64, content []int) {
+		sort.Ints(content)
+		if !tested.add(hash, content) {
+			return
+		}
+		pj := evt.newProtojet(content)
+		axis := evt.inCone(parts, pj.y, pj.phi, r)
+		sort.Ints(axis)
+		if !equalContent(axis, pj.content) {
+			return
+		}
+		cones = append(cones, pj)
+	}
+
+	for _, i := range parts {
+		check(evt.hash[i], []int{i})
+	}
+
+	var (
+		in   = make([]bool, len(evt.jets)) // whether a child is in the current cone
+		kids []int
+		xs   []crossing
+	)
+	for _, p := range parts {
+		kids = kids[:0]
+		xs = xs[:0]
+		for _, c := range parts {
+			var (
+				dy   = evt.y[c] - evt.y[p]
+				dphi = deltaPhi(evt.phi[c], evt.phi[p])
+				d2   = dy*dy + dphi*dphi
+			)
+			if c == p || d2 == 0 || d2 >= 4*r2 {
+				continue
+			}
+			// the child is within the cone when the axis of the cone is
+			// at an angle within beta of the direction of the child.
+			var (
+				alpha = math.Atan2(dphi, dy)
+				beta  = math.Acos(math.Sqrt(d2) / (2 * r))
+				enter = normAngle(alpha - beta)
+				leave = normAngle(alpha + beta)
+			)
+			kids = append(kids, c)
+			xs = append(xs,
+				crossing{theta: enter, child: c, enter: true},
+				crossing{theta: leave, child: c},
+			)
+			// the cone starts with its axis at an angle of 0.
+			in[c] = enter > leave
+		}
+		if len(kids) == 0 {
+			continue
+		}
+		sort.Slice(xs, func(i, j int) bool { return xs[i].theta < xs[j].theta })
+
+		var cur cone
+		for _, c := range kids {
+			if in[c] {
+				cur.add(evt, c)
+			}
+		}
+
+		for _, x := range xs {
+			c := x.child
+			if in[c] {
+				in[c] = false
+				cur.sub(evt, c)
+			}
+
+			// the circle goes through the parent and the child: each of
+			// them may or may not belong to the cone.
+			for _, edge := range [4][2]bool{{true, true}, {true, false}, {false, true}, {false, false}} {
+				cand := cur
+				if edge[0] {
+					cand.add(evt, p)
+				}
+				if edge[1] {
+					cand.add(evt, c)
+				}
+				if cand.n == 0 || !evt.edgeStable(&cand, p, edge[0], r2) || !evt.edgeStable(&cand, c, edge[1], r2) {
+					continue
+				}
+				content := make([]int, 0, cand.n)
+				for _, k := range kids {
+					if in[k] {
+						content = append(content, k)
+					}
+				}
+				if edge[0] {
+					content = append(content, p)
+				}
+				if edge[1] {
+					content = append(content, c)
+				}
+				check(cand.hash, content)
+			}
+
+			if x.enter {
+				in[c] = true
+				cur.add(evt, c)
+			}
+		}
+
+		for _, c := range kids {
+			in[c] = false
+		}
+	}
+
+	return cones
+}
+
+// crossing is the angle, around a parent particle, at which the axis of
+// a cone going through the parent lies when a child enters or leaves the cone.
+type crossing struct {
+	theta float64
+	child int
+	enter bool
+}
+
+// cone holds the content of a candidate cone, updated incrementally.
+type cone struct {
+	n             int    // number of particles
+	hash          uint64 // hash of the content
+	px, py, pz, e float64
+}
+
+func (c *cone) add(evt *event, i int) {
+	p := &evt.jets[i]
+	c.n++
+	c.hash ^= evt.hash[i]
+	c.px += p.Px()
+	c.py += p.Py()
+	c.pz += p.Pz()
+	c.e += p.E()
+}
+
+func (c *cone) sub(evt *event, i int) {
+	p := &evt.jets[i]
+	c.n--
+	c.hash ^= evt.hash[i]
+	c.px -= p.Px()
+	c.py -= p.Py()
+	c.pz -= p.Pz()
+	c.e -= p.E()
+}
+
+// edgeStable returns whether the i-th particle, on the edge of the cone,
+// is (or is not, depending on within) within a distance r of the axis of
+// the cone content.
+// Rounding errors of the incremental updates are accounted for: the full
+// stability check is performed afterwards.
+func (evt *event) edgeStable(c *cone, i int, within bool, r2 float64) bool {
+	const eps = 1e-9
+	var (
+		jet = fastjet.NewJet(c.px, c.py, c.pz, c.e)
+		d2  = evt.dist2(i, jet.Rapidity(), jet.Phi())
+	)
+	if within {
+		return d2 < r2*(1+eps)
+	}
+	return d2 > r2*(1-eps)
+}
+
+// normAngle returns the angle in the [0, 2pi) range.
+func normAngle(v float64) float64 {
+	v = math.Mod(v, 2*math.Pi)
+	if v < 0 {
+		v += 2 * math.Pi
+	}
+	return v
+}
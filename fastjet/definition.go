@@ -39,6 +39,18 @@ func NewJetDefinitionExtra(alg JetAlgorithm, r float64, scheme RecombinationSche
 	}
 }
 
+// NewJetDefinitionPlugin returns a new JetDefinition using the provided plugin
+// to cluster jets.
+func NewJetDefinitionPlugin(plugin Plugin) JetDefinition {
+	return JetDefinition{
+		alg:        PluginAlgorithm,
+		r:          plugin.R(),
+		recombiner: NewRecombiner(EScheme),
+		strategy:   PluginStrategy,
+		plugin:     plugin,
+	}
+}
+
 // Description returns a string description of the current JetDefinition
 // matching the one from C++ FastJet.
 func (def JetDefinition) Description() string {
@@ -103,6 +115,11 @@ func (def JetDefinition) Recombiner() Recombiner {
 	return def.recombiner
 }
 
+// SetRecombiner sets the recombiner used to merge jets during the clustering.
+func (def *JetDefinition) SetRecombiner(rec Recombiner) {
+	def.recombiner = rec
+}
+
 func (def JetDefinition) RecombinationScheme() RecombinationScheme {
 	return def.recombiner.Scheme()
 }
@@ -0,0 +1,80 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package fjtest provides helpers for the tests of the fastjet packages.
+package fjtest // import "go-hep.org/x/hep/fastjet/internal/fjtest"
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+
+	"go-hep.org/x/hep/fastjet"
+)
+
+// LoadParticles loads the particles stored in the named file, one
+// particle per line as a "px py pz e" 4-vector.
+func LoadParticles(name string) ([]fastjet.Jet, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var particles []fastjet.Jet
+	scan := bufio.NewScanner(f)
+	for scan.Scan() {
+		var px, py, pz, e float64
+		_, err = fmt.Sscanf(scan.Text(), "%f %f %f %f", &px, &py, &pz, &e)
+		if err != nil {
+			return nil, err
+		}
+		particles = append(particles, fastjet.NewJet(px, py, pz, e))
+	}
+	err = scan.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return particles, nil
+}
+
+// LoadRef loads the reference jets stored in the named file, one jet per
+// line as an "index rapidity phi pt" tuple.
+func LoadRef(name string) ([][3]float64, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var refs [][3]float64
+	scan := bufio.NewScanner(f)
+	for scan.Scan() {
+		var i int
+		var ref [3]float64
+		_, err = fmt.Sscanf(scan.Text(), "%5d %f %f %f", &i, &ref[0], &ref[1], &ref[2])
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+	err = scan.Err()
+	if err != nil {
+		return nil, err
+	}
+	return refs, nil
+}
+
+// NewJetPtYPhi creates a massless jet from its transverse momentum,
+// rapidity and azimuth.
+func NewJetPtYPhi(pt, y, phi float64) fastjet.Jet {
+	return fastjet.NewJet(
+		pt*math.Cos(phi),
+		pt*math.Sin(phi),
+		pt*math.Sinh(y),
+		pt*math.Cosh(y),
+	)
+}
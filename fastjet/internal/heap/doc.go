@@ -2,5 +2,6 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// package heap implements a min-heap for pairs of jets.
+// package heap implements a min-heap for pairs of jets and a min-heap of
+// indexed values.
 package heap
@@ -6,6 +6,7 @@ package heap
 
 import (
 	"container/heap"
+	"math"
 	"math/rand"
 	"testing"
 )
@@ -160,3 +161,46 @@ func (pq *PQ) Pop() interface{} {
 	*pq = old[0 : n-1]
 	return item
 }
+
+func TestMinHeap(t *testing.T) {
+	const n = 100
+	rnd := rand.New(rand.NewSource(1234))
+	vs := make([]float64, n)
+	for i := range vs {
+		vs[i] = rnd.Float64()
+	}
+
+	h := NewMinHeap(vs)
+	check := func(step int) {
+		t.Helper()
+		loc := 0
+		for i, v := range vs {
+			if v < vs[loc] {
+				loc = i
+			}
+		}
+		if got, want := h.MinLoc(), loc; got != want {
+			t.Fatalf("step %d: invalid min location: got=%d, want=%d", step, got, want)
+		}
+		if got, want := h.MinValue(), vs[loc]; got != want {
+			t.Fatalf("step %d: invalid min value: got=%v, want=%v", step, got, want)
+		}
+	}
+
+	check(-1)
+	for step := 0; step < 1000; step++ {
+		loc := rnd.Intn(n)
+		switch rnd.Intn(3) {
+		case 0:
+			vs[loc] = math.MaxFloat64
+			h.Remove(loc)
+		default:
+			vs[loc] = rnd.Float64()
+			h.Update(loc, vs[loc])
+		}
+		if got, want := h.Value(loc), vs[loc]; got != want {
+			t.Fatalf("step %d: invalid value: got=%v, want=%v", step, got, want)
+		}
+		check(step)
+	}
+}
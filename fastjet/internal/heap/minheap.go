@@ -0,0 +1,94 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"math"
+)
+
+// MinHeap holds a fixed number of values, indexed by their location, and
+// gives access to the minimum value.
+// Values can be updated and removed in O(ln N).
+//
+// Each node of the binary tree records the location of the minimum value
+// of its sub-tree, as in FastJet's MinHeap.
+type MinHeap struct {
+	vals []float64
+	mins []int // location of the minimum value of each sub-tree
+}
+
+// NewMinHeap returns a new heap, initialized with the provided values.
+func NewMinHeap(vs []float64) *MinHeap {
+	h := &MinHeap{
+		vals: make([]float64, len(vs)),
+		mins: make([]int, len(vs)),
+	}
+	copy(h.vals, vs)
+	for i := range h.mins {
+		h.mins[i] = i
+	}
+	for i := len(h.vals) - 1; i > 0; i-- {
+		parent := (i - 1) / 2
+		if h.vals[h.mins[i]] < h.vals[h.mins[parent]] {
+			h.mins[parent] = h.mins[i]
+		}
+	}
+	return h
+}
+
+// MinLoc returns the location of the minimum value.
+func (h *MinHeap) MinLoc() int {
+	return h.mins[0]
+}
+
+// MinValue returns the minimum value.
+func (h *MinHeap) MinValue() float64 {
+	return h.vals[h.mins[0]]
+}
+
+// Value returns the value at the provided location.
+func (h *MinHeap) Value(loc int) float64 {
+	return h.vals[loc]
+}
+
+// Remove removes the value at the provided location.
+func (h *MinHeap) Remove(loc int) {
+	h.Update(loc, math.MaxFloat64)
+}
+
+// Update sets the value at the provided location.
+func (h *MinHeap) Update(loc int, v float64) {
+	start := loc
+
+	// if the node was not the minimum of its sub-tree and it is still
+	// not smaller than that minimum, nothing else changes.
+	if h.mins[loc] != loc && !(v < h.vals[h.mins[loc]]) {
+		h.vals[loc] = v
+		return
+	}
+
+	h.vals[loc] = v
+	h.mins[loc] = loc
+
+	n := len(h.vals)
+	for changed := true; changed; {
+		changed = false
+		// nodes pointing to the updated location must be re-evaluated.
+		if h.mins[loc] == start {
+			h.mins[loc] = loc
+			changed = true
+		}
+		for c := 2*loc + 1; c <= 2*loc+2 && c < n; c++ {
+			if h.vals[h.mins[c]] < h.vals[h.mins[loc]] {
+				h.mins[loc] = h.mins[c]
+				changed = true
+			}
+		}
+		if loc == 0 {
+			break
+		}
+		loc = (loc - 1) / 2
+	}
+}
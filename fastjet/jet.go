@@ -105,3 +105,41 @@ func deltaPhi(j1, j2 *Jet) float64 {
 func deltaRap(j1, j2 *Jet) float64 {
 	return j1.Rapidity() - j2.Rapidity()
 }
+
+// HasArea returns whether this jet has area information attached.
+func (jet *Jet) HasArea() bool {
+	_, ok := jet.structure.(JetAreaStructure)
+	return ok
+}
+
+// Area returns the scalar area of this jet.
+// Area panics if the jet has no area information.
+func (jet *Jet) Area() float64 {
+	return jet.areaStructure().Area(jet)
+}
+
+// AreaErr returns the uncertainty on the area of this jet.
+// AreaErr panics if the jet has no area information.
+func (jet *Jet) AreaErr() float64 {
+	return jet.areaStructure().AreaErr(jet)
+}
+
+// Area4 returns the 4-vector area of this jet.
+// Area4 panics if the jet has no area information.
+func (jet *Jet) Area4() fmom.PxPyPzE {
+	return jet.areaStructure().Area4(jet)
+}
+
+// IsPureGhost returns whether this jet is only made of ghosts.
+// IsPureGhost panics if the jet has no area information.
+func (jet *Jet) IsPureGhost() bool {
+	return jet.areaStructure().IsPureGhost(jet)
+}
+
+func (jet *Jet) areaStructure() JetAreaStructure {
+	area, ok := jet.structure.(JetAreaStructure)
+	if !ok {
+		panic("fastjet: jet has no area information")
+	}
+	return area
+}
@@ -8,12 +8,42 @@ import (
 	"fmt"
 )
 
+// Plugin is a jet clustering algorithm external to the ones implemented
+// by ClusterSequence.
 type Plugin interface {
 	Description() string
+
+	// RunClustering clusters the jets of the builder and records
+	// the recombination steps with its PluginRecordIJ and PluginRecordIB
+	// methods.
+	// The builder must implement PluginBuilder.
 	RunClustering(builder Builder) error
+
+	// R returns the radius of the jets.
 	R() float64
 }
 
+// PluginBuilder is a Builder whose particles can be clustered by a Plugin.
+type PluginBuilder interface {
+	Builder
+
+	// Jets returns the initial particles and the jets created during
+	// the clustering, indexed by the order in which they were created.
+	Jets() []Jet
+
+	// PluginRecordIJ records the recombination of the i-th and j-th jets
+	// with the distance dij, and returns the index of the new jet.
+	PluginRecordIJ(i, j int, dij float64) (int, error)
+
+	// PluginRecordIB records the recombination of the i-th jet with the
+	// beam with the distance dib.
+	PluginRecordIB(i int, dib float64) error
+}
+
+var (
+	_ PluginBuilder = (*ClusterSequence)(nil)
+)
+
 var (
 	g_plugins = make(map[string]Plugin)
 )
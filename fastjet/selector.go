@@ -0,0 +1,567 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fastjet
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Selector selects jets that pass a given criterion.
+//
+// Selectors can be combined with the And, Or, Not and Mult methods.
+// Most selectors apply jet by jet, but some (like SelectorNHardest) can
+// only be applied to a set of jets.
+// Some selectors (like SelectorCircle) are defined with respect to a
+// reference jet, set with WithReference.
+type Selector struct {
+	w selectorWorker
+}
+
+// selectorWorker is the interface implemented by all the kinds of selectors.
+type selectorWorker interface {
+	// pass returns whether the jet passes the selection criterion.
+	pass(jet *Jet) bool
+
+	// area returns the rapidity-azimuth area covered by the selector,
+	// and whether that area is finite and known.
+	area() (float64, bool)
+
+	description() string
+}
+
+// selectorSetWorker is implemented by selectors that may not apply jet
+// by jet.
+type selectorSetWorker interface {
+	// terminator sets to nil the jets that do not pass the selection.
+	terminator(jets []*Jet)
+
+	// jetByJet returns whether the selector applies jet by jet.
+	jetByJet() bool
+}
+
+// selectorRapWorker is implemented by selectors covering a limited
+// rapidity range.
+type selectorRapWorker interface {
+	// rapRange returns the rapidity range covered by the selector.
+	rapRange() (ymin, ymax float64)
+}
+
+// selectorRefWorker is implemented by selectors that may be defined with
+// respect to a reference jet.
+type selectorRefWorker interface {
+	// takesReference returns whether the selector needs a reference jet.
+	takesReference() bool
+
+	// withReference returns a copy of the selector using the reference jet.
+	withReference(ref *Jet) selectorWorker
+}
+
+// Pass returns whether the jet passes the selection criterion.
+// Pass panics if the selector does not apply jet by jet.
+func (sel Selector) Pass(jet *Jet) bool {
+	if !sel.AppliesJetByJet() {
+		panic(fmt.Errorf("fastjet: selector %q does not apply jet by jet", sel.Description()))
+	}
+	return sel.w.pass(jet)
+}
+
+// Select returns the jets that pass the selection criterion.
+func (sel Selector) Select(jets []Jet) []Jet {
+	ptrs := make([]*Jet, len(jets))
+	for i := range jets {
+		ptrs[i] = &jets[i]
+	}
+	terminator(sel.w, ptrs)
+
+	o := make([]Jet, 0, len(jets))
+	for _, jet := range ptrs {
+		if jet != nil {
+			o = append(o, *jet)
+		}
+	}
+	return o
+}
+
+// Count returns the number of jets that pass the selection criterion.
+func (sel Selector) Count(jets []Jet) int {
+	return len(sel.Select(jets))
+}
+
+// AppliesJetByJet returns whether the selector can be applied to jets
+// individually, with Pass.
+func (sel Selector) AppliesJetByJet() bool {
+	return jetByJet(sel.w)
+}
+
+// TakesReference returns whether the selector is defined with respect to
+// a reference jet.
+func (sel Selector) TakesReference() bool {
+	return takesReference(sel.w)
+}
+
+// WithReference returns a copy of the selector using the provided jet as
+// a reference.
+// Selectors that do not need a reference are returned unchanged.
+func (sel Selector) WithReference(ref *Jet) Selector {
+	return Selector{withReference(sel.w, ref)}
+}
+
+// Area returns the rapidity-azimuth area covered by the selector.
+// Area returns an error if the selector does not have a finite area.
+//
+// When it is not known analytically, the area is estimated by counting
+// the number of ghosts, of area 0.01, that pass the selector.
+func (sel Selector) Area() (float64, error) {
+	area, ok := sel.w.area()
+	if ok {
+		return area, nil
+	}
+
+	ymin, ymax := rapRange(sel.w)
+	if math.IsInf(ymin, 0) || math.IsInf(ymax, 0) {
+		return 0, fmt.Errorf("fastjet: selector %q does not have a finite area", sel.Description())
+	}
+	if ymax <= ymin {
+		return 0, nil
+	}
+
+	const ghostArea = 0.01
+	var (
+		size = math.Sqrt(ghostArea)
+		ny   = int(math.Ceil((ymax - ymin) / size))
+		nphi = int(math.Ceil(2 * math.Pi / size))
+		dy   = (ymax - ymin) / float64(ny)
+		dphi = 2 * math.Pi / float64(nphi)
+		gs   = make([]Jet, 0, ny*nphi)
+	)
+	for iy := 0; iy < ny; iy++ {
+		for iphi := 0; iphi < nphi; iphi++ {
+			y := ymin + (float64(iy)+0.5)*dy
+			phi := (float64(iphi) + 0.5) * dphi
+			gs = append(gs, newJetPtYPhiM(1e-100, y, phi, 0))
+		}
+	}
+	n := sel.Count(gs)
+	return float64(n) * dy * dphi, nil
+}
+
+// Description returns a string description of the selection criterion.
+func (sel Selector) Description() string {
+	return sel.w.description()
+}
+
+// And returns a selector passing the jets that pass both selectors.
+// For selectors that do not apply jet by jet, both selectors are applied
+// independently to the initial set of jets.
+func (sel Selector) And(o Selector) Selector {
+	return Selector{selAnd{sel.w, o.w}}
+}
+
+// Or returns a selector passing the jets that pass either selector.
+func (sel Selector) Or(o Selector) Selector {
+	return Selector{selOr{sel.w, o.w}}
+}
+
+// Not returns a selector passing the jets that do not pass the selector.
+func (sel Selector) Not() Selector {
+	return Selector{selNot{sel.w}}
+}
+
+// Mult returns a selector applying the o selector and then this selector
+// to the resulting jets.
+// Mult is equivalent to And for selectors that apply jet by jet.
+func (sel Selector) Mult(o Selector) Selector {
+	return Selector{selMult{sel.w, o.w}}
+}
+
+// SelectorRapRange selects jets with ymin <= rapidity <= ymax.
+func SelectorRapRange(ymin, ymax float64) Selector {
+	return Selector{selRapRange{ymin, ymax}}
+}
+
+// SelectorAbsRapMax selects jets with |rapidity| <= ymax.
+func SelectorAbsRapMax(ymax float64) Selector {
+	return Selector{selRapRange{-ymax, ymax}}
+}
+
+// SelectorPtMin selects jets with pt >= ptmin.
+func SelectorPtMin(ptmin float64) Selector {
+	return Selector{selPtMin{ptmin}}
+}
+
+// SelectorNHardest selects the n jets with the largest transverse momenta.
+// SelectorNHardest does not apply jet by jet.
+func SelectorNHardest(n int) Selector {
+	return Selector{selNHardest{n}}
+}
+
+// SelectorCircle selects jets within a rapidity-azimuth distance r of a
+// reference jet.
+// The reference jet is set with WithReference.
+func SelectorCircle(r float64) Selector {
+	return Selector{selCircle{r: r}}
+}
+
+// SelectorStrip selects jets within a rapidity distance halfWidth of a
+// reference jet.
+// The reference jet is set with WithReference.
+func SelectorStrip(halfWidth float64) Selector {
+	return Selector{selStrip{hw: halfWidth}}
+}
+
+// terminator sets to nil the jets that do not pass the selection.
+func terminator(w selectorWorker, jets []*Jet) {
+	if sw, ok := w.(selectorSetWorker); ok && !sw.jetByJet() {
+		sw.terminator(jets)
+		return
+	}
+	for i, jet := range jets {
+		if jet != nil && !w.pass(jet) {
+			jets[i] = nil
+		}
+	}
+}
+
+func jetByJet(w selectorWorker) bool {
+	if sw, ok := w.(selectorSetWorker); ok {
+		return sw.jetByJet()
+	}
+	return true
+}
+
+func rapRange(w selectorWorker) (ymin, ymax float64) {
+	if rw, ok := w.(selectorRapWorker); ok {
+		return rw.rapRange()
+	}
+	return math.Inf(-1), math.Inf(+1)
+}
+
+func takesReference(w selectorWorker) bool {
+	if rw, ok := w.(selectorRefWorker); ok {
+		return rw.takesReference()
+	}
+	return false
+}
+
+func withReference(w selectorWorker, ref *Jet) selectorWorker {
+	if rw, ok := w.(selectorRefWorker); ok && rw.takesReference() {
+		return rw.withReference(ref)
+	}
+	return w
+}
+
+type selRapRange struct {
+	min, max float64
+}
+
+func (sel selRapRange) pass(jet *Jet) bool {
+	rap := jet.Rapidity()
+	return sel.min <= rap && rap <= sel.max
+}
+
+func (sel selRapRange) area() (float64, bool) {
+	return 2 * math.Pi * math.Max(0, sel.max-sel.min), true
+}
+
+func (sel selRapRange) rapRange() (float64, float64) {
+	return sel.min, sel.max
+}
+
+func (sel selRapRange) description() string {
+	return fmt.Sprintf("%v <= rap <= %v", sel.min, sel.max)
+}
+
+type selPtMin struct {
+	min float64
+}
+
+func (sel selPtMin) pass(jet *Jet) bool {
+	return jet.Pt2() >= sel.min*sel.min
+}
+
+func (sel selPtMin) area() (float64, bool) {
+	return 0, false
+}
+
+func (sel selPtMin) description() string {
+	return fmt.Sprintf("pt >= %v", sel.min)
+}
+
+type selNHardest struct {
+	n int
+}
+
+func (sel selNHardest) pass(jet *Jet) bool {
+	panic("fastjet: NHardest selector does not apply jet by jet")
+}
+
+func (sel selNHardest) terminator(jets []*Jet) {
+	idx := make([]int, 0, len(jets))
+	for i, jet := range jets {
+		if jet != nil {
+			idx = append(idx, i)
+		}
+	}
+	if len(idx) <= sel.n {
+		return
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		return jets[idx[i]].Pt2() > jets[idx[j]].Pt2()
+	})
+	for _, i := range idx[imax(sel.n, 0):] {
+		jets[i] = nil
+	}
+}
+
+func (sel selNHardest) jetByJet() bool { return false }
+
+func (sel selNHardest) area() (float64, bool) {
+	return 0, false
+}
+
+func (sel selNHardest) description() string {
+	return fmt.Sprintf("%d hardest", sel.n)
+}
+
+type selCircle struct {
+	r   float64
+	ref *Jet
+}
+
+func (sel selCircle) pass(jet *Jet) bool {
+	if sel.ref == nil {
+		panic("fastjet: Circle selector has no reference jet")
+	}
+	return Distance(jet, sel.ref) <= sel.r*sel.r
+}
+
+func (sel selCircle) area() (float64, bool) {
+	return math.Pi * sel.r * sel.r, true
+}
+
+func (sel selCircle) rapRange() (float64, float64) {
+	if sel.ref == nil {
+		return math.Inf(-1), math.Inf(+1)
+	}
+	rap := sel.ref.Rapidity()
+	return rap - sel.r, rap + sel.r
+}
+
+func (sel selCircle) takesReference() bool { return true }
+
+func (sel selCircle) withReference(ref *Jet) selectorWorker {
+	jet := *ref
+	sel.ref = &jet
+	return sel
+}
+
+func (sel selCircle) description() string {
+	return fmt.Sprintf("distance from the centre <= %v", sel.r)
+}
+
+type selStrip struct {
+	hw  float64
+	ref *Jet
+}
+
+func (sel selStrip) pass(jet *Jet) bool {
+	if sel.ref == nil {
+		panic("fastjet: Strip selector has no reference jet")
+	}
+	return math.Abs(deltaRap(jet, sel.ref)) <= sel.hw
+}
+
+func (sel selStrip) area() (float64, bool) {
+	return 2 * math.Pi * 2 * sel.hw, true
+}
+
+func (sel selStrip) rapRange() (float64, float64) {
+	if sel.ref == nil {
+		return math.Inf(-1), math.Inf(+1)
+	}
+	rap := sel.ref.Rapidity()
+	return rap - sel.hw, rap + sel.hw
+}
+
+func (sel selStrip) takesReference() bool { return true }
+
+func (sel selStrip) withReference(ref *Jet) selectorWorker {
+	jet := *ref
+	sel.ref = &jet
+	return sel
+}
+
+func (sel selStrip) description() string {
+	return fmt.Sprintf("|rap - rap_reference| <= %v", sel.hw)
+}
+
+type selAnd struct {
+	s1, s2 selectorWorker
+}
+
+func (sel selAnd) pass(jet *Jet) bool {
+	return sel.s1.pass(jet) && sel.s2.pass(jet)
+}
+
+func (sel selAnd) terminator(jets []*Jet) {
+	js := append([]*Jet(nil), jets...)
+	terminator(sel.s1, js)
+	terminator(sel.s2, jets)
+	for i, jet := range js {
+		if jet == nil {
+			jets[i] = nil
+		}
+	}
+}
+
+func (sel selAnd) jetByJet() bool {
+	return jetByJet(sel.s1) && jetByJet(sel.s2)
+}
+
+func (sel selAnd) area() (float64, bool) {
+	return 0, false
+}
+
+func (sel selAnd) rapRange() (float64, float64) {
+	min1, max1 := rapRange(sel.s1)
+	min2, max2 := rapRange(sel.s2)
+	return math.Max(min1, min2), math.Min(max1, max2)
+}
+
+func (sel selAnd) takesReference() bool {
+	return takesReference(sel.s1) || takesReference(sel.s2)
+}
+
+func (sel selAnd) withReference(ref *Jet) selectorWorker {
+	return selAnd{withReference(sel.s1, ref), withReference(sel.s2, ref)}
+}
+
+func (sel selAnd) description() string {
+	return fmt.Sprintf("(%s && %s)", sel.s1.description(), sel.s2.description())
+}
+
+type selOr struct {
+	s1, s2 selectorWorker
+}
+
+func (sel selOr) pass(jet *Jet) bool {
+	return sel.s1.pass(jet) || sel.s2.pass(jet)
+}
+
+func (sel selOr) terminator(jets []*Jet) {
+	js := append([]*Jet(nil), jets...)
+	terminator(sel.s1, js)
+	terminator(sel.s2, jets)
+	for i, jet := range js {
+		if jet != nil {
+			jets[i] = jet
+		}
+	}
+}
+
+func (sel selOr) jetByJet() bool {
+	return jetByJet(sel.s1) && jetByJet(sel.s2)
+}
+
+func (sel selOr) area() (float64, bool) {
+	return 0, false
+}
+
+func (sel selOr) rapRange() (float64, float64) {
+	min1, max1 := rapRange(sel.s1)
+	min2, max2 := rapRange(sel.s2)
+	return math.Min(min1, min2), math.Max(max1, max2)
+}
+
+func (sel selOr) takesReference() bool {
+	return takesReference(sel.s1) || takesReference(sel.s2)
+}
+
+func (sel selOr) withReference(ref *Jet) selectorWorker {
+	return selOr{withReference(sel.s1, ref), withReference(sel.s2, ref)}
+}
+
+func (sel selOr) description() string {
+	return fmt.Sprintf("(%s || %s)", sel.s1.description(), sel.s2.description())
+}
+
+type selNot struct {
+	s selectorWorker
+}
+
+func (sel selNot) pass(jet *Jet) bool {
+	return !sel.s.pass(jet)
+}
+
+func (sel selNot) terminator(jets []*Jet) {
+	js := append([]*Jet(nil), jets...)
+	terminator(sel.s, js)
+	for i, jet := range js {
+		if jet != nil {
+			jets[i] = nil
+		}
+	}
+}
+
+func (sel selNot) jetByJet() bool {
+	return jetByJet(sel.s)
+}
+
+func (sel selNot) area() (float64, bool) {
+	return 0, false
+}
+
+func (sel selNot) takesReference() bool {
+	return takesReference(sel.s)
+}
+
+func (sel selNot) withReference(ref *Jet) selectorWorker {
+	return selNot{withReference(sel.s, ref)}
+}
+
+func (sel selNot) description() string {
+	return fmt.Sprintf("!%s", sel.s.description())
+}
+
+type selMult struct {
+	s1, s2 selectorWorker
+}
+
+func (sel selMult) pass(jet *Jet) bool {
+	return sel.s1.pass(jet) && sel.s2.pass(jet)
+}
+
+func (sel selMult) terminator(jets []*Jet) {
+	terminator(sel.s2, jets)
+	terminator(sel.s1, jets)
+}
+
+func (sel selMult) jetByJet() bool {
+	return jetByJet(sel.s1) && jetByJet(sel.s2)
+}
+
+func (sel selMult) area() (float64, bool) {
+	return 0, false
+}
+
+func (sel selMult) rapRange() (float64, float64) {
+	min1, max1 := rapRange(sel.s1)
+	min2, max2 := rapRange(sel.s2)
+	return math.Max(min1, min2), math.Min(max1, max2)
+}
+
+func (sel selMult) takesReference() bool {
+	return takesReference(sel.s1) || takesReference(sel.s2)
+}
+
+func (sel selMult) withReference(ref *Jet) selectorWorker {
+	return selMult{withReference(sel.s1, ref), withReference(sel.s2, ref)}
+}
+
+func (sel selMult) description() string {
+	return fmt.Sprintf("(%s * %s)", sel.s1.description(), sel.s2.description())
+}
@@ -0,0 +1,239 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fastjet_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"go-hep.org/x/hep/fastjet"
+	"go-hep.org/x/hep/fastjet/internal/fjtest"
+)
+
+func TestSelector(t *testing.T) {
+	jets := []fastjet.Jet{
+		fjtest.NewJetPtYPhi(10, 0.0, 0.0),
+		fjtest.NewJetPtYPhi(50, 1.5, 1.0),
+		fjtest.NewJetPtYPhi(30, -2.5, 2.0),
+		fjtest.NewJetPtYPhi(5, 3.5, 3.0),
+		fjtest.NewJetPtYPhi(20, 0.5, 0.3),
+	}
+
+	for _, tc := range []struct {
+		sel   fastjet.Selector
+		desc  string
+		want  []float64 // pts of the selected jets
+		byJet bool
+	}{
+		{
+			sel:   fastjet.SelectorPtMin(15),
+			desc:  "pt >= 15",
+			want:  []float64{50, 30, 20},
+			byJet: true,
+		},
+		{
+			sel:   fastjet.SelectorAbsRapMax(2),
+			desc:  "-2 <= rap <= 2",
+			want:  []float64{10, 50, 20},
+			byJet: true,
+		},
+		{
+			sel:   fastjet.SelectorRapRange(-3, 1),
+			desc:  "-3 <= rap <= 1",
+			want:  []float64{10, 30, 20},
+			byJet: true,
+		},
+		{
+			sel:  fastjet.SelectorNHardest(2),
+			desc: "2 hardest",
+			want: []float64{50, 30},
+		},
+		{
+			sel:   fastjet.SelectorPtMin(15).And(fastjet.SelectorAbsRapMax(2)),
+			desc:  "(pt >= 15 && -2 <= rap <= 2)",
+			want:  []float64{50, 20},
+			byJet: true,
+		},
+		{
+			sel:   fastjet.SelectorPtMin(40).Or(fastjet.SelectorAbsRapMax(1)),
+			desc:  "(pt >= 40 || -1 <= rap <= 1)",
+			want:  []float64{10, 50, 20},
+			byJet: true,
+		},
+		{
+			sel:   fastjet.SelectorPtMin(15).Not(),
+			desc:  "!pt >= 15",
+			want:  []float64{10, 5},
+			byJet: true,
+		},
+		{
+			// the 2 hardest jets of the initial set, if within |rap| <= 2.
+			sel:  fastjet.SelectorNHardest(2).And(fastjet.SelectorAbsRapMax(2)),
+			desc: "(2 hardest && -2 <= rap <= 2)",
+			want: []float64{50},
+		},
+		{
+			// the 2 hardest jets among those within |rap| <= 2.
+			sel:  fastjet.SelectorNHardest(2).Mult(fastjet.SelectorAbsRapMax(2)),
+			desc: "(2 hardest * -2 <= rap <= 2)",
+			want: []float64{50, 20},
+		},
+		{
+			sel:  fastjet.SelectorNHardest(2).Not(),
+			desc: "!2 hardest",
+			want: []float64{10, 5, 20},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got, want := tc.sel.Description(), tc.desc; got != want {
+				t.Fatalf("invalid description: got=%q, want=%q", got, want)
+			}
+			if got, want := tc.sel.AppliesJetByJet(), tc.byJet; got != want {
+				t.Fatalf("invalid jet-by-jet: got=%v, want=%v", got, want)
+			}
+
+			sel := tc.sel.Select(jets)
+			if got, want := pts(sel), tc.want; !reflect.DeepEqual(got, want) {
+				t.Fatalf("invalid selection: got=%v, want=%v", got, want)
+			}
+			if got, want := tc.sel.Count(jets), len(tc.want); got != want {
+				t.Fatalf("invalid count: got=%d, want=%d", got, want)
+			}
+
+			if !tc.byJet {
+				return
+			}
+			var o []float64
+			for i := range jets {
+				if tc.sel.Pass(&jets[i]) {
+					o = append(o, jets[i].Pt())
+				}
+			}
+			if got, want := o, tc.want; !reflect.DeepEqual(got, want) {
+				t.Fatalf("invalid jet-by-jet selection: got=%v, want=%v", got, want)
+			}
+		})
+	}
+}
+
+func TestSelectorReference(t *testing.T) {
+	jets := []fastjet.Jet{
+		fjtest.NewJetPtYPhi(10, 0.0, 0.0),
+		fjtest.NewJetPtYPhi(50, 0.3, 0.3),
+		fjtest.NewJetPtYPhi(30, 1.5, 0.1),
+		fjtest.NewJetPtYPhi(5, 0.2, 2*math.Pi-0.2),
+	}
+	ref := fjtest.NewJetPtYPhi(1, 0, 0)
+
+	for _, tc := range []struct {
+		sel  fastjet.Selector
+		want []float64
+	}{
+		{
+			sel:  fastjet.SelectorCircle(0.5),
+			want: []float64{10, 50, 5},
+		},
+		{
+			sel:  fastjet.SelectorStrip(1),
+			want: []float64{10, 50, 5},
+		},
+		{
+			sel:  fastjet.SelectorCircle(0.5).And(fastjet.SelectorPtMin(8)),
+			want: []float64{10, 50},
+		},
+		{
+			sel:  fastjet.SelectorStrip(1).Not(),
+			want: []float64{30},
+		},
+	} {
+		t.Run(tc.sel.Description(), func(t *testing.T) {
+			if !tc.sel.TakesReference() {
+				t.Fatalf("selector should take a reference")
+			}
+			sel := tc.sel.WithReference(&ref)
+			if got, want := pts(sel.Select(jets)), tc.want; !reflect.DeepEqual(got, want) {
+				t.Fatalf("invalid selection: got=%v, want=%v", got, want)
+			}
+		})
+	}
+
+	if fastjet.SelectorPtMin(1).TakesReference() {
+		t.Fatalf("pt-min selector should not take a reference")
+	}
+}
+
+func TestSelectorArea(t *testing.T) {
+	for _, tc := range []struct {
+		sel  fastjet.Selector
+		want float64
+		tol  float64
+		err  bool
+	}{
+		{
+			sel:  fastjet.SelectorAbsRapMax(2),
+			want: 2 * 2 * 2 * math.Pi,
+			tol:  1e-12,
+		},
+		{
+			sel:  fastjet.SelectorRapRange(-1, 2),
+			want: 3 * 2 * math.Pi,
+			tol:  1e-12,
+		},
+		{
+			sel:  fastjet.SelectorCircle(0.4),
+			want: math.Pi * 0.4 * 0.4,
+			tol:  1e-12,
+		},
+		{
+			sel:  fastjet.SelectorStrip(0.5),
+			want: 2 * math.Pi,
+			tol:  1e-12,
+		},
+		{
+			sel:  fastjet.SelectorAbsRapMax(2).And(fastjet.SelectorPtMin(1)),
+			want: 0,
+			tol:  1e-12,
+		},
+		{
+			// estimated with ghosts: the 2 hardest ghosts are removed.
+			sel:  fastjet.SelectorAbsRapMax(2).And(fastjet.SelectorNHardest(2).Not()),
+			want: 2 * 2 * 2 * math.Pi,
+			tol:  0.05,
+		},
+		{
+			sel:  fastjet.SelectorAbsRapMax(2).And(fastjet.SelectorRapRange(1, 3)),
+			want: 1 * 2 * math.Pi,
+			tol:  1e-9,
+		},
+		{
+			sel: fastjet.SelectorPtMin(1),
+			err: true,
+		},
+	} {
+		t.Run(tc.sel.Description(), func(t *testing.T) {
+			got, err := tc.sel.Area()
+			switch {
+			case err != nil && !tc.err:
+				t.Fatalf("could not compute area: %+v", err)
+			case err == nil && tc.err:
+				t.Fatalf("expected an error")
+			case err != nil:
+				return
+			}
+			if math.Abs(got-tc.want) > tc.tol {
+				t.Fatalf("invalid area: got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func pts(jets []fastjet.Jet) []float64 {
+	var o []float64
+	for i := range jets {
+		o = append(o, math.Round(jets[i].Pt()*1e6)/1e6)
+	}
+	return o
+}
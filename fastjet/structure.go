@@ -4,7 +4,29 @@
 
 package fastjet
 
+import (
+	"go-hep.org/x/hep/fmom"
+)
+
 // JetStructure allows to retrieve information related to the clustering.
 type JetStructure interface {
 	Constituents(jet *Jet) ([]Jet, error)
 }
+
+// JetAreaStructure allows to retrieve information related to the
+// clustering and to the area of jets.
+type JetAreaStructure interface {
+	JetStructure
+
+	// Area returns the scalar area of a jet.
+	Area(jet *Jet) float64
+
+	// AreaErr returns the uncertainty on the area of a jet.
+	AreaErr(jet *Jet) float64
+
+	// Area4 returns the 4-vector area of a jet.
+	Area4(jet *Jet) fmom.PxPyPzE
+
+	// IsPureGhost returns whether a jet is only made of ghosts.
+	IsPureGhost(jet *Jet) bool
+}
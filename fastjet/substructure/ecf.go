@@ -0,0 +1,178 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package substructure
+
+import (
+	"fmt"
+	"math"
+
+	"go-hep.org/x/hep/fastjet"
+)
+
+// EnergyCorrelator computes the N-point energy correlation function of
+// jets, with the transverse momenta and rapidity-azimuth distances of the
+// constituents:
+//
+//	ECF(0) = 1
+//	ECF(1) = sum_i pt_i
+//	ECF(2) = sum_{i<j} pt_i pt_j dR_ij^beta
+//	ECF(3) = sum_{i<j<k} pt_i pt_j pt_k (dR_ij dR_ik dR_jk)^beta
+//
+// and so on for higher N.
+//
+// See:
+//   - A. J. Larkoski, G. P. Salam, J. Thaler, arXiv:1305.0007
+type EnergyCorrelator struct {
+	n    int
+	beta float64
+}
+
+// NewEnergyCorrelator returns a new N-point energy correlation function
+// calculator, with the angular exponent beta.
+func NewEnergyCorrelator(n int, beta float64) *EnergyCorrelator {
+	if n < 0 {
+		panic(fmt.Errorf("substructure: invalid number of points (%d)", n))
+	}
+	return &EnergyCorrelator{n: n, beta: beta}
+}
+
+// Value returns the energy correlation function of the jet.
+func (ecf *EnergyCorrelator) Value(jet *fastjet.Jet) float64 {
+	return ecfValue(ecf.n, ecf.beta, jet.Constituents())
+}
+
+// Description returns a string description of the observable.
+func (ecf *EnergyCorrelator) Description() string {
+	return fmt.Sprintf("Energy correlator ECF(%d, beta=%v)", ecf.n, ecf.beta)
+}
+
+// EnergyCorrelatorC computes the double ratio of energy correlation
+// functions:
+//
+//	C_N = ECF(N+1) ECF(N-1) / ECF(N)^2
+type EnergyCorrelatorC struct {
+	n    int
+	beta float64
+}
+
+// NewEnergyCorrelatorC returns a new C_N calculator, with the angular
+// exponent beta.
+func NewEnergyCorrelatorC(n int, beta float64) *EnergyCorrelatorC {
+	if n < 1 {
+		panic(fmt.Errorf("substructure: invalid number of points (%d)", n))
+	}
+	return &EnergyCorrelatorC{n: n, beta: beta}
+}
+
+// Value returns the C_N ratio of the jet.
+func (ecf *EnergyCorrelatorC) Value(jet *fastjet.Jet) float64 {
+	consts := jet.Constituents()
+	den := ecfValue(ecf.n, ecf.beta, consts)
+	if den == 0 {
+		return 0
+	}
+	var (
+		v1 = ecfValue(ecf.n+1, ecf.beta, consts)
+		v2 = ecfValue(ecf.n-1, ecf.beta, consts)
+	)
+	return v1 * v2 / (den * den)
+}
+
+// Description returns a string description of the observable.
+func (ecf *EnergyCorrelatorC) Description() string {
+	return fmt.Sprintf("Energy correlator ratio C%d(beta=%v)", ecf.n, ecf.beta)
+}
+
+// EnergyCorrelatorD2 computes the D2 ratio of energy correlation
+// functions:
+//
+//	D2 = ECF(3) ECF(1)^3 / ECF(2)^3
+//
+// See:
+//   - A. J. Larkoski, I. Moult, D. Neill, arXiv:1409.6298
+type EnergyCorrelatorD2 struct {
+	beta float64
+}
+
+// NewEnergyCorrelatorD2 returns a new D2 calculator, with the angular
+// exponent beta.
+func NewEnergyCorrelatorD2(beta float64) *EnergyCorrelatorD2 {
+	return &EnergyCorrelatorD2{beta: beta}
+}
+
+// Value returns the D2 ratio of the jet.
+func (ecf *EnergyCorrelatorD2) Value(jet *fastjet.Jet) float64 {
+	consts := jet.Constituents()
+	e2 := ecfValue(2, ecf.beta, consts)
+	if e2 == 0 {
+		return 0
+	}
+	var (
+		e1 = ecfValue(1, ecf.beta, consts)
+		e3 = ecfValue(3, ecf.beta, consts)
+	)
+	return e3 * e1 * e1 * e1 / (e2 * e2 * e2)
+}
+
+// Description returns a string description of the observable.
+func (ecf *EnergyCorrelatorD2) Description() string {
+	return fmt.Sprintf("Energy correlator ratio D2(beta=%v)", ecf.beta)
+}
+
+// ecfValue returns the n-point energy correlation function of the
+// provided particles.
+func ecfValue(n int, beta float64, particles []fastjet.Jet) float64 {
+	switch n {
+	case 0:
+		return 1
+	case 1:
+		sum := 0.0
+		for i := range particles {
+			sum += particles[i].Pt()
+		}
+		return sum
+	}
+
+	var (
+		np  = len(particles)
+		pts = make([]float64, np)
+		ang = make([][]float64, np)
+	)
+	for i := range particles {
+		pts[i] = particles[i].Pt()
+		ang[i] = make([]float64, np)
+		for j := 0; j < i; j++ {
+			v := math.Pow(fastjet.Distance(&particles[i], &particles[j]), 0.5*beta)
+			ang[i][j] = v
+			ang[j][i] = v
+		}
+	}
+
+	var (
+		sum  = 0.0
+		idx  = make([]int, n)
+		loop func(k, beg int, w float64)
+	)
+	loop = func(k, beg int, w float64) {
+		if k == n {
+			sum += w
+			return
+		}
+		for i := beg; i < np; i++ {
+			v := w * pts[i]
+			for _, j := range idx[:k] {
+				v *= ang[i][j]
+			}
+			if v == 0 {
+				continue
+			}
+			idx[k] = i
+			loop(k+1, i+1, v)
+		}
+	}
+	loop(0, 0, 1)
+
+	return sum
+}
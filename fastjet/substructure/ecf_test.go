@@ -0,0 +1,53 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package substructure_test
+
+import (
+	"math"
+	"testing"
+
+	"go-hep.org/x/hep/fastjet"
+	"go-hep.org/x/hep/fastjet/internal/fjtest"
+	"go-hep.org/x/hep/fastjet/substructure"
+)
+
+func TestEnergyCorrelator(t *testing.T) {
+	var (
+		two   = fastjet.Join(fjtest.NewJetPtYPhi(100, 0, 0), fjtest.NewJetPtYPhi(50, 0.4, 0))
+		three = fastjet.Join(fjtest.NewJetPtYPhi(100, 0, 0), fjtest.NewJetPtYPhi(50, 0.3, 0), fjtest.NewJetPtYPhi(20, 0, 0.4))
+	)
+
+	for _, tc := range []struct {
+		name string
+		jet  fastjet.Jet
+		ecf  interface {
+			Value(jet *fastjet.Jet) float64
+		}
+		want float64
+	}{
+		{"two-ecf0", two, substructure.NewEnergyCorrelator(0, 1), 1},
+		{"two-ecf1", two, substructure.NewEnergyCorrelator(1, 1), 150},
+		{"two-ecf2", two, substructure.NewEnergyCorrelator(2, 1), 100 * 50 * 0.4},
+		{"two-ecf2-beta2", two, substructure.NewEnergyCorrelator(2, 2), 100 * 50 * 0.16},
+		{"two-ecf3", two, substructure.NewEnergyCorrelator(3, 1), 0},
+		{"two-c1", two, substructure.NewEnergyCorrelatorC(1, 1), 100 * 50 * 0.4 / (150 * 150)},
+		{"two-c2", two, substructure.NewEnergyCorrelatorC(2, 1), 0},
+		{"two-d2", two, substructure.NewEnergyCorrelatorD2(1), 0},
+		{"three-ecf2", three, substructure.NewEnergyCorrelator(2, 1), 100*50*0.3 + 100*20*0.4 + 50*20*0.5},
+		{"three-ecf3", three, substructure.NewEnergyCorrelator(3, 1), 100 * 50 * 20 * 0.3 * 0.4 * 0.5},
+		{"three-ecf4", three, substructure.NewEnergyCorrelator(4, 1), 0},
+		{
+			"three-d2", three, substructure.NewEnergyCorrelatorD2(1),
+			(100 * 50 * 20 * 0.3 * 0.4 * 0.5) * math.Pow(170, 3) / math.Pow(100*50*0.3+100*20*0.4+50*20*0.5, 3),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.ecf.Value(&tc.jet)
+			if math.Abs(got-tc.want) > 1e-6*math.Max(1, tc.want) {
+				t.Fatalf("invalid value: got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package substructure
+
+import (
+	"fmt"
+	"sort"
+
+	"go-hep.org/x/hep/fastjet"
+)
+
+// Filter reclusters the constituents of a jet into subjets and only keeps
+// a subset of these subjets.
+//
+// See:
+//   - J. M. Butterworth, A. R. Davison, M. Rubin, G. P. Salam, arXiv:0802.2470 (filtering)
+//   - D. Krohn, J. Thaler, L.-T. Wang, arXiv:0912.1342 (trimming)
+type Filter struct {
+	def  fastjet.JetDefinition
+	keep func(subjets []fastjet.Jet, jet *fastjet.Jet) []fastjet.Jet
+	desc string
+}
+
+// NewFilter returns a new filter, reclustering the constituents of jets
+// with the provided jet definition and keeping the n hardest subjets.
+func NewFilter(def fastjet.JetDefinition, n int) *Filter {
+	return &Filter{
+		def: def,
+		keep: func(subjets []fastjet.Jet, jet *fastjet.Jet) []fastjet.Jet {
+			if len(subjets) > n {
+				subjets = subjets[:n]
+			}
+			return subjets
+		},
+		desc: fmt.Sprintf("the %d hardest subjets", n),
+	}
+}
+
+// NewTrimmer returns a new trimmer, reclustering the constituents of jets
+// with the provided jet definition and keeping the subjets carrying at
+// least a fraction fcut of the transverse momentum of the jet.
+func NewTrimmer(def fastjet.JetDefinition, fcut float64) *Filter {
+	return &Filter{
+		def: def,
+		keep: func(subjets []fastjet.Jet, jet *fastjet.Jet) []fastjet.Jet {
+			var (
+				ptmin = fcut * jet.Pt()
+				o     = subjets[:0]
+			)
+			for _, sub := range subjets {
+				if sub.Pt() >= ptmin {
+					o = append(o, sub)
+				}
+			}
+			return o
+		},
+		desc: fmt.Sprintf("the subjets with pt >= %v pt(jet)", fcut),
+	}
+}
+
+// Groom returns the filtered jet, made of the selected subjets.
+func (f *Filter) Groom(jet *fastjet.Jet) (fastjet.Jet, error) {
+	subjets, err := f.Subjets(jet)
+	if err != nil {
+		return fastjet.Jet{}, err
+	}
+	return fastjet.Join(subjets...), nil
+}
+
+// Subjets returns the subjets selected by the filter, sorted by
+// decreasing transverse momentum.
+func (f *Filter) Subjets(jet *fastjet.Jet) ([]fastjet.Jet, error) {
+	consts := jet.Constituents()
+	if len(consts) == 0 {
+		return nil, nil
+	}
+
+	cs, err := recluster(consts, f.def)
+	if err != nil {
+		return nil, fmt.Errorf("substructure: could not run filter: %w", err)
+	}
+
+	subjets, err := cs.InclusiveJets(0)
+	if err != nil {
+		return nil, fmt.Errorf("substructure: could not retrieve subjets: %w", err)
+	}
+	sort.Sort(fastjet.ByPt(subjets))
+
+	return f.keep(subjets, jet), nil
+}
+
+// Description returns a string description of the groomer.
+func (f *Filter) Description() string {
+	return fmt.Sprintf("Filter with subjet definition: %s, keeping %s", f.def.Description(), f.desc)
+}
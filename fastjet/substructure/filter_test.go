@@ -0,0 +1,88 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package substructure_test
+
+import (
+	"math"
+	"testing"
+
+	"go-hep.org/x/hep/fastjet"
+	"go-hep.org/x/hep/fastjet/internal/fjtest"
+	"go-hep.org/x/hep/fastjet/substructure"
+)
+
+func TestFilter(t *testing.T) {
+	particles := []fastjet.Jet{
+		fjtest.NewJetPtYPhi(90, 0, 0),
+		fjtest.NewJetPtYPhi(10, 0.05, 0.05),
+		fjtest.NewJetPtYPhi(50, 0.6, 0),
+		fjtest.NewJetPtYPhi(8, 0, 0.6),
+		fjtest.NewJetPtYPhi(2, -0.5, -0.3),
+	}
+	jet := fastjet.Join(particles...)
+	def := fastjet.NewJetDefinition(fastjet.CambridgeAlgorithm, 0.2, fastjet.EScheme, fastjet.BestStrategy)
+
+	for _, tc := range []struct {
+		name   string
+		filter *substructure.Filter
+		n      int // number of kept particles
+	}{
+		{"filter-1", substructure.NewFilter(def, 1), 2},
+		{"filter-2", substructure.NewFilter(def, 2), 3},
+		{"filter-10", substructure.NewFilter(def, 10), 5},
+		{"trimmer-0", substructure.NewTrimmer(def, 0), 5},
+		{"trimmer-0.03", substructure.NewTrimmer(def, 0.03), 4},
+		{"trimmer-0.1", substructure.NewTrimmer(def, 0.1), 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			groomed, err := tc.filter.Groom(&jet)
+			if err != nil {
+				t.Fatalf("could not groom jet: %+v", err)
+			}
+			want := fastjet.Join(particles[:tc.n]...)
+			if got, want := groomed.Pt(), want.Pt(); math.Abs(got-want) > 1e-6 {
+				t.Fatalf("invalid groomed pt: got=%v, want=%v", got, want)
+			}
+			if got, want := len(groomed.Constituents()), tc.n; got != want {
+				t.Fatalf("invalid number of constituents: got=%d, want=%d", got, want)
+			}
+		})
+	}
+}
+
+func TestPruner(t *testing.T) {
+	jet := fastjet.Join(
+		fjtest.NewJetPtYPhi(100, 0, 0),
+		fjtest.NewJetPtYPhi(50, 0.4, 0),
+		fjtest.NewJetPtYPhi(2, 0, 0.9),
+		fjtest.NewJetPtYPhi(1, 0.05, 0.05),
+	)
+	def := fastjet.NewJetDefinition(fastjet.CambridgeAlgorithm, 1.0, fastjet.EScheme, fastjet.BestStrategy)
+
+	for _, tc := range []struct {
+		name string
+		zcut float64
+		pt   float64
+		n    int
+	}{
+		{"zcut=0", 0, jet.Pt(), 4},
+		{"zcut=0.1", 0.1, 151, 3},
+		{"zcut=0.4", 0.4, 101, 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pruner := substructure.NewPruner(def, tc.zcut, 0.5)
+			groomed, err := pruner.Groom(&jet)
+			if err != nil {
+				t.Fatalf("could not groom jet: %+v", err)
+			}
+			if got, want := groomed.Pt(), tc.pt; math.Abs(got-want) > 0.1 {
+				t.Fatalf("invalid groomed pt: got=%v, want=%v", got, want)
+			}
+			if got, want := len(groomed.Constituents()), tc.n; got != want {
+				t.Fatalf("invalid number of constituents: got=%d, want=%d", got, want)
+			}
+		})
+	}
+}
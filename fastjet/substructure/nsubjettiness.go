@@ -0,0 +1,178 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package substructure
+
+import (
+	"fmt"
+	"math"
+
+	"go-hep.org/x/hep/fastjet"
+)
+
+// AxesDefinition describes how the axes of N-subjettiness are found.
+type AxesDefinition int
+
+const (
+	KtAxes    AxesDefinition = iota // exclusive kt axes, with E-scheme recombination
+	CAAxes                          // exclusive Cambridge/Aachen axes, with E-scheme recombination
+	WTAKtAxes                       // exclusive kt axes, with winner-take-all recombination
+	WTACAAxes                       // exclusive Cambridge/Aachen axes, with winner-take-all recombination
+)
+
+func (axes AxesDefinition) String() string {
+	switch axes {
+	case KtAxes:
+		return "KT Axes"
+	case CAAxes:
+		return "CA Axes"
+	case WTAKtAxes:
+		return "Winner-Take-All KT Axes"
+	case WTACAAxes:
+		return "Winner-Take-All CA Axes"
+	default:
+		panic(fmt.Errorf("substructure: invalid AxesDefinition (%d)", int(axes)))
+	}
+}
+
+func (axes AxesDefinition) jetDefinition() fastjet.JetDefinition {
+	var def fastjet.JetDefinition
+	switch axes {
+	case KtAxes, WTAKtAxes:
+		def = fastjet.NewJetDefinition(fastjet.KtAlgorithm, maxR, fastjet.EScheme, fastjet.BestStrategy)
+	case CAAxes, WTACAAxes:
+		def = fastjet.NewJetDefinition(fastjet.CambridgeAlgorithm, maxR, fastjet.EScheme, fastjet.BestStrategy)
+	default:
+		panic(fmt.Errorf("substructure: invalid AxesDefinition (%d)", int(axes)))
+	}
+	switch axes {
+	case WTAKtAxes, WTACAAxes:
+		def.SetRecombiner(wtaRecombiner{})
+	}
+	return def
+}
+
+// Nsubjettiness computes the N-subjettiness of jets:
+//
+//	tau_N = 1/d0 sum_k pt_k min(dR_1k^beta, ..., dR_Nk^beta)
+//	d0    = sum_k pt_k R0^beta
+//
+// where the sums run over the constituents of the jet and dR_ik is the
+// rapidity-azimuth distance between the i-th axis and the k-th constituent.
+//
+// See:
+//   - J. Thaler, K. Van Tilburg, arXiv:1011.2268
+//   - J. Thaler, K. Van Tilburg, arXiv:1108.2701
+type Nsubjettiness struct {
+	n    int
+	axes AxesDefinition
+	beta float64
+	r0   float64
+}
+
+// NewNsubjettiness returns a new N-subjettiness calculator for n axes,
+// with the angular exponent beta and the characteristic radius r0.
+func NewNsubjettiness(n int, axes AxesDefinition, beta, r0 float64) *Nsubjettiness {
+	if n < 1 {
+		panic(fmt.Errorf("substructure: invalid number of axes (%d)", n))
+	}
+	return &Nsubjettiness{
+		n:    n,
+		axes: axes,
+		beta: beta,
+		r0:   r0,
+	}
+}
+
+// Axes returns the axes used to compute the N-subjettiness of the jet.
+// If the jet has no more than N constituents, the constituents are used as
+// axes.
+func (ns *Nsubjettiness) Axes(jet *fastjet.Jet) ([]fastjet.Jet, error) {
+	consts := jet.Constituents()
+	if len(consts) <= ns.n {
+		return consts, nil
+	}
+
+	cs, err := recluster(consts, ns.axes.jetDefinition())
+	if err != nil {
+		return nil, fmt.Errorf("substructure: could not find N-subjettiness axes: %w", err)
+	}
+	axes, err := cs.ExclusiveJetsUpTo(ns.n)
+	if err != nil {
+		return nil, fmt.Errorf("substructure: could not find N-subjettiness axes: %w", err)
+	}
+	return axes, nil
+}
+
+// Tau returns the N-subjettiness of the jet.
+func (ns *Nsubjettiness) Tau(jet *fastjet.Jet) (float64, error) {
+	axes, err := ns.Axes(jet)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		consts = jet.Constituents()
+		num    = 0.0
+		d0     = 0.0
+		r0     = math.Pow(ns.r0, ns.beta)
+	)
+	for i := range consts {
+		c := &consts[i]
+		dr2 := math.Inf(+1)
+		for j := range axes {
+			dr2 = math.Min(dr2, fastjet.Distance(c, &axes[j]))
+		}
+		pt := c.Pt()
+		num += pt * math.Pow(dr2, 0.5*ns.beta)
+		d0 += pt * r0
+	}
+	if d0 == 0 {
+		return 0, nil
+	}
+	return num / d0, nil
+}
+
+// Description returns a string description of the observable.
+func (ns *Nsubjettiness) Description() string {
+	return fmt.Sprintf(
+		"N-subjettiness with N=%d, %v, beta=%v, R0=%v",
+		ns.n, ns.axes, ns.beta, ns.r0,
+	)
+}
+
+// wtaRecombiner is the winner-take-all recombiner: the recombined jet is
+// massless, takes the direction of the harder jet and the sum of the
+// transverse momenta of both jets.
+type wtaRecombiner struct{}
+
+func (wtaRecombiner) Description() string {
+	return "Winner-Take-All pt scheme recombination"
+}
+
+func (wtaRecombiner) Recombine(j1, j2 *fastjet.Jet) (fastjet.Jet, error) {
+	hard := j1
+	if j1.Pt() < j2.Pt() {
+		hard = j2
+	}
+	var (
+		pt  = j1.Pt() + j2.Pt()
+		y   = hard.Rapidity()
+		phi = hard.Phi()
+	)
+	return fastjet.NewJet(
+		pt*math.Cos(phi),
+		pt*math.Sin(phi),
+		pt*math.Sinh(y),
+		pt*math.Cosh(y),
+	), nil
+}
+
+func (wtaRecombiner) Preprocess(jet *fastjet.Jet) error {
+	return nil
+}
+
+func (wtaRecombiner) Scheme() fastjet.RecombinationScheme {
+	return fastjet.ExternalScheme
+}
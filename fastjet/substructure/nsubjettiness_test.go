@@ -0,0 +1,84 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package substructure_test
+
+import (
+	"math"
+	"testing"
+
+	"go-hep.org/x/hep/fastjet"
+	"go-hep.org/x/hep/fastjet/internal/fjtest"
+	"go-hep.org/x/hep/fastjet/substructure"
+)
+
+func TestNsubjettiness(t *testing.T) {
+	var (
+		sym  = fastjet.Join(fjtest.NewJetPtYPhi(100, -0.3, 0), fjtest.NewJetPtYPhi(100, +0.3, 0))
+		asym = fastjet.Join(fjtest.NewJetPtYPhi(100, 0, 0), fjtest.NewJetPtYPhi(50, 0.4, 0))
+	)
+
+	for _, tc := range []struct {
+		name string
+		jet  fastjet.Jet
+		n    int
+		axes substructure.AxesDefinition
+		beta float64
+		r0   float64
+		want float64
+	}{
+		{"sym-kt-tau1", sym, 1, substructure.KtAxes, 1, 1, 0.3},
+		{"sym-kt-tau1-beta2", sym, 1, substructure.KtAxes, 2, 1, 0.09},
+		{"sym-ca-tau1-r0", sym, 1, substructure.CAAxes, 1, 0.6, 0.5},
+		{"sym-kt-tau2", sym, 2, substructure.KtAxes, 1, 1, 0},
+		{"asym-wta-kt-tau1", asym, 1, substructure.WTAKtAxes, 1, 1, 50 * 0.4 / 150},
+		{"asym-wta-ca-tau1", asym, 1, substructure.WTACAAxes, 1, 1, 50 * 0.4 / 150},
+		{"asym-wta-kt-tau2", asym, 2, substructure.WTAKtAxes, 1, 1, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ns := substructure.NewNsubjettiness(tc.n, tc.axes, tc.beta, tc.r0)
+			got, err := ns.Tau(&tc.jet)
+			if err != nil {
+				t.Fatalf("could not compute N-subjettiness: %+v", err)
+			}
+			if math.Abs(got-tc.want) > 1e-6 {
+				t.Fatalf("invalid tau%d: got=%v, want=%v", tc.n, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNsubjettinessAxes(t *testing.T) {
+	jet := fastjet.Join(
+		fjtest.NewJetPtYPhi(100, 0, 0),
+		fjtest.NewJetPtYPhi(10, 0.1, 0),
+		fjtest.NewJetPtYPhi(50, 1, 1),
+		fjtest.NewJetPtYPhi(5, 1, 1.1),
+	)
+
+	for _, axes := range []substructure.AxesDefinition{
+		substructure.KtAxes,
+		substructure.CAAxes,
+		substructure.WTAKtAxes,
+		substructure.WTACAAxes,
+	} {
+		t.Run(axes.String(), func(t *testing.T) {
+			ns := substructure.NewNsubjettiness(2, axes, 1, 1)
+			got, err := ns.Axes(&jet)
+			if err != nil {
+				t.Fatalf("could not compute axes: %+v", err)
+			}
+			if len(got) != 2 {
+				t.Fatalf("invalid number of axes: got=%d, want=2", len(got))
+			}
+			pts := []float64{got[0].Pt(), got[1].Pt()}
+			if pts[0] < pts[1] {
+				pts[0], pts[1] = pts[1], pts[0]
+			}
+			if math.Abs(pts[0]-110) > 0.1 || math.Abs(pts[1]-55) > 0.1 {
+				t.Fatalf("invalid axes pt: %v", pts)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package substructure
+
+import (
+	"fmt"
+	"sort"
+
+	"go-hep.org/x/hep/fastjet"
+)
+
+// Pruner reclusters the constituents of a jet and, at each recombination
+// of two jets j1 and j2, discards the softer one if:
+//
+//	min(pt1, pt2) / pt12 < zcut  and  dR12 > Rcut
+//
+// with Rcut = rcutFactor * 2 m / pt, computed from the mass and transverse
+// momentum of the original jet.
+//
+// See:
+//   - S. D. Ellis, C. K. Vermilion, J. R. Walsh, arXiv:0903.5081
+type Pruner struct {
+	def        fastjet.JetDefinition
+	zcut       float64
+	rcutFactor float64
+}
+
+// NewPruner returns a new pruner, reclustering the constituents of jets
+// with the provided jet definition.
+func NewPruner(def fastjet.JetDefinition, zcut, rcutFactor float64) *Pruner {
+	return &Pruner{
+		def:        def,
+		zcut:       zcut,
+		rcutFactor: rcutFactor,
+	}
+}
+
+// Groom returns the pruned jet, made of the constituents that were not
+// discarded during the reclustering.
+func (p *Pruner) Groom(jet *fastjet.Jet) (fastjet.Jet, error) {
+	consts := jet.Constituents()
+	if len(consts) == 0 || jet.Pt() == 0 {
+		return fastjet.Join(), nil
+	}
+
+	// label the constituents with their index, to identify the discarded ones.
+	particles := make([]fastjet.Jet, len(consts))
+	for i := range consts {
+		c := &consts[i]
+		particles[i] = fastjet.NewJet(c.Px(), c.Py(), c.Pz(), c.E())
+		particles[i].UserInfo = i
+	}
+
+	var (
+		rcut = p.rcutFactor * 2 * jet.M() / jet.Pt()
+		rec  = &pruningRecombiner{
+			Recombiner: p.def.Recombiner(),
+			zcut:       p.zcut,
+			rcut2:      rcut * rcut,
+			pruned:     make([]bool, len(consts)),
+		}
+		def = p.def
+	)
+	def.SetRecombiner(rec)
+
+	cs, err := recluster(particles, def)
+	if err != nil {
+		return fastjet.Jet{}, fmt.Errorf("substructure: could not run pruner: %w", err)
+	}
+
+	jets, err := cs.InclusiveJets(0)
+	if err != nil {
+		return fastjet.Jet{}, fmt.Errorf("substructure: could not retrieve pruned jets: %w", err)
+	}
+	if len(jets) == 0 {
+		return fastjet.Join(), nil
+	}
+	sort.Sort(fastjet.ByPt(jets))
+
+	var kept []fastjet.Jet
+	for _, c := range jets[0].Constituents() {
+		i := c.UserInfo.(int)
+		if rec.pruned[i] {
+			continue
+		}
+		kept = append(kept, consts[i])
+	}
+	return fastjet.Join(kept...), nil
+}
+
+// Description returns a string description of the groomer.
+func (p *Pruner) Description() string {
+	return fmt.Sprintf(
+		"Pruner with jet definition: %s, zcut=%v, Rcut=%v x 2m/pt",
+		p.def.Description(), p.zcut, p.rcutFactor,
+	)
+}
+
+// pruningRecombiner recombines jets and discards the softer one when the
+// pruning conditions are met.
+type pruningRecombiner struct {
+	fastjet.Recombiner
+
+	zcut   float64
+	rcut2  float64
+	pruned []bool // constituents discarded by the pruning
+}
+
+func (rec *pruningRecombiner) Description() string {
+	return fmt.Sprintf("%s, with pruning (zcut=%v)", rec.Recombiner.Description(), rec.zcut)
+}
+
+func (rec *pruningRecombiner) Recombine(j1, j2 *fastjet.Jet) (fastjet.Jet, error) {
+	jet, err := rec.Recombiner.Recombine(j1, j2)
+	if err != nil {
+		return jet, err
+	}
+
+	hard, soft := j1, j2
+	if hard.Pt() < soft.Pt() {
+		hard, soft = soft, hard
+	}
+	if jet.Pt() == 0 || soft.Pt()/jet.Pt() >= rec.zcut || fastjet.Distance(j1, j2) <= rec.rcut2 {
+		return jet, nil
+	}
+
+	for _, c := range soft.Constituents() {
+		rec.pruned[c.UserInfo.(int)] = true
+	}
+	return fastjet.NewJet(hard.Px(), hard.Py(), hard.Pz(), hard.E()), nil
+}
@@ -0,0 +1,152 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package substructure
+
+import (
+	"fmt"
+	"math"
+
+	"go-hep.org/x/hep/fastjet"
+)
+
+// SoftDrop implements the Soft Drop grooming procedure and the modified
+// Mass Drop Tagger (mMDT).
+//
+// The jet is reclustered with the Cambridge/Aachen algorithm and then
+// declustered, following the hardest branch, until the two branches
+// j1 and j2 satisfy:
+//
+//	min(pt1, pt2) / (pt1 + pt2) > zcut * (dR12 / R0)^beta
+//
+// and, for the mMDT, the mass-drop condition:
+//
+//	max(m1, m2) < mu * m12
+//
+// See:
+//   - A. J. Larkoski, S. Marzani, G. Soyez, J. Thaler, arXiv:1402.2657
+//   - M. Dasgupta, A. Fregoso, S. Marzani, G. P. Salam, arXiv:1307.0007
+type SoftDrop struct {
+	beta float64
+	zcut float64
+	r0   float64
+	mu   float64
+
+	// tagging indicates whether a jet without any branching satisfying
+	// the conditions is rejected (tagging mode) or reduced to its
+	// hardest constituent (grooming mode).
+	tagging bool
+}
+
+// SoftDropResult holds the result of the Soft Drop declustering of a jet.
+type SoftDropResult struct {
+	Jet     fastjet.Jet // groomed jet
+	Zg      float64     // momentum sharing of the two branches of the groomed jet
+	Rg      float64     // rapidity-azimuth distance between the two branches of the groomed jet
+	Mu      float64     // mass drop of the two branches of the groomed jet
+	Dropped int         // number of branches removed by the grooming
+}
+
+// NewSoftDrop returns a new Soft Drop groomer with the provided angular
+// exponent beta, symmetry cut zcut and characteristic radius r0.
+//
+// Jets where no branching satisfies the Soft Drop condition are reduced
+// to their hardest constituent.
+func NewSoftDrop(beta, zcut, r0 float64) *SoftDrop {
+	return &SoftDrop{
+		beta: beta,
+		zcut: zcut,
+		r0:   r0,
+		mu:   math.Inf(+1),
+	}
+}
+
+// NewModifiedMassDrop returns a new modified Mass Drop Tagger with the
+// provided symmetry cut zcut and mass-drop parameter mu.
+// The mass-drop condition is disabled with mu = +Inf.
+//
+// Jets where no branching satisfies the conditions are rejected and
+// groomed into a jet with a null 4-momentum.
+func NewModifiedMassDrop(zcut, mu float64) *SoftDrop {
+	return &SoftDrop{
+		beta:    0,
+		zcut:    zcut,
+		r0:      1,
+		mu:      mu,
+		tagging: true,
+	}
+}
+
+// Groom returns the groomed jet.
+func (sd *SoftDrop) Groom(jet *fastjet.Jet) (fastjet.Jet, error) {
+	res, err := sd.Decluster(jet)
+	if err != nil {
+		return fastjet.Jet{}, err
+	}
+	return res.Jet, nil
+}
+
+// Decluster returns the groomed jet together with the kinematics of its
+// first two branches.
+func (sd *SoftDrop) Decluster(jet *fastjet.Jet) (SoftDropResult, error) {
+	var res SoftDropResult
+
+	consts := jet.Constituents()
+	if len(consts) == 0 {
+		res.Jet = fastjet.NewJet(0, 0, 0, 0)
+		return res, nil
+	}
+
+	cs, j, err := reclusterOne(consts)
+	if err != nil {
+		return res, fmt.Errorf("substructure: could not run soft-drop: %w", err)
+	}
+
+	var (
+		r02 = sd.r0 * sd.r0
+		mu2 = sd.mu * sd.mu
+	)
+	for {
+		p1, p2, ok := cs.Parents(&j)
+		if !ok {
+			break
+		}
+		var (
+			dr2  = fastjet.Distance(&p1, &p2)
+			pt   = p1.Pt() + p2.Pt()
+			z    = 0.0
+			drop = 0.0
+		)
+		if pt > 0 {
+			z = p2.Pt() / pt
+		}
+		if m2 := j.M2(); m2 > 0 {
+			drop = math.Max(p1.M2(), p2.M2()) / m2
+		}
+		if z > sd.zcut*math.Pow(dr2/r02, 0.5*sd.beta) && drop <= mu2 {
+			res.Jet = j
+			res.Zg = z
+			res.Rg = math.Sqrt(dr2)
+			res.Mu = math.Sqrt(drop)
+			return res, nil
+		}
+		res.Dropped++
+		j = p1
+	}
+
+	if sd.tagging {
+		res.Jet = fastjet.NewJet(0, 0, 0, 0)
+		return res, nil
+	}
+	res.Jet = j
+	return res, nil
+}
+
+// Description returns a string description of the groomer.
+func (sd *SoftDrop) Description() string {
+	if sd.tagging {
+		return fmt.Sprintf("modified Mass Drop Tagger with zcut=%v, mu=%v", sd.zcut, sd.mu)
+	}
+	return fmt.Sprintf("SoftDrop groomer with beta=%v, zcut=%v, R0=%v", sd.beta, sd.zcut, sd.r0)
+}
@@ -0,0 +1,103 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package substructure_test
+
+import (
+	"math"
+	"testing"
+
+	"go-hep.org/x/hep/fastjet"
+	"go-hep.org/x/hep/fastjet/internal/fjtest"
+	"go-hep.org/x/hep/fastjet/substructure"
+)
+
+func TestSoftDrop(t *testing.T) {
+	jet := fastjet.Join(
+		fjtest.NewJetPtYPhi(100, 0, 0),
+		fjtest.NewJetPtYPhi(50, 0.5, 0),
+		fjtest.NewJetPtYPhi(1, 0, 0.8),
+	)
+
+	for _, tc := range []struct {
+		name    string
+		sd      *substructure.SoftDrop
+		pt      float64
+		zg      float64
+		rg      float64
+		dropped int
+	}{
+		{
+			name:    "beta=0",
+			sd:      substructure.NewSoftDrop(0, 0.1, 1),
+			pt:      150,
+			zg:      1.0 / 3,
+			rg:      0.5,
+			dropped: 1,
+		},
+		{
+			name:    "beta=1",
+			sd:      substructure.NewSoftDrop(1, 0.1, 1),
+			pt:      150,
+			zg:      1.0 / 3,
+			rg:      0.5,
+			dropped: 1,
+		},
+		{
+			name:    "zcut=0",
+			sd:      substructure.NewSoftDrop(0, 0, 1),
+			pt:      jet.Pt(),
+			zg:      1.0 / 151,
+			rg:      0.817,
+			dropped: 0,
+		},
+		{
+			name:    "zcut=0.4",
+			sd:      substructure.NewSoftDrop(0, 0.4, 1),
+			pt:      100,
+			dropped: 2,
+		},
+		{
+			name:    "mmdt",
+			sd:      substructure.NewModifiedMassDrop(0.1, math.Inf(+1)),
+			pt:      150,
+			zg:      1.0 / 3,
+			rg:      0.5,
+			dropped: 1,
+		},
+		{
+			name:    "mmdt-zcut=0.4",
+			sd:      substructure.NewModifiedMassDrop(0.4, math.Inf(+1)),
+			pt:      0,
+			dropped: 2,
+		},
+		{
+			name:    "mmdt-zcut=0-mu=0.5",
+			sd:      substructure.NewModifiedMassDrop(0, 0.5),
+			pt:      150,
+			zg:      1.0 / 3,
+			rg:      0.5,
+			dropped: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.sd.Decluster(&jet)
+			if err != nil {
+				t.Fatalf("could not run soft-drop: %+v", err)
+			}
+			if got, want := res.Jet.Pt(), tc.pt; math.Abs(got-want) > 1e-3 {
+				t.Fatalf("invalid groomed pt: got=%v, want=%v", got, want)
+			}
+			if got, want := res.Zg, tc.zg; math.Abs(got-want) > 1e-3 {
+				t.Fatalf("invalid zg: got=%v, want=%v", got, want)
+			}
+			if got, want := res.Rg, tc.rg; math.Abs(got-want) > 1e-2 {
+				t.Fatalf("invalid Rg: got=%v, want=%v", got, want)
+			}
+			if got, want := res.Dropped, tc.dropped; got != want {
+				t.Fatalf("invalid number of dropped branches: got=%d, want=%d", got, want)
+			}
+		})
+	}
+}
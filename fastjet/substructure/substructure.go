@@ -0,0 +1,62 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package substructure provides tools to study the internal structure of
+// jets, similar to the ones from FastJet and FastJet-contrib.
+//
+// Groomers remove the soft and wide-angle radiation from jets:
+//   - SoftDrop and the modified Mass Drop Tagger,
+//   - Filter and Trimmer,
+//   - Pruner.
+//
+// Observables characterize the radiation pattern of jets:
+//   - N-subjettiness,
+//   - energy correlation functions.
+package substructure // import "go-hep.org/x/hep/fastjet/substructure"
+
+import (
+	"fmt"
+
+	"go-hep.org/x/hep/fastjet"
+)
+
+// maxR is the radius used to recluster all the constituents of a jet into
+// a single jet.
+const maxR = 1000
+
+// Groomer removes soft and wide-angle radiation from jets.
+type Groomer interface {
+	// Groom returns the groomed jet.
+	Groom(jet *fastjet.Jet) (fastjet.Jet, error)
+}
+
+var (
+	_ Groomer = (*SoftDrop)(nil)
+	_ Groomer = (*Filter)(nil)
+	_ Groomer = (*Pruner)(nil)
+)
+
+// recluster clusters the provided particles with the jet definition.
+func recluster(particles []fastjet.Jet, def fastjet.JetDefinition) (*fastjet.ClusterSequence, error) {
+	cs, err := fastjet.NewClusterSequence(particles, def)
+	if err != nil {
+		return nil, fmt.Errorf("substructure: could not recluster jet: %w", err)
+	}
+	return cs, nil
+}
+
+// reclusterOne clusters the provided particles into a single jet, with
+// the Cambridge/Aachen algorithm.
+func reclusterOne(particles []fastjet.Jet) (*fastjet.ClusterSequence, fastjet.Jet, error) {
+	def := fastjet.NewJetDefinition(fastjet.CambridgeAlgorithm, maxR, fastjet.EScheme, fastjet.BestStrategy)
+	cs, err := recluster(particles, def)
+	if err != nil {
+		return nil, fastjet.Jet{}, err
+	}
+	jets, err := cs.ExclusiveJetsUpTo(1)
+	if err != nil {
+		return nil, fastjet.Jet{}, fmt.Errorf("substructure: could not recluster jet: %w", err)
+	}
+	return cs, jets[0], nil
+}
@@ -0,0 +1,116 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package substructure_test
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"go-hep.org/x/hep/fastjet"
+	"go-hep.org/x/hep/fastjet/internal/fjtest"
+	"go-hep.org/x/hep/fastjet/substructure"
+)
+
+func TestGroomers(t *testing.T) {
+	jet := hardestJet(t)
+	var (
+		ca   = fastjet.NewJetDefinition(fastjet.CambridgeAlgorithm, 1.0, fastjet.EScheme, fastjet.BestStrategy)
+		kt   = fastjet.NewJetDefinition(fastjet.KtAlgorithm, 0.2, fastjet.EScheme, fastjet.BestStrategy)
+		ca03 = fastjet.NewJetDefinition(fastjet.CambridgeAlgorithm, 0.3, fastjet.EScheme, fastjet.BestStrategy)
+	)
+	for _, tc := range []struct {
+		name  string
+		groom substructure.Groomer
+	}{
+		{"softdrop", substructure.NewSoftDrop(0, 0.1, 1)},
+		{"softdrop-beta2", substructure.NewSoftDrop(2, 0.1, 1)},
+		{"mmdt", substructure.NewModifiedMassDrop(0.1, math.Inf(+1))},
+		{"filter", substructure.NewFilter(ca03, 3)},
+		{"trimmer", substructure.NewTrimmer(kt, 0.03)},
+		{"pruner", substructure.NewPruner(ca, 0.1, 0.5)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			groomed, err := tc.groom.Groom(&jet)
+			if err != nil {
+				t.Fatalf("could not groom jet: %+v", err)
+			}
+			if groomed.Pt() <= 0 {
+				t.Fatalf("invalid groomed jet pt: %v", groomed.Pt())
+			}
+			if groomed.Pt() > jet.Pt()*(1+1e-9) {
+				t.Fatalf("groomed jet pt larger than original one: got=%v, orig=%v", groomed.Pt(), jet.Pt())
+			}
+			if groomed.M() > jet.M()*(1+1e-9) {
+				t.Fatalf("groomed jet mass larger than original one: got=%v, orig=%v", groomed.M(), jet.M())
+			}
+
+			consts := groomed.Constituents()
+			if n, orig := len(consts), len(jet.Constituents()); n == 0 || n > orig {
+				t.Fatalf("invalid number of constituents: got=%d, orig=%d", n, orig)
+			}
+			var e float64
+			for i := range consts {
+				e += consts[i].E()
+			}
+			if got, want := e, groomed.E(); math.Abs(got-want) > 1e-6*want {
+				t.Fatalf("invalid constituents energy: got=%v, want=%v", got, want)
+			}
+		})
+	}
+}
+
+func TestObservables(t *testing.T) {
+	jet := hardestJet(t)
+
+	var taus [4]float64
+	for i := range taus {
+		var err error
+		taus[i], err = substructure.NewNsubjettiness(i+1, substructure.WTAKtAxes, 1, 1).Tau(&jet)
+		if err != nil {
+			t.Fatalf("could not compute tau%d: %+v", i+1, err)
+		}
+	}
+	for i := 1; i < len(taus); i++ {
+		if !(0 < taus[i] && taus[i] < taus[i-1]) {
+			t.Fatalf("invalid N-subjettiness values: %v", taus)
+		}
+	}
+
+	var (
+		e1 = substructure.NewEnergyCorrelator(1, 1).Value(&jet)
+		e2 = substructure.NewEnergyCorrelator(2, 1).Value(&jet)
+		e3 = substructure.NewEnergyCorrelator(3, 1).Value(&jet)
+		c2 = substructure.NewEnergyCorrelatorC(2, 1).Value(&jet)
+		d2 = substructure.NewEnergyCorrelatorD2(1).Value(&jet)
+	)
+	if got, want := c2, e3*e1/(e2*e2); math.Abs(got-want) > 1e-9*want {
+		t.Fatalf("invalid C2: got=%v, want=%v", got, want)
+	}
+	if got, want := d2, e3*e1*e1*e1/(e2*e2*e2); math.Abs(got-want) > 1e-9*want {
+		t.Fatalf("invalid D2: got=%v, want=%v", got, want)
+	}
+}
+
+// hardestJet returns the hardest anti-kt R=1.0 jet of the test event.
+func hardestJet(t *testing.T) fastjet.Jet {
+	t.Helper()
+
+	particles, err := fjtest.LoadParticles("../testdata/single-pp-event.dat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	def := fastjet.NewJetDefinition(fastjet.AntiKtAlgorithm, 1.0, fastjet.EScheme, fastjet.BestStrategy)
+	cs, err := fastjet.NewClusterSequence(particles, def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jets, err := cs.InclusiveJets(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Sort(fastjet.ByPt(jets))
+	return jets[0]
+}
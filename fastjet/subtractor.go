@@ -0,0 +1,84 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fastjet
+
+import (
+	"fmt"
+)
+
+// Subtractor subtracts the background contribution from jets, using their
+// 4-vector area:
+//
+//	jet_sub = jet - rho * area4
+//
+// If the transverse momentum to subtract is larger than the one of the
+// jet, the subtracted jet has a null 4-momentum.
+type Subtractor struct {
+	bkg BackgroundEstimator
+	rho float64
+}
+
+// NewSubtractor returns a new subtractor, using the background density
+// from the provided estimator.
+func NewSubtractor(bkg BackgroundEstimator) *Subtractor {
+	return &Subtractor{bkg: bkg}
+}
+
+// NewSubtractorRho returns a new subtractor, using a fixed background
+// density.
+func NewSubtractorRho(rho float64) *Subtractor {
+	return &Subtractor{rho: rho}
+}
+
+// Rho returns the background density used for the subtraction.
+func (sub *Subtractor) Rho() float64 {
+	if sub.bkg != nil {
+		return sub.bkg.Rho()
+	}
+	return sub.rho
+}
+
+// Subtract returns the background-subtracted jet.
+// The subtracted jet keeps the clustering information of the original jet.
+func (sub *Subtractor) Subtract(jet *Jet) (Jet, error) {
+	if !jet.HasArea() {
+		return Jet{}, fmt.Errorf("fastjet: subtractor needs jets with area information")
+	}
+
+	var (
+		area4 = jet.Area4()
+		rho   = sub.Rho()
+		bkg   = scaleP4(rho, area4)
+		o     Jet
+	)
+	switch {
+	case bkg.Pt() < jet.Pt():
+		o = NewJet(
+			jet.Px()-bkg.Px(),
+			jet.Py()-bkg.Py(),
+			jet.Pz()-bkg.Pz(),
+			jet.E()-bkg.E(),
+		)
+	default:
+		o = NewJet(0, 0, 0, 0)
+	}
+	o.UserInfo = jet.UserInfo
+	o.hidx = jet.hidx
+	o.structure = jet.structure
+	return o, nil
+}
+
+// SubtractAll returns the background-subtracted jets.
+func (sub *Subtractor) SubtractAll(jets []Jet) ([]Jet, error) {
+	o := make([]Jet, len(jets))
+	for i := range jets {
+		var err error
+		o[i], err = sub.Subtract(&jets[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
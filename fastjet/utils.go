@@ -32,3 +32,33 @@ func (p ByPt) Less(i, j int) bool {
 func (p ByPt) Swap(i, j int) {
 	p[i], p[j] = p[j], p[i]
 }
+
+// ByE sorts jets by descending energy
+type ByE []Jet
+
+func (p ByE) Len() int {
+	return len(p)
+}
+
+func (p ByE) Less(i, j int) bool {
+	return p[j].E() < p[i].E()
+}
+
+func (p ByE) Swap(i, j int) {
+	p[i], p[j] = p[j], p[i]
+}
+
+// ByRapidity sorts jets by ascending rapidity
+type ByRapidity []Jet
+
+func (p ByRapidity) Len() int {
+	return len(p)
+}
+
+func (p ByRapidity) Less(i, j int) bool {
+	return p[i].Rapidity() < p[j].Rapidity()
+}
+
+func (p ByRapidity) Swap(i, j int) {
+	p[i], p[j] = p[j], p[i]
+}
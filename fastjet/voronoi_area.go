@@ -0,0 +1,148 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fastjet
+
+import (
+	"math"
+)
+
+// vpoint is a point in the rapidity-azimuth plane.
+type vpoint struct {
+	x, y float64
+}
+
+func (p vpoint) sub(o vpoint) vpoint    { return vpoint{p.x - o.x, p.y - o.y} }
+func (p vpoint) dot(o vpoint) float64   { return p.x*o.x + p.y*o.y }
+func (p vpoint) cross(o vpoint) float64 { return p.x*o.y - p.y*o.x }
+func (p vpoint) lerp(o vpoint, t float64) vpoint {
+	return vpoint{p.x + t*(o.x-p.x), p.y + t*(o.y-p.y)}
+}
+
+// voronoiAreas returns the areas of the Voronoi cells of the provided
+// particles in the rapidity-azimuth plane, each cell being intersected
+// with a circle of radius r centered on its particle.
+//
+// The azimuthal periodicity is taken into account.
+func voronoiAreas(jets []Jet, r float64) []float64 {
+	var (
+		areas = make([]float64, len(jets))
+		pts   = make([]vpoint, len(jets))
+		r2    = r * r
+		shift = []float64{0, -2 * math.Pi, +2 * math.Pi}
+	)
+	for i := range jets {
+		pts[i] = vpoint{jets[i].Rapidity(), jets[i].Phi()}
+	}
+
+	cell := make([]vpoint, 0, 16)
+	tmp := make([]vpoint, 0, 16)
+loop:
+	for i, pi := range pts {
+		// start from a square enclosing the circle, in coordinates
+		// relative to the particle.
+		cell = append(cell[:0], vpoint{-2 * r, -2 * r}, vpoint{+2 * r, -2 * r}, vpoint{+2 * r, +2 * r}, vpoint{-2 * r, +2 * r})
+		for j, pj := range pts {
+			for k, dphi := range shift {
+				if i == j && k == 0 {
+					continue
+				}
+				d := vpoint{pj.x - pi.x, pj.y + dphi - pi.y}
+				d2 := d.dot(d)
+				if d2 >= 4*r2 {
+					// the bisector does not cross the circle.
+					continue
+				}
+				if d2 == 0 {
+					// coincident particles: the first one gets the cell.
+					if j < i {
+						continue loop
+					}
+					continue
+				}
+				// keep the half-plane closer to the particle.
+				cell, tmp = clipHalfPlane(cell, tmp[:0], d, 0.5*d2), cell
+				if len(cell) == 0 {
+					continue loop
+				}
+			}
+		}
+		areas[i] = polygonDiscArea(cell, r)
+	}
+	return areas
+}
+
+// clipHalfPlane clips the convex polygon with the half-plane {p: p.n <= c},
+// storing the result in dst.
+func clipHalfPlane(poly, dst []vpoint, n vpoint, c float64) []vpoint {
+	for i, cur := range poly {
+		prev := poly[(i+len(poly)-1)%len(poly)]
+		var (
+			din  = cur.dot(n) - c
+			dout = prev.dot(n) - c
+		)
+		switch {
+		case din <= 0:
+			if dout > 0 {
+				dst = append(dst, prev.lerp(cur, dout/(dout-din)))
+			}
+			dst = append(dst, cur)
+		case dout <= 0:
+			dst = append(dst, prev.lerp(cur, dout/(dout-din)))
+		}
+	}
+	return dst
+}
+
+// polygonDiscArea returns the area of the intersection of the polygon with
+// the disc of radius r centered on the origin.
+func polygonDiscArea(poly []vpoint, r float64) float64 {
+	area := 0.0
+	for i, a := range poly {
+		b := poly[(i+1)%len(poly)]
+		area += triangleDiscArea(a, b, r)
+	}
+	return math.Abs(area)
+}
+
+// triangleDiscArea returns the signed area of the intersection of the
+// triangle (origin, a, b) with the disc of radius r centered on the origin.
+func triangleDiscArea(a, b vpoint, r float64) float64 {
+	// find the intersections of the segment [a,b] with the circle.
+	var (
+		d  = b.sub(a)
+		qa = d.dot(d)
+		qb = 2 * a.dot(d)
+		qc = a.dot(a) - r*r
+		ts = [4]float64{0, 0, 0, 1}
+		n  = 1
+	)
+	if disc := qb*qb - 4*qa*qc; qa > 0 && disc > 0 {
+		sq := math.Sqrt(disc)
+		for _, t := range []float64{(-qb - sq) / (2 * qa), (-qb + sq) / (2 * qa)} {
+			if 0 < t && t < 1 {
+				ts[n] = t
+				n++
+			}
+		}
+	}
+	ts[n] = 1
+	n++
+
+	area := 0.0
+	for i := 0; i+1 < n; i++ {
+		var (
+			p = a.lerp(b, ts[i])
+			q = a.lerp(b, ts[i+1])
+			m = a.lerp(b, 0.5*(ts[i]+ts[i+1]))
+		)
+		switch {
+		case m.dot(m) < r*r:
+			area += 0.5 * p.cross(q)
+		default:
+			area += 0.5 * r * r * math.Atan2(p.cross(q), p.dot(q))
+		}
+	}
+	return area
+}
@@ -10,20 +10,14 @@ import (
 
 // Curve1D returns the result of a non-linear least squares to fit
 // a function f to the underlying data with method m.
-func Curve1D(f Func1D, settings *optimize.Settings, m optimize.Method) (*optimize.Result, error) {
+func Curve1D(f Func1D, settings *optimize.Settings, m optimize.Method) (*Result, error) {
 	f.init()
 
-	p := optimize.Problem{
-		Func: f.fct,
-		Grad: f.grad,
-		Hess: f.hess,
-	}
-
-	if m == nil {
-		m = &optimize.NelderMead{}
-	}
-
-	p0 := make([]float64, len(f.Ps))
-	copy(p0, f.Ps)
-	return optimize.Minimize(p, p0, settings, m)
+	return minimize(problem{
+		fct:    f.fct,
+		ps:     f.Ps,
+		fixed:  f.Fixed,
+		bounds: f.Bounds,
+		ndata:  len(f.Y),
+	}, settings, m)
 }
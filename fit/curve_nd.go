@@ -11,20 +11,14 @@ import (
 // CurveND returns the result of a non-linear least squares to fit
 // a function f to the underlying data with method m, where there
 // is more than one independent variable.
-func CurveND(f FuncND, settings *optimize.Settings, m optimize.Method) (*optimize.Result, error) {
+func CurveND(f FuncND, settings *optimize.Settings, m optimize.Method) (*Result, error) {
 	f.init()
 
-	p := optimize.Problem{
-		Func: f.fct,
-		Grad: f.grad,
-		Hess: f.hess,
-	}
-
-	if m == nil {
-		m = &optimize.NelderMead{}
-	}
-
-	p0 := make([]float64, len(f.Ps))
-	copy(p0, f.Ps)
-	return optimize.Minimize(p, p0, settings, m)
+	return minimize(problem{
+		fct:    f.fct,
+		ps:     f.Ps,
+		fixed:  f.Fixed,
+		bounds: f.Bounds,
+		ndata:  len(f.Y),
+	}, settings, m)
 }
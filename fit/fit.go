@@ -5,11 +5,6 @@
 // Package fit provides functions to fit data.
 package fit // import "go-hep.org/x/hep/fit"
 
-import (
-	"gonum.org/v1/gonum/diff/fd"
-	"gonum.org/v1/gonum/mat"
-)
-
 //go:generate go get github.com/campoy/embedmd
 //go:generate embedmd -w README.md
 
@@ -28,15 +23,21 @@ type Func1D struct {
 	// length N filled with zeros.
 	Ps []float64
 
+	// Fixed holds the indices of the parameters kept constant, at their
+	// initial values, during the fit.
+	Fixed []int
+
+	// Bounds holds the limits of the parameters, indexed by parameter.
+	// Parameters without limits need not be listed.
+	Bounds map[int]Bound
+
 	X   []float64
 	Y   []float64
 	Err []float64
 
 	sig2 []float64 // inverse of squares of measurement errors along Y.
 
-	fct  func(ps []float64) float64 // cost function (objective function)
-	grad func(grad, ps []float64)
-	hess func(hess *mat.SymDense, x []float64)
+	fct func(ps []float64) float64 // cost function (objective function)
 }
 
 func (f *Func1D) init() {
@@ -53,13 +54,7 @@ func (f *Func1D) init() {
 		}
 	}
 
-	if f.Ps == nil {
-		f.Ps = make([]float64, f.N)
-	}
-
-	if len(f.Ps) == 0 {
-		panic("fit: invalid number of initial parameters")
-	}
+	f.Ps = initParams(f.Ps, f.N)
 
 	if len(f.X) != len(f.Y) {
 		panic("fit: mismatch length")
@@ -77,14 +72,6 @@ func (f *Func1D) init() {
 		}
 		return 0.5 * chi2
 	}
-
-	f.grad = func(grad, ps []float64) {
-		fd.Gradient(grad, f.fct, ps, nil)
-	}
-
-	f.hess = func(hess *mat.SymDense, x []float64) {
-		fd.Hessian(hess, f.fct, x, nil)
-	}
 }
 
 // FuncND describes a multivariate function F(x0, x1... xn; p0, p1... pn)
@@ -104,6 +91,14 @@ type FuncND struct {
 	// length N filled with zeros.
 	Ps []float64
 
+	// Fixed holds the indices of the parameters kept constant, at their
+	// initial values, during the fit.
+	Fixed []int
+
+	// Bounds holds the limits of the parameters, indexed by parameter.
+	// Parameters without limits need not be listed.
+	Bounds map[int]Bound
+
 	// X is the multidimensional slice of the independent variables,
 	// it must be structured so that the X[i] is a list of values for the
 	// independent variables that corresponds to a single Y value.
@@ -115,9 +110,7 @@ type FuncND struct {
 
 	sig2 []float64 // inverse of squares of measurement errors along Y.
 
-	fct  func(ps []float64) float64 // cost function (objective function)
-	grad func(grad, ps []float64)
-	hess func(hess *mat.SymDense, x []float64) // hessian matrix
+	fct func(ps []float64) float64 // cost function (objective function)
 }
 
 func (f *FuncND) init() {
@@ -134,13 +127,7 @@ func (f *FuncND) init() {
 		}
 	}
 
-	if f.Ps == nil {
-		f.Ps = make([]float64, f.N)
-	}
-
-	if len(f.Ps) == 0 {
-		panic("fit: invalid number of initial parameters")
-	}
+	f.Ps = initParams(f.Ps, f.N)
 
 	if len(f.X) != len(f.Y) {
 		panic("fit: mismatch length")
@@ -158,12 +145,17 @@ func (f *FuncND) init() {
 		}
 		return 0.5 * chi2
 	}
+}
 
-	f.grad = func(grad []float64, ps []float64) {
-		fd.Gradient(grad, f.fct, ps, nil)
+// initParams returns the initial values of the parameters to fit.
+func initParams(ps []float64, n int) []float64 {
+	if ps == nil {
+		ps = make([]float64, n)
 	}
 
-	f.hess = func(hess *mat.SymDense, x []float64) {
-		fd.Hessian(hess, f.fct, x, nil)
+	if len(ps) == 0 {
+		panic("fit: invalid number of initial parameters")
 	}
+
+	return ps
 }
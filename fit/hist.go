@@ -5,36 +5,215 @@
 package fit
 
 import (
+	"math"
+
 	"go-hep.org/x/hep/hbook"
 	"gonum.org/v1/gonum/optimize"
 )
 
 // H1D returns the fit of histogram h with function f and optimization method m.
 //
-// Only bins with at least an entry are considered for the fit.
+// f.F(x, ps) is the expected content of the bin centred on x.
+// By default, the fit minimizes the least squares, and only bins with at
+// least an entry are considered for the fit.
+// The cost function can be selected with the HistOptions (LeastSquares,
+// Likelihood or WeightedLikelihood.)
+//
 // In case settings is nil, the optimize.DefaultSettingsLocal is used.
 // In case m is nil, the same default optimization method than for Curve1D is used.
-func H1D(h *hbook.H1D, f Func1D, settings *optimize.Settings, m optimize.Method) (*optimize.Result, error) {
+func H1D(h *hbook.H1D, f Func1D, settings *optimize.Settings, m optimize.Method, opts ...HistOptions) (*Result, error) {
+	cfg := newHistConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
 	var (
-		n     = h.Len()
-		xdata = make([]float64, 0, n)
-		ydata = make([]float64, 0, n)
-		yerrs = make([]float64, 0, n)
-		bins  = h.Binning.Bins
+		n    = h.Len()
+		bins = h.Binning.Bins
 	)
 
-	for _, bin := range bins {
-		if bin.Entries() <= 0 {
-			continue
+	switch cfg.cost {
+	case leastSquaresCost:
+		var (
+			xdata = make([]float64, 0, n)
+			ydata = make([]float64, 0, n)
+			yerrs = make([]float64, 0, n)
+		)
+		for _, bin := range bins {
+			if bin.Entries() <= 0 {
+				continue
+			}
+			xdata = append(xdata, bin.XMid())
+			ydata = append(ydata, bin.SumW())
+			yerrs = append(yerrs, bin.ErrW())
+		}
+
+		f.X = xdata
+		f.Y = ydata
+		f.Err = yerrs
+
+		return Curve1D(f, settings, m)
+
+	default:
+		var (
+			xs   = make([]float64, n)
+			ll   = newBinnedLL(n)
+			ps   = initParams(f.Ps, f.N)
+			xfct = f.F
+		)
+		for i, bin := range bins {
+			xs[i] = bin.XMid()
+			ll.sumw[i] = bin.SumW()
+			ll.sumw2[i] = bin.SumW2()
+		}
+		ll.init(cfg.cost)
+
+		fct := func(ps []float64) float64 {
+			var nll float64
+			for i, x := range xs {
+				nll += ll.nll(i, xfct(x, ps))
+			}
+			return nll
 		}
-		xdata = append(xdata, bin.XMid())
-		ydata = append(ydata, bin.SumW())
-		yerrs = append(yerrs, bin.ErrW())
+		return minimize(problem{
+			fct:    fct,
+			ps:     ps,
+			fixed:  f.Fixed,
+			bounds: f.Bounds,
+			ndata:  n,
+		}, settings, m)
 	}
+}
 
-	f.X = xdata
-	f.Y = ydata
-	f.Err = yerrs
+// H2D returns the fit of histogram h with function f and optimization method m.
+//
+// f.F(x, ps) is the expected content of the bin centred on (x[0], x[1]).
+// By default, the fit minimizes the least squares, and only bins with at
+// least an entry are considered for the fit.
+// The cost function can be selected with the HistOptions (LeastSquares,
+// Likelihood or WeightedLikelihood.)
+//
+// In case settings is nil, the optimize.DefaultSettingsLocal is used.
+// In case m is nil, the same default optimization method than for CurveND is used.
+func H2D(h *hbook.H2D, f FuncND, settings *optimize.Settings, m optimize.Method, opts ...HistOptions) (*Result, error) {
+	cfg := newHistConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var (
+		n    = len(h.Binning.Bins)
+		bins = h.Binning.Bins
+	)
 
-	return Curve1D(f, settings, m)
+	switch cfg.cost {
+	case leastSquaresCost:
+		var (
+			xdata = make([][]float64, 0, n)
+			ydata = make([]float64, 0, n)
+			yerrs = make([]float64, 0, n)
+		)
+		for _, bin := range bins {
+			if bin.Entries() <= 0 {
+				continue
+			}
+			xdata = append(xdata, []float64{bin.XMid(), bin.YMid()})
+			ydata = append(ydata, bin.SumW())
+			yerrs = append(yerrs, math.Sqrt(bin.SumW2()))
+		}
+
+		f.X = xdata
+		f.Y = ydata
+		f.Err = yerrs
+
+		return CurveND(f, settings, m)
+
+	default:
+		var (
+			xs   = make([][]float64, n)
+			ll   = newBinnedLL(n)
+			ps   = initParams(f.Ps, f.N)
+			xfct = f.F
+		)
+		for i, bin := range bins {
+			xs[i] = []float64{bin.XMid(), bin.YMid()}
+			ll.sumw[i] = bin.SumW()
+			ll.sumw2[i] = bin.SumW2()
+		}
+		ll.init(cfg.cost)
+
+		fct := func(ps []float64) float64 {
+			var nll float64
+			for i, x := range xs {
+				nll += ll.nll(i, xfct(x, ps))
+			}
+			return nll
+		}
+		return minimize(problem{
+			fct:    fct,
+			ps:     ps,
+			fixed:  f.Fixed,
+			bounds: f.Bounds,
+			ndata:  n,
+		}, settings, m)
+	}
+}
+
+// HistOptions allows to customize the behaviour of H1D and H2D.
+type HistOptions func(c *histConfig)
+
+// histConfig type specifies the possible configurations
+// passed as HistOptions.
+type histConfig struct {
+	cost costKind
+}
+
+// newHistConfig function builds the default configuration
+// for the histogram fit functions.
+func newHistConfig() *histConfig {
+	return &histConfig{cost: leastSquaresCost}
+}
+
+type costKind int
+
+const (
+	leastSquaresCost costKind = iota
+	likelihoodCost
+	weightedLikelihoodCost
+)
+
+// LeastSquares configures H1D and H2D to minimize the least squares
+// between the bins contents and the function.
+// Empty bins are not considered for the fit.
+// This is the default.
+func LeastSquares() HistOptions {
+	return func(c *histConfig) {
+		c.cost = leastSquaresCost
+	}
+}
+
+// Likelihood configures H1D and H2D to maximize the Poisson binned
+// likelihood of the bins contents, given the function.
+// All the bins, including the empty ones, are considered for the fit.
+//
+// The likelihood is best suited for histograms with low numbers of
+// entries per bin, filled with unit weights.
+func Likelihood() HistOptions {
+	return func(c *histConfig) {
+		c.cost = likelihoodCost
+	}
+}
+
+// WeightedLikelihood configures H1D and H2D to maximize the Poisson binned
+// likelihood of the bins contents, corrected for weighted entries.
+// All the bins, including the empty ones, are considered for the fit.
+//
+// The contribution of each bin is scaled by sumw/sumw2, so the bins
+// contents are treated as their effective numbers of entries, as
+// TH1::Fit does with its "WL" option.
+// Empty bins are scaled by the overall sumw/sumw2 of the histogram.
+func WeightedLikelihood() HistOptions {
+	return func(c *histConfig) {
+		c.cost = weightedLikelihoodCost
+	}
 }
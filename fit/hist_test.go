@@ -5,9 +5,147 @@
 package fit_test
 
 import (
+	"math"
 	"testing"
+
+	"go-hep.org/x/hep/fit"
+	"go-hep.org/x/hep/hbook"
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize"
+	"gonum.org/v1/gonum/stat/distuv"
 )
 
 func TestH1D(t *testing.T) {
 	checkPlot(ExampleH1D_gaussian, t, "h1d-gauss-plot.png")
 }
+
+func TestH1DLikelihood(t *testing.T) {
+	const (
+		npoints = 200
+		mean    = 2.0
+		sigma   = 4.0
+	)
+
+	dist := distuv.Normal{
+		Mu:    mean,
+		Sigma: sigma,
+		Src:   rand.New(rand.NewSource(1234)),
+	}
+
+	newHist := func(w float64) *hbook.H1D {
+		dist.Src = rand.New(rand.NewSource(1234))
+		h := hbook.NewH1D(100, -20, +25)
+		for i := 0; i < npoints; i++ {
+			h.Fill(dist.Rand(), w)
+		}
+		return h
+	}
+
+	gauss := func(x float64, ps []float64) float64 {
+		v := (x - ps[1]) / ps[2]
+		return ps[0] * math.Exp(-0.5*v*v)
+	}
+
+	// expected number of entries, from the fitted function.
+	integral := func(h *hbook.H1D, ps []float64) float64 {
+		var sum float64
+		for _, bin := range h.Binning.Bins {
+			sum += gauss(bin.XMid(), ps)
+		}
+		return sum
+	}
+
+	fitH1D := func(h *hbook.H1D, opts ...fit.HistOptions) []float64 {
+		res, err := fit.H1D(
+			h,
+			fit.Func1D{F: gauss, Ps: []float64{10, 1, 2}},
+			nil, &optimize.NelderMead{},
+			opts...,
+		)
+		if err != nil {
+			t.Fatalf("could not fit histogram: %+v", err)
+		}
+		if err := res.Status.Err(); err != nil {
+			t.Fatalf("invalid fit status: %+v", err)
+		}
+		return res.X
+	}
+
+	h := newHist(1)
+
+	// the least squares fit of a low-statistics histogram is biased low.
+	lsq := fitH1D(h, fit.LeastSquares())
+	if got, want := integral(h, lsq), h.SumW(); got > 0.95*want {
+		t.Fatalf("least squares integral not biased: got=%v, want<%v", got, 0.95*want)
+	}
+
+	// the Poisson likelihood fit preserves the number of entries.
+	nll := fitH1D(h, fit.Likelihood())
+	if got, want := integral(h, nll), h.SumW(); math.Abs(got-want) > 1e-3*want {
+		t.Fatalf("invalid likelihood integral: got=%v, want=%v", got, want)
+	}
+	if got, want := nll[1:], []float64{mean, sigma}; !floats.EqualApprox(got, want, 0.5) {
+		t.Fatalf("invalid likelihood fit: got=%v, want=%v", got, want)
+	}
+
+	// weighted entries should yield the same shape, scaled.
+	hw := newHist(2)
+	wll := fitH1D(hw, fit.WeightedLikelihood())
+	if got, want := wll[0], 2*nll[0]; math.Abs(got-want) > 1e-2*want {
+		t.Fatalf("invalid weighted likelihood amplitude: got=%v, want=%v", got, want)
+	}
+	if got, want := wll[1:], nll[1:]; !floats.EqualApprox(got, want, 1e-2) {
+		t.Fatalf("invalid weighted likelihood fit: got=%v, want=%v", got, want)
+	}
+}
+
+func TestH2D(t *testing.T) {
+	const (
+		npoints = 500
+		mux     = 1.0
+		muy     = -1.0
+		sigma   = 2.0
+	)
+
+	src := rand.New(rand.NewSource(1234))
+	h := hbook.NewH2D(20, -10, +10, 20, -10, +10)
+	for i := 0; i < npoints; i++ {
+		x := src.NormFloat64()*sigma + mux
+		y := src.NormFloat64()*sigma + muy
+		h.Fill(x, y, 1)
+	}
+
+	gauss := func(x, ps []float64) float64 {
+		vx := (x[0] - ps[1]) / ps[3]
+		vy := (x[1] - ps[2]) / ps[3]
+		return ps[0] * math.Exp(-0.5*(vx*vx+vy*vy))
+	}
+
+	for _, tc := range []struct {
+		name string
+		opt  fit.HistOptions
+	}{
+		{"lsq", fit.LeastSquares()},
+		{"nll", fit.Likelihood()},
+		{"wnll", fit.WeightedLikelihood()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := fit.H2D(
+				h,
+				fit.FuncND{F: gauss, Ps: []float64{10, 0, 0, 1}},
+				nil, &optimize.NelderMead{},
+				tc.opt,
+			)
+			if err != nil {
+				t.Fatalf("could not fit histogram: %+v", err)
+			}
+			if err := res.Status.Err(); err != nil {
+				t.Fatalf("invalid fit status: %+v", err)
+			}
+			if got, want := res.X[1:], []float64{mux, muy, sigma}; !floats.EqualApprox(got, want, 0.3) {
+				t.Fatalf("invalid fit: got=%v, want=%v", got, want)
+			}
+		})
+	}
+}
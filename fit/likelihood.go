@@ -0,0 +1,140 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fit
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/integrate/quad"
+	"gonum.org/v1/gonum/optimize"
+)
+
+// tiny is the smallest expectation value considered when computing
+// logarithms of likelihoods.
+const tiny = 1e-300
+
+// binnedLL describes the Poisson binned negative log-likelihood
+// of a set of bins.
+type binnedLL struct {
+	sumw  []float64 // bins contents
+	sumw2 []float64 // bins sum of squared weights
+	scale []float64 // scale factors of the bins contributions
+}
+
+func newBinnedLL(n int) binnedLL {
+	return binnedLL{
+		sumw:  make([]float64, n),
+		sumw2: make([]float64, n),
+		scale: make([]float64, n),
+	}
+}
+
+func (ll *binnedLL) init(cost costKind) {
+	for i := range ll.scale {
+		ll.scale[i] = 1
+	}
+	if cost != weightedLikelihoodCost {
+		return
+	}
+
+	var sumw, sumw2 float64
+	for i := range ll.sumw {
+		sumw += ll.sumw[i]
+		sumw2 += ll.sumw2[i]
+	}
+	def := 1.0
+	if sumw > 0 && sumw2 > 0 {
+		def = sumw / sumw2
+	}
+	for i, w := range ll.sumw {
+		switch {
+		case w > 0 && ll.sumw2[i] > 0:
+			ll.scale[i] = w / ll.sumw2[i]
+		default:
+			ll.scale[i] = def
+		}
+	}
+}
+
+// nll returns the contribution of the i-th bin to the negative
+// log-likelihood, given the expected bin content mu.
+//
+// The likelihood-ratio form of Baker and Cousins is used, so that the
+// minimum of the negative log-likelihood is asymptotically half a chi2.
+// Bins with a non-positive content are considered empty.
+func (ll *binnedLL) nll(i int, mu float64) float64 {
+	mu = math.Max(mu, tiny)
+	n := ll.sumw[i]
+	if n <= 0 {
+		return ll.scale[i] * mu
+	}
+	return ll.scale[i] * (mu - n + n*math.Log(n/mu))
+}
+
+// Unbinned returns the extended unbinned maximum likelihood fit of the
+// samples xs with function f and optimization method m.
+//
+// f.F(x, ps) is the expected number of samples per unit of x: its
+// integral over [xmin, xmax] is the expected total number of samples.
+// f.X, f.Y and f.Err are ignored.
+//
+// ws are the optional weights of the samples.
+// When ws is not nil, the negative log-likelihood is scaled by
+// sum(ws)/sum(ws^2), so the fit errors reflect the effective number of
+// samples.
+//
+// In case settings is nil, the optimize.DefaultSettingsLocal is used.
+// In case m is nil, the same default optimization method than for Curve1D is used.
+func Unbinned(xs, ws []float64, xmin, xmax float64, f Func1D, settings *optimize.Settings, m optimize.Method) (*Result, error) {
+	if ws != nil && len(ws) != len(xs) {
+		panic("fit: mismatch length")
+	}
+	if !(xmin < xmax) {
+		panic("fit: invalid range")
+	}
+
+	var (
+		ps    = initParams(f.Ps, f.N)
+		xfct  = f.F
+		scale = 1.0
+	)
+
+	if ws != nil {
+		var sumw, sumw2 float64
+		for _, w := range ws {
+			sumw += w
+			sumw2 += w * w
+		}
+		if sumw2 > 0 {
+			scale = sumw / sumw2
+		}
+	}
+
+	const npts = 100 // number of points for the numerical integration.
+
+	fct := func(ps []float64) float64 {
+		nu := quad.Fixed(func(x float64) float64 {
+			return xfct(x, ps)
+		}, xmin, xmax, npts, nil, 0)
+
+		var nll float64
+		for i, x := range xs {
+			w := 1.0
+			if ws != nil {
+				w = ws[i]
+			}
+			nll -= w * math.Log(math.Max(xfct(x, ps), tiny))
+		}
+		return scale * (nu + nll)
+	}
+
+	return minimize(problem{
+		fct:    fct,
+		ps:     ps,
+		fixed:  f.Fixed,
+		bounds: f.Bounds,
+		ndata:  len(xs),
+	}, settings, m)
+}
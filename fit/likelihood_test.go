@@ -0,0 +1,84 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fit_test
+
+import (
+	"math"
+	"testing"
+
+	"go-hep.org/x/hep/fit"
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/optimize"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestUnbinned(t *testing.T) {
+	const (
+		n      = 500
+		lambda = 0.5
+		xmin   = 0.0
+		xmax   = 10.0
+	)
+
+	dist := distuv.Exponential{
+		Rate: lambda,
+		Src:  rand.New(rand.NewSource(1234)),
+	}
+
+	xs := make([]float64, 0, n)
+	for len(xs) < n {
+		x := dist.Rand()
+		if x > xmax {
+			continue
+		}
+		xs = append(xs, x)
+	}
+
+	// expo is the expected number of samples per unit of x,
+	// normalized to ps[0] samples over the [xmin, xmax] range.
+	expo := func(x float64, ps []float64) float64 {
+		lam := ps[1]
+		return ps[0] * lam * math.Exp(-lam*x) / (1 - math.Exp(-lam*xmax))
+	}
+
+	for _, tc := range []struct {
+		name  string
+		ws    []float64
+		scale float64
+	}{
+		{name: "unweighted", scale: 1},
+		{name: "weighted", ws: fill(n, 2), scale: 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := fit.Unbinned(
+				xs, tc.ws, xmin, xmax,
+				fit.Func1D{F: expo, Ps: []float64{100, 1}},
+				nil, &optimize.NelderMead{},
+			)
+			if err != nil {
+				t.Fatalf("could not fit samples: %+v", err)
+			}
+			if err := res.Status.Err(); err != nil {
+				t.Fatalf("invalid fit status: %+v", err)
+			}
+
+			// the extended likelihood fit preserves the (weighted) number of samples.
+			if got, want := res.X[0], tc.scale*n; math.Abs(got-want) > 1e-2*want {
+				t.Fatalf("invalid yield: got=%v, want=%v", got, want)
+			}
+			if got, want := res.X[1], lambda; math.Abs(got-want) > 0.05 {
+				t.Fatalf("invalid rate: got=%v, want=%v", got, want)
+			}
+		})
+	}
+}
+
+func fill(n int, v float64) []float64 {
+	o := make([]float64, n)
+	for i := range o {
+		o[i] = v
+	}
+	return o
+}
@@ -0,0 +1,450 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fit
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+// Bound describes the lower and upper limits of a parameter.
+// Use math.Inf to describe a parameter limited only on one side.
+type Bound struct {
+	Min float64
+	Max float64
+}
+
+// Result is the result of a fit.
+//
+// The embedded optimize.Result holds the best-fit values of all the
+// parameters (including the fixed ones) and the minimum of the cost
+// function.
+// Its Gradient and Hessian are only set for fits without fixed nor
+// bounded parameters.
+//
+// Uncertainties are derived assuming the cost function is half a chi2
+// (least squares fits) or a negative log-likelihood (likelihood fits):
+// one standard deviation corresponds to an increase of 0.5 of the cost.
+// For least squares fits, this assumes the provided errors are the
+// measurement uncertainties.
+type Result struct {
+	*optimize.Result
+
+	ndf int           // number of degrees of freedom
+	cov *mat.SymDense // covariance matrix of the parameters
+
+	fct      func(ps []float64) float64 // cost function
+	fixed    []int                      // indices of the fixed parameters
+	bounds   map[int]Bound              // limits of the parameters
+	settings *optimize.Settings         // settings of the optimizer
+	method   optimize.Method            // optimization method
+}
+
+// up is the increase of the cost function defining one standard deviation.
+const up = 0.5
+
+// Chi2 returns twice the minimum of the cost function.
+//
+// This is the chi2 for least squares fits, and -2ln(L) for likelihood
+// fits. For binned likelihood fits, the likelihood ratio of Baker and
+// Cousins is used, so Chi2 is asymptotically chi2-distributed.
+func (res *Result) Chi2() float64 {
+	return 2 * res.F
+}
+
+// NDF returns the number of degrees of freedom of the fit: the number
+// of data points minus the number of free parameters.
+func (res *Result) NDF() int {
+	return res.ndf
+}
+
+// Cov returns the covariance matrix of the parameters, estimated from the
+// Hessian of the cost function at its minimum.
+// Rows and columns of the fixed parameters are zero.
+// If the Hessian is not positive definite, the covariance matrix of the
+// free parameters is filled with NaNs.
+func (res *Result) Cov() *mat.SymDense {
+	return mat.NewSymDense(res.cov.SymmetricDim(), append([]float64(nil), res.cov.RawSymmetric().Data...))
+}
+
+// Errors returns the symmetric uncertainties on the parameters, as the
+// square roots of the diagonal of the covariance matrix.
+func (res *Result) Errors() []float64 {
+	o := make([]float64, res.cov.SymmetricDim())
+	for i := range o {
+		o[i] = math.Sqrt(res.cov.At(i, i))
+	}
+	return o
+}
+
+// Corr returns the correlation matrix of the parameters.
+// Rows and columns of the fixed parameters are zero.
+func (res *Result) Corr() *mat.SymDense {
+	var (
+		n    = res.cov.SymmetricDim()
+		errs = res.Errors()
+		corr = mat.NewSymDense(n, nil)
+	)
+	for i := 0; i < n; i++ {
+		if errs[i] == 0 {
+			continue
+		}
+		for j := i; j < n; j++ {
+			if errs[j] == 0 {
+				continue
+			}
+			corr.SetSym(i, j, res.cov.At(i, j)/(errs[i]*errs[j]))
+		}
+	}
+	return corr
+}
+
+// Interval returns the asymmetric confidence interval [lo, hi] of the
+// i-th parameter, from the profile of the cost function.
+//
+// The bounds of the interval are the values of the parameter for which the
+// cost function, minimized with respect to all the other free parameters,
+// increases by 0.5 from its minimum, as MINOS does.
+// The cost function is minimized with the optimization method and settings
+// of the fit.
+// The interval is clipped to the bounds of the parameter, if any.
+func (res *Result) Interval(i int) (lo, hi float64, err error) {
+	if i < 0 || i >= len(res.X) {
+		return 0, 0, fmt.Errorf("fit: invalid parameter index %d", i)
+	}
+	if res.isFixed(i) {
+		return res.X[i], res.X[i], nil
+	}
+
+	lo, err = res.bound(i, -1)
+	if err != nil {
+		return 0, 0, fmt.Errorf("fit: could not find lower bound of parameter %d: %w", i, err)
+	}
+	hi, err = res.bound(i, +1)
+	if err != nil {
+		return 0, 0, fmt.Errorf("fit: could not find upper bound of parameter %d: %w", i, err)
+	}
+	return lo, hi, nil
+}
+
+func (res *Result) isFixed(i int) bool {
+	for _, j := range res.fixed {
+		if i == j {
+			return true
+		}
+	}
+	return false
+}
+
+// bound returns the value of the i-th parameter, in the direction dir,
+// for which the profiled cost function increases by up.
+func (res *Result) bound(i int, dir float64) (float64, error) {
+	var (
+		x0     = res.X[i]
+		limit  = math.Inf(int(dir))
+		target = res.F + up
+		step   = math.Sqrt(res.cov.At(i, i))
+	)
+	if b, ok := res.bounds[i]; ok {
+		limit = b.Max
+		if dir < 0 {
+			limit = b.Min
+		}
+	}
+	if !(step > 0) || math.IsInf(step, 0) {
+		step = math.Max(0.1*math.Abs(x0), 1)
+	}
+
+	// bracket the crossing point.
+	const maxSteps = 30
+	var (
+		a = x0
+		b = x0
+	)
+	for k := 0; ; k++ {
+		if k == maxSteps {
+			return 0, fmt.Errorf("could not bracket the profile crossing")
+		}
+		b = x0 + dir*step
+		if dir*(b-limit) >= 0 {
+			if res.profile(i, limit) <= target {
+				return limit, nil
+			}
+			b = limit
+			break
+		}
+		if res.profile(i, b) > target {
+			break
+		}
+		a = b
+		step *= 2
+	}
+
+	// bisect the bracket.
+	const (
+		maxIter = 50
+		tol     = 1e-4
+	)
+	width := math.Abs(b - x0)
+	for k := 0; k < maxIter && math.Abs(b-a) > tol*width; k++ {
+		mid := 0.5 * (a + b)
+		if res.profile(i, mid) > target {
+			b = mid
+		} else {
+			a = mid
+		}
+	}
+	return 0.5 * (a + b), nil
+}
+
+// profile returns the minimum of the cost function with respect to all the
+// free parameters, but the i-th one which is set to v.
+func (res *Result) profile(i int, v float64) float64 {
+	ps := make([]float64, len(res.X))
+	copy(ps, res.X)
+	ps[i] = v
+
+	pars, err := newParams(ps, append([]int{i}, res.fixed...), res.bounds)
+	if err != nil {
+		return math.Inf(+1)
+	}
+	if len(pars.free) == 0 {
+		return res.fct(ps)
+	}
+
+	o, err := optimize.Minimize(
+		newProblem(pars.cost(res.fct)),
+		pars.internal(ps), res.settings, res.method,
+	)
+	if err != nil && o == nil {
+		return math.Inf(+1)
+	}
+	return o.F
+}
+
+// params handles the mapping between the internal parameters seen by the
+// optimizer (the free parameters, transformed to remove their bounds) and
+// the external parameters seen by the user function.
+type params struct {
+	ps     []float64     // values of the external parameters
+	free   []int         // indices of the free parameters
+	bounds map[int]Bound // limits of the parameters
+}
+
+func newParams(ps []float64, fixed []int, bounds map[int]Bound) (params, error) {
+	isFixed := make(map[int]bool, len(fixed))
+	for _, i := range fixed {
+		if i < 0 || i >= len(ps) {
+			return params{}, fmt.Errorf("fit: invalid fixed parameter index %d", i)
+		}
+		isFixed[i] = true
+	}
+	for i, b := range bounds {
+		if i < 0 || i >= len(ps) {
+			return params{}, fmt.Errorf("fit: invalid bounded parameter index %d", i)
+		}
+		if !(b.Min < b.Max) {
+			return params{}, fmt.Errorf("fit: invalid bounds [%v, %v] for parameter %d", b.Min, b.Max, i)
+		}
+	}
+
+	free := make([]int, 0, len(ps))
+	for i := range ps {
+		if isFixed[i] {
+			continue
+		}
+		free = append(free, i)
+	}
+
+	return params{
+		ps:     append([]float64(nil), ps...),
+		free:   free,
+		bounds: bounds,
+	}, nil
+}
+
+// identity returns whether the internal and external parameters are the same.
+func (p params) identity() bool {
+	return len(p.free) == len(p.ps) && len(p.bounds) == 0
+}
+
+// external returns the external parameters from the internal ones.
+func (p params) external(dst, xs []float64) []float64 {
+	copy(dst, p.ps)
+	for k, i := range p.free {
+		x := xs[k]
+		b, ok := p.bounds[i]
+		switch {
+		case !ok:
+			dst[i] = x
+		case !math.IsInf(b.Min, 0) && !math.IsInf(b.Max, 0):
+			dst[i] = b.Min + 0.5*(b.Max-b.Min)*(math.Sin(x)+1)
+		case !math.IsInf(b.Min, 0):
+			dst[i] = b.Min - 1 + math.Sqrt(x*x+1)
+		case !math.IsInf(b.Max, 0):
+			dst[i] = b.Max + 1 - math.Sqrt(x*x+1)
+		default:
+			dst[i] = x
+		}
+	}
+	return dst
+}
+
+// internal returns the internal parameters from the external ones.
+// External values outside of their bounds are clipped.
+func (p params) internal(ps []float64) []float64 {
+	xs := make([]float64, len(p.free))
+	for k, i := range p.free {
+		v := ps[i]
+		b, ok := p.bounds[i]
+		switch {
+		case !ok:
+			xs[k] = v
+		case !math.IsInf(b.Min, 0) && !math.IsInf(b.Max, 0):
+			r := 2*(v-b.Min)/(b.Max-b.Min) - 1
+			xs[k] = math.Asin(math.Max(-1, math.Min(1, r)))
+		case !math.IsInf(b.Min, 0):
+			d := math.Max(v-b.Min, 0) + 1
+			xs[k] = math.Sqrt(d*d - 1)
+		case !math.IsInf(b.Max, 0):
+			d := math.Max(b.Max-v, 0) + 1
+			xs[k] = math.Sqrt(d*d - 1)
+		default:
+			xs[k] = v
+		}
+	}
+	return xs
+}
+
+// cost returns the cost function fct of the external parameters, as a
+// function of the internal parameters.
+func (p params) cost(fct func(ps []float64) float64) func(xs []float64) float64 {
+	ps := make([]float64, len(p.ps))
+	return func(xs []float64) float64 {
+		return fct(p.external(ps, xs))
+	}
+}
+
+// problem describes a cost function to minimize.
+type problem struct {
+	fct    func(ps []float64) float64 // cost function
+	ps     []float64                  // initial values of the parameters
+	fixed  []int                      // indices of the fixed parameters
+	bounds map[int]Bound              // limits of the parameters
+	ndata  int                        // number of data points
+}
+
+// minimize returns the minimum of the cost function of the problem, with
+// the provided settings and optimization method m.
+func minimize(pb problem, settings *optimize.Settings, m optimize.Method) (*Result, error) {
+	pars, err := newParams(pb.ps, pb.fixed, pb.bounds)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pars.free) == 0 {
+		return nil, fmt.Errorf("fit: no free parameter")
+	}
+
+	if m == nil {
+		m = &optimize.NelderMead{}
+	}
+
+	o, err := optimize.Minimize(newProblem(pars.cost(pb.fct)), pars.internal(pb.ps), settings, m)
+	if o == nil {
+		return nil, err
+	}
+
+	if !pars.identity() {
+		o.X = pars.external(make([]float64, len(pb.ps)), o.X)
+		o.Gradient = nil
+		o.Hessian = nil
+	}
+
+	fixed := append([]int(nil), pb.fixed...)
+	sort.Ints(fixed)
+
+	res := &Result{
+		Result:   o,
+		ndf:      pb.ndata - len(pars.free),
+		fct:      pb.fct,
+		fixed:    fixed,
+		bounds:   pb.bounds,
+		settings: settings,
+		method:   m,
+	}
+	res.cov = covariance(pb.fct, o.X, pars.free)
+
+	return res, err
+}
+
+// newProblem returns the optimization problem of minimizing fct, with its
+// gradient and Hessian estimated by finite differences.
+func newProblem(fct func(xs []float64) float64) optimize.Problem {
+	return optimize.Problem{
+		Func: fct,
+		Grad: func(grad, xs []float64) {
+			fd.Gradient(grad, fct, xs, nil)
+		},
+		Hess: func(hess *mat.SymDense, xs []float64) {
+			fd.Hessian(hess, fct, xs, nil)
+		},
+	}
+}
+
+// covariance returns the covariance matrix of the parameters ps, from the
+// Hessian of the cost function with respect to the free parameters.
+func covariance(fct func(ps []float64) float64, ps []float64, free []int) *mat.SymDense {
+	var (
+		n   = len(free)
+		xs  = make([]float64, n)
+		buf = make([]float64, len(ps))
+		cov = mat.NewSymDense(len(ps), nil)
+	)
+	for k, i := range free {
+		xs[k] = ps[i]
+	}
+
+	hess := mat.NewSymDense(n, nil)
+	fd.Hessian(hess, func(xs []float64) float64 {
+		copy(buf, ps)
+		for k, i := range free {
+			buf[i] = xs[k]
+		}
+		return fct(buf)
+	}, xs, &fd.Settings{Formula: fd.Central})
+
+	// the covariance matrix is 2*up times the inverse of the Hessian.
+	hess.ScaleSym(1/(2*up), hess)
+
+	var (
+		chol mat.Cholesky
+		inv  mat.SymDense
+	)
+	ok := chol.Factorize(hess)
+	if ok {
+		ok = chol.InverseTo(&inv) == nil
+	}
+	if !ok {
+		for _, i := range free {
+			for _, j := range free {
+				cov.SetSym(i, j, math.NaN())
+			}
+		}
+		return cov
+	}
+
+	for ki, i := range free {
+		for kj, j := range free {
+			cov.SetSym(i, j, inv.At(ki, kj))
+		}
+	}
+	return cov
+}
@@ -0,0 +1,303 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fit_test
+
+import (
+	"math"
+	"testing"
+
+	"go-hep.org/x/hep/fit"
+	"go-hep.org/x/hep/hbook"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+func TestResultLinear(t *testing.T) {
+	var (
+		xs   = []float64{0, 1, 2, 3, 4, 5, 6, 7}
+		ys   = []float64{1.1, 2.9, 5.2, 7.1, 8.8, 11.2, 12.9, 15.1}
+		errs = []float64{0.1, 0.2, 0.1, 0.2, 0.1, 0.2, 0.1, 0.2}
+		line = func(x float64, ps []float64) float64 {
+			return ps[0] + ps[1]*x
+		}
+	)
+
+	// analytical weighted least squares solution.
+	var s, sx, sxx, sy, sxy float64
+	for i, x := range xs {
+		w := 1 / (errs[i] * errs[i])
+		s += w
+		sx += w * x
+		sxx += w * x * x
+		sy += w * ys[i]
+		sxy += w * x * ys[i]
+	}
+	var (
+		det  = s*sxx - sx*sx
+		want = []float64{(sxx*sy - sx*sxy) / det, (s*sxy - sx*sy) / det}
+		cov  = mat.NewSymDense(2, []float64{sxx / det, -sx / det, -sx / det, s / det})
+		chi2 float64
+	)
+	for i, x := range xs {
+		r := (ys[i] - line(x, want)) / errs[i]
+		chi2 += r * r
+	}
+
+	res, err := fit.Curve1D(
+		fit.Func1D{F: line, N: 2, X: xs, Y: ys, Err: errs},
+		nil, &optimize.NelderMead{},
+	)
+	if err != nil {
+		t.Fatalf("could not fit: %+v", err)
+	}
+
+	if got := res.X; !floats.EqualApprox(got, want, 1e-6) {
+		t.Fatalf("invalid best-fit: got=%v, want=%v", got, want)
+	}
+	if got, want := res.Chi2(), chi2; math.Abs(got-want) > 1e-6 {
+		t.Fatalf("invalid chi2: got=%v, want=%v", got, want)
+	}
+	if got, want := res.NDF(), len(xs)-2; got != want {
+		t.Fatalf("invalid ndf: got=%d, want=%d", got, want)
+	}
+	if got := res.Cov(); !mat.EqualApprox(got, cov, 1e-5) {
+		t.Fatalf("invalid covariance:\ngot= %v\nwant=%v", mat.Formatted(got), mat.Formatted(cov))
+	}
+
+	errors := res.Errors()
+	if got, want := errors, []float64{math.Sqrt(cov.At(0, 0)), math.Sqrt(cov.At(1, 1))}; !floats.EqualApprox(got, want, 1e-5) {
+		t.Fatalf("invalid errors: got=%v, want=%v", got, want)
+	}
+
+	corr := res.Corr()
+	if got, want := corr.At(0, 0), 1.0; math.Abs(got-want) > 1e-9 {
+		t.Fatalf("invalid correlation: got=%v, want=%v", got, want)
+	}
+	if got, want := corr.At(0, 1), cov.At(0, 1)/(errors[0]*errors[1]); math.Abs(got-want) > 1e-4 {
+		t.Fatalf("invalid correlation: got=%v, want=%v", got, want)
+	}
+
+	// the profile of a linear least squares is parabolic.
+	for i := range res.X {
+		lo, hi, err := res.Interval(i)
+		if err != nil {
+			t.Fatalf("could not compute interval of parameter %d: %+v", i, err)
+		}
+		if got, want := []float64{lo, hi}, []float64{res.X[i] - errors[i], res.X[i] + errors[i]}; !floats.EqualApprox(got, want, 1e-3*errors[i]) {
+			t.Fatalf("invalid interval for parameter %d: got=%v, want=%v", i, got, want)
+		}
+	}
+}
+
+func TestResultFixedBounds(t *testing.T) {
+	var (
+		xs   = []float64{0, 1, 2, 3, 4}
+		ys   = []float64{1, 3, 5, 7, 9}
+		line = func(x float64, ps []float64) float64 {
+			return ps[0] + ps[1]*x
+		}
+	)
+
+	res, err := fit.Curve1D(
+		fit.Func1D{F: line, Ps: []float64{0, 3}, Fixed: []int{1}, X: xs, Y: ys},
+		nil, &optimize.NelderMead{},
+	)
+	if err != nil {
+		t.Fatalf("could not fit: %+v", err)
+	}
+	if got, want := res.X[1], 3.0; got != want {
+		t.Fatalf("fixed parameter modified: got=%v, want=%v", got, want)
+	}
+	if got, want := res.X[0], -1.0; math.Abs(got-want) > 1e-6 {
+		t.Fatalf("invalid free parameter: got=%v, want=%v", got, want)
+	}
+	if got, want := res.Errors()[1], 0.0; got != want {
+		t.Fatalf("invalid error on fixed parameter: got=%v, want=%v", got, want)
+	}
+	if got, want := res.NDF(), len(xs)-1; got != want {
+		t.Fatalf("invalid ndf: got=%d, want=%d", got, want)
+	}
+	if lo, hi, err := res.Interval(1); err != nil || lo != 3 || hi != 3 {
+		t.Fatalf("invalid interval for fixed parameter: [%v, %v] (err=%v)", lo, hi, err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		bounds map[int]fit.Bound
+		want   []float64
+	}{
+		{
+			name:   "no-bounds",
+			bounds: nil,
+			want:   []float64{1, 2},
+		},
+		{
+			name:   "within-bounds",
+			bounds: map[int]fit.Bound{0: {Min: -5, Max: 5}},
+			want:   []float64{1, 2},
+		},
+		{
+			name:   "upper-bound",
+			bounds: map[int]fit.Bound{1: {Min: math.Inf(-1), Max: 1.5}},
+			want:   []float64{2, 1.5},
+		},
+		{
+			name:   "lower-bound",
+			bounds: map[int]fit.Bound{0: {Min: 2, Max: math.Inf(+1)}},
+			want:   []float64{2, 5.0 / 3},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := fit.Curve1D(
+				fit.Func1D{F: line, Ps: []float64{3, 1}, Bounds: tc.bounds, X: xs, Y: ys},
+				nil, &optimize.NelderMead{},
+			)
+			if err != nil {
+				t.Fatalf("could not fit: %+v", err)
+			}
+			if got := res.X; !floats.EqualApprox(got, tc.want, 1e-4) {
+				t.Fatalf("invalid best-fit: got=%v, want=%v", got, tc.want)
+			}
+			for i, b := range tc.bounds {
+				if v := res.X[i]; v < b.Min || b.Max < v {
+					t.Fatalf("parameter %d out of bounds: %v", i, v)
+				}
+			}
+		})
+	}
+}
+
+func TestResultInvalidParams(t *testing.T) {
+	var (
+		xs   = []float64{0, 1, 2, 3, 4}
+		ys   = []float64{1, 3, 5, 7, 9}
+		line = func(x float64, ps []float64) float64 {
+			return ps[0] + ps[1]*x
+		}
+	)
+
+	for _, tc := range []struct {
+		name string
+		fct  fit.Func1D
+	}{
+		{
+			name: "all-fixed",
+			fct:  fit.Func1D{F: line, Ps: []float64{1, 2}, Fixed: []int{0, 1}, X: xs, Y: ys},
+		},
+		{
+			name: "invalid-fixed-index",
+			fct:  fit.Func1D{F: line, Ps: []float64{1, 2}, Fixed: []int{2}, X: xs, Y: ys},
+		},
+		{
+			name: "invalid-bound-index",
+			fct:  fit.Func1D{F: line, Ps: []float64{1, 2}, Bounds: map[int]fit.Bound{-1: {Min: 0, Max: 1}}, X: xs, Y: ys},
+		},
+		{
+			name: "invalid-bound",
+			fct:  fit.Func1D{F: line, Ps: []float64{1, 2}, Bounds: map[int]fit.Bound{0: {Min: 1, Max: 1}}, X: xs, Y: ys},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := fit.Curve1D(tc.fct, nil, &optimize.NelderMead{})
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
+
+// countMethod counts the number of minimizations run with NelderMead.
+type countMethod struct {
+	*optimize.NelderMead
+	n int
+}
+
+func (m *countMethod) Init(dim, tasks int) int {
+	m.n++
+	return m.NelderMead.Init(dim, tasks)
+}
+
+func TestResultIntervalMethod(t *testing.T) {
+	var (
+		xs   = []float64{0, 1, 2, 3, 4}
+		ys   = []float64{1.1, 2.9, 5.2, 7.1, 8.8}
+		errs = []float64{0.1, 0.2, 0.1, 0.2, 0.1}
+		line = func(x float64, ps []float64) float64 {
+			return ps[0] + ps[1]*x
+		}
+		m = &countMethod{NelderMead: &optimize.NelderMead{}}
+	)
+
+	res, err := fit.Curve1D(
+		fit.Func1D{F: line, Ps: []float64{0, 1}, X: xs, Y: ys, Err: errs},
+		nil, m,
+	)
+	if err != nil {
+		t.Fatalf("could not fit: %+v", err)
+	}
+
+	n := m.n
+	lo, hi, err := res.Interval(1)
+	if err != nil {
+		t.Fatalf("could not compute interval: %+v", err)
+	}
+	if m.n == n {
+		t.Fatalf("interval not computed with the method of the fit")
+	}
+
+	var (
+		x0 = res.X[1]
+		dx = res.Errors()[1]
+	)
+	if math.Abs(x0-dx-lo) > 1e-3 || math.Abs(x0+dx-hi) > 1e-3 {
+		t.Fatalf("invalid interval: got=[%v, %v], want=[%v, %v]", lo, hi, x0-dx, x0+dx)
+	}
+}
+
+func TestResultPoissonInterval(t *testing.T) {
+	const n = 4
+
+	h := hbook.NewH1D(1, 0, 1)
+	for i := 0; i < n; i++ {
+		h.Fill(0.5, 1)
+	}
+
+	res, err := fit.H1D(
+		h,
+		fit.Func1D{
+			F:      func(x float64, ps []float64) float64 { return ps[0] },
+			Ps:     []float64{1},
+			Bounds: map[int]fit.Bound{0: {Min: 0, Max: math.Inf(+1)}},
+		},
+		nil, &optimize.NelderMead{},
+		fit.Likelihood(),
+	)
+	if err != nil {
+		t.Fatalf("could not fit: %+v", err)
+	}
+	if got, want := res.X[0], float64(n); math.Abs(got-want) > 1e-6 {
+		t.Fatalf("invalid best-fit: got=%v, want=%v", got, want)
+	}
+
+	lo, hi, err := res.Interval(0)
+	if err != nil {
+		t.Fatalf("could not compute interval: %+v", err)
+	}
+
+	// the Poisson likelihood is asymmetric.
+	if !(n-lo < hi-n) {
+		t.Fatalf("interval not asymmetric: [%v, %v]", lo, hi)
+	}
+
+	nll := func(mu float64) float64 {
+		return mu - n + n*math.Log(n/mu)
+	}
+	for _, v := range []float64{lo, hi} {
+		if got, want := nll(v), 0.5; math.Abs(got-want) > 1e-3 {
+			t.Fatalf("invalid interval bound %v: nll=%v, want=%v", v, got, want)
+		}
+	}
+}
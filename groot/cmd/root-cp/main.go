@@ -11,6 +11,11 @@
 //	$> root-cp f.root out.root
 //	$> root-cp f1.root f2.root f3.root out.root
 //	$> root-cp f1.root:hist.* f2.root:h2 out.root
+//	$> root-cp -recover crashed.root repaired.root
+//
+// options:
+//
+//	-recover	recover input files that were not properly closed
 package main // import "go-hep.org/x/hep/groot/cmd/root-cp"
 
 import (
@@ -38,6 +43,7 @@ ex:
  $> root-cp f.root out.root
  $> root-cp f1.root f2.root f3.root out.root
  $> root-cp f1.root:hist.* f2.root:h2 out.root
+ $> root-cp -recover crashed.root repaired.root
 
 options:
 `,
@@ -45,6 +51,8 @@ options:
 		flag.PrintDefaults()
 	}
 
+	doRecover := flag.Bool("recover", false, "recover input files that were not properly closed")
+
 	flag.Parse()
 
 	if flag.NArg() < 2 {
@@ -56,7 +64,7 @@ options:
 	dst := flag.Arg(flag.NArg() - 1)
 	srcs := flag.Args()[:flag.NArg()-1]
 
-	err := rcmd.Copy(dst, srcs)
+	err := rcmd.Copy(dst, srcs, rcmd.CopyRecover(*doRecover))
 	if err != nil {
 		log.Fatal(err)
 	}
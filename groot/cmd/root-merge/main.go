@@ -4,6 +4,11 @@
 
 // root-merge merges ROOT files' content into a merged ROOT file.
 //
+// Trees, histograms, profiles, efficiencies and graphs are merged.
+// Objects are matched by their path in the input ROOT files: objects (and
+// directories) present in only some of the input files are merged from
+// these files only.
+//
 // Usage: root-merge [options] file1.root [file2.root [file3.root [...]]]
 //
 // ex:
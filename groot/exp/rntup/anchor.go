@@ -0,0 +1,214 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rntup
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+
+	"go-hep.org/x/hep/groot/internal/xxh3"
+	"go-hep.org/x/hep/groot/rbase"
+	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/rdict"
+	"go-hep.org/x/hep/groot/riofs"
+	"go-hep.org/x/hep/groot/rmeta"
+	"go-hep.org/x/hep/groot/root"
+	"go-hep.org/x/hep/groot/rtypes"
+)
+
+const (
+	anchorVersion = 2 // class version of ROOT::RNTuple
+
+	specEpoch = 1 // RNTuple binary format epoch
+	specMajor = 0
+	specMinor = 0
+	specPatch = 0
+
+	defaultMaxKeySize = 1 << 30
+)
+
+// envSpan describes where an envelope is located on disk.
+type envSpan struct {
+	seek   uint64 // offset of the (compressed) envelope
+	nbytes uint64 // size of the (compressed) envelope on disk
+	length uint64 // size of the uncompressed envelope
+}
+
+// RNTuple is the anchor of a ROOT::RNTuple, as written by ROOT >= 6.34.
+//
+// The anchor locates the header and footer envelopes of the RNTuple in
+// the file. The rest of the RNTuple metadata is loaded lazily, on demand.
+type RNTuple struct {
+	epoch uint16
+	major uint16
+	minor uint16
+	patch uint16
+
+	header envSpan
+	footer envSpan
+
+	maxKeySize uint64
+
+	r    io.ReaderAt
+	desc *Descriptor
+}
+
+func (*RNTuple) Class() string {
+	return "ROOT::RNTuple"
+}
+
+func (*RNTuple) RVersion() int16 {
+	return anchorVersion
+}
+
+func (nt *RNTuple) String() string {
+	return fmt.Sprintf("RNTuple{version:%d.%d.%d.%d, header:%v, footer:%v, max-key-size:%d}",
+		nt.epoch, nt.major, nt.minor, nt.patch,
+		nt.header, nt.footer, nt.maxKeySize,
+	)
+}
+
+// SetFile implements riofs.SetFiler.
+func (nt *RNTuple) SetFile(f *riofs.File) {
+	nt.r = f
+}
+
+// checksum returns the XXH3 checksum of the big-endian encoded anchor fields.
+func (nt *RNTuple) checksum() uint64 {
+	var buf [4*2 + 7*8]byte
+	binary.BigEndian.PutUint16(buf[0:], nt.epoch)
+	binary.BigEndian.PutUint16(buf[2:], nt.major)
+	binary.BigEndian.PutUint16(buf[4:], nt.minor)
+	binary.BigEndian.PutUint16(buf[6:], nt.patch)
+	binary.BigEndian.PutUint64(buf[8:], nt.header.seek)
+	binary.BigEndian.PutUint64(buf[16:], nt.header.nbytes)
+	binary.BigEndian.PutUint64(buf[24:], nt.header.length)
+	binary.BigEndian.PutUint64(buf[32:], nt.footer.seek)
+	binary.BigEndian.PutUint64(buf[40:], nt.footer.nbytes)
+	binary.BigEndian.PutUint64(buf[48:], nt.footer.length)
+	binary.BigEndian.PutUint64(buf[56:], nt.maxKeySize)
+	return xxh3.Sum64(buf[:])
+}
+
+func (nt *RNTuple) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	hdr := w.WriteHeader(nt.Class(), nt.RVersion())
+
+	w.WriteU16(nt.epoch)
+	w.WriteU16(nt.major)
+	w.WriteU16(nt.minor)
+	w.WriteU16(nt.patch)
+
+	w.WriteU64(nt.header.seek)
+	w.WriteU64(nt.header.nbytes)
+	w.WriteU64(nt.header.length)
+
+	w.WriteU64(nt.footer.seek)
+	w.WriteU64(nt.footer.nbytes)
+	w.WriteU64(nt.footer.length)
+
+	w.WriteU64(nt.maxKeySize)
+	w.WriteU64(nt.checksum())
+
+	return w.SetHeader(hdr)
+}
+
+func (nt *RNTuple) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	hdr := r.ReadHeader(nt.Class())
+	if hdr.Vers < anchorVersion {
+		return fmt.Errorf("rntup: invalid %s version (got=%d, want>=%d)", nt.Class(), hdr.Vers, anchorVersion)
+	}
+
+	nt.epoch = r.ReadU16()
+	nt.major = r.ReadU16()
+	nt.minor = r.ReadU16()
+	nt.patch = r.ReadU16()
+
+	nt.header.seek = r.ReadU64()
+	nt.header.nbytes = r.ReadU64()
+	nt.header.length = r.ReadU64()
+
+	nt.footer.seek = r.ReadU64()
+	nt.footer.nbytes = r.ReadU64()
+	nt.footer.length = r.ReadU64()
+
+	nt.maxKeySize = r.ReadU64()
+	chksum := r.ReadU64()
+
+	r.CheckHeader(hdr)
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	if got, want := chksum, nt.checksum(); got != want {
+		return fmt.Errorf("rntup: invalid anchor checksum (got=0x%x, want=0x%x)", got, want)
+	}
+
+	if nt.epoch != specEpoch {
+		return fmt.Errorf("rntup: unsupported RNTuple format epoch %d", nt.epoch)
+	}
+
+	return nil
+}
+
+func init() {
+	{
+		f := func() reflect.Value {
+			o := &RNTuple{}
+			return reflect.ValueOf(o)
+		}
+		rtypes.Factory.Add("ROOT::RNTuple", f)
+	}
+
+	rdict.StreamerInfos.Add(rdict.NewStreamerInfo("ROOT::RNTuple", anchorVersion, []rbytes.StreamerElement{
+		newStreamerElement("fVersionEpoch", rmeta.UShort),
+		newStreamerElement("fVersionMajor", rmeta.UShort),
+		newStreamerElement("fVersionMinor", rmeta.UShort),
+		newStreamerElement("fVersionPatch", rmeta.UShort),
+		newStreamerElement("fSeekHeader", rmeta.ULong64),
+		newStreamerElement("fNBytesHeader", rmeta.ULong64),
+		newStreamerElement("fLenHeader", rmeta.ULong64),
+		newStreamerElement("fSeekFooter", rmeta.ULong64),
+		newStreamerElement("fNBytesFooter", rmeta.ULong64),
+		newStreamerElement("fLenFooter", rmeta.ULong64),
+		newStreamerElement("fMaxKeySize", rmeta.ULong64),
+	}))
+}
+
+func newStreamerElement(name string, kind rmeta.Enum) rbytes.StreamerElement {
+	var (
+		size  int32
+		ename string
+	)
+	switch kind {
+	case rmeta.UShort:
+		size, ename = 2, "unsigned short"
+	case rmeta.ULong64:
+		size, ename = 8, "ULong64_t"
+	}
+	return &rdict.StreamerBasicType{StreamerElement: rdict.Element{
+		Name:  *rbase.NewNamed(name, ""),
+		Type:  kind,
+		Size:  size,
+		EName: ename,
+	}.New()}
+}
+
+var (
+	_ root.Object        = (*RNTuple)(nil)
+	_ rbytes.RVersioner  = (*RNTuple)(nil)
+	_ rbytes.Marshaler   = (*RNTuple)(nil)
+	_ rbytes.Unmarshaler = (*RNTuple)(nil)
+	_ riofs.SetFiler     = (*RNTuple)(nil)
+)
@@ -0,0 +1,405 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rntup
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// colKind is the on-disk type of a column.
+type colKind uint16
+
+const (
+	colIndex64      colKind = 0x01
+	colIndex32      colKind = 0x02
+	colSwitch       colKind = 0x03
+	colByte         colKind = 0x04
+	colChar         colKind = 0x05
+	colBit          colKind = 0x06
+	colReal64       colKind = 0x07
+	colReal32       colKind = 0x08
+	colReal16       colKind = 0x09
+	colUInt64       colKind = 0x0A
+	colUInt32       colKind = 0x0B
+	colUInt16       colKind = 0x0C
+	colUInt8        colKind = 0x0D
+	colSplitIndex64 colKind = 0x0E
+	colSplitIndex32 colKind = 0x0F
+	colSplitReal64  colKind = 0x10
+	colSplitReal32  colKind = 0x11
+	colSplitUInt64  colKind = 0x13
+	colSplitUInt32  colKind = 0x14
+	colSplitUInt16  colKind = 0x15
+	colInt64        colKind = 0x16
+	colInt32        colKind = 0x17
+	colInt16        colKind = 0x18
+	colInt8         colKind = 0x19
+	colSplitInt64   colKind = 0x1A
+	colSplitInt32   colKind = 0x1B
+	colSplitInt16   colKind = 0x1C
+	colReal32Trunc  colKind = 0x1D
+	colReal32Quant  colKind = 0x1E
+)
+
+// colEncoding describes how the elements of a column are encoded on disk.
+type colEncoding uint8
+
+const (
+	encPlain  colEncoding = iota
+	encSplit              // byte-stream split
+	encDelta              // delta encoding, then byte-stream split
+	encZigzag             // zigzag encoding, then byte-stream split
+)
+
+type colInfo struct {
+	name  string
+	nbits int // number of bits of an element on disk
+	size  int // size in bytes of an element in memory
+	enc   colEncoding
+	kind  colValueKind
+}
+
+// colValueKind describes the kind of values stored in a column.
+type colValueKind uint8
+
+const (
+	valUnknown colValueKind = iota
+	valIndex
+	valBool
+	valSigned
+	valUnsigned
+	valFloat
+)
+
+var colInfos = map[colKind]colInfo{
+	colIndex64:      {"Index64", 64, 8, encPlain, valIndex},
+	colIndex32:      {"Index32", 32, 8, encPlain, valIndex},
+	colSwitch:       {"Switch", 96, 12, encPlain, valUnknown},
+	colByte:         {"Byte", 8, 1, encPlain, valUnsigned},
+	colChar:         {"Char", 8, 1, encPlain, valSigned},
+	colBit:          {"Bit", 1, 1, encPlain, valBool},
+	colReal64:       {"Real64", 64, 8, encPlain, valFloat},
+	colReal32:       {"Real32", 32, 4, encPlain, valFloat},
+	colReal16:       {"Real16", 16, 4, encPlain, valFloat},
+	colUInt64:       {"UInt64", 64, 8, encPlain, valUnsigned},
+	colUInt32:       {"UInt32", 32, 4, encPlain, valUnsigned},
+	colUInt16:       {"UInt16", 16, 2, encPlain, valUnsigned},
+	colUInt8:        {"UInt8", 8, 1, encPlain, valUnsigned},
+	colSplitIndex64: {"SplitIndex64", 64, 8, encDelta, valIndex},
+	colSplitIndex32: {"SplitIndex32", 32, 8, encDelta, valIndex},
+	colSplitReal64:  {"SplitReal64", 64, 8, encSplit, valFloat},
+	colSplitReal32:  {"SplitReal32", 32, 4, encSplit, valFloat},
+	colSplitUInt64:  {"SplitUInt64", 64, 8, encSplit, valUnsigned},
+	colSplitUInt32:  {"SplitUInt32", 32, 4, encSplit, valUnsigned},
+	colSplitUInt16:  {"SplitUInt16", 16, 2, encSplit, valUnsigned},
+	colInt64:        {"Int64", 64, 8, encPlain, valSigned},
+	colInt32:        {"Int32", 32, 4, encPlain, valSigned},
+	colInt16:        {"Int16", 16, 2, encPlain, valSigned},
+	colInt8:         {"Int8", 8, 1, encPlain, valSigned},
+	colSplitInt64:   {"SplitInt64", 64, 8, encZigzag, valSigned},
+	colSplitInt32:   {"SplitInt32", 32, 4, encZigzag, valSigned},
+	colSplitInt16:   {"SplitInt16", 16, 2, encZigzag, valSigned},
+	colReal32Trunc:  {"Real32Trunc", 0, 4, encPlain, valUnknown},
+	colReal32Quant:  {"Real32Quant", 0, 4, encPlain, valUnknown},
+}
+
+func (kind colKind) String() string {
+	if info, ok := colInfos[kind]; ok {
+		return info.name
+	}
+	return fmt.Sprintf("colKind(0x%02x)", uint16(kind))
+}
+
+// pageSize returns the size in bytes of a page of n elements of the given
+// column kind, as stored on disk (before compression).
+func pageSize(kind colKind, n int) int {
+	nbits := colInfos[kind].nbits
+	return (n*nbits + 7) / 8
+}
+
+// unpack decodes the n elements of a page of the provided column kind into
+// their in-memory representation.
+// Index columns are always decoded as 64b values.
+// Real16 columns are decoded as float32 values.
+// Bit columns are decoded as one byte per element.
+func unpack(kind colKind, src []byte, n int) ([]byte, error) {
+	info, ok := colInfos[kind]
+	if !ok || info.kind == valUnknown {
+		return nil, fmt.Errorf("rntup: unsupported column type %v", kind)
+	}
+	if got, want := len(src), pageSize(kind, n); got != want {
+		return nil, fmt.Errorf("rntup: invalid %v page size (got=%d, want=%d)", kind, got, want)
+	}
+
+	switch kind {
+	case colBit:
+		dst := make([]byte, n)
+		for i := range dst {
+			dst[i] = (src[i/8] >> (i % 8)) & 1
+		}
+		return dst, nil
+
+	case colReal16:
+		dst := make([]byte, 4*n)
+		for i := 0; i < n; i++ {
+			v := f16tof32(binary.LittleEndian.Uint16(src[2*i:]))
+			binary.LittleEndian.PutUint32(dst[4*i:], math.Float32bits(v))
+		}
+		return dst, nil
+	}
+
+	var (
+		sz  = info.nbits / 8
+		dst = make([]byte, n*sz)
+	)
+	switch info.enc {
+	case encPlain:
+		copy(dst, src)
+	default:
+		unsplit(dst, src, sz, n)
+	}
+
+	switch info.enc {
+	case encDelta:
+		undelta(dst, sz, n)
+	case encZigzag:
+		unzigzag(dst, sz, n)
+	}
+
+	if kind == colIndex32 || kind == colSplitIndex32 {
+		out := make([]byte, 8*n)
+		for i := 0; i < n; i++ {
+			binary.LittleEndian.PutUint64(out[8*i:], uint64(binary.LittleEndian.Uint32(dst[4*i:])))
+		}
+		dst = out
+	}
+
+	return dst, nil
+}
+
+// pack encodes the n elements of the provided in-memory representation
+// into a page of the provided column kind.
+func pack(kind colKind, src []byte, n int) ([]byte, error) {
+	info, ok := colInfos[kind]
+	if !ok || info.kind == valUnknown || kind == colReal16 {
+		return nil, fmt.Errorf("rntup: unsupported column type %v", kind)
+	}
+
+	if kind == colBit {
+		dst := make([]byte, pageSize(kind, n))
+		for i := 0; i < n; i++ {
+			if src[i] != 0 {
+				dst[i/8] |= 1 << (i % 8)
+			}
+		}
+		return dst, nil
+	}
+
+	sz := info.nbits / 8
+	buf := make([]byte, n*sz)
+	switch kind {
+	case colIndex32, colSplitIndex32:
+		for i := 0; i < n; i++ {
+			binary.LittleEndian.PutUint32(buf[4*i:], uint32(binary.LittleEndian.Uint64(src[8*i:])))
+		}
+	default:
+		copy(buf, src)
+	}
+
+	switch info.enc {
+	case encPlain:
+		return buf, nil
+	case encDelta:
+		delta(buf, sz, n)
+	case encZigzag:
+		zigzag(buf, sz, n)
+	}
+
+	dst := make([]byte, n*sz)
+	split(dst, buf, sz, n)
+	return dst, nil
+}
+
+// unsplit reverts the byte-stream split of n elements of size sz.
+func unsplit(dst, src []byte, sz, n int) {
+	for b := 0; b < sz; b++ {
+		for i := 0; i < n; i++ {
+			dst[i*sz+b] = src[b*n+i]
+		}
+	}
+}
+
+// split applies the byte-stream split of n elements of size sz.
+func split(dst, src []byte, sz, n int) {
+	for b := 0; b < sz; b++ {
+		for i := 0; i < n; i++ {
+			dst[b*n+i] = src[i*sz+b]
+		}
+	}
+}
+
+func undelta(p []byte, sz, n int) {
+	switch sz {
+	case 4:
+		for i := 1; i < n; i++ {
+			prev := binary.LittleEndian.Uint32(p[4*(i-1):])
+			cur := binary.LittleEndian.Uint32(p[4*i:])
+			binary.LittleEndian.PutUint32(p[4*i:], prev+cur)
+		}
+	case 8:
+		for i := 1; i < n; i++ {
+			prev := binary.LittleEndian.Uint64(p[8*(i-1):])
+			cur := binary.LittleEndian.Uint64(p[8*i:])
+			binary.LittleEndian.PutUint64(p[8*i:], prev+cur)
+		}
+	}
+}
+
+func delta(p []byte, sz, n int) {
+	switch sz {
+	case 4:
+		for i := n - 1; i > 0; i-- {
+			prev := binary.LittleEndian.Uint32(p[4*(i-1):])
+			cur := binary.LittleEndian.Uint32(p[4*i:])
+			binary.LittleEndian.PutUint32(p[4*i:], cur-prev)
+		}
+	case 8:
+		for i := n - 1; i > 0; i-- {
+			prev := binary.LittleEndian.Uint64(p[8*(i-1):])
+			cur := binary.LittleEndian.Uint64(p[8*i:])
+			binary.LittleEndian.PutUint64(p[8*i:], cur-prev)
+		}
+	}
+}
+
+func unzigzag(p []byte, sz, n int) {
+	switch sz {
+	case 2:
+		for i := 0; i < n; i++ {
+			v := binary.LittleEndian.Uint16(p[2*i:])
+			binary.LittleEndian.PutUint16(p[2*i:], (v>>1)^-(v&1))
+		}
+	case 4:
+		for i := 0; i < n; i++ {
+			v := binary.LittleEndian.Uint32(p[4*i:])
+			binary.LittleEndian.PutUint32(p[4*i:], (v>>1)^-(v&1))
+		}
+	case 8:
+		for i := 0; i < n; i++ {
+			v := binary.LittleEndian.Uint64(p[8*i:])
+			binary.LittleEndian.PutUint64(p[8*i:], (v>>1)^-(v&1))
+		}
+	}
+}
+
+func zigzag(p []byte, sz, n int) {
+	switch sz {
+	case 2:
+		for i := 0; i < n; i++ {
+			v := int16(binary.LittleEndian.Uint16(p[2*i:]))
+			binary.LittleEndian.PutUint16(p[2*i:], uint16((v<<1)^(v>>15)))
+		}
+	case 4:
+		for i := 0; i < n; i++ {
+			v := int32(binary.LittleEndian.Uint32(p[4*i:]))
+			binary.LittleEndian.PutUint32(p[4*i:], uint32((v<<1)^(v>>31)))
+		}
+	case 8:
+		for i := 0; i < n; i++ {
+			v := int64(binary.LittleEndian.Uint64(p[8*i:]))
+			binary.LittleEndian.PutUint64(p[8*i:], uint64((v<<1)^(v>>63)))
+		}
+	}
+}
+
+// f16tof32 converts an IEEE 754 half-precision float to a float32.
+func f16tof32(h uint16) float32 {
+	var (
+		sign = uint32(h>>15) << 31
+		exp  = uint32(h>>10) & 0x1f
+		mant = uint32(h) & 0x3ff
+	)
+	switch {
+	case exp == 0 && mant == 0:
+		return math.Float32frombits(sign)
+	case exp == 0:
+		// subnormal
+		for mant&0x400 == 0 {
+			mant <<= 1
+			exp--
+		}
+		exp++
+		mant &= 0x3ff
+		return math.Float32frombits(sign | (exp+127-15)<<23 | mant<<13)
+	case exp == 0x1f:
+		return math.Float32frombits(sign | 0xff<<23 | mant<<13)
+	}
+	return math.Float32frombits(sign | (exp+127-15)<<23 | mant<<13)
+}
+
+// column holds the decoded content of a column for a given cluster.
+type column struct {
+	desc *columnDesc
+	info colInfo
+	buf  []byte // in-memory representation of the elements
+	n    int64  // number of elements
+}
+
+func (col *column) index(i int64) uint64 {
+	return binary.LittleEndian.Uint64(col.buf[8*i:])
+}
+
+// offsets returns the [beg, end) range of the i-th collection.
+func (col *column) offsets(i int64) (beg, end uint64) {
+	if i > 0 {
+		beg = col.index(i - 1)
+	}
+	return beg, col.index(i)
+}
+
+func (col *column) uint(i int64) uint64 {
+	switch sz := col.info.size; sz {
+	case 1:
+		return uint64(col.buf[i])
+	case 2:
+		return uint64(binary.LittleEndian.Uint16(col.buf[2*i:]))
+	case 4:
+		return uint64(binary.LittleEndian.Uint32(col.buf[4*i:]))
+	default:
+		return binary.LittleEndian.Uint64(col.buf[8*i:])
+	}
+}
+
+func (col *column) int(i int64) int64 {
+	switch col.info.kind {
+	case valFloat:
+		return int64(col.float(i))
+	case valSigned:
+		switch sz := col.info.size; sz {
+		case 1:
+			return int64(int8(col.buf[i]))
+		case 2:
+			return int64(int16(binary.LittleEndian.Uint16(col.buf[2*i:])))
+		case 4:
+			return int64(int32(binary.LittleEndian.Uint32(col.buf[4*i:])))
+		}
+	}
+	return int64(col.uint(i))
+}
+
+func (col *column) float(i int64) float64 {
+	switch col.info.kind {
+	case valFloat:
+		if col.info.size == 4 {
+			return float64(math.Float32frombits(binary.LittleEndian.Uint32(col.buf[4*i:])))
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(col.buf[8*i:]))
+	case valSigned:
+		return float64(col.int(i))
+	}
+	return float64(col.uint(i))
+}
@@ -0,0 +1,75 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rntup
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestColumnCodec(t *testing.T) {
+	const n = 13
+	for kind, info := range colInfos {
+		if info.kind == valUnknown || kind == colReal16 {
+			continue
+		}
+		t.Run(info.name, func(t *testing.T) {
+			want := make([]byte, n*info.size)
+			for i := 0; i < n; i++ {
+				switch {
+				case kind == colBit:
+					want[i] = byte(i % 2)
+				case info.kind == valIndex:
+					binary.LittleEndian.PutUint64(want[8*i:], uint64(i*i))
+				case info.kind == valSigned:
+					for j := 0; j < info.size; j++ {
+						want[i*info.size+j] = byte(-i)
+					}
+				default:
+					for j := 0; j < info.size; j++ {
+						want[i*info.size+j] = byte(i*7 + j)
+					}
+				}
+			}
+
+			page, err := pack(kind, want, n)
+			if err != nil {
+				t.Fatalf("could not pack: %+v", err)
+			}
+			if got, want := len(page), pageSize(kind, n); got != want {
+				t.Fatalf("invalid page size: got=%d, want=%d", got, want)
+			}
+
+			got, err := unpack(kind, page, n)
+			if err != nil {
+				t.Fatalf("could not unpack: %+v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("invalid round-trip:\ngot= %v\nwant=%v", got, want)
+			}
+		})
+	}
+}
+
+func TestF16(t *testing.T) {
+	for _, tc := range []struct {
+		v    uint16
+		want float32
+	}{
+		{0x0000, 0},
+		{0x3c00, 1},
+		{0xc000, -2},
+		{0x3555, 0.333251953125},
+		{0x7bff, 65504},
+		{0x0001, 5.960464477539063e-08},
+		{0x7c00, float32(math.Inf(+1))},
+	} {
+		if got := f16tof32(tc.v); got != tc.want {
+			t.Errorf("invalid f16 conversion of 0x%04x: got=%v, want=%v", tc.v, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,697 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rntup
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"go-hep.org/x/hep/groot/internal/rcompress"
+)
+
+// fieldRole is the structural role of a field.
+type fieldRole uint16
+
+const (
+	roleLeaf       fieldRole = 0x00
+	roleCollection fieldRole = 0x01
+	roleRecord     fieldRole = 0x02
+	roleVariant    fieldRole = 0x03
+	roleStreamer   fieldRole = 0x04
+)
+
+func (role fieldRole) String() string {
+	switch role {
+	case roleLeaf:
+		return "leaf"
+	case roleCollection:
+		return "collection"
+	case roleRecord:
+		return "record"
+	case roleVariant:
+		return "variant"
+	case roleStreamer:
+		return "streamer"
+	}
+	return fmt.Sprintf("fieldRole(%d)", uint16(role))
+}
+
+// Field flags.
+const (
+	fieldFlagRepetitive = 0x01
+	fieldFlagProjected  = 0x02
+	fieldFlagChecksum   = 0x04
+)
+
+// Column flags.
+const (
+	colFlagDeferred = 0x01
+	colFlagRange    = 0x02
+)
+
+type fieldDesc struct {
+	id       uint32
+	fvers    uint32 // field version
+	tvers    uint32 // type version
+	parent   uint32
+	role     fieldRole
+	flags    uint16
+	name     string
+	typ      string
+	alias    string
+	desc     string
+	nrep     uint64 // number of repetitions, for fixed-size arrays
+	source   uint32 // source field, for projected fields
+	checksum uint32 // type checksum
+
+	fields []uint32 // ids of the sub-fields
+	cols   []uint32 // ids of the (principal representation) columns
+}
+
+type columnDesc struct {
+	id    uint32
+	kind  colKind
+	nbits uint16
+	field uint32
+	flags uint16
+	repr  uint16
+	first int64 // first element index, for deferred columns
+	min   float64
+	max   float64
+}
+
+type aliasDesc struct {
+	phys  uint32 // physical column id
+	field uint32 // field id
+}
+
+type typeInfoDesc struct {
+	kind    uint32
+	tvers   uint32
+	typ     string
+	content string
+}
+
+type schema struct {
+	fields  []fieldDesc
+	cols    []columnDesc
+	aliases []aliasDesc
+	types   []typeInfoDesc
+}
+
+type header struct {
+	flags  uint64
+	name   string
+	desc   string
+	writer string
+	schema
+}
+
+type clusterGroup struct {
+	minEntry  uint64
+	span      uint64
+	nclusters uint32
+	pageList  envLink
+}
+
+type footer struct {
+	flags  uint64
+	hdrsum uint64
+	ext    schema // schema extension
+	groups []clusterGroup
+}
+
+type pageDesc struct {
+	nelems   uint32
+	checksum bool // whether an XXH3 checksum follows the page data
+	loc      locator
+}
+
+type colPages struct {
+	pages []pageDesc
+	first int64 // index of the first element of the column in the cluster
+	compr uint32
+	supp  bool // whether the column is suppressed in the cluster
+}
+
+type clusterDesc struct {
+	first  uint64 // first entry of the cluster
+	nelems uint64 // number of entries in the cluster
+	flags  uint8
+	cols   []colPages // pages, indexed by column id
+}
+
+type pageList struct {
+	hdrsum   uint64
+	clusters []clusterDesc
+}
+
+func (fd *fieldDesc) decode(r *rbuff, id uint32) {
+	end := r.record()
+	fd.id = id
+	fd.fvers = r.u32()
+	fd.tvers = r.u32()
+	fd.parent = r.u32()
+	fd.role = fieldRole(r.u16())
+	fd.flags = r.u16()
+	fd.name = r.str()
+	fd.typ = r.str()
+	fd.alias = r.str()
+	fd.desc = r.str()
+	if fd.flags&fieldFlagRepetitive != 0 {
+		fd.nrep = r.u64()
+	}
+	if fd.flags&fieldFlagProjected != 0 {
+		fd.source = r.u32()
+	}
+	if fd.flags&fieldFlagChecksum != 0 {
+		fd.checksum = r.u32()
+	}
+	r.seek(end)
+}
+
+func (fd *fieldDesc) encode(w *wbuff) {
+	pos := w.record()
+	w.u32(fd.fvers)
+	w.u32(fd.tvers)
+	w.u32(fd.parent)
+	w.u16(uint16(fd.role))
+	w.u16(fd.flags)
+	w.str(fd.name)
+	w.str(fd.typ)
+	w.str(fd.alias)
+	w.str(fd.desc)
+	if fd.flags&fieldFlagRepetitive != 0 {
+		w.u64(fd.nrep)
+	}
+	if fd.flags&fieldFlagProjected != 0 {
+		w.u32(fd.source)
+	}
+	if fd.flags&fieldFlagChecksum != 0 {
+		w.u32(fd.checksum)
+	}
+	w.endRecord(pos)
+}
+
+func (cd *columnDesc) decode(r *rbuff, id uint32) {
+	end := r.record()
+	cd.id = id
+	cd.kind = colKind(r.u16())
+	cd.nbits = r.u16()
+	cd.field = r.u32()
+	cd.flags = r.u16()
+	cd.repr = r.u16()
+	if cd.flags&colFlagDeferred != 0 {
+		cd.first = r.i64()
+	}
+	if cd.flags&colFlagRange != 0 {
+		cd.min = r.f64()
+		cd.max = r.f64()
+	}
+	r.seek(end)
+}
+
+func (cd *columnDesc) encode(w *wbuff) {
+	pos := w.record()
+	w.u16(uint16(cd.kind))
+	w.u16(cd.nbits)
+	w.u32(cd.field)
+	w.u16(cd.flags)
+	w.u16(cd.repr)
+	if cd.flags&colFlagDeferred != 0 {
+		w.i64(cd.first)
+	}
+	if cd.flags&colFlagRange != 0 {
+		w.f64(cd.min)
+		w.f64(cd.max)
+	}
+	w.endRecord(pos)
+}
+
+// decode decodes a schema description.
+// fid and cid are the ids of the first field and first column of the schema.
+func (sc *schema) decode(r *rbuff, fid, cid uint32) {
+	end, n := r.list()
+	sc.fields = make([]fieldDesc, n)
+	for i := range sc.fields {
+		sc.fields[i].decode(r, fid+uint32(i))
+	}
+	r.seek(end)
+
+	end, n = r.list()
+	sc.cols = make([]columnDesc, n)
+	for i := range sc.cols {
+		sc.cols[i].decode(r, cid+uint32(i))
+	}
+	r.seek(end)
+
+	end, n = r.list()
+	sc.aliases = make([]aliasDesc, n)
+	for i := range sc.aliases {
+		end := r.record()
+		sc.aliases[i].phys = r.u32()
+		sc.aliases[i].field = r.u32()
+		r.seek(end)
+	}
+	r.seek(end)
+
+	end, n = r.list()
+	sc.types = make([]typeInfoDesc, n)
+	for i := range sc.types {
+		end := r.record()
+		sc.types[i].kind = r.u32()
+		sc.types[i].tvers = r.u32()
+		sc.types[i].typ = r.str()
+		sc.types[i].content = r.str()
+		r.seek(end)
+	}
+	r.seek(end)
+}
+
+func (sc *schema) encode(w *wbuff) {
+	pos := w.list(len(sc.fields))
+	for i := range sc.fields {
+		sc.fields[i].encode(w)
+	}
+	w.endList(pos)
+
+	pos = w.list(len(sc.cols))
+	for i := range sc.cols {
+		sc.cols[i].encode(w)
+	}
+	w.endList(pos)
+
+	pos = w.list(len(sc.aliases))
+	for _, alias := range sc.aliases {
+		pos := w.record()
+		w.u32(alias.phys)
+		w.u32(alias.field)
+		w.endRecord(pos)
+	}
+	w.endList(pos)
+
+	pos = w.list(len(sc.types))
+	for _, typ := range sc.types {
+		pos := w.record()
+		w.u32(typ.kind)
+		w.u32(typ.tvers)
+		w.str(typ.typ)
+		w.str(typ.content)
+		w.endRecord(pos)
+	}
+	w.endList(pos)
+}
+
+func (hdr *header) decode(r *rbuff) error {
+	hdr.flags = r.flags()
+	hdr.name = r.str()
+	hdr.desc = r.str()
+	hdr.writer = r.str()
+	hdr.schema.decode(r, 0, 0)
+	if err := r.Err(); err != nil {
+		return fmt.Errorf("rntup: could not decode header: %w", err)
+	}
+	return nil
+}
+
+func (hdr *header) encode(w *wbuff) {
+	w.flags(hdr.flags)
+	w.str(hdr.name)
+	w.str(hdr.desc)
+	w.str(hdr.writer)
+	hdr.schema.encode(w)
+}
+
+func (ftr *footer) decode(r *rbuff, hdr *header) error {
+	ftr.flags = r.flags()
+	ftr.hdrsum = r.u64()
+
+	end := r.record()
+	ftr.ext.decode(r, uint32(len(hdr.fields)), uint32(len(hdr.cols)))
+	r.seek(end)
+
+	end, n := r.list()
+	ftr.groups = make([]clusterGroup, n)
+	for i := range ftr.groups {
+		end := r.record()
+		grp := &ftr.groups[i]
+		grp.minEntry = r.u64()
+		grp.span = r.u64()
+		grp.nclusters = r.u32()
+		grp.pageList = r.envLink()
+		r.seek(end)
+	}
+	r.seek(end)
+
+	if err := r.Err(); err != nil {
+		return fmt.Errorf("rntup: could not decode footer: %w", err)
+	}
+	return nil
+}
+
+func (ftr *footer) encode(w *wbuff) {
+	w.flags(ftr.flags)
+	w.u64(ftr.hdrsum)
+
+	pos := w.record()
+	ftr.ext.encode(w)
+	w.endRecord(pos)
+
+	pos = w.list(len(ftr.groups))
+	for _, grp := range ftr.groups {
+		pos := w.record()
+		w.u64(grp.minEntry)
+		w.u64(grp.span)
+		w.u32(grp.nclusters)
+		w.envLink(grp.pageList)
+		w.endRecord(pos)
+	}
+	w.endList(pos)
+}
+
+func (pl *pageList) decode(r *rbuff) error {
+	pl.hdrsum = r.u64()
+
+	end, n := r.list()
+	pl.clusters = make([]clusterDesc, n)
+	for i := range pl.clusters {
+		end := r.record()
+		clu := &pl.clusters[i]
+		clu.first = r.u64()
+		v := r.u64()
+		clu.nelems = v & (1<<56 - 1)
+		clu.flags = uint8(v >> 56)
+		r.seek(end)
+	}
+	r.seek(end)
+
+	end, n = r.list()
+	if n != len(pl.clusters) && r.err == nil {
+		r.err = fmt.Errorf("rntup: page list cluster mismatch (summaries=%d, clusters=%d)", len(pl.clusters), n)
+	}
+	for i := 0; i < n && r.err == nil; i++ {
+		end, ncols := r.list()
+		clu := &pl.clusters[i]
+		clu.cols = make([]colPages, ncols)
+		for j := range clu.cols {
+			end, npages := r.list()
+			col := &clu.cols[j]
+			col.pages = make([]pageDesc, npages)
+			for k := range col.pages {
+				page := &col.pages[k]
+				nelems := r.i32()
+				if nelems < 0 {
+					nelems = -nelems
+					page.checksum = true
+				}
+				page.nelems = uint32(nelems)
+				page.loc = r.locator()
+			}
+			col.first = r.i64()
+			if col.first < 0 {
+				col.supp = true
+			} else {
+				col.compr = r.u32()
+			}
+			r.seek(end)
+		}
+		r.seek(end)
+	}
+	r.seek(end)
+
+	if err := r.Err(); err != nil {
+		return fmt.Errorf("rntup: could not decode page list: %w", err)
+	}
+	return nil
+}
+
+func (pl *pageList) encode(w *wbuff) {
+	w.u64(pl.hdrsum)
+
+	pos := w.list(len(pl.clusters))
+	for _, clu := range pl.clusters {
+		pos := w.record()
+		w.u64(clu.first)
+		w.u64(clu.nelems | uint64(clu.flags)<<56)
+		w.endRecord(pos)
+	}
+	w.endList(pos)
+
+	pos = w.list(len(pl.clusters))
+	for _, clu := range pl.clusters {
+		pos := w.list(len(clu.cols))
+		for _, col := range clu.cols {
+			pos := w.list(len(col.pages))
+			for _, page := range col.pages {
+				nelems := int32(page.nelems)
+				if page.checksum {
+					nelems = -nelems
+				}
+				w.i32(nelems)
+				w.locator(page.loc)
+			}
+			switch {
+			case col.supp:
+				w.i64(-1)
+			default:
+				w.i64(col.first)
+				w.u32(col.compr)
+			}
+			w.endList(pos)
+		}
+		w.endList(pos)
+	}
+	w.endList(pos)
+}
+
+// Descriptor describes the schema and the on-disk layout of an RNTuple.
+type Descriptor struct {
+	name   string
+	desc   string
+	writer string
+
+	fields   []fieldDesc
+	cols     []columnDesc
+	clusters []clusterDesc
+}
+
+// Descriptor loads and returns the description of the RNTuple.
+func (nt *RNTuple) Descriptor() (*Descriptor, error) {
+	if nt.desc != nil {
+		return nt.desc, nil
+	}
+	if nt.r == nil {
+		return nil, fmt.Errorf("rntup: RNTuple not attached to a file")
+	}
+
+	raw, err := readEnvelope(nt.r, nt.header.seek, nt.header.nbytes, nt.header.length)
+	if err != nil {
+		return nil, fmt.Errorf("rntup: could not read header envelope: %w", err)
+	}
+	r, hdrsum, err := openEnvelope(raw, envHeader)
+	if err != nil {
+		return nil, fmt.Errorf("rntup: could not open header envelope: %w", err)
+	}
+	var hdr header
+	err = hdr.decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err = readEnvelope(nt.r, nt.footer.seek, nt.footer.nbytes, nt.footer.length)
+	if err != nil {
+		return nil, fmt.Errorf("rntup: could not read footer envelope: %w", err)
+	}
+	r, _, err = openEnvelope(raw, envFooter)
+	if err != nil {
+		return nil, fmt.Errorf("rntup: could not open footer envelope: %w", err)
+	}
+	var ftr footer
+	err = ftr.decode(r, &hdr)
+	if err != nil {
+		return nil, err
+	}
+	if ftr.hdrsum != hdrsum {
+		return nil, fmt.Errorf("rntup: footer/header checksum mismatch (got=0x%x, want=0x%x)", ftr.hdrsum, hdrsum)
+	}
+
+	desc := &Descriptor{
+		name:   hdr.name,
+		desc:   hdr.desc,
+		writer: hdr.writer,
+		fields: append(hdr.fields, ftr.ext.fields...),
+		cols:   append(hdr.cols, ftr.ext.cols...),
+	}
+
+	for i, grp := range ftr.groups {
+		link := grp.pageList
+		raw, err := readEnvelope(nt.r, link.loc.off, uint64(link.loc.size), link.length)
+		if err != nil {
+			return nil, fmt.Errorf("rntup: could not read page list envelope %d: %w", i, err)
+		}
+		r, _, err := openEnvelope(raw, envPageList)
+		if err != nil {
+			return nil, fmt.Errorf("rntup: could not open page list envelope %d: %w", i, err)
+		}
+		var pl pageList
+		err = pl.decode(r)
+		if err != nil {
+			return nil, err
+		}
+		if pl.hdrsum != hdrsum {
+			return nil, fmt.Errorf("rntup: page list/header checksum mismatch (got=0x%x, want=0x%x)", pl.hdrsum, hdrsum)
+		}
+		if got, want := len(pl.clusters), int(grp.nclusters); got != want {
+			return nil, fmt.Errorf("rntup: invalid number of clusters in page list %d (got=%d, want=%d)", i, got, want)
+		}
+		desc.clusters = append(desc.clusters, pl.clusters...)
+	}
+
+	err = desc.link()
+	if err != nil {
+		return nil, err
+	}
+
+	nt.desc = desc
+	return desc, nil
+}
+
+// link connects fields with their sub-fields and their columns.
+func (desc *Descriptor) link() error {
+	for i := range desc.fields {
+		fd := &desc.fields[i]
+		if fd.id != uint32(i) {
+			return fmt.Errorf("rntup: invalid field id (got=%d, want=%d)", fd.id, i)
+		}
+		if fd.parent == fd.id {
+			continue
+		}
+		if int(fd.parent) >= len(desc.fields) {
+			return fmt.Errorf("rntup: field %q has invalid parent id %d", fd.name, fd.parent)
+		}
+		parent := &desc.fields[fd.parent]
+		parent.fields = append(parent.fields, fd.id)
+	}
+
+	for _, cd := range desc.cols {
+		if int(cd.field) >= len(desc.fields) {
+			return fmt.Errorf("rntup: column %d has invalid field id %d", cd.id, cd.field)
+		}
+		if cd.repr != 0 {
+			continue
+		}
+		fd := &desc.fields[cd.field]
+		fd.cols = append(fd.cols, cd.id)
+	}
+
+	for _, clu := range desc.clusters {
+		if len(clu.cols) > len(desc.cols) {
+			return fmt.Errorf("rntup: cluster with invalid number of columns (got=%d, max=%d)", len(clu.cols), len(desc.cols))
+		}
+	}
+	return nil
+}
+
+// Name returns the name of the RNTuple.
+func (desc *Descriptor) Name() string { return desc.name }
+
+// Description returns the description of the RNTuple.
+func (desc *Descriptor) Description() string { return desc.desc }
+
+// Writer returns the identifier of the library that wrote the RNTuple.
+func (desc *Descriptor) Writer() string { return desc.writer }
+
+// Entries returns the number of entries stored in the RNTuple.
+func (desc *Descriptor) Entries() int64 {
+	var n int64
+	for _, clu := range desc.clusters {
+		if end := int64(clu.first + clu.nelems); end > n {
+			n = end
+		}
+	}
+	return n
+}
+
+// Field describes a field of an RNTuple.
+type Field struct {
+	Name   string  // name of the field
+	Type   string  // C++ type name of the field
+	Fields []Field // sub-fields
+}
+
+// Fields returns the top-level fields of the RNTuple.
+func (desc *Descriptor) Fields() []Field {
+	var fields []Field
+	for i := range desc.fields {
+		fd := &desc.fields[i]
+		if fd.parent != fd.id {
+			continue
+		}
+		fields = append(fields, desc.field(fd))
+	}
+	return fields
+}
+
+func (desc *Descriptor) field(fd *fieldDesc) Field {
+	f := Field{Name: fd.name, Type: fd.typ}
+	for _, id := range fd.fields {
+		f.Fields = append(f.Fields, desc.field(&desc.fields[id]))
+	}
+	return f
+}
+
+// lookup returns the top-level field or sub-field with the provided
+// (possibly dotted) name.
+func (desc *Descriptor) lookup(name string) (*fieldDesc, bool) {
+	var top []uint32
+	for i := range desc.fields {
+		fd := &desc.fields[i]
+		if fd.parent == fd.id {
+			top = append(top, fd.id)
+		}
+	}
+	return desc.lookupIn(top, name)
+}
+
+func (desc *Descriptor) lookupIn(ids []uint32, name string) (*fieldDesc, bool) {
+	for _, id := range ids {
+		fd := &desc.fields[id]
+		if fd.name == name {
+			return fd, true
+		}
+		if strings.HasPrefix(name, fd.name+".") {
+			if sub, ok := desc.lookupIn(fd.fields, name[len(fd.name)+1:]); ok {
+				return sub, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// readEnvelope reads and decompresses the envelope located at the provided offset.
+func readEnvelope(r io.ReaderAt, off, nbytes, length uint64) ([]byte, error) {
+	buf := make([]byte, nbytes)
+	_, err := r.ReadAt(buf, int64(off))
+	if err != nil {
+		return nil, err
+	}
+	return unzip(buf, int(length))
+}
+
+// unzip decompresses the provided buffer into a buffer of size n.
+// Buffers whose size is n are considered uncompressed.
+func unzip(src []byte, n int) ([]byte, error) {
+	if len(src) == n {
+		return src, nil
+	}
+	dst := make([]byte, n)
+	err := rcompress.Decompress(dst, bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
@@ -0,0 +1,575 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rntup
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"unicode"
+
+	"go-hep.org/x/hep/groot/internal/xxh3"
+)
+
+// ReadVar describes a field to be read out of an RNTuple.
+type ReadVar struct {
+	Name  string      // name of the field to read. Sub-fields are separated by a '.'
+	Value interface{} // pointer to the value to fill
+}
+
+// NewReadVars returns the complete set of ReadVars to read all the
+// top-level fields contained in the provided RNTuple.
+func NewReadVars(nt *RNTuple) ([]ReadVar, error) {
+	desc, err := nt.Descriptor()
+	if err != nil {
+		return nil, err
+	}
+
+	var rvars []ReadVar
+	for i := range desc.fields {
+		fd := &desc.fields[i]
+		if fd.parent != fd.id {
+			continue
+		}
+		rt, err := desc.goType(fd)
+		if err != nil {
+			return nil, fmt.Errorf("rntup: could not create read-var for field %q: %w", fd.name, err)
+		}
+		rvars = append(rvars, ReadVar{
+			Name:  fd.name,
+			Value: reflect.New(rt).Interface(),
+		})
+	}
+	return rvars, nil
+}
+
+// Reader reads data from an RNTuple.
+type Reader struct {
+	r    io.ReaderAt
+	desc *Descriptor
+	beg  int64
+	end  int64
+
+	rvars  []ReadVar
+	fields []rfield
+	values []reflect.Value
+	cols   []*column
+
+	clu int // index of the currently loaded cluster
+}
+
+// ReadOption configures how an RNTuple should be traversed.
+type ReadOption func(r *Reader) error
+
+// WithRange specifies the half-open interval [beg, end) of entries
+// an RNTuple reader will read through.
+func WithRange(beg, end int64) ReadOption {
+	return func(r *Reader) error {
+		r.beg = beg
+		r.end = end
+		return nil
+	}
+}
+
+// NewReader creates a new RNTuple Reader from the provided RNTuple and
+// the set of read-variables into which data will be read.
+func NewReader(nt *RNTuple, rvars []ReadVar, opts ...ReadOption) (*Reader, error) {
+	desc, err := nt.Descriptor()
+	if err != nil {
+		return nil, fmt.Errorf("rntup: could not load RNTuple descriptor: %w", err)
+	}
+
+	r := Reader{
+		r:     nt.r,
+		desc:  desc,
+		beg:   0,
+		end:   -1,
+		rvars: rvars,
+		clu:   -1,
+	}
+
+	for i, opt := range opts {
+		err := opt(&r)
+		if err != nil {
+			return nil, fmt.Errorf("rntup: could not set reader option %d: %w", i, err)
+		}
+	}
+
+	n := desc.Entries()
+	if r.end < 0 {
+		r.end = n
+	}
+
+	switch {
+	case r.beg < 0:
+		return nil, fmt.Errorf("rntup: invalid event reader range [%d, %d) (start=%d < 0)", r.beg, r.end, r.beg)
+	case r.beg > r.end:
+		return nil, fmt.Errorf("rntup: invalid event reader range [%d, %d) (start=%d > end=%d)", r.beg, r.end, r.beg, r.end)
+	case r.end > n:
+		return nil, fmt.Errorf("rntup: invalid event reader range [%d, %d) (end=%d > entries=%d)", r.beg, r.end, r.end, n)
+	}
+
+	cols := make(map[uint32]*column)
+	for _, rvar := range rvars {
+		fd, ok := desc.lookup(rvar.Name)
+		if !ok {
+			return nil, fmt.Errorf("rntup: could not find field %q", rvar.Name)
+		}
+		rv := reflect.ValueOf(rvar.Value)
+		if rv.Kind() != reflect.Ptr || rv.IsNil() {
+			return nil, fmt.Errorf("rntup: read-var %q value is not a non-nil pointer (type=%T)", rvar.Name, rvar.Value)
+		}
+		rf, err := r.bind(fd, rv.Type().Elem(), cols)
+		if err != nil {
+			return nil, fmt.Errorf("rntup: could not bind read-var %q: %w", rvar.Name, err)
+		}
+		r.fields = append(r.fields, rf)
+		r.values = append(r.values, rv.Elem())
+	}
+
+	return &r, nil
+}
+
+// Close closes the Reader and releases the decoded column data.
+func (r *Reader) Close() error {
+	for _, col := range r.cols {
+		col.buf = nil
+		col.n = 0
+	}
+	r.clu = -1
+	return nil
+}
+
+// Entries returns the number of entries stored in the underlying RNTuple.
+func (r *Reader) Entries() int64 {
+	return r.desc.Entries()
+}
+
+// RCtx provides an entry-wise local context to the RNTuple Reader.
+type RCtx struct {
+	Entry int64 // Current RNTuple entry.
+}
+
+// Read will read data from the underlying RNTuple over the whole specified range.
+// Read calls the provided user function f for each entry successfully read.
+func (r *Reader) Read(f func(ctx RCtx) error) error {
+	var (
+		clu *clusterDesc
+		beg int64 // first entry of the current cluster
+		end int64 // last entry (exclusive) of the current cluster
+	)
+	for i := r.beg; i < r.end; i++ {
+		if clu == nil || i < beg || i >= end {
+			idx, err := r.findCluster(i)
+			if err != nil {
+				return fmt.Errorf("rntup: could not read entry %d: %w", i, err)
+			}
+			err = r.loadCluster(idx)
+			if err != nil {
+				return fmt.Errorf("rntup: could not read entry %d: %w", i, err)
+			}
+			clu = &r.desc.clusters[idx]
+			beg = int64(clu.first)
+			end = beg + int64(clu.nelems)
+		}
+
+		j := i - beg
+		for k, rf := range r.fields {
+			rf.read(j, r.values[k])
+		}
+
+		err := f(RCtx{Entry: i})
+		if err != nil {
+			return fmt.Errorf("rntup: could not process entry %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (r *Reader) findCluster(entry int64) (int, error) {
+	for i, clu := range r.desc.clusters {
+		beg := int64(clu.first)
+		end := beg + int64(clu.nelems)
+		if beg <= entry && entry < end {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("rntup: could not find cluster for entry %d", entry)
+}
+
+// loadCluster loads and decodes all the columns of the i-th cluster.
+func (r *Reader) loadCluster(i int) error {
+	if r.clu == i {
+		return nil
+	}
+	clu := &r.desc.clusters[i]
+	for _, col := range r.cols {
+		err := r.loadColumn(clu, col)
+		if err != nil {
+			return fmt.Errorf("rntup: could not load column %d (%v) of cluster %d: %w",
+				col.desc.id, col.desc.kind, i, err,
+			)
+		}
+	}
+	r.clu = i
+	return nil
+}
+
+func (r *Reader) loadColumn(clu *clusterDesc, col *column) error {
+	cid := int(col.desc.id)
+	if cid >= len(clu.cols) {
+		return fmt.Errorf("rntup: column missing from cluster")
+	}
+	cp := &clu.cols[cid]
+	if cp.supp {
+		return fmt.Errorf("rntup: suppressed columns not supported")
+	}
+
+	var n int64
+	for _, page := range cp.pages {
+		n += int64(page.nelems)
+	}
+
+	col.n = n
+	col.buf = col.buf[:0]
+	for _, page := range cp.pages {
+		size := int(page.loc.size)
+		nbytes := size
+		if page.checksum {
+			nbytes += 8
+		}
+		raw := make([]byte, nbytes)
+		_, err := r.r.ReadAt(raw, int64(page.loc.off))
+		if err != nil {
+			return fmt.Errorf("rntup: could not read page: %w", err)
+		}
+		if page.checksum {
+			var (
+				got  = binary.LittleEndian.Uint64(raw[size:])
+				want = xxh3.Sum64(raw[:size])
+			)
+			if got != want {
+				return fmt.Errorf("rntup: invalid page checksum (got=0x%x, want=0x%x)", got, want)
+			}
+			raw = raw[:size]
+		}
+		nelems := int(page.nelems)
+		raw, err = unzip(raw, pageSize(col.desc.kind, nelems))
+		if err != nil {
+			return fmt.Errorf("rntup: could not decompress page: %w", err)
+		}
+		buf, err := unpack(col.desc.kind, raw, nelems)
+		if err != nil {
+			return fmt.Errorf("rntup: could not decode page: %w", err)
+		}
+		col.buf = append(col.buf, buf...)
+	}
+	return nil
+}
+
+// column returns the column with the provided id, creating it if needed.
+func (r *Reader) column(cid uint32, cols map[uint32]*column) (*column, error) {
+	if col, ok := cols[cid]; ok {
+		return col, nil
+	}
+	cd := &r.desc.cols[cid]
+	info, ok := colInfos[cd.kind]
+	if !ok || info.kind == valUnknown {
+		return nil, fmt.Errorf("rntup: unsupported column type %v", cd.kind)
+	}
+	if cd.flags&colFlagDeferred != 0 && cd.first != 0 {
+		return nil, fmt.Errorf("rntup: deferred columns not supported")
+	}
+	col := &column{desc: cd, info: info}
+	cols[cid] = col
+	r.cols = append(r.cols, col)
+	return col, nil
+}
+
+// rfield reads the content of a field into a value.
+type rfield interface {
+	// read reads the i-th cluster-local element of the field into v.
+	read(i int64, v reflect.Value)
+}
+
+// bind creates a field reader for the provided field, filling values of type rt.
+func (r *Reader) bind(fd *fieldDesc, rt reflect.Type, cols map[uint32]*column) (rfield, error) {
+	if fd.flags&fieldFlagProjected != 0 {
+		return nil, fmt.Errorf("rntup: projected field %q not supported", fd.name)
+	}
+
+	switch fd.role {
+	case roleLeaf:
+		switch {
+		case fd.nrep > 0:
+			if rt.Kind() != reflect.Array || rt.Len() != int(fd.nrep) {
+				return nil, fmt.Errorf("rntup: field %q (type=%s) not compatible with Go type %v", fd.name, fd.typ, rt)
+			}
+			if len(fd.fields) != 1 {
+				return nil, fmt.Errorf("rntup: array field %q with invalid number of sub-fields (%d)", fd.name, len(fd.fields))
+			}
+			elem, err := r.bind(&r.desc.fields[fd.fields[0]], rt.Elem(), cols)
+			if err != nil {
+				return nil, err
+			}
+			return &arrayField{n: int64(fd.nrep), elem: elem}, nil
+
+		case fd.typ == "std::string":
+			if rt.Kind() != reflect.String {
+				return nil, fmt.Errorf("rntup: field %q (type=%s) not compatible with Go type %v", fd.name, fd.typ, rt)
+			}
+			if len(fd.cols) != 2 {
+				return nil, fmt.Errorf("rntup: string field %q with invalid number of columns (%d)", fd.name, len(fd.cols))
+			}
+			offs, err := r.indexColumn(fd.cols[0], cols)
+			if err != nil {
+				return nil, err
+			}
+			chars, err := r.column(fd.cols[1], cols)
+			if err != nil {
+				return nil, err
+			}
+			return &stringField{offs: offs, chars: chars}, nil
+
+		default:
+			want, err := r.desc.goType(fd)
+			if err != nil {
+				return nil, err
+			}
+			if rt.Kind() != want.Kind() {
+				return nil, fmt.Errorf("rntup: field %q (type=%s) not compatible with Go type %v", fd.name, fd.typ, rt)
+			}
+			if len(fd.cols) != 1 {
+				return nil, fmt.Errorf("rntup: field %q with invalid number of columns (%d)", fd.name, len(fd.cols))
+			}
+			col, err := r.column(fd.cols[0], cols)
+			if err != nil {
+				return nil, err
+			}
+			if col.info.kind == valIndex {
+				return nil, fmt.Errorf("rntup: field %q with invalid column type %v", fd.name, col.desc.kind)
+			}
+			return &leafField{col: col}, nil
+		}
+
+	case roleCollection:
+		if rt.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("rntup: field %q (type=%s) not compatible with Go type %v", fd.name, fd.typ, rt)
+		}
+		if len(fd.cols) != 1 || len(fd.fields) != 1 {
+			return nil, fmt.Errorf("rntup: collection field %q with invalid layout (columns=%d, sub-fields=%d)",
+				fd.name, len(fd.cols), len(fd.fields),
+			)
+		}
+		offs, err := r.indexColumn(fd.cols[0], cols)
+		if err != nil {
+			return nil, err
+		}
+		elem, err := r.bind(&r.desc.fields[fd.fields[0]], rt.Elem(), cols)
+		if err != nil {
+			return nil, err
+		}
+		return &sliceField{offs: offs, elem: elem}, nil
+
+	case roleRecord:
+		if rt.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("rntup: field %q (type=%s) not compatible with Go type %v", fd.name, fd.typ, rt)
+		}
+		rf := &recordField{}
+		for _, id := range fd.fields {
+			sub := &r.desc.fields[id]
+			idx := structFieldIndex(rt, sub.name)
+			if idx < 0 {
+				continue
+			}
+			elem, err := r.bind(sub, rt.Field(idx).Type, cols)
+			if err != nil {
+				return nil, err
+			}
+			rf.idx = append(rf.idx, idx)
+			rf.fields = append(rf.fields, elem)
+		}
+		return rf, nil
+	}
+
+	return nil, fmt.Errorf("rntup: field %q with structural role %v not supported", fd.name, fd.role)
+}
+
+func (r *Reader) indexColumn(cid uint32, cols map[uint32]*column) (*column, error) {
+	col, err := r.column(cid, cols)
+	if err != nil {
+		return nil, err
+	}
+	if col.info.kind != valIndex {
+		return nil, fmt.Errorf("rntup: invalid offset column type %v", col.desc.kind)
+	}
+	return col, nil
+}
+
+// structFieldIndex returns the index of the struct field corresponding to
+// the provided RNTuple field name, or -1.
+func structFieldIndex(rt reflect.Type, name string) int {
+	for i := 0; i < rt.NumField(); i++ {
+		ft := rt.Field(i)
+		if tag, ok := ft.Tag.Lookup("groot"); ok {
+			if tag == name {
+				return i
+			}
+			continue
+		}
+		if ft.Name == goName(name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// goName returns an exported Go identifier for the provided field name.
+func goName(name string) string {
+	if name == "" {
+		return "F"
+	}
+	rs := []rune(name)
+	if !unicode.IsLetter(rs[0]) {
+		return "F" + name
+	}
+	rs[0] = unicode.ToUpper(rs[0])
+	return string(rs)
+}
+
+type leafField struct {
+	col *column
+}
+
+func (rf *leafField) read(i int64, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(rf.col.uint(i) != 0)
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
+		v.SetInt(rf.col.int(i))
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+		v.SetUint(rf.col.uint(i))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(rf.col.float(i))
+	}
+}
+
+type stringField struct {
+	offs  *column
+	chars *column
+}
+
+func (rf *stringField) read(i int64, v reflect.Value) {
+	beg, end := rf.offs.offsets(i)
+	v.SetString(string(rf.chars.buf[beg:end]))
+}
+
+type sliceField struct {
+	offs *column
+	elem rfield
+}
+
+func (rf *sliceField) read(i int64, v reflect.Value) {
+	beg, end := rf.offs.offsets(i)
+	n := int(end - beg)
+	if v.Cap() < n {
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+	}
+	v.SetLen(n)
+	for j := 0; j < n; j++ {
+		rf.elem.read(int64(beg)+int64(j), v.Index(j))
+	}
+}
+
+type arrayField struct {
+	n    int64
+	elem rfield
+}
+
+func (rf *arrayField) read(i int64, v reflect.Value) {
+	for j := int64(0); j < rf.n; j++ {
+		rf.elem.read(i*rf.n+j, v.Index(int(j)))
+	}
+}
+
+type recordField struct {
+	idx    []int
+	fields []rfield
+}
+
+func (rf *recordField) read(i int64, v reflect.Value) {
+	for k, f := range rf.fields {
+		f.read(i, v.Field(rf.idx[k]))
+	}
+}
+
+var scalarTypes = map[string]reflect.Type{
+	"bool":          reflect.TypeOf(false),
+	"char":          reflect.TypeOf(int8(0)),
+	"std::byte":     reflect.TypeOf(uint8(0)),
+	"std::int8_t":   reflect.TypeOf(int8(0)),
+	"std::uint8_t":  reflect.TypeOf(uint8(0)),
+	"std::int16_t":  reflect.TypeOf(int16(0)),
+	"std::uint16_t": reflect.TypeOf(uint16(0)),
+	"std::int32_t":  reflect.TypeOf(int32(0)),
+	"std::uint32_t": reflect.TypeOf(uint32(0)),
+	"std::int64_t":  reflect.TypeOf(int64(0)),
+	"std::uint64_t": reflect.TypeOf(uint64(0)),
+	"float":         reflect.TypeOf(float32(0)),
+	"double":        reflect.TypeOf(float64(0)),
+	"std::string":   reflect.TypeOf(""),
+}
+
+// goType returns the Go type corresponding to the provided field.
+func (desc *Descriptor) goType(fd *fieldDesc) (reflect.Type, error) {
+	switch fd.role {
+	case roleLeaf:
+		if fd.nrep > 0 {
+			if len(fd.fields) != 1 {
+				return nil, fmt.Errorf("rntup: array field %q with invalid number of sub-fields (%d)", fd.name, len(fd.fields))
+			}
+			elem, err := desc.goType(&desc.fields[fd.fields[0]])
+			if err != nil {
+				return nil, err
+			}
+			return reflect.ArrayOf(int(fd.nrep), elem), nil
+		}
+		rt, ok := scalarTypes[fd.typ]
+		if !ok {
+			return nil, fmt.Errorf("rntup: field %q with unsupported type %q", fd.name, fd.typ)
+		}
+		return rt, nil
+
+	case roleCollection:
+		if len(fd.fields) != 1 {
+			return nil, fmt.Errorf("rntup: collection field %q with invalid number of sub-fields (%d)", fd.name, len(fd.fields))
+		}
+		elem, err := desc.goType(&desc.fields[fd.fields[0]])
+		if err != nil {
+			return nil, err
+		}
+		return reflect.SliceOf(elem), nil
+
+	case roleRecord:
+		fields := make([]reflect.StructField, len(fd.fields))
+		for i, id := range fd.fields {
+			sub := &desc.fields[id]
+			rt, err := desc.goType(sub)
+			if err != nil {
+				return nil, err
+			}
+			fields[i] = reflect.StructField{
+				Name: goName(sub.name),
+				Type: rt,
+				Tag:  reflect.StructTag(`groot:` + strconv.Quote(sub.name)),
+			}
+		}
+		return reflect.StructOf(fields), nil
+	}
+
+	return nil, fmt.Errorf("rntup: field %q with structural role %v not supported", fd.name, fd.role)
+}
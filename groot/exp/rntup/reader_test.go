@@ -0,0 +1,445 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rntup
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-hep.org/x/hep/groot/internal/rcompress"
+	"go-hep.org/x/hep/groot/internal/xxh3"
+)
+
+type testRecord struct {
+	X float64 `groot:"x"`
+	N uint8   `groot:"n"`
+}
+
+type testEvent struct {
+	I32 int32
+	F32 float32
+	F64 float64
+	Ok  bool
+	Str string
+	Vec []float32
+	Arr [2]int16
+	Rec testRecord
+}
+
+func newTestEvent(i int) testEvent {
+	evt := testEvent{
+		I32: int32(i - 50),
+		F32: float32(i) * 1.5,
+		F64: float64(i) / 3,
+		Ok:  i%3 == 0,
+		Str: strings.Repeat("x", i%4) + fmt.Sprint(i),
+		Vec: make([]float32, i%3),
+		Arr: [2]int16{int16(i), int16(-i)},
+		Rec: testRecord{X: float64(2 * i), N: uint8(i)},
+	}
+	for j := range evt.Vec {
+		evt.Vec[j] = float32(10*i + j)
+	}
+	return evt
+}
+
+var testFields = []fieldDesc{
+	{id: 0, parent: 0, role: roleLeaf, name: "i32", typ: "std::int32_t"},
+	{id: 1, parent: 1, role: roleLeaf, name: "f32", typ: "float"},
+	{id: 2, parent: 2, role: roleLeaf, name: "f64", typ: "double"},
+	{id: 3, parent: 3, role: roleLeaf, name: "ok", typ: "bool"},
+	{id: 4, parent: 4, role: roleLeaf, name: "str", typ: "std::string"},
+	{id: 5, parent: 5, role: roleCollection, name: "vec", typ: "std::vector<float>"},
+	{id: 6, parent: 5, role: roleLeaf, name: "_0", typ: "float"},
+	{id: 7, parent: 7, role: roleLeaf, name: "arr", typ: "std::array<std::int16_t,2>", flags: fieldFlagRepetitive, nrep: 2},
+	{id: 8, parent: 7, role: roleLeaf, name: "_0", typ: "std::int16_t"},
+	{id: 9, parent: 9, role: roleRecord, name: "rec", typ: "Rec"},
+	{id: 10, parent: 9, role: roleLeaf, name: "x", typ: "double"},
+	{id: 11, parent: 9, role: roleLeaf, name: "n", typ: "std::uint8_t"},
+}
+
+var testCols = []columnDesc{
+	{id: 0, kind: colSplitInt32, nbits: 32, field: 0},
+	{id: 1, kind: colSplitReal32, nbits: 32, field: 1},
+	{id: 2, kind: colReal64, nbits: 64, field: 2},
+	{id: 3, kind: colBit, nbits: 1, field: 3},
+	{id: 4, kind: colSplitIndex64, nbits: 64, field: 4},
+	{id: 5, kind: colChar, nbits: 8, field: 4},
+	{id: 6, kind: colSplitIndex32, nbits: 32, field: 5},
+	{id: 7, kind: colSplitReal32, nbits: 32, field: 6},
+	{id: 8, kind: colInt16, nbits: 16, field: 8},
+	{id: 9, kind: colSplitReal64, nbits: 64, field: 10},
+	{id: 10, kind: colUInt8, nbits: 8, field: 11},
+}
+
+// testColumns returns the in-memory content of the test columns for
+// the provided events.
+func testColumns(evts []testEvent) [][]byte {
+	var (
+		cols = make([][]byte, len(testCols))
+		buf  [8]byte
+		put  = func(i int, p []byte) { cols[i] = append(cols[i], p...) }
+		nstr uint64
+		nvec uint64
+	)
+	for _, evt := range evts {
+		binary.LittleEndian.PutUint32(buf[:], uint32(evt.I32))
+		put(0, buf[:4])
+		binary.LittleEndian.PutUint32(buf[:], math.Float32bits(evt.F32))
+		put(1, buf[:4])
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(evt.F64))
+		put(2, buf[:8])
+		switch evt.Ok {
+		case true:
+			put(3, []byte{1})
+		default:
+			put(3, []byte{0})
+		}
+		nstr += uint64(len(evt.Str))
+		binary.LittleEndian.PutUint64(buf[:], nstr)
+		put(4, buf[:8])
+		put(5, []byte(evt.Str))
+		nvec += uint64(len(evt.Vec))
+		binary.LittleEndian.PutUint64(buf[:], nvec)
+		put(6, buf[:8])
+		for _, v := range evt.Vec {
+			binary.LittleEndian.PutUint32(buf[:], math.Float32bits(v))
+			put(7, buf[:4])
+		}
+		for _, v := range evt.Arr {
+			binary.LittleEndian.PutUint16(buf[:], uint16(v))
+			put(8, buf[:2])
+		}
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(evt.Rec.X))
+		put(9, buf[:8])
+		put(10, []byte{evt.Rec.N})
+	}
+	return cols
+}
+
+// newTestNTuple creates an in-memory RNTuple holding the provided clusters of events.
+func newTestNTuple(t *testing.T, clusters [][]testEvent) *RNTuple {
+	t.Helper()
+
+	const (
+		pageSize = 64 // max number of elements per page
+		compr    = 101
+	)
+
+	var (
+		file  = make([]byte, 16) // leave some room so offsets are not zero.
+		write = func(p []byte) locator {
+			loc := locator{size: uint32(len(p)), off: uint64(len(file))}
+			file = append(file, p...)
+			return loc
+		}
+		zip = func(p []byte) []byte {
+			out, err := rcompress.Compress(nil, p, compr)
+			if err != nil {
+				t.Fatalf("could not compress: %+v", err)
+			}
+			return out
+		}
+	)
+
+	hdr := header{
+		name:   "evts",
+		desc:   "test events",
+		writer: "go-hep",
+		schema: schema{fields: testFields, cols: testCols},
+	}
+	var w wbuff
+	hdr.encode(&w)
+	henv, hdrsum := newEnvelope(envHeader, w.Bytes())
+	hloc := write(zip(henv))
+
+	pl := pageList{hdrsum: hdrsum}
+	var first uint64
+	for ic, evts := range clusters {
+		clu := clusterDesc{first: first, nelems: uint64(len(evts))}
+		for i, data := range testColumns(evts) {
+			var (
+				kind = testCols[i].kind
+				sz   = colInfos[kind].size
+				n    = len(data) / sz
+				cp   = colPages{compr: compr}
+			)
+			for beg := 0; beg < n; beg += pageSize {
+				end := beg + pageSize
+				if end > n {
+					end = n
+				}
+				page, err := pack(kind, data[beg*sz:end*sz], end-beg)
+				if err != nil {
+					t.Fatalf("could not pack page: %+v", err)
+				}
+				page = zip(page)
+				pd := pageDesc{nelems: uint32(end - beg), loc: write(page)}
+				if ic%2 == 1 {
+					var sum [8]byte
+					binary.LittleEndian.PutUint64(sum[:], xxh3.Sum64(page))
+					file = append(file, sum[:]...)
+					pd.checksum = true
+				}
+				cp.pages = append(cp.pages, pd)
+			}
+			clu.cols = append(clu.cols, cp)
+		}
+		pl.clusters = append(pl.clusters, clu)
+		first += uint64(len(evts))
+	}
+
+	w = wbuff{}
+	pl.encode(&w)
+	penv, _ := newEnvelope(envPageList, w.Bytes())
+	ploc := write(zip(penv))
+
+	ftr := footer{
+		hdrsum: hdrsum,
+		groups: []clusterGroup{{
+			minEntry:  0,
+			span:      first,
+			nclusters: uint32(len(clusters)),
+			pageList:  envLink{length: uint64(len(penv)), loc: ploc},
+		}},
+	}
+	w = wbuff{}
+	ftr.encode(&w)
+	fenv, _ := newEnvelope(envFooter, w.Bytes())
+	floc := write(zip(fenv))
+
+	return &RNTuple{
+		epoch:      specEpoch,
+		header:     envSpan{seek: hloc.off, nbytes: uint64(hloc.size), length: uint64(len(henv))},
+		footer:     envSpan{seek: floc.off, nbytes: uint64(floc.size), length: uint64(len(fenv))},
+		maxKeySize: defaultMaxKeySize,
+		r:          bytes.NewReader(file),
+	}
+}
+
+func newTestClusters(sizes ...int) [][]testEvent {
+	var (
+		clusters = make([][]testEvent, len(sizes))
+		ievt     = 0
+	)
+	for i, n := range sizes {
+		for j := 0; j < n; j++ {
+			clusters[i] = append(clusters[i], newTestEvent(ievt))
+			ievt++
+		}
+	}
+	return clusters
+}
+
+func TestDescriptor(t *testing.T) {
+	nt := newTestNTuple(t, newTestClusters(3, 200, 5))
+	desc, err := nt.Descriptor()
+	if err != nil {
+		t.Fatalf("could not load descriptor: %+v", err)
+	}
+
+	if got, want := desc.Name(), "evts"; got != want {
+		t.Fatalf("invalid name: got=%q, want=%q", got, want)
+	}
+	if got, want := desc.Description(), "test events"; got != want {
+		t.Fatalf("invalid description: got=%q, want=%q", got, want)
+	}
+	if got, want := desc.Entries(), int64(208); got != want {
+		t.Fatalf("invalid entries: got=%d, want=%d", got, want)
+	}
+
+	want := []Field{
+		{Name: "i32", Type: "std::int32_t"},
+		{Name: "f32", Type: "float"},
+		{Name: "f64", Type: "double"},
+		{Name: "ok", Type: "bool"},
+		{Name: "str", Type: "std::string"},
+		{Name: "vec", Type: "std::vector<float>", Fields: []Field{{Name: "_0", Type: "float"}}},
+		{Name: "arr", Type: "std::array<std::int16_t,2>", Fields: []Field{{Name: "_0", Type: "std::int16_t"}}},
+		{Name: "rec", Type: "Rec", Fields: []Field{{Name: "x", Type: "double"}, {Name: "n", Type: "std::uint8_t"}}},
+	}
+	if got := desc.Fields(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid fields:\ngot= %+v\nwant=%+v", got, want)
+	}
+}
+
+func TestReader(t *testing.T) {
+	clusters := newTestClusters(3, 200, 5)
+	nt := newTestNTuple(t, clusters)
+
+	var evt testEvent
+	rvars := []ReadVar{
+		{Name: "i32", Value: &evt.I32},
+		{Name: "f32", Value: &evt.F32},
+		{Name: "f64", Value: &evt.F64},
+		{Name: "ok", Value: &evt.Ok},
+		{Name: "str", Value: &evt.Str},
+		{Name: "vec", Value: &evt.Vec},
+		{Name: "arr", Value: &evt.Arr},
+		{Name: "rec", Value: &evt.Rec},
+	}
+
+	for _, tc := range []struct {
+		beg, end int64
+	}{
+		{0, -1},
+		{0, 3},
+		{2, 4},
+		{150, 208},
+		{205, 208},
+	} {
+		t.Run(fmt.Sprintf("[%d,%d)", tc.beg, tc.end), func(t *testing.T) {
+			r, err := NewReader(nt, rvars, WithRange(tc.beg, tc.end))
+			if err != nil {
+				t.Fatalf("could not create reader: %+v", err)
+			}
+			defer r.Close()
+
+			end := tc.end
+			if end < 0 {
+				end = r.Entries()
+			}
+
+			n := tc.beg
+			err = r.Read(func(ctx RCtx) error {
+				if ctx.Entry != n {
+					return fmt.Errorf("invalid entry: got=%d, want=%d", ctx.Entry, n)
+				}
+				want := newTestEvent(int(n))
+				if len(want.Vec) == 0 {
+					want.Vec = evt.Vec[:0]
+				}
+				if !reflect.DeepEqual(evt, want) {
+					return fmt.Errorf("invalid event %d:\ngot= %+v\nwant=%+v", n, evt, want)
+				}
+				n++
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("could not read entries: %+v", err)
+			}
+			if n != end {
+				t.Fatalf("invalid number of entries: got=%d, want=%d", n, end)
+			}
+		})
+	}
+}
+
+func TestReaderSubFields(t *testing.T) {
+	nt := newTestNTuple(t, newTestClusters(10))
+
+	var (
+		x float64
+		n uint8
+	)
+	r, err := NewReader(nt, []ReadVar{
+		{Name: "rec.x", Value: &x},
+		{Name: "rec.n", Value: &n},
+	})
+	if err != nil {
+		t.Fatalf("could not create reader: %+v", err)
+	}
+	defer r.Close()
+
+	err = r.Read(func(ctx RCtx) error {
+		if got, want := x, float64(2*ctx.Entry); got != want {
+			return fmt.Errorf("invalid rec.x value: got=%v, want=%v", got, want)
+		}
+		if got, want := n, uint8(ctx.Entry); got != want {
+			return fmt.Errorf("invalid rec.n value: got=%v, want=%v", got, want)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("could not read entries: %+v", err)
+	}
+}
+
+func TestNewReadVars(t *testing.T) {
+	nt := newTestNTuple(t, newTestClusters(4))
+	rvars, err := NewReadVars(nt)
+	if err != nil {
+		t.Fatalf("could not create read-vars: %+v", err)
+	}
+
+	r, err := NewReader(nt, rvars)
+	if err != nil {
+		t.Fatalf("could not create reader: %+v", err)
+	}
+	defer r.Close()
+
+	err = r.Read(func(ctx RCtx) error {
+		want := newTestEvent(int(ctx.Entry))
+		if got, want := *rvars[0].Value.(*int32), want.I32; got != want {
+			return fmt.Errorf("invalid i32: got=%v, want=%v", got, want)
+		}
+		if got, want := *rvars[4].Value.(*string), want.Str; got != want {
+			return fmt.Errorf("invalid str: got=%q, want=%q", got, want)
+		}
+		if got, want := *rvars[6].Value.(*[2]int16), want.Arr; got != want {
+			return fmt.Errorf("invalid arr: got=%v, want=%v", got, want)
+		}
+		rec := reflect.ValueOf(rvars[7].Value).Elem()
+		if got, want := rec.FieldByName("X").Float(), want.Rec.X; got != want {
+			return fmt.Errorf("invalid rec.x: got=%v, want=%v", got, want)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("could not read entries: %+v", err)
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	nt := newTestNTuple(t, newTestClusters(4))
+
+	for _, tc := range []struct {
+		name  string
+		rvars []ReadVar
+		opts  []ReadOption
+		err   string
+	}{
+		{
+			name:  "not-there",
+			rvars: []ReadVar{{Name: "not-there", Value: new(int32)}},
+			err:   `rntup: could not find field "not-there"`,
+		},
+		{
+			name:  "not-ptr",
+			rvars: []ReadVar{{Name: "i32", Value: int32(0)}},
+			err:   `rntup: read-var "i32" value is not a non-nil pointer (type=int32)`,
+		},
+		{
+			name:  "type-mismatch",
+			rvars: []ReadVar{{Name: "f64", Value: new(int32)}},
+			err:   `rntup: could not bind read-var "f64": rntup: field "f64" (type=double) not compatible with Go type int32`,
+		},
+		{
+			name:  "array-mismatch",
+			rvars: []ReadVar{{Name: "arr", Value: new([3]int16)}},
+			err:   `rntup: could not bind read-var "arr": rntup: field "arr" (type=std::array<std::int16_t,2>) not compatible with Go type [3]int16`,
+		},
+		{
+			name: "range",
+			opts: []ReadOption{WithRange(2, 10)},
+			err:  `rntup: invalid event reader range [2, 10) (end=10 > entries=4)`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewReader(nt, tc.rvars, tc.opts...)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if got, want := err.Error(), tc.err; got != want {
+				t.Fatalf("invalid error:\ngot= %v\nwant=%v", got, want)
+			}
+		})
+	}
+}
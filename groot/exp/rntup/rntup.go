@@ -20,6 +20,8 @@ type span struct {
 	length uint32
 }
 
+// NTuple is the anchor of a pre-release ROOT::Experimental::RNTuple.
+// Use RNTuple to read RNTuples written with the stable binary format.
 type NTuple struct {
 	rvers uint32
 	size  uint32
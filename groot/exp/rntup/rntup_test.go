@@ -5,6 +5,10 @@
 package rntup
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -44,6 +48,38 @@ func TestNTuple(t *testing.T) {
 	}
 }
 
+func TestRNTuple(t *testing.T) {
+	want := &RNTuple{
+		epoch:      specEpoch,
+		header:     envSpan{1, 2, 3},
+		footer:     envSpan{4, 5, 6},
+		maxKeySize: defaultMaxKeySize,
+	}
+
+	wbuf := rbytes.NewWBuffer(nil, nil, 0, nil)
+	_, err := want.MarshalROOT(wbuf)
+	if err != nil {
+		t.Fatalf("could not marshal: %+v", err)
+	}
+
+	var got RNTuple
+	err = got.UnmarshalROOT(rbytes.NewRBuffer(wbuf.Bytes(), nil, 0, nil))
+	if err != nil {
+		t.Fatalf("could not unmarshal: %+v", err)
+	}
+
+	if !reflect.DeepEqual(&got, want) {
+		t.Fatalf("invalid r/w round-trip:\ngot= %#v\nwant=%#v", &got, want)
+	}
+
+	raw := wbuf.Bytes()
+	raw[len(raw)-1] ^= 0xff // corrupt checksum
+	err = new(RNTuple).UnmarshalROOT(rbytes.NewRBuffer(raw, nil, 0, nil))
+	if err == nil {
+		t.Fatalf("expected a checksum error")
+	}
+}
+
 func TestReadNTuple(t *testing.T) {
 	f, err := riofs.Open("../../testdata/ntpl001_staff.root")
 	if err != nil {
@@ -85,3 +121,124 @@ func TestReadNTuple(t *testing.T) {
 		t.Fatalf("error:\ngot= %v\nwant=%v", got, want)
 	}
 }
+
+func TestReadNTupleFromROOT(t *testing.T) {
+	if !rtests.HasROOT {
+		t.Skip("ROOT not installed")
+	}
+
+	const nevts = 1000
+
+	tmp := t.TempDir()
+	fname := filepath.Join(tmp, "ntuple.root")
+
+	code := `#include <array>
+#include <cstdint>
+#include <iostream>
+#include <string>
+#include <vector>
+
+#include "RVersion.h"
+
+#if ROOT_VERSION_CODE >= ROOT_VERSION(6,34,0)
+#include <ROOT/RNTupleModel.hxx>
+#include <ROOT/RNTupleWriter.hxx>
+#if ROOT_VERSION_CODE < ROOT_VERSION(6,35,0)
+using ROOT::Experimental::RNTupleModel;
+using ROOT::Experimental::RNTupleWriter;
+#else
+using ROOT::RNTupleModel;
+using ROOT::RNTupleWriter;
+#endif
+#endif
+
+void gen(const char *fname, int n) {
+#if ROOT_VERSION_CODE < ROOT_VERSION(6,34,0)
+	std::cerr << "RNTuple format v1 needs ROOT >= 6.34\n";
+#else
+	auto model = RNTupleModel::Create();
+	auto i32 = model->MakeField<std::int32_t>("i32");
+	auto f32 = model->MakeField<float>("f32");
+	auto f64 = model->MakeField<double>("f64");
+	auto ok  = model->MakeField<bool>("ok");
+	auto str = model->MakeField<std::string>("str");
+	auto vec = model->MakeField<std::vector<float>>("vec");
+	auto arr = model->MakeField<std::array<std::int16_t,2>>("arr");
+
+	auto w = RNTupleWriter::Recreate(std::move(model), "ntpl", fname);
+	for (int i = 0; i < n; i++) {
+		*i32 = i - 50;
+		*f32 = float(i) * 1.5;
+		*f64 = double(i) / 3;
+		*ok  = i%3 == 0;
+		*str = std::string(i%4, 'x') + std::to_string(i);
+		vec->resize(i%3);
+		for (int j = 0; j < i%3; j++) {
+			(*vec)[j] = float(10*i + j);
+		}
+		(*arr)[0] = i;
+		(*arr)[1] = -i;
+		w->Fill();
+	}
+#endif
+}
+`
+	out, err := rtests.RunCxxROOT("gen", []byte(code), fname, nevts)
+	if err != nil {
+		t.Fatalf("could not run C++ ROOT: %+v\noutput:\n%s", err, out)
+	}
+	if _, err := os.Stat(fname); errors.Is(err, os.ErrNotExist) {
+		t.Skipf("ROOT could not create RNTuple:\n%s", out)
+	}
+
+	f, err := riofs.Open(fname)
+	if err != nil {
+		t.Fatalf("could not open file: %+v", err)
+	}
+	defer f.Close()
+
+	obj, err := f.Get("ntpl")
+	if err != nil {
+		t.Fatalf("could not get RNTuple: %+v", err)
+	}
+	nt, ok := obj.(*RNTuple)
+	if !ok {
+		t.Fatalf("invalid object type %T", obj)
+	}
+
+	var evt testEvent
+	rvars := []ReadVar{
+		{Name: "i32", Value: &evt.I32},
+		{Name: "f32", Value: &evt.F32},
+		{Name: "f64", Value: &evt.F64},
+		{Name: "ok", Value: &evt.Ok},
+		{Name: "str", Value: &evt.Str},
+		{Name: "vec", Value: &evt.Vec},
+		{Name: "arr", Value: &evt.Arr},
+	}
+
+	r, err := NewReader(nt, rvars)
+	if err != nil {
+		t.Fatalf("could not create reader: %+v", err)
+	}
+	defer r.Close()
+
+	if got, want := r.Entries(), int64(nevts); got != want {
+		t.Fatalf("invalid number of entries: got=%d, want=%d", got, want)
+	}
+
+	err = r.Read(func(ctx RCtx) error {
+		want := newTestEvent(int(ctx.Entry))
+		want.Rec = evt.Rec
+		if len(want.Vec) == 0 {
+			want.Vec = evt.Vec[:0]
+		}
+		if !reflect.DeepEqual(evt, want) {
+			return fmt.Errorf("invalid event %d:\ngot= %+v\nwant=%+v", ctx.Entry, evt, want)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("could not read entries: %+v", err)
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rntup
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"go-hep.org/x/hep/groot/internal/xxh3"
+)
+
+// Envelope types.
+const (
+	envHeader   = 0x01
+	envFooter   = 0x02
+	envPageList = 0x03
+)
+
+const (
+	envPreambleLen = 8 // envelope type+length
+	envChecksumLen = 8 // XXH3 checksum of the envelope
+)
+
+// locator describes a blob of data stored in the file.
+type locator struct {
+	size uint32 // size of the blob on disk
+	off  uint64 // offset of the blob in the file
+}
+
+// envLink describes a (possibly compressed) envelope stored in the file.
+type envLink struct {
+	length uint64 // size of the uncompressed envelope
+	loc    locator
+}
+
+// rbuff is a little-endian reader of RNTuple metadata.
+type rbuff struct {
+	p   []byte
+	c   int
+	err error
+}
+
+func newRBuff(p []byte) *rbuff {
+	return &rbuff{p: p}
+}
+
+func (r *rbuff) Err() error { return r.err }
+func (r *rbuff) Len() int   { return len(r.p) - r.c }
+
+func (r *rbuff) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || r.c+n > len(r.p) {
+		r.err = fmt.Errorf("rntup: read out of bounds (pos=%d, n=%d, len=%d): %w", r.c, n, len(r.p), io.ErrUnexpectedEOF)
+		return nil
+	}
+	p := r.p[r.c : r.c+n]
+	r.c += n
+	return p
+}
+
+func (r *rbuff) seek(pos int) {
+	if r.err != nil {
+		return
+	}
+	if pos < r.c || pos > len(r.p) {
+		r.err = fmt.Errorf("rntup: invalid frame end position (pos=%d, cur=%d, len=%d)", pos, r.c, len(r.p))
+		return
+	}
+	r.c = pos
+}
+
+func (r *rbuff) u16() uint16 {
+	p := r.next(2)
+	if p == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(p)
+}
+
+func (r *rbuff) u32() uint32 {
+	p := r.next(4)
+	if p == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(p)
+}
+
+func (r *rbuff) u64() uint64 {
+	p := r.next(8)
+	if p == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(p)
+}
+
+func (r *rbuff) i32() int32   { return int32(r.u32()) }
+func (r *rbuff) i64() int64   { return int64(r.u64()) }
+func (r *rbuff) f64() float64 { return math.Float64frombits(r.u64()) }
+
+func (r *rbuff) str() string {
+	n := r.u32()
+	return string(r.next(int(n)))
+}
+
+// flags reads a list of feature flags and returns the first word.
+func (r *rbuff) flags() uint64 {
+	v := r.u64()
+	for w := v; w&(1<<63) != 0 && r.err == nil; {
+		w = r.u64()
+	}
+	return v &^ (1 << 63)
+}
+
+func (r *rbuff) locator() locator {
+	n := r.i32()
+	if n < 0 {
+		if r.err == nil {
+			r.err = fmt.Errorf("rntup: non-standard locators are not supported (type=%d)", -n)
+		}
+		return locator{}
+	}
+	return locator{size: uint32(n), off: r.u64()}
+}
+
+func (r *rbuff) envLink() envLink {
+	var link envLink
+	link.length = r.u64()
+	link.loc = r.locator()
+	return link
+}
+
+// record reads the preamble of a record frame and returns the position
+// of the end of the frame.
+func (r *rbuff) record() int {
+	beg := r.c
+	n := r.i64()
+	if r.err != nil {
+		return r.c
+	}
+	if n < 8 {
+		r.err = fmt.Errorf("rntup: invalid record frame size %d at %d", n, beg)
+		return r.c
+	}
+	return beg + int(n)
+}
+
+// list reads the preamble of a list frame and returns the position of the
+// end of the frame together with the number of items in the list.
+func (r *rbuff) list() (end, n int) {
+	beg := r.c
+	sz := r.i64()
+	if r.err != nil {
+		return r.c, 0
+	}
+	if sz >= 0 || -sz < 12 {
+		r.err = fmt.Errorf("rntup: invalid list frame size %d at %d", sz, beg)
+		return r.c, 0
+	}
+	n = int(r.u32())
+	return beg + int(-sz), n
+}
+
+// wbuff is a little-endian writer of RNTuple metadata.
+type wbuff struct {
+	p []byte
+}
+
+func (w *wbuff) Bytes() []byte { return w.p }
+func (w *wbuff) pos() int      { return len(w.p) }
+
+func (w *wbuff) grow(n int) []byte {
+	w.p = append(w.p, make([]byte, n)...)
+	return w.p[len(w.p)-n:]
+}
+
+func (w *wbuff) u16(v uint16) { binary.LittleEndian.PutUint16(w.grow(2), v) }
+func (w *wbuff) u32(v uint32) { binary.LittleEndian.PutUint32(w.grow(4), v) }
+func (w *wbuff) u64(v uint64) { binary.LittleEndian.PutUint64(w.grow(8), v) }
+func (w *wbuff) i32(v int32)  { w.u32(uint32(v)) }
+func (w *wbuff) i64(v int64)  { w.u64(uint64(v)) }
+
+func (w *wbuff) f64(v float64) { w.u64(math.Float64bits(v)) }
+
+func (w *wbuff) str(v string) {
+	w.u32(uint32(len(v)))
+	w.p = append(w.p, v...)
+}
+
+func (w *wbuff) flags(v uint64) { w.u64(v &^ (1 << 63)) }
+
+func (w *wbuff) locator(loc locator) {
+	w.i32(int32(loc.size))
+	w.u64(loc.off)
+}
+
+func (w *wbuff) envLink(link envLink) {
+	w.u64(link.length)
+	w.locator(link.loc)
+}
+
+// record starts a record frame and returns the position of its preamble.
+func (w *wbuff) record() int {
+	pos := w.pos()
+	w.i64(0)
+	return pos
+}
+
+// list starts a list frame with n items and returns the position of its preamble.
+func (w *wbuff) list(n int) int {
+	pos := w.pos()
+	w.i64(0)
+	w.u32(uint32(n))
+	return pos
+}
+
+// endRecord finalizes the record frame started at pos.
+func (w *wbuff) endRecord(pos int) {
+	binary.LittleEndian.PutUint64(w.p[pos:], uint64(w.pos()-pos))
+}
+
+// endList finalizes the list frame started at pos.
+func (w *wbuff) endList(pos int) {
+	binary.LittleEndian.PutUint64(w.p[pos:], uint64(-int64(w.pos()-pos)))
+}
+
+// openEnvelope checks the integrity of the provided envelope and returns
+// a reader of its payload together with its checksum.
+func openEnvelope(p []byte, typ uint16) (*rbuff, uint64, error) {
+	if len(p) < envPreambleLen+envChecksumLen {
+		return nil, 0, fmt.Errorf("rntup: envelope too small (len=%d)", len(p))
+	}
+	var (
+		preamble = binary.LittleEndian.Uint64(p)
+		etype    = uint16(preamble & 0xffff)
+		elen     = preamble >> 16
+	)
+	if etype != typ {
+		return nil, 0, fmt.Errorf("rntup: invalid envelope type (got=0x%02x, want=0x%02x)", etype, typ)
+	}
+	if elen != uint64(len(p)) {
+		return nil, 0, fmt.Errorf("rntup: invalid envelope length (got=%d, want=%d)", elen, len(p))
+	}
+	var (
+		end  = len(p) - envChecksumLen
+		sum  = binary.LittleEndian.Uint64(p[end:])
+		want = xxh3.Sum64(p[:end])
+	)
+	if sum != want {
+		return nil, 0, fmt.Errorf("rntup: invalid envelope checksum (got=0x%x, want=0x%x)", sum, want)
+	}
+	return newRBuff(p[envPreambleLen:end]), sum, nil
+}
+
+// newEnvelope creates an envelope of the given type, with the provided
+// payload, and returns it together with its checksum.
+func newEnvelope(typ uint16, payload []byte) ([]byte, uint64) {
+	n := envPreambleLen + len(payload) + envChecksumLen
+	p := make([]byte, n)
+	binary.LittleEndian.PutUint64(p, uint64(typ)|uint64(n)<<16)
+	copy(p[envPreambleLen:], payload)
+	sum := xxh3.Sum64(p[:n-envChecksumLen])
+	binary.LittleEndian.PutUint64(p[n-envChecksumLen:], sum)
+	return p, sum
+}
@@ -0,0 +1,591 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rntup
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+
+	"go-hep.org/x/hep/groot/internal/rcompress"
+	"go-hep.org/x/hep/groot/internal/xxh3"
+	"go-hep.org/x/hep/groot/riofs"
+)
+
+const (
+	defaultPageSize    = 64 * 1024        // default target size (in bytes) of uncompressed pages
+	defaultClusterSize = 50 * 1024 * 1024 // default target size (in bytes) of uncompressed clusters
+
+	blobClass = "RBlob"
+	writerID  = "go-hep.org/x/hep/groot/exp/rntup"
+)
+
+// WriteVar describes a field to be written to an RNTuple.
+type WriteVar struct {
+	Name  string      // name of the field
+	Value interface{} // pointer to the value to write
+}
+
+// WriteOption configures how an RNTuple should be created.
+type WriteOption func(opt *wopt) error
+
+type wopt struct {
+	desc     string // description of the RNTuple
+	compress int32  // compression algorithm name and compression level
+	page     int    // target size of uncompressed pages
+	cluster  int    // target size of uncompressed clusters
+}
+
+// WithDescription sets the description of the RNTuple.
+func WithDescription(desc string) WriteOption {
+	return func(opt *wopt) error {
+		opt.desc = desc
+		return nil
+	}
+}
+
+// WithLZ4 configures an RNTuple to use LZ4 as a compression mechanism.
+func WithLZ4(level int) WriteOption {
+	return func(opt *wopt) error {
+		opt.compress = rcompress.Settings{Alg: rcompress.LZ4, Lvl: level}.Compression()
+		return nil
+	}
+}
+
+// WithLZMA configures an RNTuple to use LZMA as a compression mechanism.
+func WithLZMA(level int) WriteOption {
+	return func(opt *wopt) error {
+		opt.compress = rcompress.Settings{Alg: rcompress.LZMA, Lvl: level}.Compression()
+		return nil
+	}
+}
+
+// WithZlib configures an RNTuple to use zlib as a compression mechanism.
+func WithZlib(level int) WriteOption {
+	return func(opt *wopt) error {
+		opt.compress = rcompress.Settings{Alg: rcompress.ZLIB, Lvl: level}.Compression()
+		return nil
+	}
+}
+
+// WithZstd configures an RNTuple to use zstd as a compression mechanism.
+func WithZstd(level int) WriteOption {
+	return func(opt *wopt) error {
+		opt.compress = rcompress.Settings{Alg: rcompress.ZSTD, Lvl: level}.Compression()
+		return nil
+	}
+}
+
+// WithoutCompression configures an RNTuple to not use any compression mechanism.
+func WithoutCompression() WriteOption {
+	return func(opt *wopt) error {
+		opt.compress = 0
+		return nil
+	}
+}
+
+// WithPageSize sets the target size (in bytes) of uncompressed pages.
+func WithPageSize(size int) WriteOption {
+	return func(opt *wopt) error {
+		if size <= 0 {
+			return fmt.Errorf("rntup: invalid page size %d", size)
+		}
+		opt.page = size
+		return nil
+	}
+}
+
+// WithClusterSize sets the target size (in bytes) of uncompressed clusters.
+func WithClusterSize(size int) WriteOption {
+	return func(opt *wopt) error {
+		if size <= 0 {
+			return fmt.Errorf("rntup: invalid cluster size %d", size)
+		}
+		opt.cluster = size
+		return nil
+	}
+}
+
+// Writer writes data to an RNTuple.
+type Writer struct {
+	dir  riofs.Directory
+	name string
+	cfg  wopt
+
+	hdr    header
+	hdrsum uint64
+	anchor RNTuple
+
+	fields []wfield
+	values []reflect.Value
+	cols   []*wcolumn
+
+	nentries uint64 // number of entries written so far
+	first    uint64 // first entry of the current cluster
+	csize    int    // size (in bytes) of the current cluster
+
+	clusters []clusterDesc
+	closed   bool
+}
+
+// NewWriter creates a new RNTuple with the provided name in the provided
+// directory, together with the set of variables from which data will be
+// written.
+func NewWriter(dir riofs.Directory, name string, wvars []WriteVar, opts ...WriteOption) (*Writer, error) {
+	if dir == nil {
+		return nil, fmt.Errorf("rntup: missing parent directory")
+	}
+	if len(wvars) == 0 {
+		return nil, fmt.Errorf("rntup: empty list of write-vars")
+	}
+
+	w := &Writer{
+		dir:  dir,
+		name: name,
+		cfg: wopt{
+			compress: fileOf(dir).Compression(),
+			page:     defaultPageSize,
+			cluster:  defaultClusterSize,
+		},
+	}
+
+	for _, opt := range opts {
+		err := opt(&w.cfg)
+		if err != nil {
+			return nil, fmt.Errorf("rntup: could not configure RNTuple writer: %w", err)
+		}
+	}
+
+	w.hdr = header{
+		name:   name,
+		desc:   w.cfg.desc,
+		writer: writerID,
+	}
+
+	for _, wvar := range wvars {
+		rv := reflect.ValueOf(wvar.Value)
+		if rv.Kind() != reflect.Ptr || rv.IsNil() {
+			return nil, fmt.Errorf("rntup: write-var %q value is not a non-nil pointer (type=%T)", wvar.Name, wvar.Value)
+		}
+		if wvar.Name == "" {
+			return nil, fmt.Errorf("rntup: write-var with empty name")
+		}
+		wf, err := w.addField(wvar.Name, rv.Type().Elem(), -1)
+		if err != nil {
+			return nil, fmt.Errorf("rntup: could not create field %q: %w", wvar.Name, err)
+		}
+		w.fields = append(w.fields, wf)
+		w.values = append(w.values, rv.Elem())
+	}
+
+	var wbuf wbuff
+	w.hdr.encode(&wbuf)
+	env, sum := newEnvelope(envHeader, wbuf.Bytes())
+	loc, err := w.writeBlob(w.zip(env))
+	if err != nil {
+		return nil, fmt.Errorf("rntup: could not write header: %w", err)
+	}
+	w.hdrsum = sum
+	w.anchor = RNTuple{
+		epoch:      specEpoch,
+		major:      specMajor,
+		minor:      specMinor,
+		patch:      specPatch,
+		header:     envSpan{seek: loc.off, nbytes: uint64(loc.size), length: uint64(len(env))},
+		maxKeySize: defaultMaxKeySize,
+	}
+
+	return w, nil
+}
+
+// Write writes the event data to ROOT storage and returns the number
+// of bytes (before compression, if any) written.
+func (w *Writer) Write() (int, error) {
+	if w.closed {
+		return 0, fmt.Errorf("rntup: write to closed RNTuple writer")
+	}
+
+	var n int
+	for i, wf := range w.fields {
+		n += wf.write(w.values[i])
+	}
+	w.nentries++
+	w.csize += n
+
+	for _, col := range w.cols {
+		if len(col.buf) >= w.cfg.page {
+			err := w.flushPage(col)
+			if err != nil {
+				return n, fmt.Errorf("rntup: could not flush page: %w", err)
+			}
+		}
+	}
+
+	if w.csize >= w.cfg.cluster {
+		err := w.commitCluster()
+		if err != nil {
+			return n, fmt.Errorf("rntup: could not commit cluster: %w", err)
+		}
+	}
+
+	return n, nil
+}
+
+// Close writes the remaining data and the RNTuple metadata to ROOT storage.
+// Close does not close the underlying ROOT file.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	if w.nentries > w.first {
+		err := w.commitCluster()
+		if err != nil {
+			return fmt.Errorf("rntup: could not commit cluster: %w", err)
+		}
+	}
+
+	var wbuf wbuff
+	pl := pageList{hdrsum: w.hdrsum, clusters: w.clusters}
+	pl.encode(&wbuf)
+	penv, _ := newEnvelope(envPageList, wbuf.Bytes())
+	ploc, err := w.writeBlob(w.zip(penv))
+	if err != nil {
+		return fmt.Errorf("rntup: could not write page list: %w", err)
+	}
+
+	ftr := footer{
+		hdrsum: w.hdrsum,
+		groups: []clusterGroup{{
+			minEntry:  0,
+			span:      w.nentries,
+			nclusters: uint32(len(w.clusters)),
+			pageList:  envLink{length: uint64(len(penv)), loc: ploc},
+		}},
+	}
+	wbuf = wbuff{}
+	ftr.encode(&wbuf)
+	fenv, _ := newEnvelope(envFooter, wbuf.Bytes())
+	floc, err := w.writeBlob(w.zip(fenv))
+	if err != nil {
+		return fmt.Errorf("rntup: could not write footer: %w", err)
+	}
+	w.anchor.footer = envSpan{seek: floc.off, nbytes: uint64(floc.size), length: uint64(len(fenv))}
+
+	err = w.dir.Put(w.name, &w.anchor)
+	if err != nil {
+		return fmt.Errorf("rntup: could not write RNTuple anchor: %w", err)
+	}
+
+	return nil
+}
+
+// commitCluster flushes all the pending pages and records the current cluster.
+func (w *Writer) commitCluster() error {
+	clu := clusterDesc{
+		first:  w.first,
+		nelems: w.nentries - w.first,
+		cols:   make([]colPages, len(w.cols)),
+	}
+	for i, col := range w.cols {
+		if col.n > 0 {
+			err := w.flushPage(col)
+			if err != nil {
+				return err
+			}
+		}
+		clu.cols[i] = colPages{
+			pages: col.pages,
+			first: int64(col.first),
+			compr: uint32(w.cfg.compress),
+		}
+		col.first += col.nelems
+		col.nelems = 0
+		col.pages = nil
+		col.offset = 0
+	}
+	w.clusters = append(w.clusters, clu)
+	w.first = w.nentries
+	w.csize = 0
+	return nil
+}
+
+// flushPage encodes, compresses and writes the pending elements of
+// the provided column to a new page.
+func (w *Writer) flushPage(col *wcolumn) error {
+	page, err := pack(col.kind, col.buf, col.n)
+	if err != nil {
+		return err
+	}
+	page = w.zip(page)
+
+	blob := make([]byte, len(page)+8)
+	copy(blob, page)
+	binary.LittleEndian.PutUint64(blob[len(page):], xxh3.Sum64(page))
+
+	loc, err := w.writeBlob(blob)
+	if err != nil {
+		return err
+	}
+	loc.size = uint32(len(page))
+
+	col.pages = append(col.pages, pageDesc{
+		nelems:   uint32(col.n),
+		checksum: true,
+		loc:      loc,
+	})
+	col.nelems += uint64(col.n)
+	col.buf = col.buf[:0]
+	col.n = 0
+	return nil
+}
+
+// zip compresses the provided buffer, if that reduces its size.
+func (w *Writer) zip(p []byte) []byte {
+	out, err := rcompress.Compress(nil, p, w.cfg.compress)
+	if err != nil || len(out) >= len(p) {
+		return p
+	}
+	return out
+}
+
+func (w *Writer) writeBlob(p []byte) (locator, error) {
+	key, err := riofs.NewBlobKeyInternal(w.dir, blobClass, p)
+	if err != nil {
+		return locator{}, err
+	}
+	return locator{
+		size: uint32(len(p)),
+		off:  uint64(key.SeekKey()) + uint64(key.KeyLen()),
+	}, nil
+}
+
+// addField adds a field (and its sub-fields) to the RNTuple schema.
+func (w *Writer) addField(name string, rt reflect.Type, parent int) (wfield, error) {
+	id := uint32(len(w.hdr.fields))
+	fd := fieldDesc{
+		id:     id,
+		parent: id,
+		name:   name,
+	}
+	if parent >= 0 {
+		fd.parent = uint32(parent)
+	}
+
+	switch rt.Kind() {
+	case reflect.String:
+		fd.typ = "std::string"
+		w.hdr.fields = append(w.hdr.fields, fd)
+		offs := w.addColumn(id, colSplitIndex64)
+		chars := w.addColumn(id, colChar)
+		return &wstring{offs: offs, chars: chars}, nil
+
+	case reflect.Slice:
+		fd.role = roleCollection
+		w.hdr.fields = append(w.hdr.fields, fd)
+		offs := w.addColumn(id, colSplitIndex64)
+		elem, err := w.addField("_0", rt.Elem(), int(id))
+		if err != nil {
+			return nil, err
+		}
+		w.hdr.fields[id].typ = "std::vector<" + w.hdr.fields[id+1].typ + ">"
+		return &wslice{offs: offs, elem: elem}, nil
+
+	case reflect.Array:
+		fd.flags = fieldFlagRepetitive
+		fd.nrep = uint64(rt.Len())
+		w.hdr.fields = append(w.hdr.fields, fd)
+		elem, err := w.addField("_0", rt.Elem(), int(id))
+		if err != nil {
+			return nil, err
+		}
+		w.hdr.fields[id].typ = fmt.Sprintf("std::array<%s,%d>", w.hdr.fields[id+1].typ, rt.Len())
+		return &warray{elem: elem}, nil
+	}
+
+	kind, typ, ok := scalarColumn(rt)
+	if !ok {
+		return nil, fmt.Errorf("rntup: unsupported Go type %v", rt)
+	}
+	fd.typ = typ
+	w.hdr.fields = append(w.hdr.fields, fd)
+	col := w.addColumn(id, kind)
+	return &wleaf{col: col}, nil
+}
+
+func (w *Writer) addColumn(field uint32, kind colKind) *wcolumn {
+	id := uint32(len(w.hdr.cols))
+	w.hdr.cols = append(w.hdr.cols, columnDesc{
+		id:    id,
+		kind:  kind,
+		nbits: uint16(colInfos[kind].nbits),
+		field: field,
+	})
+	col := &wcolumn{kind: kind}
+	w.cols = append(w.cols, col)
+	return col
+}
+
+// scalarColumn returns the column type and C++ type name used to store
+// values of the provided Go type.
+func scalarColumn(rt reflect.Type) (colKind, string, bool) {
+	switch rt.Kind() {
+	case reflect.Bool:
+		return colBit, "bool", true
+	case reflect.Int8:
+		return colInt8, "std::int8_t", true
+	case reflect.Int16:
+		return colSplitInt16, "std::int16_t", true
+	case reflect.Int32:
+		return colSplitInt32, "std::int32_t", true
+	case reflect.Int64:
+		return colSplitInt64, "std::int64_t", true
+	case reflect.Uint8:
+		return colUInt8, "std::uint8_t", true
+	case reflect.Uint16:
+		return colSplitUInt16, "std::uint16_t", true
+	case reflect.Uint32:
+		return colSplitUInt32, "std::uint32_t", true
+	case reflect.Uint64:
+		return colSplitUInt64, "std::uint64_t", true
+	case reflect.Float32:
+		return colSplitReal32, "float", true
+	case reflect.Float64:
+		return colSplitReal64, "double", true
+	}
+	return 0, "", false
+}
+
+// wcolumn holds the pending content of a column being written.
+type wcolumn struct {
+	kind colKind
+
+	buf []byte // in-memory representation of the pending elements
+	n   int    // number of pending elements
+
+	pages  []pageDesc // pages written for the current cluster
+	nelems uint64     // number of elements written for the current cluster
+	first  uint64     // number of elements written before the current cluster
+	offset uint64     // current cluster-local offset, for index columns
+}
+
+func (col *wcolumn) grow(n int) []byte {
+	col.buf = append(col.buf, make([]byte, n)...)
+	col.n++
+	return col.buf[len(col.buf)-n:]
+}
+
+func (col *wcolumn) putIndex(n int) {
+	col.offset += uint64(n)
+	binary.LittleEndian.PutUint64(col.grow(8), col.offset)
+}
+
+// wfield writes the content of a value to the columns of a field.
+type wfield interface {
+	// write appends v to the field's columns and returns the number of
+	// bytes (before compression) written.
+	write(v reflect.Value) int
+}
+
+type wleaf struct {
+	col *wcolumn
+}
+
+func (wf *wleaf) write(v reflect.Value) int {
+	col := wf.col
+	switch v.Kind() {
+	case reflect.Bool:
+		p := col.grow(1)
+		if v.Bool() {
+			p[0] = 1
+		}
+		return 1
+	case reflect.Int8:
+		col.grow(1)[0] = byte(v.Int())
+		return 1
+	case reflect.Uint8:
+		col.grow(1)[0] = byte(v.Uint())
+		return 1
+	case reflect.Int16:
+		binary.LittleEndian.PutUint16(col.grow(2), uint16(v.Int()))
+		return 2
+	case reflect.Uint16:
+		binary.LittleEndian.PutUint16(col.grow(2), uint16(v.Uint()))
+		return 2
+	case reflect.Int32:
+		binary.LittleEndian.PutUint32(col.grow(4), uint32(v.Int()))
+		return 4
+	case reflect.Uint32:
+		binary.LittleEndian.PutUint32(col.grow(4), uint32(v.Uint()))
+		return 4
+	case reflect.Int64:
+		binary.LittleEndian.PutUint64(col.grow(8), uint64(v.Int()))
+		return 8
+	case reflect.Uint64:
+		binary.LittleEndian.PutUint64(col.grow(8), v.Uint())
+		return 8
+	case reflect.Float32:
+		binary.LittleEndian.PutUint32(col.grow(4), math.Float32bits(float32(v.Float())))
+		return 4
+	case reflect.Float64:
+		binary.LittleEndian.PutUint64(col.grow(8), math.Float64bits(v.Float()))
+		return 8
+	}
+	panic(fmt.Errorf("rntup: invalid value kind %v", v.Kind()))
+}
+
+type wstring struct {
+	offs  *wcolumn
+	chars *wcolumn
+}
+
+func (wf *wstring) write(v reflect.Value) int {
+	str := v.String()
+	wf.offs.putIndex(len(str))
+	wf.chars.buf = append(wf.chars.buf, str...)
+	wf.chars.n += len(str)
+	return 8 + len(str)
+}
+
+type wslice struct {
+	offs *wcolumn
+	elem wfield
+}
+
+func (wf *wslice) write(v reflect.Value) int {
+	n := v.Len()
+	wf.offs.putIndex(n)
+	nbytes := 8
+	for i := 0; i < n; i++ {
+		nbytes += wf.elem.write(v.Index(i))
+	}
+	return nbytes
+}
+
+type warray struct {
+	elem wfield
+}
+
+func (wf *warray) write(v reflect.Value) int {
+	var nbytes int
+	for i := 0; i < v.Len(); i++ {
+		nbytes += wf.elem.write(v.Index(i))
+	}
+	return nbytes
+}
+
+func fileOf(d riofs.Directory) *riofs.File {
+	const max = 1<<31 - 1
+	for i := 0; i < max; i++ {
+		p := d.Parent()
+		if p == nil {
+			return d.(*riofs.File)
+		}
+		d = p
+	}
+	panic("impossible")
+}
@@ -0,0 +1,253 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rntup
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go-hep.org/x/hep/groot/riofs"
+)
+
+type wevent struct {
+	B   bool
+	I8  int8
+	I16 int16
+	I32 int32
+	I64 int64
+	U8  uint8
+	U16 uint16
+	U32 uint32
+	U64 uint64
+	F32 float32
+	F64 float64
+	Str string
+	Arr [3]float64
+	Sli []float32
+	SSl [][]int16
+	SAr []string
+}
+
+func newWEvent(i int) wevent {
+	evt := wevent{
+		B:   i%2 == 0,
+		I8:  int8(-i),
+		I16: int16(-i * 10),
+		I32: int32(-i * 100),
+		I64: int64(-i * 1000),
+		U8:  uint8(i),
+		U16: uint16(i * 10),
+		U32: uint32(i * 100),
+		U64: uint64(i * 1000),
+		F32: float32(i) + 0.5,
+		F64: float64(i) + 0.25,
+		Str: fmt.Sprintf("evt-%03d", i),
+		Arr: [3]float64{float64(i), float64(2 * i), float64(3 * i)},
+		Sli: make([]float32, i%5),
+		SSl: make([][]int16, i%3),
+		SAr: make([]string, i%4),
+	}
+	for j := range evt.Sli {
+		evt.Sli[j] = float32(i + j)
+	}
+	for j := range evt.SSl {
+		evt.SSl[j] = make([]int16, j+1)
+		for k := range evt.SSl[j] {
+			evt.SSl[j][k] = int16(i - j - k)
+		}
+	}
+	for j := range evt.SAr {
+		evt.SAr[j] = fmt.Sprintf("s%d-%d", i, j)
+	}
+	return evt
+}
+
+func wvarsOf(evt *wevent) []WriteVar {
+	return []WriteVar{
+		{Name: "b", Value: &evt.B},
+		{Name: "i8", Value: &evt.I8},
+		{Name: "i16", Value: &evt.I16},
+		{Name: "i32", Value: &evt.I32},
+		{Name: "i64", Value: &evt.I64},
+		{Name: "u8", Value: &evt.U8},
+		{Name: "u16", Value: &evt.U16},
+		{Name: "u32", Value: &evt.U32},
+		{Name: "u64", Value: &evt.U64},
+		{Name: "f32", Value: &evt.F32},
+		{Name: "f64", Value: &evt.F64},
+		{Name: "str", Value: &evt.Str},
+		{Name: "arr", Value: &evt.Arr},
+		{Name: "sli", Value: &evt.Sli},
+		{Name: "ssl", Value: &evt.SSl},
+		{Name: "sar", Value: &evt.SAr},
+	}
+}
+
+func TestWriter(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []WriteOption
+	}{
+		{name: "default"},
+		{name: "no-compr", opts: []WriteOption{WithoutCompression()}},
+		{name: "small-pages", opts: []WriteOption{WithPageSize(128), WithClusterSize(2048)}},
+		{name: "lz4", opts: []WriteOption{WithLZ4(1), WithPageSize(1024), WithClusterSize(8192)}},
+		{name: "zstd", opts: []WriteOption{WithZstd(1), WithPageSize(1024), WithClusterSize(8192)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			const nevts = 500
+			fname := filepath.Join(t.TempDir(), "ntuple.root")
+
+			func() {
+				f, err := riofs.Create(fname)
+				if err != nil {
+					t.Fatalf("could not create file: %+v", err)
+				}
+				defer f.Close()
+
+				var evt wevent
+				opts := append([]WriteOption{WithDescription("my events")}, tc.opts...)
+				w, err := NewWriter(f, "ntpl", wvarsOf(&evt), opts...)
+				if err != nil {
+					t.Fatalf("could not create writer: %+v", err)
+				}
+
+				for i := 0; i < nevts; i++ {
+					evt = newWEvent(i)
+					_, err = w.Write()
+					if err != nil {
+						t.Fatalf("could not write event %d: %+v", i, err)
+					}
+				}
+
+				err = w.Close()
+				if err != nil {
+					t.Fatalf("could not close writer: %+v", err)
+				}
+
+				err = f.Close()
+				if err != nil {
+					t.Fatalf("could not close file: %+v", err)
+				}
+			}()
+
+			f, err := riofs.Open(fname)
+			if err != nil {
+				t.Fatalf("could not open file: %+v", err)
+			}
+			defer f.Close()
+
+			obj, err := f.Get("ntpl")
+			if err != nil {
+				t.Fatalf("could not get ntuple: %+v", err)
+			}
+			nt := obj.(*RNTuple)
+
+			desc, err := nt.Descriptor()
+			if err != nil {
+				t.Fatalf("could not load descriptor: %+v", err)
+			}
+			if got, want := desc.Entries(), int64(nevts); got != want {
+				t.Fatalf("invalid number of entries: got=%d, want=%d", got, want)
+			}
+			if got, want := desc.Description(), "my events"; got != want {
+				t.Fatalf("invalid description: got=%q, want=%q", got, want)
+			}
+
+			for i, want := range []Field{
+				{Name: "i16", Type: "std::int16_t"},
+				{Name: "arr", Type: "std::array<double,3>", Fields: []Field{{Name: "_0", Type: "double"}}},
+				{Name: "ssl", Type: "std::vector<std::vector<std::int16_t>>", Fields: []Field{
+					{Name: "_0", Type: "std::vector<std::int16_t>", Fields: []Field{{Name: "_0", Type: "std::int16_t"}}},
+				}},
+				{Name: "sar", Type: "std::vector<std::string>", Fields: []Field{{Name: "_0", Type: "std::string"}}},
+			} {
+				idx := []int{2, 12, 14, 15}[i]
+				if got := desc.Fields()[idx]; !reflect.DeepEqual(got, want) {
+					t.Fatalf("invalid field %d:\ngot= %+v\nwant=%+v", idx, got, want)
+				}
+			}
+
+			var evt wevent
+			wvars := wvarsOf(&evt)
+			rvars := make([]ReadVar, len(wvars))
+			for i, wvar := range wvars {
+				rvars[i] = ReadVar{Name: wvar.Name, Value: wvar.Value}
+			}
+
+			r, err := NewReader(nt, rvars)
+			if err != nil {
+				t.Fatalf("could not create reader: %+v", err)
+			}
+			defer r.Close()
+
+			n := 0
+			err = r.Read(func(ctx RCtx) error {
+				want := newWEvent(int(ctx.Entry))
+				if len(want.Sli) == 0 {
+					want.Sli = evt.Sli[:0]
+				}
+				if len(want.SSl) == 0 {
+					want.SSl = evt.SSl[:0]
+				}
+				if len(want.SAr) == 0 {
+					want.SAr = evt.SAr[:0]
+				}
+				if !reflect.DeepEqual(evt, want) {
+					return fmt.Errorf("invalid event %d:\ngot= %+v\nwant=%+v", ctx.Entry, evt, want)
+				}
+				n++
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("could not read ntuple: %+v", err)
+			}
+			if n != nevts {
+				t.Fatalf("invalid number of events: got=%d, want=%d", n, nevts)
+			}
+		})
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	f, err := riofs.Create(filepath.Join(t.TempDir(), "ntuple.root"))
+	if err != nil {
+		t.Fatalf("could not create file: %+v", err)
+	}
+	defer f.Close()
+
+	for _, tc := range []struct {
+		name  string
+		wvars []WriteVar
+		err   string
+	}{
+		{
+			name: "empty",
+			err:  "rntup: empty list of write-vars",
+		},
+		{
+			name:  "not-ptr",
+			wvars: []WriteVar{{Name: "x", Value: 1.0}},
+			err:   `rntup: write-var "x" value is not a non-nil pointer (type=float64)`,
+		},
+		{
+			name:  "int",
+			wvars: []WriteVar{{Name: "x", Value: new(int)}},
+			err:   `rntup: could not create field "x": rntup: unsupported Go type int`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewWriter(f, "ntpl", tc.wvars)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if got, want := err.Error(), tc.err; got != want {
+				t.Fatalf("invalid error:\ngot= %v\nwant=%v", got, want)
+			}
+		})
+	}
+}
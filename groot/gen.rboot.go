@@ -28,7 +28,7 @@ import (
 var (
 	classes = []string{
 		// rbase
-		"TAttAxis", "TAttFill", "TAttLine", "TAttMarker",
+		"TAtt3D", "TAttAxis", "TAttFill", "TAttLine", "TAttMarker",
 		"TDatime",
 		"TNamed",
 		"TObject", "TObjString",
@@ -73,8 +73,10 @@ var (
 		"TF1AbsComposition", "TF1Convolution", "TF1NormSum", "TF1Parameters",
 		"TFormula",
 		"TGraph", "TGraphErrors", "TGraphAsymmErrors", "TGraphMultiErrors",
+		"TGraph2D", "TGraph2DErrors",
 		"TH1", "TH1C", "TH1D", "TH1F", "TH1I", "TH1K", "TH1S",
 		"TH2", "TH2C", "TH2D", "TH2F", "TH2I", "TH2Poly", "TH2PolyBin", "TH2S",
+		"TH3", "TH3C", "TH3D", "TH3F", "TH3I", "TH3S",
 		"TLimit", "TLimitDataSource",
 		"TMultiGraph",
 		"TProfile", "TProfile2D",
@@ -98,6 +100,7 @@ var (
 		"TBasket",
 		"TBranch", "TBranchElement", "TBranchObject", "TBranchRef",
 		"TChain",
+		"TEntryList", "TEntryListBlock",
 		"TLeaf", "TLeafElement", "TLeafObject",
 		"TLeafO",
 		"TLeafB", "TLeafS", "TLeafI", "TLeafL",
@@ -106,6 +109,7 @@ var (
 		"TLeafC",
 		"TNtuple", "TNtupleD",
 		"TTree",
+		"TTreeIndex", "TVirtualIndex",
 	}
 )
 
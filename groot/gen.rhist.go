@@ -18,6 +18,7 @@ import (
 func main() {
 	genH1()
 	genH2()
+	genH3()
 }
 
 func genH1() {
@@ -136,6 +137,64 @@ func genH2() {
 	genroot.GoFmt(f)
 }
 
+func genH3() {
+	fname := "./rhist/h3_gen.go"
+	year := genroot.ExtractYear(fname)
+	f, err := os.Create(fname)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	genroot.GenImports(year, "rhist", f,
+		"fmt", "math", "reflect",
+		"",
+		"go-hep.org/x/hep/hbook",
+		"go-hep.org/x/hep/groot/root",
+		"go-hep.org/x/hep/groot/rcont",
+		"go-hep.org/x/hep/groot/rbytes",
+		"go-hep.org/x/hep/groot/rtypes",
+		"go-hep.org/x/hep/groot/rvers",
+	)
+
+	for i, typ := range []struct {
+		Name string
+		Type string
+		Elem string
+	}{
+		{
+			Name: "H3F",
+			Type: "rcont.ArrayF",
+			Elem: "float32",
+		},
+		{
+			Name: "H3D",
+			Type: "rcont.ArrayD",
+			Elem: "float64",
+		},
+		{
+			Name: "H3I",
+			Type: "rcont.ArrayI",
+			Elem: "int32",
+		},
+	} {
+		if i > 0 {
+			fmt.Fprintf(f, "\n")
+		}
+		tmpl := template.Must(template.New(typ.Name).Parse(h3Tmpl))
+		err = tmpl.Execute(f, typ)
+		if err != nil {
+			log.Fatalf("error executing template for %q: %v\n", typ.Name, err)
+		}
+	}
+
+	err = f.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+	genroot.GoFmt(f)
+}
+
 const h1Tmpl = `// {{.Name}} implements ROOT T{{.Name}}
 type {{.Name}} struct {
 	th1
@@ -380,19 +439,26 @@ func (h *{{.Name}}) UnmarshalYODA(raw []byte) error {
 	return nil
 }
 
+// ROOTMerge merges the content of src into h.
+// ROOTMerge returns an error if the axes of the histograms are not compatible.
 func (h *{{.Name}}) ROOTMerge(src root.Object) error {
 	hsrc, ok := src.(*{{.Name}})
 	if !ok {
-		return fmt.Errorf("rhist: object %q is not a *rhist.H1F (%T)", src.(root.Named).Name(), src)
+		return fmt.Errorf("rhist: object %q is not a *rhist.{{.Name}} (%T)", src.(root.Named).Name(), src)
 	}
 
-	var (
-		h1   = h.AsH1D()
-		h2   = hsrc.AsH1D()
-		hadd = hbook.AddH1D(h1, h2)
+	err := h.th1.merge(
+		&hsrc.th1,
+		func(i int) float64 { return float64(h.arr.Data[i]) },
+		func(i int) float64 { return float64(hsrc.arr.Data[i]) },
 	)
+	if err != nil {
+		return fmt.Errorf("rhist: could not merge %q: %w", h.Name(), err)
+	}
 
-	*h = *New{{.Name}}From(hadd)
+	for i, v := range hsrc.arr.Data {
+		h.arr.Data[i] += v
+	}
 	return nil
 }
 
@@ -763,6 +829,29 @@ func (h *{{.Name}}) MarshalROOT(w *rbytes.WBuffer) (int, error) {
 	return w.SetHeader(hdr)
 }
 
+// ROOTMerge merges the content of src into h.
+// ROOTMerge returns an error if the axes of the histograms are not compatible.
+func (h *{{.Name}}) ROOTMerge(src root.Object) error {
+	hsrc, ok := src.(*{{.Name}})
+	if !ok {
+		return fmt.Errorf("rhist: object %q is not a *rhist.{{.Name}} (%T)", src.(root.Named).Name(), src)
+	}
+
+	err := h.th2.merge(
+		&hsrc.th2,
+		func(i int) float64 { return float64(h.arr.Data[i]) },
+		func(i int) float64 { return float64(hsrc.arr.Data[i]) },
+	)
+	if err != nil {
+		return fmt.Errorf("rhist: could not merge %q: %w", h.Name(), err)
+	}
+
+	for i, v := range hsrc.arr.Data {
+		h.arr.Data[i] += v
+	}
+	return nil
+}
+
 func (h *{{.Name}}) UnmarshalROOT(r *rbytes.RBuffer) error {
 	if r.Err() != nil {
 		return r.Err()
@@ -793,9 +882,421 @@ func init() {
 
 var (
 	_ root.Object        = (*{{.Name}})(nil)
+	_ root.Merger        = (*{{.Name}})(nil)
 	_ root.Named         = (*{{.Name}})(nil)
 	_ H2                 = (*{{.Name}})(nil)
 	_ rbytes.Marshaler   = (*{{.Name}})(nil)
 	_ rbytes.Unmarshaler = (*{{.Name}})(nil)
 )
 `
+
+const h3Tmpl = `// {{.Name}} implements ROOT T{{.Name}}
+type {{.Name}} struct {
+	th3
+	arr {{.Type}}
+}
+
+func new{{.Name}}() *{{.Name}} {
+	return &{{.Name}}{
+		th3:   *newH3(),
+	}
+}
+
+// New{{.Name}}From creates a new {{.Name}} from hbook 3-dim histogram.
+func New{{.Name}}From(h *hbook.H3D) *{{.Name}} {
+	var (
+		hroot  = new{{.Name}}()
+		bins   = h.Binning.Bins
+		nxbins = h.Binning.Nx
+		nybins = h.Binning.Ny
+		nzbins = h.Binning.Nz
+		xedges = make([]float64, 0, nxbins+1)
+		yedges = make([]float64, 0, nybins+1)
+		zedges = make([]float64, 0, nzbins+1)
+	)
+
+	hroot.th3.th1.entries = float64(h.Entries())
+	hroot.th3.th1.tsumw = h.SumW()
+	hroot.th3.th1.tsumw2 = h.SumW2()
+	hroot.th3.th1.tsumwx = h.SumWX()
+	hroot.th3.th1.tsumwx2 = h.SumWX2()
+	hroot.th3.tsumwy = h.SumWY()
+	hroot.th3.tsumwy2 = h.SumWY2()
+	hroot.th3.tsumwxy = h.SumWXY()
+	hroot.th3.tsumwz = h.SumWZ()
+	hroot.th3.tsumwz2 = h.SumWZ2()
+	hroot.th3.tsumwxz = h.SumWXZ()
+	hroot.th3.tsumwyz = h.SumWYZ()
+
+	ncells := (nxbins + 2) * (nybins + 2) * (nzbins + 2)
+	hroot.th3.th1.ncells = ncells
+
+	hroot.th3.th1.xaxis.nbins = nxbins
+	hroot.th3.th1.xaxis.xmin = h.XMin()
+	hroot.th3.th1.xaxis.xmax = h.XMax()
+
+	hroot.th3.th1.yaxis.nbins = nybins
+	hroot.th3.th1.yaxis.xmin = h.YMin()
+	hroot.th3.th1.yaxis.xmax = h.YMax()
+
+	hroot.th3.th1.zaxis.nbins = nzbins
+	hroot.th3.th1.zaxis.xmin = h.ZMin()
+	hroot.th3.th1.zaxis.xmax = h.ZMax()
+
+	hroot.arr.Data = make([]{{.Elem}}, ncells)
+	hroot.th3.th1.sumw2.Data = make([]float64, ncells)
+
+	ibin := func(ix, iy, iz int) int { return (iz*nybins+iy)*nxbins + ix }
+
+	for ix := 0; ix < nxbins; ix++ {
+		for iy := 0; iy < nybins; iy++ {
+			for iz := 0; iz < nzbins; iz++ {
+				bin := bins[ibin(ix, iy, iz)]
+				if iy == 0 && iz == 0 {
+					xedges = append(xedges, bin.XMin())
+				}
+				if ix == 0 && iz == 0 {
+					yedges = append(yedges, bin.YMin())
+				}
+				if ix == 0 && iy == 0 {
+					zedges = append(zedges, bin.ZMin())
+				}
+				hroot.setDist3D(ix+1, iy+1, iz+1, bin.Dist.SumW(), bin.Dist.SumW2())
+			}
+		}
+	}
+
+	// outflows are stored in the first ROOT bin of their region.
+	cell := func(k, n int) int {
+		switch k {
+		case -1:
+			return 0
+		case +1:
+			return n + 1
+		default:
+			return 1
+		}
+	}
+	i := 0
+	for kx := -1; kx <= 1; kx++ {
+		for ky := -1; ky <= 1; ky++ {
+			for kz := -1; kz <= 1; kz++ {
+				if kx == 0 && ky == 0 && kz == 0 {
+					continue
+				}
+				oflow := &h.Binning.Outflows[i]
+				hroot.setDist3D(
+					cell(kx, nxbins), cell(ky, nybins), cell(kz, nzbins),
+					oflow.SumW(), oflow.SumW2(),
+				)
+				i++
+			}
+		}
+	}
+
+	xedges = append(xedges, bins[ibin(nxbins-1, 0, 0)].XMax())
+	yedges = append(yedges, bins[ibin(0, nybins-1, 0)].YMax())
+	zedges = append(zedges, bins[ibin(0, 0, nzbins-1)].ZMax())
+
+	hroot.th3.th1.SetName(h.Name())
+	if v, ok := h.Annotation()["title"]; ok && v != nil {
+		hroot.th3.th1.SetTitle(v.(string))
+	}
+	hroot.th3.th1.xaxis.xbins.Data = xedges
+	hroot.th3.th1.yaxis.xbins.Data = yedges
+	hroot.th3.th1.zaxis.xbins.Data = zedges
+
+	return hroot
+}
+
+func (*{{.Name}}) RVersion() int16 {
+	return rvers.{{.Name}}
+}
+
+func (*{{.Name}}) isH3() {}
+
+// Class returns the ROOT class name.
+func (*{{.Name}}) Class() string {
+	return "T{{.Name}}"
+}
+
+func (h *{{.Name}}) Array() {{.Type}} {
+	return h.arr
+}
+
+// Rank returns the number of dimensions of this histogram.
+func (h *{{.Name}}) Rank() int {
+	return 3
+}
+
+// NbinsX returns the number of bins in X.
+func (h *{{.Name}}) NbinsX() int {
+	return h.th1.xaxis.nbins
+}
+
+// XAxis returns the axis along X.
+func (h*{{.Name}}) XAxis() Axis {
+	return &h.th1.xaxis
+}
+
+// NbinsY returns the number of bins in Y.
+func (h *{{.Name}}) NbinsY() int {
+	return h.th1.yaxis.nbins
+}
+
+// YAxis returns the axis along Y.
+func (h*{{.Name}}) YAxis() Axis {
+	return &h.th1.yaxis
+}
+
+// NbinsZ returns the number of bins in Z.
+func (h *{{.Name}}) NbinsZ() int {
+	return h.th1.zaxis.nbins
+}
+
+// ZAxis returns the axis along Z.
+func (h*{{.Name}}) ZAxis() Axis {
+	return &h.th1.zaxis
+}
+
+// BinContent returns the content of the (ix,iy,iz) bin.
+// Bin indices start at 1; 0 and Nbins+1 are the under- and overflow bins.
+func (h *{{.Name}}) BinContent(ix, iy, iz int) float64 {
+	return float64(h.arr.Data[h.bin(ix, iy, iz)])
+}
+
+// BinError returns the error of the (ix,iy,iz) bin.
+// Bin indices start at 1; 0 and Nbins+1 are the under- and overflow bins.
+func (h *{{.Name}}) BinError(ix, iy, iz int) float64 {
+	i := h.bin(ix, iy, iz)
+	if len(h.th1.sumw2.Data) > 0 {
+		return math.Sqrt(float64(h.th1.sumw2.Data[i]))
+	}
+	return math.Sqrt(math.Abs(float64(h.arr.Data[i])))
+}
+
+// bin returns the regularized bin number given an (x,y,z) bin index triplet.
+func (h *{{.Name}}) bin(ix, iy, iz int) int {
+	nx := h.th1.xaxis.nbins + 1 // overflow bin
+	ny := h.th1.yaxis.nbins + 1 // overflow bin
+	nz := h.th1.zaxis.nbins + 1 // overflow bin
+	switch {
+	case ix < 0:
+		ix = 0
+	case ix > nx:
+		ix = nx
+	}
+	switch {
+	case iy < 0:
+		iy = 0
+	case iy > ny:
+		iy = ny
+	}
+	switch {
+	case iz < 0:
+		iz = 0
+	case iz > nz:
+		iz = nz
+	}
+	return ix + (nx+1)*(iy+(ny+1)*iz)
+}
+
+func (h *{{.Name}}) setDist3D(ix, iy, iz int, sumw, sumw2 float64) {
+	i := h.bin(ix, iy, iz)
+	h.arr.Data[i] = {{.Elem}}(sumw)
+	h.th1.sumw2.Data[i] = sumw2
+}
+
+// dist3D returns the distribution of the ROOT bins within the
+// [ix0,ix1]x[iy0,iy1]x[iz0,iz1] (inclusive) bin indices ranges.
+func (h *{{.Name}}) dist3D(ix0, ix1, iy0, iy1, iz0, iz1 int) hbook.Dist3D {
+	var (
+		n     int64
+		sumw  float64
+		sumw2 float64
+	)
+	for ix := ix0; ix <= ix1; ix++ {
+		for iy := iy0; iy <= iy1; iy++ {
+			for iz := iz0; iz <= iz1; iz++ {
+				i := h.bin(ix, iy, iz)
+				v := float64(h.arr.Data[i])
+				n += h.entries(v, h.BinError(ix, iy, iz))
+				sumw += v
+				if len(h.th1.sumw2.Data) > 0 {
+					sumw2 += h.th1.sumw2.Data[i]
+				}
+			}
+		}
+	}
+	d := hbook.Dist1D{
+		Dist: hbook.Dist0D{
+			N:     n,
+			SumW:  sumw,
+			SumW2: sumw2,
+		},
+	}
+	return hbook.Dist3D{X: d, Y: d, Z: d}
+}
+
+func (h *{{.Name}}) entries(height, err float64) int64 {
+	if height <= 0 {
+		return 0
+	}
+	v := height / err
+	return int64(v*v + 0.5)
+}
+
+// AsH3D creates a new hbook.H3D from this ROOT histogram.
+func (h *{{.Name}}) AsH3D() *hbook.H3D {
+	var (
+		nx = h.NbinsX()
+		ny = h.NbinsY()
+		nz = h.NbinsZ()
+		hh = hbook.NewH3DFromEdges(
+			axisEdges(h.XAxis()),
+			axisEdges(h.YAxis()),
+			axisEdges(h.ZAxis()),
+		)
+	)
+	hh.Ann = hbook.Annotation{
+		"name":  h.Name(),
+		"title": h.Title(),
+	}
+
+	// ranges of ROOT bin indices for underflow, in-range and overflow bins.
+	rng := func(k, n int) (int, int) {
+		switch k {
+		case -1:
+			return 0, 0
+		case +1:
+			return n + 1, n + 1
+		default:
+			return 1, n
+		}
+	}
+	i := 0
+	for kx := -1; kx <= 1; kx++ {
+		for ky := -1; ky <= 1; ky++ {
+			for kz := -1; kz <= 1; kz++ {
+				if kx == 0 && ky == 0 && kz == 0 {
+					continue
+				}
+				ix0, ix1 := rng(kx, nx)
+				iy0, iy1 := rng(ky, ny)
+				iz0, iz1 := rng(kz, nz)
+				hh.Binning.Outflows[i] = h.dist3D(ix0, ix1, iy0, iy1, iz0, iz1)
+				i++
+			}
+		}
+	}
+
+	hh.Binning.Dist = hbook.Dist3D{
+		X: hbook.Dist1D{
+			Dist: hbook.Dist0D{
+				N:     int64(h.Entries()),
+				SumW:  float64(h.SumW()),
+				SumW2: float64(h.SumW2()),
+			},
+		},
+	}
+	hh.Binning.Dist.Y = hh.Binning.Dist.X
+	hh.Binning.Dist.Z = hh.Binning.Dist.X
+	hh.Binning.Dist.X.Stats.SumWX = float64(h.SumWX())
+	hh.Binning.Dist.X.Stats.SumWX2 = float64(h.SumWX2())
+	hh.Binning.Dist.Y.Stats.SumWX = float64(h.SumWY())
+	hh.Binning.Dist.Y.Stats.SumWX2 = float64(h.SumWY2())
+	hh.Binning.Dist.Z.Stats.SumWX = float64(h.SumWZ())
+	hh.Binning.Dist.Z.Stats.SumWX2 = float64(h.SumWZ2())
+	hh.Binning.Dist.Stats.SumWXY = h.SumWXY()
+	hh.Binning.Dist.Stats.SumWXZ = h.SumWXZ()
+	hh.Binning.Dist.Stats.SumWYZ = h.SumWYZ()
+
+	for ix := 0; ix < nx; ix++ {
+		for iy := 0; iy < ny; iy++ {
+			for iz := 0; iz < nz; iz++ {
+				i := (iz*ny+iy)*nx + ix
+				hh.Binning.Bins[i].Dist = h.dist3D(ix+1, ix+1, iy+1, iy+1, iz+1, iz+1)
+			}
+		}
+	}
+
+	return hh
+}
+
+// MarshalYODA implements the YODAMarshaler interface.
+func (h *{{.Name}}) MarshalYODA() ([]byte, error) {
+	return h.AsH3D().MarshalYODA()
+}
+
+func (h *{{.Name}}) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	hdr := w.WriteHeader(h.Class(), h.RVersion())
+	w.WriteObject(&h.th3)
+	w.WriteObject(&h.arr)
+
+	return w.SetHeader(hdr)
+}
+
+// ROOTMerge merges the content of src into h.
+// ROOTMerge returns an error if the axes of the histograms are not compatible.
+func (h *{{.Name}}) ROOTMerge(src root.Object) error {
+	hsrc, ok := src.(*{{.Name}})
+	if !ok {
+		return fmt.Errorf("rhist: object %q is not a *rhist.{{.Name}} (%T)", src.(root.Named).Name(), src)
+	}
+
+	err := h.th3.merge(
+		&hsrc.th3,
+		func(i int) float64 { return float64(h.arr.Data[i]) },
+		func(i int) float64 { return float64(hsrc.arr.Data[i]) },
+	)
+	if err != nil {
+		return fmt.Errorf("rhist: could not merge %q: %w", h.Name(), err)
+	}
+
+	for i, v := range hsrc.arr.Data {
+		h.arr.Data[i] += v
+	}
+	return nil
+}
+
+func (h *{{.Name}}) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	hdr := r.ReadHeader(h.Class())
+	if hdr.Vers > rvers.{{.Name}} {
+		panic(fmt.Errorf("rhist: invalid {{.Name}} version=%d > %d", hdr.Vers, rvers.{{.Name}}))
+	}
+	if hdr.Vers < 1 {
+		return fmt.Errorf("rhist: T{{.Name}} version too old (%d<1)", hdr.Vers)
+	}
+
+	r.ReadObject(&h.th3)
+	r.ReadObject(&h.arr)
+
+	r.CheckHeader(hdr)
+	return r.Err()
+}
+
+func init() {
+	f := func() reflect.Value {
+		o := new{{.Name}}()
+		return reflect.ValueOf(o)
+	}
+	rtypes.Factory.Add("T{{.Name}}", f)
+}
+
+var (
+	_ root.Object        = (*{{.Name}})(nil)
+	_ root.Merger        = (*{{.Name}})(nil)
+	_ root.Named         = (*{{.Name}})(nil)
+	_ H3                 = (*{{.Name}})(nil)
+	_ rbytes.Marshaler   = (*{{.Name}})(nil)
+	_ rbytes.Unmarshaler = (*{{.Name}})(nil)
+)
+`
@@ -23,8 +23,8 @@ const (
 // Open opens the named ROOT file for reading. If successful, methods on the
 // returned file can be used for reading; the associated file descriptor
 // has mode os.O_RDONLY.
-func Open(path string) (*File, error) {
-	return riofs.Open(path)
+func Open(path string, opts ...FileOption) (*File, error) {
+	return riofs.Open(path, opts...)
 }
 
 // NewReader creates a new ROOT file reader.
@@ -37,6 +37,12 @@ func Create(name string, opts ...FileOption) (*File, error) {
 	return riofs.Create(name, opts...)
 }
 
+// Update opens the named ROOT file for reading and writing.
+// New objects may be added to (or deleted from) the file.
+func Update(path string, opts ...FileOption) (*File, error) {
+	return riofs.Update(path, opts...)
+}
+
 type (
 	File       = riofs.File
 	FileOption = riofs.FileOption
@@ -0,0 +1,223 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package xxh3 implements the 64b variant of the XXH3 hash algorithm
+// (with the default secret and a zero seed), as used by ROOT to checksum
+// RNTuple data.
+package xxh3 // import "go-hep.org/x/hep/groot/internal/xxh3"
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const (
+	prime32_1 = 0x9E3779B1
+	prime32_2 = 0x85EBCA77
+	prime32_3 = 0xC2B2AE3D
+
+	prime64_1 = 0x9E3779B185EBCA87
+	prime64_2 = 0xC2B2AE3D27D4EB4F
+	prime64_3 = 0x165667B19E3779F9
+	prime64_4 = 0x85EBCA77C2B2AE63
+	prime64_5 = 0x27D4EB2F165667C5
+
+	stripeLen  = 64
+	secretSize = len(secret)
+	nStripes   = (secretSize - stripeLen) / 8 // stripes per block
+	blockLen   = stripeLen * nStripes
+)
+
+var secret = [...]byte{
+	0xb8, 0xfe, 0x6c, 0x39, 0x23, 0xa4, 0x4b, 0xbe, 0x7c, 0x01, 0x81, 0x2c, 0xf7, 0x21, 0xad, 0x1c,
+	0xde, 0xd4, 0x6d, 0xe9, 0x83, 0x90, 0x97, 0xdb, 0x72, 0x40, 0xa4, 0xa4, 0xb7, 0xb3, 0x67, 0x1f,
+	0xcb, 0x79, 0xe6, 0x4e, 0xcc, 0xc0, 0xe5, 0x78, 0x82, 0x5a, 0xd0, 0x7d, 0xcc, 0xff, 0x72, 0x21,
+	0xb8, 0x08, 0x46, 0x74, 0xf7, 0x43, 0x24, 0x8e, 0xe0, 0x35, 0x90, 0xe6, 0x81, 0x3a, 0x26, 0x4c,
+	0x3c, 0x28, 0x52, 0xbb, 0x91, 0xc3, 0x00, 0xcb, 0x88, 0xd0, 0x65, 0x8b, 0x1b, 0x53, 0x2e, 0xa3,
+	0x71, 0x64, 0x48, 0x97, 0xa2, 0x0d, 0xf9, 0x4e, 0x38, 0x19, 0xef, 0x46, 0xa9, 0xde, 0xac, 0xd8,
+	0xa8, 0xfa, 0x76, 0x3f, 0xe3, 0x9c, 0x34, 0x3f, 0xf9, 0xdc, 0xbb, 0xc7, 0xc7, 0x0b, 0x4f, 0x1d,
+	0x8a, 0x51, 0xe0, 0x4b, 0xcd, 0xb4, 0x59, 0x31, 0xc8, 0x9f, 0x7e, 0xc9, 0xd9, 0x78, 0x73, 0x64,
+	0xea, 0xc5, 0xac, 0x83, 0x34, 0xd3, 0xeb, 0xc3, 0xc5, 0x81, 0xa0, 0xff, 0xfa, 0x13, 0x63, 0xeb,
+	0x17, 0x0d, 0xdd, 0x51, 0xb7, 0xf0, 0xda, 0x49, 0xd3, 0x16, 0x55, 0x26, 0x29, 0xd4, 0x68, 0x9e,
+	0x2b, 0x16, 0xbe, 0x58, 0x7d, 0x47, 0xa1, 0xfc, 0x8f, 0xf8, 0xb8, 0xd1, 0x7a, 0xd0, 0x31, 0xce,
+	0x45, 0xcb, 0x3a, 0x8f, 0x95, 0x16, 0x04, 0x28, 0xaf, 0xd7, 0xfb, 0xca, 0xbb, 0x4b, 0x40, 0x7e,
+}
+
+// Sum64 returns the XXH3 64b hash of p.
+func Sum64(p []byte) uint64 {
+	n := len(p)
+	switch {
+	case n <= 16:
+		return hash0To16(p)
+	case n <= 128:
+		return hash17To128(p)
+	case n <= 240:
+		return hash129To240(p)
+	default:
+		return hashLong(p)
+	}
+}
+
+func u32(p []byte, i int) uint64 { return uint64(binary.LittleEndian.Uint32(p[i:])) }
+func u64(p []byte, i int) uint64 { return binary.LittleEndian.Uint64(p[i:]) }
+
+func hash0To16(p []byte) uint64 {
+	n := len(p)
+	switch {
+	case n > 8:
+		lo := u64(p, 0) ^ (u64(secret[:], 24) ^ u64(secret[:], 32))
+		hi := u64(p, n-8) ^ (u64(secret[:], 40) ^ u64(secret[:], 48))
+		acc := uint64(n) + bits.ReverseBytes64(lo) + hi + mulFold64(lo, hi)
+		return avalanche(acc)
+
+	case n >= 4:
+		in1 := u32(p, 0)
+		in2 := u32(p, n-4)
+		keyed := (in2 + in1<<32) ^ (u64(secret[:], 8) ^ u64(secret[:], 16))
+		return rrmxmx(keyed, uint64(n))
+
+	case n > 0:
+		var (
+			c1 = uint64(p[0])
+			c2 = uint64(p[n>>1])
+			c3 = uint64(p[n-1])
+		)
+		combined := c1<<16 | c2<<24 | c3 | uint64(n)<<8
+		keyed := combined ^ (u32(secret[:], 0) ^ u32(secret[:], 4))
+		return xxh64Avalanche(keyed)
+
+	default:
+		return xxh64Avalanche(u64(secret[:], 56) ^ u64(secret[:], 64))
+	}
+}
+
+func hash17To128(p []byte) uint64 {
+	n := len(p)
+	acc := uint64(n) * prime64_1
+	if n > 32 {
+		if n > 64 {
+			if n > 96 {
+				acc += mix16(p[48:], 96)
+				acc += mix16(p[n-64:], 112)
+			}
+			acc += mix16(p[32:], 64)
+			acc += mix16(p[n-48:], 80)
+		}
+		acc += mix16(p[16:], 32)
+		acc += mix16(p[n-32:], 48)
+	}
+	acc += mix16(p[0:], 0)
+	acc += mix16(p[n-16:], 16)
+	return avalanche(acc)
+}
+
+func hash129To240(p []byte) uint64 {
+	const (
+		secretSizeMin = 136
+		startOffset   = 3
+		lastOffset    = 17
+	)
+	n := len(p)
+	acc := uint64(n) * prime64_1
+	rounds := n / 16
+	for i := 0; i < 8; i++ {
+		acc += mix16(p[16*i:], 16*i)
+	}
+	acc = avalanche(acc)
+	for i := 8; i < rounds; i++ {
+		acc += mix16(p[16*i:], 16*(i-8)+startOffset)
+	}
+	acc += mix16(p[n-16:], secretSizeMin-lastOffset)
+	return avalanche(acc)
+}
+
+func hashLong(p []byte) uint64 {
+	n := len(p)
+	acc := [8]uint64{
+		prime32_3, prime64_1, prime64_2, prime64_3,
+		prime64_4, prime32_2, prime64_5, prime32_1,
+	}
+
+	blocks := (n - 1) / blockLen
+	for i := 0; i < blocks; i++ {
+		accumulate(&acc, p[i*blockLen:], nStripes)
+		scramble(&acc)
+	}
+
+	stripes := ((n - 1) - blockLen*blocks) / stripeLen
+	accumulate(&acc, p[blocks*blockLen:], stripes)
+	accumulate512(&acc, p[n-stripeLen:], secretSize-stripeLen-7)
+
+	const mergeOffset = 11
+	res := uint64(n) * prime64_1
+	for i := 0; i < 4; i++ {
+		res += mulFold64(
+			acc[2*i+0]^u64(secret[:], mergeOffset+16*i+0),
+			acc[2*i+1]^u64(secret[:], mergeOffset+16*i+8),
+		)
+	}
+	return avalanche(res)
+}
+
+func accumulate(acc *[8]uint64, p []byte, n int) {
+	for i := 0; i < n; i++ {
+		accumulate512(acc, p[i*stripeLen:], 8*i)
+	}
+}
+
+func accumulate512(acc *[8]uint64, p []byte, off int) {
+	for i := 0; i < 8; i++ {
+		v := u64(p, 8*i)
+		k := v ^ u64(secret[:], off+8*i)
+		acc[i^1] += v
+		acc[i] += (k & 0xffffffff) * (k >> 32)
+	}
+}
+
+func scramble(acc *[8]uint64) {
+	for i := range acc {
+		v := acc[i]
+		v ^= v >> 47
+		v ^= u64(secret[:], secretSize-stripeLen+8*i)
+		v *= prime32_1
+		acc[i] = v
+	}
+}
+
+func mix16(p []byte, off int) uint64 {
+	return mulFold64(
+		u64(p, 0)^u64(secret[:], off),
+		u64(p, 8)^u64(secret[:], off+8),
+	)
+}
+
+func mulFold64(x, y uint64) uint64 {
+	hi, lo := bits.Mul64(x, y)
+	return hi ^ lo
+}
+
+func avalanche(h uint64) uint64 {
+	h ^= h >> 37
+	h *= 0x165667919E3779F9
+	h ^= h >> 32
+	return h
+}
+
+func xxh64Avalanche(h uint64) uint64 {
+	h ^= h >> 33
+	h *= prime64_2
+	h ^= h >> 29
+	h *= prime64_3
+	h ^= h >> 32
+	return h
+}
+
+func rrmxmx(h, n uint64) uint64 {
+	h ^= bits.RotateLeft64(h, 49) ^ bits.RotateLeft64(h, 24)
+	h *= 0x9FB21C651E98DF25
+	h ^= (h >> 35) + n
+	h *= 0x9FB21C651E98DF25
+	h ^= h >> 28
+	return h
+}
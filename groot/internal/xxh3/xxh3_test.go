@@ -0,0 +1,88 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package xxh3
+
+import (
+	"testing"
+)
+
+func TestSum64(t *testing.T) {
+	for _, tc := range []struct {
+		n    int
+		want uint64
+	}{
+		{n: 0, want: 0x2d06800538d394c2},
+		{n: 1, want: 0x13e608bc156defed},
+		{n: 2, want: 0x1c9074b93943b86c},
+		{n: 3, want: 0xa9088dda485b481c},
+		{n: 4, want: 0x6d9253b16c8b1ed3},
+		{n: 8, want: 0x60539db630471163},
+		{n: 9, want: 0xfeff668361d723a8},
+		{n: 16, want: 0xb8c859b0f030b585},
+		{n: 17, want: 0x714a04408e79b80f},
+		{n: 32, want: 0x19ff4ee1d6ba1a55},
+		{n: 33, want: 0x3e44983ad21679c8},
+		{n: 64, want: 0x287eb1fa9e4be2c1},
+		{n: 65, want: 0x829218de4d798646},
+		{n: 96, want: 0xf084e7cfbc624743},
+		{n: 97, want: 0x1daa83271a8e7b7c},
+		{n: 128, want: 0x67425a03650261bf},
+		{n: 129, want: 0xc664bf3311c6abc4},
+		{n: 200, want: 0x746cd0025327bf5b},
+		{n: 240, want: 0x64556dc6b462a6cf},
+		{n: 241, want: 0x8beadd3a8874fe17},
+		{n: 1024, want: 0x9b81661c641c72b1},
+		{n: 1025, want: 0x806c2072ed713576},
+		{n: 2048, want: 0xabe604813ba62ed1},
+		{n: 4096, want: 0xd7428746842be37e},
+		{n: 10000, want: 0xfcd0ecba1a48462d},
+	} {
+		p := make([]byte, tc.n)
+		for i := range p {
+			p[i] = byte(i*7 + 3)
+		}
+		if got, want := Sum64(p), tc.want; got != want {
+			t.Errorf("invalid hash for n=%d: got=0x%016x, want=0x%016x", tc.n, got, want)
+		}
+	}
+}
+
+func TestSum64Reference(t *testing.T) {
+	// test vectors from the sanity checks of the xxHash reference
+	// implementation (cli/xsum_sanity_check.c), with a zero seed.
+	const (
+		prime32 = 2654435761
+		prime64 = 11400714785074694797
+	)
+	buf := make([]byte, 2367)
+	gen := uint64(prime32)
+	for i := range buf {
+		buf[i] = byte(gen >> 56)
+		gen *= prime64
+	}
+
+	for _, tc := range []struct {
+		n    int
+		want uint64
+	}{
+		{n: 0, want: 0x2d06800538d394c2},
+		{n: 1, want: 0xc44bdff4074eecdb},
+		{n: 6, want: 0x27b56a84cd2d7325},
+		{n: 12, want: 0xa713daf0dfbb77e7},
+		{n: 24, want: 0xa3fe70bf9d3510eb},
+		{n: 48, want: 0x397da259ecba1f11},
+		{n: 80, want: 0xbcdefbbb2c47c90a},
+		{n: 195, want: 0xcd94217ee362ec3a},
+		{n: 403, want: 0xcdeb804d65c6dea4},
+		{n: 512, want: 0x617e49599013cb6b},
+		{n: 2048, want: 0xdd59e2c3a5f038e0},
+		{n: 2240, want: 0x6e73a90539cf2948},
+		{n: 2367, want: 0xcb37aeb9e5d361ed},
+	} {
+		if got, want := Sum64(buf[:tc.n]), tc.want; got != want {
+			t.Errorf("invalid hash for n=%d: got=0x%016x, want=0x%016x", tc.n, got, want)
+		}
+	}
+}
@@ -16,9 +16,23 @@ import (
 	"go-hep.org/x/hep/groot/rtree"
 )
 
+// CopyOption controls how Copy behaves.
+type CopyOption func(*copyCmd)
+
+// CopyRecover enables the recovery of input ROOT files that were not
+// properly closed or that were truncated.
+// See riofs.WithRecover for details.
+func CopyRecover(v bool) CopyOption {
+	return func(cmd *copyCmd) {
+		cmd.recover = v
+	}
+}
+
 // Copy copies the content of the ROOT files fnames into the output
 // ROOT file named oname.
-func Copy(oname string, fnames []string) error {
+//
+// Copy's behaviour can be customized with a set of optional CopyOptions.
+func Copy(oname string, fnames []string, opts ...CopyOption) error {
 	o, err := groot.Create(oname)
 	if err != nil {
 		return fmt.Errorf("could not create output ROOT file %q: %w", oname, err)
@@ -26,6 +40,10 @@ func Copy(oname string, fnames []string) error {
 	defer o.Close()
 
 	var cmd copyCmd
+	for _, opt := range opts {
+		opt(&cmd)
+	}
+
 	for _, arg := range fnames {
 		err := cmd.process(o, arg)
 		if err != nil {
@@ -40,7 +58,9 @@ func Copy(oname string, fnames []string) error {
 	return nil
 }
 
-type copyCmd struct{}
+type copyCmd struct {
+	recover bool
+}
 
 func (cmd copyCmd) process(o *riofs.File, arg string) error {
 	log.Printf("copying %q...", arg)
@@ -51,7 +71,12 @@ func (cmd copyCmd) process(o *riofs.File, arg string) error {
 	}
 	re := regexp.MustCompile(sel)
 
-	f, err := groot.Open(fname)
+	var fopts []riofs.FileOption
+	if cmd.recover {
+		fopts = append(fopts, riofs.WithRecover())
+	}
+
+	f, err := groot.Open(fname, fopts...)
 	if err != nil {
 		return fmt.Errorf("could not open input ROOT file %q: %w", fname, err)
 	}
@@ -10,13 +10,16 @@ import (
 	stdpath "path"
 
 	"go-hep.org/x/hep/groot"
-	"go-hep.org/x/hep/groot/rhist"
 	"go-hep.org/x/hep/groot/riofs"
 	"go-hep.org/x/hep/groot/root"
 	"go-hep.org/x/hep/groot/rtree"
 )
 
 // Merge merges all input fnames ROOT files into the output oname one.
+//
+// Objects are matched by their full path within the input files.
+// Objects (and directories) present in only some of the input files are
+// merged from these files only.
 func Merge(oname string, fnames []string, verbose bool) error {
 	o, err := groot.Create(oname)
 	if err != nil {
@@ -24,21 +27,19 @@ func Merge(oname string, fnames []string, verbose bool) error {
 	}
 	defer o.Close()
 
-	cmd := mergeCmd{verbose: verbose}
-	tsks, err := cmd.mergeTasksFrom(o, fnames[0])
-	if err != nil {
-		return fmt.Errorf("could not create merge tasks: %w", err)
+	cmd := mergeCmd{
+		verbose: verbose,
+		index:   make(map[string]int),
 	}
-
-	for _, fname := range fnames[1:] {
-		err := cmd.process(tsks, fname)
+	for _, fname := range fnames {
+		err := cmd.process(o, fname)
 		if err != nil {
 			return fmt.Errorf("could not process ROOT file %q: %w", fname, err)
 		}
 	}
 
-	for i := range tsks {
-		tsk := &tsks[i]
+	for i := range cmd.tsks {
+		tsk := &cmd.tsks[i]
 		err := tsk.close(o)
 		if err != nil {
 			return fmt.Errorf("could not close task %d (%s): %w", i, tsk.path(), err)
@@ -55,6 +56,9 @@ func Merge(oname string, fnames []string, verbose bool) error {
 
 type mergeCmd struct {
 	verbose bool
+
+	tsks  []task
+	index map[string]int // index of tasks by path of the merged object
 }
 
 func (mergeCmd) acceptObj(obj root.Object) bool {
@@ -63,8 +67,6 @@ func (mergeCmd) acceptObj(obj root.Object) bool {
 		// need to specially handle rtree.Tree.
 		// rtree.Tree does not implement root.Merger: only rtree.Writer does.
 		return true
-	case rhist.H1, rhist.H2:
-		return true
 	case root.Merger:
 		return true
 	default:
@@ -72,28 +74,6 @@ func (mergeCmd) acceptObj(obj root.Object) bool {
 	}
 }
 
-func (cmd mergeCmd) process(tsks []task, fname string) error {
-	if cmd.verbose {
-		log.Printf("merging [%s]...", fname)
-	}
-
-	f, err := groot.Open(fname)
-	if err != nil {
-		return fmt.Errorf("could not open input ROOT file %q: %w", fname, err)
-	}
-	defer f.Close()
-
-	for i := range tsks {
-		tsk := &tsks[i]
-		err = tsk.merge(f)
-		if err != nil {
-			return fmt.Errorf("could not merge task %d (%s) for file %q: %w", i, tsk.path(), fname, err)
-		}
-	}
-
-	return nil
-}
-
 type task struct {
 	dir string
 	key string
@@ -102,14 +82,20 @@ type task struct {
 	verbose bool
 }
 
-func (cmd *mergeCmd) mergeTasksFrom(o *riofs.File, fname string) ([]task, error) {
+// process merges all the objects of the input fname ROOT file into the
+// output o ROOT file.
+// Objects seen for the first time are selected and seed new merge tasks.
+func (cmd *mergeCmd) process(o *riofs.File, fname string) error {
+	if cmd.verbose {
+		log.Printf("merging [%s]...", fname)
+	}
+
 	f, err := groot.Open(fname)
 	if err != nil {
-		return nil, fmt.Errorf("could not open input ROOT file %q: %w", fname, err)
+		return fmt.Errorf("could not open input ROOT file %q: %w", fname, err)
 	}
 	defer f.Close()
 
-	var tsks []task
 	err = riofs.Walk(f, func(path string, obj root.Object, err error) error {
 		if err != nil {
 			return err
@@ -120,6 +106,9 @@ func (cmd *mergeCmd) mergeTasksFrom(o *riofs.File, fname string) ([]task, error)
 		}
 
 		if _, ok := obj.(riofs.Directory); ok {
+			if _, err := riofs.Dir(o).Get(name); err == nil {
+				return nil
+			}
 			_, err := riofs.Dir(o).Mkdir(name)
 			if err != nil {
 				return fmt.Errorf("could not create dir %q in output ROOT file: %w", name, err)
@@ -133,83 +122,84 @@ func (cmd *mergeCmd) mergeTasksFrom(o *riofs.File, fname string) ([]task, error)
 		if !cmd.acceptObj(obj) {
 			return nil
 		}
-		if cmd.verbose {
-			log.Printf("selecting %q", name)
-		}
-
-		var (
-			dirName = stdpath.Dir(name)
-			objName = stdpath.Base(name)
-			dir     = riofs.Directory(o)
-		)
 
-		if dirName != "/" && dirName != "" {
-			obj, err := riofs.Dir(o).Get(dirName)
+		if i, ok := cmd.index[name]; ok {
+			tsk := &cmd.tsks[i]
+			err := tsk.mergeObj(tsk.obj, obj)
 			if err != nil {
-				return fmt.Errorf("could not get dir %q from output ROOT file: %w", dirName, err)
+				return fmt.Errorf("could not merge %q: %w", name, err)
 			}
-			dir = obj.(riofs.Directory)
+			return nil
 		}
 
-		switch oo := obj.(type) {
-		case rtree.Tree:
-			w, err := rtree.NewWriter(dir, objName, rtree.WriteVarsFromTree(oo), rtree.WithTitle(oo.Title()))
-			if err != nil {
-				return fmt.Errorf("could not create output ROOT tree %q: %w", name, err)
-			}
-
-			r, err := rtree.NewReader(oo, nil)
-			if err != nil {
-				return fmt.Errorf(
-					"could not create input ROOT tree reader %q: %w",
-					name, err,
-				)
-			}
-			defer r.Close()
-
-			_, err = rtree.Copy(w, r)
-			if err != nil {
-				return fmt.Errorf("could not seed output ROOT tree %q: %w", name, err)
-			}
-			obj = w
+		if cmd.verbose {
+			log.Printf("selecting %q", name)
 		}
 
-		tsks = append(tsks, task{
-			dir:     dirName,
-			key:     objName,
-			obj:     obj,
-			verbose: cmd.verbose,
-		})
+		tsk, err := cmd.newTask(o, name, obj)
+		if err != nil {
+			return err
+		}
+		cmd.index[name] = len(cmd.tsks)
+		cmd.tsks = append(cmd.tsks, tsk)
 		return nil
 	})
 	if err != nil {
-		return nil, fmt.Errorf("could not inspect input ROOT file: %w", err)
+		return fmt.Errorf("could not merge input ROOT file: %w", err)
 	}
 
-	if cmd.verbose {
-		log.Printf("merging [%s]...", fname)
-	}
-
-	return tsks, nil
+	return nil
 }
 
-func (tsk *task) path() string {
-	return stdpath.Join(tsk.dir, tsk.key)
-}
+// newTask creates a new merge task, seeded with the provided object.
+func (cmd *mergeCmd) newTask(o *riofs.File, name string, obj root.Object) (task, error) {
+	var (
+		dirName = stdpath.Dir(name)
+		objName = stdpath.Base(name)
+		dir     = riofs.Directory(o)
+	)
 
-func (tsk *task) merge(f *riofs.File) error {
-	name := tsk.path()
-	obj, err := riofs.Dir(f).Get(name)
-	if err != nil {
-		return fmt.Errorf("could not get %q: %w", name, err)
+	if dirName != "/" && dirName != "" {
+		obj, err := riofs.Dir(o).Get(dirName)
+		if err != nil {
+			return task{}, fmt.Errorf("could not get dir %q from output ROOT file: %w", dirName, err)
+		}
+		dir = obj.(riofs.Directory)
 	}
 
-	err = tsk.mergeObj(tsk.obj, obj)
-	if err != nil {
-		return fmt.Errorf("could not merge %q: %w", name, err)
+	switch oo := obj.(type) {
+	case rtree.Tree:
+		w, err := rtree.NewWriter(dir, objName, rtree.WriteVarsFromTree(oo), rtree.WithTitle(oo.Title()))
+		if err != nil {
+			return task{}, fmt.Errorf("could not create output ROOT tree %q: %w", name, err)
+		}
+
+		r, err := rtree.NewReader(oo, nil)
+		if err != nil {
+			return task{}, fmt.Errorf(
+				"could not create input ROOT tree reader %q: %w",
+				name, err,
+			)
+		}
+		defer r.Close()
+
+		_, err = rtree.Copy(w, r)
+		if err != nil {
+			return task{}, fmt.Errorf("could not seed output ROOT tree %q: %w", name, err)
+		}
+		obj = w
 	}
 
-	return nil
+	return task{
+		dir:     dirName,
+		key:     objName,
+		obj:     obj,
+		verbose: cmd.verbose,
+	}, nil
+}
+
+func (tsk *task) path() string {
+	return stdpath.Join(tsk.dir, tsk.key)
 }
 
 func (tsk *task) close(f *riofs.File) error {
@@ -238,15 +228,9 @@ func (tsk *task) mergeObj(dst, src root.Object) error {
 	}
 
 	switch dst := dst.(type) {
-	case rhist.H2:
-		return tsk.mergeH2(dst, src.(rhist.H2))
 	case root.Merger:
 		return dst.ROOTMerge(src)
 	default:
 		return fmt.Errorf("could not find suitable merge-API for (dst=%T, src=%T)", dst, src)
 	}
 }
-
-func (tsk *task) mergeH2(dst, src rhist.H2) error {
-	panic("not implemented")
-}
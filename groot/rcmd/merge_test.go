@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"go-hep.org/x/hep/groot"
@@ -83,7 +84,21 @@ func TestMerge(t *testing.T) {
 			name:   "h2d-2",
 			inputs: []funcT{makeH2D(1), makeH2D(1)},
 			output: makeH2D(2),
-			panics: "not implemented", // FIXME(sbinet)
+		},
+		{
+			name:   "h2d-3",
+			inputs: []funcT{makeH2D(1), makeH2D(1), makeH2D(1)},
+			output: makeH2D(3),
+		},
+		{
+			name:   "h2f-2",
+			inputs: []funcT{makeH2F(1), makeH2F(1)},
+			output: makeH2F(2),
+		},
+		{
+			name:   "dirs",
+			inputs: []funcT{makeDirs(1, 0), makeDirs(1, 1), makeDirs(1, 1)},
+			output: makeDirs(3, 2),
 		},
 		{
 			name:   "graph-1",
@@ -173,6 +188,50 @@ func TestMerge(t *testing.T) {
 	}
 }
 
+func TestMergeIncompatible(t *testing.T) {
+	tmp, err := os.MkdirTemp("", "groot-root-merge-")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	var (
+		fnames = []string{
+			filepath.Join(tmp, "h2-00.root"),
+			filepath.Join(tmp, "h2-01.root"),
+		}
+		oname = filepath.Join(tmp, "h2.out.root")
+	)
+	for i, fname := range fnames {
+		f, err := groot.Create(fname)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		defer f.Close()
+
+		h := hbook.NewH2D(10, 0, 10, 10+i, 0, 10)
+		err = f.Put("h2", rootcnv.FromH2D(h))
+		if err != nil {
+			t.Fatalf("could not save H2D: %+v", err)
+		}
+
+		err = f.Close()
+		if err != nil {
+			t.Fatalf("could not close file: %+v", err)
+		}
+	}
+
+	err = rcmd.Merge(oname, fnames, false)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	const want = "incompatible y-axes (nbins=10, range=[0, 10]) and (nbins=11, range=[0, 10])"
+	if got := err.Error(); !strings.Contains(got, want) {
+		t.Fatalf("invalid error:\ngot= %q\nwant=%q", got, want)
+	}
+}
+
 func makeFlatTree(n int) func(t *testing.T, fname string) error {
 	return func(t *testing.T, fname string) error {
 		type Data struct {
@@ -389,6 +448,95 @@ func makeH2D(n int) func(t *testing.T, fname string) error {
 	}
 }
 
+func makeH2F(n int) func(t *testing.T, fname string) error {
+	return func(t *testing.T, fname string) error {
+		f, err := groot.Create(fname)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		defer f.Close()
+
+		dir, err := riofs.Dir(f).Mkdir("dir-1/dir-11")
+		if err != nil {
+			t.Fatalf("could not create directory: %+v", err)
+		}
+
+		h := hbook.NewH2D(10, 0, 10, 5, 0, 5)
+		h.Annotation()["title"] = "h2f"
+		for i := 0; i < n; i++ {
+			h.Fill(5, 2, 1)
+			h.Fill(-1, 6, 2)
+			h.Fill(11, 6, 3)
+		}
+
+		err = dir.Put("h2f", rhist.NewH2FFrom(h))
+		if err != nil {
+			t.Fatalf("could not save H2F: %+v", err)
+		}
+
+		err = f.Close()
+		if err != nil {
+			t.Fatalf("could not close file: %+v", err)
+		}
+
+		return nil
+	}
+}
+
+// makeDirs creates a ROOT file with a histogram, filled n1 times, under a
+// nested directory.
+// If n2 > 0, an additional directory and histogram, filled n2 times, are created.
+func makeDirs(n1, n2 int) func(t *testing.T, fname string) error {
+	return func(t *testing.T, fname string) error {
+		f, err := groot.Create(fname)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		defer f.Close()
+
+		dir, err := riofs.Dir(f).Mkdir("dir-1/dir-11/dir-111")
+		if err != nil {
+			t.Fatalf("could not create directory: %+v", err)
+		}
+
+		h := hbook.NewH1D(10, 0, 10)
+		h.Annotation()["title"] = "h1"
+		for i := 0; i < n1; i++ {
+			h.Fill(5, 1)
+		}
+
+		err = dir.Put("h1", rootcnv.FromH1D(h))
+		if err != nil {
+			t.Fatalf("could not save H1D: %+v", err)
+		}
+
+		if n2 > 0 {
+			dir, err := riofs.Dir(f).Mkdir("dir-2/dir-21")
+			if err != nil {
+				t.Fatalf("could not create directory: %+v", err)
+			}
+
+			h := hbook.NewH2D(10, 0, 10, 10, 0, 10)
+			h.Annotation()["title"] = "h2"
+			for i := 0; i < n2; i++ {
+				h.Fill(5, 5, 1)
+			}
+
+			err = dir.Put("h2", rootcnv.FromH2D(h))
+			if err != nil {
+				t.Fatalf("could not save H2D: %+v", err)
+			}
+		}
+
+		err = f.Close()
+		if err != nil {
+			t.Fatalf("could not close file: %+v", err)
+		}
+
+		return nil
+	}
+}
+
 func makeGraph(beg, end int) func(t *testing.T, fname string) error {
 	return func(t *testing.T, fname string) error {
 		f, err := groot.Create(fname)
@@ -13,6 +13,7 @@ import (
 )
 
 func init() {
+	StreamerInfos.Add(NewCxxStreamerInfo("TAtt3D", 1, 0x757a, []rbytes.StreamerElement{}))
 	StreamerInfos.Add(NewCxxStreamerInfo("TAttAxis", 4, 0x5c6fff3e, []rbytes.StreamerElement{
 		&StreamerBasicType{StreamerElement: Element{
 			Name:   *rbase.NewNamed("fNdivisions", "Number of divisions(10000*n3 + 100*n2 + n1)"),
@@ -3224,6 +3225,270 @@ func init() {
 			Factor: 0.000000,
 		}.New(), 1, 61),
 	}))
+	StreamerInfos.Add(NewCxxStreamerInfo("TGraph2D", 1, 0x3f8d192e, []rbytes.StreamerElement{
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TNamed", "The basis for a named object (name, title)"),
+			Type:   rmeta.Base,
+			Size:   0,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, -541636036, 0, 0, 0},
+			Offset: 0,
+			EName:  "BASE",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1),
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TAttLine", "Line attributes"),
+			Type:   rmeta.Base,
+			Size:   0,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, -1811462839, 0, 0, 0},
+			Offset: 0,
+			EName:  "BASE",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 2),
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TAttFill", "Fill area attributes"),
+			Type:   rmeta.Base,
+			Size:   0,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, -2545006, 0, 0, 0},
+			Offset: 0,
+			EName:  "BASE",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 2),
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TAttMarker", "Marker attributes"),
+			Type:   rmeta.Base,
+			Size:   0,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 689802220, 0, 0, 0},
+			Offset: 0,
+			EName:  "BASE",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 2),
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fNpoints", "Number of points in the data set"),
+			Type:   rmeta.Counter,
+			Size:   4,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "int",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fNpx", "Number of bins along X in fHistogram"),
+			Type:   rmeta.Int,
+			Size:   4,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "int",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fNpy", "Number of bins along Y in fHistogram"),
+			Type:   rmeta.Int,
+			Size:   4,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "int",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fMaxIter", "Maximum number of iterations to find Delaunay triangles"),
+			Type:   rmeta.Int,
+			Size:   4,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "int",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		NewStreamerBasicPointer(Element{
+			Name:   *rbase.NewNamed("fX", "[fNpoints]"),
+			Type:   48,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double*",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1, "fNpoints", "TGraph2D"),
+		NewStreamerBasicPointer(Element{
+			Name:   *rbase.NewNamed("fY", "[fNpoints] Data set to be plotted"),
+			Type:   48,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double*",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1, "fNpoints", "TGraph2D"),
+		NewStreamerBasicPointer(Element{
+			Name:   *rbase.NewNamed("fZ", "[fNpoints]"),
+			Type:   48,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double*",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1, "fNpoints", "TGraph2D"),
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fMinimum", "Minimum value for plotting along z"),
+			Type:   rmeta.Double,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fMaximum", "Maximum value for plotting along z"),
+			Type:   rmeta.Double,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fMargin", "Extra space (in %) around interpolated area for fHistogram"),
+			Type:   rmeta.Double,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fZout", "fHistogram bin height for points lying outside the interpolated area"),
+			Type:   rmeta.Double,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerObjectPointer{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fFunctions", "Pointer to list of functions (fits and user)"),
+			Type:   rmeta.ObjectP,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "TList*",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+	}))
+	StreamerInfos.Add(NewCxxStreamerInfo("TGraph2DErrors", 1, 0x4f13ae71, []rbytes.StreamerElement{
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TGraph2D", "Set of n x[i],y[i],z[i] points with 3-d graphics including Delaunay triangulation"),
+			Type:   rmeta.Base,
+			Size:   0,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 1066211630, 0, 0, 0},
+			Offset: 0,
+			EName:  "BASE",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1),
+		NewStreamerBasicPointer(Element{
+			Name:   *rbase.NewNamed("fEX", "[fNpoints] array of X errors"),
+			Type:   48,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double*",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1, "fNpoints", "TGraph2D"),
+		NewStreamerBasicPointer(Element{
+			Name:   *rbase.NewNamed("fEY", "[fNpoints] array of Y errors"),
+			Type:   48,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double*",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1, "fNpoints", "TGraph2D"),
+		NewStreamerBasicPointer(Element{
+			Name:   *rbase.NewNamed("fEZ", "[fNpoints] array of Z errors"),
+			Type:   48,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double*",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1, "fNpoints", "TGraph2D"),
+	}))
 	StreamerInfos.Add(NewCxxStreamerInfo("TH1", 8, 0x1c3740c4, []rbytes.StreamerElement{
 		NewStreamerBase(Element{
 			Name:   *rbase.NewNamed("TNamed", "The basis for a named object (name, title)"),
@@ -4167,67 +4432,326 @@ func init() {
 			Size:   8,
 			ArrLen: 0,
 			ArrDim: 0,
-			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fYmin", "Y minimum value"),
+			Type:   rmeta.Double,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fXmax", "X maximum value"),
+			Type:   rmeta.Double,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fYmax", "Y maximum value"),
+			Type:   rmeta.Double,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+	}))
+	StreamerInfos.Add(NewCxxStreamerInfo("TH2S", 4, 0x1256ca1c, []rbytes.StreamerElement{
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TH2", "2-Dim histogram base class"),
+			Type:   rmeta.Base,
+			Size:   0,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 25310335, 0, 0, 0},
+			Offset: 0,
+			EName:  "BASE",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 5),
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TArrayS", "Array of shorts"),
+			Type:   rmeta.Base,
+			Size:   0,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 56398612, 0, 0, 0},
+			Offset: 0,
+			EName:  "BASE",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1),
+	}))
+	StreamerInfos.Add(NewCxxStreamerInfo("TH3", 6, 0x42d2445f, []rbytes.StreamerElement{
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TH1", "1-Dim histogram base class"),
+			Type:   rmeta.Base,
+			Size:   0,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 473383108, 0, 0, 0},
+			Offset: 0,
+			EName:  "BASE",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 8),
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TAtt3D", "3D attributes"),
+			Type:   rmeta.Base,
+			Size:   0,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 30074, 0, 0, 0},
+			Offset: 0,
+			EName:  "BASE",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1),
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fTsumwy", "Total Sum of weight*Y"),
+			Type:   rmeta.Double,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fTsumwy2", "Total Sum of weight*Y*Y"),
+			Type:   rmeta.Double,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fTsumwxy", "Total Sum of weight*X*Y"),
+			Type:   rmeta.Double,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fTsumwz", "Total Sum of weight*Z"),
+			Type:   rmeta.Double,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fTsumwz2", "Total Sum of weight*Z*Z"),
+			Type:   rmeta.Double,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fTsumwxz", "Total Sum of weight*X*Z"),
+			Type:   rmeta.Double,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fTsumwyz", "Total Sum of weight*Y*Z"),
+			Type:   rmeta.Double,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+	}))
+	StreamerInfos.Add(NewCxxStreamerInfo("TH3C", 4, 0xa1ff8d94, []rbytes.StreamerElement{
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TH3", "3-Dim histogram base class"),
+			Type:   rmeta.Base,
+			Size:   0,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 1121076319, 0, 0, 0},
+			Offset: 0,
+			EName:  "BASE",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 6),
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TArrayC", "Array of chars"),
+			Type:   rmeta.Base,
+			Size:   0,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, -1366845130, 0, 0, 0},
+			Offset: 0,
+			EName:  "BASE",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1),
+	}))
+	StreamerInfos.Add(NewCxxStreamerInfo("TH3D", 4, 0x64b9ff86, []rbytes.StreamerElement{
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TH3", "3-Dim histogram base class"),
+			Type:   rmeta.Base,
+			Size:   0,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 1121076319, 0, 0, 0},
+			Offset: 0,
+			EName:  "BASE",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 6),
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TArrayD", "Array of doubles"),
+			Type:   rmeta.Base,
+			Size:   0,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 1899622196, 0, 0, 0},
+			Offset: 0,
+			EName:  "BASE",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1),
+	}))
+	StreamerInfos.Add(NewCxxStreamerInfo("TH3F", 4, 0x4d9c3f2b, []rbytes.StreamerElement{
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TH3", "3-Dim histogram base class"),
+			Type:   rmeta.Base,
+			Size:   0,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 1121076319, 0, 0, 0},
 			Offset: 0,
-			EName:  "double",
+			EName:  "BASE",
 			XMin:   0.000000,
 			XMax:   0.000000,
 			Factor: 0.000000,
-		}.New()},
-		&StreamerBasicType{StreamerElement: Element{
-			Name:   *rbase.NewNamed("fYmin", "Y minimum value"),
-			Type:   rmeta.Double,
-			Size:   8,
+		}.New(), 6),
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TArrayF", "Array of floats"),
+			Type:   rmeta.Base,
+			Size:   0,
 			ArrLen: 0,
 			ArrDim: 0,
-			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			MaxIdx: [5]int32{0, 1510733553, 0, 0, 0},
 			Offset: 0,
-			EName:  "double",
+			EName:  "BASE",
 			XMin:   0.000000,
 			XMax:   0.000000,
 			Factor: 0.000000,
-		}.New()},
-		&StreamerBasicType{StreamerElement: Element{
-			Name:   *rbase.NewNamed("fXmax", "X maximum value"),
-			Type:   rmeta.Double,
-			Size:   8,
+		}.New(), 1),
+	}))
+	StreamerInfos.Add(NewCxxStreamerInfo("TH3I", 4, 0xcd7e0ddd, []rbytes.StreamerElement{
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TH3", "3-Dim histogram base class"),
+			Type:   rmeta.Base,
+			Size:   0,
 			ArrLen: 0,
 			ArrDim: 0,
-			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			MaxIdx: [5]int32{0, 1121076319, 0, 0, 0},
 			Offset: 0,
-			EName:  "double",
+			EName:  "BASE",
 			XMin:   0.000000,
 			XMax:   0.000000,
 			Factor: 0.000000,
-		}.New()},
-		&StreamerBasicType{StreamerElement: Element{
-			Name:   *rbase.NewNamed("fYmax", "Y maximum value"),
-			Type:   rmeta.Double,
-			Size:   8,
+		}.New(), 6),
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TArrayI", "Array of ints"),
+			Type:   rmeta.Base,
+			Size:   0,
 			ArrLen: 0,
 			ArrDim: 0,
-			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			MaxIdx: [5]int32{0, -640323129, 0, 0, 0},
 			Offset: 0,
-			EName:  "double",
+			EName:  "BASE",
 			XMin:   0.000000,
 			XMax:   0.000000,
 			Factor: 0.000000,
-		}.New()},
+		}.New(), 1),
 	}))
-	StreamerInfos.Add(NewCxxStreamerInfo("TH2S", 4, 0x1256ca1c, []rbytes.StreamerElement{
+	StreamerInfos.Add(NewCxxStreamerInfo("TH3S", 4, 0xf75646b2, []rbytes.StreamerElement{
 		NewStreamerBase(Element{
-			Name:   *rbase.NewNamed("TH2", "2-Dim histogram base class"),
+			Name:   *rbase.NewNamed("TH3", "3-Dim histogram base class"),
 			Type:   rmeta.Base,
 			Size:   0,
 			ArrLen: 0,
 			ArrDim: 0,
-			MaxIdx: [5]int32{0, 25310335, 0, 0, 0},
+			MaxIdx: [5]int32{0, 1121076319, 0, 0, 0},
 			Offset: 0,
 			EName:  "BASE",
 			XMin:   0.000000,
 			XMax:   0.000000,
 			Factor: 0.000000,
-		}.New(), 5),
+		}.New(), 6),
 		NewStreamerBase(Element{
 			Name:   *rbase.NewNamed("TArrayS", "Array of shorts"),
 			Type:   rmeta.Base,
@@ -6076,91 +6600,294 @@ func init() {
 			ArrDim: 0,
 			MaxIdx: [5]int32{0, 0, 0, 0, 0},
 			Offset: 0,
-			EName:  "TString",
+			EName:  "TString",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerString{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fClonesName", "Name of class in TClonesArray (if any)"),
+			Type:   rmeta.TString,
+			Size:   24,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "TString",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fCheckSum", "CheckSum of class"),
+			Type:   rmeta.UInt,
+			Size:   4,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "unsigned int",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fClassVersion", "Version number of class"),
+			Type:   rmeta.Short,
+			Size:   2,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "short",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fID", "element serial number in fInfo"),
+			Type:   rmeta.Int,
+			Size:   4,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "int",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fType", "Branch type"),
+			Type:   rmeta.Int,
+			Size:   4,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "int",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fStreamerType", "branch streamer type"),
+			Type:   rmeta.Int,
+			Size:   4,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "int",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fMaximum", "Maximum entries for a TClonesArray or variable array"),
+			Type:   rmeta.Int,
+			Size:   4,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "int",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerObjectPointer{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fBranchCount", "pointer to primary branchcount branch"),
+			Type:   rmeta.ObjectP,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "TBranchElement*",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerObjectPointer{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fBranchCount2", "pointer to secondary branchcount branch"),
+			Type:   rmeta.ObjectP,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "TBranchElement*",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+	}))
+	StreamerInfos.Add(NewCxxStreamerInfo("TBranchObject", 1, 0xa4720f49, []rbytes.StreamerElement{
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TBranch", "Branch descriptor"),
+			Type:   rmeta.Base,
+			Size:   0,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 278366892, 0, 0, 0},
+			Offset: 0,
+			EName:  "BASE",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 13),
+		&StreamerString{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fClassName", "Class name of referenced object"),
+			Type:   rmeta.TString,
+			Size:   24,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "TString",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+	}))
+	StreamerInfos.Add(NewCxxStreamerInfo("TBranchRef", 1, 0x2360b3fd, []rbytes.StreamerElement{
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TBranch", "Branch descriptor"),
+			Type:   rmeta.Base,
+			Size:   0,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 278366892, 0, 0, 0},
+			Offset: 0,
+			EName:  "BASE",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 13),
+		&StreamerObjectPointer{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fRefTable", "pointer to the TRefTable"),
+			Type:   rmeta.ObjectP,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "TRefTable*",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+	}))
+	StreamerInfos.Add(NewCxxStreamerInfo("TChain", 5, 0x3a9ec568, []rbytes.StreamerElement{
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TTree", "Tree descriptor (the main ROOT I/O class)"),
+			Type:   rmeta.Base,
+			Size:   0,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 1919213695, 0, 0, 0},
+			Offset: 0,
+			EName:  "BASE",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 20),
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fTreeOffsetLen", "Current size of fTreeOffset array"),
+			Type:   rmeta.Counter,
+			Size:   4,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "int",
 			XMin:   0.000000,
 			XMax:   0.000000,
 			Factor: 0.000000,
 		}.New()},
-		&StreamerString{StreamerElement: Element{
-			Name:   *rbase.NewNamed("fClonesName", "Name of class in TClonesArray (if any)"),
-			Type:   rmeta.TString,
-			Size:   24,
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fNtrees", "Number of trees"),
+			Type:   rmeta.Int,
+			Size:   4,
 			ArrLen: 0,
 			ArrDim: 0,
 			MaxIdx: [5]int32{0, 0, 0, 0, 0},
 			Offset: 0,
-			EName:  "TString",
+			EName:  "int",
 			XMin:   0.000000,
 			XMax:   0.000000,
 			Factor: 0.000000,
 		}.New()},
-		&StreamerBasicType{StreamerElement: Element{
-			Name:   *rbase.NewNamed("fCheckSum", "CheckSum of class"),
-			Type:   rmeta.UInt,
-			Size:   4,
+		NewStreamerBasicPointer(Element{
+			Name:   *rbase.NewNamed("fTreeOffset", "[fTreeOffsetLen] Array of variables"),
+			Type:   56,
+			Size:   8,
 			ArrLen: 0,
 			ArrDim: 0,
 			MaxIdx: [5]int32{0, 0, 0, 0, 0},
 			Offset: 0,
-			EName:  "unsigned int",
+			EName:  "Long64_t*",
 			XMin:   0.000000,
 			XMax:   0.000000,
 			Factor: 0.000000,
-		}.New()},
-		&StreamerBasicType{StreamerElement: Element{
-			Name:   *rbase.NewNamed("fClassVersion", "Version number of class"),
-			Type:   rmeta.Short,
-			Size:   2,
+		}.New(), 5, "fTreeOffsetLen", "TChain"),
+		&StreamerObjectPointer{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fFiles", "-> List of file names containing the trees (TChainElement, owned)"),
+			Type:   rmeta.Objectp,
+			Size:   8,
 			ArrLen: 0,
 			ArrDim: 0,
 			MaxIdx: [5]int32{0, 0, 0, 0, 0},
 			Offset: 0,
-			EName:  "short",
+			EName:  "TObjArray*",
 			XMin:   0.000000,
 			XMax:   0.000000,
 			Factor: 0.000000,
 		}.New()},
-		&StreamerBasicType{StreamerElement: Element{
-			Name:   *rbase.NewNamed("fID", "element serial number in fInfo"),
-			Type:   rmeta.Int,
-			Size:   4,
+		&StreamerObjectPointer{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fStatus", "-> List of active/inactive branches (TChainElement, owned)"),
+			Type:   rmeta.Objectp,
+			Size:   8,
 			ArrLen: 0,
 			ArrDim: 0,
 			MaxIdx: [5]int32{0, 0, 0, 0, 0},
 			Offset: 0,
-			EName:  "int",
+			EName:  "TList*",
 			XMin:   0.000000,
 			XMax:   0.000000,
 			Factor: 0.000000,
 		}.New()},
-		&StreamerBasicType{StreamerElement: Element{
-			Name:   *rbase.NewNamed("fType", "Branch type"),
-			Type:   rmeta.Int,
-			Size:   4,
+	}))
+	StreamerInfos.Add(NewCxxStreamerInfo("TEntryList", 2, 0x56a6120e, []rbytes.StreamerElement{
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TNamed", "The basis for a named object (name, title)"),
+			Type:   rmeta.Base,
+			Size:   0,
 			ArrLen: 0,
 			ArrDim: 0,
-			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			MaxIdx: [5]int32{0, -541636036, 0, 0, 0},
 			Offset: 0,
-			EName:  "int",
+			EName:  "BASE",
 			XMin:   0.000000,
 			XMax:   0.000000,
 			Factor: 0.000000,
-		}.New()},
-		&StreamerBasicType{StreamerElement: Element{
-			Name:   *rbase.NewNamed("fStreamerType", "branch streamer type"),
-			Type:   rmeta.Int,
-			Size:   4,
+		}.New(), 1),
+		&StreamerObjectPointer{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fLists", "a list of underlying entry lists for each tree of a chain"),
+			Type:   rmeta.ObjectP,
+			Size:   8,
 			ArrLen: 0,
 			ArrDim: 0,
 			MaxIdx: [5]int32{0, 0, 0, 0, 0},
 			Offset: 0,
-			EName:  "int",
+			EName:  "TList*",
 			XMin:   0.000000,
 			XMax:   0.000000,
 			Factor: 0.000000,
 		}.New()},
 		&StreamerBasicType{StreamerElement: Element{
-			Name:   *rbase.NewNamed("fMaximum", "Maximum entries for a TClonesArray or variable array"),
+			Name:   *rbase.NewNamed("fNBlocks", "number of TEntryListBlocks"),
 			Type:   rmeta.Int,
 			Size:   4,
 			ArrLen: 0,
@@ -6173,48 +6900,46 @@ func init() {
 			Factor: 0.000000,
 		}.New()},
 		&StreamerObjectPointer{StreamerElement: Element{
-			Name:   *rbase.NewNamed("fBranchCount", "pointer to primary branchcount branch"),
+			Name:   *rbase.NewNamed("fBlocks", "blocks with indices of passing events (TEntryListBlocks)"),
 			Type:   rmeta.ObjectP,
 			Size:   8,
 			ArrLen: 0,
 			ArrDim: 0,
 			MaxIdx: [5]int32{0, 0, 0, 0, 0},
 			Offset: 0,
-			EName:  "TBranchElement*",
+			EName:  "TObjArray*",
 			XMin:   0.000000,
 			XMax:   0.000000,
 			Factor: 0.000000,
 		}.New()},
-		&StreamerObjectPointer{StreamerElement: Element{
-			Name:   *rbase.NewNamed("fBranchCount2", "pointer to secondary branchcount branch"),
-			Type:   rmeta.ObjectP,
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fN", "number of entries in the list"),
+			Type:   rmeta.Long64,
 			Size:   8,
 			ArrLen: 0,
 			ArrDim: 0,
 			MaxIdx: [5]int32{0, 0, 0, 0, 0},
 			Offset: 0,
-			EName:  "TBranchElement*",
+			EName:  "Long64_t",
 			XMin:   0.000000,
 			XMax:   0.000000,
 			Factor: 0.000000,
 		}.New()},
-	}))
-	StreamerInfos.Add(NewCxxStreamerInfo("TBranchObject", 1, 0xa4720f49, []rbytes.StreamerElement{
-		NewStreamerBase(Element{
-			Name:   *rbase.NewNamed("TBranch", "Branch descriptor"),
-			Type:   rmeta.Base,
-			Size:   0,
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fEntriesToProcess", "used on proof to set the number of entries to process in a packet"),
+			Type:   rmeta.Long64,
+			Size:   8,
 			ArrLen: 0,
 			ArrDim: 0,
-			MaxIdx: [5]int32{0, 278366892, 0, 0, 0},
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
 			Offset: 0,
-			EName:  "BASE",
+			EName:  "Long64_t",
 			XMin:   0.000000,
 			XMax:   0.000000,
 			Factor: 0.000000,
-		}.New(), 13),
+		}.New()},
 		&StreamerString{StreamerElement: Element{
-			Name:   *rbase.NewNamed("fClassName", "Class name of referenced object"),
+			Name:   *rbase.NewNamed("fTreeName", "name of the tree"),
 			Type:   rmeta.TString,
 			Size:   24,
 			ArrLen: 0,
@@ -6226,52 +6951,50 @@ func init() {
 			XMax:   0.000000,
 			Factor: 0.000000,
 		}.New()},
-	}))
-	StreamerInfos.Add(NewCxxStreamerInfo("TBranchRef", 1, 0x2360b3fd, []rbytes.StreamerElement{
-		NewStreamerBase(Element{
-			Name:   *rbase.NewNamed("TBranch", "Branch descriptor"),
-			Type:   rmeta.Base,
-			Size:   0,
+		&StreamerString{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fFileName", "name of the file, where the tree is"),
+			Type:   rmeta.TString,
+			Size:   24,
 			ArrLen: 0,
 			ArrDim: 0,
-			MaxIdx: [5]int32{0, 278366892, 0, 0, 0},
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
 			Offset: 0,
-			EName:  "BASE",
+			EName:  "TString",
 			XMin:   0.000000,
 			XMax:   0.000000,
 			Factor: 0.000000,
-		}.New(), 13),
-		&StreamerObjectPointer{StreamerElement: Element{
-			Name:   *rbase.NewNamed("fRefTable", "pointer to the TRefTable"),
-			Type:   rmeta.ObjectP,
-			Size:   8,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fReapply", "If true, TTree::Draw will 'reapply' the original cut"),
+			Type:   rmeta.Bool,
+			Size:   1,
 			ArrLen: 0,
 			ArrDim: 0,
 			MaxIdx: [5]int32{0, 0, 0, 0, 0},
 			Offset: 0,
-			EName:  "TRefTable*",
+			EName:  "bool",
 			XMin:   0.000000,
 			XMax:   0.000000,
 			Factor: 0.000000,
 		}.New()},
 	}))
-	StreamerInfos.Add(NewCxxStreamerInfo("TChain", 5, 0x3a9ec568, []rbytes.StreamerElement{
+	StreamerInfos.Add(NewCxxStreamerInfo("TEntryListBlock", 1, 0xc72399a9, []rbytes.StreamerElement{
 		NewStreamerBase(Element{
-			Name:   *rbase.NewNamed("TTree", "Tree descriptor (the main ROOT I/O class)"),
+			Name:   *rbase.NewNamed("TObject", "Basic ROOT object"),
 			Type:   rmeta.Base,
 			Size:   0,
 			ArrLen: 0,
 			ArrDim: 0,
-			MaxIdx: [5]int32{0, 1919213695, 0, 0, 0},
+			MaxIdx: [5]int32{0, -1877229523, 0, 0, 0},
 			Offset: 0,
 			EName:  "BASE",
 			XMin:   0.000000,
 			XMax:   0.000000,
 			Factor: 0.000000,
-		}.New(), 20),
+		}.New(), 1),
 		&StreamerBasicType{StreamerElement: Element{
-			Name:   *rbase.NewNamed("fTreeOffsetLen", "Current size of fTreeOffset array"),
-			Type:   rmeta.Counter,
+			Name:   *rbase.NewNamed("fNPassed", "number of entries in the entry list (if fPassing=0 - number of entries"),
+			Type:   rmeta.Int,
 			Size:   4,
 			ArrLen: 0,
 			ArrDim: 0,
@@ -6283,8 +7006,8 @@ func init() {
 			Factor: 0.000000,
 		}.New()},
 		&StreamerBasicType{StreamerElement: Element{
-			Name:   *rbase.NewNamed("fNtrees", "Number of trees"),
-			Type:   rmeta.Int,
+			Name:   *rbase.NewNamed("fN", "size of fIndices for I/O  =fNPassed for list, fBlockSize for bits"),
+			Type:   rmeta.Counter,
 			Size:   4,
 			ArrLen: 0,
 			ArrDim: 0,
@@ -6296,40 +7019,40 @@ func init() {
 			Factor: 0.000000,
 		}.New()},
 		NewStreamerBasicPointer(Element{
-			Name:   *rbase.NewNamed("fTreeOffset", "[fTreeOffsetLen] Array of variables"),
-			Type:   56,
-			Size:   8,
+			Name:   *rbase.NewNamed("fIndices", "[fN] can be either a bit field or a list of indices"),
+			Type:   52,
+			Size:   2,
 			ArrLen: 0,
 			ArrDim: 0,
 			MaxIdx: [5]int32{0, 0, 0, 0, 0},
 			Offset: 0,
-			EName:  "Long64_t*",
+			EName:  "unsigned short*",
 			XMin:   0.000000,
 			XMax:   0.000000,
 			Factor: 0.000000,
-		}.New(), 5, "fTreeOffsetLen", "TChain"),
-		&StreamerObjectPointer{StreamerElement: Element{
-			Name:   *rbase.NewNamed("fFiles", "-> List of file names containing the trees (TChainElement, owned)"),
-			Type:   rmeta.Objectp,
-			Size:   8,
+		}.New(), 1, "fN", "TEntryListBlock"),
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fType", "0 - bits, 1 - list"),
+			Type:   rmeta.Int,
+			Size:   4,
 			ArrLen: 0,
 			ArrDim: 0,
 			MaxIdx: [5]int32{0, 0, 0, 0, 0},
 			Offset: 0,
-			EName:  "TObjArray*",
+			EName:  "int",
 			XMin:   0.000000,
 			XMax:   0.000000,
 			Factor: 0.000000,
 		}.New()},
-		&StreamerObjectPointer{StreamerElement: Element{
-			Name:   *rbase.NewNamed("fStatus", "-> List of active/inactive branches (TChainElement, owned)"),
-			Type:   rmeta.Objectp,
-			Size:   8,
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fPassing", "1 - stores entries that belong to the list"),
+			Type:   rmeta.Bool,
+			Size:   1,
 			ArrLen: 0,
 			ArrDim: 0,
 			MaxIdx: [5]int32{0, 0, 0, 0, 0},
 			Offset: 0,
-			EName:  "TList*",
+			EName:  "bool",
 			XMin:   0.000000,
 			XMax:   0.000000,
 			Factor: 0.000000,
@@ -7394,5 +8117,113 @@ func init() {
 			Factor: 0.000000,
 		}.New()},
 	}))
+	StreamerInfos.Add(NewCxxStreamerInfo("TTreeIndex", 2, 0xb0dd6362, []rbytes.StreamerElement{
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TVirtualIndex", "Abstract interface for Tree Index"),
+			Type:   rmeta.Base,
+			Size:   0,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 1008215460, 0, 0, 0},
+			Offset: 0,
+			EName:  "BASE",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1),
+		&StreamerString{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fMajorName", "Index major name"),
+			Type:   rmeta.TString,
+			Size:   24,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "TString",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerString{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fMinorName", "Index minor name"),
+			Type:   rmeta.TString,
+			Size:   24,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "TString",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fN", "Number of entries"),
+			Type:   rmeta.Long64,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "Long64_t",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		NewStreamerBasicPointer(Element{
+			Name:   *rbase.NewNamed("fIndexValues", "[fN] Sorted index values, higher 64bits"),
+			Type:   56,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "Long64_t*",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 2, "fN", "TTreeIndex"),
+		NewStreamerBasicPointer(Element{
+			Name:   *rbase.NewNamed("fIndexValuesMinor", "[fN] Sorted index values, lower 64bits"),
+			Type:   56,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "Long64_t*",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 2, "fN", "TTreeIndex"),
+		NewStreamerBasicPointer(Element{
+			Name:   *rbase.NewNamed("fIndex", "[fN] Index of sorted values"),
+			Type:   56,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "Long64_t*",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 2, "fN", "TTreeIndex"),
+	}))
+	StreamerInfos.Add(NewCxxStreamerInfo("TVirtualIndex", 1, 0x3c1825a4, []rbytes.StreamerElement{
+		NewStreamerBase(Element{
+			Name:   *rbase.NewNamed("TNamed", "The basis for a named object (name, title)"),
+			Type:   rmeta.Base,
+			Size:   0,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, -541636036, 0, 0, 0},
+			Offset: 0,
+			EName:  "BASE",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1),
+	}))
 
 }
@@ -126,6 +126,10 @@ func (v *visitor) visitSE(depth int, se rbytes.StreamerElement) error {
 				// no-op: C++ builtin.
 				return nil
 			}
+			if rmeta.IsCxxBuiltinPair(tname) {
+				// no-op: ROOT does not store streamers for pairs of C++ builtins.
+				return nil
+			}
 			si, err := v.ctx.StreamerInfo(tname, -1)
 			if err != nil {
 				return fmt.Errorf("could not find std::container<T> element %q: %w", tname, err)
@@ -193,3 +193,19 @@ var (
 	_ rbytes.Marshaler   = (*taxis)(nil)
 	_ rbytes.Unmarshaler = (*taxis)(nil)
 )
+
+// axisEdges returns the nbins+1 edges of the provided axis.
+func axisEdges(axis Axis) []float64 {
+	if xbins := axis.XBins(); len(xbins) > 0 {
+		edges := make([]float64, len(xbins))
+		copy(edges, xbins)
+		return edges
+	}
+	n := axis.NBins()
+	edges := make([]float64, n+1)
+	for i := range edges {
+		edges[i] = axis.BinLowEdge(i + 1)
+	}
+	edges[n] = axis.XMax()
+	return edges
+}
@@ -6,6 +6,7 @@ package rhist
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 
 	"go-hep.org/x/hep/groot/rbase"
@@ -14,6 +15,7 @@ import (
 	"go-hep.org/x/hep/groot/root"
 	"go-hep.org/x/hep/groot/rtypes"
 	"go-hep.org/x/hep/groot/rvers"
+	"go-hep.org/x/hep/hbook"
 )
 
 // Efficiency handles efficiency histograms.
@@ -37,6 +39,79 @@ type Efficiency struct {
 	weight     float64 // weight for all events (default = 1)
 }
 
+// NewEfficiencyFrom creates a new efficiency from the hbook 1-dim histograms
+// of passed and total events.
+// As with ROOT's TEfficiency, the passed and total histograms are renamed
+// after the efficiency, with a "_passed" and "_total" suffix.
+//
+// NewEfficiencyFrom returns an error if the binnings of the histograms are
+// not compatible, or if a bin of the passed histogram has more weighted
+// entries than the total one.
+func NewEfficiencyFrom(name, title string, passed, total *hbook.H1D) (*Efficiency, error) {
+	var (
+		bpass = passed.Binning.Bins
+		btot  = total.Binning.Bins
+	)
+	if len(bpass) != len(btot) {
+		return nil, fmt.Errorf("rhist: binnings of passed and total histograms are not compatible")
+	}
+	for i := range bpass {
+		var (
+			bp = &bpass[i]
+			bt = &btot[i]
+		)
+		if bp.XMin() != bt.XMin() || bp.XMax() != bt.XMax() {
+			return nil, fmt.Errorf("rhist: binnings of passed and total histograms are not compatible")
+		}
+		if bp.SumW() > bt.SumW() {
+			return nil, fmt.Errorf(
+				"rhist: passed histogram has more entries than total histogram in bin %d (%v > %v)",
+				i, bp.SumW(), bt.SumW(),
+			)
+		}
+	}
+
+	o := newEfficiency()
+	o.named.SetName(name)
+	o.named.SetTitle(title)
+
+	hpass := NewH1DFrom(passed)
+	hpass.SetName(name + "_passed")
+	hpass.SetTitle(title)
+
+	htot := NewH1DFrom(total)
+	htot.SetName(name + "_total")
+	htot.SetTitle(title)
+
+	o.passedHist = hpass
+	o.totHist = htot
+
+	return o, nil
+}
+
+func newEfficiency() *Efficiency {
+	return &Efficiency{
+		named:     *rbase.NewNamed("", ""),
+		attline:   *rbase.NewAttLine(),
+		attfill:   *rbase.NewAttFill(),
+		attmark:   *rbase.NewAttMarker(),
+		betaAlpha: 1,
+		betaBeta:  1,
+		confLvl:   math.Erf(1 / math.Sqrt2), // 1 sigma
+		funcs:     *rcont.NewList("", nil),
+		weight:    1,
+	}
+}
+
+func (o *Efficiency) Name() string  { return o.named.Name() }
+func (o *Efficiency) Title() string { return o.named.Title() }
+
+// Passed returns the histogram of passed events.
+func (o *Efficiency) Passed() H1 { return o.passedHist }
+
+// Total returns the histogram of total events.
+func (o *Efficiency) Total() H1 { return o.totHist }
+
 func (*Efficiency) Class() string {
 	return "TEfficiency"
 }
@@ -186,6 +261,46 @@ func readVecPairF64(r *rbytes.RBuffer, vs *[][2]float64) error {
 	return r.Err()
 }
 
+// ROOTMerge merges the content of src into o.
+// The passed and total histograms of the efficiencies are merged: ROOTMerge
+// returns an error if their axes are not compatible, or if the efficiencies
+// have different weights.
+func (o *Efficiency) ROOTMerge(src root.Object) error {
+	osrc, ok := src.(*Efficiency)
+	if !ok {
+		return fmt.Errorf("rhist: can not merge %T into %T", src, o)
+	}
+
+	if o.weight != osrc.weight {
+		return fmt.Errorf(
+			"rhist: can not merge efficiency %q with different weights (%v != %v)",
+			o.named.Name(), o.weight, osrc.weight,
+		)
+	}
+
+	for _, v := range []struct {
+		name     string
+		dst, src H1
+	}{
+		{"passed", o.passedHist, osrc.passedHist},
+		{"total", o.totHist, osrc.totHist},
+	} {
+		if v.dst == nil || v.src == nil {
+			return fmt.Errorf("rhist: efficiency %q has no %s histogram", o.named.Name(), v.name)
+		}
+		m, ok := v.dst.(root.Merger)
+		if !ok {
+			return fmt.Errorf("rhist: can not merge %s histogram %T of efficiency %q", v.name, v.dst, o.named.Name())
+		}
+		err := m.ROOTMerge(v.src)
+		if err != nil {
+			return fmt.Errorf("rhist: could not merge %s histogram of efficiency %q: %w", v.name, o.named.Name(), err)
+		}
+	}
+
+	return nil
+}
+
 func init() {
 	f := func() reflect.Value {
 		var o Efficiency
@@ -196,6 +311,8 @@ func init() {
 
 var (
 	_ root.Object        = (*Efficiency)(nil)
+	_ root.Named         = (*Efficiency)(nil)
+	_ root.Merger        = (*Efficiency)(nil)
 	_ rbytes.RVersioner  = (*Efficiency)(nil)
 	_ rbytes.Marshaler   = (*Efficiency)(nil)
 	_ rbytes.Unmarshaler = (*Efficiency)(nil)
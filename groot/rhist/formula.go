@@ -101,6 +101,47 @@ func (f *Formula) UnmarshalROOT(r *rbytes.RBuffer) error {
 	return r.Err()
 }
 
+// Expr returns the expression of the formula.
+func (f *Formula) Expr() string {
+	return f.formula
+}
+
+// Params returns the values of the formula parameters.
+func (f *Formula) Params() []float64 {
+	return f.clingParams
+}
+
+// ParNames returns the names of the formula parameters, ordered by index.
+func (f *Formula) ParNames() []string {
+	names := make([]string, len(f.clingParams))
+	for k, v := range f.params {
+		if int(v) < len(names) {
+			names[v] = k
+		}
+	}
+	return names
+}
+
+// Func returns a function evaluating the formula expression for the
+// provided variables (x, y, z, t) and parameters.
+//
+// The TFormula language is interpreted: predefined functions (gaus, gausn,
+// expo, landau, landaun and polN), parameters ([0], [p0] or [name]),
+// variables (x, y, z, t and x[i]) and the usual TMath functions are
+// supported.
+func (f *Formula) Func() (func(x, p []float64) float64, error) {
+	pars := tfParams{names: f.params}
+	node, err := tfParse(f.formula, &pars)
+	if err != nil {
+		return nil, fmt.Errorf("rhist: could not parse TFormula %q: %w", f.formula, err)
+	}
+	fct, err := tfCompile(node)
+	if err != nil {
+		return nil, fmt.Errorf("rhist: could not compile TFormula %q: %w", f.formula, err)
+	}
+	return fct, nil
+}
+
 func (f *Formula) String() string {
 	return fmt.Sprintf("TFormula{%s}", f.formula)
 }
@@ -0,0 +1,829 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rhist
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// tfNode is a node of a parsed TFormula expression.
+type tfNode interface {
+	isTFNode()
+}
+
+type (
+	tfNum struct {
+		v float64
+	}
+
+	// tfVar is a variable (x, y, z, t or x[i]).
+	tfVar struct {
+		idx int
+	}
+
+	// tfPar is a parameter ([0], [p0] or [name]).
+	tfPar struct {
+		idx int
+	}
+
+	tfUnary struct {
+		op string
+		x  tfNode
+	}
+
+	tfBinary struct {
+		op   string
+		x, y tfNode
+	}
+
+	tfCall struct {
+		name string
+		args []tfNode
+	}
+)
+
+func (*tfNum) isTFNode()    {}
+func (*tfVar) isTFNode()    {}
+func (*tfPar) isTFNode()    {}
+func (*tfUnary) isTFNode()  {}
+func (*tfBinary) isTFNode() {}
+func (*tfCall) isTFNode()   {}
+
+type tfTokKind int
+
+const (
+	tfTokEOF tfTokKind = iota
+	tfTokNum
+	tfTokIdent
+	tfTokParam
+	tfTokOp
+)
+
+type tfTok struct {
+	kind tfTokKind
+	pos  int
+	str  string
+}
+
+// tfOps lists the operators and punctuation recognized by the lexer.
+// Longer operators must be listed before their prefixes.
+var tfOps = []string{
+	"**", "&&", "||", "==", "!=", "<=", ">=",
+	"+", "-", "*", "/", "%", "^", "!", "<", ">",
+	"(", ")", "[", "]", ",",
+}
+
+func tfLex(expr string) ([]tfTok, error) {
+	var (
+		toks []tfTok
+		i    = 0
+	)
+	isIdent := func(r byte, first bool) bool {
+		c := rune(r)
+		if first {
+			return c == '_' || unicode.IsLetter(c)
+		}
+		return c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c)
+	}
+
+loop:
+	for i < len(expr) {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c >= '0' && c <= '9' || (c == '.' && i+1 < len(expr) && expr[i+1] >= '0' && expr[i+1] <= '9'):
+			beg := i
+			for i < len(expr) {
+				c := expr[i]
+				switch {
+				case c >= '0' && c <= '9', c == '.':
+					i++
+				case (c == 'e' || c == 'E') && i+1 < len(expr):
+					i++
+					if expr[i] == '+' || expr[i] == '-' {
+						i++
+					}
+				default:
+					toks = append(toks, tfTok{kind: tfTokNum, pos: beg, str: expr[beg:i]})
+					continue loop
+				}
+			}
+			toks = append(toks, tfTok{kind: tfTokNum, pos: beg, str: expr[beg:i]})
+
+		case isIdent(c, true):
+			beg := i
+			for i < len(expr) {
+				switch {
+				case isIdent(expr[i], false):
+					i++
+				case strings.HasPrefix(expr[i:], "::"):
+					i += 2
+				default:
+					toks = append(toks, tfTok{kind: tfTokIdent, pos: beg, str: expr[beg:i]})
+					continue loop
+				}
+			}
+			toks = append(toks, tfTok{kind: tfTokIdent, pos: beg, str: expr[beg:i]})
+
+		case c == '[' && (len(toks) == 0 || toks[len(toks)-1].kind != tfTokIdent):
+			// a parameter: [0], [p0] or [name].
+			end := strings.Index(expr[i:], "]")
+			if end < 0 {
+				return nil, fmt.Errorf("missing ']' for parameter at position %d", i)
+			}
+			name := strings.TrimSpace(expr[i+1 : i+end])
+			if name == "" {
+				return nil, fmt.Errorf("invalid empty parameter at position %d", i)
+			}
+			toks = append(toks, tfTok{kind: tfTokParam, pos: i, str: name})
+			i += end + 1
+
+		default:
+			for _, op := range tfOps {
+				if strings.HasPrefix(expr[i:], op) {
+					toks = append(toks, tfTok{kind: tfTokOp, pos: i, str: op})
+					i += len(op)
+					continue loop
+				}
+			}
+			return nil, fmt.Errorf("invalid character %q at position %d", c, i)
+		}
+	}
+	toks = append(toks, tfTok{kind: tfTokEOF, pos: len(expr)})
+	return toks, nil
+}
+
+// tfPrec holds the precedence of binary operators.
+// Unary operators bind tighter than all binary operators but the power.
+var tfPrec = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+// tfVars maps the names of the TFormula variables to their index.
+var tfVars = map[string]int{
+	"x": 0, "y": 1, "z": 2, "t": 3,
+}
+
+// tfConsts holds the TFormula predefined constants.
+var tfConsts = map[string]float64{
+	"pi":       math.Pi,
+	"e":        math.E,
+	"sqrt2":    math.Sqrt2,
+	"ln10":     math.Ln10,
+	"loge":     math.Log10E,
+	"infinity": math.Inf(+1),
+	"true":     1,
+	"false":    0,
+}
+
+// tfParams resolves the parameters of a TFormula expression.
+type tfParams struct {
+	names map[string]int32 // parameter names
+	npars int              // number of parameters seen so far
+}
+
+func (ps *tfParams) index(name string) int {
+	if i, ok := ps.names[name]; ok {
+		return ps.use(int(i))
+	}
+
+	v := strings.TrimPrefix(name, "p")
+	if i, err := strconv.Atoi(v); err == nil && i >= 0 {
+		return ps.use(i)
+	}
+
+	// a user-named parameter, not declared.
+	if ps.names == nil {
+		ps.names = make(map[string]int32)
+	}
+	i := ps.npars
+	ps.names[name] = int32(i)
+	return ps.use(i)
+}
+
+func (ps *tfParams) use(i int) int {
+	if i >= ps.npars {
+		ps.npars = i + 1
+	}
+	return i
+}
+
+type tfParser struct {
+	toks []tfTok
+	pos  int
+	pars *tfParams
+}
+
+// tfParse parses the provided TFormula expression.
+// Named parameters are resolved with the pars table.
+func tfParse(expr string, pars *tfParams) (tfNode, error) {
+	toks, err := tfLex(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := tfParser{toks: toks, pars: pars}
+	node, err := p.parseBinary(1)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tfTokEOF {
+		return nil, fmt.Errorf("unexpected token %q at position %d", tok.str, tok.pos)
+	}
+	return node, nil
+}
+
+func (p *tfParser) peek() tfTok { return p.toks[p.pos] }
+func (p *tfParser) next() tfTok {
+	tok := p.toks[p.pos]
+	if tok.kind != tfTokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *tfParser) expect(op string) error {
+	tok := p.next()
+	if tok.kind != tfTokOp || tok.str != op {
+		if tok.kind == tfTokEOF {
+			return fmt.Errorf("unexpected end of expression (want %q)", op)
+		}
+		return fmt.Errorf("unexpected token %q at position %d (want %q)", tok.str, tok.pos, op)
+	}
+	return nil
+}
+
+func (p *tfParser) parseBinary(prec int) (tfNode, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tfTokOp {
+			return x, nil
+		}
+		oprec, ok := tfPrec[tok.str]
+		if !ok || oprec < prec {
+			return x, nil
+		}
+		p.next()
+		y, err := p.parseBinary(oprec + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = &tfBinary{op: tok.str, x: x, y: y}
+	}
+}
+
+func (p *tfParser) parseUnary() (tfNode, error) {
+	tok := p.peek()
+	if tok.kind == tfTokOp {
+		switch tok.str {
+		case "-", "+", "!":
+			p.next()
+			x, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			return &tfUnary{op: tok.str, x: x}, nil
+		}
+	}
+	return p.parsePower()
+}
+
+func (p *tfParser) parsePower() (tfNode, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	tok := p.peek()
+	if tok.kind == tfTokOp && (tok.str == "^" || tok.str == "**") {
+		p.next()
+		// power is right-associative.
+		y, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &tfBinary{op: "^", x: x, y: y}, nil
+	}
+	return x, nil
+}
+
+func (p *tfParser) parsePrimary() (tfNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tfTokNum:
+		v, err := strconv.ParseFloat(tok.str, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", tok.str, tok.pos)
+		}
+		return &tfNum{v: v}, nil
+
+	case tfTokParam:
+		return &tfPar{idx: p.pars.index(tok.str)}, nil
+
+	case tfTokIdent:
+		if _, ok := tfBuiltin(tok.str); ok {
+			return p.parseBuiltin(tok)
+		}
+		next := p.peek()
+		if next.kind == tfTokOp {
+			switch next.str {
+			case "(":
+				p.next()
+				return p.parseCall(tok)
+			case "[":
+				p.next()
+				return p.parseIndex(tok)
+			}
+		}
+		if i, ok := tfVars[tok.str]; ok {
+			return &tfVar{idx: i}, nil
+		}
+		if v, ok := tfConsts[strings.ToLower(tok.str)]; ok {
+			return &tfNum{v: v}, nil
+		}
+		return nil, fmt.Errorf("unknown identifier %q at position %d", tok.str, tok.pos)
+
+	case tfTokOp:
+		if tok.str == "(" {
+			x, err := p.parseBinary(1)
+			if err != nil {
+				return nil, err
+			}
+			err = p.expect(")")
+			if err != nil {
+				return nil, err
+			}
+			return x, nil
+		}
+		return nil, fmt.Errorf("unexpected token %q at position %d", tok.str, tok.pos)
+
+	default:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+}
+
+// parseIndex parses a x[i] variable.
+func (p *tfParser) parseIndex(name tfTok) (tfNode, error) {
+	if name.str != "x" {
+		return nil, fmt.Errorf("invalid indexed variable %q at position %d", name.str, name.pos)
+	}
+	tok := p.next()
+	if tok.kind != tfTokNum {
+		return nil, fmt.Errorf("invalid index %q at position %d", tok.str, tok.pos)
+	}
+	i, err := strconv.Atoi(tok.str)
+	if err != nil || i < 0 {
+		return nil, fmt.Errorf("invalid index %q at position %d", tok.str, tok.pos)
+	}
+	err = p.expect("]")
+	if err != nil {
+		return nil, err
+	}
+	return &tfVar{idx: i}, nil
+}
+
+func (p *tfParser) parseCall(fct tfTok) (tfNode, error) {
+	name := tfFuncName(fct.str)
+	if _, ok := tfFuncs[name]; !ok {
+		return nil, fmt.Errorf("unknown function %q at position %d", fct.str, fct.pos)
+	}
+
+	call := &tfCall{name: name}
+	if tok := p.peek(); tok.kind == tfTokOp && tok.str == ")" {
+		p.next()
+		return call, p.checkCall(call, fct)
+	}
+	for {
+		arg, err := p.parseBinary(1)
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+
+		tok := p.next()
+		if tok.kind == tfTokOp && tok.str == ")" {
+			return call, p.checkCall(call, fct)
+		}
+		if tok.kind != tfTokOp || tok.str != "," {
+			if tok.kind == tfTokEOF {
+				return nil, fmt.Errorf("unexpected end of expression (want %q)", ")")
+			}
+			return nil, fmt.Errorf("unexpected token %q at position %d", tok.str, tok.pos)
+		}
+	}
+}
+
+func (p *tfParser) checkCall(call *tfCall, tok tfTok) error {
+	fct := tfFuncs[call.name]
+	if n := len(call.args); n < fct.min || n > fct.max {
+		return fmt.Errorf(
+			"invalid number of arguments to %q at position %d (got=%d)",
+			tok.str, tok.pos, n,
+		)
+	}
+	return nil
+}
+
+// parseBuiltin parses one of the TFormula predefined functions (gaus,
+// expo, landau, polN, ...), with an optional variable and parameter offset,
+// as in gaus, gaus(2), gaus(y) or gaus(y,2).
+//
+// When no offset is given, the parameters of the predefined function start
+// right after the last parameter seen so far in the expression.
+func (p *tfParser) parseBuiltin(name tfTok) (tfNode, error) {
+	var (
+		bfct, _ = tfBuiltin(name.str)
+		vname   = "x"
+		offset  = p.pars.npars
+	)
+
+	if tok := p.peek(); tok.kind == tfTokOp && tok.str == "(" {
+		p.next()
+		for i := 0; ; i++ {
+			tok := p.next()
+			switch {
+			case i < 2 && tok.kind == tfTokIdent:
+				if _, ok := tfVars[tok.str]; !ok {
+					return nil, fmt.Errorf("invalid variable %q at position %d", tok.str, tok.pos)
+				}
+				vname = tok.str
+			case i < 2 && tok.kind == tfTokNum:
+				v, err := strconv.Atoi(tok.str)
+				if err != nil || v < 0 {
+					return nil, fmt.Errorf("invalid parameter offset %q at position %d", tok.str, tok.pos)
+				}
+				offset = v
+			default:
+				return nil, fmt.Errorf("unexpected token %q at position %d", tok.str, tok.pos)
+			}
+			tok = p.next()
+			if tok.kind == tfTokOp && tok.str == ")" {
+				break
+			}
+			if tok.kind != tfTokOp || tok.str != "," {
+				return nil, fmt.Errorf("unexpected token %q at position %d", tok.str, tok.pos)
+			}
+		}
+	}
+
+	expr := bfct(vname, offset)
+	node, err := tfParse(expr, p.pars)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %q (%q): %w", name.str, expr, err)
+	}
+	return node, nil
+}
+
+// tfBuiltin returns the expansion of the named TFormula predefined function,
+// as a function of its variable and of its parameter offset.
+func tfBuiltin(name string) (func(v string, i int) string, bool) {
+	switch name {
+	case "gaus":
+		return func(v string, i int) string {
+			return fmt.Sprintf(
+				"[%[2]d]*exp(-0.5*((%[1]s-[%[3]d])/[%[4]d])*((%[1]s-[%[3]d])/[%[4]d]))",
+				v, i, i+1, i+2,
+			)
+		}, true
+	case "gausn":
+		return func(v string, i int) string {
+			return fmt.Sprintf(
+				"[%[2]d]*exp(-0.5*((%[1]s-[%[3]d])/[%[4]d])*((%[1]s-[%[3]d])/[%[4]d]))/(sqrt(2*pi)*[%[4]d])",
+				v, i, i+1, i+2,
+			)
+		}, true
+	case "expo":
+		return func(v string, i int) string {
+			return fmt.Sprintf("exp([%d]+[%d]*%s)", i, i+1, v)
+		}, true
+	case "landau":
+		return func(v string, i int) string {
+			return fmt.Sprintf("[%d]*TMath::Landau(%s,[%d],[%d],false)", i, v, i+1, i+2)
+		}, true
+	case "landaun":
+		return func(v string, i int) string {
+			return fmt.Sprintf("[%d]*TMath::Landau(%s,[%d],[%d],true)", i, v, i+1, i+2)
+		}, true
+	}
+
+	if !strings.HasPrefix(name, "pol") {
+		return nil, false
+	}
+	n, err := strconv.Atoi(name[len("pol"):])
+	if err != nil || n < 0 {
+		return nil, false
+	}
+	return func(v string, i int) string {
+		o := new(strings.Builder)
+		fmt.Fprintf(o, "([%d]", i)
+		for k := 1; k <= n; k++ {
+			fmt.Fprintf(o, "+[%d]*%s", i+k, v)
+			if k > 1 {
+				fmt.Fprintf(o, "^%d", k)
+			}
+		}
+		o.WriteString(")")
+		return o.String()
+	}, true
+}
+
+// tfFuncName returns the normalized name of a formula function.
+func tfFuncName(name string) string {
+	name = strings.TrimPrefix(name, "TMath::")
+	name = strings.TrimPrefix(name, "std::")
+	return strings.ToLower(name)
+}
+
+type tfFunc struct {
+	min, max int // min and max number of arguments
+	fct      func(args []float64) float64
+}
+
+func tfFunc0(v float64) tfFunc {
+	return tfFunc{fct: func([]float64) float64 { return v }}
+}
+
+func tfFunc1(f func(float64) float64) tfFunc {
+	return tfFunc{min: 1, max: 1, fct: func(args []float64) float64 { return f(args[0]) }}
+}
+
+func tfFunc2(f func(x, y float64) float64) tfFunc {
+	return tfFunc{min: 2, max: 2, fct: func(args []float64) float64 { return f(args[0], args[1]) }}
+}
+
+// tfFuncN returns a function with n arguments, the trailing ones taking
+// their default values when omitted.
+func tfFuncN(f func(args []float64) float64, min int, defaults ...float64) tfFunc {
+	max := min + len(defaults)
+	return tfFunc{
+		min: min, max: max,
+		fct: func(args []float64) float64 {
+			if len(args) < max {
+				args = append(args[:len(args):len(args)], defaults[len(args)-min:]...)
+			}
+			return f(args)
+		},
+	}
+}
+
+var tfFuncs = map[string]tfFunc{
+	"abs":   tfFunc1(math.Abs),
+	"fabs":  tfFunc1(math.Abs),
+	"sqrt":  tfFunc1(math.Sqrt),
+	"exp":   tfFunc1(math.Exp),
+	"log":   tfFunc1(math.Log),
+	"log10": tfFunc1(math.Log10),
+	"sin":   tfFunc1(math.Sin),
+	"cos":   tfFunc1(math.Cos),
+	"tan":   tfFunc1(math.Tan),
+	"asin":  tfFunc1(math.Asin),
+	"acos":  tfFunc1(math.Acos),
+	"atan":  tfFunc1(math.Atan),
+	"sinh":  tfFunc1(math.Sinh),
+	"cosh":  tfFunc1(math.Cosh),
+	"tanh":  tfFunc1(math.Tanh),
+	"floor": tfFunc1(math.Floor),
+	"ceil":  tfFunc1(math.Ceil),
+	"erf":   tfFunc1(math.Erf),
+	"erfc":  tfFunc1(math.Erfc),
+	"atan2": tfFunc2(math.Atan2),
+	"pow":   tfFunc2(math.Pow),
+	"power": tfFunc2(math.Pow),
+	"fmod":  tfFunc2(math.Mod),
+	"hypot": tfFunc2(math.Hypot),
+	"min":   tfFunc2(math.Min),
+	"max":   tfFunc2(math.Max),
+	"sq":    tfFunc1(func(x float64) float64 { return x * x }),
+	"pi":    tfFunc0(math.Pi),
+	"e":     tfFunc0(math.E),
+	"gaus": tfFuncN(func(args []float64) float64 {
+		return tmathGaus(args[0], args[1], args[2], args[3] != 0)
+	}, 1, 0, 1, 0),
+	"landau": tfFuncN(func(args []float64) float64 {
+		return tmathLandau(args[0], args[1], args[2], args[3] != 0)
+	}, 1, 0, 1, 0),
+	"breitwigner": tfFuncN(func(args []float64) float64 {
+		return tmathBreitWigner(args[0], args[1], args[2])
+	}, 1, 0, 1),
+}
+
+func b2f(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// tfCompile compiles the TFormula expression into a function of the
+// variables x and parameters p.
+func tfCompile(node tfNode) (func(x, p []float64) float64, error) {
+	switch node := node.(type) {
+	case *tfNum:
+		v := node.v
+		return func(x, p []float64) float64 { return v }, nil
+
+	case *tfVar:
+		i := node.idx
+		return func(x, p []float64) float64 { return x[i] }, nil
+
+	case *tfPar:
+		i := node.idx
+		return func(x, p []float64) float64 { return p[i] }, nil
+
+	case *tfUnary:
+		f, err := tfCompile(node.x)
+		if err != nil {
+			return nil, err
+		}
+		switch node.op {
+		case "+":
+			return f, nil
+		case "-":
+			return func(x, p []float64) float64 { return -f(x, p) }, nil
+		case "!":
+			return func(x, p []float64) float64 { return b2f(f(x, p) == 0) }, nil
+		}
+		return nil, fmt.Errorf("unknown unary operator %q", node.op)
+
+	case *tfBinary:
+		f, err := tfCompile(node.x)
+		if err != nil {
+			return nil, err
+		}
+		g, err := tfCompile(node.y)
+		if err != nil {
+			return nil, err
+		}
+		switch node.op {
+		case "+":
+			return func(x, p []float64) float64 { return f(x, p) + g(x, p) }, nil
+		case "-":
+			return func(x, p []float64) float64 { return f(x, p) - g(x, p) }, nil
+		case "*":
+			return func(x, p []float64) float64 { return f(x, p) * g(x, p) }, nil
+		case "/":
+			return func(x, p []float64) float64 { return f(x, p) / g(x, p) }, nil
+		case "%":
+			return func(x, p []float64) float64 { return math.Mod(f(x, p), g(x, p)) }, nil
+		case "^":
+			return func(x, p []float64) float64 { return math.Pow(f(x, p), g(x, p)) }, nil
+		case "==":
+			return func(x, p []float64) float64 { return b2f(f(x, p) == g(x, p)) }, nil
+		case "!=":
+			return func(x, p []float64) float64 { return b2f(f(x, p) != g(x, p)) }, nil
+		case "<":
+			return func(x, p []float64) float64 { return b2f(f(x, p) < g(x, p)) }, nil
+		case "<=":
+			return func(x, p []float64) float64 { return b2f(f(x, p) <= g(x, p)) }, nil
+		case ">":
+			return func(x, p []float64) float64 { return b2f(f(x, p) > g(x, p)) }, nil
+		case ">=":
+			return func(x, p []float64) float64 { return b2f(f(x, p) >= g(x, p)) }, nil
+		case "&&":
+			return func(x, p []float64) float64 { return b2f(f(x, p) != 0 && g(x, p) != 0) }, nil
+		case "||":
+			return func(x, p []float64) float64 { return b2f(f(x, p) != 0 || g(x, p) != 0) }, nil
+		}
+		return nil, fmt.Errorf("unknown binary operator %q", node.op)
+
+	case *tfCall:
+		fct := tfFuncs[node.name]
+		args := make([]func(x, p []float64) float64, len(node.args))
+		for i, arg := range node.args {
+			f, err := tfCompile(arg)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = f
+		}
+		return func(x, p []float64) float64 {
+			vs := make([]float64, len(args), fct.max)
+			for i, arg := range args {
+				vs[i] = arg(x, p)
+			}
+			return fct.fct(vs)
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown expression node %T", node)
+}
+
+// tmathGaus is TMath::Gaus.
+func tmathGaus(x, mean, sigma float64, norm bool) float64 {
+	if sigma == 0 {
+		return 1e30
+	}
+	arg := (x - mean) / sigma
+	// for |arg| > 39, the result is zero in double precision.
+	if arg < -39 || arg > 39 {
+		return 0
+	}
+	res := math.Exp(-0.5 * arg * arg)
+	if !norm {
+		return res
+	}
+	return res / (math.Sqrt(2*math.Pi) * sigma)
+}
+
+// tmathLandau is TMath::Landau.
+func tmathLandau(x, mpv, sigma float64, norm bool) float64 {
+	if sigma <= 0 {
+		return 0
+	}
+	den := landauPDF((x-mpv)/sigma, 1, 0)
+	if !norm {
+		return den
+	}
+	return den / sigma
+}
+
+// tmathBreitWigner is TMath::BreitWigner.
+func tmathBreitWigner(x, mean, gamma float64) float64 {
+	bw := gamma / ((x-mean)*(x-mean) + gamma*gamma/4)
+	return bw / (2 * math.Pi)
+}
+
+// landauPDF is the Landau probability density function, as implemented
+// in ROOT::Math::landau_pdf (from CERNLIB G110 DENLAN).
+func landauPDF(x, xi, x0 float64) float64 {
+	var (
+		p1 = [5]float64{0.4259894875, -0.1249762550, 0.03984243700, -0.006298287635, 0.001511162253}
+		q1 = [5]float64{1.0, -0.3388260629, 0.09594393323, -0.01608042283, 0.003778942063}
+
+		p2 = [5]float64{0.1788541609, 0.1173957403, 0.01488850518, -0.001394989411, 0.0001283617211}
+		q2 = [5]float64{1.0, 0.7428795082, 0.3153932961, 0.06694219548, 0.008790609714}
+
+		p3 = [5]float64{0.1788544503, 0.09359161662, 0.006325387654, 0.00006611667319, -0.000002031049101}
+		q3 = [5]float64{1.0, 0.6097809921, 0.2560616665, 0.04746722384, 0.006957301675}
+
+		p4 = [5]float64{0.9874054407, 118.6723273, 849.2794360, -743.7792444, 427.0262186}
+		q4 = [5]float64{1.0, 106.8615961, 337.6496214, 2016.712389, 1597.063511}
+
+		p5 = [5]float64{1.003675074, 167.5702434, 4789.711289, 21217.86767, -22324.94910}
+		q5 = [5]float64{1.0, 156.9424537, 3745.310488, 9834.698876, 66924.28357}
+
+		p6 = [5]float64{1.000827619, 664.9143136, 62972.92665, 475554.6998, -5743609.109}
+		q6 = [5]float64{1.0, 651.4101098, 56974.73333, 165917.4725, -2815759.939}
+
+		a1 = [3]float64{0.04166666667, -0.01996527778, 0.02709538966}
+		a2 = [2]float64{-1.845568670, -4.284640743}
+	)
+
+	if xi <= 0 {
+		return 0
+	}
+
+	poly := func(c [5]float64, v float64) float64 {
+		return c[0] + (c[1]+(c[2]+(c[3]+c[4]*v)*v)*v)*v
+	}
+
+	var (
+		v   = (x - x0) / xi
+		den float64
+	)
+	switch {
+	case v < -5.5:
+		u := math.Exp(v + 1.0)
+		if u < 1e-10 {
+			return 0
+		}
+		ue := math.Exp(-1 / u)
+		us := math.Sqrt(u)
+		den = 0.3989422803 * (ue / us) * (1 + (a1[0]+(a1[1]+a1[2]*u)*u)*u)
+	case v < -1:
+		u := math.Exp(-v - 1)
+		den = math.Exp(-u) * math.Sqrt(u) * poly(p1, v) / poly(q1, v)
+	case v < 1:
+		den = poly(p2, v) / poly(q2, v)
+	case v < 5:
+		den = poly(p3, v) / poly(q3, v)
+	case v < 12:
+		u := 1 / v
+		den = u * u * poly(p4, u) / poly(q4, u)
+	case v < 50:
+		u := 1 / v
+		den = u * u * poly(p5, u) / poly(q5, u)
+	case v < 300:
+		u := 1 / v
+		den = u * u * poly(p6, u) / poly(q6, u)
+	default:
+		u := 1 / (v - v*math.Log(v)/(v+1))
+		den = u * u * (1 + (a2[0]+a2[1]*u)*u)
+	}
+	return den / xi
+}
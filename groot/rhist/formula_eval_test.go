@@ -0,0 +1,223 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rhist
+
+import (
+	"math"
+	"testing"
+
+	"go-hep.org/x/hep/groot/riofs"
+)
+
+func TestFormulaEval(t *testing.T) {
+	gaus := func(x, c, m, s float64) float64 {
+		return c * math.Exp(-0.5*(x-m)*(x-m)/(s*s))
+	}
+
+	for _, tc := range []struct {
+		expr   string
+		params map[string]int32
+		x      []float64
+		p      []float64
+		want   float64
+	}{
+		{expr: "42", want: 42},
+		{expr: "1+2*3", want: 7},
+		{expr: "2^3^2", want: 512},
+		{expr: "-2**2", want: -4},
+		{expr: "(1+2)*3", want: 9},
+		{expr: "7%4", want: 3},
+		{expr: "1.5e2+.5", want: 150.5},
+		{expr: "x<2 && y>=1", x: []float64{1, 1}, want: 1},
+		{expr: "x==2 || !(y!=1)", x: []float64{1, 2}, want: 0},
+		{expr: "pi", want: math.Pi},
+		{expr: "TMath::Pi()", want: math.Pi},
+		{expr: "sqrt(x[0]*x[0]+x[1]*x[1])", x: []float64{3, 4}, want: 5},
+		{expr: "TMath::Power(x,2)+TMath::Sq(y)+z*t", x: []float64{2, 3, 4, 5}, want: 33},
+		{expr: "max(x,y)+std::min(x,y)", x: []float64{2, 3}, want: 5},
+		{expr: "[0]+[1]*x", x: []float64{2}, p: []float64{10, 20}, want: 50},
+		{expr: "[p0]+[p1]*x", x: []float64{2}, p: []float64{10, 20}, want: 50},
+		{
+			expr:   "[a]+[b]*x",
+			params: map[string]int32{"a": 1, "b": 0},
+			x:      []float64{2}, p: []float64{10, 20},
+			want: 40,
+		},
+		{expr: "[a]+[b]*x", x: []float64{2}, p: []float64{10, 20}, want: 50},
+		{expr: "pol0", x: []float64{2}, p: []float64{3}, want: 3},
+		{expr: "pol2", x: []float64{2}, p: []float64{1, 2, 3}, want: 1 + 2*2 + 3*4},
+		{expr: "pol1(y)", x: []float64{2, 3}, p: []float64{1, 2}, want: 7},
+		{expr: "expo", x: []float64{2}, p: []float64{1, -0.5}, want: 1},
+		{expr: "gaus", x: []float64{2}, p: []float64{3, 1, 2}, want: gaus(2, 3, 1, 2)},
+		{
+			expr: "gausn",
+			x:    []float64{2}, p: []float64{3, 1, 2},
+			want: gaus(2, 3, 1, 2) / (math.Sqrt(2*math.Pi) * 2),
+		},
+		{
+			expr: "gaus+expo",
+			x:    []float64{2}, p: []float64{3, 1, 2, 1, -0.5},
+			want: gaus(2, 3, 1, 2) + 1,
+		},
+		{
+			expr: "[0]+gaus(1)",
+			x:    []float64{2}, p: []float64{10, 3, 1, 2},
+			want: 10 + gaus(2, 3, 1, 2),
+		},
+		{
+			expr: "gaus(y,0)*gaus(x,3)",
+			x:    []float64{2, 4}, p: []float64{3, 1, 2, 2, 1, 1},
+			want: gaus(4, 3, 1, 2) * gaus(2, 2, 1, 1),
+		},
+		{expr: "TMath::Gaus(x,1,2)", x: []float64{2}, want: gaus(2, 1, 1, 2)},
+		{
+			expr: "TMath::Gaus(x,1,2,true)",
+			x:    []float64{2},
+			want: gaus(2, 1, 1, 2) / (math.Sqrt(2*math.Pi) * 2),
+		},
+		{expr: "landau", x: []float64{1}, p: []float64{2, 1, 1}, want: 2 * 0.1788541609},
+		{expr: "landaun", x: []float64{1}, p: []float64{2, 1, 2}, want: 2 * 0.1788541609 / 2},
+		{expr: "TMath::Landau(x)", x: []float64{0}, want: 0.1788541609},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			f := &Formula{formula: tc.expr, params: tc.params}
+			fct, err := f.Func()
+			if err != nil {
+				t.Fatalf("could not compile formula: %+v", err)
+			}
+			got := fct(tc.x, tc.p)
+			if math.Abs(got-tc.want) > 1e-12 {
+				t.Fatalf("invalid value: got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFormulaEvalErrors(t *testing.T) {
+	for _, tc := range []struct {
+		expr string
+		err  string
+	}{
+		{
+			expr: "",
+			err:  `rhist: could not parse TFormula "": unexpected end of expression`,
+		},
+		{
+			expr: "1+",
+			err:  `rhist: could not parse TFormula "1+": unexpected end of expression`,
+		},
+		{
+			expr: "(1+2",
+			err:  `rhist: could not parse TFormula "(1+2": unexpected end of expression (want ")")`,
+		},
+		{
+			expr: "1 2",
+			err:  `rhist: could not parse TFormula "1 2": unexpected token "2" at position 2`,
+		},
+		{
+			expr: "x+[0",
+			err:  `rhist: could not parse TFormula "x+[0": missing ']' for parameter at position 2`,
+		},
+		{
+			expr: "foo",
+			err:  `rhist: could not parse TFormula "foo": unknown identifier "foo" at position 0`,
+		},
+		{
+			expr: "foo(x)",
+			err:  `rhist: could not parse TFormula "foo(x)": unknown function "foo" at position 0`,
+		},
+		{
+			expr: "sqrt(x,y)",
+			err:  `rhist: could not parse TFormula "sqrt(x,y)": invalid number of arguments to "sqrt" at position 0 (got=2)`,
+		},
+		{
+			expr: "gaus(w)",
+			err:  `rhist: could not parse TFormula "gaus(w)": invalid variable "w" at position 5`,
+		},
+		{
+			expr: "x$",
+			err:  `rhist: could not parse TFormula "x$": invalid character '$' at position 1`,
+		},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			f := &Formula{formula: tc.expr}
+			_, err := f.Func()
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if got, want := err.Error(), tc.err; got != want {
+				t.Fatalf("invalid error:\ngot= %s\nwant=%s", got, want)
+			}
+		})
+	}
+}
+
+func TestLandauPDF(t *testing.T) {
+	// the Landau distribution peaks at x≈-0.22278.
+	var (
+		xmax = -0.22278
+		fmax = landauPDF(xmax, 1, 0)
+	)
+	for _, x := range []float64{-10, -5, -2, -1, -0.5, 0, 1, 3, 10, 30, 100, 500} {
+		v := landauPDF(x, 1, 0)
+		if v < 0 || v > fmax {
+			t.Fatalf("invalid landau(%v)=%v (max=%v)", x, v, fmax)
+		}
+	}
+	if got, want := fmax, 0.180655; math.Abs(got-want) > 1e-5 {
+		t.Fatalf("invalid landau maximum: got=%v, want=%v", got, want)
+	}
+	if got := landauPDF(1, 0, 0); got != 0 {
+		t.Fatalf("invalid landau for xi=0: got=%v", got)
+	}
+}
+
+func TestF1Eval(t *testing.T) {
+	f, err := riofs.Open("../testdata/tformula.root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	obj, err := f.Get("func1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f1 := obj.(*F1)
+
+	fct, err := f1.Func()
+	if err != nil {
+		t.Fatalf("could not create function: %+v", err)
+	}
+	for _, x := range []float64{0, 1, 2, 5.5, 10} {
+		if got, want := fct(x), 10+20*x; got != want {
+			t.Fatalf("invalid f(%v): got=%v, want=%v", x, got, want)
+		}
+	}
+
+	pfct, err := f1.ParamFunc()
+	if err != nil {
+		t.Fatalf("could not create parametric function: %+v", err)
+	}
+	if got, want := pfct(2, []float64{1, 2}), 5.0; got != want {
+		t.Fatalf("invalid f(2; 1, 2): got=%v, want=%v", got, want)
+	}
+
+	if got, want := f1.ParNames(), []string{"p0", "p1"}; got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("invalid parameter names: got=%q, want=%q", got, want)
+	}
+	if got, want := f1.XMax(), 10.0; got != want {
+		t.Fatalf("invalid xmax: got=%v, want=%v", got, want)
+	}
+
+	obj, err = f.Get("func2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = obj.(*F1).Func()
+	if err == nil {
+		t.Fatalf("expected an error for a non-formula function")
+	}
+}
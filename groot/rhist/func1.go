@@ -77,6 +77,92 @@ func (f *F1) Title() string {
 	return f.named.Title()
 }
 
+// XMin returns the lower bound of the function range.
+func (f *F1) XMin() float64 {
+	return f.xmin
+}
+
+// XMax returns the upper bound of the function range.
+func (f *F1) XMax() float64 {
+	return f.xmax
+}
+
+// Chi2 returns the chi-square of the fit of the function.
+func (f *F1) Chi2() float64 {
+	return f.chi2
+}
+
+// NDF returns the number of degrees of freedom of the fit of the function.
+func (f *F1) NDF() int {
+	return int(f.ndf)
+}
+
+// Formula returns the formula of the function, if any.
+func (f *F1) Formula() *Formula {
+	return f.formula
+}
+
+// Params returns the values of the function parameters.
+func (f *F1) Params() []float64 {
+	switch {
+	case f.formula != nil:
+		return f.formula.Params()
+	case f.params != nil:
+		return f.params.params
+	default:
+		return nil
+	}
+}
+
+// ParNames returns the names of the function parameters.
+func (f *F1) ParNames() []string {
+	switch {
+	case f.formula != nil:
+		return f.formula.ParNames()
+	case f.params != nil:
+		return f.params.names
+	default:
+		return nil
+	}
+}
+
+// ParErrors returns the errors on the function parameters.
+func (f *F1) ParErrors() []float64 {
+	return f.parErrs
+}
+
+// ParamFunc returns the function with its parameters as arguments,
+// as expected by fit.Func1D.
+// ParamFunc returns an error if the function is not defined by a formula
+// expression (e.g. a compiled C++ function.)
+func (f *F1) ParamFunc() (func(x float64, ps []float64) float64, error) {
+	if f.formula == nil {
+		return nil, fmt.Errorf("rhist: TF1 %q is not defined by a formula", f.Name())
+	}
+	fct, err := f.formula.Func()
+	if err != nil {
+		return nil, err
+	}
+	return func(x float64, ps []float64) float64 {
+		return fct([]float64{x}, ps)
+	}, nil
+}
+
+// Func returns the function, evaluated with its current parameters,
+// as expected by hplot.NewFunction.
+// Func returns an error if the function is not defined by a formula
+// expression (e.g. a compiled C++ function.)
+func (f *F1) Func() (func(x float64) float64, error) {
+	fct, err := f.ParamFunc()
+	if err != nil {
+		return nil, err
+	}
+	ps := append([]float64(nil), f.Params()...)
+	return func(x float64) float64 {
+		return fct(x, ps)
+	}, nil
+}
+
 // MarshalROOT implements rbytes.Marshaler
 func (f *F1) MarshalROOT(w *rbytes.WBuffer) (int, error) {
 	if w.Err() != nil {
@@ -0,0 +1,424 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rhist
+
+import (
+	"fmt"
+	"reflect"
+
+	"go-hep.org/x/hep/groot/rbase"
+	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/rcont"
+	"go-hep.org/x/hep/groot/root"
+	"go-hep.org/x/hep/groot/rtypes"
+	"go-hep.org/x/hep/groot/rvers"
+	"go-hep.org/x/hep/hbook"
+	"go-hep.org/x/hep/hbook/yodacnv"
+)
+
+type tgraph2d struct {
+	rbase.Named
+	attline   rbase.AttLine
+	attfill   rbase.AttFill
+	attmarker rbase.AttMarker
+
+	npoints int32
+	npx     int32 // number of bins along X in fHistogram
+	npy     int32 // number of bins along Y in fHistogram
+	maxiter int32 // maximum number of iterations to find Delaunay triangles
+	x       []float64
+	y       []float64
+	z       []float64
+	min     float64 // minimum value for plotting along z
+	max     float64 // maximum value for plotting along z
+	margin  float64 // extra space (in %) around interpolated area for fHistogram
+	zout    float64 // fHistogram bin height for points lying outside the interpolated area
+	funcs   root.List
+}
+
+func newGraph2D(n int) *tgraph2d {
+	return &tgraph2d{
+		Named:     *rbase.NewNamed("Graph2D", "Graph2D"),
+		attline:   *rbase.NewAttLine(),
+		attfill:   *rbase.NewAttFill(),
+		attmarker: *rbase.NewAttMarker(),
+		npoints:   int32(n),
+		npx:       40,
+		npy:       40,
+		maxiter:   100000,
+		x:         make([]float64, n),
+		y:         make([]float64, n),
+		z:         make([]float64, n),
+		min:       -1111,
+		max:       -1111,
+		margin:    0.1,
+		funcs:     rcont.NewList("", nil),
+	}
+}
+
+// NewGraph2DFrom creates a new Graph2D from 3-dim hbook data points.
+func NewGraph2DFrom(s3 *hbook.S3D) Graph2D {
+	var (
+		n     = s3.Len()
+		groot = newGraph2D(n)
+	)
+
+	for i, pt := range s3.Points() {
+		groot.x[i] = pt.X
+		groot.y[i] = pt.Y
+		groot.z[i] = pt.Z
+	}
+
+	groot.Named.SetName(s3.Name())
+	if v, ok := s3.Annotation()["title"]; ok {
+		groot.Named.SetTitle(v.(string))
+	}
+
+	return groot
+}
+
+func (*tgraph2d) RVersion() int16 {
+	return rvers.Graph2D
+}
+
+func (g *tgraph2d) Class() string {
+	return "TGraph2D"
+}
+
+func (g *tgraph2d) Len() int {
+	return len(g.x)
+}
+
+func (g *tgraph2d) XYZ(i int) (float64, float64, float64) {
+	return g.x[i], g.y[i], g.z[i]
+}
+
+func (g *tgraph2d) ROOTMerge(src root.Object) error {
+	switch src := src.(type) {
+	case *tgraph2d:
+		g.npoints += src.npoints
+		g.x = append(g.x, src.x...)
+		g.y = append(g.y, src.y...)
+		g.z = append(g.z, src.z...)
+		// FIXME(sbinet): handle g.funcs
+		return nil
+	default:
+		return fmt.Errorf("rhist: can not merge %T into %T", src, g)
+	}
+}
+
+// MarshalROOT implements rbytes.Marshaler
+func (g *tgraph2d) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	hdr := w.WriteHeader(g.Class(), g.RVersion())
+
+	w.WriteObject(&g.Named)
+	w.WriteObject(&g.attline)
+	w.WriteObject(&g.attfill)
+	w.WriteObject(&g.attmarker)
+
+	w.WriteI32(g.npoints)
+	w.WriteI32(g.npx)
+	w.WriteI32(g.npy)
+	w.WriteI32(g.maxiter)
+	{
+		w.WriteI8(1)
+		w.WriteArrayF64(g.x)
+		w.WriteI8(1)
+		w.WriteArrayF64(g.y)
+		w.WriteI8(1)
+		w.WriteArrayF64(g.z)
+	}
+	w.WriteF64(g.min)
+	w.WriteF64(g.max)
+	w.WriteF64(g.margin)
+	w.WriteF64(g.zout)
+	w.WriteObjectAny(g.funcs)
+
+	return w.SetHeader(hdr)
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (g *tgraph2d) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	hdr := r.ReadHeader(g.Class())
+	if hdr.Vers > rvers.Graph2D {
+		panic(fmt.Errorf(
+			"rhist: invalid %s version=%d > %d",
+			g.Class(), hdr.Vers, g.RVersion(),
+		))
+	}
+
+	r.ReadObject(&g.Named)
+	r.ReadObject(&g.attline)
+	r.ReadObject(&g.attfill)
+	r.ReadObject(&g.attmarker)
+
+	g.npoints = r.ReadI32()
+	g.npx = r.ReadI32()
+	g.npy = r.ReadI32()
+	g.maxiter = r.ReadI32()
+	{
+		_ = r.ReadI8()
+		g.x = make([]float64, g.npoints)
+		r.ReadArrayF64(g.x)
+		_ = r.ReadI8()
+		g.y = make([]float64, g.npoints)
+		r.ReadArrayF64(g.y)
+		_ = r.ReadI8()
+		g.z = make([]float64, g.npoints)
+		r.ReadArrayF64(g.z)
+	}
+	g.min = r.ReadF64()
+	g.max = r.ReadF64()
+	g.margin = r.ReadF64()
+	g.zout = r.ReadF64()
+
+	g.funcs = nil
+	if funcs := r.ReadObjectAny(); funcs != nil {
+		g.funcs = funcs.(root.List)
+	}
+
+	r.CheckHeader(hdr)
+	return r.Err()
+}
+
+// MarshalYODA implements the YODAMarshaler interface.
+func (g *tgraph2d) MarshalYODA() ([]byte, error) {
+	pts := make([]hbook.Point3D, g.Len())
+	for i := range pts {
+		x, y, z := g.XYZ(i)
+		pts[i].X = x
+		pts[i].Y = y
+		pts[i].Z = z
+	}
+
+	s3d := hbook.NewS3D(pts...)
+	s3d.Annotation()["name"] = g.Name()
+	s3d.Annotation()["title"] = g.Title()
+	return s3d.MarshalYODA()
+}
+
+// UnmarshalYODA implements the YODAUnmarshaler interface.
+func (g *tgraph2d) UnmarshalYODA(raw []byte) error {
+	var gg hbook.S3D
+	err := gg.UnmarshalYODA(raw)
+	if err != nil {
+		return err
+	}
+
+	*g = *NewGraph2DFrom(&gg).(*tgraph2d)
+	return nil
+}
+
+type tgraph2derrs struct {
+	tgraph2d
+
+	xerr []float64
+	yerr []float64
+	zerr []float64
+}
+
+func newGraph2DErrs(n int) *tgraph2derrs {
+	return &tgraph2derrs{
+		tgraph2d: *newGraph2D(n),
+		xerr:     make([]float64, n),
+		yerr:     make([]float64, n),
+		zerr:     make([]float64, n),
+	}
+}
+
+// NewGraph2DErrorsFrom creates a new Graph2DErrors from 3-dim hbook data points.
+// As TGraph2DErrors only stores symmetric errors, the lower error of each
+// data point is used.
+func NewGraph2DErrorsFrom(s3 *hbook.S3D) Graph2DErrors {
+	var (
+		n     = s3.Len()
+		groot = newGraph2DErrs(n)
+	)
+
+	for i, pt := range s3.Points() {
+		groot.x[i] = pt.X
+		groot.xerr[i] = pt.ErrX.Min
+		groot.y[i] = pt.Y
+		groot.yerr[i] = pt.ErrY.Min
+		groot.z[i] = pt.Z
+		groot.zerr[i] = pt.ErrZ.Min
+	}
+
+	groot.tgraph2d.Named.SetName(s3.Name())
+	if v, ok := s3.Annotation()["title"]; ok {
+		groot.tgraph2d.Named.SetTitle(v.(string))
+	}
+
+	return groot
+}
+
+func (*tgraph2derrs) RVersion() int16 {
+	return rvers.Graph2DErrors
+}
+
+func (g *tgraph2derrs) Class() string {
+	return "TGraph2DErrors"
+}
+
+func (g *tgraph2derrs) XError(i int) (float64, float64) {
+	return g.xerr[i], g.xerr[i]
+}
+
+func (g *tgraph2derrs) YError(i int) (float64, float64) {
+	return g.yerr[i], g.yerr[i]
+}
+
+func (g *tgraph2derrs) ZError(i int) (float64, float64) {
+	return g.zerr[i], g.zerr[i]
+}
+
+func (g *tgraph2derrs) ROOTMerge(src root.Object) error {
+	switch src := src.(type) {
+	case *tgraph2derrs:
+		err := g.tgraph2d.ROOTMerge(&src.tgraph2d)
+		if err != nil {
+			return fmt.Errorf("rhist: could not merge %q: %w", src.Name(), err)
+		}
+		g.xerr = append(g.xerr, src.xerr...)
+		g.yerr = append(g.yerr, src.yerr...)
+		g.zerr = append(g.zerr, src.zerr...)
+		return nil
+	default:
+		return fmt.Errorf("rhist: can not merge %T into %T", src, g)
+	}
+}
+
+// MarshalROOT implements rbytes.Marshaler
+func (g *tgraph2derrs) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	hdr := w.WriteHeader(g.Class(), g.RVersion())
+
+	w.WriteObject(&g.tgraph2d)
+	{
+		w.WriteI8(1)
+		w.WriteArrayF64(g.xerr)
+		w.WriteI8(1)
+		w.WriteArrayF64(g.yerr)
+		w.WriteI8(1)
+		w.WriteArrayF64(g.zerr)
+	}
+
+	return w.SetHeader(hdr)
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (g *tgraph2derrs) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	hdr := r.ReadHeader(g.Class())
+	if hdr.Vers > rvers.Graph2DErrors {
+		panic(fmt.Errorf(
+			"rhist: invalid %s version=%d > %d",
+			g.Class(), hdr.Vers, g.RVersion(),
+		))
+	}
+
+	r.ReadObject(&g.tgraph2d)
+	{
+		n := g.tgraph2d.npoints
+		_ = r.ReadI8()
+		g.xerr = make([]float64, n)
+		r.ReadArrayF64(g.xerr)
+		_ = r.ReadI8()
+		g.yerr = make([]float64, n)
+		r.ReadArrayF64(g.yerr)
+		_ = r.ReadI8()
+		g.zerr = make([]float64, n)
+		r.ReadArrayF64(g.zerr)
+	}
+
+	r.CheckHeader(hdr)
+	return r.Err()
+}
+
+// MarshalYODA implements the YODAMarshaler interface.
+func (g *tgraph2derrs) MarshalYODA() ([]byte, error) {
+	pts := make([]hbook.Point3D, g.Len())
+	for i := range pts {
+		x, y, z := g.XYZ(i)
+		xlo, xhi := g.XError(i)
+		ylo, yhi := g.YError(i)
+		zlo, zhi := g.ZError(i)
+		pts[i] = hbook.Point3D{
+			X: x, Y: y, Z: z,
+			ErrX: hbook.Range{Min: xlo, Max: xhi},
+			ErrY: hbook.Range{Min: ylo, Max: yhi},
+			ErrZ: hbook.Range{Min: zlo, Max: zhi},
+		}
+	}
+
+	s3d := hbook.NewS3D(pts...)
+	s3d.Annotation()["name"] = g.Name()
+	s3d.Annotation()["title"] = g.Title()
+	return s3d.MarshalYODA()
+}
+
+// UnmarshalYODA implements the YODAUnmarshaler interface.
+func (g *tgraph2derrs) UnmarshalYODA(raw []byte) error {
+	var gg hbook.S3D
+	err := gg.UnmarshalYODA(raw)
+	if err != nil {
+		return err
+	}
+
+	*g = *NewGraph2DErrorsFrom(&gg).(*tgraph2derrs)
+	return nil
+}
+
+func init() {
+	{
+		f := func() reflect.Value {
+			o := newGraph2D(0)
+			return reflect.ValueOf(o)
+		}
+		rtypes.Factory.Add("TGraph2D", f)
+	}
+	{
+		f := func() reflect.Value {
+			o := newGraph2DErrs(0)
+			return reflect.ValueOf(o)
+		}
+		rtypes.Factory.Add("TGraph2DErrors", f)
+	}
+}
+
+var (
+	_ root.Object         = (*tgraph2d)(nil)
+	_ root.Named          = (*tgraph2d)(nil)
+	_ root.Merger         = (*tgraph2d)(nil)
+	_ Graph2D             = (*tgraph2d)(nil)
+	_ rbytes.Marshaler    = (*tgraph2d)(nil)
+	_ rbytes.Unmarshaler  = (*tgraph2d)(nil)
+	_ yodacnv.Marshaler   = (*tgraph2d)(nil)
+	_ yodacnv.Unmarshaler = (*tgraph2d)(nil)
+
+	_ root.Object         = (*tgraph2derrs)(nil)
+	_ root.Named          = (*tgraph2derrs)(nil)
+	_ root.Merger         = (*tgraph2derrs)(nil)
+	_ Graph2D             = (*tgraph2derrs)(nil)
+	_ Graph2DErrors       = (*tgraph2derrs)(nil)
+	_ rbytes.Marshaler    = (*tgraph2derrs)(nil)
+	_ rbytes.Unmarshaler  = (*tgraph2derrs)(nil)
+	_ yodacnv.Marshaler   = (*tgraph2derrs)(nil)
+	_ yodacnv.Unmarshaler = (*tgraph2derrs)(nil)
+)
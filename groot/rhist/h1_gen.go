@@ -263,19 +263,26 @@ func (h *H1F) UnmarshalYODA(raw []byte) error {
 	return nil
 }
 
+// ROOTMerge merges the content of src into h.
+// ROOTMerge returns an error if the axes of the histograms are not compatible.
 func (h *H1F) ROOTMerge(src root.Object) error {
 	hsrc, ok := src.(*H1F)
 	if !ok {
 		return fmt.Errorf("rhist: object %q is not a *rhist.H1F (%T)", src.(root.Named).Name(), src)
 	}
 
-	var (
-		h1   = h.AsH1D()
-		h2   = hsrc.AsH1D()
-		hadd = hbook.AddH1D(h1, h2)
+	err := h.th1.merge(
+		&hsrc.th1,
+		func(i int) float64 { return float64(h.arr.Data[i]) },
+		func(i int) float64 { return float64(hsrc.arr.Data[i]) },
 	)
+	if err != nil {
+		return fmt.Errorf("rhist: could not merge %q: %w", h.Name(), err)
+	}
 
-	*h = *NewH1FFrom(hadd)
+	for i, v := range hsrc.arr.Data {
+		h.arr.Data[i] += v
+	}
 	return nil
 }
 
@@ -540,19 +547,26 @@ func (h *H1D) UnmarshalYODA(raw []byte) error {
 	return nil
 }
 
+// ROOTMerge merges the content of src into h.
+// ROOTMerge returns an error if the axes of the histograms are not compatible.
 func (h *H1D) ROOTMerge(src root.Object) error {
 	hsrc, ok := src.(*H1D)
 	if !ok {
-		return fmt.Errorf("rhist: object %q is not a *rhist.H1F (%T)", src.(root.Named).Name(), src)
+		return fmt.Errorf("rhist: object %q is not a *rhist.H1D (%T)", src.(root.Named).Name(), src)
 	}
 
-	var (
-		h1   = h.AsH1D()
-		h2   = hsrc.AsH1D()
-		hadd = hbook.AddH1D(h1, h2)
+	err := h.th1.merge(
+		&hsrc.th1,
+		func(i int) float64 { return float64(h.arr.Data[i]) },
+		func(i int) float64 { return float64(hsrc.arr.Data[i]) },
 	)
+	if err != nil {
+		return fmt.Errorf("rhist: could not merge %q: %w", h.Name(), err)
+	}
 
-	*h = *NewH1DFrom(hadd)
+	for i, v := range hsrc.arr.Data {
+		h.arr.Data[i] += v
+	}
 	return nil
 }
 
@@ -817,19 +831,26 @@ func (h *H1I) UnmarshalYODA(raw []byte) error {
 	return nil
 }
 
+// ROOTMerge merges the content of src into h.
+// ROOTMerge returns an error if the axes of the histograms are not compatible.
 func (h *H1I) ROOTMerge(src root.Object) error {
 	hsrc, ok := src.(*H1I)
 	if !ok {
-		return fmt.Errorf("rhist: object %q is not a *rhist.H1F (%T)", src.(root.Named).Name(), src)
+		return fmt.Errorf("rhist: object %q is not a *rhist.H1I (%T)", src.(root.Named).Name(), src)
 	}
 
-	var (
-		h1   = h.AsH1D()
-		h2   = hsrc.AsH1D()
-		hadd = hbook.AddH1D(h1, h2)
+	err := h.th1.merge(
+		&hsrc.th1,
+		func(i int) float64 { return float64(h.arr.Data[i]) },
+		func(i int) float64 { return float64(hsrc.arr.Data[i]) },
 	)
+	if err != nil {
+		return fmt.Errorf("rhist: could not merge %q: %w", h.Name(), err)
+	}
 
-	*h = *NewH1IFrom(hadd)
+	for i, v := range hsrc.arr.Data {
+		h.arr.Data[i] += v
+	}
 	return nil
 }
 
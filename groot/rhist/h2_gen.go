@@ -368,6 +368,29 @@ func (h *H2F) MarshalROOT(w *rbytes.WBuffer) (int, error) {
 	return w.SetHeader(hdr)
 }
 
+// ROOTMerge merges the content of src into h.
+// ROOTMerge returns an error if the axes of the histograms are not compatible.
+func (h *H2F) ROOTMerge(src root.Object) error {
+	hsrc, ok := src.(*H2F)
+	if !ok {
+		return fmt.Errorf("rhist: object %q is not a *rhist.H2F (%T)", src.(root.Named).Name(), src)
+	}
+
+	err := h.th2.merge(
+		&hsrc.th2,
+		func(i int) float64 { return float64(h.arr.Data[i]) },
+		func(i int) float64 { return float64(hsrc.arr.Data[i]) },
+	)
+	if err != nil {
+		return fmt.Errorf("rhist: could not merge %q: %w", h.Name(), err)
+	}
+
+	for i, v := range hsrc.arr.Data {
+		h.arr.Data[i] += v
+	}
+	return nil
+}
+
 func (h *H2F) UnmarshalROOT(r *rbytes.RBuffer) error {
 	if r.Err() != nil {
 		return r.Err()
@@ -398,6 +421,7 @@ func init() {
 
 var (
 	_ root.Object        = (*H2F)(nil)
+	_ root.Merger        = (*H2F)(nil)
 	_ root.Named         = (*H2F)(nil)
 	_ H2                 = (*H2F)(nil)
 	_ rbytes.Marshaler   = (*H2F)(nil)
@@ -753,6 +777,29 @@ func (h *H2D) MarshalROOT(w *rbytes.WBuffer) (int, error) {
 	return w.SetHeader(hdr)
 }
 
+// ROOTMerge merges the content of src into h.
+// ROOTMerge returns an error if the axes of the histograms are not compatible.
+func (h *H2D) ROOTMerge(src root.Object) error {
+	hsrc, ok := src.(*H2D)
+	if !ok {
+		return fmt.Errorf("rhist: object %q is not a *rhist.H2D (%T)", src.(root.Named).Name(), src)
+	}
+
+	err := h.th2.merge(
+		&hsrc.th2,
+		func(i int) float64 { return float64(h.arr.Data[i]) },
+		func(i int) float64 { return float64(hsrc.arr.Data[i]) },
+	)
+	if err != nil {
+		return fmt.Errorf("rhist: could not merge %q: %w", h.Name(), err)
+	}
+
+	for i, v := range hsrc.arr.Data {
+		h.arr.Data[i] += v
+	}
+	return nil
+}
+
 func (h *H2D) UnmarshalROOT(r *rbytes.RBuffer) error {
 	if r.Err() != nil {
 		return r.Err()
@@ -783,6 +830,7 @@ func init() {
 
 var (
 	_ root.Object        = (*H2D)(nil)
+	_ root.Merger        = (*H2D)(nil)
 	_ root.Named         = (*H2D)(nil)
 	_ H2                 = (*H2D)(nil)
 	_ rbytes.Marshaler   = (*H2D)(nil)
@@ -1138,6 +1186,29 @@ func (h *H2I) MarshalROOT(w *rbytes.WBuffer) (int, error) {
 	return w.SetHeader(hdr)
 }
 
+// ROOTMerge merges the content of src into h.
+// ROOTMerge returns an error if the axes of the histograms are not compatible.
+func (h *H2I) ROOTMerge(src root.Object) error {
+	hsrc, ok := src.(*H2I)
+	if !ok {
+		return fmt.Errorf("rhist: object %q is not a *rhist.H2I (%T)", src.(root.Named).Name(), src)
+	}
+
+	err := h.th2.merge(
+		&hsrc.th2,
+		func(i int) float64 { return float64(h.arr.Data[i]) },
+		func(i int) float64 { return float64(hsrc.arr.Data[i]) },
+	)
+	if err != nil {
+		return fmt.Errorf("rhist: could not merge %q: %w", h.Name(), err)
+	}
+
+	for i, v := range hsrc.arr.Data {
+		h.arr.Data[i] += v
+	}
+	return nil
+}
+
 func (h *H2I) UnmarshalROOT(r *rbytes.RBuffer) error {
 	if r.Err() != nil {
 		return r.Err()
@@ -1168,6 +1239,7 @@ func init() {
 
 var (
 	_ root.Object        = (*H2I)(nil)
+	_ root.Merger        = (*H2I)(nil)
 	_ root.Named         = (*H2I)(nil)
 	_ H2                 = (*H2I)(nil)
 	_ rbytes.Marshaler   = (*H2I)(nil)
@@ -0,0 +1,1250 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Automatically generated. DO NOT EDIT.
+
+package rhist
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/rcont"
+	"go-hep.org/x/hep/groot/root"
+	"go-hep.org/x/hep/groot/rtypes"
+	"go-hep.org/x/hep/groot/rvers"
+	"go-hep.org/x/hep/hbook"
+)
+
+// H3F implements ROOT TH3F
+type H3F struct {
+	th3
+	arr rcont.ArrayF
+}
+
+func newH3F() *H3F {
+	return &H3F{
+		th3: *newH3(),
+	}
+}
+
+// NewH3FFrom creates a new H3F from hbook 3-dim histogram.
+func NewH3FFrom(h *hbook.H3D) *H3F {
+	var (
+		hroot  = newH3F()
+		bins   = h.Binning.Bins
+		nxbins = h.Binning.Nx
+		nybins = h.Binning.Ny
+		nzbins = h.Binning.Nz
+		xedges = make([]float64, 0, nxbins+1)
+		yedges = make([]float64, 0, nybins+1)
+		zedges = make([]float64, 0, nzbins+1)
+	)
+
+	hroot.th3.th1.entries = float64(h.Entries())
+	hroot.th3.th1.tsumw = h.SumW()
+	hroot.th3.th1.tsumw2 = h.SumW2()
+	hroot.th3.th1.tsumwx = h.SumWX()
+	hroot.th3.th1.tsumwx2 = h.SumWX2()
+	hroot.th3.tsumwy = h.SumWY()
+	hroot.th3.tsumwy2 = h.SumWY2()
+	hroot.th3.tsumwxy = h.SumWXY()
+	hroot.th3.tsumwz = h.SumWZ()
+	hroot.th3.tsumwz2 = h.SumWZ2()
+	hroot.th3.tsumwxz = h.SumWXZ()
+	hroot.th3.tsumwyz = h.SumWYZ()
+
+	ncells := (nxbins + 2) * (nybins + 2) * (nzbins + 2)
+	hroot.th3.th1.ncells = ncells
+
+	hroot.th3.th1.xaxis.nbins = nxbins
+	hroot.th3.th1.xaxis.xmin = h.XMin()
+	hroot.th3.th1.xaxis.xmax = h.XMax()
+
+	hroot.th3.th1.yaxis.nbins = nybins
+	hroot.th3.th1.yaxis.xmin = h.YMin()
+	hroot.th3.th1.yaxis.xmax = h.YMax()
+
+	hroot.th3.th1.zaxis.nbins = nzbins
+	hroot.th3.th1.zaxis.xmin = h.ZMin()
+	hroot.th3.th1.zaxis.xmax = h.ZMax()
+
+	hroot.arr.Data = make([]float32, ncells)
+	hroot.th3.th1.sumw2.Data = make([]float64, ncells)
+
+	ibin := func(ix, iy, iz int) int { return (iz*nybins+iy)*nxbins + ix }
+
+	for ix := 0; ix < nxbins; ix++ {
+		for iy := 0; iy < nybins; iy++ {
+			for iz := 0; iz < nzbins; iz++ {
+				bin := bins[ibin(ix, iy, iz)]
+				if iy == 0 && iz == 0 {
+					xedges = append(xedges, bin.XMin())
+				}
+				if ix == 0 && iz == 0 {
+					yedges = append(yedges, bin.YMin())
+				}
+				if ix == 0 && iy == 0 {
+					zedges = append(zedges, bin.ZMin())
+				}
+				hroot.setDist3D(ix+1, iy+1, iz+1, bin.Dist.SumW(), bin.Dist.SumW2())
+			}
+		}
+	}
+
+	// outflows are stored in the first ROOT bin of their region.
+	cell := func(k, n int) int {
+		switch k {
+		case -1:
+			return 0
+		case +1:
+			return n + 1
+		default:
+			return 1
+		}
+	}
+	i := 0
+	for kx := -1; kx <= 1; kx++ {
+		for ky := -1; ky <= 1; ky++ {
+			for kz := -1; kz <= 1; kz++ {
+				if kx == 0 && ky == 0 && kz == 0 {
+					continue
+				}
+				oflow := &h.Binning.Outflows[i]
+				hroot.setDist3D(
+					cell(kx, nxbins), cell(ky, nybins), cell(kz, nzbins),
+					oflow.SumW(), oflow.SumW2(),
+				)
+				i++
+			}
+		}
+	}
+
+	xedges = append(xedges, bins[ibin(nxbins-1, 0, 0)].XMax())
+	yedges = append(yedges, bins[ibin(0, nybins-1, 0)].YMax())
+	zedges = append(zedges, bins[ibin(0, 0, nzbins-1)].ZMax())
+
+	hroot.th3.th1.SetName(h.Name())
+	if v, ok := h.Annotation()["title"]; ok && v != nil {
+		hroot.th3.th1.SetTitle(v.(string))
+	}
+	hroot.th3.th1.xaxis.xbins.Data = xedges
+	hroot.th3.th1.yaxis.xbins.Data = yedges
+	hroot.th3.th1.zaxis.xbins.Data = zedges
+
+	return hroot
+}
+
+func (*H3F) RVersion() int16 {
+	return rvers.H3F
+}
+
+func (*H3F) isH3() {}
+
+// Class returns the ROOT class name.
+func (*H3F) Class() string {
+	return "TH3F"
+}
+
+func (h *H3F) Array() rcont.ArrayF {
+	return h.arr
+}
+
+// Rank returns the number of dimensions of this histogram.
+func (h *H3F) Rank() int {
+	return 3
+}
+
+// NbinsX returns the number of bins in X.
+func (h *H3F) NbinsX() int {
+	return h.th1.xaxis.nbins
+}
+
+// XAxis returns the axis along X.
+func (h *H3F) XAxis() Axis {
+	return &h.th1.xaxis
+}
+
+// NbinsY returns the number of bins in Y.
+func (h *H3F) NbinsY() int {
+	return h.th1.yaxis.nbins
+}
+
+// YAxis returns the axis along Y.
+func (h *H3F) YAxis() Axis {
+	return &h.th1.yaxis
+}
+
+// NbinsZ returns the number of bins in Z.
+func (h *H3F) NbinsZ() int {
+	return h.th1.zaxis.nbins
+}
+
+// ZAxis returns the axis along Z.
+func (h *H3F) ZAxis() Axis {
+	return &h.th1.zaxis
+}
+
+// BinContent returns the content of the (ix,iy,iz) bin.
+// Bin indices start at 1; 0 and Nbins+1 are the under- and overflow bins.
+func (h *H3F) BinContent(ix, iy, iz int) float64 {
+	return float64(h.arr.Data[h.bin(ix, iy, iz)])
+}
+
+// BinError returns the error of the (ix,iy,iz) bin.
+// Bin indices start at 1; 0 and Nbins+1 are the under- and overflow bins.
+func (h *H3F) BinError(ix, iy, iz int) float64 {
+	i := h.bin(ix, iy, iz)
+	if len(h.th1.sumw2.Data) > 0 {
+		return math.Sqrt(float64(h.th1.sumw2.Data[i]))
+	}
+	return math.Sqrt(math.Abs(float64(h.arr.Data[i])))
+}
+
+// bin returns the regularized bin number given an (x,y,z) bin index triplet.
+func (h *H3F) bin(ix, iy, iz int) int {
+	nx := h.th1.xaxis.nbins + 1 // overflow bin
+	ny := h.th1.yaxis.nbins + 1 // overflow bin
+	nz := h.th1.zaxis.nbins + 1 // overflow bin
+	switch {
+	case ix < 0:
+		ix = 0
+	case ix > nx:
+		ix = nx
+	}
+	switch {
+	case iy < 0:
+		iy = 0
+	case iy > ny:
+		iy = ny
+	}
+	switch {
+	case iz < 0:
+		iz = 0
+	case iz > nz:
+		iz = nz
+	}
+	return ix + (nx+1)*(iy+(ny+1)*iz)
+}
+
+func (h *H3F) setDist3D(ix, iy, iz int, sumw, sumw2 float64) {
+	i := h.bin(ix, iy, iz)
+	h.arr.Data[i] = float32(sumw)
+	h.th1.sumw2.Data[i] = sumw2
+}
+
+// dist3D returns the distribution of the ROOT bins within the
+// [ix0,ix1]x[iy0,iy1]x[iz0,iz1] (inclusive) bin indices ranges.
+func (h *H3F) dist3D(ix0, ix1, iy0, iy1, iz0, iz1 int) hbook.Dist3D {
+	var (
+		n     int64
+		sumw  float64
+		sumw2 float64
+	)
+	for ix := ix0; ix <= ix1; ix++ {
+		for iy := iy0; iy <= iy1; iy++ {
+			for iz := iz0; iz <= iz1; iz++ {
+				i := h.bin(ix, iy, iz)
+				v := float64(h.arr.Data[i])
+				n += h.entries(v, h.BinError(ix, iy, iz))
+				sumw += v
+				if len(h.th1.sumw2.Data) > 0 {
+					sumw2 += h.th1.sumw2.Data[i]
+				}
+			}
+		}
+	}
+	d := hbook.Dist1D{
+		Dist: hbook.Dist0D{
+			N:     n,
+			SumW:  sumw,
+			SumW2: sumw2,
+		},
+	}
+	return hbook.Dist3D{X: d, Y: d, Z: d}
+}
+
+func (h *H3F) entries(height, err float64) int64 {
+	if height <= 0 {
+		return 0
+	}
+	v := height / err
+	return int64(v*v + 0.5)
+}
+
+// AsH3D creates a new hbook.H3D from this ROOT histogram.
+func (h *H3F) AsH3D() *hbook.H3D {
+	var (
+		nx = h.NbinsX()
+		ny = h.NbinsY()
+		nz = h.NbinsZ()
+		hh = hbook.NewH3DFromEdges(
+			axisEdges(h.XAxis()),
+			axisEdges(h.YAxis()),
+			axisEdges(h.ZAxis()),
+		)
+	)
+	hh.Ann = hbook.Annotation{
+		"name":  h.Name(),
+		"title": h.Title(),
+	}
+
+	// ranges of ROOT bin indices for underflow, in-range and overflow bins.
+	rng := func(k, n int) (int, int) {
+		switch k {
+		case -1:
+			return 0, 0
+		case +1:
+			return n + 1, n + 1
+		default:
+			return 1, n
+		}
+	}
+	i := 0
+	for kx := -1; kx <= 1; kx++ {
+		for ky := -1; ky <= 1; ky++ {
+			for kz := -1; kz <= 1; kz++ {
+				if kx == 0 && ky == 0 && kz == 0 {
+					continue
+				}
+				ix0, ix1 := rng(kx, nx)
+				iy0, iy1 := rng(ky, ny)
+				iz0, iz1 := rng(kz, nz)
+				hh.Binning.Outflows[i] = h.dist3D(ix0, ix1, iy0, iy1, iz0, iz1)
+				i++
+			}
+		}
+	}
+
+	hh.Binning.Dist = hbook.Dist3D{
+		X: hbook.Dist1D{
+			Dist: hbook.Dist0D{
+				N:     int64(h.Entries()),
+				SumW:  float64(h.SumW()),
+				SumW2: float64(h.SumW2()),
+			},
+		},
+	}
+	hh.Binning.Dist.Y = hh.Binning.Dist.X
+	hh.Binning.Dist.Z = hh.Binning.Dist.X
+	hh.Binning.Dist.X.Stats.SumWX = float64(h.SumWX())
+	hh.Binning.Dist.X.Stats.SumWX2 = float64(h.SumWX2())
+	hh.Binning.Dist.Y.Stats.SumWX = float64(h.SumWY())
+	hh.Binning.Dist.Y.Stats.SumWX2 = float64(h.SumWY2())
+	hh.Binning.Dist.Z.Stats.SumWX = float64(h.SumWZ())
+	hh.Binning.Dist.Z.Stats.SumWX2 = float64(h.SumWZ2())
+	hh.Binning.Dist.Stats.SumWXY = h.SumWXY()
+	hh.Binning.Dist.Stats.SumWXZ = h.SumWXZ()
+	hh.Binning.Dist.Stats.SumWYZ = h.SumWYZ()
+
+	for ix := 0; ix < nx; ix++ {
+		for iy := 0; iy < ny; iy++ {
+			for iz := 0; iz < nz; iz++ {
+				i := (iz*ny+iy)*nx + ix
+				hh.Binning.Bins[i].Dist = h.dist3D(ix+1, ix+1, iy+1, iy+1, iz+1, iz+1)
+			}
+		}
+	}
+
+	return hh
+}
+
+// MarshalYODA implements the YODAMarshaler interface.
+func (h *H3F) MarshalYODA() ([]byte, error) {
+	return h.AsH3D().MarshalYODA()
+}
+
+func (h *H3F) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	hdr := w.WriteHeader(h.Class(), h.RVersion())
+	w.WriteObject(&h.th3)
+	w.WriteObject(&h.arr)
+
+	return w.SetHeader(hdr)
+}
+
+// ROOTMerge merges the content of src into h.
+// ROOTMerge returns an error if the axes of the histograms are not compatible.
+func (h *H3F) ROOTMerge(src root.Object) error {
+	hsrc, ok := src.(*H3F)
+	if !ok {
+		return fmt.Errorf("rhist: object %q is not a *rhist.H3F (%T)", src.(root.Named).Name(), src)
+	}
+
+	err := h.th3.merge(
+		&hsrc.th3,
+		func(i int) float64 { return float64(h.arr.Data[i]) },
+		func(i int) float64 { return float64(hsrc.arr.Data[i]) },
+	)
+	if err != nil {
+		return fmt.Errorf("rhist: could not merge %q: %w", h.Name(), err)
+	}
+
+	for i, v := range hsrc.arr.Data {
+		h.arr.Data[i] += v
+	}
+	return nil
+}
+
+func (h *H3F) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	hdr := r.ReadHeader(h.Class())
+	if hdr.Vers > rvers.H3F {
+		panic(fmt.Errorf("rhist: invalid H3F version=%d > %d", hdr.Vers, rvers.H3F))
+	}
+	if hdr.Vers < 1 {
+		return fmt.Errorf("rhist: TH3F version too old (%d<1)", hdr.Vers)
+	}
+
+	r.ReadObject(&h.th3)
+	r.ReadObject(&h.arr)
+
+	r.CheckHeader(hdr)
+	return r.Err()
+}
+
+func init() {
+	f := func() reflect.Value {
+		o := newH3F()
+		return reflect.ValueOf(o)
+	}
+	rtypes.Factory.Add("TH3F", f)
+}
+
+var (
+	_ root.Object        = (*H3F)(nil)
+	_ root.Merger        = (*H3F)(nil)
+	_ root.Named         = (*H3F)(nil)
+	_ H3                 = (*H3F)(nil)
+	_ rbytes.Marshaler   = (*H3F)(nil)
+	_ rbytes.Unmarshaler = (*H3F)(nil)
+)
+
+// H3D implements ROOT TH3D
+type H3D struct {
+	th3
+	arr rcont.ArrayD
+}
+
+func newH3D() *H3D {
+	return &H3D{
+		th3: *newH3(),
+	}
+}
+
+// NewH3DFrom creates a new H3D from hbook 3-dim histogram.
+func NewH3DFrom(h *hbook.H3D) *H3D {
+	var (
+		hroot  = newH3D()
+		bins   = h.Binning.Bins
+		nxbins = h.Binning.Nx
+		nybins = h.Binning.Ny
+		nzbins = h.Binning.Nz
+		xedges = make([]float64, 0, nxbins+1)
+		yedges = make([]float64, 0, nybins+1)
+		zedges = make([]float64, 0, nzbins+1)
+	)
+
+	hroot.th3.th1.entries = float64(h.Entries())
+	hroot.th3.th1.tsumw = h.SumW()
+	hroot.th3.th1.tsumw2 = h.SumW2()
+	hroot.th3.th1.tsumwx = h.SumWX()
+	hroot.th3.th1.tsumwx2 = h.SumWX2()
+	hroot.th3.tsumwy = h.SumWY()
+	hroot.th3.tsumwy2 = h.SumWY2()
+	hroot.th3.tsumwxy = h.SumWXY()
+	hroot.th3.tsumwz = h.SumWZ()
+	hroot.th3.tsumwz2 = h.SumWZ2()
+	hroot.th3.tsumwxz = h.SumWXZ()
+	hroot.th3.tsumwyz = h.SumWYZ()
+
+	ncells := (nxbins + 2) * (nybins + 2) * (nzbins + 2)
+	hroot.th3.th1.ncells = ncells
+
+	hroot.th3.th1.xaxis.nbins = nxbins
+	hroot.th3.th1.xaxis.xmin = h.XMin()
+	hroot.th3.th1.xaxis.xmax = h.XMax()
+
+	hroot.th3.th1.yaxis.nbins = nybins
+	hroot.th3.th1.yaxis.xmin = h.YMin()
+	hroot.th3.th1.yaxis.xmax = h.YMax()
+
+	hroot.th3.th1.zaxis.nbins = nzbins
+	hroot.th3.th1.zaxis.xmin = h.ZMin()
+	hroot.th3.th1.zaxis.xmax = h.ZMax()
+
+	hroot.arr.Data = make([]float64, ncells)
+	hroot.th3.th1.sumw2.Data = make([]float64, ncells)
+
+	ibin := func(ix, iy, iz int) int { return (iz*nybins+iy)*nxbins + ix }
+
+	for ix := 0; ix < nxbins; ix++ {
+		for iy := 0; iy < nybins; iy++ {
+			for iz := 0; iz < nzbins; iz++ {
+				bin := bins[ibin(ix, iy, iz)]
+				if iy == 0 && iz == 0 {
+					xedges = append(xedges, bin.XMin())
+				}
+				if ix == 0 && iz == 0 {
+					yedges = append(yedges, bin.YMin())
+				}
+				if ix == 0 && iy == 0 {
+					zedges = append(zedges, bin.ZMin())
+				}
+				hroot.setDist3D(ix+1, iy+1, iz+1, bin.Dist.SumW(), bin.Dist.SumW2())
+			}
+		}
+	}
+
+	// outflows are stored in the first ROOT bin of their region.
+	cell := func(k, n int) int {
+		switch k {
+		case -1:
+			return 0
+		case +1:
+			return n + 1
+		default:
+			return 1
+		}
+	}
+	i := 0
+	for kx := -1; kx <= 1; kx++ {
+		for ky := -1; ky <= 1; ky++ {
+			for kz := -1; kz <= 1; kz++ {
+				if kx == 0 && ky == 0 && kz == 0 {
+					continue
+				}
+				oflow := &h.Binning.Outflows[i]
+				hroot.setDist3D(
+					cell(kx, nxbins), cell(ky, nybins), cell(kz, nzbins),
+					oflow.SumW(), oflow.SumW2(),
+				)
+				i++
+			}
+		}
+	}
+
+	xedges = append(xedges, bins[ibin(nxbins-1, 0, 0)].XMax())
+	yedges = append(yedges, bins[ibin(0, nybins-1, 0)].YMax())
+	zedges = append(zedges, bins[ibin(0, 0, nzbins-1)].ZMax())
+
+	hroot.th3.th1.SetName(h.Name())
+	if v, ok := h.Annotation()["title"]; ok && v != nil {
+		hroot.th3.th1.SetTitle(v.(string))
+	}
+	hroot.th3.th1.xaxis.xbins.Data = xedges
+	hroot.th3.th1.yaxis.xbins.Data = yedges
+	hroot.th3.th1.zaxis.xbins.Data = zedges
+
+	return hroot
+}
+
+func (*H3D) RVersion() int16 {
+	return rvers.H3D
+}
+
+func (*H3D) isH3() {}
+
+// Class returns the ROOT class name.
+func (*H3D) Class() string {
+	return "TH3D"
+}
+
+func (h *H3D) Array() rcont.ArrayD {
+	return h.arr
+}
+
+// Rank returns the number of dimensions of this histogram.
+func (h *H3D) Rank() int {
+	return 3
+}
+
+// NbinsX returns the number of bins in X.
+func (h *H3D) NbinsX() int {
+	return h.th1.xaxis.nbins
+}
+
+// XAxis returns the axis along X.
+func (h *H3D) XAxis() Axis {
+	return &h.th1.xaxis
+}
+
+// NbinsY returns the number of bins in Y.
+func (h *H3D) NbinsY() int {
+	return h.th1.yaxis.nbins
+}
+
+// YAxis returns the axis along Y.
+func (h *H3D) YAxis() Axis {
+	return &h.th1.yaxis
+}
+
+// NbinsZ returns the number of bins in Z.
+func (h *H3D) NbinsZ() int {
+	return h.th1.zaxis.nbins
+}
+
+// ZAxis returns the axis along Z.
+func (h *H3D) ZAxis() Axis {
+	return &h.th1.zaxis
+}
+
+// BinContent returns the content of the (ix,iy,iz) bin.
+// Bin indices start at 1; 0 and Nbins+1 are the under- and overflow bins.
+func (h *H3D) BinContent(ix, iy, iz int) float64 {
+	return float64(h.arr.Data[h.bin(ix, iy, iz)])
+}
+
+// BinError returns the error of the (ix,iy,iz) bin.
+// Bin indices start at 1; 0 and Nbins+1 are the under- and overflow bins.
+func (h *H3D) BinError(ix, iy, iz int) float64 {
+	i := h.bin(ix, iy, iz)
+	if len(h.th1.sumw2.Data) > 0 {
+		return math.Sqrt(float64(h.th1.sumw2.Data[i]))
+	}
+	return math.Sqrt(math.Abs(float64(h.arr.Data[i])))
+}
+
+// bin returns the regularized bin number given an (x,y,z) bin index triplet.
+func (h *H3D) bin(ix, iy, iz int) int {
+	nx := h.th1.xaxis.nbins + 1 // overflow bin
+	ny := h.th1.yaxis.nbins + 1 // overflow bin
+	nz := h.th1.zaxis.nbins + 1 // overflow bin
+	switch {
+	case ix < 0:
+		ix = 0
+	case ix > nx:
+		ix = nx
+	}
+	switch {
+	case iy < 0:
+		iy = 0
+	case iy > ny:
+		iy = ny
+	}
+	switch {
+	case iz < 0:
+		iz = 0
+	case iz > nz:
+		iz = nz
+	}
+	return ix + (nx+1)*(iy+(ny+1)*iz)
+}
+
+func (h *H3D) setDist3D(ix, iy, iz int, sumw, sumw2 float64) {
+	i := h.bin(ix, iy, iz)
+	h.arr.Data[i] = float64(sumw)
+	h.th1.sumw2.Data[i] = sumw2
+}
+
+// dist3D returns the distribution of the ROOT bins within the
+// [ix0,ix1]x[iy0,iy1]x[iz0,iz1] (inclusive) bin indices ranges.
+func (h *H3D) dist3D(ix0, ix1, iy0, iy1, iz0, iz1 int) hbook.Dist3D {
+	var (
+		n     int64
+		sumw  float64
+		sumw2 float64
+	)
+	for ix := ix0; ix <= ix1; ix++ {
+		for iy := iy0; iy <= iy1; iy++ {
+			for iz := iz0; iz <= iz1; iz++ {
+				i := h.bin(ix, iy, iz)
+				v := float64(h.arr.Data[i])
+				n += h.entries(v, h.BinError(ix, iy, iz))
+				sumw += v
+				if len(h.th1.sumw2.Data) > 0 {
+					sumw2 += h.th1.sumw2.Data[i]
+				}
+			}
+		}
+	}
+	d := hbook.Dist1D{
+		Dist: hbook.Dist0D{
+			N:     n,
+			SumW:  sumw,
+			SumW2: sumw2,
+		},
+	}
+	return hbook.Dist3D{X: d, Y: d, Z: d}
+}
+
+func (h *H3D) entries(height, err float64) int64 {
+	if height <= 0 {
+		return 0
+	}
+	v := height / err
+	return int64(v*v + 0.5)
+}
+
+// AsH3D creates a new hbook.H3D from this ROOT histogram.
+func (h *H3D) AsH3D() *hbook.H3D {
+	var (
+		nx = h.NbinsX()
+		ny = h.NbinsY()
+		nz = h.NbinsZ()
+		hh = hbook.NewH3DFromEdges(
+			axisEdges(h.XAxis()),
+			axisEdges(h.YAxis()),
+			axisEdges(h.ZAxis()),
+		)
+	)
+	hh.Ann = hbook.Annotation{
+		"name":  h.Name(),
+		"title": h.Title(),
+	}
+
+	// ranges of ROOT bin indices for underflow, in-range and overflow bins.
+	rng := func(k, n int) (int, int) {
+		switch k {
+		case -1:
+			return 0, 0
+		case +1:
+			return n + 1, n + 1
+		default:
+			return 1, n
+		}
+	}
+	i := 0
+	for kx := -1; kx <= 1; kx++ {
+		for ky := -1; ky <= 1; ky++ {
+			for kz := -1; kz <= 1; kz++ {
+				if kx == 0 && ky == 0 && kz == 0 {
+					continue
+				}
+				ix0, ix1 := rng(kx, nx)
+				iy0, iy1 := rng(ky, ny)
+				iz0, iz1 := rng(kz, nz)
+				hh.Binning.Outflows[i] = h.dist3D(ix0, ix1, iy0, iy1, iz0, iz1)
+				i++
+			}
+		}
+	}
+
+	hh.Binning.Dist = hbook.Dist3D{
+		X: hbook.Dist1D{
+			Dist: hbook.Dist0D{
+				N:     int64(h.Entries()),
+				SumW:  float64(h.SumW()),
+				SumW2: float64(h.SumW2()),
+			},
+		},
+	}
+	hh.Binning.Dist.Y = hh.Binning.Dist.X
+	hh.Binning.Dist.Z = hh.Binning.Dist.X
+	hh.Binning.Dist.X.Stats.SumWX = float64(h.SumWX())
+	hh.Binning.Dist.X.Stats.SumWX2 = float64(h.SumWX2())
+	hh.Binning.Dist.Y.Stats.SumWX = float64(h.SumWY())
+	hh.Binning.Dist.Y.Stats.SumWX2 = float64(h.SumWY2())
+	hh.Binning.Dist.Z.Stats.SumWX = float64(h.SumWZ())
+	hh.Binning.Dist.Z.Stats.SumWX2 = float64(h.SumWZ2())
+	hh.Binning.Dist.Stats.SumWXY = h.SumWXY()
+	hh.Binning.Dist.Stats.SumWXZ = h.SumWXZ()
+	hh.Binning.Dist.Stats.SumWYZ = h.SumWYZ()
+
+	for ix := 0; ix < nx; ix++ {
+		for iy := 0; iy < ny; iy++ {
+			for iz := 0; iz < nz; iz++ {
+				i := (iz*ny+iy)*nx + ix
+				hh.Binning.Bins[i].Dist = h.dist3D(ix+1, ix+1, iy+1, iy+1, iz+1, iz+1)
+			}
+		}
+	}
+
+	return hh
+}
+
+// MarshalYODA implements the YODAMarshaler interface.
+func (h *H3D) MarshalYODA() ([]byte, error) {
+	return h.AsH3D().MarshalYODA()
+}
+
+func (h *H3D) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	hdr := w.WriteHeader(h.Class(), h.RVersion())
+	w.WriteObject(&h.th3)
+	w.WriteObject(&h.arr)
+
+	return w.SetHeader(hdr)
+}
+
+// ROOTMerge merges the content of src into h.
+// ROOTMerge returns an error if the axes of the histograms are not compatible.
+func (h *H3D) ROOTMerge(src root.Object) error {
+	hsrc, ok := src.(*H3D)
+	if !ok {
+		return fmt.Errorf("rhist: object %q is not a *rhist.H3D (%T)", src.(root.Named).Name(), src)
+	}
+
+	err := h.th3.merge(
+		&hsrc.th3,
+		func(i int) float64 { return float64(h.arr.Data[i]) },
+		func(i int) float64 { return float64(hsrc.arr.Data[i]) },
+	)
+	if err != nil {
+		return fmt.Errorf("rhist: could not merge %q: %w", h.Name(), err)
+	}
+
+	for i, v := range hsrc.arr.Data {
+		h.arr.Data[i] += v
+	}
+	return nil
+}
+
+func (h *H3D) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	hdr := r.ReadHeader(h.Class())
+	if hdr.Vers > rvers.H3D {
+		panic(fmt.Errorf("rhist: invalid H3D version=%d > %d", hdr.Vers, rvers.H3D))
+	}
+	if hdr.Vers < 1 {
+		return fmt.Errorf("rhist: TH3D version too old (%d<1)", hdr.Vers)
+	}
+
+	r.ReadObject(&h.th3)
+	r.ReadObject(&h.arr)
+
+	r.CheckHeader(hdr)
+	return r.Err()
+}
+
+func init() {
+	f := func() reflect.Value {
+		o := newH3D()
+		return reflect.ValueOf(o)
+	}
+	rtypes.Factory.Add("TH3D", f)
+}
+
+var (
+	_ root.Object        = (*H3D)(nil)
+	_ root.Merger        = (*H3D)(nil)
+	_ root.Named         = (*H3D)(nil)
+	_ H3                 = (*H3D)(nil)
+	_ rbytes.Marshaler   = (*H3D)(nil)
+	_ rbytes.Unmarshaler = (*H3D)(nil)
+)
+
+// H3I implements ROOT TH3I
+type H3I struct {
+	th3
+	arr rcont.ArrayI
+}
+
+func newH3I() *H3I {
+	return &H3I{
+		th3: *newH3(),
+	}
+}
+
+// NewH3IFrom creates a new H3I from hbook 3-dim histogram.
+func NewH3IFrom(h *hbook.H3D) *H3I {
+	var (
+		hroot  = newH3I()
+		bins   = h.Binning.Bins
+		nxbins = h.Binning.Nx
+		nybins = h.Binning.Ny
+		nzbins = h.Binning.Nz
+		xedges = make([]float64, 0, nxbins+1)
+		yedges = make([]float64, 0, nybins+1)
+		zedges = make([]float64, 0, nzbins+1)
+	)
+
+	hroot.th3.th1.entries = float64(h.Entries())
+	hroot.th3.th1.tsumw = h.SumW()
+	hroot.th3.th1.tsumw2 = h.SumW2()
+	hroot.th3.th1.tsumwx = h.SumWX()
+	hroot.th3.th1.tsumwx2 = h.SumWX2()
+	hroot.th3.tsumwy = h.SumWY()
+	hroot.th3.tsumwy2 = h.SumWY2()
+	hroot.th3.tsumwxy = h.SumWXY()
+	hroot.th3.tsumwz = h.SumWZ()
+	hroot.th3.tsumwz2 = h.SumWZ2()
+	hroot.th3.tsumwxz = h.SumWXZ()
+	hroot.th3.tsumwyz = h.SumWYZ()
+
+	ncells := (nxbins + 2) * (nybins + 2) * (nzbins + 2)
+	hroot.th3.th1.ncells = ncells
+
+	hroot.th3.th1.xaxis.nbins = nxbins
+	hroot.th3.th1.xaxis.xmin = h.XMin()
+	hroot.th3.th1.xaxis.xmax = h.XMax()
+
+	hroot.th3.th1.yaxis.nbins = nybins
+	hroot.th3.th1.yaxis.xmin = h.YMin()
+	hroot.th3.th1.yaxis.xmax = h.YMax()
+
+	hroot.th3.th1.zaxis.nbins = nzbins
+	hroot.th3.th1.zaxis.xmin = h.ZMin()
+	hroot.th3.th1.zaxis.xmax = h.ZMax()
+
+	hroot.arr.Data = make([]int32, ncells)
+	hroot.th3.th1.sumw2.Data = make([]float64, ncells)
+
+	ibin := func(ix, iy, iz int) int { return (iz*nybins+iy)*nxbins + ix }
+
+	for ix := 0; ix < nxbins; ix++ {
+		for iy := 0; iy < nybins; iy++ {
+			for iz := 0; iz < nzbins; iz++ {
+				bin := bins[ibin(ix, iy, iz)]
+				if iy == 0 && iz == 0 {
+					xedges = append(xedges, bin.XMin())
+				}
+				if ix == 0 && iz == 0 {
+					yedges = append(yedges, bin.YMin())
+				}
+				if ix == 0 && iy == 0 {
+					zedges = append(zedges, bin.ZMin())
+				}
+				hroot.setDist3D(ix+1, iy+1, iz+1, bin.Dist.SumW(), bin.Dist.SumW2())
+			}
+		}
+	}
+
+	// outflows are stored in the first ROOT bin of their region.
+	cell := func(k, n int) int {
+		switch k {
+		case -1:
+			return 0
+		case +1:
+			return n + 1
+		default:
+			return 1
+		}
+	}
+	i := 0
+	for kx := -1; kx <= 1; kx++ {
+		for ky := -1; ky <= 1; ky++ {
+			for kz := -1; kz <= 1; kz++ {
+				if kx == 0 && ky == 0 && kz == 0 {
+					continue
+				}
+				oflow := &h.Binning.Outflows[i]
+				hroot.setDist3D(
+					cell(kx, nxbins), cell(ky, nybins), cell(kz, nzbins),
+					oflow.SumW(), oflow.SumW2(),
+				)
+				i++
+			}
+		}
+	}
+
+	xedges = append(xedges, bins[ibin(nxbins-1, 0, 0)].XMax())
+	yedges = append(yedges, bins[ibin(0, nybins-1, 0)].YMax())
+	zedges = append(zedges, bins[ibin(0, 0, nzbins-1)].ZMax())
+
+	hroot.th3.th1.SetName(h.Name())
+	if v, ok := h.Annotation()["title"]; ok && v != nil {
+		hroot.th3.th1.SetTitle(v.(string))
+	}
+	hroot.th3.th1.xaxis.xbins.Data = xedges
+	hroot.th3.th1.yaxis.xbins.Data = yedges
+	hroot.th3.th1.zaxis.xbins.Data = zedges
+
+	return hroot
+}
+
+func (*H3I) RVersion() int16 {
+	return rvers.H3I
+}
+
+func (*H3I) isH3() {}
+
+// Class returns the ROOT class name.
+func (*H3I) Class() string {
+	return "TH3I"
+}
+
+func (h *H3I) Array() rcont.ArrayI {
+	return h.arr
+}
+
+// Rank returns the number of dimensions of this histogram.
+func (h *H3I) Rank() int {
+	return 3
+}
+
+// NbinsX returns the number of bins in X.
+func (h *H3I) NbinsX() int {
+	return h.th1.xaxis.nbins
+}
+
+// XAxis returns the axis along X.
+func (h *H3I) XAxis() Axis {
+	return &h.th1.xaxis
+}
+
+// NbinsY returns the number of bins in Y.
+func (h *H3I) NbinsY() int {
+	return h.th1.yaxis.nbins
+}
+
+// YAxis returns the axis along Y.
+func (h *H3I) YAxis() Axis {
+	return &h.th1.yaxis
+}
+
+// NbinsZ returns the number of bins in Z.
+func (h *H3I) NbinsZ() int {
+	return h.th1.zaxis.nbins
+}
+
+// ZAxis returns the axis along Z.
+func (h *H3I) ZAxis() Axis {
+	return &h.th1.zaxis
+}
+
+// BinContent returns the content of the (ix,iy,iz) bin.
+// Bin indices start at 1; 0 and Nbins+1 are the under- and overflow bins.
+func (h *H3I) BinContent(ix, iy, iz int) float64 {
+	return float64(h.arr.Data[h.bin(ix, iy, iz)])
+}
+
+// BinError returns the error of the (ix,iy,iz) bin.
+// Bin indices start at 1; 0 and Nbins+1 are the under- and overflow bins.
+func (h *H3I) BinError(ix, iy, iz int) float64 {
+	i := h.bin(ix, iy, iz)
+	if len(h.th1.sumw2.Data) > 0 {
+		return math.Sqrt(float64(h.th1.sumw2.Data[i]))
+	}
+	return math.Sqrt(math.Abs(float64(h.arr.Data[i])))
+}
+
+// bin returns the regularized bin number given an (x,y,z) bin index triplet.
+func (h *H3I) bin(ix, iy, iz int) int {
+	nx := h.th1.xaxis.nbins + 1 // overflow bin
+	ny := h.th1.yaxis.nbins + 1 // overflow bin
+	nz := h.th1.zaxis.nbins + 1 // overflow bin
+	switch {
+	case ix < 0:
+		ix = 0
+	case ix > nx:
+		ix = nx
+	}
+	switch {
+	case iy < 0:
+		iy = 0
+	case iy > ny:
+		iy = ny
+	}
+	switch {
+	case iz < 0:
+		iz = 0
+	case iz > nz:
+		iz = nz
+	}
+	return ix + (nx+1)*(iy+(ny+1)*iz)
+}
+
+func (h *H3I) setDist3D(ix, iy, iz int, sumw, sumw2 float64) {
+	i := h.bin(ix, iy, iz)
+	h.arr.Data[i] = int32(sumw)
+	h.th1.sumw2.Data[i] = sumw2
+}
+
+// dist3D returns the distribution of the ROOT bins within the
+// [ix0,ix1]x[iy0,iy1]x[iz0,iz1] (inclusive) bin indices ranges.
+func (h *H3I) dist3D(ix0, ix1, iy0, iy1, iz0, iz1 int) hbook.Dist3D {
+	var (
+		n     int64
+		sumw  float64
+		sumw2 float64
+	)
+	for ix := ix0; ix <= ix1; ix++ {
+		for iy := iy0; iy <= iy1; iy++ {
+			for iz := iz0; iz <= iz1; iz++ {
+				i := h.bin(ix, iy, iz)
+				v := float64(h.arr.Data[i])
+				n += h.entries(v, h.BinError(ix, iy, iz))
+				sumw += v
+				if len(h.th1.sumw2.Data) > 0 {
+					sumw2 += h.th1.sumw2.Data[i]
+				}
+			}
+		}
+	}
+	d := hbook.Dist1D{
+		Dist: hbook.Dist0D{
+			N:     n,
+			SumW:  sumw,
+			SumW2: sumw2,
+		},
+	}
+	return hbook.Dist3D{X: d, Y: d, Z: d}
+}
+
+func (h *H3I) entries(height, err float64) int64 {
+	if height <= 0 {
+		return 0
+	}
+	v := height / err
+	return int64(v*v + 0.5)
+}
+
+// AsH3D creates a new hbook.H3D from this ROOT histogram.
+func (h *H3I) AsH3D() *hbook.H3D {
+	var (
+		nx = h.NbinsX()
+		ny = h.NbinsY()
+		nz = h.NbinsZ()
+		hh = hbook.NewH3DFromEdges(
+			axisEdges(h.XAxis()),
+			axisEdges(h.YAxis()),
+			axisEdges(h.ZAxis()),
+		)
+	)
+	hh.Ann = hbook.Annotation{
+		"name":  h.Name(),
+		"title": h.Title(),
+	}
+
+	// ranges of ROOT bin indices for underflow, in-range and overflow bins.
+	rng := func(k, n int) (int, int) {
+		switch k {
+		case -1:
+			return 0, 0
+		case +1:
+			return n + 1, n + 1
+		default:
+			return 1, n
+		}
+	}
+	i := 0
+	for kx := -1; kx <= 1; kx++ {
+		for ky := -1; ky <= 1; ky++ {
+			for kz := -1; kz <= 1; kz++ {
+				if kx == 0 && ky == 0 && kz == 0 {
+					continue
+				}
+				ix0, ix1 := rng(kx, nx)
+				iy0, iy1 := rng(ky, ny)
+				iz0, iz1 := rng(kz, nz)
+				hh.Binning.Outflows[i] = h.dist3D(ix0, ix1, iy0, iy1, iz0, iz1)
+				i++
+			}
+		}
+	}
+
+	hh.Binning.Dist = hbook.Dist3D{
+		X: hbook.Dist1D{
+			Dist: hbook.Dist0D{
+				N:     int64(h.Entries()),
+				SumW:  float64(h.SumW()),
+				SumW2: float64(h.SumW2()),
+			},
+		},
+	}
+	hh.Binning.Dist.Y = hh.Binning.Dist.X
+	hh.Binning.Dist.Z = hh.Binning.Dist.X
+	hh.Binning.Dist.X.Stats.SumWX = float64(h.SumWX())
+	hh.Binning.Dist.X.Stats.SumWX2 = float64(h.SumWX2())
+	hh.Binning.Dist.Y.Stats.SumWX = float64(h.SumWY())
+	hh.Binning.Dist.Y.Stats.SumWX2 = float64(h.SumWY2())
+	hh.Binning.Dist.Z.Stats.SumWX = float64(h.SumWZ())
+	hh.Binning.Dist.Z.Stats.SumWX2 = float64(h.SumWZ2())
+	hh.Binning.Dist.Stats.SumWXY = h.SumWXY()
+	hh.Binning.Dist.Stats.SumWXZ = h.SumWXZ()
+	hh.Binning.Dist.Stats.SumWYZ = h.SumWYZ()
+
+	for ix := 0; ix < nx; ix++ {
+		for iy := 0; iy < ny; iy++ {
+			for iz := 0; iz < nz; iz++ {
+				i := (iz*ny+iy)*nx + ix
+				hh.Binning.Bins[i].Dist = h.dist3D(ix+1, ix+1, iy+1, iy+1, iz+1, iz+1)
+			}
+		}
+	}
+
+	return hh
+}
+
+// MarshalYODA implements the YODAMarshaler interface.
+func (h *H3I) MarshalYODA() ([]byte, error) {
+	return h.AsH3D().MarshalYODA()
+}
+
+func (h *H3I) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	hdr := w.WriteHeader(h.Class(), h.RVersion())
+	w.WriteObject(&h.th3)
+	w.WriteObject(&h.arr)
+
+	return w.SetHeader(hdr)
+}
+
+// ROOTMerge merges the content of src into h.
+// ROOTMerge returns an error if the axes of the histograms are not compatible.
+func (h *H3I) ROOTMerge(src root.Object) error {
+	hsrc, ok := src.(*H3I)
+	if !ok {
+		return fmt.Errorf("rhist: object %q is not a *rhist.H3I (%T)", src.(root.Named).Name(), src)
+	}
+
+	err := h.th3.merge(
+		&hsrc.th3,
+		func(i int) float64 { return float64(h.arr.Data[i]) },
+		func(i int) float64 { return float64(hsrc.arr.Data[i]) },
+	)
+	if err != nil {
+		return fmt.Errorf("rhist: could not merge %q: %w", h.Name(), err)
+	}
+
+	for i, v := range hsrc.arr.Data {
+		h.arr.Data[i] += v
+	}
+	return nil
+}
+
+func (h *H3I) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	hdr := r.ReadHeader(h.Class())
+	if hdr.Vers > rvers.H3I {
+		panic(fmt.Errorf("rhist: invalid H3I version=%d > %d", hdr.Vers, rvers.H3I))
+	}
+	if hdr.Vers < 1 {
+		return fmt.Errorf("rhist: TH3I version too old (%d<1)", hdr.Vers)
+	}
+
+	r.ReadObject(&h.th3)
+	r.ReadObject(&h.arr)
+
+	r.CheckHeader(hdr)
+	return r.Err()
+}
+
+func init() {
+	f := func() reflect.Value {
+		o := newH3I()
+		return reflect.ValueOf(o)
+	}
+	rtypes.Factory.Add("TH3I", f)
+}
+
+var (
+	_ root.Object        = (*H3I)(nil)
+	_ root.Merger        = (*H3I)(nil)
+	_ root.Named         = (*H3I)(nil)
+	_ H3                 = (*H3I)(nil)
+	_ rbytes.Marshaler   = (*H3I)(nil)
+	_ rbytes.Unmarshaler = (*H3I)(nil)
+)
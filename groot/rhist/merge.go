@@ -0,0 +1,149 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rhist
+
+import (
+	"fmt"
+
+	"go-hep.org/x/hep/groot/rcont"
+)
+
+// checkAxes returns an error if the provided axes do not have the same
+// number of bins and the same bin edges.
+func checkAxes(name string, dst, src *taxis) error {
+	if dst.nbins != src.nbins || dst.xmin != src.xmin || dst.xmax != src.xmax {
+		return fmt.Errorf(
+			"rhist: incompatible %s-axes (nbins=%d, range=[%v, %v]) and (nbins=%d, range=[%v, %v])",
+			name,
+			dst.nbins, dst.xmin, dst.xmax,
+			src.nbins, src.xmin, src.xmax,
+		)
+	}
+
+	var (
+		edst = axisEdges(dst)
+		esrc = axisEdges(src)
+	)
+	for i := range edst {
+		if edst[i] != esrc[i] {
+			return fmt.Errorf(
+				"rhist: incompatible %s-axes (bin edge %d: %v != %v)",
+				name, i, edst[i], esrc[i],
+			)
+		}
+	}
+	return nil
+}
+
+// merge adds the statistics and the sums of squares of weights of src to h.
+// The bin contents of h and src, used when only one of the histograms
+// stores the sums of squares of weights, are provided by hc and sc.
+func (h *th1) merge(src *th1, hc, sc func(i int) float64) error {
+	for _, axis := range []struct {
+		name     string
+		dst, src *taxis
+	}{
+		{"x", &h.xaxis, &src.xaxis},
+		{"y", &h.yaxis, &src.yaxis},
+		{"z", &h.zaxis, &src.zaxis},
+	} {
+		err := checkAxes(axis.name, axis.dst, axis.src)
+		if err != nil {
+			return err
+		}
+	}
+	if h.ncells != src.ncells {
+		return fmt.Errorf("rhist: incompatible number of cells (%d != %d)", h.ncells, src.ncells)
+	}
+
+	switch {
+	case len(h.sumw2.Data) == 0 && len(src.sumw2.Data) == 0:
+		// no sums of squares of weights to merge.
+	default:
+		if len(h.sumw2.Data) == 0 {
+			h.sumw2.Data = make([]float64, h.ncells)
+			for i := range h.sumw2.Data {
+				h.sumw2.Data[i] = hc(i)
+			}
+		}
+		for i := range h.sumw2.Data {
+			switch {
+			case len(src.sumw2.Data) == 0:
+				h.sumw2.Data[i] += sc(i)
+			default:
+				h.sumw2.Data[i] += src.sumw2.Data[i]
+			}
+		}
+	}
+
+	h.entries += src.entries
+	h.tsumw += src.tsumw
+	h.tsumw2 += src.tsumw2
+	h.tsumwx += src.tsumwx
+	h.tsumwx2 += src.tsumwx2
+
+	return nil
+}
+
+// merge adds the statistics and the sums of squares of weights of src to h.
+func (h *th2) merge(src *th2, hc, sc func(i int) float64) error {
+	err := h.th1.merge(&src.th1, hc, sc)
+	if err != nil {
+		return err
+	}
+
+	h.tsumwy += src.tsumwy
+	h.tsumwy2 += src.tsumwy2
+	h.tsumwxy += src.tsumwxy
+
+	return nil
+}
+
+// merge adds the statistics and the sums of squares of weights of src to h.
+func (h *th3) merge(src *th3, hc, sc func(i int) float64) error {
+	err := h.th1.merge(&src.th1, hc, sc)
+	if err != nil {
+		return err
+	}
+
+	h.tsumwy += src.tsumwy
+	h.tsumwy2 += src.tsumwy2
+	h.tsumwxy += src.tsumwxy
+	h.tsumwz += src.tsumwz
+	h.tsumwz2 += src.tsumwz2
+	h.tsumwxz += src.tsumwxz
+	h.tsumwyz += src.tsumwyz
+
+	return nil
+}
+
+// mergeBinEntries adds the number of entries and the sums of squares of
+// weights per bin of a profile histogram (sent, ssw2) to the ones of
+// another profile histogram (dent, dsw2).
+// Missing sums of squares of weights are computed from the number of
+// entries, as for unweighted entries.
+func mergeBinEntries(dent, dsw2, sent, ssw2 *rcont.ArrayD) {
+	switch {
+	case len(dsw2.Data) == 0 && len(ssw2.Data) == 0:
+		// no sums of squares of weights to merge.
+	default:
+		if len(dsw2.Data) == 0 {
+			dsw2.Data = make([]float64, len(dent.Data))
+			copy(dsw2.Data, dent.Data)
+		}
+		for i := range dsw2.Data {
+			switch {
+			case len(ssw2.Data) == 0:
+				dsw2.Data[i] += sent.Data[i]
+			default:
+				dsw2.Data[i] += ssw2.Data[i]
+			}
+		}
+	}
+
+	for i, v := range sent.Data {
+		dent.Data[i] += v
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rhist
+
+import (
+	"strings"
+	"testing"
+
+	"go-hep.org/x/hep/groot/riofs"
+	"go-hep.org/x/hep/groot/root"
+	"go-hep.org/x/hep/hbook"
+)
+
+func TestMergeH2(t *testing.T) {
+	newH2D := func(n int) *hbook.H2D {
+		h := hbook.NewH2D(10, 0, 10, 5, 0, 5)
+		for i := 0; i < n; i++ {
+			h.Fill(1, 1, 1)
+			h.Fill(2, 3, 2)
+			h.Fill(-1, 7, 3)
+			h.Fill(11, -1, 4)
+		}
+		return h
+	}
+
+	for _, tc := range []struct {
+		name string
+		dst  root.Merger
+		src  root.Object
+		want *hbook.H2D
+		as   func(o root.Merger) *hbook.H2D
+	}{
+		{
+			name: "H2F",
+			dst:  NewH2FFrom(newH2D(1)),
+			src:  NewH2FFrom(newH2D(2)),
+			want: newH2D(3),
+			as:   func(o root.Merger) *hbook.H2D { return o.(*H2F).AsH2D() },
+		},
+		{
+			name: "H2D",
+			dst:  NewH2DFrom(newH2D(1)),
+			src:  NewH2DFrom(newH2D(2)),
+			want: newH2D(3),
+			as:   func(o root.Merger) *hbook.H2D { return o.(*H2D).AsH2D() },
+		},
+		{
+			name: "H2I",
+			dst:  NewH2IFrom(newH2D(1)),
+			src:  NewH2IFrom(newH2D(2)),
+			want: newH2D(3),
+			as:   func(o root.Merger) *hbook.H2D { return o.(*H2I).AsH2D() },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.dst.ROOTMerge(tc.src)
+			if err != nil {
+				t.Fatalf("could not merge: %+v", err)
+			}
+
+			got := tc.as(tc.dst)
+			if got, want := got.Entries(), tc.want.Entries(); got != want {
+				t.Fatalf("invalid entries: got=%d, want=%d", got, want)
+			}
+			if got, want := got.SumW(), tc.want.SumW(); got != want {
+				t.Fatalf("invalid sumw: got=%v, want=%v", got, want)
+			}
+			if got, want := got.SumWXY(), tc.want.SumWXY(); got != want {
+				t.Fatalf("invalid sumwxy: got=%v, want=%v", got, want)
+			}
+			for i := range got.Binning.Bins {
+				var (
+					g = got.Binning.Bins[i].SumW()
+					w = tc.want.Binning.Bins[i].SumW()
+				)
+				if g != w {
+					t.Fatalf("invalid bin %d: got=%v, want=%v", i, g, w)
+				}
+			}
+			for i := range got.Binning.Outflows {
+				// ROOT does not store the per-bin statistics.
+				var (
+					g = got.Binning.Outflows[i].X.Dist
+					w = tc.want.Binning.Outflows[i].X.Dist
+				)
+				if g != w {
+					t.Fatalf("invalid outflow %d:\ngot= %+v\nwant=%+v", i, g, w)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeProfiles(t *testing.T) {
+	load := func(name string) root.Object {
+		// open a new file to get a new instance of the object.
+		f, err := riofs.Open("../testdata/tprofile.root")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		obj, err := riofs.Dir(f).Get(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return obj
+	}
+
+	t.Run("p1d", func(t *testing.T) {
+		var (
+			dst = load("p1d").(*Profile1D)
+			src = load("p1d").(*Profile1D)
+			ref = load("p1d").(*Profile1D)
+		)
+		err := dst.ROOTMerge(src)
+		if err != nil {
+			t.Fatalf("could not merge: %+v", err)
+		}
+
+		if got, want := dst.h1d.Entries(), 2*ref.h1d.Entries(); got != want {
+			t.Fatalf("invalid entries: got=%v, want=%v", got, want)
+		}
+		if got, want := dst.sumwy, 2*ref.sumwy; got != want {
+			t.Fatalf("invalid sumwy: got=%v, want=%v", got, want)
+		}
+		for i, v := range ref.binEntries.Data {
+			if got, want := dst.binEntries.Data[i], 2*v; got != want {
+				t.Fatalf("invalid bin-entries[%d]: got=%v, want=%v", i, got, want)
+			}
+			if got, want := dst.h1d.arr.Data[i], 2*ref.h1d.arr.Data[i]; got != want {
+				t.Fatalf("invalid bin-content[%d]: got=%v, want=%v", i, got, want)
+			}
+		}
+	})
+
+	t.Run("p2d", func(t *testing.T) {
+		var (
+			dst = load("p2d").(*Profile2D)
+			src = load("p2d").(*Profile2D)
+			ref = load("p2d").(*Profile2D)
+		)
+		err := dst.ROOTMerge(src)
+		if err != nil {
+			t.Fatalf("could not merge: %+v", err)
+		}
+
+		var (
+			got  = dst.AsP2D().Binning().Dist()
+			want = ref.AsP2D().Binning().Dist()
+		)
+		if got, want := got.SumW(), 2*want.SumW(); got != want {
+			t.Fatalf("invalid sumw: got=%v, want=%v", got, want)
+		}
+		if got, want := dst.sumwz, 2*ref.sumwz; got != want {
+			t.Fatalf("invalid sumwz: got=%v, want=%v", got, want)
+		}
+	})
+
+	t.Run("incompatible", func(t *testing.T) {
+		var (
+			dst = load("p2d").(*Profile2D)
+			src = NewProfile2DFrom(hbook.NewP2D(3, 0, 1, 3, 0, 1))
+		)
+		err := dst.ROOTMerge(src)
+		if err == nil {
+			t.Fatalf("expected an error")
+		}
+		if got, want := err.Error(), "incompatible x-axes"; !strings.Contains(got, want) {
+			t.Fatalf("invalid error: got=%q, want=%q", got, want)
+		}
+
+		err = dst.ROOTMerge(load("p1d"))
+		if err == nil {
+			t.Fatalf("expected an error")
+		}
+	})
+}
+
+func TestMergeEfficiency(t *testing.T) {
+	load := func() *Efficiency {
+		// open a new file to get a new instance of the object.
+		f, err := riofs.Open("../testdata/tconfidence-level.root")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		obj, err := riofs.Dir(f).Get("eff")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return obj.(*Efficiency)
+	}
+
+	var (
+		dst = load()
+		src = load()
+		ref = load()
+	)
+	err := dst.ROOTMerge(src)
+	if err != nil {
+		t.Fatalf("could not merge: %+v", err)
+	}
+
+	if got, want := dst.passedHist.Entries(), 2*ref.passedHist.Entries(); got != want {
+		t.Fatalf("invalid passed entries: got=%v, want=%v", got, want)
+	}
+	if got, want := dst.totHist.Entries(), 2*ref.totHist.Entries(); got != want {
+		t.Fatalf("invalid total entries: got=%v, want=%v", got, want)
+	}
+
+	src = load()
+	src.weight = 2
+	err = dst.ROOTMerge(src)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if got, want := err.Error(), `rhist: can not merge efficiency "eff" with different weights (1 != 2)`; got != want {
+		t.Fatalf("invalid error: got=%q, want=%q", got, want)
+	}
+}
+
+func TestMergeIncompatibleH1(t *testing.T) {
+	var (
+		h1 = NewH1DFrom(hbook.NewH1D(10, 0, 10))
+		h2 = NewH1DFrom(hbook.NewH1D(10, 0, 20))
+		h3 = NewH1DFrom(hbook.NewH1D(5, 0, 10))
+	)
+
+	for _, tc := range []struct {
+		src  root.Object
+		want string
+	}{
+		{
+			src:  h2,
+			want: `rhist: could not merge "": rhist: incompatible x-axes (nbins=10, range=[0, 10]) and (nbins=10, range=[0, 20])`,
+		},
+		{
+			src:  h3,
+			want: `rhist: could not merge "": rhist: incompatible x-axes (nbins=10, range=[0, 10]) and (nbins=5, range=[0, 10])`,
+		},
+		{
+			src:  NewH1FFrom(hbook.NewH1D(10, 0, 10)),
+			want: `rhist: object "" is not a *rhist.H1D (*rhist.H1F)`,
+		},
+	} {
+		err := h1.ROOTMerge(tc.src)
+		if err == nil {
+			t.Fatalf("expected an error")
+		}
+		if got, want := err.Error(), tc.want; got != want {
+			t.Fatalf("invalid error:\ngot= %q\nwant=%q", got, want)
+		}
+	}
+}
@@ -89,6 +89,25 @@ func (p *Profile1D) UnmarshalROOT(r *rbytes.RBuffer) error {
 	return r.Err()
 }
 
+// ROOTMerge merges the content of src into p.
+// ROOTMerge returns an error if the axes of the profiles are not compatible.
+func (p *Profile1D) ROOTMerge(src root.Object) error {
+	psrc, ok := src.(*Profile1D)
+	if !ok {
+		return fmt.Errorf("rhist: can not merge %T into %T", src, p)
+	}
+
+	err := p.h1d.ROOTMerge(&psrc.h1d)
+	if err != nil {
+		return err
+	}
+	mergeBinEntries(&p.binEntries, &p.binSumw2, &psrc.binEntries, &psrc.binSumw2)
+
+	p.sumwy += psrc.sumwy
+	p.sumwy2 += psrc.sumwy2
+	return nil
+}
+
 func init() {
 	f := func() reflect.Value {
 		p1d := newProfile1D()
@@ -99,6 +118,7 @@ func init() {
 
 var (
 	_ root.Object        = (*Profile1D)(nil)
+	_ root.Merger        = (*Profile1D)(nil)
 	_ rbytes.RVersioner  = (*Profile1D)(nil)
 	_ rbytes.Marshaler   = (*Profile1D)(nil)
 	_ rbytes.Unmarshaler = (*Profile1D)(nil)
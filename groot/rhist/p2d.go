@@ -13,6 +13,7 @@ import (
 	"go-hep.org/x/hep/groot/root"
 	"go-hep.org/x/hep/groot/rtypes"
 	"go-hep.org/x/hep/groot/rvers"
+	"go-hep.org/x/hep/hbook"
 )
 
 // Profile2D is a 2-dim profile histogram.
@@ -33,6 +34,191 @@ func newProfile2D() *Profile2D {
 	}
 }
 
+// NewProfile2DFrom creates a new 2-dim profile histogram from hbook.
+func NewProfile2DFrom(p *hbook.P2D) *Profile2D {
+	var (
+		bng = p.Binning()
+		nx  = bng.Nx()
+		ny  = bng.Ny()
+		p2d = &Profile2D{
+			h2d: *NewH2DFrom(hbook.NewH2D(nx, p.XMin(), p.XMax(), ny, p.YMin(), p.YMax())),
+		}
+		h2 = &p2d.h2d
+	)
+
+	ncells := len(h2.arr.Data)
+	p2d.binEntries.Data = make([]float64, ncells)
+	p2d.binSumw2.Data = make([]float64, ncells)
+
+	set := func(ix, iy int, d *hbook.Dist3D) {
+		i := h2.bin(ix, iy)
+		h2.arr.Data[i] = d.SumWZ()
+		h2.th1.sumw2.Data[i] = d.SumWZ2()
+		p2d.binEntries.Data[i] = d.SumW()
+		p2d.binSumw2.Data[i] = d.SumW2()
+	}
+
+	bins := bng.Bins()
+	for ix := 0; ix < nx; ix++ {
+		for iy := 0; iy < ny; iy++ {
+			set(ix+1, iy+1, bins[iy*nx+ix].Dist())
+		}
+	}
+
+	oflows := bng.Outflows()
+	for i, v := range outflowsP2D(nx, ny) {
+		set(v[0], v[1], &oflows[i])
+	}
+
+	d := bng.Dist()
+	h2.th1.entries = float64(d.Entries())
+	h2.th1.tsumw = d.SumW()
+	h2.th1.tsumw2 = d.SumW2()
+	h2.th1.tsumwx = d.SumWX()
+	h2.th1.tsumwx2 = d.SumWX2()
+	h2.th2.tsumwy = d.SumWY()
+	h2.th2.tsumwy2 = d.SumWY2()
+	h2.th2.tsumwxy = d.SumWXY()
+	p2d.sumwz = d.SumWZ()
+	p2d.sumwz2 = d.SumWZ2()
+
+	h2.th1.SetName(p.Name())
+	if v, ok := p.Annotation()["title"]; ok && v != nil {
+		h2.th1.SetTitle(v.(string))
+	}
+
+	return p2d
+}
+
+// outflowsP2D returns the (x,y) indices of the ROOT bins holding the
+// outflows of a hbook.P2D, in the BngNW, ..., BngW order.
+func outflowsP2D(nx, ny int) [8][2]int {
+	return [8][2]int{
+		{0, ny + 1},      // NW
+		{1, ny + 1},      // N
+		{nx + 1, ny + 1}, // NE
+		{nx + 1, 1},      // E
+		{nx + 1, 0},      // SE
+		{1, 0},           // S
+		{0, 0},           // SW
+		{0, 1},           // W
+	}
+}
+
+// Name returns the name of this profile histogram.
+func (p2d *Profile2D) Name() string {
+	return p2d.h2d.Name()
+}
+
+// Title returns the title of this profile histogram.
+func (p2d *Profile2D) Title() string {
+	return p2d.h2d.Title()
+}
+
+// AsP2D creates a new hbook.P2D from this ROOT profile histogram.
+//
+// Only the range and number of bins of the X and Y axes are considered:
+// variable-size bins are not supported.
+func (p2d *Profile2D) AsP2D() *hbook.P2D {
+	var (
+		h  = &p2d.h2d
+		nx = h.NbinsX()
+		ny = h.NbinsY()
+		p  = hbook.NewP2D(
+			nx, h.XAxis().XMin(), h.XAxis().XMax(),
+			ny, h.YAxis().XMin(), h.YAxis().XMax(),
+		)
+		bng = p.Binning()
+	)
+	p.Annotation()["name"] = p2d.Name()
+	p.Annotation()["title"] = p2d.Title()
+
+	bins := bng.Bins()
+	for ix := 0; ix < nx; ix++ {
+		for iy := 0; iy < ny; iy++ {
+			*bins[iy*nx+ix].Dist() = p2d.dist3D(ix+1, ix+1, iy+1, iy+1)
+		}
+	}
+
+	// outflows regions, in the BngNW, ..., BngW order.
+	oflows := bng.Outflows()
+	for i, v := range [8][4]int{
+		{0, 0, ny + 1, ny + 1},           // NW
+		{1, nx, ny + 1, ny + 1},          // N
+		{nx + 1, nx + 1, ny + 1, ny + 1}, // NE
+		{nx + 1, nx + 1, 1, ny},          // E
+		{nx + 1, nx + 1, 0, 0},           // SE
+		{1, nx, 0, 0},                    // S
+		{0, 0, 0, 0},                     // SW
+		{0, 0, 1, ny},                    // W
+	} {
+		oflows[i] = p2d.dist3D(v[0], v[1], v[2], v[3])
+	}
+
+	d := bng.Dist()
+	d.X.Dist = hbook.Dist0D{
+		N:     int64(h.Entries()),
+		SumW:  h.SumW(),
+		SumW2: h.SumW2(),
+	}
+	d.Y.Dist = d.X.Dist
+	d.Z.Dist = d.X.Dist
+	d.X.Stats.SumWX = h.SumWX()
+	d.X.Stats.SumWX2 = h.SumWX2()
+	d.Y.Stats.SumWX = h.SumWY()
+	d.Y.Stats.SumWX2 = h.SumWY2()
+	d.Z.Stats.SumWX = p2d.sumwz
+	d.Z.Stats.SumWX2 = p2d.sumwz2
+	d.Stats.SumWXY = h.SumWXY()
+
+	return p
+}
+
+// dist3D returns the distribution of the ROOT bins within the
+// [ix0,ix1]x[iy0,iy1] (inclusive) bin indices ranges.
+// The number of entries of each bin is estimated from its effective
+// number of entries.
+func (p2d *Profile2D) dist3D(ix0, ix1, iy0, iy1 int) hbook.Dist3D {
+	var (
+		h = &p2d.h2d
+		n int64
+
+		sumw   float64
+		sumw2  float64
+		sumwz  float64
+		sumwz2 float64
+	)
+	for ix := ix0; ix <= ix1; ix++ {
+		for iy := iy0; iy <= iy1; iy++ {
+			i := h.bin(ix, iy)
+			w := p2d.binEntries.Data[i]
+			w2 := w
+			if len(p2d.binSumw2.Data) > 0 {
+				w2 = p2d.binSumw2.Data[i]
+			}
+			if w2 > 0 {
+				n += int64(w*w/w2 + 0.5)
+			}
+			sumw += w
+			sumw2 += w2
+			sumwz += h.arr.Data[i]
+			if len(h.th1.sumw2.Data) > 0 {
+				sumwz2 += h.th1.sumw2.Data[i]
+			}
+		}
+	}
+
+	d0 := hbook.Dist0D{N: n, SumW: sumw, SumW2: sumw2}
+	d := hbook.Dist3D{
+		X: hbook.Dist1D{Dist: d0},
+		Y: hbook.Dist1D{Dist: d0},
+		Z: hbook.Dist1D{Dist: d0},
+	}
+	d.Z.Stats.SumWX = sumwz
+	d.Z.Stats.SumWX2 = sumwz2
+	return d
+}
+
 func (*Profile2D) Class() string {
 	return "TProfile2D"
 }
@@ -89,6 +275,25 @@ func (p2d *Profile2D) UnmarshalROOT(r *rbytes.RBuffer) error {
 	return r.Err()
 }
 
+// ROOTMerge merges the content of src into p2d.
+// ROOTMerge returns an error if the axes of the profiles are not compatible.
+func (p2d *Profile2D) ROOTMerge(src root.Object) error {
+	psrc, ok := src.(*Profile2D)
+	if !ok {
+		return fmt.Errorf("rhist: can not merge %T into %T", src, p2d)
+	}
+
+	err := p2d.h2d.ROOTMerge(&psrc.h2d)
+	if err != nil {
+		return err
+	}
+	mergeBinEntries(&p2d.binEntries, &p2d.binSumw2, &psrc.binEntries, &psrc.binSumw2)
+
+	p2d.sumwz += psrc.sumwz
+	p2d.sumwz2 += psrc.sumwz2
+	return nil
+}
+
 func init() {
 	f := func() reflect.Value {
 		p2d := newProfile2D()
@@ -99,6 +304,8 @@ func init() {
 
 var (
 	_ root.Object        = (*Profile2D)(nil)
+	_ root.Merger        = (*Profile2D)(nil)
+	_ root.Named         = (*Profile2D)(nil)
 	_ rbytes.RVersioner  = (*Profile2D)(nil)
 	_ rbytes.Marshaler   = (*Profile2D)(nil)
 	_ rbytes.Unmarshaler = (*Profile2D)(nil)
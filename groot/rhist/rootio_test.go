@@ -190,6 +190,82 @@ func TestCreate(t *testing.T) {
 				}(),
 			},
 		},
+		{
+			Name: "TH3D",
+			ROOT: "retrieved: [h3d]\n",
+			Want: []rtests.ROOTer{
+				func() *rhist.H3D {
+					h := hbook.NewH3D(10, 0, 10, 5, 0, 5, 4, 0, 4)
+					h.Annotation()["name"] = "h3d"
+					h.Annotation()["title"] = "my title"
+					h.Fill(-1, -1, -1, 1)
+					h.Fill(+200, 200, 200, 1)
+					h.Fill(1, 1, 1, 1)
+					h.Fill(2, 2, 2, 1)
+					h.Fill(3, 3, 3, 10)
+					return rhist.NewH3DFrom(h)
+				}(),
+			},
+		},
+		{
+			Name: "TGraph2D",
+			ROOT: "retrieved: [tg2]\n",
+			Want: []rtests.ROOTer{
+				func() rtests.ROOTer {
+					hg := hbook.NewS3D(
+						hbook.Point3D{X: 1, Y: 1, Z: 2},
+						hbook.Point3D{X: 2, Y: 1.5, Z: 3},
+						hbook.Point3D{X: -1, Y: +2, Z: 4},
+					)
+					hg.Annotation()["name"] = "tg2"
+					hg.Annotation()["title"] = "my title"
+					return rhist.NewGraph2DFrom(hg).(rtests.ROOTer)
+				}(),
+			},
+		},
+		{
+			Name: "TGraph2DErrors",
+			ROOT: "retrieved: [tg2e]\n",
+			Want: []rtests.ROOTer{
+				func() rtests.ROOTer {
+					var (
+						ex = hbook.Range{Min: 1, Max: 1}
+						ey = hbook.Range{Min: 2, Max: 2}
+						ez = hbook.Range{Min: 3, Max: 3}
+					)
+					hg := hbook.NewS3D(
+						hbook.Point3D{X: 1, Y: 1, Z: 2, ErrX: ex, ErrY: ey, ErrZ: ez},
+						hbook.Point3D{X: 2, Y: 1.5, Z: 3, ErrX: ex, ErrY: ey, ErrZ: ez},
+						hbook.Point3D{X: -1, Y: +2, Z: 4, ErrX: ex, ErrY: ey, ErrZ: ez},
+					)
+					hg.Annotation()["name"] = "tg2e"
+					hg.Annotation()["title"] = "my title"
+					return rhist.NewGraph2DErrorsFrom(hg).(rtests.ROOTer)
+				}(),
+			},
+		},
+		{
+			Name: "TEfficiency",
+			ROOT: "retrieved: [eff]\n",
+			Want: []rtests.ROOTer{
+				func() rtests.ROOTer {
+					var (
+						pass = hbook.NewH1D(10, 0, 10)
+						tot  = hbook.NewH1D(10, 0, 10)
+					)
+					for i := 0; i < 10; i++ {
+						x := float64(i) + 0.5
+						tot.Fill(x, 10)
+						pass.Fill(x, float64(i))
+					}
+					eff, err := rhist.NewEfficiencyFrom("eff", "my title", pass, tot)
+					if err != nil {
+						t.Fatalf("could not create efficiency: %+v", err)
+					}
+					return eff
+				}(),
+			},
+		},
 	} {
 		fname := filepath.Join(dir, fmt.Sprintf("out-%d.root", i))
 		t.Run(tc.Name, func(t *testing.T) {
@@ -245,6 +321,34 @@ func TestCreate(t *testing.T) {
 				}
 
 				switch rgot := rgot.(type) {
+				case *rhist.Efficiency:
+					want := want.(*rhist.Efficiency)
+					if got, want := rgot.Name(), want.Name(); got != want {
+						t.Fatalf("invalid name: got=%q, want=%q", got, want)
+					}
+					if got, want := rgot.Title(), want.Title(); got != want {
+						t.Fatalf("invalid title: got=%q, want=%q", got, want)
+					}
+					for _, v := range []struct {
+						name      string
+						got, want rhist.H1
+					}{
+						{"passed", rgot.Passed(), want.Passed()},
+						{"total", rgot.Total(), want.Total()},
+					} {
+						got, err := v.got.(yodacnv.Marshaler).MarshalYODA()
+						if err != nil {
+							t.Fatalf("could not marshal %s 'rgot' to YODA: %+v", v.name, err)
+						}
+						want, err := v.want.(yodacnv.Marshaler).MarshalYODA()
+						if err != nil {
+							t.Fatalf("could not marshal %s 'want' to YODA: %+v", v.name, err)
+						}
+						if !bytes.Equal(got, want) {
+							t.Fatalf("error reading back %s histogram.\ngot:\n%s\nwant:\n%s", v.name, got, want)
+						}
+					}
+
 				case yodacnv.Marshaler:
 					got, err := rgot.MarshalYODA()
 					if err != nil {
@@ -222,6 +222,62 @@ func TestWRBuffer(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "TH3D",
+			want: &H3D{
+				th3: th3{
+					th1: th1{
+						Named:     *rbase.NewNamed("h3d", "my title"),
+						attline:   rbase.AttLine{Color: 602, Style: 1, Width: 1},
+						attfill:   rbase.AttFill{Color: 0, Style: 1001},
+						attmarker: rbase.AttMarker{Color: 1, Style: 1, Width: 1},
+						ncells:    27,
+						xaxis: taxis{
+							Named: *rbase.NewNamed("xaxis", ""),
+							nbins: 1, xmin: 0, xmax: 1,
+						},
+						yaxis: taxis{
+							Named: *rbase.NewNamed("yaxis", ""),
+							nbins: 1, xmin: 0, xmax: 2,
+						},
+						zaxis: taxis{
+							Named: *rbase.NewNamed("zaxis", ""),
+							nbins: 1, xmin: 0, xmax: 3,
+						},
+						bwidth:  1000,
+						entries: 3,
+						tsumw:   2,
+						tsumw2:  2,
+						tsumwx:  1,
+						tsumwx2: 0.5,
+						max:     -1111,
+						min:     -1111,
+						sumw2: rcont.ArrayD{
+							Data: []float64{
+								0, 0, 0, 0, 0, 0, 0, 0, 0,
+								0, 0, 0, 0, 2, 0, 0, 0, 0,
+								0, 0, 0, 0, 0, 0, 0, 0, 1,
+							},
+						},
+						funcs: *rcont.NewList("", []root.Object{}),
+					},
+					tsumwy:  2,
+					tsumwy2: 2,
+					tsumwxy: 1,
+					tsumwz:  3,
+					tsumwz2: 4.5,
+					tsumwxz: 1.5,
+					tsumwyz: 3,
+				},
+				arr: rcont.ArrayD{
+					Data: []float64{
+						0, 0, 0, 0, 0, 0, 0, 0, 0,
+						0, 0, 0, 0, 2, 0, 0, 0, 0,
+						0, 0, 0, 0, 0, 0, 0, 0, 1,
+					},
+				},
+			},
+		},
 		{
 			name: "TConfidenceLevel",
 			want: &ConfidenceLevel{
@@ -122,8 +122,6 @@ func newDirectoryFile(name, title string, f *File, parent *tdirectoryFile) *tdir
 	dir.seekdir = key.seekkey
 
 	buf := rbytes.NewWBuffer(make([]byte, objlen), nil, 0, f)
-	buf.WriteString(f.id)
-	buf.WriteString(f.Title())
 	// dir-marshal
 	_, err := dir.MarshalROOT(buf)
 	if err != nil {
@@ -365,10 +363,7 @@ func (dir *tdirectoryFile) Get(namecycle string) (root.Object, error) {
 	for i := range dir.keys {
 		k := &dir.keys[i]
 		if k.Name() == name {
-			if cycle != 9999 {
-				if k.cycle == cycle {
-					return k.Object()
-				}
+			if cycle != 9999 && k.cycle != cycle {
 				continue
 			}
 			keys = append(keys, k)
@@ -402,6 +397,9 @@ func (dir *tdirectoryFile) Get(namecycle string) (root.Object, error) {
 			if obj.Title() == "" {
 				obj.dir.named.SetTitle(name)
 			}
+			if dir.file.w != nil {
+				dir.addDir(obj)
+			}
 		}
 	}
 	return obj, nil
@@ -479,6 +477,91 @@ func (dir *tdirectoryFile) Put(name string, obj root.Object) error {
 	return nil
 }
 
+// Delete removes the key(s) identified by namecycle from this directory.
+// The space used by the deleted keys is marked as free on file.
+// Deleting a directory also deletes all of its content.
+//
+//	namecycle has the format name;cycle
+//
+//	examples:
+//	  foo   : delete all cycles of foo
+//	  foo;* : delete all cycles of foo
+//	  foo;1 : delete cycle 1 of foo
+func (dir *tdirectoryFile) Delete(namecycle string) error {
+	if dir.file.w == nil {
+		return fmt.Errorf("could not delete %q from directory %q: %w", namecycle, dir.dir.Name(), ErrReadOnly)
+	}
+
+	var (
+		name, cycle = decodeNameCycle(namecycle)
+		keep        = make([]Key, 0, len(dir.keys))
+		dels        []Key
+	)
+	for _, k := range dir.keys {
+		if k.name == name && (cycle == 9999 || k.cycle == cycle) {
+			dels = append(dels, k)
+			continue
+		}
+		keep = append(keep, k)
+	}
+
+	if len(dels) == 0 {
+		return noKeyError{key: namecycle, obj: dir}
+	}
+
+	for i := range dels {
+		err := dir.deleteKey(&dels[i])
+		if err != nil {
+			return fmt.Errorf("riofs: could not delete %q: %w", namecycle, err)
+		}
+	}
+	dir.keys = keep
+
+	return nil
+}
+
+// deleteKey marks the space used by the provided key as free.
+// If the key holds a directory, its content is deleted as well.
+func (dir *tdirectoryFile) deleteKey(k *Key) error {
+	switch k.class {
+	case "TDirectory", "TDirectoryFile":
+		obj, err := k.Object()
+		if err != nil {
+			return fmt.Errorf("riofs: could not load directory %q: %w", k.Name(), err)
+		}
+		sub := obj.(*tdirectoryFile)
+		for i := range sub.keys {
+			err = sub.deleteKey(&sub.keys[i])
+			if err != nil {
+				return err
+			}
+		}
+		sub.keys = nil
+		if sub.seekkeys > 0 {
+			dir.file.markFree(sub.seekkeys, sub.seekkeys+int64(sub.nbyteskeys)-1)
+		}
+		for i, d := range dir.dirs {
+			if d == sub {
+				dir.dirs = append(dir.dirs[:i], dir.dirs[i+1:]...)
+				break
+			}
+		}
+	}
+
+	dir.file.markFree(k.seekkey, k.seekkey+int64(k.nbytes)-1)
+	return nil
+}
+
+// addDir registers sub as a sub-directory to be saved when this directory is closed.
+func (dir *tdirectoryFile) addDir(sub *tdirectoryFile) {
+	for _, d := range dir.dirs {
+		if d == sub {
+			return
+		}
+	}
+	dir.dirs = append(dir.dirs, sub)
+}
+
 // Keys returns the list of keys being held by this directory.
 func (dir *tdirectoryFile) Keys() []Key {
 	return dir.keys
@@ -619,8 +702,6 @@ func (dir *tdirectoryFile) writeKeys() error {
 		nbytes += key.keylen
 	}
 
-	hdr := newKey(dir, dir.Name(), dir.Title(), "TDirectory", nbytes, dir.file)
-
 	buf := rbytes.NewWBuffer(make([]byte, nbytes), nil, 0, nil)
 	buf.WriteI32(int32(len(dir.Keys())))
 	for _, k := range dir.Keys() {
@@ -629,6 +710,18 @@ func (dir *tdirectoryFile) writeKeys() error {
 			return fmt.Errorf("riofs: could not write key: %w", err)
 		}
 	}
+	if n := int32(len(buf.Bytes())); n > nbytes {
+		// keys read back from file may have a longer header
+		// (e.g. "TDirectory" keys are promoted to "TDirectoryFile".)
+		nbytes = n
+	}
+
+	if dir.seekkeys > 0 {
+		// release the previous list of keys (file opened in update mode.)
+		dir.file.markFree(dir.seekkeys, dir.seekkeys+int64(dir.nbyteskeys)-1)
+	}
+
+	hdr := newKey(dir, dir.Name(), dir.Title(), "TDirectory", nbytes, dir.file)
 	hdr.buf = buf.Bytes()
 
 	dir.seekkeys = hdr.seekkey
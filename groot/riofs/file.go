@@ -120,12 +120,14 @@ type File struct {
 	simap  map[rbytes.StreamerInfo]struct{} // local set of streamers, when writing
 
 	spans freeList // list of free spans on file
+
+	recovery bool // whether to recover the file if it was not properly closed
 }
 
 // Open opens the named ROOT file for reading. If successful, methods on the
 // returned file can be used for reading; the associated file descriptor
 // has mode os.O_RDONLY.
-func Open(path string) (*File, error) {
+func Open(path string, opts ...FileOption) (*File, error) {
 	fd, err := openFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("riofs: unable to open %q: %w", path, err)
@@ -139,7 +141,21 @@ func Open(path string) (*File, error) {
 	f.dir.file = f
 
 	err = f.readHeader()
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		err := opt(f)
+		if err != nil {
+			_ = fd.Close()
+			return nil, fmt.Errorf("riofs: could not apply option to ROOT file: %w", err)
+		}
+	}
+
+	err = f.recoverIfNeeded(err)
 	if err != nil {
+		_ = fd.Close()
 		return nil, fmt.Errorf("riofs: failed to read header %q: %w", path, err)
 	}
 
@@ -233,6 +249,62 @@ func Create(name string, opts ...FileOption) (*File, error) {
 	return f, nil
 }
 
+// Update opens the named ROOT file for reading and writing.
+// If successful, methods on the returned file can be used to read existing
+// objects, to add new objects (or new cycles of existing objects) and to
+// delete keys.
+// The list of keys, the directory records, the list of free segments and
+// the StreamerInfos are written back to the file upon Close.
+func Update(path string, opts ...FileOption) (*File, error) {
+	fd, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("riofs: unable to open %q for update: %w", path, err)
+	}
+
+	f := &File{
+		r:      fd,
+		w:      fd,
+		closer: fd,
+		id:     path,
+		simap:  make(map[rbytes.StreamerInfo]struct{}),
+	}
+	f.dir.file = f
+
+	err = f.readHeader()
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		err := opt(f)
+		if err != nil {
+			_ = fd.Close()
+			return nil, fmt.Errorf("riofs: could not apply option to ROOT file: %w", err)
+		}
+	}
+
+	err = f.recoverIfNeeded(err)
+	if err != nil {
+		_ = fd.Close()
+		return nil, fmt.Errorf("riofs: failed to read header %q: %w", path, err)
+	}
+
+	for _, si := range f.sinfos {
+		f.simap[si] = struct{}{}
+	}
+
+	if blk := f.spans.last(); blk == nil || blk.last < f.end {
+		f.spans.add(f.end, kStartBigFile)
+	}
+	err = f.setEnd(f.end)
+	if err != nil {
+		_ = fd.Close()
+		return nil, fmt.Errorf("riofs: invalid free segments list for %q: %w", path, err)
+	}
+
+	return f, nil
+}
+
 func (f *File) setEnd(pos int64) error {
 	f.end = pos
 	if f.spans.Len() == 0 {
@@ -287,7 +359,36 @@ func (f *File) Version() int {
 }
 
 func (f *File) readHeader() error {
+	err := f.readFileHeader()
+	if err != nil {
+		return err
+	}
+
+	if f.seekfree > 0 {
+		err = f.readFreeSegments()
+		if err != nil {
+			return fmt.Errorf("riofs: failed to read ROOT file free segments: %w", err)
+		}
+	}
+
+	if f.seekinfo > 0 {
+		err = f.readStreamerInfo()
+		if err != nil {
+			return fmt.Errorf("riofs: failed to read ROOT streamer infos: %w", err)
+		}
+	}
 
+	err = f.dir.readKeys()
+	if err != nil {
+		return fmt.Errorf("riofs: failed to read ROOT file keys: %w", err)
+	}
+
+	return nil
+}
+
+// readFileHeader reads the file description and the record of the
+// top-level directory.
+func (f *File) readFileHeader() error {
 	buf := make([]byte, 64+12) // 64: small file + extra space for big file
 	if _, err := f.ReadAt(buf, 0); err != nil {
 		return err
@@ -344,25 +445,6 @@ func (f *File) readHeader() error {
 		return fmt.Errorf("riofs: failed to read ROOT directory infos: %w", err)
 	}
 
-	if f.seekfree > 0 {
-		err = f.readFreeSegments()
-		if err != nil {
-			return fmt.Errorf("riofs: failed to read ROOT file free segments: %w", err)
-		}
-	}
-
-	if f.seekinfo > 0 {
-		err = f.readStreamerInfo()
-		if err != nil {
-			return fmt.Errorf("riofs: failed to read ROOT streamer infos: %w", err)
-		}
-	}
-
-	err = f.dir.readKeys()
-	if err != nil {
-		return fmt.Errorf("riofs: failed to read ROOT file keys: %w", err)
-	}
-
 	return nil
 }
 
@@ -626,7 +708,7 @@ func (f *File) findDepStreamers() error {
 	}
 
 	for _, dep := range deps {
-		if isCoreType(dep.name) || isCxxBuiltin(dep.name) {
+		if isCoreType(dep.name) || isCxxBuiltin(dep.name) || rmeta.IsCxxBuiltinPair(dep.name) {
 			continue
 		}
 		sub, err := rdict.StreamerInfos.StreamerInfo(dep.name, dep.vers)
@@ -873,6 +955,21 @@ func (f *File) Put(name string, v root.Object) error {
 	return f.dir.Put(name, v)
 }
 
+// Delete removes the key(s) identified by namecycle from the file.
+//
+//	namecycle has the format name;cycle
+//
+//	examples:
+//	  foo   : delete all cycles of foo
+//	  foo;* : delete all cycles of foo
+//	  foo;1 : delete cycle 1 of foo
+func (f *File) Delete(namecycle string) error {
+	if f.w == nil {
+		return fmt.Errorf("could not delete %q from file %q: %w", namecycle, f.Name(), ErrReadOnly)
+	}
+	return f.dir.Delete(namecycle)
+}
+
 // Mkdir creates a new subdirectory
 func (f *File) Mkdir(name string) (Directory, error) {
 	if f.w == nil {
@@ -547,3 +547,482 @@ func TestReadOnlyFile(t *testing.T) {
 		t.Fatalf("expected an error. got nil")
 	}
 }
+
+func TestUpdate(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "update.root")
+
+	func() {
+		f, err := riofs.Create(fname)
+		if err != nil {
+			t.Fatalf("could not create file: %+v", err)
+		}
+		defer f.Close()
+
+		err = f.Put("str", rbase.NewObjString("v1"))
+		if err != nil {
+			t.Fatalf("could not put str: %+v", err)
+		}
+
+		for _, name := range []string{"dir1", "dir2"} {
+			dir, err := f.Mkdir(name)
+			if err != nil {
+				t.Fatalf("could not create %q: %+v", name, err)
+			}
+			err = dir.Put("obj", rbase.NewObjString(name+"-obj"))
+			if err != nil {
+				t.Fatalf("could not put obj in %q: %+v", name, err)
+			}
+		}
+
+		err = f.Close()
+		if err != nil {
+			t.Fatalf("could not close file: %+v", err)
+		}
+	}()
+
+	func() {
+		f, err := riofs.Update(fname)
+		if err != nil {
+			t.Fatalf("could not open file for update: %+v", err)
+		}
+		defer f.Close()
+
+		err = f.Put("str", rbase.NewObjString("v2"))
+		if err != nil {
+			t.Fatalf("could not put str: %+v", err)
+		}
+
+		err = f.Put("new", rbase.NewObjString("new"))
+		if err != nil {
+			t.Fatalf("could not put new: %+v", err)
+		}
+
+		err = f.Delete("str;1")
+		if err != nil {
+			t.Fatalf("could not delete str;1: %+v", err)
+		}
+
+		err = f.Delete("dir2")
+		if err != nil {
+			t.Fatalf("could not delete dir2: %+v", err)
+		}
+
+		err = f.Delete("not-there")
+		if err == nil {
+			t.Fatalf("expected an error deleting a missing key")
+		}
+
+		o, err := f.Get("dir1")
+		if err != nil {
+			t.Fatalf("could not get dir1: %+v", err)
+		}
+		err = o.(riofs.Directory).Put("obj", rbase.NewObjString("dir1-obj-v2"))
+		if err != nil {
+			t.Fatalf("could not put obj in dir1: %+v", err)
+		}
+
+		sub, err := o.(riofs.Directory).Mkdir("dir11")
+		if err != nil {
+			t.Fatalf("could not create dir1/dir11: %+v", err)
+		}
+		err = sub.Put("obj", rbase.NewObjString("dir11-obj"))
+		if err != nil {
+			t.Fatalf("could not put obj in dir1/dir11: %+v", err)
+		}
+
+		err = f.Close()
+		if err != nil {
+			t.Fatalf("could not close file: %+v", err)
+		}
+	}()
+
+	f, err := riofs.Open(fname)
+	if err != nil {
+		t.Fatalf("could not open file: %+v", err)
+	}
+	defer f.Close()
+
+	var keys []string
+	for _, k := range f.Keys() {
+		keys = append(keys, fmt.Sprintf("%s;%d", k.Name(), k.Cycle()))
+	}
+	if got, want := keys, []string{"dir1;1", "str;2", "new;1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid keys:\ngot= %q\nwant=%q", got, want)
+	}
+
+	for _, tc := range []struct {
+		name string
+		want string
+	}{
+		{"str", "v2"},
+		{"new", "new"},
+		{"dir1/obj;1", "dir1-obj"},
+		{"dir1/obj;2", "dir1-obj-v2"},
+		{"dir1/dir11/obj", "dir11-obj"},
+	} {
+		o, err := riofs.Dir(f).Get(tc.name)
+		if err != nil {
+			t.Fatalf("could not get %q: %+v", tc.name, err)
+		}
+		if got := o.(*rbase.ObjString).String(); got != tc.want {
+			t.Fatalf("invalid value for %q: got=%q, want=%q", tc.name, got, tc.want)
+		}
+	}
+
+	for _, name := range []string{"str;1", "dir2"} {
+		_, err = f.Get(name)
+		if err == nil {
+			t.Fatalf("expected %q to be deleted", name)
+		}
+	}
+}
+
+func TestUpdateDirCycle(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "update-cycle.root")
+
+	func() {
+		f, err := riofs.Create(fname)
+		if err != nil {
+			t.Fatalf("could not create file: %+v", err)
+		}
+		defer f.Close()
+
+		dir, err := f.Mkdir("dir1")
+		if err != nil {
+			t.Fatalf("could not create dir1: %+v", err)
+		}
+		err = dir.Put("a", rbase.NewObjString("a"))
+		if err != nil {
+			t.Fatalf("could not put a: %+v", err)
+		}
+
+		err = f.Close()
+		if err != nil {
+			t.Fatalf("could not close file: %+v", err)
+		}
+	}()
+
+	func() {
+		f, err := riofs.Update(fname)
+		if err != nil {
+			t.Fatalf("could not open file for update: %+v", err)
+		}
+		defer f.Close()
+
+		o, err := f.Get("dir1;1")
+		if err != nil {
+			t.Fatalf("could not get dir1;1: %+v", err)
+		}
+		err = o.(riofs.Directory).Put("b", rbase.NewObjString("b"))
+		if err != nil {
+			t.Fatalf("could not put b in dir1: %+v", err)
+		}
+
+		err = f.Close()
+		if err != nil {
+			t.Fatalf("could not close file: %+v", err)
+		}
+	}()
+
+	f, err := riofs.Open(fname)
+	if err != nil {
+		t.Fatalf("could not open file: %+v", err)
+	}
+	defer f.Close()
+
+	for _, name := range []string{"a", "b"} {
+		o, err := riofs.Dir(f).Get("dir1/" + name)
+		if err != nil {
+			t.Fatalf("could not get dir1/%s: %+v", name, err)
+		}
+		if got, want := o.(*rbase.ObjString).String(), name; got != want {
+			t.Fatalf("invalid value: got=%q, want=%q", got, want)
+		}
+	}
+}
+
+func TestUpdateROOTFile(t *testing.T) {
+	raw, err := os.ReadFile("../testdata/dirs-6.14.00.root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fname := filepath.Join(t.TempDir(), "dirs.root")
+	err = os.WriteFile(fname, raw, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := riofs.Update(fname)
+	if err != nil {
+		t.Fatalf("could not open file for update: %+v", err)
+	}
+	defer f.Close()
+
+	o, err := riofs.Dir(f).Get("dir1/dir11")
+	if err != nil {
+		t.Fatalf("could not get dir1/dir11: %+v", err)
+	}
+	err = o.(riofs.Directory).Put("obj", rbase.NewObjString("hello"))
+	if err != nil {
+		t.Fatalf("could not put obj: %+v", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		t.Fatalf("could not close file: %+v", err)
+	}
+
+	f, err = riofs.Open(fname)
+	if err != nil {
+		t.Fatalf("could not open file: %+v", err)
+	}
+	defer f.Close()
+
+	o, err = riofs.Dir(f).Get("dir1/dir11/obj")
+	if err != nil {
+		t.Fatalf("could not get obj: %+v", err)
+	}
+	if got, want := o.(*rbase.ObjString).String(), "hello"; got != want {
+		t.Fatalf("invalid value: got=%q, want=%q", got, want)
+	}
+
+	err = riofs.Walk(f, func(path string, obj root.Object, err error) error {
+		return err
+	})
+	if err != nil {
+		t.Fatalf("could not walk file: %+v", err)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	tmp := t.TempDir()
+	fname := filepath.Join(tmp, "recover.root")
+
+	var crashed []byte
+	func() {
+		f, err := riofs.Create(fname)
+		if err != nil {
+			t.Fatalf("could not create file: %+v", err)
+		}
+		defer f.Close()
+
+		err = f.Put("str", rbase.NewObjString("v1"))
+		if err != nil {
+			t.Fatalf("could not put str: %+v", err)
+		}
+
+		dir, err := f.Mkdir("dir1")
+		if err != nil {
+			t.Fatalf("could not create dir1: %+v", err)
+		}
+		err = dir.Put("obj", rbase.NewObjString("dir1-obj"))
+		if err != nil {
+			t.Fatalf("could not put obj in dir1: %+v", err)
+		}
+
+		sub, err := dir.Mkdir("dir11")
+		if err != nil {
+			t.Fatalf("could not create dir1/dir11: %+v", err)
+		}
+		err = sub.Put("obj", rbase.NewObjString("dir11-obj"))
+		if err != nil {
+			t.Fatalf("could not put obj in dir1/dir11: %+v", err)
+		}
+
+		err = f.Put("str", rbase.NewObjString("v2"))
+		if err != nil {
+			t.Fatalf("could not put str: %+v", err)
+		}
+
+		// simulate a crash of the writing process:
+		// take a snapshot of the file before it is closed.
+		crashed, err = os.ReadFile(fname)
+		if err != nil {
+			t.Fatalf("could not read file: %+v", err)
+		}
+
+		err = f.Close()
+		if err != nil {
+			t.Fatalf("could not close file: %+v", err)
+		}
+	}()
+
+	want := []struct {
+		name string
+		want string
+	}{
+		{"str;1", "v1"},
+		{"str;2", "v2"},
+		{"dir1/obj", "dir1-obj"},
+		{"dir1/dir11/obj", "dir11-obj"},
+	}
+
+	check := func(t *testing.T, f *riofs.File) {
+		t.Helper()
+
+		var keys []string
+		for _, k := range f.Keys() {
+			keys = append(keys, fmt.Sprintf("%s;%d", k.Name(), k.Cycle()))
+		}
+		if got, want := keys, []string{"str;1", "dir1;1", "str;2"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("invalid keys:\ngot= %q\nwant=%q", got, want)
+		}
+
+		for _, tc := range want {
+			o, err := riofs.Dir(f).Get(tc.name)
+			if err != nil {
+				t.Fatalf("could not get %q: %+v", tc.name, err)
+			}
+			if got := o.(*rbase.ObjString).String(); got != tc.want {
+				t.Fatalf("invalid value for %q: got=%q, want=%q", tc.name, got, tc.want)
+			}
+		}
+	}
+
+	crash := filepath.Join(tmp, "crashed.root")
+	err := os.WriteFile(crash, crashed, 0644)
+	if err != nil {
+		t.Fatalf("could not create crashed file: %+v", err)
+	}
+
+	t.Run("open", func(t *testing.T) {
+		f, err := riofs.Open(crash)
+		if err == nil {
+			defer f.Close()
+			if len(f.Keys()) != 0 {
+				t.Fatalf("expected no keys without recovery")
+			}
+		}
+
+		f, err = riofs.Open(crash, riofs.WithRecover())
+		if err != nil {
+			t.Fatalf("could not recover file: %+v", err)
+		}
+		defer f.Close()
+
+		check(t, f)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		f, err := riofs.Open(fname, riofs.WithRecover())
+		if err != nil {
+			t.Fatalf("could not open file: %+v", err)
+		}
+		defer f.Close()
+
+		check(t, f)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		raw, err := os.ReadFile(fname)
+		if err != nil {
+			t.Fatalf("could not read file: %+v", err)
+		}
+		// drop the trailing records (lists of keys, streamers, free segments).
+		trunc := filepath.Join(tmp, "truncated.root")
+		err = os.WriteFile(trunc, raw[:len(crashed)+3], 0644)
+		if err != nil {
+			t.Fatalf("could not create truncated file: %+v", err)
+		}
+
+		f, err := riofs.Open(trunc, riofs.WithRecover())
+		if err != nil {
+			t.Fatalf("could not recover file: %+v", err)
+		}
+		defer f.Close()
+
+		check(t, f)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		repair := filepath.Join(tmp, "repair.root")
+		err := os.WriteFile(repair, crashed, 0644)
+		if err != nil {
+			t.Fatalf("could not create crashed file: %+v", err)
+		}
+
+		f, err := riofs.Update(repair, riofs.WithRecover())
+		if err != nil {
+			t.Fatalf("could not recover file: %+v", err)
+		}
+		defer f.Close()
+
+		err = f.Put("new", rbase.NewObjString("new"))
+		if err != nil {
+			t.Fatalf("could not put new: %+v", err)
+		}
+
+		err = f.Close()
+		if err != nil {
+			t.Fatalf("could not close repaired file: %+v", err)
+		}
+
+		f, err = riofs.Open(repair)
+		if err != nil {
+			t.Fatalf("could not open repaired file: %+v", err)
+		}
+		defer f.Close()
+
+		o, err := f.Get("new")
+		if err != nil {
+			t.Fatalf("could not get new: %+v", err)
+		}
+		if got, want := o.(*rbase.ObjString).String(), "new"; got != want {
+			t.Fatalf("invalid value: got=%q, want=%q", got, want)
+		}
+
+		for _, tc := range want {
+			o, err := riofs.Dir(f).Get(tc.name)
+			if err != nil {
+				t.Fatalf("could not get %q: %+v", tc.name, err)
+			}
+			if got := o.(*rbase.ObjString).String(); got != tc.want {
+				t.Fatalf("invalid value for %q: got=%q, want=%q", tc.name, got, tc.want)
+			}
+		}
+	})
+
+	t.Run("root-file", func(t *testing.T) {
+		raw, err := os.ReadFile("../testdata/dirs-6.14.00.root")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// truncate the file right before the list of keys of the top-level
+		// directory: the streamer info and free segments are dropped as well.
+		const seekkeys = 1297
+		trunc := filepath.Join(tmp, "dirs.root")
+		err = os.WriteFile(trunc, raw[:seekkeys], 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		f, err := riofs.Open(trunc, riofs.WithRecover())
+		if err != nil {
+			t.Fatalf("could not recover file: %+v", err)
+		}
+		defer f.Close()
+
+		for _, name := range []string{"dir1", "dir2", "dir3", "dir1/dir11"} {
+			o, err := riofs.Dir(f).Get(name)
+			if err != nil {
+				t.Fatalf("could not get %q: %+v", name, err)
+			}
+			if _, ok := o.(riofs.Directory); !ok {
+				t.Fatalf("%q is not a directory (%T)", name, o)
+			}
+		}
+
+		n := 0
+		err = riofs.Walk(f, func(path string, obj root.Object, err error) error {
+			n++
+			return err
+		})
+		if err != nil {
+			t.Fatalf("could not walk recovered file: %+v", err)
+		}
+		if n < 6 {
+			t.Fatalf("invalid number of recovered objects: %d", n)
+		}
+	})
+}
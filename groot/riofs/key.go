@@ -186,13 +186,35 @@ func newKeyFrom(dir *tdirectoryFile, name, title, class string, obj root.Object,
 }
 
 func newKeyFromBuf(dir *tdirectoryFile, name, title, class string, cycle int16, buf []byte, f *File) (Key, error) {
-	var err error
+	zip, err := rcompress.Compress(nil, buf, f.compression)
+	if err != nil {
+		return Key{}, fmt.Errorf("riofs: could not compress object %s for key %q: %w", class, name, err)
+	}
+	return newKeyFromZip(dir, name, title, class, cycle, int32(len(buf)), zip, f)
+}
+
+// NewKeyFromCompressedInternal creates a new key from the provided
+// serialized object buffer, already compressed.
+// objlen is the length of the object buffer before compression.
+// NewKeyFromCompressedInternal puts the key and its payload at the end
+// of the provided file f.
+// This is needed for Tree/Branch/Basket persistency.
+//
+// DO NOT USE.
+func NewKeyFromCompressedInternal(dir Directory, name, title, class string, cycle int16, objlen int32, zip []byte, f *File) (Key, error) {
+	var d *tdirectoryFile
+	if dir != nil {
+		d = dir.(*tdirectoryFile)
+	}
+	return newKeyFromZip(d, name, title, class, cycle, objlen, zip, f)
+}
+
+func newKeyFromZip(dir *tdirectoryFile, name, title, class string, cycle int16, objlen int32, zip []byte, f *File) (Key, error) {
 	if dir == nil {
 		dir = &f.dir
 	}
 
 	keylen := keylenFor(name, title, class, dir, f.end)
-	objlen := int32(len(buf))
 	k := Key{
 		f:        f,
 		nbytes:   keylen + objlen,
@@ -212,13 +234,10 @@ func newKeyFromBuf(dir *tdirectoryFile, name, title, class string, cycle int16,
 		k.rvers += 1000
 	}
 
-	k.buf, err = rcompress.Compress(nil, buf, k.f.compression)
-	if err != nil {
-		return k, fmt.Errorf("riofs: could not compress object %s for key %q: %w", class, name, err)
-	}
+	k.buf = zip
 	k.nbytes = k.keylen + int32(len(k.buf))
 
-	err = f.setEnd(k.seekkey + int64(k.nbytes))
+	err := f.setEnd(k.seekkey + int64(k.nbytes))
 	if err != nil {
 		return k, fmt.Errorf("riofs: could not update ROOT file end: %w", err)
 	}
@@ -268,6 +287,63 @@ func NewKeyForBasketInternal(dir Directory, name, title, class string, cycle int
 	return k
 }
 
+// NewBlobKeyInternal creates a new key of the provided class, holding the
+// provided payload, and writes it to the file.
+// The payload is stored as-is, without any compression.
+// This is needed for RNTuple persistency.
+//
+// DO NOT USE.
+func NewBlobKeyInternal(dir Directory, class string, blob []byte) (Key, error) {
+	var (
+		f = fileOf(dir)
+		d *tdirectoryFile
+	)
+	switch v := dir.(type) {
+	case *File:
+		d = &v.dir
+	case *tdirectoryFile:
+		d = v
+	default:
+		return Key{}, fmt.Errorf("riofs: invalid directory type %T", dir)
+	}
+
+	if f.w == nil {
+		return Key{}, fmt.Errorf("riofs: could not write blob: %w", ErrReadOnly)
+	}
+
+	keylen := keylenFor("", "", class, d, f.end)
+	objlen := int32(len(blob))
+	k := Key{
+		f:        f,
+		nbytes:   keylen + objlen,
+		rvers:    rvers.Key,
+		keylen:   keylen,
+		objlen:   objlen,
+		datetime: nowUTC(),
+		cycle:    1,
+		class:    class,
+		seekkey:  f.end,
+		seekpdir: d.seekdir,
+		parent:   dir,
+		buf:      blob,
+	}
+	if f.IsBigFile() {
+		k.rvers += 1000
+	}
+
+	err := f.setEnd(k.seekkey + int64(k.nbytes))
+	if err != nil {
+		return k, fmt.Errorf("riofs: could not update ROOT file end: %w", err)
+	}
+
+	_, err = k.writeFile(f)
+	if err != nil {
+		return k, fmt.Errorf("riofs: could not write blob key: %w", err)
+	}
+
+	return k, nil
+}
+
 // KeyFromDir creates a new empty key (with no associated payload object)
 // with provided name and title, and the expected object type name.
 // The key will be held by the provided directory.
@@ -0,0 +1,241 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package riofs
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"go-hep.org/x/hep/groot/rbytes"
+)
+
+// WithRecover configures a ROOT file, opened with Open or Update, to be
+// recovered when it was not properly closed (e.g. when the process writing
+// it crashed) or when it was truncated.
+//
+// As TFile::Recover does, the file is then scanned linearly for records,
+// from the beginning of the file until the first incomplete or corrupted
+// record, and the list of keys of each directory is rebuilt from the
+// records found.
+// Objects, such as trees, are recovered in the state of their last complete
+// record on file.
+//
+// A file opened with Update and WithRecover is repaired in place:
+// the rebuilt lists of keys and the file header are written back to the
+// file upon Close.
+func WithRecover() FileOption {
+	return func(f *File) error {
+		f.recovery = true
+		return nil
+	}
+}
+
+// recoverIfNeeded recovers the content of the file if recovery was requested
+// and the file could not be read (err != nil) or was not properly closed.
+func (f *File) recoverIfNeeded(err error) error {
+	if !f.recovery {
+		return err
+	}
+
+	if err == nil {
+		size, ok := f.size()
+		if f.dir.seekkeys > f.begin && (!ok || f.end <= size) {
+			// file properly closed.
+			return nil
+		}
+	}
+
+	return f.recoverKeys()
+}
+
+// size returns the size of the underlying file, if known.
+func (f *File) size() (int64, bool) {
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, false
+	}
+	return fi.Size(), true
+}
+
+// recoverKeys rebuilds the directories of the file from the records found
+// while scanning the file.
+func (f *File) recoverKeys() error {
+	err := f.readFileHeader()
+	if err != nil {
+		return fmt.Errorf("riofs: could not recover file: %w", err)
+	}
+
+	f.seekfree = 0
+	f.nbytesfree = 0
+	f.nfree = 0
+	f.spans = nil
+	f.seekinfo = 0
+	f.nbytesinfo = 0
+	f.sinfos = nil
+	f.dir.seekkeys = 0
+	f.dir.nbyteskeys = 0
+	f.dir.keys = nil
+	f.dir.dirs = nil
+
+	size, ok := f.size()
+
+	var (
+		keys []Key
+		pos  = f.begin
+	)
+	for !ok || pos < size {
+		k, err := f.recoverKey(pos)
+		if err != nil {
+			// truncated or corrupted record: stop here.
+			break
+		}
+		if k.nbytes < 0 {
+			// gap of free bytes.
+			pos -= int64(k.nbytes)
+			continue
+		}
+		pos += int64(k.nbytes)
+
+		switch {
+		case k.class == "TFile", k.class == "TBasket":
+			// directory header, list of free segments or basket of a tree.
+			continue
+		case k.name == "StreamerInfo" && k.seekpdir == f.dir.seekdir:
+			f.seekinfo = k.seekkey
+			f.nbytesinfo = k.nbytes
+			continue
+		}
+		keys = append(keys, k)
+	}
+	f.end = pos
+
+	if f.seekinfo > 0 {
+		err = f.readStreamerInfo()
+		if err != nil {
+			// try to decode objects with the streamers known to groot.
+			f.seekinfo = 0
+			f.nbytesinfo = 0
+			f.sinfos = nil
+		}
+	}
+
+	dirs := map[int64]*tdirectoryFile{f.dir.seekdir: &f.dir}
+	for i := range keys {
+		k := &keys[i]
+		switch k.class {
+		case "TDirectory", "TDirectoryFile":
+			dir, ok := f.recoverDir(k)
+			if !ok {
+				// list of keys of a directory.
+				k.class = ""
+				continue
+			}
+			dirs[dir.seekdir] = dir
+		}
+	}
+
+	for i := range keys {
+		k := keys[i]
+		if k.class == "" {
+			continue
+		}
+		dir, ok := dirs[k.seekpdir]
+		if !ok {
+			// orphan record.
+			continue
+		}
+		k.parent = dir
+		dir.keys = append(dir.keys, k)
+		if sub, ok := k.obj.(*tdirectoryFile); ok {
+			sub.dir.parent = dir
+			dir.addDir(sub)
+		}
+	}
+
+	return nil
+}
+
+// recoverKey reads and validates the header of the record located at pos.
+func (f *File) recoverKey(pos int64) (Key, error) {
+	const n = 16 // nbytes, version, objlen, datime and keylen
+	var (
+		k   = Key{f: f}
+		hdr = make([]byte, n)
+	)
+	_, err := f.ReadAt(hdr, pos)
+	if err != nil {
+		return k, err
+	}
+
+	r := rbytes.NewRBuffer(hdr, nil, 0, nil)
+	k.nbytes = r.ReadI32()
+	if k.nbytes < 0 {
+		k.class = "[GAP]"
+		return k, nil
+	}
+	r.SetPos(n - 2) // keylen
+	keylen := int32(r.ReadI16())
+	if k.nbytes == 0 || keylen < n || keylen > k.nbytes {
+		return k, fmt.Errorf("riofs: invalid record at %d (nbytes=%d, keylen=%d)", pos, k.nbytes, keylen)
+	}
+
+	buf := make([]byte, keylen)
+	_, err = f.ReadAt(buf, pos)
+	if err != nil {
+		return k, err
+	}
+	err = k.UnmarshalROOT(rbytes.NewRBuffer(buf, nil, 0, nil))
+	if err != nil {
+		return k, err
+	}
+	if k.seekkey != pos || k.objlen < 0 || k.class == "" {
+		return k, fmt.Errorf("riofs: invalid record at %d", pos)
+	}
+
+	// make sure the whole record is on file.
+	_, err = f.ReadAt(hdr[:1], pos+int64(k.nbytes)-1)
+	if err != nil {
+		return k, err
+	}
+
+	return k, nil
+}
+
+// recoverDir creates the directory described by the provided record.
+// recoverDir returns false if the record does not hold a directory.
+func (f *File) recoverDir(k *Key) (*tdirectoryFile, bool) {
+	buf, err := k.Bytes()
+	if err != nil {
+		return nil, false
+	}
+
+	// make sure the record is large enough to hold a directory header:
+	// lists of keys are also stored with the class of their directory.
+	n := 2 + 4 + 4 + 4 + 4 + 3*4 // version, ctime, mtime, nbyteskeys, nbytesname and seeks
+	if len(buf) < 2 {
+		return nil, false
+	}
+	if vers := int16(binary.BigEndian.Uint16(buf)); vers > 1000 {
+		n += 3 * 4
+	}
+	if len(buf) < n {
+		return nil, false
+	}
+
+	dir := &tdirectoryFile{file: f}
+	err = dir.UnmarshalROOT(rbytes.NewRBuffer(buf, nil, uint32(k.keylen), f))
+	if err != nil || dir.seekdir != k.seekkey {
+		return nil, false
+	}
+
+	dir.dir.named.SetName(k.Name())
+	dir.dir.named.SetTitle(k.Name())
+	dir.classname = k.class
+	dir.seekkeys = 0
+	dir.nbyteskeys = 0
+
+	k.obj = dir
+	return dir, true
+}
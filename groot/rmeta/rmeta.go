@@ -84,6 +84,22 @@ func CxxTemplateFrom(typename string) CxxTemplate {
 	return cxx
 }
 
+// IsCxxBuiltinPair returns whether the provided typename is a std::pair<K,V>
+// of C++ builtins.
+// ROOT does not store streamers for these.
+func IsCxxBuiltinPair(typename string) bool {
+	typename = strings.TrimPrefix(typename, "std::")
+	if !strings.HasPrefix(typename, "pair<") {
+		return false
+	}
+	for _, arg := range CxxTemplateFrom(typename).Args {
+		if _, ok := CxxBuiltins[arg]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
 // TypeName2Enum returns the Enum corresponding to the provided C++ (or Go) typename.
 func TypeName2Enum(typename string) (Enum, bool) {
 	switch typename {
@@ -324,3 +324,24 @@ func TestCxxTemplate(t *testing.T) {
 		})
 	}
 }
+
+func TestIsCxxBuiltinPair(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		want bool
+	}{
+		{"pair<int,int>", true},
+		{"std::pair<int,float>", true},
+		{"pair<unsigned int, double>", true},
+		{"pair<int,TObject>", false},
+		{"pair<int,pair<int,int>>", false},
+		{"map<int,int>", false},
+		{"int", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got, want := rmeta.IsCxxBuiltinPair(tc.name), tc.want; got != want {
+				t.Fatalf("invalid builtin pair: got=%v, want=%v", got, want)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"reflect"
 
+	"go-hep.org/x/hep/groot/internal/rcompress"
 	"go-hep.org/x/hep/groot/rbytes"
 	"go-hep.org/x/hep/groot/riofs"
 	"go-hep.org/x/hep/groot/root"
@@ -36,6 +37,10 @@ type Basket struct {
 	rbuf *rbytes.RBuffer
 	wbuf *rbytes.WBuffer
 
+	sealed bool  // whether the entries offsets have been appended to wbuf
+	dlen   int64 // length of the entries data in wbuf, once sealed
+	big    bool  // whether the basket was sealed for a file with 64b offsets
+
 	branch Branch // basket support branch
 }
 
@@ -306,12 +311,29 @@ func (b *Basket) grow(n int) {
 	b.offsets = append(b.offsets, make([]int32, delta)...)
 }
 
-func (b *Basket) writeFile(f *riofs.File) (totBytes int64, zipBytes int64, err error) {
-	header := b.header
-	b.header = true
-	defer func() {
-		b.header = header
-	}()
+// writeFile serializes, compresses with the provided compression settings
+// and writes the basket to the provided file.
+func (b *Basket) writeFile(f *riofs.File, compress int32) (totBytes int64, zipBytes int64, err error) {
+	zip, err := rcompress.Compress(nil, b.seal(f.IsBigFile()), compress)
+	if err != nil {
+		return 0, 0, fmt.Errorf("rtree: could not compress basket: %w", err)
+	}
+	return b.commit(f, compress, zip)
+}
+
+// seal appends the offsets of the entries to the basket buffer and returns
+// the uncompressed payload of the basket.
+// big indicates whether the basket will be written to a file using 64b offsets.
+// seal can be called multiple times.
+func (b *Basket) seal(big bool) []byte {
+	switch {
+	case b.sealed:
+		b.wbuf.SetPos(b.dlen)
+	default:
+		b.sealed = true
+		b.dlen = b.wbuf.Len()
+	}
+	b.big = big
 
 	// we need to handle the case for a basket being created
 	// while the file was small, and *then* being flushed while
@@ -319,27 +341,53 @@ func (b *Basket) writeFile(f *riofs.File) (totBytes int64, zipBytes int64, err e
 	// ie: the TKey structure switched to 64b offsets, and add an
 	// extra 8bytes.
 	// we need to propagate to the 'offsets' and 'last' fields.
-	adjust := !(b.key.RVersion() > 1000) && f.IsBigFile()
+	adjust := !(b.key.RVersion() > 1000) && big
 
-	b.last = int(int64(b.key.KeyLen()) + b.wbuf.Len())
+	b.last = int(int64(b.key.KeyLen()) + b.dlen)
 	if b.offsets != nil {
+		offsets := b.offsets[:b.nevbuf]
 		if adjust {
-			for i, v := range b.offsets {
-				b.offsets[i] = v + 8
+			offsets = make([]int32, b.nevbuf)
+			for i, v := range b.offsets[:b.nevbuf] {
+				offsets[i] = v + 8
 			}
 		}
 		b.wbuf.WriteI32(int32(b.nevbuf + 1))
-		b.wbuf.WriteArrayI32(b.offsets[:b.nevbuf])
+		b.wbuf.WriteArrayI32(offsets)
 		b.wbuf.WriteI32(0)
 	}
-	b.key, err = riofs.NewKey(nil, b.key.Name(), b.key.Title(), b.Class(), int16(b.key.Cycle()), b.wbuf.Bytes(), f)
-	if err != nil {
-		return 0, 0, fmt.Errorf("rtree: could not create basket-key: %w", err)
-	}
 	if adjust {
 		b.last += 8
 	}
 
+	return b.wbuf.Bytes()
+}
+
+// commit writes the sealed basket, with its payload compressed as zip,
+// at the end of the provided file.
+func (b *Basket) commit(f *riofs.File, compress int32, zip []byte) (totBytes int64, zipBytes int64, err error) {
+	if big := f.IsBigFile(); big != b.big {
+		// the file switched to 64b offsets since the basket was sealed.
+		zip, err = rcompress.Compress(nil, b.seal(big), compress)
+		if err != nil {
+			return 0, 0, fmt.Errorf("rtree: could not compress basket: %w", err)
+		}
+	}
+
+	header := b.header
+	b.header = true
+	defer func() {
+		b.header = header
+	}()
+
+	b.key, err = riofs.NewKeyFromCompressedInternal(
+		nil, b.key.Name(), b.key.Title(), b.Class(), int16(b.key.Cycle()),
+		int32(b.wbuf.Len()), zip, f,
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("rtree: could not create basket-key: %w", err)
+	}
+
 	nbytes := b.key.KeyLen() + b.key.ObjLen()
 	buf := rbytes.NewWBuffer(make([]byte, nbytes), nil, uint32(b.key.KeyLen()), f)
 	_, err = b.MarshalROOT(buf)
@@ -8,14 +8,22 @@ import (
 	"fmt"
 	"io"
 	"runtime"
+	"sync"
 
 	"go-hep.org/x/hep/groot/riofs"
 )
 
+// bkopts configures how baskets are read and decompressed.
+type bkopts struct {
+	n    int // number of read-ahead baskets
+	conc int // number of goroutines decompressing baskets
+}
+
 // bkreader is a read-ahead basket reader.
 type bkreader struct {
 	f     *riofs.File
 	spans []rspan
+	bko   bkopts
 
 	beg    int64         // first event to process
 	end    int64         // last-1 event to process (ie: [beg,end) half-open interval of entries to process)
@@ -23,6 +31,7 @@ type bkreader struct {
 	reuse  chan bkReq    // baskets to reuse for input reading
 	exit   chan struct{} // closes when finished
 	n      int           // number of in-flight baskets
+	conc   int           // number of goroutines decompressing baskets
 	cur    *rbasket      // current buffer being served
 	closed chan struct{} // channel is closed when the async reader shuts down
 
@@ -34,40 +43,58 @@ type bkReq struct {
 	err error
 }
 
-func newBkReader(b Branch, n int, beg, end int64) *bkreader {
+func newBkReader(b Branch, bko bkopts, beg, end int64) *bkreader {
+	n := bko.n
 	if n < 0 {
 		n = runtime.NumCPU() + 1
 	}
 	if n == 0 {
 		n = 1
 	}
+	conc := bko.conc
+	if conc < 0 {
+		conc = runtime.NumCPU()
+	}
+	if conc == 0 {
+		conc = 1
+	}
+	if n < conc {
+		n = conc
+	}
 	base := asBranch(b)
 	if m := len(base.basketSeek); n > m && m != 0 {
 		n = m
 	}
+	if conc > n {
+		conc = n
+	}
 	bkr := &bkreader{
 		f:      b.getTree().f,
 		spans:  make([]rspan, len(base.basketSeek)),
+		bko:    bko,
 		beg:    beg,
 		end:    end,
 		ready:  make(chan bkReq, n),
 		reuse:  make(chan bkReq, n),
 		exit:   make(chan struct{}),
 		n:      n,
+		conc:   conc,
 		closed: make(chan struct{}),
 		name:   b.Name(),
 	}
 
-	if len(base.basketEntry) == len(base.basketSeek) {
+	entries := base.basketEntry
+	if len(entries) == len(base.basketSeek) {
 		// prepare for recover basket mode.
-		base.basketEntry = append(base.basketEntry, 0)
+		// do not modify the branch: it may be shared by concurrent readers.
+		entries = append(entries[:len(entries):len(entries)], 0)
 	} else {
 		for i, seek := range base.basketSeek {
 			bkr.spans[i] = rspan{
 				pos: seek,
 				sz:  base.basketBytes[i],
-				beg: base.basketEntry[i],
-				end: base.basketEntry[i+1],
+				beg: entries[i],
+				end: entries[i+1],
 			}
 		}
 	}
@@ -77,7 +104,7 @@ func newBkReader(b Branch, n int, beg, end int64) *bkreader {
 	}
 
 	switch {
-	case base.entries == base.basketEntry[len(base.basketSeek)]:
+	case base.entries == entries[len(base.basketSeek)]:
 		if len(bkr.spans) == 0 && base.entries == 0 {
 			bkr.spans = append(bkr.spans, rspan{
 				beg: 0,
@@ -122,7 +149,12 @@ func newBkReader(b Branch, n int, beg, end int64) *bkreader {
 		))
 	}
 
-	go bkr.run(base.entryOffsetLen, ibeg, iend)
+	switch {
+	case bkr.conc > 1:
+		go bkr.runConcurrent(base.entryOffsetLen, ibeg, iend)
+	default:
+		go bkr.run(base.entryOffsetLen, ibeg, iend)
+	}
 
 	return bkr
 }
@@ -161,6 +193,68 @@ func (bkr *bkreader) run(eoff, beg, end int) {
 	}
 }
 
+// runConcurrent decompresses baskets with a pool of bkr.conc goroutines,
+// handing them to the reader in order.
+func (bkr *bkreader) runConcurrent(eoff, beg, end int) {
+	defer close(bkr.closed)
+	defer close(bkr.ready)
+
+	type job struct {
+		id   int
+		span rspan
+		tok  bkReq
+		done chan bkReq
+	}
+
+	var (
+		wg      sync.WaitGroup
+		jobs    = make(chan job)
+		pending = make(chan job, bkr.n) // jobs, in order of submission
+		fwd     = make(chan struct{})
+	)
+
+	wg.Add(bkr.conc)
+	for i := 0; i < bkr.conc; i++ {
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				job.tok.err = job.tok.bkt.inflate(bkr.name, job.id, job.span, eoff, bkr.f)
+				job.done <- job.tok
+			}
+		}()
+	}
+
+	go func() {
+		defer close(fwd)
+		for job := range pending {
+			bkr.ready <- <-job.done
+		}
+	}()
+
+	defer func() {
+		close(jobs)
+		wg.Wait()
+		close(pending)
+		<-fwd
+	}()
+
+	for i, span := range bkr.spans[beg:end] {
+		select {
+		case tok := <-bkr.reuse:
+			job := job{
+				id:   beg + i,
+				span: span,
+				tok:  tok,
+				done: make(chan bkReq, 1),
+			}
+			pending <- job
+			jobs <- job
+		case <-bkr.exit:
+			return
+		}
+	}
+}
+
 func (bkr *bkreader) read() (*rbasket, error) {
 	if bkr.cur != nil {
 		bkr.cur.reset()
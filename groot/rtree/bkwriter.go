@@ -0,0 +1,131 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"fmt"
+	"sync"
+
+	"go-hep.org/x/hep/groot/internal/rcompress"
+	"go-hep.org/x/hep/groot/riofs"
+)
+
+// bkwriter compresses baskets with a pool of goroutines.
+//
+// Baskets are written to the file by the goroutine filling the tree,
+// in the order they were submitted, so the layout of the file does not
+// depend on the number of goroutines.
+type bkwriter struct {
+	f       *riofs.File
+	jobs    chan *bkjob // baskets to be compressed
+	pending []*bkjob    // baskets submitted and not yet written, in submission order
+	max     int         // maximum number of pending baskets
+	wg      sync.WaitGroup
+}
+
+type bkjob struct {
+	br  *tbranch
+	id  int // index of the basket in the branch
+	bkt *Basket
+
+	compress int32
+	raw      []byte
+	zip      []byte
+	err      error
+	done     chan struct{} // closed when the basket has been compressed
+}
+
+func newBkWriter(f *riofs.File, n int) *bkwriter {
+	bkw := &bkwriter{
+		f:    f,
+		jobs: make(chan *bkjob, 4*n),
+		max:  4 * n,
+	}
+
+	bkw.wg.Add(n)
+	for i := 0; i < n; i++ {
+		go bkw.run()
+	}
+
+	return bkw
+}
+
+func (bkw *bkwriter) run() {
+	defer bkw.wg.Done()
+	for job := range bkw.jobs {
+		job.zip, job.err = rcompress.Compress(nil, job.raw, job.compress)
+		close(job.done)
+	}
+}
+
+// submit seals the provided basket of branch b and hands it to the pool
+// of compressors.
+// submit then writes all the baskets already compressed, in order,
+// waiting for the oldest ones if too many baskets are in flight.
+func (bkw *bkwriter) submit(b *tbranch, id int, bkt *Basket) error {
+	job := &bkjob{
+		br:       b,
+		id:       id,
+		bkt:      bkt,
+		compress: int32(b.compress),
+		raw:      bkt.seal(bkw.f.IsBigFile()),
+		done:     make(chan struct{}),
+	}
+	bkw.pending = append(bkw.pending, job)
+	bkw.jobs <- job
+
+	for len(bkw.pending) > 0 {
+		if len(bkw.pending) <= bkw.max {
+			select {
+			case <-bkw.pending[0].done:
+			default:
+				return nil
+			}
+		}
+		err := bkw.commit()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// commit waits for the oldest pending basket to be compressed and
+// writes it to file.
+func (bkw *bkwriter) commit() error {
+	job := bkw.pending[0]
+	bkw.pending[0] = nil
+	bkw.pending = bkw.pending[1:]
+
+	<-job.done
+	if job.err != nil {
+		return fmt.Errorf("could not compress basket[%d] (branch=%q): %w", job.id, job.br.Name(), job.err)
+	}
+
+	totBytes, zipBytes, err := job.bkt.commit(bkw.f, job.compress, job.zip)
+	if err != nil {
+		return fmt.Errorf("could not marshal basket[%d] (branch=%q): %w", job.id, job.br.Name(), err)
+	}
+	job.br.addBasket(job.bkt, totBytes, zipBytes)
+
+	return nil
+}
+
+// flush writes all the pending baskets to file.
+func (bkw *bkwriter) flush() error {
+	for len(bkw.pending) > 0 {
+		err := bkw.commit()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// close stops the pool of compressors.
+func (bkw *bkwriter) close() {
+	close(bkw.jobs)
+	bkw.wg.Wait()
+}
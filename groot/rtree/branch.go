@@ -30,6 +30,7 @@ type tbranch struct {
 	attfill        rbase.AttFill
 	compress       int         // compression level and algorithm
 	basketSize     int         // initial size of Basket buffer
+	autoFlush      int64       // number of entries after which the current basket is flushed (writing only)
 	entryOffsetLen int         // initial length of entryOffset table in the basket buffers
 	writeBasket    int         // last basket number written
 	entryNumber    int64       // current entry number (last one filled in this branch)
@@ -67,6 +68,7 @@ func newBranchFromWVar(w *wtree, name string, wvar WriteVar, parent Branch, lvl
 
 		iobits:      w.ttree.iobits,
 		basketSize:  int(cfg.bufsize),
+		autoFlush:   cfg.flush,
 		maxBaskets:  defaultMaxBaskets,
 		basketBytes: make([]int32, 0, defaultMaxBaskets),
 		basketEntry: make([]int64, 1, defaultMaxBaskets),
@@ -719,8 +721,7 @@ func (b *tbranch) write() (int, error) {
 		b.ctx.bk.nevsize = n
 	}
 
-	// FIXME(sbinet): harmonize or drive via "auto-flush" ?
-	if szNew+int64(n) >= int64(b.basketSize) {
+	if b.needFlush(szNew, n) {
 		err = b.flush()
 		if err != nil {
 			return n, fmt.Errorf("could not flush branch (auto-flush): %w", err)
@@ -731,6 +732,19 @@ func (b *tbranch) write() (int, error) {
 	return n, nil
 }
 
+// needFlush returns whether the current basket, holding sz bytes after
+// an entry of n bytes was written, should be flushed.
+func (b *tbranch) needFlush(sz int64, n int) bool {
+	if sz+int64(n) >= int64(b.basketSize) {
+		return true
+	}
+	if b.autoFlush <= 0 {
+		return false
+	}
+	beg := b.basketEntry[len(b.basketEntry)-1]
+	return b.entryNumber-beg >= b.autoFlush
+}
+
 func (b *tbranch) writeToBuffer(w *rbytes.WBuffer) (int, error) {
 	var tot int
 	for i, leaf := range b.leaves {
@@ -751,21 +765,35 @@ func (b *tbranch) flush() error {
 		}
 	}
 
+	var (
+		id  = b.writeBasket
+		bkt = b.ctx.bk
+	)
+	b.basketEntry = append(b.basketEntry, b.entryNumber)
+	b.writeBasket++
+	b.ctx.bk = nil
+
+	if bkw := b.tree.bkw; bkw != nil {
+		return bkw.submit(b, id, bkt)
+	}
+
 	f := b.tree.getFile()
-	totBytes, zipBytes, err := b.ctx.bk.writeFile(f)
+	totBytes, zipBytes, err := bkt.writeFile(f, int32(b.compress))
 	if err != nil {
-		return fmt.Errorf("could not marshal basket[%d] (branch=%q): %w", b.writeBasket, b.Name(), err)
+		return fmt.Errorf("could not marshal basket[%d] (branch=%q): %w", id, b.Name(), err)
 	}
+	b.addBasket(bkt, totBytes, zipBytes)
+
+	return nil
+}
+
+// addBasket records the location of a basket written to file.
+func (b *tbranch) addBasket(bkt *Basket, totBytes, zipBytes int64) {
 	b.totBytes += totBytes
 	b.zipBytes += zipBytes
 
-	b.basketBytes = append(b.basketBytes, b.ctx.bk.key.Nbytes())
-	b.basketEntry = append(b.basketEntry, b.entryNumber)
-	b.basketSeek = append(b.basketSeek, b.ctx.bk.key.SeekKey())
-	b.writeBasket++
-	b.ctx.bk = nil
-
-	return nil
+	b.basketBytes = append(b.basketBytes, bkt.key.Nbytes())
+	b.basketSeek = append(b.basketSeek, bkt.key.SeekKey())
 }
 
 // tbranchObject is a Branch for objects.
@@ -1097,8 +1125,7 @@ func (b *tbranchElement) write() (int, error) {
 		b.ctx.bk.grow(n)
 	}
 
-	// FIXME(sbinet): harmonize or drive via "auto-flush" ?
-	if szNew+int64(n) >= int64(b.basketSize) {
+	if b.needFlush(szNew, n) {
 		err = b.flush()
 		if err != nil {
 			return n, fmt.Errorf("could not flush branch (auto-flush): %w", err)
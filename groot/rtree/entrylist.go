@@ -0,0 +1,549 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"go-hep.org/x/hep/groot/rbase"
+	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/rcont"
+	"go-hep.org/x/hep/groot/riofs"
+	"go-hep.org/x/hep/groot/root"
+	"go-hep.org/x/hep/groot/rtypes"
+	"go-hep.org/x/hep/groot/rvers"
+)
+
+// EntryList is a list of entries of a tree.
+//
+// An entry list for a chain of trees holds one sub-list per tree of
+// the chain, each sub-list holding the local entry numbers of its tree.
+//
+// EntryList is the Go equivalent of ROOT's TEntryList.
+type EntryList struct {
+	named rbase.Named
+
+	lists   []*EntryList // entry lists of the trees of a chain
+	entries []int64      // sorted entry numbers
+	nproc   int64        // number of entries to process (used by PROOF)
+	tree    string       // name of the tree
+	file    string       // name of the file holding the tree
+	reapply bool         // whether TTree::Draw should reapply the original cut
+}
+
+// NewEntryList creates a new, empty, entry list.
+func NewEntryList(name, title string) *EntryList {
+	return &EntryList{
+		named: *rbase.NewNamed(name, title),
+	}
+}
+
+func (*EntryList) RVersion() int16 { return rvers.EntryList }
+func (*EntryList) Class() string   { return "TEntryList" }
+
+// Name returns the name of the entry list.
+func (el *EntryList) Name() string { return el.named.Name() }
+
+// Title returns the title of the entry list.
+func (el *EntryList) Title() string { return el.named.Title() }
+
+// TreeName returns the name of the tree this entry list applies to.
+func (el *EntryList) TreeName() string { return el.tree }
+
+// FileName returns the name of the file holding the tree this entry list
+// applies to.
+func (el *EntryList) FileName() string { return el.file }
+
+// SetTree sets the names of the tree and of the file holding the tree
+// this entry list applies to.
+func (el *EntryList) SetTree(tree, file string) {
+	el.tree = tree
+	el.file = file
+}
+
+// Lists returns the entry lists of the trees of a chain, if any.
+func (el *EntryList) Lists() []*EntryList { return el.lists }
+
+// Len returns the number of entries in the list, including the entries
+// of its sub-lists.
+func (el *EntryList) Len() int64 {
+	n := int64(len(el.entries))
+	for _, sub := range el.lists {
+		n += sub.Len()
+	}
+	return n
+}
+
+// Entries returns the sorted entry numbers held by this list.
+// The entries of the sub-lists are not included.
+func (el *EntryList) Entries() []int64 { return el.entries }
+
+// Contains returns whether the provided entry is held by this list.
+func (el *EntryList) Contains(entry int64) bool {
+	i := sort.Search(len(el.entries), func(i int) bool {
+		return el.entries[i] >= entry
+	})
+	return i < len(el.entries) && el.entries[i] == entry
+}
+
+// Enter adds the provided entry to the list.
+func (el *EntryList) Enter(entry int64) {
+	n := len(el.entries)
+	if n == 0 || el.entries[n-1] < entry {
+		el.entries = append(el.entries, entry)
+		return
+	}
+	i := sort.Search(n, func(i int) bool {
+		return el.entries[i] >= entry
+	})
+	if el.entries[i] == entry {
+		return
+	}
+	el.entries = append(el.entries, 0)
+	copy(el.entries[i+1:], el.entries[i:])
+	el.entries[i] = entry
+}
+
+// EnterTree adds the provided entry of the tree t to the list.
+//
+// If t is a chain, entry is the global entry number in the chain and
+// the corresponding local entry number is added to the sub-list of
+// the tree of the chain holding that entry.
+func (el *EntryList) EnterTree(t Tree, entry int64) error {
+	switch t := t.(type) {
+	case *chain:
+		if len(el.entries) != 0 {
+			return fmt.Errorf("rtree: entry list %q already holds entries of tree %q", el.Name(), el.tree)
+		}
+		i := sort.Search(len(t.tots), func(i int) bool {
+			return t.tots[i] > entry
+		})
+		if entry < 0 || i >= len(t.trees) {
+			return fmt.Errorf("rtree: invalid entry %d for chain with %d entries", entry, t.Entries())
+		}
+		tree := t.trees[i]
+		sub := el.list(tree.Name(), fileNameOf(tree))
+		if sub == nil {
+			sub = NewEntryList(el.Name(), el.Title())
+			sub.SetTree(tree.Name(), fileNameOf(tree))
+			el.lists = append(el.lists, sub)
+		}
+		sub.Enter(entry - t.offs[i])
+		return nil
+
+	default:
+		if entry < 0 || entry >= t.Entries() {
+			return fmt.Errorf("rtree: invalid entry %d for tree with %d entries", entry, t.Entries())
+		}
+		var (
+			name = t.Name()
+			file = fileNameOf(t)
+		)
+		if len(el.lists) == 0 && len(el.entries) == 0 && el.tree == "" && el.file == "" {
+			el.SetTree(name, file)
+		}
+		if len(el.lists) != 0 || !el.matches(name, file) {
+			return fmt.Errorf("rtree: entry list %q does not apply to tree %q", el.Name(), name)
+		}
+		el.Enter(entry)
+		return nil
+	}
+}
+
+// list returns the sub-list applying to the named tree and file.
+func (el *EntryList) list(tree, file string) *EntryList {
+	for _, sub := range el.lists {
+		if sub.matches(tree, file) {
+			return sub
+		}
+	}
+	return nil
+}
+
+// matches returns whether the entry list applies to the named tree and file.
+// An empty file name matches any file.
+func (el *EntryList) matches(tree, file string) bool {
+	if el.tree != tree && path.Base(el.tree) != tree {
+		return false
+	}
+	if el.file == "" || file == "" || el.file == file {
+		return true
+	}
+	return sameFile(el.file, file)
+}
+
+// entriesOf returns the sorted list of entries of the tree t within
+// the [beg, end) range of entries that are held by the entry list.
+//
+// An entry list without sub-lists holds the global entry numbers of t.
+func (el *EntryList) entriesOf(t Tree, beg, end int64) ([]int64, error) {
+	switch t := t.(type) {
+	case *chain:
+		if len(el.lists) == 0 {
+			return appendEntries(nil, el.entries, 0, t.Entries(), beg, end), nil
+		}
+		var entries []int64
+		for i, tree := range t.trees {
+			sub := el.list(tree.Name(), fileNameOf(tree))
+			if sub == nil {
+				continue
+			}
+			entries = appendEntries(entries, sub.entries, t.offs[i], tree.Entries(), beg, end)
+		}
+		return entries, nil
+
+	case *ttree, *tntuple, *tntupleD:
+		sub := el
+		if len(el.lists) != 0 {
+			sub = el.list(t.Name(), fileNameOf(t))
+			if sub == nil {
+				return nil, nil
+			}
+		}
+		return appendEntries(nil, sub.entries, 0, t.Entries(), beg, end), nil
+
+	default:
+		return nil, fmt.Errorf("rtree: entry lists not supported for tree of type %T", t)
+	}
+}
+
+// appendEntries appends to dst the entries of a tree with n entries, shifted
+// by off, and that are within the [beg, end) range.
+func appendEntries(dst, entries []int64, off, n, beg, end int64) []int64 {
+	for _, entry := range entries {
+		if entry < 0 || entry >= n {
+			continue
+		}
+		entry += off
+		if entry < beg || entry >= end {
+			continue
+		}
+		dst = append(dst, entry)
+	}
+	return dst
+}
+
+func fileNameOf(t Tree) string {
+	var f *riofs.File
+	switch t := t.(type) {
+	case *ttree:
+		f = t.f
+	case *tntuple:
+		f = t.f
+	case *tntupleD:
+		f = t.f
+	}
+	if f == nil {
+		return ""
+	}
+	return f.Name()
+}
+
+func sameFile(a, b string) bool {
+	a, err := filepath.Abs(a)
+	if err != nil {
+		return false
+	}
+	b, err = filepath.Abs(b)
+	if err != nil {
+		return false
+	}
+	return a == b
+}
+
+const (
+	entryBlockSize  = 4000                // number of 16b words of an entry list block
+	entryBlockRange = entryBlockSize * 16 // number of entries covered by an entry list block
+)
+
+// blocks returns the entries of the list, packed as ROOT's TEntryListBlocks.
+func (el *EntryList) blocks() []root.Object {
+	if len(el.entries) == 0 {
+		return nil
+	}
+
+	n := int(el.entries[len(el.entries)-1]/entryBlockRange) + 1
+	blocks := make([]root.Object, n)
+	for i := range blocks {
+		beg := sort.Search(len(el.entries), func(j int) bool {
+			return el.entries[j] >= int64(i)*entryBlockRange
+		})
+		end := sort.Search(len(el.entries), func(j int) bool {
+			return el.entries[j] >= int64(i+1)*entryBlockRange
+		})
+		blocks[i] = newEntryBlock(el.entries[beg:end], int64(i)*entryBlockRange)
+	}
+	return blocks
+}
+
+// MarshalROOT implements rbytes.Marshaler
+func (el *EntryList) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	hdr := w.WriteHeader(el.Class(), el.RVersion())
+	w.WriteObject(&el.named)
+	{
+		var obj root.Object
+		if len(el.lists) != 0 {
+			objs := make([]root.Object, len(el.lists))
+			for i, sub := range el.lists {
+				objs[i] = sub
+			}
+			obj = rcont.NewList("", objs)
+		}
+		w.WriteObjectAny(obj)
+	}
+
+	blocks := el.blocks()
+	w.WriteI32(int32(len(blocks)))
+	{
+		var obj root.Object
+		if len(blocks) != 0 {
+			arr := rcont.NewObjArray()
+			arr.SetElems(blocks)
+			obj = arr
+		}
+		w.WriteObjectAny(obj)
+	}
+	w.WriteI64(el.Len())
+	w.WriteI64(el.nproc)
+	w.WriteString(el.tree)
+	w.WriteString(el.file)
+	w.WriteBool(el.reapply)
+
+	return w.SetHeader(hdr)
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (el *EntryList) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	hdr := r.ReadHeader(el.Class())
+	if hdr.Vers > rvers.EntryList {
+		panic(fmt.Errorf(
+			"rtree: invalid %s version=%d > %d",
+			el.Class(), hdr.Vers, el.RVersion(),
+		))
+	}
+
+	r.ReadObject(&el.named)
+
+	el.lists = nil
+	if v := r.ReadObjectAny(); v != nil {
+		lst := v.(*rcont.List)
+		el.lists = make([]*EntryList, 0, lst.Len())
+		for i := 0; i < lst.Len(); i++ {
+			sub, ok := lst.At(i).(*EntryList)
+			if !ok {
+				return fmt.Errorf("rtree: invalid entry list element %T", lst.At(i))
+			}
+			el.lists = append(el.lists, sub)
+		}
+	}
+
+	_ = r.ReadI32() // number of blocks
+
+	el.entries = nil
+	if v := r.ReadObjectAny(); v != nil {
+		arr := v.(*rcont.ObjArray)
+		for i := 0; i < arr.Len(); i++ {
+			obj := arr.At(i)
+			if obj == nil {
+				continue
+			}
+			blk, ok := obj.(*entryBlock)
+			if !ok {
+				return fmt.Errorf("rtree: invalid entry list block %T", obj)
+			}
+			el.entries = blk.appendEntries(el.entries, int64(i)*entryBlockRange)
+		}
+	}
+
+	_ = r.ReadI64() // number of entries, recomputed from the blocks and sub-lists.
+	el.nproc = r.ReadI64()
+	el.tree = r.ReadString()
+	el.file = r.ReadString()
+	if hdr.Vers > 1 {
+		el.reapply = r.ReadBool()
+	}
+
+	r.CheckHeader(hdr)
+	return r.Err()
+}
+
+// entryBlock holds the entries of an entry list within a range of
+// entryBlockRange entries, either as a bit field or as a list of indices.
+//
+// entryBlock is the Go equivalent of ROOT's TEntryListBlock.
+type entryBlock struct {
+	obj rbase.Object
+
+	npassed int32    // number of entries in (passing) or not in (!passing) the list
+	indices []uint16 // bit field or list of indices
+	kind    int32    // 0: bit field, 1: list of indices
+	passing bool     // whether indices hold entries in the list or not
+}
+
+const (
+	entryBlockBits = 0
+	entryBlockList = 1
+)
+
+func newEntryBlock(entries []int64, off int64) *entryBlock {
+	blk := &entryBlock{
+		obj:     *rbase.NewObject(),
+		npassed: int32(len(entries)),
+		passing: true,
+	}
+
+	switch {
+	case len(entries) < entryBlockSize:
+		blk.kind = entryBlockList
+		blk.indices = make([]uint16, len(entries))
+		for i, entry := range entries {
+			blk.indices[i] = uint16(entry - off)
+		}
+	default:
+		blk.kind = entryBlockBits
+		blk.indices = make([]uint16, entryBlockSize)
+		for _, entry := range entries {
+			i := entry - off
+			blk.indices[i>>4] |= 1 << (i & 15)
+		}
+	}
+
+	return blk
+}
+
+func (*entryBlock) RVersion() int16 { return rvers.EntryListBlock }
+func (*entryBlock) Class() string   { return "TEntryListBlock" }
+
+// appendEntries appends the entries of the block, shifted by off, to dst.
+func (blk *entryBlock) appendEntries(dst []int64, off int64) []int64 {
+	switch blk.kind {
+	case entryBlockBits:
+		for i := 0; i < len(blk.indices)*16; i++ {
+			set := blk.indices[i>>4]&(1<<(i&15)) != 0
+			if set == blk.passing {
+				dst = append(dst, off+int64(i))
+			}
+		}
+
+	case entryBlockList:
+		indices := make([]uint16, len(blk.indices))
+		copy(indices, blk.indices)
+		sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+
+		if blk.passing {
+			for i, v := range indices {
+				if i > 0 && v == indices[i-1] {
+					continue
+				}
+				dst = append(dst, off+int64(v))
+			}
+			return dst
+		}
+
+		for i := 0; i < entryBlockRange; i++ {
+			if len(indices) > 0 && int(indices[0]) == i {
+				for len(indices) > 0 && int(indices[0]) == i {
+					indices = indices[1:]
+				}
+				continue
+			}
+			dst = append(dst, off+int64(i))
+		}
+	}
+
+	return dst
+}
+
+// MarshalROOT implements rbytes.Marshaler
+func (blk *entryBlock) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	hdr := w.WriteHeader(blk.Class(), blk.RVersion())
+	w.WriteObject(&blk.obj)
+	w.WriteI32(blk.npassed)
+	w.WriteI32(int32(len(blk.indices)))
+	switch len(blk.indices) {
+	case 0:
+		w.WriteI8(0)
+	default:
+		w.WriteI8(1)
+		w.WriteArrayU16(blk.indices)
+	}
+	w.WriteI32(blk.kind)
+	w.WriteBool(blk.passing)
+
+	return w.SetHeader(hdr)
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (blk *entryBlock) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	hdr := r.ReadHeader(blk.Class())
+	if hdr.Vers > rvers.EntryListBlock {
+		panic(fmt.Errorf(
+			"rtree: invalid %s version=%d > %d",
+			blk.Class(), hdr.Vers, blk.RVersion(),
+		))
+	}
+
+	r.ReadObject(&blk.obj)
+	blk.npassed = r.ReadI32()
+	n := int(r.ReadI32())
+	blk.indices = nil
+	if isArray := r.ReadI8(); isArray != 0 {
+		blk.indices = make([]uint16, n)
+		r.ReadArrayU16(blk.indices)
+	}
+	blk.kind = r.ReadI32()
+	blk.passing = r.ReadBool()
+
+	r.CheckHeader(hdr)
+	return r.Err()
+}
+
+func init() {
+	{
+		f := func() reflect.Value {
+			o := &EntryList{}
+			return reflect.ValueOf(o)
+		}
+		rtypes.Factory.Add("TEntryList", f)
+	}
+	{
+		f := func() reflect.Value {
+			o := &entryBlock{}
+			return reflect.ValueOf(o)
+		}
+		rtypes.Factory.Add("TEntryListBlock", f)
+	}
+}
+
+var (
+	_ root.Object        = (*EntryList)(nil)
+	_ root.Named         = (*EntryList)(nil)
+	_ rbytes.Marshaler   = (*EntryList)(nil)
+	_ rbytes.Unmarshaler = (*EntryList)(nil)
+
+	_ root.Object        = (*entryBlock)(nil)
+	_ rbytes.Marshaler   = (*entryBlock)(nil)
+	_ rbytes.Unmarshaler = (*entryBlock)(nil)
+)
@@ -0,0 +1,376 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-hep.org/x/hep/groot/internal/rtests"
+	"go-hep.org/x/hep/groot/riofs"
+)
+
+func createEntryListTree(t *testing.T, fname string, nevts int) {
+	t.Helper()
+
+	f, err := riofs.Create(fname)
+	if err != nil {
+		t.Fatalf("could not create %q: %+v", fname, err)
+	}
+	defer f.Close()
+
+	var x int64
+	w, err := NewWriter(f, "tree", []WriteVar{{Name: "x", Value: &x}}, WithBasketSize(128))
+	if err != nil {
+		t.Fatalf("could not create tree writer: %+v", err)
+	}
+
+	for i := 0; i < nevts; i++ {
+		x = int64(i)
+		_, err = w.Write()
+		if err != nil {
+			t.Fatalf("could not write entry %d: %+v", i, err)
+		}
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("could not close tree writer: %+v", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		t.Fatalf("could not close file: %+v", err)
+	}
+}
+
+func TestEntryListRW(t *testing.T) {
+	var want []int64
+	for i := int64(0); i < 10000; i++ {
+		want = append(want, i) // dense block, stored as a bit field.
+	}
+	for i := int64(70000); i < 200000; i += 1000 {
+		want = append(want, i) // sparse blocks, stored as lists of indices.
+	}
+
+	elist := NewEntryList("elist", "my entry list")
+	for i := len(want) - 1; i >= 0; i-- {
+		elist.Enter(want[i])
+	}
+	elist.Enter(want[42]) // duplicate entries are ignored.
+	elist.SetTree("tree", "data.root")
+
+	chlist := NewEntryList("chlist", "my chain entry list")
+	chlist.lists = []*EntryList{NewEntryList("chlist", ""), NewEntryList("chlist", "")}
+	chlist.lists[0].SetTree("tree", "f1.root")
+	chlist.lists[0].Enter(2)
+	chlist.lists[0].Enter(1)
+	chlist.lists[1].SetTree("tree", "f2.root")
+	chlist.lists[1].Enter(3)
+
+	fname := filepath.Join(t.TempDir(), "elist.root")
+	{
+		f, err := riofs.Create(fname)
+		if err != nil {
+			t.Fatalf("could not create file: %+v", err)
+		}
+		defer f.Close()
+
+		for _, el := range []*EntryList{elist, chlist} {
+			err = f.Put(el.Name(), el)
+			if err != nil {
+				t.Fatalf("could not write entry list %q: %+v", el.Name(), err)
+			}
+		}
+
+		err = f.Close()
+		if err != nil {
+			t.Fatalf("could not close file: %+v", err)
+		}
+	}
+
+	f, err := riofs.Open(fname)
+	if err != nil {
+		t.Fatalf("could not open file: %+v", err)
+	}
+	defer f.Close()
+
+	o, err := f.Get("elist")
+	if err != nil {
+		t.Fatalf("could not read entry list: %+v", err)
+	}
+	got := o.(*EntryList)
+
+	if got, want := got.Name(), "elist"; got != want {
+		t.Fatalf("invalid name: got=%q, want=%q", got, want)
+	}
+	if got, want := got.Title(), "my entry list"; got != want {
+		t.Fatalf("invalid title: got=%q, want=%q", got, want)
+	}
+	if got, want := got.TreeName(), "tree"; got != want {
+		t.Fatalf("invalid tree name: got=%q, want=%q", got, want)
+	}
+	if got, want := got.FileName(), "data.root"; got != want {
+		t.Fatalf("invalid file name: got=%q, want=%q", got, want)
+	}
+	if got, want := got.Len(), int64(len(want)); got != want {
+		t.Fatalf("invalid length: got=%d, want=%d", got, want)
+	}
+	if !reflect.DeepEqual(got.Entries(), want) {
+		t.Fatalf("invalid entries")
+	}
+	for _, tc := range []struct {
+		entry int64
+		want  bool
+	}{
+		{0, true},
+		{9999, true},
+		{10000, false},
+		{71000, true},
+		{71001, false},
+		{-1, false},
+	} {
+		if got := got.Contains(tc.entry); got != tc.want {
+			t.Fatalf("invalid contains(%d): got=%v, want=%v", tc.entry, got, tc.want)
+		}
+	}
+
+	o, err = f.Get("chlist")
+	if err != nil {
+		t.Fatalf("could not read chain entry list: %+v", err)
+	}
+	got = o.(*EntryList)
+	if got, want := got.Len(), int64(3); got != want {
+		t.Fatalf("invalid length: got=%d, want=%d", got, want)
+	}
+	if got, want := len(got.Lists()), 2; got != want {
+		t.Fatalf("invalid number of sub-lists: got=%d, want=%d", got, want)
+	}
+	for i, want := range [][]int64{{1, 2}, {3}} {
+		sub := got.Lists()[i]
+		if got, want := sub.FileName(), fmt.Sprintf("f%d.root", i+1); got != want {
+			t.Fatalf("invalid sub-list file name: got=%q, want=%q", got, want)
+		}
+		if got := sub.Entries(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("invalid sub-list entries: got=%v, want=%v", got, want)
+		}
+	}
+}
+
+func TestEntryListReader(t *testing.T) {
+	const nevts = 500
+
+	dir := t.TempDir()
+	fnames := []string{
+		filepath.Join(dir, "f1.root"),
+		filepath.Join(dir, "f2.root"),
+	}
+	for _, fname := range fnames {
+		createEntryListTree(t, fname, nevts)
+	}
+
+	sel := func(i int64) bool { return i%7 == 3 || (100 < i && i < 120) }
+
+	// entry list for the chain, with one sub-list per tree.
+	ch, closer, err := ChainOf("tree", fnames...)
+	if err != nil {
+		t.Fatalf("could not create chain: %+v", err)
+	}
+	defer closer()
+
+	elist := NewEntryList("elist", "")
+	for i := int64(0); i < ch.Entries(); i++ {
+		if !sel(i) {
+			continue
+		}
+		err = elist.EnterTree(ch, i)
+		if err != nil {
+			t.Fatalf("could not enter entry %d: %+v", i, err)
+		}
+	}
+	if got, want := len(elist.Lists()), len(fnames); got != want {
+		t.Fatalf("invalid number of sub-lists: got=%d, want=%d", got, want)
+	}
+
+	tree := ch.(*chain).trees[0]
+	err = NewEntryList("elist", "").EnterTree(tree, nevts)
+	if err == nil {
+		t.Fatalf("expected an error (invalid entry)")
+	}
+
+	for _, tc := range []struct {
+		name  string
+		tree  Tree
+		elist *EntryList
+		beg   int64
+		end   int64
+	}{
+		{
+			name:  "chain",
+			tree:  ch,
+			elist: elist,
+			beg:   0,
+			end:   -1,
+		},
+		{
+			name:  "chain-range",
+			tree:  ch,
+			elist: elist,
+			beg:   110,
+			end:   nevts + 50,
+		},
+		{
+			name:  "tree",
+			tree:  tree,
+			elist: elist,
+			beg:   0,
+			end:   -1,
+		},
+		{
+			name: "chain-global",
+			tree: ch,
+			elist: func() *EntryList {
+				el := NewEntryList("global", "")
+				for i := int64(0); i < ch.Entries(); i++ {
+					if sel(i) {
+						el.Enter(i)
+					}
+				}
+				return el
+			}(),
+			beg: 0,
+			end: -1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			end := tc.end
+			if end < 0 {
+				end = tc.tree.Entries()
+			}
+
+			var want []int64
+			for i := tc.beg; i < end; i++ {
+				if sel(i) {
+					want = append(want, i)
+				}
+			}
+
+			var x int64
+			r, err := NewReader(tc.tree, []ReadVar{{Name: "x", Value: &x}},
+				WithRange(tc.beg, tc.end),
+				WithEntryList(tc.elist),
+			)
+			if err != nil {
+				t.Fatalf("could not create reader: %+v", err)
+			}
+			defer r.Close()
+
+			for k := 0; k < 2; k++ {
+				var got []int64
+				err = r.Read(func(ctx RCtx) error {
+					if want := ctx.Entry % nevts; x != want {
+						return fmt.Errorf("invalid x value for entry %d: got=%d, want=%d", ctx.Entry, x, want)
+					}
+					got = append(got, ctx.Entry)
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("could not read tree: %+v", err)
+				}
+
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("invalid entries:\ngot= %v\nwant=%v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEntryListWithROOT(t *testing.T) {
+	if !rtests.HasROOT {
+		t.Skip("ROOT not installed")
+	}
+
+	const nevts = 200
+
+	tmp := t.TempDir()
+	fname := filepath.Join(tmp, "tree.root")
+	createEntryListTree(t, fname, nevts)
+
+	f, err := riofs.Update(fname)
+	if err != nil {
+		t.Fatalf("could not open file: %+v", err)
+	}
+	defer f.Close()
+
+	o, err := f.Get("tree")
+	if err != nil {
+		t.Fatalf("could not get tree: %+v", err)
+	}
+
+	elist := NewEntryList("elist", "")
+	for i := int64(0); i < nevts; i += 3 {
+		err = elist.EnterTree(o.(Tree), i)
+		if err != nil {
+			t.Fatalf("could not enter entry %d: %+v", i, err)
+		}
+	}
+
+	err = f.Put("elist", elist)
+	if err != nil {
+		t.Fatalf("could not write entry list: %+v", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		t.Fatalf("could not close file: %+v", err)
+	}
+
+	code := `#include <iostream>
+#include <fstream>
+#include "TFile.h"
+#include "TEntryList.h"
+
+void elist(const char *fname, const char *oname) {
+	auto f = TFile::Open(fname);
+	auto el = (TEntryList*)f->Get("elist");
+	if (!el) {
+		std::cerr << "could not fetch entry list from file [" << fname << "]\n";
+		exit(1);
+	}
+
+	std::ofstream o(oname);
+	o << el->GetN() << "\n";
+	for (Long64_t i = 0; i < el->GetN(); i++) {
+		o << el->GetEntry(i) << "\n";
+	}
+	o.close();
+}
+`
+	ofile := filepath.Join(tmp, "elist.txt")
+	out, err := rtests.RunCxxROOT("elist", []byte(code), fname, ofile)
+	if err != nil {
+		t.Fatalf("could not run C++ ROOT: %+v\noutput:\n%s", err, out)
+	}
+
+	got, err := os.ReadFile(ofile)
+	if err != nil {
+		t.Fatalf("could not read C++ ROOT output file %q: %+v\noutput:\n%s", ofile, err, out)
+	}
+
+	want := new(strings.Builder)
+	fmt.Fprintf(want, "%d\n", elist.Len())
+	for _, i := range elist.Entries() {
+		fmt.Fprintf(want, "%d\n", i)
+	}
+
+	if got, want := string(got), want.String(); got != want {
+		t.Fatalf("invalid ROOT entry list:\ngot:\n%v\nwant:\n%v\noutput:\n%s", got, want, out)
+	}
+}
@@ -461,6 +461,142 @@ func TestFormulaFunc(t *testing.T) {
 	}
 }
 
+func TestFormulaExpr(t *testing.T) {
+	for _, tc := range []struct {
+		fname string
+		tname string
+		expr  string
+		want  []float64
+		err   error
+	}{
+		{
+			fname: "../testdata/simple.root",
+			tname: "tree",
+			expr:  "one",
+			want:  []float64{1, 2, 3},
+		},
+		{
+			fname: "../testdata/simple.root",
+			tname: "tree",
+			expr:  "one + 100*two",
+			want: []float64{
+				1 + 100*float64(float32(1.1)),
+				2 + 100*float64(float32(2.2)),
+				3 + 100*float64(float32(3.3)),
+			},
+		},
+		{
+			fname: "../testdata/simple.root",
+			tname: "tree",
+			expr:  "sqrt(one*one + 3*3) > 3.5 && abs(-one) < 3",
+			want:  []float64{0, 1, 0},
+		},
+		{
+			fname: "../testdata/simple.root",
+			tname: "tree",
+			expr:  "TMath::Power(one, 2) + one**2 + one^2",
+			want:  []float64{3, 12, 27},
+		},
+		{
+			fname: "../testdata/leaves.root",
+			tname: "tree",
+			expr:  "I32 + F64 + D32 + U8 + !B",
+			want:  []float64{0, 3, 4},
+		},
+		{
+			fname: "../testdata/leaves.root",
+			tname: "tree",
+			expr:  "ArrF64[0] + ArrI32[9] + SliF64[1]",
+			want:  []float64{math.NaN(), math.NaN(), 2},
+		},
+		{
+			fname: "../testdata/leaves.root",
+			tname: "tree",
+			expr:  "N == 2 || min(N, 1) == 0",
+			want:  []float64{1, 0, 1},
+		},
+		{
+			fname: "../testdata/simple.root",
+			tname: "tree",
+			expr:  "ones + 1",
+			err:   fmt.Errorf(`rtree: could not create formula: rtree: could not find all needed ReadVars (missing: [ones])`),
+		},
+		{
+			fname: "../testdata/simple.root",
+			tname: "tree",
+			expr:  "foo(one)",
+			err:   fmt.Errorf(`rtree: could not create formula: rfunc: invalid formula "foo(one)": unknown function "foo"`),
+		},
+		{
+			fname: "../testdata/simple.root",
+			tname: "tree",
+			expr:  "(one + 2",
+			err:   fmt.Errorf(`rtree: could not create formula: rfunc: could not parse formula "(one + 2": unexpected end of expression (want ")")`),
+		},
+		{
+			fname: "../testdata/simple.root",
+			tname: "tree",
+			expr:  "one[0]",
+			err:   fmt.Errorf(`rtree: could not create formula: rtree: could not bind formula to rvars: rfunc: could not compile formula "one[0]": variable "one" can not be indexed`),
+		},
+		{
+			fname: "../testdata/simple.root",
+			tname: "tree",
+			expr:  "three > 1",
+			err:   fmt.Errorf(`rtree: could not create formula: rtree: could not bind formula to rvars: rfunc: argument type 0 (name=three) mismatch: got=*string, want a pointer to a numerical value`),
+		},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			f, err := riofs.Open(tc.fname)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			o, err := riofs.Dir(f).Get(tc.tname)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tree := o.(Tree)
+
+			r, err := NewReader(tree, nil, WithRange(0, 3))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			form, err := r.FormulaExpr(tc.expr)
+			switch {
+			case err != nil && tc.err != nil:
+				if got, want := err.Error(), tc.err.Error(); got != want {
+					t.Fatalf("invalid error.\ngot= %v\nwant=%v", got, want)
+				}
+				return
+			case err != nil && tc.err == nil:
+				t.Fatalf("unexpected error: %+v", err)
+			case err == nil && tc.err != nil:
+				t.Fatalf("expected an error: %v (got=nil)", tc.err)
+			case err == nil && tc.err == nil:
+				// ok.
+			}
+
+			eval := form.Func().(func() float64)
+			err = r.Read(func(ctx RCtx) error {
+				got := eval()
+				want := tc.want[ctx.Entry]
+				if got != want && !(math.IsNaN(got) && math.IsNaN(want)) {
+					return fmt.Errorf("entry[%d]: invalid form-eval: got=%v, want=%v", ctx.Entry, got, want)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("error: %+v", err)
+			}
+		})
+	}
+}
+
 var sumBenchFormulaFunc float64
 
 func BenchmarkFormulaFunc(b *testing.B) {
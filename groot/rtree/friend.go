@@ -0,0 +1,181 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"fmt"
+
+	"go-hep.org/x/hep/groot/root"
+	"go-hep.org/x/hep/groot/rtree/rfunc"
+)
+
+// Friend describes a tree befriended with a main tree, and how
+// the entries of the friend tree are aligned with the ones of the main tree.
+type Friend struct {
+	Tree Tree // friend tree
+
+	// Index aligns the entries of the friend tree with the entries of
+	// the main tree: for each entry of the main tree, the major and minor
+	// expressions of the index are evaluated with the branches of the
+	// main tree and the friend entry with the same (major,minor) values
+	// is loaded.
+	//
+	// If Index is nil, the index attached to the friend tree (if any)
+	// is used. Otherwise, entries are aligned by entry number.
+	Index *Index
+}
+
+type friend struct {
+	main     Tree
+	friends  []Friend
+	branches []Branch
+	leaves   []Leaf
+}
+
+// Friends returns a new Tree that represents the main tree t augmented
+// with the columns of the provided friend trees.
+// The returned tree has the same number of entries than the main tree.
+//
+// When reading the returned tree, the friend variables of a main tree
+// entry without a matching friend entry are set to their zero value.
+//
+// Columns of the main tree take precedence over columns of the friend trees
+// with the same name, and columns of a friend tree take precedence over
+// the ones of the next friend trees.
+//
+// Friends errors out if the main tree does not have the columns needed to
+// evaluate the index of a friend tree.
+func Friends(t Tree, friends ...Friend) (Tree, error) {
+	if t == nil {
+		return nil, fmt.Errorf("rtree: no main tree to befriend")
+	}
+
+	tree := &friend{
+		main:    t,
+		friends: make([]Friend, len(friends)),
+	}
+	copy(tree.friends, friends)
+
+	trees := make([]Tree, 0, 1+len(friends))
+	trees = append(trees, t)
+	for i := range tree.friends {
+		fr := &tree.friends[i]
+		if fr.Tree == nil {
+			return nil, fmt.Errorf("rtree: nil friend tree (index=%d)", i)
+		}
+		if fr.Index == nil {
+			fr.Index = IndexOf(fr.Tree)
+		}
+		if fr.Index != nil {
+			for _, expr := range []string{fr.Index.major, fr.Index.minor} {
+				f, err := rfunc.NewFormulaExpr(expr)
+				if err != nil {
+					return nil, fmt.Errorf("rtree: invalid index expression %q for friend tree %s: %w", expr, fr.Tree.Name(), err)
+				}
+				for _, name := range f.RVars() {
+					if t.Branch(name) == nil {
+						return nil, fmt.Errorf(
+							"rtree: main tree %s has no branch %q needed by index of friend tree %s",
+							t.Name(), name, fr.Tree.Name(),
+						)
+					}
+				}
+			}
+		}
+		trees = append(trees, fr.Tree)
+	}
+
+	var (
+		bset = make(map[string]struct{})
+		lset = make(map[string]struct{})
+	)
+	for _, t := range trees {
+		for _, b := range t.Branches() {
+			if _, dup := bset[b.Name()]; dup {
+				continue
+			}
+			bset[b.Name()] = struct{}{}
+			tree.branches = append(tree.branches, b)
+		}
+		for _, leaf := range t.Leaves() {
+			if _, dup := lset[leaf.Name()]; dup {
+				continue
+			}
+			lset[leaf.Name()] = struct{}{}
+			tree.leaves = append(tree.leaves, leaf)
+		}
+	}
+
+	return tree, nil
+}
+
+// Class returns the ROOT class of the argument.
+func (*friend) Class() string {
+	return "TFriend"
+}
+
+// Name returns the name of the ROOT objet in the argument.
+func (t *friend) Name() string {
+	return t.main.Name()
+}
+
+// Title returns the title of the ROOT object in the argument.
+func (t *friend) Title() string {
+	return t.main.Title()
+}
+
+// Entries returns the total number of entries.
+func (t *friend) Entries() int64 {
+	return t.main.Entries()
+}
+
+// Branches returns the list of branches.
+func (t *friend) Branches() []Branch {
+	return t.branches
+}
+
+// Branch returns the branch whose name is the argument.
+func (t *friend) Branch(name string) Branch {
+	_, b := t.treeOf(name)
+	return b
+}
+
+// Leaves returns direct pointers to individual branch leaves.
+func (t *friend) Leaves() []Leaf {
+	return t.leaves
+}
+
+// Leaf returns the leaf whose name is the argument.
+func (t *friend) Leaf(name string) Leaf {
+	if leaf := t.main.Leaf(name); leaf != nil {
+		return leaf
+	}
+	for _, fr := range t.friends {
+		if leaf := fr.Tree.Leaf(name); leaf != nil {
+			return leaf
+		}
+	}
+	return nil
+}
+
+// treeOf returns the index of the tree holding the named branch
+// (-1 for the main tree) and that branch.
+func (t *friend) treeOf(name string) (int, Branch) {
+	if b := t.main.Branch(name); b != nil {
+		return -1, b
+	}
+	for i, fr := range t.friends {
+		if b := fr.Tree.Branch(name); b != nil {
+			return i, b
+		}
+	}
+	return -1, nil
+}
+
+var (
+	_ root.Object = (*friend)(nil)
+	_ root.Named  = (*friend)(nil)
+	_ Tree        = (*friend)(nil)
+)
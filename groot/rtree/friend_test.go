@@ -0,0 +1,547 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-hep.org/x/hep/groot/internal/rtests"
+	"go-hep.org/x/hep/groot/riofs"
+)
+
+type friendEvent struct {
+	Run int32
+	Evt int64
+	X   float64
+}
+
+type friendScore struct {
+	Run   int32
+	Evt   int64
+	Score float32
+}
+
+func scoreOf(run int32, evt int64) float32 {
+	return float32(run)*1000 + float32(evt)
+}
+
+// createFriendFiles creates a main file with nevts entries (ordered by
+// run and event numbers) and nfiles friend files with score entries,
+// stored in reverse order and without the entries for which skip is true.
+func createFriendFiles(t *testing.T, dir string, nevts, nfiles int, skip func(i int) bool) (string, []string) {
+	t.Helper()
+
+	evtOf := func(i int) (int32, int64) {
+		return int32(1 + i/10), int64(i % 10)
+	}
+
+	create := func(fname, tname string, wvars []WriteVar, n int, fill func(i int) bool, opts ...WriteOption) {
+		f, err := riofs.Create(fname)
+		if err != nil {
+			t.Fatalf("could not create %q: %+v", fname, err)
+		}
+		defer f.Close()
+
+		opts = append([]WriteOption{WithBasketSize(64)}, opts...)
+		w, err := NewWriter(f, tname, wvars, opts...)
+		if err != nil {
+			t.Fatalf("could not create tree writer: %+v", err)
+		}
+
+		for i := 0; i < n; i++ {
+			if !fill(i) {
+				continue
+			}
+			_, err = w.Write()
+			if err != nil {
+				t.Fatalf("could not write entry %d: %+v", i, err)
+			}
+		}
+
+		err = w.Close()
+		if err != nil {
+			t.Fatalf("could not close tree writer: %+v", err)
+		}
+
+		err = f.Close()
+		if err != nil {
+			t.Fatalf("could not close file: %+v", err)
+		}
+	}
+
+	main := filepath.Join(dir, "main.root")
+	{
+		var evt friendEvent
+		wvars := []WriteVar{
+			{Name: "run", Value: &evt.Run},
+			{Name: "evt", Value: &evt.Evt},
+			{Name: "x", Value: &evt.X},
+		}
+		create(main, "tree", wvars, nevts, func(i int) bool {
+			evt.Run, evt.Evt = evtOf(i)
+			evt.X = float64(i)
+			return true
+		})
+	}
+
+	var friends []string
+	for ifile := 0; ifile < nfiles; ifile++ {
+		var evt friendScore
+		wvars := []WriteVar{
+			{Name: "run", Value: &evt.Run},
+			{Name: "evt", Value: &evt.Evt},
+			{Name: "score", Value: &evt.Score},
+		}
+		fname := filepath.Join(dir, fmt.Sprintf("friend-%d.root", ifile))
+		create(fname, "scores", wvars, nevts, func(i int) bool {
+			i = nevts - 1 - i
+			if i%nfiles != ifile || skip(i) {
+				return false
+			}
+			evt.Run, evt.Evt = evtOf(i)
+			evt.Score = scoreOf(evt.Run, evt.Evt)
+			return true
+		}, WithIndex("run", "evt"))
+		friends = append(friends, fname)
+	}
+
+	return main, friends
+}
+
+func TestFriendsIndex(t *testing.T) {
+	const nevts = 200
+	skip := func(i int) bool { return i%7 == 3 }
+
+	main, friends := createFriendFiles(t, t.TempDir(), nevts, 1, skip)
+
+	f, err := riofs.Open(main)
+	if err != nil {
+		t.Fatalf("could not open main file: %+v", err)
+	}
+	defer f.Close()
+
+	o, err := f.Get("tree")
+	if err != nil {
+		t.Fatalf("could not get main tree: %+v", err)
+	}
+	tree := o.(Tree)
+
+	ff, err := riofs.Open(friends[0])
+	if err != nil {
+		t.Fatalf("could not open friend file: %+v", err)
+	}
+	defer ff.Close()
+
+	o, err = ff.Get("scores")
+	if err != nil {
+		t.Fatalf("could not get friend tree: %+v", err)
+	}
+	scores := o.(Tree)
+
+	idx := IndexOf(scores)
+	if idx == nil {
+		t.Fatalf("no index attached to friend tree")
+	}
+	if got, want := idx.Major(), "run"; got != want {
+		t.Fatalf("invalid index major: got=%q, want=%q", got, want)
+	}
+	if got, want := idx.Minor(), "evt"; got != want {
+		t.Fatalf("invalid index minor: got=%q, want=%q", got, want)
+	}
+	if got, want := int64(idx.Len()), scores.Entries(); got != want {
+		t.Fatalf("invalid index length: got=%d, want=%d", got, want)
+	}
+	if got, want := idx.Entry(1, 0), scores.Entries()-1; got != want {
+		t.Fatalf("invalid entry for (1,0): got=%d, want=%d", got, want)
+	}
+	if got, want := idx.Entry(1, 3), int64(-1); got != want {
+		t.Fatalf("invalid entry for missing (1,3): got=%d, want=%d", got, want)
+	}
+
+	_, err = Friends(tree, Friend{Tree: scores, Index: newIndex("srun", "sevt", nil)})
+	if err == nil {
+		t.Fatalf("expected an error (missing index branches in main tree)")
+	}
+
+	sidx, err := NewIndex(scores, "run", "evt")
+	if err != nil {
+		t.Fatalf("could not build index: %+v", err)
+	}
+	if got, want := sidx.Len(), idx.Len(); got != want {
+		t.Fatalf("invalid built index length: got=%d, want=%d", got, want)
+	}
+	for i := range idx.index {
+		if idx.majors[i] != sidx.majors[i] || idx.minors[i] != sidx.minors[i] || idx.index[i] != sidx.index[i] {
+			t.Fatalf("index mismatch at %d", i)
+		}
+	}
+
+	ft, err := Friends(tree, Friend{Tree: scores})
+	if err != nil {
+		t.Fatalf("could not create friend tree: %+v", err)
+	}
+	if got, want := ft.Entries(), tree.Entries(); got != want {
+		t.Fatalf("invalid number of entries: got=%d, want=%d", got, want)
+	}
+	if ft.Branch("score") == nil || ft.Branch("x") == nil {
+		t.Fatalf("missing branches in friend tree")
+	}
+
+	var (
+		x     float64
+		score float32
+	)
+	r, err := NewReader(ft, []ReadVar{
+		{Name: "x", Value: &x},
+		{Name: "score", Value: &score},
+	})
+	if err != nil {
+		t.Fatalf("could not create reader: %+v", err)
+	}
+	defer r.Close()
+
+	n := 0
+	err = r.Read(func(ctx RCtx) error {
+		i := int(ctx.Entry)
+		if got, want := x, float64(i); got != want {
+			return fmt.Errorf("invalid x value for entry %d: got=%v, want=%v", i, got, want)
+		}
+		want := scoreOf(int32(1+i/10), int64(i%10))
+		if skip(i) {
+			want = 0
+		}
+		if score != want {
+			return fmt.Errorf("invalid score for entry %d: got=%v, want=%v", i, score, want)
+		}
+		n++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("could not read friend tree: %+v", err)
+	}
+	if n != nevts {
+		t.Fatalf("invalid number of entries: got=%d, want=%d", n, nevts)
+	}
+}
+
+func TestFriendsChain(t *testing.T) {
+	const nevts = 300
+	skip := func(i int) bool { return i%11 == 5 }
+
+	dir := t.TempDir()
+	main, friends := createFriendFiles(t, dir, nevts, 3, skip)
+
+	tree, closeMain, err := ChainOf("tree", main, main)
+	if err != nil {
+		t.Fatalf("could not create main chain: %+v", err)
+	}
+	defer closeMain()
+
+	scores, closeFriends, err := ChainOf("scores", friends...)
+	if err != nil {
+		t.Fatalf("could not create friend chain: %+v", err)
+	}
+	defer closeFriends()
+
+	idx, err := NewIndex(scores, "run", "evt")
+	if err != nil {
+		t.Fatalf("could not build chain index: %+v", err)
+	}
+
+	ft, err := Friends(tree, Friend{Tree: scores, Index: idx})
+	if err != nil {
+		t.Fatalf("could not create friend tree: %+v", err)
+	}
+
+	var (
+		run   int32
+		evt   int64
+		score float32
+	)
+	r, err := NewReader(ft, []ReadVar{
+		{Name: "run", Value: &run},
+		{Name: "evt", Value: &evt},
+		{Name: "score", Value: &score},
+	}, WithRange(50, 2*nevts-20))
+	if err != nil {
+		t.Fatalf("could not create reader: %+v", err)
+	}
+	defer r.Close()
+
+	n := 0
+	err = r.Read(func(ctx RCtx) error {
+		i := int(ctx.Entry) % nevts
+		if run != int32(1+i/10) || evt != int64(i%10) {
+			return fmt.Errorf("invalid (run,evt) for entry %d: got=(%d,%d)", ctx.Entry, run, evt)
+		}
+		want := scoreOf(run, evt)
+		if skip(i) {
+			want = 0
+		}
+		if score != want {
+			return fmt.Errorf("invalid score for entry %d: got=%v, want=%v", ctx.Entry, score, want)
+		}
+		n++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("could not read friend tree: %+v", err)
+	}
+	if got, want := n, 2*nevts-20-50; got != want {
+		t.Fatalf("invalid number of entries: got=%d, want=%d", got, want)
+	}
+}
+
+func TestFriendsPositional(t *testing.T) {
+	const nevts = 100
+	skip := func(i int) bool { return i >= 60 }
+
+	main, friends := createFriendFiles(t, t.TempDir(), nevts, 1, skip)
+
+	tree, closeMain, err := ChainOf("tree", main)
+	if err != nil {
+		t.Fatalf("could not open main tree: %+v", err)
+	}
+	defer closeMain()
+
+	f, err := riofs.Open(friends[0])
+	if err != nil {
+		t.Fatalf("could not open friend file: %+v", err)
+	}
+	defer f.Close()
+	o, err := f.Get("scores")
+	if err != nil {
+		t.Fatalf("could not get friend tree: %+v", err)
+	}
+	scores := o.(*ttree)
+	scores.treeIndex = nil // force positional alignment.
+
+	ft, err := Friends(tree, Friend{Tree: scores})
+	if err != nil {
+		t.Fatalf("could not create friend tree: %+v", err)
+	}
+
+	var (
+		x     float64
+		score float32
+	)
+	r, err := NewReader(ft, []ReadVar{
+		{Name: "x", Value: &x},
+		{Name: "score", Value: &score},
+	})
+	if err != nil {
+		t.Fatalf("could not create reader: %+v", err)
+	}
+	defer r.Close()
+
+	err = r.Read(func(ctx RCtx) error {
+		i := int(ctx.Entry)
+		// friend entries are stored in reverse order: 59, 58, ..., 0.
+		want := float32(0)
+		if i < 60 {
+			j := 59 - i
+			want = scoreOf(int32(1+j/10), int64(j%10))
+		}
+		if score != want {
+			return fmt.Errorf("invalid score for entry %d: got=%v, want=%v", i, score, want)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("could not read friend tree: %+v", err)
+	}
+}
+
+func TestIndexWithROOT(t *testing.T) {
+	if !rtests.HasROOT {
+		t.Skip("ROOT not installed")
+	}
+
+	const nevts = 200
+	skip := func(i int) bool { return i%7 == 3 }
+
+	tmp := t.TempDir()
+	main, friends := createFriendFiles(t, tmp, nevts, 1, skip)
+
+	t.Run("go-to-root", func(t *testing.T) {
+		f, err := riofs.Open(friends[0])
+		if err != nil {
+			t.Fatalf("could not open friend file: %+v", err)
+		}
+		defer f.Close()
+
+		o, err := f.Get("scores")
+		if err != nil {
+			t.Fatalf("could not get friend tree: %+v", err)
+		}
+		idx := IndexOf(o.(Tree))
+		if idx == nil {
+			t.Fatalf("no index attached to friend tree")
+		}
+
+		code := `#include <iostream>
+#include <fstream>
+#include "TFile.h"
+#include "TTree.h"
+#include "TVirtualIndex.h"
+
+void index(const char *fname, const char *oname, int nruns) {
+	auto f = TFile::Open(fname);
+	auto t = (TTree*)f->Get("scores");
+	auto idx = t->GetTreeIndex();
+	if (!idx) {
+		std::cerr << "could not fetch tree index from file [" << fname << "]\n";
+		exit(1);
+	}
+
+	std::ofstream o(oname);
+	o << idx->GetN() << " " << idx->GetMajorName() << " " << idx->GetMinorName() << "\n";
+	for (int run = 1; run <= nruns; run++) {
+		for (int evt = 0; evt < 10; evt++) {
+			o << run << " " << evt << " " << t->GetEntryNumberWithIndex(run, evt) << "\n";
+		}
+	}
+	o.close();
+}
+`
+		ofile := filepath.Join(tmp, "index-go.txt")
+		out, err := rtests.RunCxxROOT("index", []byte(code), friends[0], ofile, nevts/10)
+		if err != nil {
+			t.Fatalf("could not run C++ ROOT: %+v\noutput:\n%s", err, out)
+		}
+
+		got, err := os.ReadFile(ofile)
+		if err != nil {
+			t.Fatalf("could not read C++ ROOT output file %q: %+v\noutput:\n%s", ofile, err, out)
+		}
+
+		want := new(strings.Builder)
+		fmt.Fprintf(want, "%d %s %s\n", idx.Len(), idx.Major(), idx.Minor())
+		for run := int64(1); run <= nevts/10; run++ {
+			for evt := int64(0); evt < 10; evt++ {
+				fmt.Fprintf(want, "%d %d %d\n", run, evt, idx.Entry(run, evt))
+			}
+		}
+
+		if got, want := string(got), want.String(); got != want {
+			t.Fatalf("invalid ROOT tree index:\ngot:\n%v\nwant:\n%v\noutput:\n%s", got, want, out)
+		}
+	})
+
+	t.Run("root-to-go", func(t *testing.T) {
+		code := `#include "TFile.h"
+#include "TTree.h"
+
+void index(const char *fname, int nevts) {
+	auto f = TFile::Open(fname, "RECREATE");
+	auto t = new TTree("scores", "scores");
+
+	Int_t run;
+	Long64_t evt;
+	Float_t score;
+	t->Branch("run", &run);
+	t->Branch("evt", &evt);
+	t->Branch("score", &score);
+
+	for (int i = nevts-1; i >= 0; i--) {
+		if (i%7 == 3) {
+			continue;
+		}
+		run = 1 + i/10;
+		evt = i%10;
+		score = Float_t(run)*1000 + Float_t(evt);
+		t->Fill();
+	}
+	t->BuildIndex("run", "evt");
+
+	f->Write();
+	f->Close();
+}
+`
+		fname := filepath.Join(tmp, "friend-root.root")
+		out, err := rtests.RunCxxROOT("index", []byte(code), fname, nevts)
+		if err != nil {
+			t.Fatalf("could not run C++ ROOT: %+v\noutput:\n%s", err, out)
+		}
+
+		f, err := riofs.Open(fname)
+		if err != nil {
+			t.Fatalf("could not open ROOT file: %+v", err)
+		}
+		defer f.Close()
+
+		o, err := f.Get("scores")
+		if err != nil {
+			t.Fatalf("could not get ROOT tree: %+v", err)
+		}
+		scores := o.(Tree)
+
+		idx := IndexOf(scores)
+		if idx == nil {
+			t.Fatalf("no index attached to ROOT tree")
+		}
+		if got, want := idx.Major(), "run"; got != want {
+			t.Fatalf("invalid index major: got=%q, want=%q", got, want)
+		}
+		if got, want := idx.Minor(), "evt"; got != want {
+			t.Fatalf("invalid index minor: got=%q, want=%q", got, want)
+		}
+		if got, want := int64(idx.Len()), scores.Entries(); got != want {
+			t.Fatalf("invalid index length: got=%d, want=%d", got, want)
+		}
+
+		sidx, err := NewIndex(scores, "run", "evt")
+		if err != nil {
+			t.Fatalf("could not build index: %+v", err)
+		}
+		for i := range idx.index {
+			if idx.majors[i] != sidx.majors[i] || idx.minors[i] != sidx.minors[i] || idx.index[i] != sidx.index[i] {
+				t.Fatalf("index mismatch at %d", i)
+			}
+		}
+
+		mf, err := riofs.Open(main)
+		if err != nil {
+			t.Fatalf("could not open main file: %+v", err)
+		}
+		defer mf.Close()
+
+		o, err = mf.Get("tree")
+		if err != nil {
+			t.Fatalf("could not get main tree: %+v", err)
+		}
+
+		ft, err := Friends(o.(Tree), Friend{Tree: scores})
+		if err != nil {
+			t.Fatalf("could not create friend tree: %+v", err)
+		}
+
+		var score float32
+		r, err := NewReader(ft, []ReadVar{{Name: "score", Value: &score}})
+		if err != nil {
+			t.Fatalf("could not create reader: %+v", err)
+		}
+		defer r.Close()
+
+		err = r.Read(func(ctx RCtx) error {
+			i := int(ctx.Entry)
+			want := scoreOf(int32(1+i/10), int64(i%10))
+			if skip(i) {
+				want = 0
+			}
+			if score != want {
+				return fmt.Errorf("invalid score for entry %d: got=%v, want=%v", i, score, want)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("could not read friend tree: %+v", err)
+		}
+	})
+}
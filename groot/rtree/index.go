@@ -0,0 +1,359 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"go-hep.org/x/hep/groot/rbase"
+	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/root"
+	"go-hep.org/x/hep/groot/rtree/rfunc"
+	"go-hep.org/x/hep/groot/rtypes"
+	"go-hep.org/x/hep/groot/rvers"
+)
+
+// Index is a sorted index of the entries of a tree, keyed by
+// a pair of (major,minor) values.
+// The major and minor values are computed from the major and minor
+// expressions, evaluated for each entry of the indexed tree.
+//
+// Index is the Go equivalent of ROOT's TTreeIndex.
+type Index struct {
+	named rbase.Named
+
+	major  string  // expression for the major value (e.g. "run")
+	minor  string  // expression for the minor value (e.g. "event")
+	majors []int64 // sorted major values
+	minors []int64 // sorted minor values
+	index  []int64 // entry numbers, sorted by (major,minor) values
+}
+
+// NewIndex creates a new index for the provided tree, from the major
+// and minor expressions.
+// The syntax of the expressions is described in rfunc.NewFormulaExpr.
+// An empty minor expression is equivalent to "0".
+func NewIndex(t Tree, major, minor string) (*Index, error) {
+	if minor == "" {
+		minor = "0"
+	}
+
+	r, err := NewReader(t, nil)
+	if err != nil {
+		return nil, fmt.Errorf("rtree: could not create reader: %w", err)
+	}
+	defer r.Close()
+
+	fmaj, err := r.FormulaExpr(major)
+	if err != nil {
+		return nil, fmt.Errorf("rtree: could not create major formula %q: %w", major, err)
+	}
+	fmin, err := r.FormulaExpr(minor)
+	if err != nil {
+		return nil, fmt.Errorf("rtree: could not create minor formula %q: %w", minor, err)
+	}
+
+	var (
+		n    = t.Entries()
+		kmaj = fmaj.Func().(func() float64)
+		kmin = fmin.Func().(func() float64)
+		keys = make([]indexKey, 0, n)
+	)
+	err = r.Read(func(ctx RCtx) error {
+		keys = append(keys, indexKey{
+			major: int64(kmaj()),
+			minor: int64(kmin()),
+			entry: ctx.Entry,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("rtree: could not build index: %w", err)
+	}
+
+	return newIndex(major, minor, keys), nil
+}
+
+type indexKey struct {
+	major int64
+	minor int64
+	entry int64
+}
+
+func newIndex(major, minor string, keys []indexKey) *Index {
+	sort.SliceStable(keys, func(i, j int) bool {
+		ki := keys[i]
+		kj := keys[j]
+		if ki.major != kj.major {
+			return ki.major < kj.major
+		}
+		return ki.minor < kj.minor
+	})
+
+	idx := &Index{
+		named:  *rbase.NewNamed("", ""),
+		major:  major,
+		minor:  minor,
+		majors: make([]int64, len(keys)),
+		minors: make([]int64, len(keys)),
+		index:  make([]int64, len(keys)),
+	}
+	for i, k := range keys {
+		idx.majors[i] = k.major
+		idx.minors[i] = k.minor
+		idx.index[i] = k.entry
+	}
+	return idx
+}
+
+// IndexOf returns the index attached to the provided tree, if any.
+func IndexOf(t Tree) *Index {
+	var tree *ttree
+	switch t := t.(type) {
+	case *ttree:
+		tree = t
+	case *tntuple:
+		tree = &t.ttree
+	case *tntupleD:
+		tree = &t.ttree
+	case *wtree:
+		tree = &t.ttree
+	default:
+		return nil
+	}
+	idx, _ := tree.treeIndex.(*Index)
+	return idx
+}
+
+func (*Index) RVersion() int16 { return rvers.TreeIndex }
+func (*Index) Class() string   { return "TTreeIndex" }
+
+// Name returns the name of the index.
+func (idx *Index) Name() string { return idx.named.Name() }
+
+// Title returns the title of the index.
+func (idx *Index) Title() string { return idx.named.Title() }
+
+// Major returns the expression used to compute the major values of the index.
+func (idx *Index) Major() string { return idx.major }
+
+// Minor returns the expression used to compute the minor values of the index.
+func (idx *Index) Minor() string { return idx.minor }
+
+// Len returns the number of entries in the index.
+func (idx *Index) Len() int { return len(idx.index) }
+
+// Entry returns the tree entry number corresponding to the provided
+// (major,minor) pair of values.
+// Entry returns -1 if no such entry exists.
+func (idx *Index) Entry(major, minor int64) int64 {
+	i := sort.Search(len(idx.index), func(i int) bool {
+		if idx.majors[i] != major {
+			return idx.majors[i] > major
+		}
+		return idx.minors[i] >= minor
+	})
+	if i < len(idx.index) && idx.majors[i] == major && idx.minors[i] == minor {
+		return idx.index[i]
+	}
+	return -1
+}
+
+// MarshalROOT implements rbytes.Marshaler
+func (idx *Index) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	hdr := w.WriteHeader(idx.Class(), idx.RVersion())
+	{
+		hdr := w.WriteHeader("TVirtualIndex", rvers.VirtualIndex)
+		w.WriteObject(&idx.named)
+		_, _ = w.SetHeader(hdr)
+	}
+	w.WriteString(idx.major)
+	w.WriteString(idx.minor)
+	w.WriteI64(int64(len(idx.index)))
+	w.WriteI8(1) // is-array
+	w.WriteArrayI64(idx.majors)
+	w.WriteI8(1) // is-array
+	w.WriteArrayI64(idx.minors)
+	w.WriteI8(1) // is-array
+	w.WriteArrayI64(idx.index)
+
+	return w.SetHeader(hdr)
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (idx *Index) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	hdr := r.ReadHeader(idx.Class())
+	if hdr.Vers > rvers.TreeIndex {
+		panic(fmt.Errorf(
+			"rtree: invalid %s version=%d > %d",
+			idx.Class(), hdr.Vers, idx.RVersion(),
+		))
+	}
+	{
+		hdr := r.ReadHeader("TVirtualIndex")
+		r.ReadObject(&idx.named)
+		r.CheckHeader(hdr)
+	}
+	idx.major = r.ReadString()
+	idx.minor = r.ReadString()
+
+	n := int(r.ReadI64())
+	// arrays are preceded by a flag, 0 for a nil array.
+	readArray := func() []int64 {
+		if r.ReadI8() == 0 {
+			return nil
+		}
+		vs := make([]int64, n)
+		r.ReadArrayI64(vs)
+		return vs
+	}
+
+	idx.majors = readArray()
+	switch {
+	case hdr.Vers > 1:
+		idx.minors = readArray()
+	default:
+		// old TTreeIndex versions packed (major,minor) as major<<31 + minor.
+		const mask = 1<<31 - 1
+		idx.minors = make([]int64, len(idx.majors))
+		for i, v := range idx.majors {
+			idx.majors[i] = v >> 31
+			idx.minors[i] = v & mask
+		}
+	}
+	idx.index = readArray()
+
+	if r.Err() == nil && (len(idx.majors) != len(idx.index) || len(idx.minors) != len(idx.index)) {
+		r.SetErr(fmt.Errorf(
+			"rtree: inconsistent %s arrays (majors=%d, minors=%d, index=%d)",
+			idx.Class(), len(idx.majors), len(idx.minors), len(idx.index),
+		))
+		return r.Err()
+	}
+
+	r.CheckHeader(hdr)
+	return r.Err()
+}
+
+// bindIndexKey binds the major and minor expressions of the provided index
+// to the read-variables of a tree, loading the needed branches of that tree
+// when not already part of the read-variables.
+func bindIndexKey(t Tree, idx *Index, rvars []ReadVar) (kmaj, kmin func() float64, _ []ReadVar, err error) {
+	lookup := func(name string) (interface{}, bool) {
+		for _, rv := range rvars {
+			if rv.Name == name {
+				return rv.Value, true
+			}
+		}
+		for _, rv := range NewReadVars(t) {
+			if rv.Name == name {
+				rvars = append(rvars, rv)
+				return rv.Value, true
+			}
+		}
+		return nil, false
+	}
+
+	kmaj, err = bindIndexExpr(idx.major, lookup)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("rtree: could not bind major index expression %q: %w", idx.major, err)
+	}
+	kmin, err = bindIndexExpr(idx.minor, lookup)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("rtree: could not bind minor index expression %q: %w", idx.minor, err)
+	}
+	return kmaj, kmin, rvars, nil
+}
+
+// bindIndexExpr compiles the provided index expression, binding its
+// variables to the values returned by lookup.
+func bindIndexExpr(expr string, lookup func(name string) (interface{}, bool)) (func() float64, error) {
+	f, err := rfunc.NewFormulaExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]interface{}, len(f.RVars()))
+	for i, name := range f.RVars() {
+		v, ok := lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("rtree: no variable named %q", name)
+		}
+		args[i] = v
+	}
+	err = f.Bind(args)
+	if err != nil {
+		return nil, err
+	}
+	return f.Func().(func() float64), nil
+}
+
+// windex collects the (major,minor) keys of the entries of a tree being written.
+type windex struct {
+	major string
+	minor string
+	kmaj  func() float64
+	kmin  func() float64
+	keys  []indexKey
+}
+
+func newWIndex(major, minor string, wvars []WriteVar) (*windex, error) {
+	lookup := func(name string) (interface{}, bool) {
+		for _, wv := range wvars {
+			if wv.Name == name {
+				return wv.Value, true
+			}
+		}
+		return nil, false
+	}
+
+	var (
+		idx = &windex{major: major, minor: minor}
+		err error
+	)
+	idx.kmaj, err = bindIndexExpr(major, lookup)
+	if err != nil {
+		return nil, fmt.Errorf("rtree: could not bind major index expression %q: %w", major, err)
+	}
+	idx.kmin, err = bindIndexExpr(minor, lookup)
+	if err != nil {
+		return nil, fmt.Errorf("rtree: could not bind minor index expression %q: %w", minor, err)
+	}
+	return idx, nil
+}
+
+func (idx *windex) add(entry int64) {
+	idx.keys = append(idx.keys, indexKey{
+		major: int64(idx.kmaj()),
+		minor: int64(idx.kmin()),
+		entry: entry,
+	})
+}
+
+func init() {
+	{
+		f := func() reflect.Value {
+			o := &Index{}
+			return reflect.ValueOf(o)
+		}
+		rtypes.Factory.Add("TTreeIndex", f)
+	}
+}
+
+var (
+	_ root.Object        = (*Index)(nil)
+	_ root.Named         = (*Index)(nil)
+	_ rbytes.Marshaler   = (*Index)(nil)
+	_ rbytes.Unmarshaler = (*Index)(nil)
+)
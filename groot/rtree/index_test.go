@@ -0,0 +1,69 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"go-hep.org/x/hep/groot/rbase"
+	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/rdict"
+)
+
+func TestIndexStreamer(t *testing.T) {
+	idx := &Index{
+		named:  *rbase.NewNamed("", ""),
+		major:  "run",
+		minor:  "evt",
+		majors: []int64{1, 1, 1, 2, 2},
+		minors: []int64{1, 2, 5, 1, 3},
+		index:  []int64{4, 0, 1, 3, 2},
+	}
+
+	wbuf := rbytes.NewWBuffer(nil, nil, 0, nil)
+	_, err := idx.MarshalROOT(wbuf)
+	if err != nil {
+		t.Fatalf("could not marshal index: %+v", err)
+	}
+
+	// decode and re-encode the index with its StreamerInfo, the way ROOT does.
+	// the StreamerInfo is renamed so its elements are decoded into a generic
+	// Go type rather than into the Index type.
+	si, err := rdict.StreamerInfos.StreamerInfo(idx.Class(), int(idx.RVersion()))
+	if err != nil {
+		t.Fatalf("could not find streamer info: %+v", err)
+	}
+	si = rdict.NewStreamerInfo("TTreeIndexGeneric", si.ClassVersion(), si.Elements())
+
+	obj := rdict.ObjectFrom(si, rdict.StreamerInfos)
+	rbuf := rbytes.NewRBuffer(wbuf.Bytes(), nil, 0, nil)
+	err = obj.UnmarshalROOT(rbuf)
+	if err != nil {
+		t.Fatalf("could not decode index with its streamer info: %+v", err)
+	}
+	if n := rbuf.Len(); n != 0 {
+		t.Fatalf("streamer info left %d bytes undecoded", n)
+	}
+
+	want := rbytes.NewWBuffer(nil, nil, 0, nil)
+	_, err = obj.MarshalROOT(want)
+	if err != nil {
+		t.Fatalf("could not encode index with its streamer info: %+v", err)
+	}
+	if !bytes.Equal(wbuf.Bytes(), want.Bytes()) {
+		t.Fatalf("invalid index encoding:\ngot= %v\nwant=%v", wbuf.Bytes(), want.Bytes())
+	}
+
+	var got Index
+	err = got.UnmarshalROOT(rbytes.NewRBuffer(want.Bytes(), nil, 0, nil))
+	if err != nil {
+		t.Fatalf("could not unmarshal index: %+v", err)
+	}
+	if !reflect.DeepEqual(&got, idx) {
+		t.Fatalf("invalid index round-trip:\ngot= %#v\nwant=%#v", &got, idx)
+	}
+}
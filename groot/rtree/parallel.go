@@ -0,0 +1,259 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Worker describes the work done by one goroutine of a parallel event loop.
+type Worker struct {
+	// RVars are the read-variables into which data is read for this worker.
+	RVars []ReadVar
+
+	// Read is called for each entry read by this worker.
+	Read func(ctx RCtx) error
+
+	// Reduce is called once all the workers are done, if not nil.
+	// Reduce functions are called sequentially, in worker order, so
+	// they can merge the state of their worker into a shared result
+	// without synchronization.
+	Reduce func() error
+}
+
+// ReadParallel reads data from the underlying tree over the whole specified
+// range, with n goroutines.
+// If n is negative or zero, runtime.NumCPU() goroutines are used.
+//
+// ReadParallel calls newWorker, sequentially, for each of the n goroutines
+// (with id in [0, n)) to create the read-variables and the functions of that
+// goroutine.
+// The range of entries is split in sub-ranges, aligned with the clusters of
+// baskets of the tree when possible, that are dispatched to the goroutines.
+// Within a sub-range, entries are read in order.
+//
+// The read-variables and formulas of the Reader are not used by ReadParallel.
+func (r *Reader) ReadParallel(n int, newWorker func(id int) (Worker, error)) error {
+	if n <= 0 {
+		n = runtime.NumCPU()
+	}
+
+	workers := make([]Worker, n)
+	for i := range workers {
+		w, err := newWorker(i)
+		if err != nil {
+			return fmt.Errorf("rtree: could not create worker %d: %w", i, err)
+		}
+		w.RVars, err = sanitizeRVars(r.tree, w.RVars)
+		if err != nil {
+			return fmt.Errorf("rtree: could not create worker %d: %w", i, err)
+		}
+		workers[i] = w
+	}
+
+	var (
+		ranges = clusterRanges(r.tree, workers[0].RVars, r.beg, r.end, 4*n)
+		queue  = make(chan [2]int64, len(ranges))
+		mu     sync.Mutex // serializes the creation of the per-range readers
+	)
+
+	for _, rng := range ranges {
+		queue <- rng
+	}
+	close(queue)
+
+	grp, ctx := errgroup.WithContext(context.Background())
+	for i := range workers {
+		w := workers[i]
+		grp.Go(func() error {
+			for rng := range queue {
+				select {
+				case <-ctx.Done():
+					return nil
+				default:
+				}
+
+				mu.Lock()
+				rr, ok, err := r.rangeScanner(w.RVars, rng[0], rng[1])
+				mu.Unlock()
+				if err != nil {
+					return err
+				}
+				if !ok {
+					continue
+				}
+
+				err = rr.run(0, rng[0], rng[1], w.Read)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	err := grp.Wait()
+	if err != nil {
+		return err
+	}
+
+	for i, w := range workers {
+		if w.Reduce == nil {
+			continue
+		}
+		err := w.Reduce()
+		if err != nil {
+			return fmt.Errorf("rtree: could not reduce worker %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// rangeScanner creates a reader over the [beg, end) range of entries.
+// rangeScanner returns false if there is no entry to read in that range.
+func (r *Reader) rangeScanner(rvars []ReadVar, beg, end int64) (reader, bool, error) {
+	if r.elist == nil {
+		rr, err := newReader(r.tree, rvars, r.bko, beg, end)
+		if err != nil {
+			return nil, false, fmt.Errorf("rtree: could not create reader for [%d, %d): %w", beg, end, err)
+		}
+		return rr, true, nil
+	}
+
+	var (
+		ibeg = sort.Search(len(r.entries), func(i int) bool { return r.entries[i] >= beg })
+		iend = sort.Search(len(r.entries), func(i int) bool { return r.entries[i] >= end })
+	)
+	if ibeg == iend {
+		return nil, false, nil
+	}
+	rr, err := newREntries(r.tree, rvars, r.bko, r.entries[ibeg:iend])
+	if err != nil {
+		return nil, false, fmt.Errorf("rtree: could not create reader for [%d, %d): %w", beg, end, err)
+	}
+	return rr, true, nil
+}
+
+// clusterRanges splits the [beg, end) range of entries of the provided tree
+// into about n sub-ranges, aligned with the clusters of baskets of the
+// branches needed by the read-variables.
+func clusterRanges(t Tree, rvars []ReadVar, beg, end int64, n int) [][2]int64 {
+	if beg >= end {
+		return nil
+	}
+
+	size := (end - beg + int64(n) - 1) / int64(n)
+
+	var bounds []int64
+	switch t.(type) {
+	case *ttree, *tntuple, *tntupleD, *chain:
+		bounds = clusterBounds(t, rvars)
+	default:
+		// no clusters information: split evenly.
+		for i := beg + size; i < end; i += size {
+			bounds = append(bounds, i)
+		}
+	}
+
+	var (
+		ranges = make([][2]int64, 0, n)
+		cur    = beg
+	)
+	for _, b := range bounds {
+		if b <= cur || b >= end {
+			continue
+		}
+		if b-cur < size {
+			continue
+		}
+		ranges = append(ranges, [2]int64{cur, b})
+		cur = b
+	}
+	ranges = append(ranges, [2]int64{cur, end})
+
+	return ranges
+}
+
+// clusterBounds returns the sorted list of entries at which all the baskets
+// of the branches needed by the read-variables start.
+func clusterBounds(t Tree, rvars []ReadVar) []int64 {
+	switch t := t.(type) {
+	case *ttree:
+		return t.clusterBounds(rvars)
+	case *tntuple:
+		return t.ttree.clusterBounds(rvars)
+	case *tntupleD:
+		return t.ttree.clusterBounds(rvars)
+	case *chain:
+		var bounds []int64
+		for i, tree := range t.trees {
+			off := t.offs[i]
+			if off > 0 {
+				bounds = append(bounds, off)
+			}
+			for _, b := range clusterBounds(tree, rvars) {
+				bounds = append(bounds, off+b)
+			}
+		}
+		return bounds
+	}
+	return nil
+}
+
+func (t *ttree) clusterBounds(rvars []ReadVar) []int64 {
+	var (
+		bounds map[int64]int
+		nbrs   int
+	)
+
+	var visit func(b Branch)
+	visit = func(b Branch) {
+		for _, sub := range b.Branches() {
+			visit(sub)
+		}
+		base := asBranch(b)
+		if len(base.basketSeek) == 0 {
+			return
+		}
+		nbrs++
+		if bounds == nil {
+			bounds = make(map[int64]int)
+		}
+		for _, entry := range base.basketEntry[1:len(base.basketSeek)] {
+			bounds[entry]++
+		}
+	}
+
+	set := make(map[string]struct{}, len(rvars))
+	for _, rv := range rvars {
+		if _, dup := set[rv.Name]; dup {
+			continue
+		}
+		set[rv.Name] = struct{}{}
+		b := t.Branch(rv.Name)
+		if b == nil {
+			continue
+		}
+		visit(b)
+	}
+
+	out := make([]int64, 0, len(bounds))
+	for entry, n := range bounds {
+		if n != nbrs {
+			continue
+		}
+		out = append(out, entry)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+
+	return out
+}
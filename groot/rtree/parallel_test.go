@@ -0,0 +1,390 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"go-hep.org/x/hep/groot/riofs"
+)
+
+func createParallelTree(t *testing.T, fname string, nevts int) {
+	t.Helper()
+
+	f, err := riofs.Create(fname)
+	if err != nil {
+		t.Fatalf("could not create %q: %+v", fname, err)
+	}
+	defer f.Close()
+
+	var (
+		i32 int32
+		f64 float64
+		arr []float64
+		n   int32
+	)
+	wvars := []WriteVar{
+		{Name: "i32", Value: &i32},
+		{Name: "f64", Value: &f64},
+		{Name: "n", Value: &n},
+		{Name: "arr", Value: &arr, Count: "n"},
+	}
+	w, err := NewWriter(f, "tree", wvars, WithBasketSize(256))
+	if err != nil {
+		t.Fatalf("could not create tree writer: %+v", err)
+	}
+
+	for i := 0; i < nevts; i++ {
+		i32 = int32(i)
+		f64 = float64(i)
+		n = int32(i % 5)
+		arr = arr[:0]
+		for j := 0; j < int(n); j++ {
+			arr = append(arr, float64(i))
+		}
+		_, err = w.Write()
+		if err != nil {
+			t.Fatalf("could not write entry %d: %+v", i, err)
+		}
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("could not close tree writer: %+v", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		t.Fatalf("could not close file: %+v", err)
+	}
+}
+
+func TestReadParallel(t *testing.T) {
+	const nevts = 5000
+
+	dir := t.TempDir()
+	fnames := []string{
+		filepath.Join(dir, "f1.root"),
+		filepath.Join(dir, "f2.root"),
+	}
+	for _, fname := range fnames {
+		createParallelTree(t, fname, nevts)
+	}
+
+	f, err := riofs.Open(fnames[0])
+	if err != nil {
+		t.Fatalf("could not open file: %+v", err)
+	}
+	defer f.Close()
+
+	o, err := f.Get("tree")
+	if err != nil {
+		t.Fatalf("could not get tree: %+v", err)
+	}
+	tree := o.(Tree)
+
+	ch, closer, err := ChainOf("tree", fnames...)
+	if err != nil {
+		t.Fatalf("could not create chain: %+v", err)
+	}
+	defer closer()
+
+	elist := NewEntryList("elist", "")
+	for i := int64(0); i < nevts; i += 3 {
+		elist.Enter(i)
+	}
+
+	for _, tc := range []struct {
+		name string
+		tree Tree
+		n    int
+		opts []ReadOption
+		want func(i int64) bool
+	}{
+		{
+			name: "tree",
+			tree: tree,
+			n:    4,
+			want: func(i int64) bool { return true },
+		},
+		{
+			name: "tree-1",
+			tree: tree,
+			n:    1,
+			want: func(i int64) bool { return true },
+		},
+		{
+			name: "tree-ncpu",
+			tree: tree,
+			n:    -1,
+			opts: []ReadOption{WithDecompressionWorkers(-1)},
+			want: func(i int64) bool { return true },
+		},
+		{
+			name: "tree-range",
+			tree: tree,
+			n:    3,
+			opts: []ReadOption{WithRange(123, 4321)},
+			want: func(i int64) bool { return 123 <= i && i < 4321 },
+		},
+		{
+			name: "chain",
+			tree: ch,
+			n:    8,
+			opts: []ReadOption{WithDecompressionWorkers(2)},
+			want: func(i int64) bool { return true },
+		},
+		{
+			name: "entry-list",
+			tree: tree,
+			n:    4,
+			opts: []ReadOption{WithEntryList(elist), WithRange(10, nevts)},
+			want: func(i int64) bool { return i%3 == 0 && i >= 10 },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewReader(tc.tree, nil, tc.opts...)
+			if err != nil {
+				t.Fatalf("could not create reader: %+v", err)
+			}
+			defer r.Close()
+
+			var (
+				got  []int64
+				sum  float64
+				nwrk int
+			)
+			err = r.ReadParallel(tc.n, func(id int) (Worker, error) {
+				var (
+					i32     int32
+					f64     float64
+					arr     []float64
+					entries []int64
+					wsum    float64
+				)
+				nwrk++
+				return Worker{
+					RVars: []ReadVar{
+						{Name: "i32", Value: &i32},
+						{Name: "f64", Value: &f64},
+						{Name: "arr", Value: &arr},
+					},
+					Read: func(ctx RCtx) error {
+						i := ctx.Entry % nevts
+						if int64(i32) != i || f64 != float64(i) || len(arr) != int(i%5) {
+							return fmt.Errorf(
+								"invalid data for entry %d: i32=%d, f64=%v, arr=%v",
+								ctx.Entry, i32, f64, arr,
+							)
+						}
+						entries = append(entries, ctx.Entry)
+						wsum += f64
+						return nil
+					},
+					Reduce: func() error {
+						got = append(got, entries...)
+						sum += wsum
+						return nil
+					},
+				}, nil
+			})
+			if err != nil {
+				t.Fatalf("could not read tree: %+v", err)
+			}
+
+			if tc.n > 0 && nwrk != tc.n {
+				t.Fatalf("invalid number of workers: got=%d, want=%d", nwrk, tc.n)
+			}
+
+			var (
+				want []int64
+				wsum float64
+			)
+			for i := int64(0); i < tc.tree.Entries(); i++ {
+				if tc.want(i) {
+					want = append(want, i)
+					wsum += float64(i % nevts)
+				}
+			}
+
+			sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("invalid entries: got=%d entries, want=%d entries", len(got), len(want))
+			}
+			if sum != wsum {
+				t.Fatalf("invalid sum: got=%v, want=%v", sum, wsum)
+			}
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		r, err := NewReader(tree, nil)
+		if err != nil {
+			t.Fatalf("could not create reader: %+v", err)
+		}
+		defer r.Close()
+
+		reduced := false
+		err = r.ReadParallel(4, func(id int) (Worker, error) {
+			var i32 int32
+			return Worker{
+				RVars: []ReadVar{{Name: "i32", Value: &i32}},
+				Read: func(ctx RCtx) error {
+					if i32 == 42 {
+						return fmt.Errorf("boom")
+					}
+					return nil
+				},
+				Reduce: func() error {
+					reduced = true
+					return nil
+				},
+			}, nil
+		})
+		if err == nil {
+			t.Fatalf("expected an error")
+		}
+		if reduced {
+			t.Fatalf("reduce called after an error")
+		}
+
+		err = r.ReadParallel(2, func(id int) (Worker, error) {
+			var v int32
+			return Worker{
+				RVars: []ReadVar{{Name: "not-there", Value: &v}},
+				Read:  func(ctx RCtx) error { return nil },
+			}, nil
+		})
+		if err == nil {
+			t.Fatalf("expected an error (invalid read-var)")
+		}
+	})
+}
+
+func TestReadDecompressionWorkers(t *testing.T) {
+	const nevts = 3000
+
+	fname := filepath.Join(t.TempDir(), "tree.root")
+	createParallelTree(t, fname, nevts)
+
+	f, err := riofs.Open(fname)
+	if err != nil {
+		t.Fatalf("could not open file: %+v", err)
+	}
+	defer f.Close()
+
+	o, err := f.Get("tree")
+	if err != nil {
+		t.Fatalf("could not get tree: %+v", err)
+	}
+	tree := o.(Tree)
+
+	for _, conc := range []int{-1, 0, 1, 2, 8} {
+		for _, nrab := range []int{1, 2, 16} {
+			t.Run(fmt.Sprintf("conc=%d-prefetch=%d", conc, nrab), func(t *testing.T) {
+				var (
+					f64 float64
+					arr []float64
+				)
+				r, err := NewReader(tree, []ReadVar{
+					{Name: "f64", Value: &f64},
+					{Name: "arr", Value: &arr},
+				},
+					WithDecompressionWorkers(conc),
+					WithPrefetchBaskets(nrab),
+					WithRange(7, nevts-5),
+				)
+				if err != nil {
+					t.Fatalf("could not create reader: %+v", err)
+				}
+				defer r.Close()
+
+				next := int64(7)
+				err = r.Read(func(ctx RCtx) error {
+					if ctx.Entry != next {
+						return fmt.Errorf("invalid entry: got=%d, want=%d", ctx.Entry, next)
+					}
+					next++
+					if f64 != float64(ctx.Entry) || len(arr) != int(ctx.Entry%5) {
+						return fmt.Errorf("invalid data for entry %d: f64=%v, arr=%v", ctx.Entry, f64, arr)
+					}
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("could not read tree: %+v", err)
+				}
+				if next != nevts-5 {
+					t.Fatalf("invalid number of entries: got=%d, want=%d", next-7, nevts-5-7)
+				}
+			})
+		}
+	}
+}
+
+func TestClusterRanges(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "tree.root")
+	createParallelTree(t, fname, 5000)
+
+	f, err := riofs.Open(fname)
+	if err != nil {
+		t.Fatalf("could not open file: %+v", err)
+	}
+	defer f.Close()
+
+	o, err := f.Get("tree")
+	if err != nil {
+		t.Fatalf("could not get tree: %+v", err)
+	}
+	tree := o.(*ttree)
+
+	rvars := []ReadVar{{Name: "i32"}, {Name: "f64"}}
+	bounds := clusterBounds(tree, rvars)
+	if len(bounds) == 0 {
+		t.Fatalf("no cluster bounds")
+	}
+
+	isBound := make(map[int64]bool)
+	for _, b := range bounds {
+		isBound[b] = true
+	}
+	for _, name := range []string{"i32", "f64"} {
+		b := asBranch(tree.Branch(name))
+		set := make(map[int64]bool)
+		for _, v := range b.basketEntry {
+			set[v] = true
+		}
+		for _, v := range bounds {
+			if !set[v] {
+				t.Fatalf("bound %d is not a basket boundary of branch %q", v, name)
+			}
+		}
+	}
+
+	ranges := clusterRanges(tree, rvars, 0, tree.Entries(), 8)
+	if len(ranges) < 2 {
+		t.Fatalf("invalid number of ranges: %d", len(ranges))
+	}
+	cur := int64(0)
+	for _, rng := range ranges {
+		if rng[0] != cur || rng[1] <= rng[0] {
+			t.Fatalf("invalid range %v (cur=%d)", rng, cur)
+		}
+		if rng[1] != tree.Entries() && !isBound[rng[1]] {
+			t.Fatalf("range %v is not cluster-aligned", rng)
+		}
+		cur = rng[1]
+	}
+	if cur != tree.Entries() {
+		t.Fatalf("invalid last range: got=%d, want=%d", cur, tree.Entries())
+	}
+
+	if got := clusterRanges(tree, rvars, 10, 10, 4); got != nil {
+		t.Fatalf("invalid empty range: %v", got)
+	}
+}
@@ -11,10 +11,10 @@ type rbranch struct {
 	leaves []rleaf
 }
 
-func newRBranch(b Branch, n int, beg, end int64, leaves []rleaf, rctx rleafCtx) rbranch {
+func newRBranch(b Branch, bko bkopts, beg, end int64, leaves []rleaf, rctx rleafCtx) rbranch {
 	rb := rbranch{
 		b:      b,
-		rb:     newBkReader(b, n, beg, end),
+		rb:     newBkReader(b, bko, beg, end),
 		leaves: leaves,
 	}
 	return rb
@@ -36,12 +36,12 @@ func (rb *rbranch) stop() error {
 
 func (rb *rbranch) reset() {
 	rb.rb.close()
-	rb.rb = newBkReader(rb.b, rb.rb.n, rb.rb.beg, rb.rb.end)
+	rb.rb = newBkReader(rb.b, rb.rb.bko, rb.rb.beg, rb.rb.end)
 }
 
 func (rb *rbranch) read(i int64) error {
 	var err error
-	if i >= rb.cur.span.end {
+	for i >= rb.cur.span.end {
 		rb.cur, err = rb.rb.read()
 		if err != nil {
 			return err
@@ -68,6 +68,27 @@ func (rb *rbranch) read(i int64) error {
 	return nil
 }
 
+// load loads the i-th entry, possibly out of order.
+// load seeks to the basket holding that entry when it is not among
+// the current or the read-ahead baskets.
+func (rb *rbranch) load(i int64) error {
+	if rb.cur != nil && rb.cur.span.beg <= i {
+		ibkt, _ := rb.rb.findBaskets(i, i+1)
+		if 0 <= ibkt && ibkt-rb.cur.id <= rb.rb.n {
+			return rb.read(i)
+		}
+	}
+
+	var err error
+	rb.rb.close()
+	rb.rb = newBkReader(rb.b, rb.rb.bko, i, rb.rb.end)
+	rb.cur, err = rb.rb.read()
+	if err != nil {
+		return err
+	}
+	return rb.read(i)
+}
+
 func asBranch(b Branch) *tbranch {
 	switch b := b.(type) {
 	case *tbranch:
@@ -0,0 +1,134 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"go-hep.org/x/hep/groot/riofs"
+)
+
+func TestRBranchSparseRead(t *testing.T) {
+	const nevts = 1000
+
+	fname := filepath.Join(t.TempDir(), "sparse.root")
+	func() {
+		f, err := riofs.Create(fname)
+		if err != nil {
+			t.Fatalf("could not create file: %+v", err)
+		}
+		defer f.Close()
+
+		var (
+			i64 int64
+			f64 float64
+		)
+		w, err := NewWriter(f, "tree", []WriteVar{
+			{Name: "i64", Value: &i64},
+			{Name: "f64", Value: &f64},
+		}, WithBasketSize(64))
+		if err != nil {
+			t.Fatalf("could not create tree writer: %+v", err)
+		}
+
+		for i := 0; i < nevts; i++ {
+			i64 = int64(i)
+			f64 = float64(2 * i)
+			_, err = w.Write()
+			if err != nil {
+				t.Fatalf("could not write entry %d: %+v", i, err)
+			}
+		}
+
+		err = w.Close()
+		if err != nil {
+			t.Fatalf("could not close tree writer: %+v", err)
+		}
+
+		err = f.Close()
+		if err != nil {
+			t.Fatalf("could not close file: %+v", err)
+		}
+	}()
+
+	f, err := riofs.Open(fname)
+	if err != nil {
+		t.Fatalf("could not open file: %+v", err)
+	}
+	defer f.Close()
+
+	o, err := f.Get("tree")
+	if err != nil {
+		t.Fatalf("could not get tree: %+v", err)
+	}
+	tree := o.(*ttree)
+
+	if n := len(tree.Branch("i64").(*tbranch).basketEntry); n < 10 {
+		t.Fatalf("test needs more baskets (got=%d)", n)
+	}
+
+	var (
+		i64   int64
+		f64   float64
+		rvars = []ReadVar{
+			{Name: "i64", Value: &i64},
+			{Name: "f64", Value: &f64},
+		}
+		check = func(i int64) error {
+			if i64 != i || f64 != float64(2*i) {
+				return fmt.Errorf("invalid entry %d: got=(%d, %v)", i, i64, f64)
+			}
+			return nil
+		}
+	)
+
+	t.Run("read", func(t *testing.T) {
+		rvars, err := sanitizeRVars(tree, rvars)
+		if err != nil {
+			t.Fatalf("could not sanitize read-vars: %+v", err)
+		}
+		r := newRTree(tree, rvars, bkopts{n: 2, conc: 1}, 0, nevts)
+		defer r.Close()
+
+		err = r.start()
+		if err != nil {
+			t.Fatalf("could not start reader: %+v", err)
+		}
+		defer r.stop()
+
+		// increasing entries, skipping several baskets at once.
+		for _, i := range []int64{0, 1, 2, 250, 251, 600, 998, 999} {
+			err := r.read(i)
+			if err != nil {
+				t.Fatalf("could not read entry %d: %+v", i, err)
+			}
+			err = check(i)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+
+	t.Run("load", func(t *testing.T) {
+		r, err := newRLoader(tree, rvars, bkopts{n: 2, conc: 1})
+		if err != nil {
+			t.Fatalf("could not create loader: %+v", err)
+		}
+		defer r.Close()
+
+		for _, i := range []int64{500, 10, 998, 10, 11, 400, 0, 999, 3, 501} {
+			err := r.load(i)
+			if err != nil {
+				t.Fatalf("could not load entry %d: %+v", i, err)
+			}
+			err = check(i)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+}
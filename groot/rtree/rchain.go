@@ -11,10 +11,10 @@ import (
 type rchain struct {
 	ch *chain
 
-	rvs  []ReadVar
-	nrab int
-	beg  int64
-	end  int64
+	rvs []ReadVar
+	bko bkopts
+	beg int64
+	end int64
 
 	ibeg int // first tree to process
 	iend int // last-1 tree to process
@@ -24,28 +24,31 @@ var (
 	_ reader = (*rchain)(nil)
 )
 
-func newRChain(ch *chain, rvars []ReadVar, n int, beg, end int64) *rchain {
+func newRChain(ch *chain, rvars []ReadVar, bko bkopts, beg, end int64) (*rchain, error) {
 	r := &rchain{
-		ch:   ch,
-		rvs:  rvars,
-		nrab: n,
-		beg:  beg,
-		end:  end,
+		ch:  ch,
+		rvs: rvars,
+		bko: bko,
+		beg: beg,
+		end: end,
 	}
 
 	tbeg, tend := r.findTrees(beg, end)
 	if tbeg < 0 || tend < 0 {
-		panic(fmt.Errorf(
+		return nil, fmt.Errorf(
 			"rtree: could not find matching trees in chain for [%d, %d) within [%d, %d)",
 			beg, end, 0, ch.Entries(),
-		))
+		)
 	}
 	r.ibeg = tbeg
 	r.iend = tend
 
-	r.loadRVars()
+	err := r.loadRVars()
+	if err != nil {
+		return nil, err
+	}
 
-	return r
+	return r, nil
 }
 
 func (r *rchain) Close() error {
@@ -54,14 +57,18 @@ func (r *rchain) Close() error {
 
 func (r *rchain) rvars() []ReadVar { return r.rvs }
 
-func (r *rchain) loadRVars() {
+func (r *rchain) loadRVars() error {
 	if len(r.ch.trees) == 0 {
-		return
+		return nil
 	}
 
-	rr := newReader(r.ch.trees[0], r.rvs, r.nrab, 0, 1)
+	rr, err := newReader(r.ch.trees[0], r.rvs, r.bko, 0, 1)
+	if err != nil {
+		return fmt.Errorf("rtree: could not create reader for chain: %w", err)
+	}
 	defer rr.Close()
 	r.rvs = rr.rvars()
+	return nil
 }
 
 func (r *rchain) run(off, beg, end int64, f func(RCtx) error) error {
@@ -119,7 +126,10 @@ func (r *rchain) findTrees(beg, end int64) (int, int) {
 }
 
 func (r *rchain) runTree(itree int, off, beg, end int64, f func(RCtx) error) error {
-	rr := newReader(r.ch.trees[itree], r.rvs, r.nrab, beg, end)
+	rr, err := newReader(r.ch.trees[itree], r.rvs, r.bko, beg, end)
+	if err != nil {
+		return err
+	}
 	return rr.run(off, beg, end, f)
 }
 
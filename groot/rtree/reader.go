@@ -13,14 +13,17 @@ import (
 
 // Reader reads data from a Tree.
 type Reader struct {
-	r    reader
-	beg  int64
-	end  int64
-	nrab int // number of read-ahead baskets
+	r   reader
+	beg int64
+	end int64
+	bko bkopts // basket reading options
 
 	tree  Tree
 	rvars []ReadVar
 
+	elist   *EntryList // entry list to iterate over (if any)
+	entries []int64    // entries of the tree to read, when iterating over an entry list
+
 	evals []rfunc.Formula
 	dirty bool // whether we need to re-create scanner (if formula needed new branches)
 }
@@ -43,7 +46,33 @@ func WithRange(beg, end int64) ReadOption {
 // The number of prefetch baskets is cap'ed by the number of baskets, per branch.
 func WithPrefetchBaskets(n int) ReadOption {
 	return func(r *Reader) error {
-		r.nrab = n
+		r.bko.n = n
+		return nil
+	}
+}
+
+// WithDecompressionWorkers specifies the number of goroutines decompressing
+// baskets, per branch.
+// The default is 1: baskets are decompressed one after the other, ahead of
+// the event loop.
+// If n is negative, runtime.NumCPU() goroutines are used.
+// The number of read-ahead baskets is raised to n if needed.
+func WithDecompressionWorkers(n int) ReadOption {
+	return func(r *Reader) error {
+		r.bko.conc = n
+		return nil
+	}
+}
+
+// WithEntryList specifies the list of entries a Tree reader will read through.
+// Only the entries of the list that are within the range of entries of
+// the reader are read.
+//
+// The entry list of a chain either holds one sub-list per tree of the chain
+// or, if it has no sub-list, the global entry numbers of the chain.
+func WithEntryList(elist *EntryList) ReadOption {
+	return func(r *Reader) error {
+		r.elist = elist
 		return nil
 	}
 }
@@ -63,7 +92,10 @@ func NewReader(t Tree, rvars []ReadVar, opts ...ReadOption) (*Reader, error) {
 		return nil, fmt.Errorf("rtree: could not create reader: %w", err)
 	}
 
-	r.r = newReader(t, rvars, r.nrab, r.beg, r.end)
+	r.r, err = r.newScanner(rvars)
+	if err != nil {
+		return nil, fmt.Errorf("rtree: could not create reader: %w", err)
+	}
 	r.rvars = r.r.rvars()
 
 	return &r, nil
@@ -72,7 +104,9 @@ func NewReader(t Tree, rvars []ReadVar, opts ...ReadOption) (*Reader, error) {
 func (r *Reader) setup(t Tree, opts []ReadOption) error {
 	r.beg = 0
 	r.end = -1
-	r.nrab = 2
+	r.bko = bkopts{n: 2, conc: 1}
+	r.elist = nil
+	r.entries = nil
 
 	for i, opt := range opts {
 		err := opt(r)
@@ -112,9 +146,30 @@ func (r *Reader) setup(t Tree, opts []ReadOption) error {
 		)
 	}
 
+	if r.elist != nil {
+		var err error
+		r.entries, err = r.elist.entriesOf(t, r.beg, r.end)
+		if err != nil {
+			return fmt.Errorf("rtree: could not apply entry list %q: %w", r.elist.Name(), err)
+		}
+	}
+
 	return nil
 }
 
+// newScanner creates the internal reader that iterates over the entries
+// of the tree.
+func (r *Reader) newScanner(rvars []ReadVar) (reader, error) {
+	if r.elist != nil {
+		rr, err := newREntries(r.tree, rvars, r.bko, r.entries)
+		if err != nil {
+			return nil, err
+		}
+		return rr, nil
+	}
+	return newReader(r.tree, rvars, r.bko, r.beg, r.end)
+}
+
 // Close closes the Reader.
 func (r *Reader) Close() error {
 	if r.r == nil {
@@ -137,7 +192,11 @@ func (r *Reader) Read(f func(ctx RCtx) error) error {
 	if r.dirty {
 		r.dirty = false
 		_ = r.r.Close()
-		r.r = newReader(r.tree, r.rvars, r.nrab, r.beg, r.end)
+		var err error
+		r.r, err = r.newScanner(r.rvars)
+		if err != nil {
+			return fmt.Errorf("rtree: could not create reader: %w", err)
+		}
 	}
 	r.r.reset()
 
@@ -159,7 +218,10 @@ func (r *Reader) Reset(opts ...ReadOption) error {
 		return fmt.Errorf("rtree: could not reset reader options: %w", err)
 	}
 
-	r.r = newReader(r.tree, r.rvars, r.nrab, r.beg, r.end)
+	r.r, err = r.newScanner(r.rvars)
+	if err != nil {
+		return fmt.Errorf("rtree: could not reset internal reader: %w", err)
+	}
 	r.rvars = r.r.rvars()
 
 	return nil
@@ -175,6 +237,19 @@ func (r *Reader) FormulaFunc(branches []string, fct interface{}) (rfunc.Formula,
 	return r.Formula(f)
 }
 
+// FormulaExpr creates a new formula based on the provided string expression.
+// The branches needed by the expression are automatically loaded.
+// The syntax of the expression is described in rfunc.NewFormulaExpr.
+//
+// The returned formula's Func method returns a func() float64.
+func (r *Reader) FormulaExpr(expr string) (rfunc.Formula, error) {
+	f, err := rfunc.NewFormulaExpr(expr)
+	if err != nil {
+		return nil, fmt.Errorf("rtree: could not create formula: %w", err)
+	}
+	return r.Formula(f)
+}
+
 // Formula creates a new formula based on the provided user provided formula.
 // Formula binds the provided function with the requested list of leaves.
 func (r *Reader) Formula(f rfunc.Formula) (rfunc.Formula, error) {
@@ -244,29 +319,39 @@ var (
 
 func (r *rtree) rvars() []ReadVar { return r.rvs }
 
-func newReader(t Tree, rvars []ReadVar, n int, beg, end int64) reader {
+func newReader(t Tree, rvars []ReadVar, bko bkopts, beg, end int64) (reader, error) {
 	rvars, err := sanitizeRVars(t, rvars)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	switch t := t.(type) {
 	case *ttree:
-		return newRTree(t, rvars, n, beg, end)
+		return newRTree(t, rvars, bko, beg, end), nil
 	case *tntuple:
-		return newRTree(&t.ttree, rvars, n, beg, end)
+		return newRTree(&t.ttree, rvars, bko, beg, end), nil
 	case *tntupleD:
-		return newRTree(&t.ttree, rvars, n, beg, end)
+		return newRTree(&t.ttree, rvars, bko, beg, end), nil
 	case *chain:
-		return newRChain(t, rvars, n, beg, end)
+		r, err := newRChain(t, rvars, bko, beg, end)
+		if err != nil {
+			return nil, err
+		}
+		return r, nil
 	case *join:
-		return newRJoin(t, rvars, n, beg, end)
+		return newRJoin(t, rvars, bko, beg, end), nil
+	case *friend:
+		r, err := newRFriend(t, rvars, bko, beg, end)
+		if err != nil {
+			return nil, err
+		}
+		return r, nil
 	default:
-		panic(fmt.Errorf("rtree: unknown Tree implementation %T", t))
+		return nil, fmt.Errorf("rtree: unknown Tree implementation %T", t)
 	}
 }
 
-func newRTree(t *ttree, rvars []ReadVar, n int, beg, end int64) *rtree {
+func newRTree(t *ttree, rvars []ReadVar, bko bkopts, beg, end int64) *rtree {
 	r := &rtree{
 		tree: t,
 		rvs:  rvars,
@@ -330,7 +415,7 @@ func newRTree(t *ttree, rvars []ReadVar, n int, beg, end int64) *rtree {
 	r.brs = make([]rbranch, len(brs))
 	for i, leaves := range brs {
 		branch := leaves[0].Leaf().Branch()
-		r.brs[i] = newRBranch(branch, n, beg, end, leaves, r)
+		r.brs[i] = newRBranch(branch, bko, beg, end, leaves, r)
 	}
 
 	return r
@@ -0,0 +1,92 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"fmt"
+)
+
+// rentries reads a selected list of entries of a tree.
+type rentries struct {
+	tree    Tree
+	rvs     []ReadVar
+	bko     bkopts
+	entries []int64 // sorted list of entries to read
+
+	r rloader
+}
+
+var (
+	_ reader = (*rentries)(nil)
+)
+
+func newREntries(t Tree, rvars []ReadVar, bko bkopts, entries []int64) (*rentries, error) {
+	r := &rentries{
+		tree:    t,
+		bko:     bko,
+		entries: entries,
+	}
+
+	err := r.open(rvars)
+	if err != nil {
+		return nil, err
+	}
+	r.rvs = r.r.rvars()
+
+	return r, nil
+}
+
+func (r *rentries) open(rvars []ReadVar) error {
+	var err error
+	r.r, err = newRLoader(r.tree, rvars, r.bko)
+	if err != nil {
+		return fmt.Errorf("rtree: could not create entry list reader: %w", err)
+	}
+	return nil
+}
+
+func (r *rentries) Close() error {
+	if r.r == nil {
+		return nil
+	}
+	err := r.r.Close()
+	r.r = nil
+	return err
+}
+
+func (r *rentries) rvars() []ReadVar { return r.rvs }
+
+func (r *rentries) run(off, beg, end int64, f func(RCtx) error) error {
+	defer r.Close()
+
+	if r.r == nil {
+		err := r.open(r.rvs)
+		if err != nil {
+			return err
+		}
+	}
+
+	var rctx RCtx
+	for _, i := range r.entries {
+		if i < beg || end <= i {
+			continue
+		}
+		err := r.r.load(i)
+		if err != nil {
+			return fmt.Errorf("rtree: could not read entry %d: %w", i, err)
+		}
+		rctx.Entry = i + off
+		err = f(rctx)
+		if err != nil {
+			return fmt.Errorf("rtree: could not process entry %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *rentries) start() error { return nil }
+func (r *rentries) stop()        {}
+func (r *rentries) reset()       { _ = r.Close() }
@@ -0,0 +1,290 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// rfriend reads a main tree and its friend trees.
+type rfriend struct {
+	t *friend
+
+	main reader
+	fs   []*rfentry
+
+	rvs []ReadVar
+	bko bkopts
+}
+
+// rfentry loads the entries of a friend tree.
+type rfentry struct {
+	tree Tree
+	idx  *Index
+	rvs  []ReadVar // read-vars requested from the friend tree
+	bko  bkopts
+
+	r    rloader
+	kmaj func() float64 // major value of the index, evaluated on the main tree
+	kmin func() float64 // minor value of the index, evaluated on the main tree
+}
+
+var (
+	_ reader = (*rfriend)(nil)
+)
+
+func newRFriend(t *friend, rvars []ReadVar, bko bkopts, beg, end int64) (*rfriend, error) {
+	r := &rfriend{
+		t:   t,
+		fs:  make([]*rfentry, len(t.friends)),
+		bko: bko,
+	}
+
+	var (
+		mvars []ReadVar
+		fvars = make([][]ReadVar, len(t.friends))
+	)
+	for _, rv := range rvars {
+		i, _ := t.treeOf(rv.Name)
+		switch {
+		case i < 0:
+			mvars = append(mvars, rv)
+		default:
+			fvars[i] = append(fvars[i], rv)
+		}
+	}
+
+	for i, fr := range t.friends {
+		re := &rfentry{
+			tree: fr.Tree,
+			idx:  fr.Index,
+			rvs:  fvars[i],
+			bko:  bko,
+		}
+		if re.idx != nil {
+			var err error
+			re.kmaj, re.kmin, mvars, err = bindIndexKey(t.main, re.idx, mvars)
+			if err != nil {
+				return nil, fmt.Errorf("rtree: could not bind index of friend tree %s: %w", fr.Tree.Name(), err)
+			}
+		}
+		r.fs[i] = re
+	}
+
+	var err error
+	r.main, err = newReader(t.main, mvars, bko, beg, end)
+	if err != nil {
+		return nil, fmt.Errorf("rtree: could not create reader for main tree: %w", err)
+	}
+	r.rvs = append(r.rvs, r.main.rvars()...)
+	for _, re := range r.fs {
+		err = re.open()
+		if err != nil {
+			_ = r.Close()
+			return nil, err
+		}
+		r.rvs = append(r.rvs, re.r.rvars()...)
+	}
+
+	return r, nil
+}
+
+func (r *rfriend) Close() error {
+	err := r.main.Close()
+	for _, re := range r.fs {
+		e := re.close()
+		if e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (r *rfriend) rvars() []ReadVar { return r.rvs }
+
+func (r *rfriend) run(off, beg, end int64, f func(RCtx) error) error {
+	defer r.Close()
+
+	return r.main.run(0, beg, end, func(ctx RCtx) error {
+		for _, re := range r.fs {
+			err := re.load(ctx.Entry)
+			if err != nil {
+				return fmt.Errorf("rtree: could not load friend tree %s: %w", re.tree.Name(), err)
+			}
+		}
+		ctx.Entry += off
+		return f(ctx)
+	})
+}
+
+func (r *rfriend) start() error { return r.main.start() }
+func (r *rfriend) stop()        { r.main.stop() }
+
+func (r *rfriend) reset() {
+	r.main.reset()
+	for _, re := range r.fs {
+		_ = re.close()
+	}
+}
+
+func (re *rfentry) open() error {
+	var err error
+	re.r, err = newRLoader(re.tree, re.rvs, re.bko)
+	if err != nil {
+		return fmt.Errorf("rtree: could not create reader for friend tree %s: %w", re.tree.Name(), err)
+	}
+	re.rvs = re.r.rvars()
+	return nil
+}
+
+func (re *rfentry) close() error {
+	if re.r == nil {
+		return nil
+	}
+	err := re.r.Close()
+	re.r = nil
+	return err
+}
+
+// load loads the friend entry matching the i-th entry of the main tree.
+func (re *rfentry) load(i int64) error {
+	if re.idx != nil {
+		i = re.idx.Entry(int64(re.kmaj()), int64(re.kmin()))
+	}
+
+	if i < 0 || i >= re.tree.Entries() {
+		for _, rv := range re.rvs {
+			v := reflect.ValueOf(rv.Value).Elem()
+			v.Set(reflect.Zero(v.Type()))
+		}
+		return nil
+	}
+
+	if re.r == nil {
+		err := re.open()
+		if err != nil {
+			return err
+		}
+	}
+	return re.r.load(i)
+}
+
+// rloader loads entries of a tree, possibly out of order.
+type rloader interface {
+	Close() error
+	rvars() []ReadVar
+	load(i int64) error
+}
+
+func newRLoader(t Tree, rvars []ReadVar, bko bkopts) (rloader, error) {
+	rvars, err := sanitizeRVars(t, rvars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := t.(type) {
+	case *ttree:
+		return newRTree(t, rvars, bko, 0, t.Entries()), nil
+	case *tntuple:
+		return newRTree(&t.ttree, rvars, bko, 0, t.Entries()), nil
+	case *tntupleD:
+		return newRTree(&t.ttree, rvars, bko, 0, t.Entries()), nil
+	case *chain:
+		return newRChainLoader(t, rvars, bko)
+	default:
+		return nil, fmt.Errorf("rtree: friend tree of type %T not supported", t)
+	}
+}
+
+func (r *rtree) load(i int64) error {
+	for j := range r.brs {
+		rb := &r.brs[j]
+		err := rb.load(i)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rchainLoader loads entries of a chain, possibly out of order.
+type rchainLoader struct {
+	ch  *chain
+	rvs []ReadVar
+	bko bkopts
+
+	cur int    // index of the current tree in the chain
+	r   *rtree // reader for the current tree
+}
+
+func newRChainLoader(ch *chain, rvars []ReadVar, bko bkopts) (*rchainLoader, error) {
+	r := &rchainLoader{
+		ch:  ch,
+		rvs: rvars,
+		bko: bko,
+		cur: -1,
+	}
+	if len(ch.trees) == 0 {
+		return r, nil
+	}
+
+	err := r.loadTree(0)
+	if err != nil {
+		return nil, err
+	}
+	r.rvs = r.r.rvars()
+
+	return r, nil
+}
+
+func (r *rchainLoader) Close() error {
+	if r.r == nil {
+		return nil
+	}
+	err := r.r.Close()
+	r.r = nil
+	r.cur = -1
+	return err
+}
+
+func (r *rchainLoader) rvars() []ReadVar { return r.rvs }
+
+func (r *rchainLoader) loadTree(i int) error {
+	_ = r.Close()
+
+	rr, err := newRLoader(r.ch.trees[i], r.rvs, r.bko)
+	if err != nil {
+		return err
+	}
+	tree, ok := rr.(*rtree)
+	if !ok {
+		_ = rr.Close()
+		return fmt.Errorf("rtree: chain of %T not supported", r.ch.trees[i])
+	}
+	r.r = tree
+	r.cur = i
+	return nil
+}
+
+func (r *rchainLoader) load(i int64) error {
+	if r.cur < 0 || i < r.ch.offs[r.cur] || r.ch.tots[r.cur] <= i {
+		itree := -1
+		for j := range r.ch.trees {
+			if r.ch.offs[j] <= i && i < r.ch.tots[j] {
+				itree = j
+				break
+			}
+		}
+		if itree < 0 {
+			return fmt.Errorf("rtree: could not find tree for entry %d in chain", i)
+		}
+		err := r.loadTree(itree)
+		if err != nil {
+			return err
+		}
+	}
+	return r.r.load(i - r.ch.offs[r.cur])
+}
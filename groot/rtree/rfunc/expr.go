@@ -0,0 +1,431 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rfunc
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// NewFormulaExpr returns a new formula from the provided string expression.
+//
+// The expression syntax follows closely the one of ROOT's TTreeFormula:
+//   - arithmetic operators: +, -, *, /, % and ^ (or **) for the power,
+//   - comparison operators: ==, !=, <, <=, >, >=,
+//   - logical operators: &&, ||, !,
+//   - bitwise operators: &, |, <<, >>,
+//   - indexing of array and slice branches: x[0],
+//   - mathematical functions: abs, sqrt, exp, log, log10, pow, min, max, ...
+//     (optionally prefixed with TMath::, e.g. TMath::Sqrt),
+//   - constants: pi() and e() (or TMath::Pi() and TMath::E()).
+//
+// Any other identifier (including dotted names such as evt.px) is
+// interpreted as the name of a tree variable.
+//
+// As for TTreeFormula, the expression is evaluated as a float64 value.
+// Boolean sub-expressions evaluate to 1 (true) or 0 (false).
+// Indexing an array or a slice out of its bounds yields NaN.
+//
+// The function returned by Func is a func() float64.
+func NewFormulaExpr(expr string) (Formula, error) {
+	return newExprFormula(expr)
+}
+
+type exprFormula struct {
+	expr  string
+	node  exprNode
+	names []string
+	fct   func() float64
+}
+
+func newExprFormula(expr string) (*exprFormula, error) {
+	node, err := exprParse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("rfunc: could not parse formula %q: %w", expr, err)
+	}
+
+	f := &exprFormula{
+		expr: expr,
+		node: node,
+	}
+
+	err = f.vars(node, make(map[string]struct{}))
+	if err != nil {
+		return nil, fmt.Errorf("rfunc: invalid formula %q: %w", expr, err)
+	}
+
+	return f, nil
+}
+
+func (f *exprFormula) RVars() []string { return f.names }
+
+func (f *exprFormula) Bind(args []interface{}) error {
+	if got, want := len(args), len(f.names); got != want {
+		return fmt.Errorf(
+			"rfunc: invalid number of bind arguments (got=%d, want=%d)",
+			got, want,
+		)
+	}
+
+	vars := make(map[string]exprVar, len(args))
+	for i, arg := range args {
+		v, err := newExprVar(arg)
+		if err != nil {
+			return fmt.Errorf(
+				"rfunc: argument type %d (name=%s) mismatch: %w",
+				i, f.names[i], err,
+			)
+		}
+		vars[f.names[i]] = v
+	}
+
+	fct, err := exprCompile(f.node, vars)
+	if err != nil {
+		return fmt.Errorf("rfunc: could not compile formula %q: %w", f.expr, err)
+	}
+	f.fct = fct
+
+	return nil
+}
+
+func (f *exprFormula) Func() interface{} {
+	return func() float64 {
+		return f.fct()
+	}
+}
+
+// vars collects the names of the tree variables needed by the expression,
+// in order of first appearance.
+func (f *exprFormula) vars(node exprNode, set map[string]struct{}) error {
+	add := func(name string) {
+		if _, dup := set[name]; dup {
+			return
+		}
+		set[name] = struct{}{}
+		f.names = append(f.names, name)
+	}
+
+	switch node := node.(type) {
+	case *exprNum:
+		return nil
+	case *exprIdent:
+		add(node.name)
+		return nil
+	case *exprIndex:
+		add(node.name)
+		return f.vars(node.idx, set)
+	case *exprUnary:
+		return f.vars(node.x, set)
+	case *exprBinary:
+		err := f.vars(node.x, set)
+		if err != nil {
+			return err
+		}
+		return f.vars(node.y, set)
+	case *exprCall:
+		fct, ok := exprFuncs[exprFuncName(node.name)]
+		if !ok {
+			return fmt.Errorf("unknown function %q", node.name)
+		}
+		if got, want := len(node.args), fct.arity; got != want {
+			return fmt.Errorf(
+				"invalid number of arguments to %s (got=%d, want=%d)",
+				node.name, got, want,
+			)
+		}
+		for _, arg := range node.args {
+			err := f.vars(arg, set)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		panic(fmt.Errorf("rfunc: unknown expression node %T", node))
+	}
+}
+
+// exprFuncName returns the normalized name of a formula function.
+func exprFuncName(name string) string {
+	return strings.ToLower(strings.TrimPrefix(name, "TMath::"))
+}
+
+type exprFunc struct {
+	arity int
+	fct   func(args []float64) float64
+}
+
+func exprFunc0(v float64) exprFunc {
+	return exprFunc{arity: 0, fct: func([]float64) float64 { return v }}
+}
+
+func exprFunc1(f func(float64) float64) exprFunc {
+	return exprFunc{arity: 1, fct: func(args []float64) float64 { return f(args[0]) }}
+}
+
+func exprFunc2(f func(x, y float64) float64) exprFunc {
+	return exprFunc{arity: 2, fct: func(args []float64) float64 { return f(args[0], args[1]) }}
+}
+
+var exprFuncs = map[string]exprFunc{
+	"abs":   exprFunc1(math.Abs),
+	"fabs":  exprFunc1(math.Abs),
+	"sqrt":  exprFunc1(math.Sqrt),
+	"exp":   exprFunc1(math.Exp),
+	"log":   exprFunc1(math.Log),
+	"log10": exprFunc1(math.Log10),
+	"sin":   exprFunc1(math.Sin),
+	"cos":   exprFunc1(math.Cos),
+	"tan":   exprFunc1(math.Tan),
+	"asin":  exprFunc1(math.Asin),
+	"acos":  exprFunc1(math.Acos),
+	"atan":  exprFunc1(math.Atan),
+	"sinh":  exprFunc1(math.Sinh),
+	"cosh":  exprFunc1(math.Cosh),
+	"tanh":  exprFunc1(math.Tanh),
+	"floor": exprFunc1(math.Floor),
+	"ceil":  exprFunc1(math.Ceil),
+	"atan2": exprFunc2(math.Atan2),
+	"pow":   exprFunc2(math.Pow),
+	"power": exprFunc2(math.Pow),
+	"fmod":  exprFunc2(math.Mod),
+	"hypot": exprFunc2(math.Hypot),
+	"min":   exprFunc2(math.Min),
+	"max":   exprFunc2(math.Max),
+	"sq":    exprFunc1(func(x float64) float64 { return x * x }),
+	"pi":    exprFunc0(math.Pi),
+	"e":     exprFunc0(math.E),
+}
+
+// exprVar gives access to the value of a bound tree variable.
+type exprVar struct {
+	val func() float64      // scalar access
+	idx func(i int) float64 // indexed access (arrays and slices)
+}
+
+func newExprVar(ptr interface{}) (exprVar, error) {
+	switch ptr := ptr.(type) {
+	case *bool:
+		return exprVar{val: func() float64 { return b2f(*ptr) }}, nil
+	case *int8:
+		return exprVar{val: func() float64 { return float64(*ptr) }}, nil
+	case *int16:
+		return exprVar{val: func() float64 { return float64(*ptr) }}, nil
+	case *int32:
+		return exprVar{val: func() float64 { return float64(*ptr) }}, nil
+	case *int64:
+		return exprVar{val: func() float64 { return float64(*ptr) }}, nil
+	case *uint8:
+		return exprVar{val: func() float64 { return float64(*ptr) }}, nil
+	case *uint16:
+		return exprVar{val: func() float64 { return float64(*ptr) }}, nil
+	case *uint32:
+		return exprVar{val: func() float64 { return float64(*ptr) }}, nil
+	case *uint64:
+		return exprVar{val: func() float64 { return float64(*ptr) }}, nil
+	case *float32:
+		return exprVar{val: func() float64 { return float64(*ptr) }}, nil
+	case *float64:
+		return exprVar{val: func() float64 { return *ptr }}, nil
+	case *[]float32:
+		return exprVar{idx: func(i int) float64 {
+			if i < 0 || i >= len(*ptr) {
+				return math.NaN()
+			}
+			return float64((*ptr)[i])
+		}}, nil
+	case *[]float64:
+		return exprVar{idx: func(i int) float64 {
+			if i < 0 || i >= len(*ptr) {
+				return math.NaN()
+			}
+			return (*ptr)[i]
+		}}, nil
+	case *[]int32:
+		return exprVar{idx: func(i int) float64 {
+			if i < 0 || i >= len(*ptr) {
+				return math.NaN()
+			}
+			return float64((*ptr)[i])
+		}}, nil
+	}
+
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Ptr {
+		return exprVar{}, fmt.Errorf("got=%T, want a pointer to a numerical value", ptr)
+	}
+
+	elem := rv.Type().Elem()
+	switch elem.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		rv := rv.Elem()
+		return exprVar{val: func() float64 { return rv2f(rv) }}, nil
+
+	case reflect.Array, reflect.Slice:
+		switch elem.Elem().Kind() {
+		case reflect.Bool,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+		default:
+			return exprVar{}, fmt.Errorf("got=%T, want a pointer to a numerical value", ptr)
+		}
+		rv := rv.Elem()
+		return exprVar{idx: func(i int) float64 {
+			if i < 0 || i >= rv.Len() {
+				return math.NaN()
+			}
+			return rv2f(rv.Index(i))
+		}}, nil
+	}
+
+	return exprVar{}, fmt.Errorf("got=%T, want a pointer to a numerical value", ptr)
+}
+
+func rv2f(rv reflect.Value) float64 {
+	switch rv.Kind() {
+	case reflect.Bool:
+		return b2f(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	}
+	panic(fmt.Errorf("rfunc: invalid value type %v", rv.Type()))
+}
+
+func b2f(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+func exprCompile(node exprNode, vars map[string]exprVar) (func() float64, error) {
+	switch node := node.(type) {
+	case *exprNum:
+		v := node.v
+		return func() float64 { return v }, nil
+
+	case *exprIdent:
+		v, ok := vars[node.name]
+		if !ok {
+			return nil, fmt.Errorf("unknown variable %q", node.name)
+		}
+		if v.val == nil {
+			return nil, fmt.Errorf("variable %q must be indexed", node.name)
+		}
+		return v.val, nil
+
+	case *exprIndex:
+		v, ok := vars[node.name]
+		if !ok {
+			return nil, fmt.Errorf("unknown variable %q", node.name)
+		}
+		if v.idx == nil {
+			return nil, fmt.Errorf("variable %q can not be indexed", node.name)
+		}
+		idx, err := exprCompile(node.idx, vars)
+		if err != nil {
+			return nil, err
+		}
+		return func() float64 { return v.idx(int(idx())) }, nil
+
+	case *exprUnary:
+		x, err := exprCompile(node.x, vars)
+		if err != nil {
+			return nil, err
+		}
+		switch node.op {
+		case "+":
+			return x, nil
+		case "-":
+			return func() float64 { return -x() }, nil
+		case "!":
+			return func() float64 { return b2f(x() == 0) }, nil
+		}
+		return nil, fmt.Errorf("unknown unary operator %q", node.op)
+
+	case *exprBinary:
+		x, err := exprCompile(node.x, vars)
+		if err != nil {
+			return nil, err
+		}
+		y, err := exprCompile(node.y, vars)
+		if err != nil {
+			return nil, err
+		}
+		switch node.op {
+		case "+":
+			return func() float64 { return x() + y() }, nil
+		case "-":
+			return func() float64 { return x() - y() }, nil
+		case "*":
+			return func() float64 { return x() * y() }, nil
+		case "/":
+			return func() float64 { return x() / y() }, nil
+		case "%":
+			return func() float64 { return math.Mod(x(), y()) }, nil
+		case "^":
+			return func() float64 { return math.Pow(x(), y()) }, nil
+		case "==":
+			return func() float64 { return b2f(x() == y()) }, nil
+		case "!=":
+			return func() float64 { return b2f(x() != y()) }, nil
+		case "<":
+			return func() float64 { return b2f(x() < y()) }, nil
+		case "<=":
+			return func() float64 { return b2f(x() <= y()) }, nil
+		case ">":
+			return func() float64 { return b2f(x() > y()) }, nil
+		case ">=":
+			return func() float64 { return b2f(x() >= y()) }, nil
+		case "&&":
+			return func() float64 { return b2f(x() != 0 && y() != 0) }, nil
+		case "||":
+			return func() float64 { return b2f(x() != 0 || y() != 0) }, nil
+		case "&":
+			return func() float64 { return float64(int64(x()) & int64(y())) }, nil
+		case "|":
+			return func() float64 { return float64(int64(x()) | int64(y())) }, nil
+		case "<<":
+			return func() float64 { return float64(int64(x()) << uint64(y())) }, nil
+		case ">>":
+			return func() float64 { return float64(int64(x()) >> uint64(y())) }, nil
+		}
+		return nil, fmt.Errorf("unknown binary operator %q", node.op)
+
+	case *exprCall:
+		fct := exprFuncs[exprFuncName(node.name)]
+		args := make([]func() float64, len(node.args))
+		for i, arg := range node.args {
+			f, err := exprCompile(arg, vars)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = f
+		}
+		vs := make([]float64, len(args))
+		return func() float64 {
+			for i, arg := range args {
+				vs[i] = arg()
+			}
+			return fct.fct(vs)
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown expression node %T", node)
+}
+
+var (
+	_ Formula = (*exprFormula)(nil)
+)
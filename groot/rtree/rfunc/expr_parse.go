@@ -0,0 +1,335 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rfunc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// exprNode is a node of a parsed formula expression.
+type exprNode interface {
+	isExprNode()
+}
+
+type (
+	exprNum struct {
+		v float64
+	}
+
+	exprIdent struct {
+		name string
+	}
+
+	exprIndex struct {
+		name string
+		idx  exprNode
+	}
+
+	exprUnary struct {
+		op string
+		x  exprNode
+	}
+
+	exprBinary struct {
+		op   string
+		x, y exprNode
+	}
+
+	exprCall struct {
+		name string
+		args []exprNode
+	}
+)
+
+func (*exprNum) isExprNode()    {}
+func (*exprIdent) isExprNode()  {}
+func (*exprIndex) isExprNode()  {}
+func (*exprUnary) isExprNode()  {}
+func (*exprBinary) isExprNode() {}
+func (*exprCall) isExprNode()   {}
+
+type exprTokKind int
+
+const (
+	exprTokEOF exprTokKind = iota
+	exprTokNum
+	exprTokIdent
+	exprTokOp
+)
+
+type exprTok struct {
+	kind exprTokKind
+	pos  int
+	str  string
+}
+
+// exprOps lists the operators and punctuation recognized by the lexer.
+// Longer operators must be listed before their prefixes.
+var exprOps = []string{
+	"**", "&&", "||", "==", "!=", "<=", ">=", "<<", ">>",
+	"+", "-", "*", "/", "%", "^", "!", "<", ">", "&", "|",
+	"(", ")", "[", "]", ",",
+}
+
+func exprLex(expr string) ([]exprTok, error) {
+	var (
+		toks []exprTok
+		i    = 0
+	)
+	isIdent := func(r byte, first bool) bool {
+		c := rune(r)
+		if first {
+			return c == '_' || unicode.IsLetter(c)
+		}
+		return c == '_' || c == '.' || c == '$' || unicode.IsLetter(c) || unicode.IsDigit(c)
+	}
+
+loop:
+	for i < len(expr) {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c >= '0' && c <= '9' || (c == '.' && i+1 < len(expr) && expr[i+1] >= '0' && expr[i+1] <= '9'):
+			beg := i
+			for i < len(expr) {
+				c := expr[i]
+				switch {
+				case c >= '0' && c <= '9', c == '.':
+					i++
+				case (c == 'e' || c == 'E') && i+1 < len(expr):
+					i++
+					if expr[i] == '+' || expr[i] == '-' {
+						i++
+					}
+				default:
+					toks = append(toks, exprTok{kind: exprTokNum, pos: beg, str: expr[beg:i]})
+					continue loop
+				}
+			}
+			toks = append(toks, exprTok{kind: exprTokNum, pos: beg, str: expr[beg:i]})
+
+		case isIdent(c, true):
+			beg := i
+			for i < len(expr) {
+				switch {
+				case isIdent(expr[i], false):
+					i++
+				case strings.HasPrefix(expr[i:], "::"):
+					i += 2
+				default:
+					toks = append(toks, exprTok{kind: exprTokIdent, pos: beg, str: expr[beg:i]})
+					continue loop
+				}
+			}
+			toks = append(toks, exprTok{kind: exprTokIdent, pos: beg, str: expr[beg:i]})
+
+		default:
+			for _, op := range exprOps {
+				if strings.HasPrefix(expr[i:], op) {
+					toks = append(toks, exprTok{kind: exprTokOp, pos: i, str: op})
+					i += len(op)
+					continue loop
+				}
+			}
+			return nil, fmt.Errorf("invalid character %q at position %d", c, i)
+		}
+	}
+	toks = append(toks, exprTok{kind: exprTokEOF, pos: len(expr)})
+	return toks, nil
+}
+
+// exprPrec holds the precedence of binary operators.
+// Unary operators bind tighter than all binary operators but the power.
+var exprPrec = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"|":  3,
+	"&":  4,
+	"==": 5, "!=": 5,
+	"<": 6, "<=": 6, ">": 6, ">=": 6,
+	"<<": 7, ">>": 7,
+	"+": 8, "-": 8,
+	"*": 9, "/": 9, "%": 9,
+}
+
+type exprParser struct {
+	toks []exprTok
+	pos  int
+}
+
+func exprParse(expr string) (exprNode, error) {
+	toks, err := exprLex(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := exprParser{toks: toks}
+	node, err := p.parseBinary(1)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != exprTokEOF {
+		return nil, fmt.Errorf("unexpected token %q at position %d", tok.str, tok.pos)
+	}
+	return node, nil
+}
+
+func (p *exprParser) peek() exprTok { return p.toks[p.pos] }
+func (p *exprParser) next() exprTok {
+	tok := p.toks[p.pos]
+	if tok.kind != exprTokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *exprParser) expect(op string) error {
+	tok := p.next()
+	if tok.kind != exprTokOp || tok.str != op {
+		if tok.kind == exprTokEOF {
+			return fmt.Errorf("unexpected end of expression (want %q)", op)
+		}
+		return fmt.Errorf("unexpected token %q at position %d (want %q)", tok.str, tok.pos, op)
+	}
+	return nil
+}
+
+func (p *exprParser) parseBinary(prec int) (exprNode, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != exprTokOp {
+			return x, nil
+		}
+		oprec, ok := exprPrec[tok.str]
+		if !ok || oprec < prec {
+			return x, nil
+		}
+		p.next()
+		y, err := p.parseBinary(oprec + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = &exprBinary{op: tok.str, x: x, y: y}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	tok := p.peek()
+	if tok.kind == exprTokOp {
+		switch tok.str {
+		case "-", "+", "!":
+			p.next()
+			x, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			return &exprUnary{op: tok.str, x: x}, nil
+		}
+	}
+	return p.parsePower()
+}
+
+func (p *exprParser) parsePower() (exprNode, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	tok := p.peek()
+	if tok.kind == exprTokOp && (tok.str == "^" || tok.str == "**") {
+		p.next()
+		// power is right-associative.
+		y, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &exprBinary{op: "^", x: x, y: y}, nil
+	}
+	return x, nil
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case exprTokNum:
+		v, err := strconv.ParseFloat(tok.str, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", tok.str, tok.pos)
+		}
+		return &exprNum{v: v}, nil
+
+	case exprTokIdent:
+		next := p.peek()
+		if next.kind == exprTokOp {
+			switch next.str {
+			case "(":
+				p.next()
+				return p.parseCall(tok)
+			case "[":
+				p.next()
+				idx, err := p.parseBinary(1)
+				if err != nil {
+					return nil, err
+				}
+				err = p.expect("]")
+				if err != nil {
+					return nil, err
+				}
+				return &exprIndex{name: tok.str, idx: idx}, nil
+			}
+		}
+		return &exprIdent{name: tok.str}, nil
+
+	case exprTokOp:
+		if tok.str == "(" {
+			x, err := p.parseBinary(1)
+			if err != nil {
+				return nil, err
+			}
+			err = p.expect(")")
+			if err != nil {
+				return nil, err
+			}
+			return x, nil
+		}
+		return nil, fmt.Errorf("unexpected token %q at position %d", tok.str, tok.pos)
+
+	default:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+}
+
+func (p *exprParser) parseCall(fct exprTok) (exprNode, error) {
+	call := &exprCall{name: fct.str}
+	if tok := p.peek(); tok.kind == exprTokOp && tok.str == ")" {
+		p.next()
+		return call, nil
+	}
+	for {
+		arg, err := p.parseBinary(1)
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+
+		tok := p.next()
+		if tok.kind == exprTokOp && tok.str == ")" {
+			return call, nil
+		}
+		if tok.kind != exprTokOp || tok.str != "," {
+			if tok.kind == exprTokEOF {
+				return nil, fmt.Errorf("unexpected end of expression (want %q)", ")")
+			}
+			return nil, fmt.Errorf("unexpected token %q at position %d", tok.str, tok.pos)
+		}
+	}
+}
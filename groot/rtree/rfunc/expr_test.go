@@ -0,0 +1,150 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rfunc
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestFormulaExpr(t *testing.T) {
+	var (
+		x   = 2.0
+		y   = int32(-3)
+		ok  = true
+		arr = [3]float32{1, 2, 3}
+		sli = []int64{10, 20}
+	)
+
+	for _, tc := range []struct {
+		expr  string
+		rvars []string
+		args  []interface{}
+		want  float64
+	}{
+		{expr: "42", want: 42},
+		{expr: "1.5e2 + .5", want: 150.5},
+		{expr: "1 + 2 * 3", want: 7},
+		{expr: "(1 + 2) * 3", want: 9},
+		{expr: "2 * 3 ^ 2", want: 18},
+		{expr: "2 ** 3 ** 2", want: 512},
+		{expr: "-2^2", want: -4},
+		{expr: "7 % 4", want: 3},
+		{expr: "1 < 2 && 2 < 1 || 3 == 3", want: 1},
+		{expr: "!(1 != 1)", want: 1},
+		{expr: "6 & 3 | 8", want: 10},
+		{expr: "1 << 4 >> 2", want: 4},
+		{expr: "TMath::Sqrt(16) + sqrt(9) + TMath::Pi() - pi()", want: 7},
+		{expr: "max(1, min(5, 3))", want: 3},
+		{
+			expr:  "x*x + y",
+			rvars: []string{"x", "y"},
+			args:  []interface{}{&x, &y},
+			want:  1,
+		},
+		{
+			expr:  "abs(y) > x && ok",
+			rvars: []string{"y", "x", "ok"},
+			args:  []interface{}{&y, &x, &ok},
+			want:  1,
+		},
+		{
+			expr:  "evt.arr[2] + evt.sli[x-1] + evt.arr[x]",
+			rvars: []string{"evt.arr", "evt.sli", "x"},
+			args:  []interface{}{&arr, &sli, &x},
+			want:  26,
+		},
+		{
+			expr:  "evt.arr[3]",
+			rvars: []string{"evt.arr"},
+			args:  []interface{}{&arr},
+			want:  math.NaN(),
+		},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			form, err := NewFormulaExpr(tc.expr)
+			if err != nil {
+				t.Fatalf("could not create formula: %+v", err)
+			}
+
+			if got, want := form.RVars(), tc.rvars; !reflect.DeepEqual(got, want) {
+				t.Fatalf("invalid rvars: got=%q, want=%q", got, want)
+			}
+
+			err = form.Bind(tc.args)
+			if err != nil {
+				t.Fatalf("could not bind formula: %+v", err)
+			}
+
+			got := form.Func().(func() float64)()
+			if got != tc.want && !(math.IsNaN(got) && math.IsNaN(tc.want)) {
+				t.Fatalf("invalid value: got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFormulaExprErrors(t *testing.T) {
+	for _, tc := range []struct {
+		expr string
+		args []interface{}
+		err  string
+	}{
+		{
+			expr: "1 +",
+			err:  `rfunc: could not parse formula "1 +": unexpected end of expression`,
+		},
+		{
+			expr: "1 # 2",
+			err:  `rfunc: could not parse formula "1 # 2": invalid character '#' at position 2`,
+		},
+		{
+			expr: "1 2",
+			err:  `rfunc: could not parse formula "1 2": unexpected token "2" at position 2`,
+		},
+		{
+			expr: "sqrt(1, 2)",
+			err:  `rfunc: invalid formula "sqrt(1, 2)": invalid number of arguments to sqrt (got=2, want=1)`,
+		},
+		{
+			expr: "foo(2)",
+			err:  `rfunc: invalid formula "foo(2)": unknown function "foo"`,
+		},
+		{
+			expr: "x",
+			args: []interface{}{},
+			err:  `rfunc: invalid number of bind arguments (got=0, want=1)`,
+		},
+		{
+			expr: "x",
+			args: []interface{}{new(string)},
+			err:  `rfunc: argument type 0 (name=x) mismatch: got=*string, want a pointer to a numerical value`,
+		},
+		{
+			expr: "x",
+			args: []interface{}{new([]float64)},
+			err:  `rfunc: could not compile formula "x": variable "x" must be indexed`,
+		},
+		{
+			expr: "x[0]",
+			args: []interface{}{new(float64)},
+			err:  `rfunc: could not compile formula "x[0]": variable "x" can not be indexed`,
+		},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			form, err := NewFormulaExpr(tc.expr)
+			if err == nil {
+				err = form.Bind(tc.args)
+			}
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if got, want := err.Error(), tc.err; got != want {
+				t.Fatalf("invalid error.\ngot= %v\nwant=%v", got, want)
+			}
+		})
+	}
+}
@@ -13,21 +13,21 @@ type rjoin struct {
 
 	rs []*rtree // FIXME(sbinet): handle join of chains?
 
-	rvs  []ReadVar
-	nrab int
-	beg  int64
-	end  int64
+	rvs []ReadVar
+	bko bkopts
+	beg int64
+	end int64
 }
 
-func newRJoin(t *join, rvars []ReadVar, n int, beg, end int64) *rjoin {
+func newRJoin(t *join, rvars []ReadVar, bko bkopts, beg, end int64) *rjoin {
 	rvars = bindRVarsTo(t, rvars)
 	r := &rjoin{
-		j:    t,
-		rs:   make([]*rtree, len(t.trees)),
-		rvs:  rvars,
-		nrab: n,
-		beg:  beg,
-		end:  end,
+		j:   t,
+		rs:  make([]*rtree, len(t.trees)),
+		rvs: rvars,
+		bko: bko,
+		beg: beg,
+		end: end,
 	}
 	rps := make([][]ReadVar, len(r.rs))
 	for i, t := range r.j.trees {
@@ -36,7 +36,7 @@ func newRJoin(t *join, rvars []ReadVar, n int, beg, end int64) *rjoin {
 
 	r.rvs = r.rvs[:0]
 	for i, tree := range t.trees {
-		r.rs[i] = newRTree(tree.(*ttree), rps[i], r.nrab, beg, end)
+		r.rs[i] = newRTree(tree.(*ttree), rps[i], r.bko, beg, end)
 		r.rvs = append(r.rvs, r.rs[i].rvars()...)
 	}
 
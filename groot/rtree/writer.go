@@ -7,6 +7,7 @@ package rtree
 import (
 	"fmt"
 	"reflect"
+	"runtime"
 
 	"go-hep.org/x/hep/groot/internal/rcompress"
 	"go-hep.org/x/hep/groot/rbase"
@@ -34,10 +35,45 @@ type Writer interface {
 type WriteOption func(opt *wopt) error
 
 type wopt struct {
-	title    string // title of the writer tree
-	bufsize  int32  // buffer size for branches
-	splitlvl int32  // maximum split-level for branches
-	compress int32  // compression algorithm name and compression level
+	title    string   // title of the writer tree
+	bufsize  int32    // buffer size for branches
+	splitlvl int32    // maximum split-level for branches
+	compress int32    // compression algorithm name and compression level
+	index    []string // major and minor expressions of the tree index, if any
+	nzip     int      // number of goroutines compressing baskets
+	flush    int64    // number of entries after which baskets are flushed, if any
+
+	branches map[string][]WriteOption // branch-specific options, keyed by write-var name
+}
+
+// branch returns the configuration of the branch associated with the named
+// write-variable, derived from the configuration of its tree.
+func (cfg wopt) branch(name string) (wopt, error) {
+	opts, ok := cfg.branches[name]
+	cfg.branches = nil
+	if !ok {
+		return cfg, nil
+	}
+
+	bcfg := cfg
+	for _, opt := range opts {
+		err := opt(&bcfg)
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	// only the compression, basket size and split level settings
+	// may differ from one branch to another.
+	want := cfg
+	want.compress = bcfg.compress
+	want.bufsize = bcfg.bufsize
+	want.splitlvl = bcfg.splitlvl
+	if !reflect.DeepEqual(bcfg, want) {
+		return cfg, fmt.Errorf("rtree: invalid tree-wide option for write-var %q", name)
+	}
+
+	return bcfg, nil
 }
 
 // WithLZ4 configures a ROOT tree to use LZ4 as a compression mechanism.
@@ -72,6 +108,22 @@ func WithZlib(level int) WriteOption {
 	}
 }
 
+// WithCompressionWorkers configures a ROOT tree to compress its baskets
+// with a pool of n goroutines, while the tree is being filled.
+// Baskets are still written to the file in the order they were filled,
+// so the content of the file does not depend on n.
+// If n is negative, runtime.NumCPU() goroutines are used.
+// The default is 0: baskets are compressed by Write, as they are filled.
+func WithCompressionWorkers(n int) WriteOption {
+	return func(opt *wopt) error {
+		if n < 0 {
+			n = runtime.NumCPU()
+		}
+		opt.nzip = n
+		return nil
+	}
+}
+
 // WithBasketSize configures a ROOT tree to use 'size' (in bytes) as a basket buffer size.
 // if size is <= 0, the default buffer size is used (DefaultBasketSize).
 func WithBasketSize(size int) WriteOption {
@@ -84,6 +136,20 @@ func WithBasketSize(size int) WriteOption {
 	}
 }
 
+// WithAutoFlush configures a ROOT tree to flush the baskets of its branches
+// every n entries, whatever the size of their buffers.
+// Baskets are still flushed when their buffer is full.
+// If n <= 0, baskets are only flushed when their buffer is full (the default).
+func WithAutoFlush(n int64) WriteOption {
+	return func(opt *wopt) error {
+		if n < 0 {
+			n = 0
+		}
+		opt.flush = n
+		return nil
+	}
+}
+
 // WithTitle sets the title of the tree writer.
 func WithTitle(title string) WriteOption {
 	return func(opt *wopt) error {
@@ -100,10 +166,47 @@ func WithSplitLevel(lvl int) WriteOption {
 	}
 }
 
+// WithBranchOptions configures the branch associated with the named
+// write-variable, overriding the settings it inherits from its tree.
+//
+// Per-branch settings are not carried by WriteVar: they are given to
+// NewWriter with WithBranchOptions, keyed by the name of the write-variable.
+// Only WithLZ4, WithLZMA, WithZlib, WithoutCompression, WithBasketSize and
+// WithSplitLevel are valid branch options.
+// In particular, WithAutoFlush is a tree-wide option: all the branches of
+// a tree are flushed at the same entries, so that baskets are aligned on
+// the clusters of the tree.
+func WithBranchOptions(name string, opts ...WriteOption) WriteOption {
+	return func(opt *wopt) error {
+		if opt.branches == nil {
+			opt.branches = make(map[string][]WriteOption)
+		}
+		opt.branches[name] = append(opt.branches[name], opts...)
+		return nil
+	}
+}
+
+// WithIndex configures a ROOT tree to build and store an index of its
+// entries, keyed by the values of the major and minor expressions.
+// The expressions are evaluated with the write-variables of the tree.
+// The syntax of the expressions is described in rfunc.NewFormulaExpr.
+// An empty minor expression is equivalent to "0".
+func WithIndex(major, minor string) WriteOption {
+	return func(opt *wopt) error {
+		if minor == "" {
+			minor = "0"
+		}
+		opt.index = []string{major, minor}
+		return nil
+	}
+}
+
 type wtree struct {
 	ttree
 	wvars []WriteVar
 
+	index *windex // index of entries, if any
+
 	closed bool
 }
 
@@ -150,15 +253,47 @@ func NewWriter(dir riofs.Directory, name string, vars []WriteVar, opts ...WriteO
 	}
 
 	w.ttree.named.SetTitle(cfg.title)
+	if cfg.flush > 0 {
+		w.ttree.autoFlush = cfg.flush
+	}
+
+	for name := range cfg.branches {
+		found := false
+		for _, v := range vars {
+			if v.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("rtree: could not configure branch %q: no such write-var", name)
+		}
+	}
 
 	for _, v := range vars {
-		b, err := newBranchFromWVar(w, v.Name, v, nil, 0, cfg)
+		bcfg, err := cfg.branch(v.Name)
+		if err != nil {
+			return nil, fmt.Errorf("rtree: could not configure branch %q: %w", v.Name, err)
+		}
+		b, err := newBranchFromWVar(w, v.Name, v, nil, 0, bcfg)
 		if err != nil {
 			return nil, fmt.Errorf("rtree: could not create branch for write-var %#v: %w", v, err)
 		}
 		w.ttree.branches = append(w.ttree.branches, b)
 	}
 
+	if cfg.index != nil {
+		idx, err := newWIndex(cfg.index[0], cfg.index[1], vars)
+		if err != nil {
+			return nil, fmt.Errorf("rtree: could not create tree index: %w", err)
+		}
+		w.index = idx
+	}
+
+	if cfg.nzip > 0 {
+		w.ttree.bkw = newBkWriter(w.ttree.f, cfg.nzip)
+	}
+
 	return w, nil
 }
 
@@ -197,10 +332,12 @@ func (w *wtree) Write() (int, error) {
 		}
 		tot += nbytes
 	}
+	if w.index != nil {
+		w.index.add(w.ttree.entries)
+	}
 	w.ttree.entries++
 	w.ttree.totBytes += int64(tot)
 	w.ttree.zipBytes += int64(zip)
-	// FIXME(sbinet): autoflush
 
 	return tot, nil
 }
@@ -213,6 +350,12 @@ func (w *wtree) Flush() error {
 			return fmt.Errorf("rtree: could not flush branch %q: %w", b.Name(), err)
 		}
 	}
+	if w.ttree.bkw != nil {
+		err := w.ttree.bkw.flush()
+		if err != nil {
+			return fmt.Errorf("rtree: could not write baskets: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -225,10 +368,18 @@ func (w *wtree) Close() error {
 		w.closed = true
 	}()
 
+	if w.ttree.bkw != nil {
+		defer w.ttree.bkw.close()
+	}
+
 	if err := w.Flush(); err != nil {
 		return fmt.Errorf("rtree: could not flush tree %q: %w", w.Name(), err)
 	}
 
+	if w.index != nil {
+		w.ttree.treeIndex = newIndex(w.index.major, w.index.minor, w.index.keys)
+	}
+
 	if err := w.ttree.dir.Put(w.Name(), w); err != nil {
 		return fmt.Errorf("rtree: could not save tree %q: %w", w.Name(), err)
 	}
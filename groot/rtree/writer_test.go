@@ -5,6 +5,7 @@
 package rtree
 
 import (
+	"encoding/binary"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -170,3 +171,335 @@ func TestConcurrentWrite(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestCompressionWorkers(t *testing.T) {
+	tmp := t.TempDir()
+
+	const nevts = 5000
+
+	type layout struct {
+		bytes   [][]int32
+		entries [][]int64
+		seeks   [][]int64
+	}
+
+	write := func(t *testing.T, fname string, opts ...WriteOption) {
+		f, err := riofs.Create(fname)
+		if err != nil {
+			t.Fatalf("could not create root file: %+v", err)
+		}
+		defer f.Close()
+
+		var (
+			evt struct {
+				I32 int32
+				F64 float64
+				N   int32
+				Sli []float64 `groot:"Sli[N]"`
+			}
+			wvars = WriteVarsFromStruct(&evt)
+		)
+		w, err := NewWriter(f, "tree", wvars, append([]WriteOption{WithBasketSize(1024)}, opts...)...)
+		if err != nil {
+			t.Fatalf("could not create tree writer: %+v", err)
+		}
+		defer w.Close()
+
+		for i := 0; i < nevts; i++ {
+			evt.I32 = int32(i)
+			evt.F64 = float64(i)
+			evt.N = int32(i % 10)
+			evt.Sli = evt.Sli[:0]
+			for j := 0; j < int(evt.N); j++ {
+				evt.Sli = append(evt.Sli, float64(i+j))
+			}
+			_, err = w.Write()
+			if err != nil {
+				t.Fatalf("could not write event %d: %+v", i, err)
+			}
+		}
+
+		err = w.Close()
+		if err != nil {
+			t.Fatalf("could not close tree writer: %+v", err)
+		}
+
+		err = f.Close()
+		if err != nil {
+			t.Fatalf("could not close root file: %+v", err)
+		}
+	}
+
+	read := func(t *testing.T, fname string) layout {
+		f, err := riofs.Open(fname)
+		if err != nil {
+			t.Fatalf("could not open root file: %+v", err)
+		}
+		defer f.Close()
+
+		o, err := f.Get("tree")
+		if err != nil {
+			t.Fatalf("could not get tree: %+v", err)
+		}
+		tree := o.(Tree)
+
+		var (
+			lay layout
+			zip int64
+			tot int64
+		)
+		for _, b := range tree.Branches() {
+			b := asBranch(b)
+			lay.bytes = append(lay.bytes, b.basketBytes)
+			lay.entries = append(lay.entries, b.basketEntry)
+			lay.seeks = append(lay.seeks, b.basketSeek)
+			zip += b.zipBytes
+			tot += b.totBytes
+		}
+		if zip >= tot {
+			t.Fatalf("baskets not compressed: zip=%d, tot=%d", zip, tot)
+		}
+
+		var (
+			i32 int32
+			f64 float64
+			sli []float64
+		)
+		r, err := NewReader(tree, []ReadVar{
+			{Name: "I32", Value: &i32},
+			{Name: "F64", Value: &f64},
+			{Name: "Sli", Value: &sli},
+		})
+		if err != nil {
+			t.Fatalf("could not create reader: %+v", err)
+		}
+		defer r.Close()
+
+		err = r.Read(func(ctx RCtx) error {
+			i := ctx.Entry
+			if int64(i32) != i || f64 != float64(i) || len(sli) != int(i%10) {
+				return fmt.Errorf("invalid entry %d: i32=%d, f64=%v, sli=%v", i, i32, f64, sli)
+			}
+			for j, v := range sli {
+				if v != float64(i)+float64(j) {
+					return fmt.Errorf("invalid entry %d: sli=%v", i, sli)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("could not read tree: %+v", err)
+		}
+		if got, want := tree.Entries(), int64(nevts); got != want {
+			t.Fatalf("invalid number of entries: got=%d, want=%d", got, want)
+		}
+
+		return lay
+	}
+
+	for _, tc := range []struct {
+		name string
+		opt  WriteOption
+	}{
+		{"zlib", WithZlib(1)},
+		{"lz4", WithLZ4(1)},
+		{"lzma", WithLZMA(1)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// file names of the same length, for the same baskets layout.
+			ref := filepath.Join(tmp, tc.name+"-ref.root")
+			write(t, ref, tc.opt)
+			want := read(t, ref)
+
+			for _, n := range []int{-1, 1, 2, 8} {
+				fname := filepath.Join(tmp, fmt.Sprintf("%s-%+03d.root", tc.name, n))
+				write(t, fname, tc.opt, WithCompressionWorkers(n))
+				got := read(t, fname)
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("invalid baskets layout with %d workers:\ngot= %+v\nwant=%+v", n, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteVarOptions(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "wvar-opts.root")
+
+	const nevts = 1000
+
+	func() {
+		f, err := riofs.Create(fname)
+		if err != nil {
+			t.Fatalf("could not create root file: %+v", err)
+		}
+		defer f.Close()
+
+		var (
+			i32 int32
+			f64 float64
+			u8  uint8
+		)
+		wvars := []WriteVar{
+			{Name: "i32", Value: &i32},
+			{Name: "f64", Value: &f64},
+			{Name: "u8", Value: &u8},
+		}
+
+		_, err = NewWriter(f, "invalid", wvars,
+			WithBranchOptions("i32", WithTitle("title")),
+		)
+		if err == nil {
+			t.Fatalf("expected an error with a tree-wide write-var option")
+		}
+
+		_, err = NewWriter(f, "invalid", wvars,
+			WithBranchOptions("u8", WithAutoFlush(100)),
+		)
+		if err == nil {
+			t.Fatalf("expected an error with a per-branch auto-flush")
+		}
+
+		_, err = NewWriter(f, "invalid", wvars,
+			WithBranchOptions("u8", WithCompressionWorkers(2)),
+		)
+		if err == nil {
+			t.Fatalf("expected an error with per-branch compression workers")
+		}
+
+		_, err = NewWriter(f, "invalid", wvars,
+			WithBranchOptions("i64", WithLZMA(9)),
+		)
+		if err == nil {
+			t.Fatalf("expected an error with an unknown write-var")
+		}
+
+		w, err := NewWriter(f, "tree", wvars,
+			WithZlib(1), WithAutoFlush(300),
+			WithBranchOptions("i32", WithLZMA(9)),
+			WithBranchOptions("f64", WithLZ4(1), WithBasketSize(4096)),
+			WithBranchOptions("u8", WithoutCompression()),
+		)
+		if err != nil {
+			t.Fatalf("could not create tree writer: %+v", err)
+		}
+		defer w.Close()
+
+		for i := 0; i < nevts; i++ {
+			i32 = int32(i)
+			f64 = float64(i)
+			u8 = uint8(i)
+			_, err = w.Write()
+			if err != nil {
+				t.Fatalf("could not write event %d: %+v", i, err)
+			}
+		}
+
+		err = w.Close()
+		if err != nil {
+			t.Fatalf("could not close tree writer: %+v", err)
+		}
+
+		err = f.Close()
+		if err != nil {
+			t.Fatalf("could not close root file: %+v", err)
+		}
+	}()
+
+	f, err := riofs.Open(fname)
+	if err != nil {
+		t.Fatalf("could not open root file: %+v", err)
+	}
+	defer f.Close()
+
+	o, err := f.Get("tree")
+	if err != nil {
+		t.Fatalf("could not get tree: %+v", err)
+	}
+	tree := o.(*ttree)
+
+	if got, want := tree.autoFlush, int64(300); got != want {
+		t.Fatalf("invalid tree auto-flush: got=%d, want=%d", got, want)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		compress int
+		size     int
+		magic    string
+		flush    int64
+	}{
+		{name: "i32", compress: 209, size: defaultBasketSize, magic: "XZ", flush: 300},
+		{name: "f64", compress: 401, size: 4096, magic: "L4", flush: 300},
+		{name: "u8", compress: 0, size: defaultBasketSize, flush: 300},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b := asBranch(tree.Branch(tc.name))
+			if b.compress != tc.compress {
+				t.Fatalf("invalid compression: got=%d, want=%d", b.compress, tc.compress)
+			}
+			if b.basketSize != tc.size {
+				t.Fatalf("invalid basket size: got=%d, want=%d", b.basketSize, tc.size)
+			}
+
+			if tc.flush > 0 {
+				for i, beg := range b.basketEntry[:len(b.basketEntry)-1] {
+					if end := b.basketEntry[i+1]; end-beg > tc.flush {
+						t.Fatalf("invalid basket[%d]: entries=[%d, %d)", i, beg, end)
+					}
+				}
+			}
+
+			nzip := 0
+			for i, seek := range b.basketSeek {
+				buf := make([]byte, b.basketBytes[i])
+				_, err := f.ReadAt(buf, seek)
+				if err != nil {
+					t.Fatalf("could not read basket[%d]: %+v", i, err)
+				}
+				var (
+					objlen = int32(binary.BigEndian.Uint32(buf[6:]))
+					keylen = int32(binary.BigEndian.Uint16(buf[14:]))
+					nbytes = b.basketBytes[i]
+				)
+				if objlen == nbytes-keylen {
+					// not compressed.
+					continue
+				}
+				if got := string(buf[keylen : keylen+2]); got != tc.magic {
+					t.Fatalf("invalid compression of basket[%d]: got=%q, want=%q", i, got, tc.magic)
+				}
+				nzip++
+			}
+			if tc.magic != "" && nzip == 0 {
+				t.Fatalf("no compressed basket")
+			}
+		})
+	}
+
+	var (
+		i32 int32
+		f64 float64
+		u8  uint8
+	)
+	r, err := NewReader(tree, []ReadVar{
+		{Name: "i32", Value: &i32},
+		{Name: "f64", Value: &f64},
+		{Name: "u8", Value: &u8},
+	})
+	if err != nil {
+		t.Fatalf("could not create reader: %+v", err)
+	}
+	defer r.Close()
+
+	err = r.Read(func(ctx RCtx) error {
+		i := ctx.Entry
+		if int64(i32) != i || f64 != float64(i) || u8 != uint8(i) {
+			return fmt.Errorf("invalid entry %d: i32=%d, f64=%v, u8=%d", i, i32, f64, u8)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("could not read tree: %+v", err)
+	}
+}
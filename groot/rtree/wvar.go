@@ -12,6 +12,12 @@ import (
 )
 
 // WriteVar describes a variable to be written out to a tree.
+//
+// The branch created for a variable inherits the compression, basket size
+// and auto-flush settings of its tree.
+// WriteVar does not carry per-branch settings: the compression and basket
+// size of the branch of a given variable are overridden by passing
+// WithBranchOptions to NewWriter.
 type WriteVar struct {
 	Name  string      // name of the variable
 	Value interface{} // pointer to the value to write
@@ -11,6 +11,7 @@ const ROOT = 62600
 
 // ROOT classes versions
 const (
+	Att3D                    = 1  // ROOT version for TAtt3D
 	AttAxis                  = 4  // ROOT version for TAttAxis
 	AttFill                  = 2  // ROOT version for TAttFill
 	AttLine                  = 2  // ROOT version for TAttLine
@@ -70,6 +71,8 @@ const (
 	GraphErrors              = 3  // ROOT version for TGraphErrors
 	GraphAsymmErrors         = 3  // ROOT version for TGraphAsymmErrors
 	GraphMultiErrors         = 1  // ROOT version for TGraphMultiErrors
+	Graph2D                  = 1  // ROOT version for TGraph2D
+	Graph2DErrors            = 1  // ROOT version for TGraph2DErrors
 	H1                       = 8  // ROOT version for TH1
 	H1C                      = 3  // ROOT version for TH1C
 	H1D                      = 3  // ROOT version for TH1D
@@ -85,6 +88,12 @@ const (
 	H2Poly                   = 3  // ROOT version for TH2Poly
 	H2PolyBin                = 1  // ROOT version for TH2PolyBin
 	H2S                      = 4  // ROOT version for TH2S
+	H3                       = 6  // ROOT version for TH3
+	H3C                      = 4  // ROOT version for TH3C
+	H3D                      = 4  // ROOT version for TH3D
+	H3F                      = 4  // ROOT version for TH3F
+	H3I                      = 4  // ROOT version for TH3I
+	H3S                      = 4  // ROOT version for TH3S
 	Limit                    = 2  // ROOT version for TLimit
 	LimitDataSource          = 2  // ROOT version for TLimitDataSource
 	MultiGraph               = 2  // ROOT version for TMultiGraph
@@ -105,6 +114,8 @@ const (
 	BranchObject             = 1  // ROOT version for TBranchObject
 	BranchRef                = 1  // ROOT version for TBranchRef
 	Chain                    = 5  // ROOT version for TChain
+	EntryList                = 2  // ROOT version for TEntryList
+	EntryListBlock           = 1  // ROOT version for TEntryListBlock
 	Leaf                     = 2  // ROOT version for TLeaf
 	LeafElement              = 1  // ROOT version for TLeafElement
 	LeafObject               = 4  // ROOT version for TLeafObject
@@ -121,4 +132,6 @@ const (
 	Ntuple                   = 2  // ROOT version for TNtuple
 	NtupleD                  = 1  // ROOT version for TNtupleD
 	Tree                     = 20 // ROOT version for TTree
+	TreeIndex                = 2  // ROOT version for TTreeIndex
+	VirtualIndex             = 1  // ROOT version for TVirtualIndex
 )
@@ -18,6 +18,10 @@ func (Bin2D) Rank() int { return 2 }
 // 	b.Dist.scaleW(f)
 // }
 
+func (b *Bin2D) addScaled(a, a2 float64, o Bin2D) {
+	b.Dist.addScaled(a, a2, o.Dist)
+}
+
 func (b *Bin2D) fill(x, y, w float64) {
 	b.Dist.fill(x, y, w)
 }
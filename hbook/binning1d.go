@@ -28,6 +28,15 @@ var (
 	errShortYAxis     = errors.New("hbook: too few 1-dim Y-bins")
 	errNotSortedYAxis = errors.New("hbook: Y-edges slice not sorted")
 	errDupEdgesYAxis  = errors.New("hbook: duplicates in Y-edge values")
+
+	errInvalidZAxis   = errors.New("hbook: invalid Z-axis limits")
+	errEmptyZAxis     = errors.New("hbook: Z-axis with zero bins")
+	errShortZAxis     = errors.New("hbook: too few 1-dim Z-bins")
+	errNotSortedZAxis = errors.New("hbook: Z-edges slice not sorted")
+	errDupEdgesZAxis  = errors.New("hbook: duplicates in Z-edge values")
+
+	errInvalidRebin = errors.New("hbook: invalid rebinning factor")
+	errRebinEdges   = errors.New("hbook: rebinning edges do not match bin edges")
 )
 
 // Binning1D is a 1-dim binning of the x-axis.
@@ -178,6 +187,113 @@ func (bng *Binning1D) scaleW(f float64) {
 	}
 }
 
+// rebin returns a new binning where each group of n consecutive bins
+// has been merged into a single bin.
+func (bng *Binning1D) rebin(n int) Binning1D {
+	if n <= 0 || len(bng.Bins)%n != 0 {
+		panic(errInvalidRebin)
+	}
+	edges := make([]float64, 0, len(bng.Bins)/n+1)
+	for i := 0; i < len(bng.Bins); i += n {
+		edges = append(edges, bng.Bins[i].Range.Min)
+	}
+	edges = append(edges, bng.Bins[len(bng.Bins)-1].Range.Max)
+	return bng.rebinEdges(edges)
+}
+
+// rebinEdges returns a new binning with the provided edges.
+// Bins outside of the new edges are merged into the under/over flows.
+func (bng *Binning1D) rebinEdges(edges []float64) Binning1D {
+	idx := rebinIndices(edges, len(bng.Bins), func(i int) Range {
+		return bng.Bins[i].Range
+	})
+
+	n := len(edges) - 1
+	o := Binning1D{
+		Bins: make([]Bin1D, n),
+		Dist: bng.Dist.clone(),
+		Outflows: [2]Dist1D{
+			bng.Outflows[0].clone(),
+			bng.Outflows[1].clone(),
+		},
+		XRange: Range{Min: edges[0], Max: edges[n]},
+	}
+	for i := range o.Bins {
+		bin := &o.Bins[i]
+		bin.Range.Min = edges[i]
+		bin.Range.Max = edges[i+1]
+	}
+
+	for i, bin := range bng.Bins {
+		switch j := idx[i]; {
+		case j < 0:
+			o.Outflows[-j-1].addScaled(1, 1, bin.Dist)
+		default:
+			o.Bins[j].Dist.addScaled(1, 1, bin.Dist)
+		}
+	}
+
+	return o
+}
+
+// rebinIndices returns, for each of the n (sorted) bins whose range is
+// given by rng, the index of the new bin defined by edges it falls into.
+// Bins below (above) the first (last) edge are associated with
+// UnderflowBin1D (OverflowBin1D).
+//
+// rebinIndices panics if edges do not match existing bin edges.
+func rebinIndices(edges []float64, n int, rng func(i int) Range) []int {
+	if len(edges) <= 1 {
+		panic(errShortXAxis)
+	}
+	for i := 1; i < len(edges); i++ {
+		switch {
+		case edges[i] == edges[i-1]:
+			panic(errDupEdgesXAxis)
+		case edges[i] < edges[i-1]:
+			panic(errNotSortedXAxis)
+		}
+	}
+
+	var (
+		idx   = make([]int, n)
+		match = make([]bool, len(edges))
+		last  = len(edges) - 1
+		le    = func(a, b float64) bool { return a < b || fuzzyEq(a, b) }
+		j     = 0
+	)
+	for i := range idx {
+		r := rng(i)
+		for k, e := range edges {
+			if fuzzyEq(e, r.Min) || fuzzyEq(e, r.Max) {
+				match[k] = true
+			}
+		}
+		switch {
+		case le(r.Max, edges[0]):
+			idx[i] = UnderflowBin1D
+		case le(edges[last], r.Min):
+			idx[i] = OverflowBin1D
+		default:
+			for j < last-1 && le(edges[j+1], r.Min) {
+				j++
+			}
+			if !le(edges[j], r.Min) || !le(r.Max, edges[j+1]) {
+				panic(errRebinEdges)
+			}
+			idx[i] = j
+		}
+	}
+
+	for _, ok := range match {
+		if !ok {
+			panic(errRebinEdges)
+		}
+	}
+
+	return idx
+}
+
 func (bng *Binning1D) Underflow() *Dist1D {
 	return &bng.Outflows[0]
 }
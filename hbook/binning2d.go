@@ -133,6 +133,14 @@ func newBinning2DFromEdges(xedges, yedges []float64) Binning2D {
 	return bng
 }
 
+func (bng *Binning2D) clone() Binning2D {
+	o := *bng
+	o.Bins = append([]Bin2D(nil), bng.Bins...)
+	o.XEdges = append([]Bin1D(nil), bng.XEdges...)
+	o.YEdges = append([]Bin1D(nil), bng.YEdges...)
+	return o
+}
+
 func (bng *Binning2D) entries() int64 {
 	return bng.Dist.Entries()
 }
@@ -0,0 +1,255 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hbook
+
+import "sort"
+
+// Binning3D is a 3-dim binning of the (x,y,z) space.
+type Binning3D struct {
+	Bins []Bin3D
+	Dist Dist3D
+
+	// Outflows holds the distributions of the 26 regions surrounding
+	// the binned volume.
+	// The region of a point is described by its position along each axis,
+	// kx, ky and kz: -1 for underflow, 0 for in-range and +1 for overflow.
+	// The region is then stored at index:
+	//  i = (kx+1)*9 + (ky+1)*3 + (kz+1)
+	// minus 1 if i is greater than 13 (the binned volume itself).
+	Outflows [26]Dist3D
+
+	XRange Range
+	YRange Range
+	ZRange Range
+	Nx     int
+	Ny     int
+	Nz     int
+	XEdges []Bin1D
+	YEdges []Bin1D
+	ZEdges []Bin1D
+}
+
+func newBinning3D(nx int, xlow, xhigh float64, ny int, ylow, yhigh float64, nz int, zlow, zhigh float64) Binning3D {
+	if xlow >= xhigh {
+		panic(errInvalidXAxis)
+	}
+	if ylow >= yhigh {
+		panic(errInvalidYAxis)
+	}
+	if zlow >= zhigh {
+		panic(errInvalidZAxis)
+	}
+	if nx <= 0 {
+		panic(errEmptyXAxis)
+	}
+	if ny <= 0 {
+		panic(errEmptyYAxis)
+	}
+	if nz <= 0 {
+		panic(errEmptyZAxis)
+	}
+
+	edges := func(n int, low, high float64) []float64 {
+		width := (high - low) / float64(n)
+		vs := make([]float64, n+1)
+		for i := range vs {
+			vs[i] = low + float64(i)*width
+		}
+		vs[n] = high
+		return vs
+	}
+
+	bng := Binning3D{
+		XRange: Range{Min: xlow, Max: xhigh},
+		YRange: Range{Min: ylow, Max: yhigh},
+		ZRange: Range{Min: zlow, Max: zhigh},
+		Nx:     nx,
+		Ny:     ny,
+		Nz:     nz,
+	}
+	bng.setEdges(edges(nx, xlow, xhigh), edges(ny, ylow, yhigh), edges(nz, zlow, zhigh))
+	return bng
+}
+
+func newBinning3DFromEdges(xedges, yedges, zedges []float64) Binning3D {
+	if len(xedges) <= 1 {
+		panic(errShortXAxis)
+	}
+	if !sort.IsSorted(sort.Float64Slice(xedges)) {
+		panic(errNotSortedXAxis)
+	}
+	if len(yedges) <= 1 {
+		panic(errShortYAxis)
+	}
+	if !sort.IsSorted(sort.Float64Slice(yedges)) {
+		panic(errNotSortedYAxis)
+	}
+	if len(zedges) <= 1 {
+		panic(errShortZAxis)
+	}
+	if !sort.IsSorted(sort.Float64Slice(zedges)) {
+		panic(errNotSortedZAxis)
+	}
+	for _, v := range []struct {
+		edges []float64
+		err   error
+	}{
+		{xedges, errDupEdgesXAxis},
+		{yedges, errDupEdgesYAxis},
+		{zedges, errDupEdgesZAxis},
+	} {
+		for i := 1; i < len(v.edges); i++ {
+			if v.edges[i-1] == v.edges[i] {
+				panic(v.err)
+			}
+		}
+	}
+
+	var (
+		nx = len(xedges) - 1
+		ny = len(yedges) - 1
+		nz = len(zedges) - 1
+	)
+	bng := Binning3D{
+		XRange: Range{Min: xedges[0], Max: xedges[nx]},
+		YRange: Range{Min: yedges[0], Max: yedges[ny]},
+		ZRange: Range{Min: zedges[0], Max: zedges[nz]},
+		Nx:     nx,
+		Ny:     ny,
+		Nz:     nz,
+	}
+	bng.setEdges(xedges, yedges, zedges)
+	return bng
+}
+
+// setEdges creates the bins of the binning from the provided edges.
+func (bng *Binning3D) setEdges(xedges, yedges, zedges []float64) {
+	axis := func(edges []float64) []Bin1D {
+		bins := make([]Bin1D, len(edges)-1)
+		for i := range bins {
+			bins[i].Range.Min = edges[i]
+			bins[i].Range.Max = edges[i+1]
+		}
+		return bins
+	}
+
+	bng.XEdges = axis(xedges)
+	bng.YEdges = axis(yedges)
+	bng.ZEdges = axis(zedges)
+	bng.Bins = make([]Bin3D, bng.Nx*bng.Ny*bng.Nz)
+	for iz, zbin := range bng.ZEdges {
+		for iy, ybin := range bng.YEdges {
+			for ix, xbin := range bng.XEdges {
+				bin := &bng.Bins[bng.index(ix, iy, iz)]
+				bin.XRange = xbin.Range
+				bin.YRange = ybin.Range
+				bin.ZRange = zbin.Range
+			}
+		}
+	}
+}
+
+func (bng *Binning3D) entries() int64 {
+	return bng.Dist.Entries()
+}
+
+func (bng *Binning3D) effEntries() float64 {
+	return bng.Dist.EffEntries()
+}
+
+// xMin returns the low edge of the X-axis
+func (bng *Binning3D) xMin() float64 {
+	return bng.XRange.Min
+}
+
+// xMax returns the high edge of the X-axis
+func (bng *Binning3D) xMax() float64 {
+	return bng.XRange.Max
+}
+
+// yMin returns the low edge of the Y-axis
+func (bng *Binning3D) yMin() float64 {
+	return bng.YRange.Min
+}
+
+// yMax returns the high edge of the Y-axis
+func (bng *Binning3D) yMax() float64 {
+	return bng.YRange.Max
+}
+
+// zMin returns the low edge of the Z-axis
+func (bng *Binning3D) zMin() float64 {
+	return bng.ZRange.Min
+}
+
+// zMax returns the high edge of the Z-axis
+func (bng *Binning3D) zMax() float64 {
+	return bng.ZRange.Max
+}
+
+// index returns the index of the bin (ix,iy,iz) in the slice of bins.
+func (bng *Binning3D) index(ix, iy, iz int) int {
+	return (iz*bng.Ny+iy)*bng.Nx + ix
+}
+
+func (bng *Binning3D) fill(x, y, z, w float64) {
+	idx := bng.coordToIndex(x, y, z)
+	bng.Dist.fill(x, y, z, w)
+	if idx == len(bng.Bins) {
+		// GAP bin
+		return
+	}
+	if idx < 0 {
+		bng.Outflows[-idx-1].fill(x, y, z, w)
+		return
+	}
+	bng.Bins[idx].fill(x, y, z, w)
+}
+
+// coordToIndex returns the index of the bin containing (x,y,z).
+// coordToIndex returns -(i+1) for a point located in the i-th outflow region,
+// and len(bng.Bins) for a point located in a gap.
+func (bng *Binning3D) coordToIndex(x, y, z float64) int {
+	var (
+		ix = Bin1Ds(bng.XEdges).IndexOf(x)
+		iy = Bin1Ds(bng.YEdges).IndexOf(y)
+		iz = Bin1Ds(bng.ZEdges).IndexOf(z)
+	)
+
+	if ix == bng.Nx || iy == bng.Ny || iz == bng.Nz {
+		// GAP
+		return len(bng.Bins)
+	}
+
+	kx := outflowKind(ix)
+	ky := outflowKind(iy)
+	kz := outflowKind(iz)
+	if kx == 0 && ky == 0 && kz == 0 {
+		return bng.index(ix, iy, iz)
+	}
+	return -outflowIndex3D(kx, ky, kz) - 1
+}
+
+// outflowKind returns -1, 0 or +1 for an underflow, in-range or overflow
+// 1-dim index.
+func outflowKind(i int) int {
+	switch i {
+	case UnderflowBin1D:
+		return -1
+	case OverflowBin1D:
+		return +1
+	}
+	return 0
+}
+
+// outflowIndex3D returns the index in Binning3D.Outflows of the region
+// described by (kx,ky,kz).
+func outflowIndex3D(kx, ky, kz int) int {
+	i := (kx+1)*9 + (ky+1)*3 + (kz + 1)
+	if i > 13 {
+		i--
+	}
+	return i
+}
@@ -0,0 +1,199 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hbook
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// EfficiencyH1D computes the bin-by-bin efficiency of the pass histogram
+// with regard to the total histogram, and returns a 2D scatter with
+// asymmetric errors.
+//
+// Bins with no entry in the total histogram are skipped.
+// EfficiencyH1D returns an error if the binning of the 1D histograms are
+// not compatible, or if a bin of the pass histogram has more weighted
+// entries than the total one.
+// If no EffOptions is passed, binomial errors are computed.
+func EfficiencyH1D(pass, total *H1D, opts ...EffOptions) (*S2D, error) {
+	cfg := newEffConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	s2d := NewS2D()
+
+	bins1 := pass.Binning.Bins
+	bins2 := total.Binning.Bins
+	if len(bins1) != len(bins2) {
+		return nil, fmt.Errorf("hbook: x binnings are not equivalent in %v / %v", pass.Name(), total.Name())
+	}
+
+	for i := range bins1 {
+		b1 := &bins1[i]
+		b2 := &bins2[i]
+
+		if !fuzzyEq(b1.XMin(), b2.XMin()) || !fuzzyEq(b1.XMax(), b2.XMax()) {
+			return nil, fmt.Errorf("hbook: x binnings are not equivalent in %v / %v", pass.Name(), total.Name())
+		}
+
+		if b2.SumW() == 0 {
+			continue
+		}
+
+		eff, lo, hi, err := cfg.eff(b1.Dist.Dist, b2.Dist.Dist)
+		if err != nil {
+			return nil, fmt.Errorf("hbook: invalid efficiency for bin %d: %w", i, err)
+		}
+
+		x := b1.XMid()
+		s2d.Fill(Point2D{
+			X: x, Y: eff,
+			ErrX: Range{Min: x - b1.XMin(), Max: b1.XMax() - x},
+			ErrY: Range{Min: lo, Max: hi},
+		})
+	}
+	return s2d, nil
+}
+
+// EfficiencyH2D computes the bin-by-bin efficiency of the pass histogram
+// with regard to the total histogram, and returns a 3D scatter with
+// asymmetric errors.
+//
+// Bins with no entry in the total histogram are skipped.
+// EfficiencyH2D returns an error if the binning of the 2D histograms are
+// not compatible, or if a bin of the pass histogram has more weighted
+// entries than the total one.
+// If no EffOptions is passed, binomial errors are computed.
+func EfficiencyH2D(pass, total *H2D, opts ...EffOptions) (*S3D, error) {
+	cfg := newEffConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	s3d := NewS3D()
+
+	bins1 := pass.Binning.Bins
+	bins2 := total.Binning.Bins
+	if len(bins1) != len(bins2) {
+		return nil, fmt.Errorf("hbook: binnings are not equivalent in %v / %v", pass.Name(), total.Name())
+	}
+
+	for i := range bins1 {
+		b1 := &bins1[i]
+		b2 := &bins2[i]
+
+		if !equivBins2D(b1, b2) {
+			return nil, fmt.Errorf("hbook: binnings are not equivalent in %v / %v", pass.Name(), total.Name())
+		}
+
+		if b2.SumW() == 0 {
+			continue
+		}
+
+		eff, lo, hi, err := cfg.eff(b1.Dist.X.Dist, b2.Dist.X.Dist)
+		if err != nil {
+			return nil, fmt.Errorf("hbook: invalid efficiency for bin %d: %w", i, err)
+		}
+
+		pt := newPoint3DFromBin2D(b1, eff, 0)
+		pt.ErrZ = Range{Min: lo, Max: hi}
+		s3d.Fill(pt)
+	}
+	return s3d, nil
+}
+
+// EffOptions allows to customize the behaviour of EfficiencyH1D and EfficiencyH2D.
+type EffOptions func(c *effConfig)
+
+// effConfig type specifies the possible configurations
+// passed as EffOptions.
+type effConfig struct {
+	bayes bool    // whether to compute Bayesian intervals
+	cl    float64 // confidence level of the Bayesian intervals
+}
+
+// newEffConfig function builds the default configuration
+// for the efficiency functions.
+func newEffConfig() *effConfig {
+	return &effConfig{cl: oneSigma}
+}
+
+// oneSigma is the probability content of a 1-sigma interval
+// of a normal distribution.
+const oneSigma = 0.682689492137086
+
+// EffBinomial configures the efficiency functions to compute
+// errors from the normal approximation of the binomial distribution.
+// This is the default.
+//
+// Weighted entries are taken into account as TH1::Divide does with
+// its "B" option.
+func EffBinomial() EffOptions {
+	return func(c *effConfig) {
+		c.bayes = false
+	}
+}
+
+// EffBayesian configures the efficiency functions to compute
+// Bayesian central intervals with the provided confidence level,
+// using a uniform prior.
+// The efficiency is then the mean of the posterior Beta distribution,
+// as TEfficiency does by default.
+// If cl is not within (0,1), a 68.27% (1-sigma) confidence level is used.
+//
+// Weighted entries are normalized to the effective number of entries
+// of the total histogram.
+func EffBayesian(cl float64) EffOptions {
+	return func(c *effConfig) {
+		c.bayes = true
+		c.cl = cl
+		if !(0 < cl && cl < 1) {
+			c.cl = oneSigma
+		}
+	}
+}
+
+// eff returns the efficiency of pass with regard to total, and the
+// lower and upper errors on that efficiency.
+func (cfg *effConfig) eff(pass, total Dist0D) (eff, lo, hi float64, err error) {
+	var (
+		k  = pass.SumW
+		n  = total.SumW
+		k2 = pass.SumW2
+		n2 = total.SumW2
+	)
+	switch {
+	case k < 0 || n < 0:
+		return 0, 0, 0, fmt.Errorf("negative sum of weights (pass=%v, total=%v)", k, n)
+	case k > n && !fuzzyEq(k, n):
+		return 0, 0, 0, fmt.Errorf("pass=%v > total=%v", k, n)
+	}
+	k = math.Min(k, n)
+
+	if !cfg.bayes {
+		eff = k / n
+		e := math.Sqrt(math.Abs((1-2*eff)*k2+eff*eff*n2)) / n
+		return eff, math.Min(e, eff), math.Min(e, 1-eff), nil
+	}
+
+	norm := 1.0
+	if n2 > 0 {
+		norm = n / n2
+	}
+	beta := distuv.Beta{
+		Alpha: k*norm + 1,
+		Beta:  (n-k)*norm + 1,
+	}
+	var (
+		mean = beta.Mean()
+		low  = beta.Quantile(0.5 * (1 - cfg.cl))
+		up   = beta.Quantile(0.5 * (1 + cfg.cl))
+	)
+	return mean, mean - low, up - mean, nil
+}
@@ -0,0 +1,160 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hbook
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestEfficiencyH1D(t *testing.T) {
+	var (
+		pass  = NewH1D(4, 0, 4)
+		total = NewH1D(4, 0, 4)
+	)
+	for i, v := range [][2]float64{
+		{3, 4}, // bin 0: 3/4
+		{0, 2}, // bin 1: 0/2
+		{5, 5}, // bin 2: 5/5
+		{0, 0}, // bin 3: empty
+	} {
+		x := float64(i) + 0.5
+		for j := 0; j < int(v[1]); j++ {
+			total.Fill(x, 1)
+			if j < int(v[0]) {
+				pass.Fill(x, 1)
+			}
+		}
+	}
+
+	t.Run("binomial", func(t *testing.T) {
+		s, err := EfficiencyH1D(pass, total)
+		if err != nil {
+			t.Fatalf("could not compute efficiency: %+v", err)
+		}
+		if got, want := s.Len(), 3; got != want {
+			t.Fatalf("invalid number of points: got=%d, want=%d", got, want)
+		}
+
+		e := math.Sqrt(0.75 * 0.25 / 4)
+		for i, want := range []Point2D{
+			{X: 0.5, Y: 0.75, ErrX: Range{Min: 0.5, Max: 0.5}, ErrY: Range{Min: e, Max: e}},
+			{X: 1.5, Y: 0, ErrX: Range{Min: 0.5, Max: 0.5}, ErrY: Range{Min: 0, Max: 0}},
+			{X: 2.5, Y: 1, ErrX: Range{Min: 0.5, Max: 0.5}, ErrY: Range{Min: 0, Max: 0}},
+		} {
+			got := s.Point(i)
+			if !cmpPoint2D(got, want) {
+				t.Fatalf("invalid point %d:\ngot= %+v\nwant=%+v", i, got, want)
+			}
+		}
+	})
+
+	t.Run("bayesian", func(t *testing.T) {
+		const cl = 0.9
+		s, err := EfficiencyH1D(pass, total, EffBayesian(cl))
+		if err != nil {
+			t.Fatalf("could not compute efficiency: %+v", err)
+		}
+		if got, want := s.Len(), 3; got != want {
+			t.Fatalf("invalid number of points: got=%d, want=%d", got, want)
+		}
+
+		for i, v := range [][2]float64{{3, 4}, {0, 2}, {5, 5}} {
+			var (
+				pt   = s.Point(i)
+				beta = distuv.Beta{Alpha: v[0] + 1, Beta: v[1] - v[0] + 1}
+				mean = (v[0] + 1) / (v[1] + 2)
+			)
+			if math.Abs(pt.Y-mean) > 1e-12 {
+				t.Fatalf("invalid efficiency for point %d: got=%v, want=%v", i, pt.Y, mean)
+			}
+			lo := beta.CDF(pt.Y - pt.ErrY.Min)
+			hi := beta.CDF(pt.Y + pt.ErrY.Max)
+			if math.Abs(lo-0.05) > 1e-6 || math.Abs(hi-0.95) > 1e-6 {
+				t.Fatalf("invalid interval for point %d: cdf(lo)=%v, cdf(hi)=%v", i, lo, hi)
+			}
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := EfficiencyH1D(total, pass)
+		if err == nil {
+			t.Fatalf("expected an error (pass > total)")
+		}
+		_, err = EfficiencyH1D(pass, NewH1D(4, 0, 5))
+		if err == nil {
+			t.Fatalf("expected an error (binning)")
+		}
+	})
+}
+
+func TestEfficiencyH2D(t *testing.T) {
+	var (
+		pass  = NewH2D(2, 0, 2, 2, 0, 2)
+		total = NewH2D(2, 0, 2, 2, 0, 2)
+	)
+	for i := 0; i < 10; i++ {
+		total.Fill(0.5, 1.5, 1)
+		if i < 2 {
+			pass.Fill(0.5, 1.5, 1)
+		}
+	}
+
+	s, err := EfficiencyH2D(pass, total)
+	if err != nil {
+		t.Fatalf("could not compute efficiency: %+v", err)
+	}
+	if got, want := s.Len(), 1; got != want {
+		t.Fatalf("invalid number of points: got=%d, want=%d", got, want)
+	}
+	pt := s.Point(0)
+	if pt.X != 0.5 || pt.Y != 1.5 || pt.Z != 0.2 {
+		t.Fatalf("invalid point: %+v", pt)
+	}
+	if got, want := pt.ErrZ.Min, math.Sqrt(0.2*0.8/10); math.Abs(got-want) > 1e-12 {
+		t.Fatalf("invalid error: got=%v, want=%v", got, want)
+	}
+
+	_, err = EfficiencyH2D(total, pass)
+	if err == nil {
+		t.Fatalf("expected an error (pass > total)")
+	}
+}
+
+func TestEfficiencyAnnotation(t *testing.T) {
+	var (
+		h1 = NewH1D(2, 0, 2)
+		h2 = NewH2D(2, 0, 2, 2, 0, 2)
+	)
+	h1.Fill(0.5, 1)
+	h2.Fill(0.5, 0.5, 1)
+
+	s2, err := EfficiencyH1D(h1, h1)
+	if err != nil {
+		t.Fatalf("could not compute efficiency: %+v", err)
+	}
+	s2.Annotation()["name"] = "eff"
+	if got, want := s2.Name(), "eff"; got != want {
+		t.Fatalf("invalid name: got=%q, want=%q", got, want)
+	}
+
+	s3, err := EfficiencyH2D(h2, h2)
+	if err != nil {
+		t.Fatalf("could not compute efficiency: %+v", err)
+	}
+	s3.Annotation()["name"] = "eff"
+	if got, want := s3.Name(), "eff"; got != want {
+		t.Fatalf("invalid name: got=%q, want=%q", got, want)
+	}
+}
+
+func cmpPoint2D(a, b Point2D) bool {
+	eq := func(a, b float64) bool { return math.Abs(a-b) < 1e-12 }
+	return eq(a.X, b.X) && eq(a.Y, b.Y) &&
+		eq(a.ErrX.Min, b.ErrX.Min) && eq(a.ErrX.Max, b.ErrX.Max) &&
+		eq(a.ErrY.Min, b.ErrY.Min) && eq(a.ErrY.Max, b.ErrY.Max)
+}
@@ -195,6 +195,32 @@ func (h *H1D) Scale(factor float64) {
 	h.Binning.scaleW(factor)
 }
 
+// Rebin returns a new histogram where each group of n consecutive bins
+// has been merged into a single bin.
+// The receiver is left unmodified.
+// Rebin panics if n is not a positive divisor of the number of bins.
+func (h *H1D) Rebin(n int) *H1D {
+	return &H1D{
+		Binning: h.Binning.rebin(n),
+		Ann:     h.Ann.clone(),
+	}
+}
+
+// RebinEdges returns a new histogram with the provided bin edges.
+// The receiver is left unmodified.
+//
+// Each edge must match an edge of the bins of the histogram.
+// Bins below (above) the first (last) edge are merged into the
+// underflow (overflow) bin.
+// RebinEdges panics if the edges are not sorted, if there are
+// duplicate edge values or if an edge does not match the current binning.
+func (h *H1D) RebinEdges(edges []float64) *H1D {
+	return &H1D{
+		Binning: h.Binning.rebinEdges(edges),
+		Ann:     h.Ann.clone(),
+	}
+}
+
 // Integral computes the integral of the histogram.
 //
 // The number of parameters can be 0 or 2.
@@ -737,3 +737,135 @@ func TestH1DClone(t *testing.T) {
 		)
 	}
 }
+
+func TestH1DRebin(t *testing.T) {
+	var (
+		xs = []float64{-1, 0, 0.5, 1, 2, 2.5, 3, 4, 5, 6, 6.5, 7, 8, 9, 9.5, 11}
+		ws = []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	)
+
+	for _, tc := range []struct {
+		name string
+		got  func(h *H1D) *H1D
+		want *H1D
+	}{
+		{
+			name: "rebin-1",
+			got:  func(h *H1D) *H1D { return h.Rebin(1) },
+			want: NewH1D(10, 0, 10),
+		},
+		{
+			name: "rebin-2",
+			got:  func(h *H1D) *H1D { return h.Rebin(2) },
+			want: NewH1D(5, 0, 10),
+		},
+		{
+			name: "rebin-10",
+			got:  func(h *H1D) *H1D { return h.Rebin(10) },
+			want: NewH1D(1, 0, 10),
+		},
+		{
+			name: "edges",
+			got:  func(h *H1D) *H1D { return h.RebinEdges([]float64{0, 1, 4, 5, 10}) },
+			want: NewH1DFromEdges([]float64{0, 1, 4, 5, 10}),
+		},
+		{
+			name: "edges-outflows",
+			got:  func(h *H1D) *H1D { return h.RebinEdges([]float64{2, 4, 7}) },
+			want: NewH1DFromEdges([]float64{2, 4, 7}),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewH1D(10, 0, 10)
+			h.Ann["name"] = "h1"
+			h.FillN(xs, ws)
+			tc.want.Ann["name"] = "h1"
+			tc.want.FillN(xs, ws)
+
+			orig, err := h.MarshalYODA()
+			if err != nil {
+				t.Fatalf("could not marshal original: %+v", err)
+			}
+
+			got, err := tc.got(h).MarshalYODA()
+			if err != nil {
+				t.Fatalf("could not marshal rebinned histogram: %+v", err)
+			}
+
+			want, err := tc.want.MarshalYODA()
+			if err != nil {
+				t.Fatalf("could not marshal reference: %+v", err)
+			}
+
+			if !bytes.Equal(got, want) {
+				t.Fatalf("invalid rebinned histogram:\n%s", cmp.Diff(string(want), string(got)))
+			}
+
+			chk, err := h.MarshalYODA()
+			if err != nil {
+				t.Fatalf("could not marshal original: %+v", err)
+			}
+			if !bytes.Equal(chk, orig) {
+				t.Fatalf("original histogram modified:\n%s", cmp.Diff(string(orig), string(chk)))
+			}
+		})
+	}
+}
+
+func TestH1DRebinWithPanics(t *testing.T) {
+	h := NewH1D(10, 0, 10)
+	for _, tc := range []struct {
+		name string
+		fct  func()
+		want error
+	}{
+		{
+			name: "rebin-0",
+			fct:  func() { h.Rebin(0) },
+			want: errInvalidRebin,
+		},
+		{
+			name: "rebin-3",
+			fct:  func() { h.Rebin(3) },
+			want: errInvalidRebin,
+		},
+		{
+			name: "short-edges",
+			fct:  func() { h.RebinEdges([]float64{1}) },
+			want: errShortXAxis,
+		},
+		{
+			name: "not-sorted",
+			fct:  func() { h.RebinEdges([]float64{2, 1}) },
+			want: errNotSortedXAxis,
+		},
+		{
+			name: "dup-edges",
+			fct:  func() { h.RebinEdges([]float64{1, 2, 2}) },
+			want: errDupEdgesXAxis,
+		},
+		{
+			name: "edges-mismatch",
+			fct:  func() { h.RebinEdges([]float64{1, 2.5, 4}) },
+			want: errRebinEdges,
+		},
+		{
+			name: "edges-outside",
+			fct:  func() { h.RebinEdges([]float64{-1, 2}) },
+			want: errRebinEdges,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				err := recover()
+				if err == nil {
+					t.Fatalf("expected a panic")
+				}
+				if got, want := err.(error), tc.want; got != want {
+					t.Fatalf("invalid panic: got=%v, want=%v", got, want)
+				}
+			}()
+			tc.fct()
+		})
+	}
+}
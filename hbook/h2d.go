@@ -39,6 +39,14 @@ func NewH2DFromEdges(xedges []float64, yedges []float64) *H2D {
 	}
 }
 
+// Clone returns a deep copy of this 2-dim histogram.
+func (h *H2D) Clone() *H2D {
+	return &H2D{
+		Binning: h.Binning.clone(),
+		Ann:     h.Ann.clone(),
+	}
+}
+
 // Name returns the name of this histogram, if any
 func (h *H2D) Name() string {
 	v, ok := h.Ann["name"]
@@ -246,6 +254,208 @@ func (h *H2D) Integral() float64 {
 	return h.SumW()
 }
 
+// ProjectionX returns the projection of this histogram onto its X-axis.
+//
+// The under/overflow of the projection hold all the entries below/above
+// the X-axis range, whatever their y value.
+// Entries within the X-axis range but outside of the Y-axis range can not
+// be attributed to a given X-bin: they are only accounted for in the total
+// distribution of the projection.
+func (h *H2D) ProjectionX() *H1D {
+	var (
+		bng = &h.Binning
+		o   = h.projX(func(*Bin2D) bool { return true })
+	)
+	for _, i := range []int{BngNW, BngW, BngSW} {
+		o.Binning.Outflows[0].addScaled(1, 1, bng.Outflows[i-1].X)
+	}
+	for _, i := range []int{BngNE, BngE, BngSE} {
+		o.Binning.Outflows[1].addScaled(1, 1, bng.Outflows[i-1].X)
+	}
+	o.Binning.Dist = bng.Dist.X.clone()
+	return o
+}
+
+// ProjectionY returns the projection of this histogram onto its Y-axis.
+//
+// The under/overflow of the projection hold all the entries below/above
+// the Y-axis range, whatever their x value.
+// Entries within the Y-axis range but outside of the X-axis range can not
+// be attributed to a given Y-bin: they are only accounted for in the total
+// distribution of the projection.
+func (h *H2D) ProjectionY() *H1D {
+	var (
+		bng = &h.Binning
+		o   = h.projY(func(*Bin2D) bool { return true })
+	)
+	for _, i := range []int{BngSW, BngS, BngSE} {
+		o.Binning.Outflows[0].addScaled(1, 1, bng.Outflows[i-1].Y)
+	}
+	for _, i := range []int{BngNW, BngN, BngNE} {
+		o.Binning.Outflows[1].addScaled(1, 1, bng.Outflows[i-1].Y)
+	}
+	o.Binning.Dist = bng.Dist.Y.clone()
+	return o
+}
+
+// SliceX returns the projection onto the X-axis of the bins of this
+// histogram whose lower Y-edge is in [ymin, ymax).
+//
+// Under/overflows of the histogram are not included in the slice:
+// the total distribution of the slice is the sum of its bins.
+func (h *H2D) SliceX(ymin, ymax float64) *H1D {
+	if ymin > ymax {
+		panic("hbook: min > max")
+	}
+	o := h.projX(func(bin *Bin2D) bool {
+		v := bin.YRange.Min
+		return ymin <= v && v < ymax
+	})
+	for _, bin := range o.Binning.Bins {
+		o.Binning.Dist.addScaled(1, 1, bin.Dist)
+	}
+	return o
+}
+
+// SliceY returns the projection onto the Y-axis of the bins of this
+// histogram whose lower X-edge is in [xmin, xmax).
+//
+// Under/overflows of the histogram are not included in the slice:
+// the total distribution of the slice is the sum of its bins.
+func (h *H2D) SliceY(xmin, xmax float64) *H1D {
+	if xmin > xmax {
+		panic("hbook: min > max")
+	}
+	o := h.projY(func(bin *Bin2D) bool {
+		v := bin.XRange.Min
+		return xmin <= v && v < xmax
+	})
+	for _, bin := range o.Binning.Bins {
+		o.Binning.Dist.addScaled(1, 1, bin.Dist)
+	}
+	return o
+}
+
+// projX returns the projection onto the X-axis of the selected bins.
+func (h *H2D) projX(sel func(bin *Bin2D) bool) *H1D {
+	var (
+		bng = &h.Binning
+		o   = &H1D{
+			Binning: newBinning1DFromEdges(h.xedges()),
+			Ann:     h.Ann.clone(),
+		}
+	)
+	for i := range bng.Bins {
+		bin := &bng.Bins[i]
+		if !sel(bin) {
+			continue
+		}
+		ix := i % bng.Nx
+		o.Binning.Bins[ix].Dist.addScaled(1, 1, bin.Dist.X)
+	}
+	return o
+}
+
+// projY returns the projection onto the Y-axis of the selected bins.
+func (h *H2D) projY(sel func(bin *Bin2D) bool) *H1D {
+	var (
+		bng = &h.Binning
+		o   = &H1D{
+			Binning: newBinning1DFromEdges(h.yedges()),
+			Ann:     h.Ann.clone(),
+		}
+	)
+	for i := range bng.Bins {
+		bin := &bng.Bins[i]
+		if !sel(bin) {
+			continue
+		}
+		iy := i / bng.Nx
+		o.Binning.Bins[iy].Dist.addScaled(1, 1, bin.Dist.Y)
+	}
+	return o
+}
+
+// ProfileX returns the profile of the mean y value of this histogram,
+// as a function of x.
+//
+// The under/overflow of the profile hold all the entries below/above
+// the X-axis range, whatever their y value.
+// Entries within the X-axis range but outside of the Y-axis range can not
+// be attributed to a given X-bin: they are only accounted for in the total
+// distribution of the profile.
+func (h *H2D) ProfileX() *P1D {
+	var (
+		bng = &h.Binning
+		o   = &P1D{
+			bng: newBinningP1DFromEdges(h.xedges()),
+			ann: h.Ann.clone(),
+		}
+	)
+	for i, bin := range bng.Bins {
+		ix := i % bng.Nx
+		o.bng.bins[ix].dist.addScaled(1, 1, bin.Dist)
+	}
+	for _, i := range []int{BngNW, BngW, BngSW} {
+		o.bng.outflows[0].addScaled(1, 1, bng.Outflows[i-1])
+	}
+	for _, i := range []int{BngNE, BngE, BngSE} {
+		o.bng.outflows[1].addScaled(1, 1, bng.Outflows[i-1])
+	}
+	o.bng.dist = bng.Dist
+	return o
+}
+
+// ProfileY returns the profile of the mean x value of this histogram,
+// as a function of y.
+//
+// The under/overflow of the profile hold all the entries below/above
+// the Y-axis range, whatever their x value.
+// Entries within the Y-axis range but outside of the X-axis range can not
+// be attributed to a given Y-bin: they are only accounted for in the total
+// distribution of the profile.
+func (h *H2D) ProfileY() *P1D {
+	var (
+		bng = &h.Binning
+		o   = &P1D{
+			bng: newBinningP1DFromEdges(h.yedges()),
+			ann: h.Ann.clone(),
+		}
+	)
+	for i, bin := range bng.Bins {
+		iy := i / bng.Nx
+		o.bng.bins[iy].dist.addScaled(1, 1, bin.Dist.transpose())
+	}
+	for _, i := range []int{BngSW, BngS, BngSE} {
+		o.bng.outflows[0].addScaled(1, 1, bng.Outflows[i-1].transpose())
+	}
+	for _, i := range []int{BngNW, BngN, BngNE} {
+		o.bng.outflows[1].addScaled(1, 1, bng.Outflows[i-1].transpose())
+	}
+	o.bng.dist = bng.Dist.transpose()
+	return o
+}
+
+// xedges returns the edges of the X-axis bins.
+func (h *H2D) xedges() []float64 {
+	bng := &h.Binning
+	edges := make([]float64, 0, bng.Nx+1)
+	for _, bin := range bng.XEdges {
+		edges = append(edges, bin.Range.Min)
+	}
+	return append(edges, bng.XEdges[bng.Nx-1].Range.Max)
+}
+
+// yedges returns the edges of the Y-axis bins.
+func (h *H2D) yedges() []float64 {
+	bng := &h.Binning
+	edges := make([]float64, 0, bng.Ny+1)
+	for _, bin := range bng.YEdges {
+		edges = append(edges, bin.Range.Min)
+	}
+	return append(edges, bng.YEdges[bng.Ny-1].Range.Max)
+}
+
 // GridXYZ returns an anonymous struct value that implements
 // gonum/plot/plotter.GridXYZ and is ready to plot.
 func (h *H2D) GridXYZ() h2dGridXYZ {
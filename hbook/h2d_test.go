@@ -5,6 +5,7 @@
 package hbook
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 	"os"
@@ -487,3 +488,203 @@ func TestH2DFillN(t *testing.T) {
 		h2.FillN(xs, ys, []float64{1})
 	}()
 }
+
+func TestH2DProjections(t *testing.T) {
+	type point struct{ x, y, w float64 }
+	var (
+		xedges = []float64{0, 1, 2, 4, 8}
+		yedges = []float64{-2, -1, 0, 1, 3}
+		pts    []point
+	)
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 5; j++ {
+			pts = append(pts, point{
+				x: float64(i) - 0.5,
+				y: float64(j) - 1.5,
+				w: float64(i%4 + j),
+			})
+		}
+	}
+	for _, tc := range []struct {
+		name  string
+		edges []float64
+		proj  func(h *H2D) *H1D
+		prof  func(h *H2D) *P1D
+	}{
+		{
+			name:  "x",
+			edges: xedges,
+			proj:  (*H2D).ProjectionX,
+			prof:  (*H2D).ProfileX,
+		},
+		{
+			name:  "y",
+			edges: yedges,
+			proj:  (*H2D).ProjectionY,
+			prof:  (*H2D).ProfileY,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				h    = NewH2DFromEdges(xedges, yedges)
+				href = NewH1DFromEdges(tc.edges)
+				pref = &P1D{bng: newBinningP1DFromEdges(tc.edges), ann: make(Annotation)}
+			)
+			h.Ann["name"] = "h2d"
+			href.Ann["name"] = "h2d"
+			pref.ann["name"] = "h2d"
+
+			for _, p := range pts {
+				if tc.name == "y" {
+					// only keep points within the X-range of h.
+					if p.x < 0 || 8 <= p.x {
+						continue
+					}
+					h.Fill(p.x, p.y, p.w)
+					href.Fill(p.y, p.w)
+					pref.Fill(p.y, p.x, p.w)
+					continue
+				}
+				// only keep points within the Y-range of h.
+				if p.y < -2 || 3 <= p.y {
+					continue
+				}
+				h.Fill(p.x, p.y, p.w)
+				href.Fill(p.x, p.w)
+				pref.Fill(p.x, p.y, p.w)
+			}
+
+			cmpYODA(t, tc.proj(h), href)
+			cmpYODA(t, tc.prof(h), pref)
+		})
+	}
+}
+
+func TestH2DProjectionsOutflows(t *testing.T) {
+	h := NewH2D(4, 0, 4, 2, 0, 2)
+	h.Fill(-1, -1, 1) // SW
+	h.Fill(-1, +1, 2) // W
+	h.Fill(-1, +3, 3) // NW
+	h.Fill(+1, +3, 4) // N
+	h.Fill(+5, +3, 5) // NE
+	h.Fill(+5, +1, 6) // E
+	h.Fill(+5, -1, 7) // SE
+	h.Fill(+1, -1, 8) // S
+	h.Fill(+1, +1, 9)
+	h.Fill(+2, +0, 10)
+
+	px := h.ProjectionX()
+	if got, want := px.Binning.Underflow().SumW(), 6.0; got != want {
+		t.Fatalf("invalid x-underflow: got=%v, want=%v", got, want)
+	}
+	if got, want := px.Binning.Overflow().SumW(), 18.0; got != want {
+		t.Fatalf("invalid x-overflow: got=%v, want=%v", got, want)
+	}
+	if got, want := px.SumW(), h.SumW(); got != want {
+		t.Fatalf("invalid x-projection sumw: got=%v, want=%v", got, want)
+	}
+	if got, want := px.Entries(), h.Entries(); got != want {
+		t.Fatalf("invalid x-projection entries: got=%v, want=%v", got, want)
+	}
+	if got, want := px.XMean(), h.XMean(); got != want {
+		t.Fatalf("invalid x-projection mean: got=%v, want=%v", got, want)
+	}
+	if got, want := px.Integral(px.XMin(), px.XMax()), 19.0; got != want {
+		t.Fatalf("invalid x-projection integral: got=%v, want=%v", got, want)
+	}
+
+	py := h.ProjectionY()
+	if got, want := py.Binning.Underflow().SumW(), 16.0; got != want {
+		t.Fatalf("invalid y-underflow: got=%v, want=%v", got, want)
+	}
+	if got, want := py.Binning.Overflow().SumW(), 12.0; got != want {
+		t.Fatalf("invalid y-overflow: got=%v, want=%v", got, want)
+	}
+	if got, want := py.XMean(), h.YMean(); got != want {
+		t.Fatalf("invalid y-projection mean: got=%v, want=%v", got, want)
+	}
+
+	prx := h.ProfileX()
+	if got, want := prx.Binning().outflows[0].SumW(), 6.0; got != want {
+		t.Fatalf("invalid x-profile underflow: got=%v, want=%v", got, want)
+	}
+	if got, want := prx.SumW(), h.SumW(); got != want {
+		t.Fatalf("invalid x-profile sumw: got=%v, want=%v", got, want)
+	}
+
+	pry := h.ProfileY()
+	if got, want := pry.Binning().outflows[1].SumW(), 12.0; got != want {
+		t.Fatalf("invalid y-profile overflow: got=%v, want=%v", got, want)
+	}
+	if got, want := pry.XMean(), h.YMean(); got != want {
+		t.Fatalf("invalid y-profile mean: got=%v, want=%v", got, want)
+	}
+
+	sx := h.SliceX(1, 2)
+	if got, want := sx.SumW(), 9.0; got != want {
+		t.Fatalf("invalid x-slice sumw: got=%v, want=%v", got, want)
+	}
+	if got, want := sx.Binning.Bins[1].SumW(), 9.0; got != want {
+		t.Fatalf("invalid x-slice bin: got=%v, want=%v", got, want)
+	}
+	if got, want := sx.Binning.Underflow().SumW(), 0.0; got != want {
+		t.Fatalf("invalid x-slice underflow: got=%v, want=%v", got, want)
+	}
+
+	sy := h.SliceY(2, 4)
+	if got, want := sy.SumW(), 10.0; got != want {
+		t.Fatalf("invalid y-slice sumw: got=%v, want=%v", got, want)
+	}
+	if got, want := sy.Binning.Bins[0].SumW(), 10.0; got != want {
+		t.Fatalf("invalid y-slice bin: got=%v, want=%v", got, want)
+	}
+}
+
+func TestH2DSlices(t *testing.T) {
+	var (
+		h    = NewH2D(5, 0, 5, 4, 0, 4)
+		sx   = NewH1D(5, 0, 5)
+		sy   = NewH1D(4, 0, 4)
+		ymin = 1.0
+		ymax = 3.0
+		xmin = 2.0
+		xmax = 3.0
+	)
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 4; j++ {
+			var (
+				x = float64(i) + 0.25
+				y = float64(j) + 0.75
+				w = float64(i + 2*j + 1)
+			)
+			h.Fill(x, y, w)
+			if ymin <= y && y < ymax {
+				sx.Fill(x, w)
+			}
+			if xmin <= x && x < xmax {
+				sy.Fill(y, w)
+			}
+		}
+	}
+
+	cmpYODA(t, h.SliceX(ymin, ymax), sx)
+	cmpYODA(t, h.SliceY(xmin, xmax), sy)
+}
+
+func cmpYODA(t *testing.T, got, want interface{ MarshalYODA() ([]byte, error) }) {
+	t.Helper()
+
+	g, err := got.MarshalYODA()
+	if err != nil {
+		t.Fatalf("could not marshal got: %+v", err)
+	}
+
+	w, err := want.MarshalYODA()
+	if err != nil {
+		t.Fatalf("could not marshal want: %+v", err)
+	}
+
+	if !bytes.Equal(g, w) {
+		t.Fatalf("invalid YODA content:\n%s", cmp.Diff(string(w), string(g)))
+	}
+}
@@ -0,0 +1,557 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hbook
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// H3D is a 3-dim histogram with weighted entries.
+type H3D struct {
+	Binning Binning3D
+	Ann     Annotation
+}
+
+// NewH3D creates a new 3-dim histogram.
+func NewH3D(nx int, xlow, xhigh float64, ny int, ylow, yhigh float64, nz int, zlow, zhigh float64) *H3D {
+	return &H3D{
+		Binning: newBinning3D(nx, xlow, xhigh, ny, ylow, yhigh, nz, zlow, zhigh),
+		Ann:     make(Annotation),
+	}
+}
+
+// NewH3DFromEdges creates a new 3-dim histogram from slices
+// of edges in x, y and z.
+// The number of bins in x, y and z is thus len(edges)-1.
+// It panics if the length of edges is <=1 (in any dimension.)
+// It panics if the edges are not sorted (in any dimension.)
+// It panics if there are duplicate edge values (in any dimension.)
+func NewH3DFromEdges(xedges, yedges, zedges []float64) *H3D {
+	return &H3D{
+		Binning: newBinning3DFromEdges(xedges, yedges, zedges),
+		Ann:     make(Annotation),
+	}
+}
+
+// Name returns the name of this histogram, if any
+func (h *H3D) Name() string {
+	v, ok := h.Ann["name"]
+	if !ok {
+		return ""
+	}
+	n, ok := v.(string)
+	if !ok {
+		return ""
+	}
+	return n
+}
+
+// Annotation returns the annotations attached to this histogram
+func (h *H3D) Annotation() Annotation {
+	return h.Ann
+}
+
+// Rank returns the number of dimensions for this histogram
+func (h *H3D) Rank() int {
+	return 3
+}
+
+// Entries returns the number of entries in this histogram
+func (h *H3D) Entries() int64 {
+	return h.Binning.entries()
+}
+
+// EffEntries returns the number of effective entries in this histogram
+func (h *H3D) EffEntries() float64 {
+	return h.Binning.effEntries()
+}
+
+// SumW returns the sum of weights in this histogram.
+// Overflows are included in the computation.
+func (h *H3D) SumW() float64 {
+	return h.Binning.Dist.SumW()
+}
+
+// SumW2 returns the sum of squared weights in this histogram.
+// Overflows are included in the computation.
+func (h *H3D) SumW2() float64 {
+	return h.Binning.Dist.SumW2()
+}
+
+// SumWX returns the 1st order weighted x moment
+// Overflows are included in the computation.
+func (h *H3D) SumWX() float64 {
+	return h.Binning.Dist.SumWX()
+}
+
+// SumWX2 returns the 2nd order weighted x moment
+// Overflows are included in the computation.
+func (h *H3D) SumWX2() float64 {
+	return h.Binning.Dist.SumWX2()
+}
+
+// SumWY returns the 1st order weighted y moment
+// Overflows are included in the computation.
+func (h *H3D) SumWY() float64 {
+	return h.Binning.Dist.SumWY()
+}
+
+// SumWY2 returns the 2nd order weighted y moment
+// Overflows are included in the computation.
+func (h *H3D) SumWY2() float64 {
+	return h.Binning.Dist.SumWY2()
+}
+
+// SumWZ returns the 1st order weighted z moment
+// Overflows are included in the computation.
+func (h *H3D) SumWZ() float64 {
+	return h.Binning.Dist.SumWZ()
+}
+
+// SumWZ2 returns the 2nd order weighted z moment
+// Overflows are included in the computation.
+func (h *H3D) SumWZ2() float64 {
+	return h.Binning.Dist.SumWZ2()
+}
+
+// SumWXY returns the 1st order weighted x*y moment
+// Overflows are included in the computation.
+func (h *H3D) SumWXY() float64 {
+	return h.Binning.Dist.SumWXY()
+}
+
+// SumWXZ returns the 1st order weighted x*z moment
+// Overflows are included in the computation.
+func (h *H3D) SumWXZ() float64 {
+	return h.Binning.Dist.SumWXZ()
+}
+
+// SumWYZ returns the 1st order weighted y*z moment
+// Overflows are included in the computation.
+func (h *H3D) SumWYZ() float64 {
+	return h.Binning.Dist.SumWYZ()
+}
+
+// XMean returns the mean X.
+// Overflows are included in the computation.
+func (h *H3D) XMean() float64 {
+	return h.Binning.Dist.xMean()
+}
+
+// YMean returns the mean Y.
+// Overflows are included in the computation.
+func (h *H3D) YMean() float64 {
+	return h.Binning.Dist.yMean()
+}
+
+// ZMean returns the mean Z.
+// Overflows are included in the computation.
+func (h *H3D) ZMean() float64 {
+	return h.Binning.Dist.zMean()
+}
+
+// XVariance returns the variance in X.
+// Overflows are included in the computation.
+func (h *H3D) XVariance() float64 {
+	return h.Binning.Dist.xVariance()
+}
+
+// YVariance returns the variance in Y.
+// Overflows are included in the computation.
+func (h *H3D) YVariance() float64 {
+	return h.Binning.Dist.yVariance()
+}
+
+// ZVariance returns the variance in Z.
+// Overflows are included in the computation.
+func (h *H3D) ZVariance() float64 {
+	return h.Binning.Dist.zVariance()
+}
+
+// XStdDev returns the standard deviation in X.
+// Overflows are included in the computation.
+func (h *H3D) XStdDev() float64 {
+	return h.Binning.Dist.xStdDev()
+}
+
+// YStdDev returns the standard deviation in Y.
+// Overflows are included in the computation.
+func (h *H3D) YStdDev() float64 {
+	return h.Binning.Dist.yStdDev()
+}
+
+// ZStdDev returns the standard deviation in Z.
+// Overflows are included in the computation.
+func (h *H3D) ZStdDev() float64 {
+	return h.Binning.Dist.zStdDev()
+}
+
+// XStdErr returns the standard error in X.
+// Overflows are included in the computation.
+func (h *H3D) XStdErr() float64 {
+	return h.Binning.Dist.xStdErr()
+}
+
+// YStdErr returns the standard error in Y.
+// Overflows are included in the computation.
+func (h *H3D) YStdErr() float64 {
+	return h.Binning.Dist.yStdErr()
+}
+
+// ZStdErr returns the standard error in Z.
+// Overflows are included in the computation.
+func (h *H3D) ZStdErr() float64 {
+	return h.Binning.Dist.zStdErr()
+}
+
+// XRMS returns the RMS in X.
+// Overflows are included in the computation.
+func (h *H3D) XRMS() float64 {
+	return h.Binning.Dist.xRMS()
+}
+
+// YRMS returns the RMS in Y.
+// Overflows are included in the computation.
+func (h *H3D) YRMS() float64 {
+	return h.Binning.Dist.yRMS()
+}
+
+// ZRMS returns the RMS in Z.
+// Overflows are included in the computation.
+func (h *H3D) ZRMS() float64 {
+	return h.Binning.Dist.zRMS()
+}
+
+// Fill fills this histogram with (x,y,z) and weight w.
+func (h *H3D) Fill(x, y, z, w float64) {
+	h.Binning.fill(x, y, z, w)
+}
+
+// FillN fills this histogram with the provided slices (xs,ys,zs) and weights ws.
+// if ws is nil, the histogram will be filled with entries of weight 1.
+// Otherwise, FillN panics if the slices lengths differ.
+func (h *H3D) FillN(xs, ys, zs, ws []float64) {
+	if len(xs) != len(ys) || len(xs) != len(zs) {
+		panic(fmt.Errorf("hbook: lengths mismatch"))
+	}
+	switch ws {
+	case nil:
+		for i := range xs {
+			h.Binning.fill(xs[i], ys[i], zs[i], 1)
+		}
+	default:
+		if len(xs) != len(ws) {
+			panic(fmt.Errorf("hbook: lengths mismatch"))
+		}
+		for i := range xs {
+			h.Binning.fill(xs[i], ys[i], zs[i], ws[i])
+		}
+	}
+}
+
+// Bin returns the bin at coordinates (x,y,z) for this 3-dim histogram.
+// Bin returns nil for under/over flow bins.
+func (h *H3D) Bin(x, y, z float64) *Bin3D {
+	idx := h.Binning.coordToIndex(x, y, z)
+	if idx < 0 || idx == len(h.Binning.Bins) {
+		return nil
+	}
+	return &h.Binning.Bins[idx]
+}
+
+// XMin returns the low edge of the X-axis of this histogram.
+func (h *H3D) XMin() float64 {
+	return h.Binning.xMin()
+}
+
+// XMax returns the high edge of the X-axis of this histogram.
+func (h *H3D) XMax() float64 {
+	return h.Binning.xMax()
+}
+
+// YMin returns the low edge of the Y-axis of this histogram.
+func (h *H3D) YMin() float64 {
+	return h.Binning.yMin()
+}
+
+// YMax returns the high edge of the Y-axis of this histogram.
+func (h *H3D) YMax() float64 {
+	return h.Binning.yMax()
+}
+
+// ZMin returns the low edge of the Z-axis of this histogram.
+func (h *H3D) ZMin() float64 {
+	return h.Binning.zMin()
+}
+
+// ZMax returns the high edge of the Z-axis of this histogram.
+func (h *H3D) ZMax() float64 {
+	return h.Binning.zMax()
+}
+
+// Integral computes the integral of the histogram.
+//
+// Overflows are included in the computation.
+func (h *H3D) Integral() float64 {
+	return h.SumW()
+}
+
+// check various interfaces
+var _ Object = (*H3D)(nil)
+var _ Histogram = (*H3D)(nil)
+
+// annToYODA creates a new Annotation with fields compatible with YODA
+func (h *H3D) annToYODA() Annotation {
+	ann := make(Annotation, len(h.Ann))
+	ann["Type"] = "Histo3D"
+	ann["Path"] = "/" + h.Name()
+	ann["Title"] = ""
+	for k, v := range h.Ann {
+		if k == "name" {
+			continue
+		}
+		if k == "title" {
+			ann["Title"] = v
+			continue
+		}
+		ann[k] = v
+	}
+	return ann
+}
+
+// annFromYODA creates a new Annotation from YODA compatible fields
+func (h *H3D) annFromYODA(ann Annotation) {
+	if len(h.Ann) == 0 {
+		h.Ann = make(Annotation, len(ann))
+	}
+	for k, v := range ann {
+		switch k {
+		case "Type":
+			// noop
+		case "Path":
+			name := v.(string)
+			name = strings.TrimPrefix(name, "/")
+			h.Ann["name"] = name
+		case "Title":
+			h.Ann["title"] = v
+		default:
+			h.Ann[k] = v
+		}
+	}
+}
+
+// MarshalYODA implements the YODAMarshaler interface.
+func (h *H3D) MarshalYODA() ([]byte, error) {
+	return h.marshalYODAv2()
+}
+
+func (h *H3D) marshalYODAv1() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	ann := h.annToYODA()
+	fmt.Fprintf(buf, "BEGIN YODA_HISTO3D %s\n", ann["Path"])
+	data, err := ann.marshalYODAv1()
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(data)
+	h.writeYODA(buf, func(n int64) string { return fmt.Sprintf("%d", n) })
+	fmt.Fprintf(buf, "END YODA_HISTO3D\n\n")
+	return buf.Bytes(), err
+}
+
+func (h *H3D) marshalYODAv2() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	ann := h.annToYODA()
+	fmt.Fprintf(buf, "BEGIN YODA_HISTO3D_V2 %s\n", ann["Path"])
+	data, err := ann.marshalYODAv2()
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(data)
+	buf.Write([]byte("---\n"))
+	h.writeYODA(buf, func(n int64) string { return fmt.Sprintf("%e", float64(n)) })
+	fmt.Fprintf(buf, "END YODA_HISTO3D_V2\n\n")
+	return buf.Bytes(), err
+}
+
+// writeYODA writes the distributions of the histogram in the YODA format.
+// entries formats the number of entries of a distribution.
+func (h *H3D) writeYODA(buf *bytes.Buffer, entries func(n int64) string) {
+	fmt.Fprintf(buf, "# Mean: (%e, %e, %e)\n", h.XMean(), h.YMean(), h.ZMean())
+	fmt.Fprintf(buf, "# Volume: %e\n", h.Integral())
+
+	fmt.Fprintf(buf, "# ID\t ID\t sumw\t sumw2\t sumwx\t sumwx2\t sumwy\t sumwy2\t sumwz\t sumwz2\t sumwxy\t sumwxz\t sumwyz\t numEntries\n")
+	d := h.Binning.Dist
+	fmt.Fprintf(
+		buf,
+		"Total   \tTotal   \t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%s\n",
+		d.SumW(), d.SumW2(), d.SumWX(), d.SumWX2(), d.SumWY(), d.SumWY2(), d.SumWZ(), d.SumWZ2(),
+		d.SumWXY(), d.SumWXZ(), d.SumWYZ(), entries(d.Entries()),
+	)
+
+	// outflows
+	fmt.Fprintf(buf, "# 3D outflow persistency not currently supported until API is stable\n")
+
+	// bins
+	fmt.Fprintf(buf, "# xlow\t xhigh\t ylow\t yhigh\t zlow\t zhigh\t sumw\t sumw2\t sumwx\t sumwx2\t sumwy\t sumwy2\t sumwz\t sumwz2\t sumwxy\t sumwxz\t sumwyz\t numEntries\n")
+	for ix := 0; ix < h.Binning.Nx; ix++ {
+		for iy := 0; iy < h.Binning.Ny; iy++ {
+			for iz := 0; iz < h.Binning.Nz; iz++ {
+				bin := h.Binning.Bins[h.Binning.index(ix, iy, iz)]
+				d := bin.Dist
+				fmt.Fprintf(
+					buf,
+					"%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%s\n",
+					bin.XRange.Min, bin.XRange.Max, bin.YRange.Min, bin.YRange.Max, bin.ZRange.Min, bin.ZRange.Max,
+					d.SumW(), d.SumW2(), d.SumWX(), d.SumWX2(), d.SumWY(), d.SumWY2(), d.SumWZ(), d.SumWZ2(),
+					d.SumWXY(), d.SumWXZ(), d.SumWYZ(), entries(d.Entries()),
+				)
+			}
+		}
+	}
+}
+
+// UnmarshalYODA implements the YODAUnmarshaler interface.
+func (h *H3D) UnmarshalYODA(data []byte) error {
+	r := newRBuffer(data)
+	_, vers, err := readYODAHeader(r, "BEGIN YODA_HISTO3D")
+	if err != nil {
+		return err
+	}
+	ann := make(Annotation)
+
+	// pos of end of annotations
+	pos := bytes.Index(r.Bytes(), []byte("\n# Mean:"))
+	if pos < 0 {
+		return fmt.Errorf("hbook: invalid H3D-YODA data")
+	}
+	switch vers {
+	case 1:
+		err = ann.unmarshalYODAv1(r.Bytes()[:pos+1])
+	case 2:
+		err = ann.unmarshalYODAv2(r.Bytes()[:pos+1])
+	default:
+		return fmt.Errorf("hbook: invalid YODA version %v", vers)
+	}
+	if err != nil {
+		return fmt.Errorf("hbook: %q\nhbook: %w", string(r.Bytes()[:pos+1]), err)
+	}
+	h.annFromYODA(ann)
+	r.next(pos)
+
+	var ctx struct {
+		dist bool
+		bins bool
+	}
+
+	// sets of xlow, ylow and zlow values, to infer number of bins in X, Y and Z.
+	xset := make(map[float64]int)
+	yset := make(map[float64]int)
+	zset := make(map[float64]int)
+
+	var (
+		dist Dist3D
+		bins []Bin3D
+		xmin = math.Inf(+1)
+		xmax = math.Inf(-1)
+		ymin = math.Inf(+1)
+		ymax = math.Inf(-1)
+		zmin = math.Inf(+1)
+		zmax = math.Inf(-1)
+	)
+	s := bufio.NewScanner(r)
+scanLoop:
+	for s.Scan() {
+		buf := s.Bytes()
+		if len(buf) == 0 || buf[0] == '#' {
+			continue
+		}
+		rbuf := bytes.NewReader(buf)
+		switch {
+		case bytes.HasPrefix(buf, []byte("END YODA_HISTO3D")):
+			break scanLoop
+		case !ctx.dist && bytes.HasPrefix(buf, []byte("Total   \t")):
+			ctx.dist = true
+			var n float64
+			d := &dist
+			_, err = fmt.Fscanf(
+				rbuf,
+				"Total   \tTotal   \t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\n",
+				&d.X.Dist.SumW, &d.X.Dist.SumW2,
+				&d.X.Stats.SumWX, &d.X.Stats.SumWX2,
+				&d.Y.Stats.SumWX, &d.Y.Stats.SumWX2,
+				&d.Z.Stats.SumWX, &d.Z.Stats.SumWX2,
+				&d.Stats.SumWXY, &d.Stats.SumWXZ, &d.Stats.SumWYZ,
+				&n,
+			)
+			if err != nil {
+				return fmt.Errorf("hbook: %q\nhbook: %w", string(buf), err)
+			}
+			d.X.Dist.N = int64(n)
+			d.Y.Dist = d.X.Dist
+			d.Z.Dist = d.X.Dist
+			ctx.bins = true
+		case ctx.bins:
+			var (
+				bin Bin3D
+				n   float64
+			)
+			d := &bin.Dist
+			_, err = fmt.Fscanf(
+				rbuf,
+				"%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\n",
+				&bin.XRange.Min, &bin.XRange.Max, &bin.YRange.Min, &bin.YRange.Max, &bin.ZRange.Min, &bin.ZRange.Max,
+				&d.X.Dist.SumW, &d.X.Dist.SumW2,
+				&d.X.Stats.SumWX, &d.X.Stats.SumWX2,
+				&d.Y.Stats.SumWX, &d.Y.Stats.SumWX2,
+				&d.Z.Stats.SumWX, &d.Z.Stats.SumWX2,
+				&d.Stats.SumWXY, &d.Stats.SumWXZ, &d.Stats.SumWYZ,
+				&n,
+			)
+			if err != nil {
+				return fmt.Errorf("hbook: %q\nhbook: %w", string(buf), err)
+			}
+			d.X.Dist.N = int64(n)
+			d.Y.Dist = d.X.Dist
+			d.Z.Dist = d.X.Dist
+			xset[bin.XRange.Min] = 1
+			yset[bin.YRange.Min] = 1
+			zset[bin.ZRange.Min] = 1
+			xmin = math.Min(xmin, bin.XRange.Min)
+			xmax = math.Max(xmax, bin.XRange.Max)
+			ymin = math.Min(ymin, bin.YRange.Min)
+			ymax = math.Max(ymax, bin.YRange.Max)
+			zmin = math.Min(zmin, bin.ZRange.Min)
+			zmax = math.Max(zmax, bin.ZRange.Max)
+			bins = append(bins, bin)
+
+		default:
+			return fmt.Errorf("hbook: invalid H3D-YODA data: %q", string(buf))
+		}
+	}
+	h.Binning = newBinning3D(len(xset), xmin, xmax, len(yset), ymin, ymax, len(zset), zmin, zmax)
+	h.Binning.Dist = dist
+	// YODA bins are ordered along Z, then Y, then X.
+	var (
+		nx = h.Binning.Nx
+		ny = h.Binning.Ny
+		nz = h.Binning.Nz
+	)
+	if len(bins) != nx*ny*nz {
+		return fmt.Errorf("hbook: invalid H3D-YODA data: got %d bins, want %d", len(bins), nx*ny*nz)
+	}
+	for ix := 0; ix < nx; ix++ {
+		for iy := 0; iy < ny; iy++ {
+			for iz := 0; iz < nz; iz++ {
+				h.Binning.Bins[h.Binning.index(ix, iy, iz)] = bins[(ix*ny+iy)*nz+iz]
+			}
+		}
+	}
+	return err
+}
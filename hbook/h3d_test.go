@@ -0,0 +1,284 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hbook
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestH3D(t *testing.T) {
+	h := NewH3D(2, 0, 2, 3, 0, 3, 4, 0, 4)
+	if got, want := len(h.Binning.Bins), 2*3*4; got != want {
+		t.Fatalf("invalid number of bins: got=%d, want=%d", got, want)
+	}
+	if got, want := h.Rank(), 3; got != want {
+		t.Fatalf("invalid rank: got=%d, want=%d", got, want)
+	}
+
+	h.Fill(0.5, 0.5, 0.5, 1)
+	h.Fill(1.5, 2.5, 3.5, 2)
+	h.Fill(1.5, 2.5, 3.5, 1)
+	h.Fill(1.5, 1.5, 10, 1) // z-overflow
+	h.Fill(-1, -1, -1, 1)   // underflow
+
+	for _, tc := range []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"entries", float64(h.Entries()), 5},
+		{"sumw", h.SumW(), 6},
+		{"sumw2", h.SumW2(), 8},
+		{"sumwx", h.SumWX(), 0.5 + 1.5*3 + 1.5 - 1},
+		{"sumwy", h.SumWY(), 0.5 + 2.5*3 + 1.5 - 1},
+		{"sumwz", h.SumWZ(), 0.5 + 3.5*3 + 10 - 1},
+		{"sumwxy", h.SumWXY(), 0.25 + 1.5*2.5*3 + 1.5*1.5 + 1},
+		{"sumwxz", h.SumWXZ(), 0.25 + 1.5*3.5*3 + 1.5*10 + 1},
+		{"sumwyz", h.SumWYZ(), 0.25 + 2.5*3.5*3 + 1.5*10 + 1},
+		{"xmean", h.XMean(), (0.5 + 1.5*3 + 1.5 - 1) / 6},
+		{"zmean", h.ZMean(), (0.5 + 3.5*3 + 10 - 1) / 6},
+		{"integral", h.Integral(), 6},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if math.Abs(tc.got-tc.want) > 1e-12 {
+				t.Fatalf("got=%v, want=%v", tc.got, tc.want)
+			}
+		})
+	}
+
+	bin := h.Bin(1.5, 2.5, 3.5)
+	if bin == nil {
+		t.Fatalf("unexpected nil bin")
+	}
+	if got, want := bin.SumW(), 3.0; got != want {
+		t.Fatalf("invalid bin sumw: got=%v, want=%v", got, want)
+	}
+	if got, want := bin.Entries(), int64(2); got != want {
+		t.Fatalf("invalid bin entries: got=%v, want=%v", got, want)
+	}
+	if got, want := bin.Volume(), 1.0; got != want {
+		t.Fatalf("invalid bin volume: got=%v, want=%v", got, want)
+	}
+	if bin := h.Bin(1.5, 1.5, 10); bin != nil {
+		t.Fatalf("expected nil bin for overflow")
+	}
+
+	var sumw float64
+	for _, d := range h.Binning.Outflows {
+		sumw += d.SumW()
+	}
+	if got, want := sumw, 2.0; got != want {
+		t.Fatalf("invalid outflows sumw: got=%v, want=%v", got, want)
+	}
+}
+
+func TestH3DFromEdges(t *testing.T) {
+	h := NewH3DFromEdges(
+		[]float64{0, 1, 3},
+		[]float64{-2, 0, 2},
+		[]float64{0, 10, 20, 50},
+	)
+	for _, tc := range []struct {
+		got, want float64
+	}{
+		{h.XMin(), 0},
+		{h.XMax(), 3},
+		{h.YMin(), -2},
+		{h.YMax(), 2},
+		{h.ZMin(), 0},
+		{h.ZMax(), 50},
+	} {
+		if tc.got != tc.want {
+			t.Fatalf("invalid limits: got=%v, want=%v", tc.got, tc.want)
+		}
+	}
+
+	h.Fill(2, 1, 30, 1)
+	h.Fill(0, -2, 0, 1)
+	h.Fill(0, -2, 0, 1)
+
+	for _, tc := range []struct {
+		x, y, z float64
+		n       int64
+	}{
+		{2.5, 1.5, 49, 1},
+		{0.5, -1, 5, 2},
+		{0.5, 1, 5, 0},
+	} {
+		t.Run(fmt.Sprintf("x,y,z=(%v,%v,%v)", tc.x, tc.y, tc.z), func(t *testing.T) {
+			bin := h.Bin(tc.x, tc.y, tc.z)
+			if bin == nil {
+				t.Fatalf("unexpected nil bin")
+			}
+			if got := bin.Entries(); got != tc.n {
+				t.Fatalf("got=%d, want=%d", got, tc.n)
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		name  string
+		edges [3][]float64
+		err   error
+	}{
+		{"short-z", [3][]float64{{0, 1}, {0, 1}, {0}}, errShortZAxis},
+		{"sorted-z", [3][]float64{{0, 1}, {0, 1}, {1, 0}}, errNotSortedZAxis},
+		{"dup-z", [3][]float64{{0, 1}, {0, 1}, {0, 1, 1, 2}}, errDupEdgesZAxis},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				e := recover()
+				if e == nil {
+					t.Fatalf("expected a panic")
+				}
+				if e != tc.err {
+					t.Fatalf("invalid panic: got=%v, want=%v", e, tc.err)
+				}
+			}()
+			_ = NewH3DFromEdges(tc.edges[0], tc.edges[1], tc.edges[2])
+		})
+	}
+}
+
+func TestH3DFillN(t *testing.T) {
+	h1 := NewH3D(2, 0, 2, 2, 0, 2, 2, 0, 2)
+	h2 := NewH3D(2, 0, 2, 2, 0, 2, 2, 0, 2)
+
+	xs := []float64{0.5, 1.5, 1.5, 3}
+	ys := []float64{0.5, 0.5, 1.5, 1}
+	zs := []float64{1.5, 0.5, 1.5, 1}
+	ws := []float64{1, 2, 3, 4}
+
+	h1.FillN(xs, ys, zs, ws)
+	for i := range xs {
+		h2.Fill(xs[i], ys[i], zs[i], ws[i])
+	}
+	if !reflect.DeepEqual(h1, h2) {
+		t.Fatalf("FillN and Fill differ")
+	}
+
+	h1.FillN(xs, ys, zs, nil)
+	if got, want := h1.Entries(), int64(8); got != want {
+		t.Fatalf("invalid entries: got=%d, want=%d", got, want)
+	}
+}
+
+func newTestH3D() *H3D {
+	h := NewH3D(2, -1, 1, 3, -2, +2, 2, 0, 10)
+	h.Fill(+0.5, +1, 2, 1)
+	h.Fill(-0.5, +1, 8, 2)
+	h.Fill(+0.0, -1, 5, 1)
+	h.Fill(+0.0, -1, 50, 1)
+	return h
+}
+
+func TestH3DWriteYODA(t *testing.T) {
+	h := newTestH3D()
+
+	chk, err := h.MarshalYODA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref, err := os.ReadFile("testdata/h3d_v2_golden.yoda")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(chk, ref) {
+		t.Fatalf("h3d file differ:\n%s\n",
+			cmp.Diff(
+				string(ref),
+				string(chk),
+			),
+		)
+	}
+}
+
+func TestH3DReadYODAv1(t *testing.T) {
+	ref, err := os.ReadFile("testdata/h3d_v1_golden.yoda")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var h H3D
+	err = h.UnmarshalYODA(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chk, err := h.marshalYODAv1()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(chk, ref) {
+		t.Fatalf("h3d file differ:\n%s\n",
+			cmp.Diff(
+				string(ref),
+				string(chk),
+			),
+		)
+	}
+}
+
+func TestH3DReadYODAv2(t *testing.T) {
+	ref, err := os.ReadFile("testdata/h3d_v2_golden.yoda")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var h H3D
+	err = h.UnmarshalYODA(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chk, err := h.marshalYODAv2()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(chk, ref) {
+		t.Fatalf("h3d file differ:\n%s\n",
+			cmp.Diff(
+				string(ref),
+				string(chk),
+			),
+		)
+	}
+}
+
+func TestH3DSerialization(t *testing.T) {
+	href := newTestH3D()
+	href.Annotation()["title"] = "h3d title"
+	href.Annotation()["name"] = "h3d-name"
+
+	buf := new(bytes.Buffer)
+	enc := gob.NewEncoder(buf)
+	err := enc.Encode(href)
+	if err != nil {
+		t.Fatalf("could not serialize h3d: %v\n", err)
+	}
+
+	var hnew H3D
+	dec := gob.NewDecoder(buf)
+	err = dec.Decode(&hnew)
+	if err != nil {
+		t.Fatalf("could not deserialize h3d: %v\n", err)
+	}
+
+	if !reflect.DeepEqual(href, &hnew) {
+		t.Fatalf("ref=%v\nnew=%v\n", href, &hnew)
+	}
+}
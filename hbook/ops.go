@@ -157,3 +157,307 @@ func AddH1D(h1, h2 *H1D) *H1D {
 func SubH1D(h1, h2 *H1D) *H1D {
 	return AddScaledH1D(h1, -1, h2)
 }
+
+// AddScaledH2D returns the histogram with the bin-by-bin h1+alpha*h2
+// operation, assuming statistical uncertainties are uncorrelated.
+func AddScaledH2D(h1 *H2D, alpha float64, h2 *H2D) *H2D {
+	if h1.Binning.Nx != h2.Binning.Nx || h1.Binning.Ny != h2.Binning.Ny {
+		panic(fmt.Errorf("hbook: h1 and h2 have different number of bins"))
+	}
+
+	if h1.XMin() != h2.XMin() || h1.XMax() != h2.XMax() ||
+		h1.YMin() != h2.YMin() || h1.YMax() != h2.YMax() {
+		panic(fmt.Errorf("hbook: h1 and h2 have different range"))
+	}
+
+	var (
+		o  = h1.Clone()
+		a2 = alpha * alpha
+	)
+
+	for i := range o.Binning.Bins {
+		o := &o.Binning.Bins[i]
+		o.addScaled(alpha, a2, h2.Binning.Bins[i])
+	}
+
+	o.Binning.Dist.addScaled(alpha, a2, h2.Binning.Dist)
+	for i := range o.Binning.Outflows {
+		o.Binning.Outflows[i].addScaled(alpha, a2, h2.Binning.Outflows[i])
+	}
+	return o
+}
+
+// AddH2D returns the bin-by-bin summed histogram of h1 and h2
+// assuming their statistical uncertainties are uncorrelated.
+func AddH2D(h1, h2 *H2D) *H2D {
+	return AddScaledH2D(h1, 1, h2)
+}
+
+// SubH2D returns the bin-by-bin subtracted histogram of h1 and h2
+// assuming their statistical uncertainties are uncorrelated.
+func SubH2D(h1, h2 *H2D) *H2D {
+	return AddScaledH2D(h1, -1, h2)
+}
+
+// AddScaledP1D returns the profile histogram with the bin-by-bin p1+alpha*p2
+// operation, assuming statistical uncertainties are uncorrelated.
+func AddScaledP1D(p1 *P1D, alpha float64, p2 *P1D) *P1D {
+	if len(p1.bng.bins) != len(p2.bng.bins) {
+		panic(fmt.Errorf("hbook: p1 and p2 have different number of bins"))
+	}
+
+	if p1.XMin() != p2.XMin() || p1.XMax() != p2.XMax() {
+		panic(fmt.Errorf("hbook: p1 and p2 have different range"))
+	}
+
+	var (
+		o  = p1.Clone()
+		a2 = alpha * alpha
+	)
+
+	for i := range o.bng.bins {
+		o := &o.bng.bins[i]
+		o.addScaled(alpha, a2, p2.bng.bins[i])
+	}
+
+	o.bng.dist.addScaled(alpha, a2, p2.bng.dist)
+	o.bng.outflows[0].addScaled(alpha, a2, p2.bng.outflows[0])
+	o.bng.outflows[1].addScaled(alpha, a2, p2.bng.outflows[1])
+	return o
+}
+
+// AddP1D returns the bin-by-bin summed profile histogram of p1 and p2
+// assuming their statistical uncertainties are uncorrelated.
+func AddP1D(p1, p2 *P1D) *P1D {
+	return AddScaledP1D(p1, 1, p2)
+}
+
+// SubP1D returns the bin-by-bin subtracted profile histogram of p1 and p2
+// assuming their statistical uncertainties are uncorrelated.
+func SubP1D(p1, p2 *P1D) *P1D {
+	return AddScaledP1D(p1, -1, p2)
+}
+
+// MultiplyH1D multiplies 2 1D-histograms and returns a 2D scatter.
+// The Y value of each point is the product of the heights of the bins.
+// MultiplyH1D returns an error if the binning of the 1D histograms are not compatible.
+func MultiplyH1D(h1, h2 *H1D) (*S2D, error) {
+	s2d := NewS2D()
+
+	bins1 := h1.Binning.Bins
+	bins2 := h2.Binning.Bins
+	if len(bins1) != len(bins2) {
+		return nil, fmt.Errorf("hbook: x binnings are not equivalent in %v * %v", h1.Name(), h2.Name())
+	}
+
+	for i := range bins1 {
+		b1 := bins1[i]
+		b2 := bins2[i]
+
+		if !fuzzyEq(b1.XMin(), b2.XMin()) || !fuzzyEq(b1.XMax(), b2.XMax()) {
+			return nil, fmt.Errorf("hbook: x binnings are not equivalent in %v * %v", h1.Name(), h2.Name())
+		}
+
+		x := b1.XMid()
+		y, ey := multiply(
+			b1.SumW()/b1.XWidth(), math.Sqrt(b1.SumW2())/b1.XWidth(),
+			b2.SumW()/b2.XWidth(), math.Sqrt(b2.SumW2())/b2.XWidth(),
+		)
+
+		s2d.Fill(Point2D{X: x, Y: y, ErrX: Range{Min: x - b1.XMin(), Max: b1.XMax() - x}, ErrY: Range{Min: ey, Max: ey}})
+	}
+	return s2d, nil
+}
+
+// DivideH2D divides 2 2D-histograms and returns a 3D scatter.
+// DivideH2D returns an error if the binning of the 2D histograms are not compatible.
+// If no DivOptions is passed, NaN raised during division are kept.
+func DivideH2D(num, den *H2D, opts ...DivOptions) (*S3D, error) {
+	cfg := newDivConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	s3d := NewS3D()
+
+	bins1 := num.Binning.Bins
+	bins2 := den.Binning.Bins
+	if len(bins1) != len(bins2) {
+		return nil, fmt.Errorf("hbook: binnings are not equivalent in %v / %v", num.Name(), den.Name())
+	}
+
+	for i := range bins1 {
+		b1 := &bins1[i]
+		b2 := &bins2[i]
+
+		if !equivBins2D(b1, b2) {
+			return nil, fmt.Errorf("hbook: binnings are not equivalent in %v / %v", num.Name(), den.Name())
+		}
+
+		var (
+			a1 = b1.XWidth() * b1.YWidth()
+			a2 = b2.XWidth() * b2.YWidth()
+		)
+		z, ez, ok := divide(
+			b1.SumW()/a1, math.Sqrt(b1.SumW2())/a1,
+			b2.SumW()/a2, math.Sqrt(b2.SumW2())/a2,
+		)
+		if !ok {
+			if cfg.ignoreNaN {
+				continue
+			}
+			z = cfg.replaceNaN
+			ez = 0
+		}
+
+		s3d.Fill(newPoint3DFromBin2D(b1, z, ez))
+	}
+	return s3d, nil
+}
+
+// MultiplyH2D multiplies 2 2D-histograms and returns a 3D scatter.
+// The Z value of each point is the product of the densities of the bins.
+// MultiplyH2D returns an error if the binning of the 2D histograms are not compatible.
+func MultiplyH2D(h1, h2 *H2D) (*S3D, error) {
+	s3d := NewS3D()
+
+	bins1 := h1.Binning.Bins
+	bins2 := h2.Binning.Bins
+	if len(bins1) != len(bins2) {
+		return nil, fmt.Errorf("hbook: binnings are not equivalent in %v * %v", h1.Name(), h2.Name())
+	}
+
+	for i := range bins1 {
+		b1 := &bins1[i]
+		b2 := &bins2[i]
+
+		if !equivBins2D(b1, b2) {
+			return nil, fmt.Errorf("hbook: binnings are not equivalent in %v * %v", h1.Name(), h2.Name())
+		}
+
+		var (
+			a1 = b1.XWidth() * b1.YWidth()
+			a2 = b2.XWidth() * b2.YWidth()
+		)
+		z, ez := multiply(
+			b1.SumW()/a1, math.Sqrt(b1.SumW2())/a1,
+			b2.SumW()/a2, math.Sqrt(b2.SumW2())/a2,
+		)
+
+		s3d.Fill(newPoint3DFromBin2D(b1, z, ez))
+	}
+	return s3d, nil
+}
+
+// DivideP1D divides the mean values of 2 1D-profiles and returns a 2D scatter.
+// The error on the mean value of each bin is its standard error.
+// DivideP1D returns an error if the binning of the 1D profiles are not compatible.
+// If no DivOptions is passed, NaN raised during division (including
+// from empty bins) are kept.
+func DivideP1D(num, den *P1D, opts ...DivOptions) (*S2D, error) {
+	cfg := newDivConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	s2d := NewS2D()
+
+	bins1 := num.bng.bins
+	bins2 := den.bng.bins
+	if len(bins1) != len(bins2) {
+		return nil, fmt.Errorf("hbook: x binnings are not equivalent in %v / %v", num.Name(), den.Name())
+	}
+
+	for i := range bins1 {
+		b1 := &bins1[i]
+		b2 := &bins2[i]
+
+		if !fuzzyEq(b1.XMin(), b2.XMin()) || !fuzzyEq(b1.XMax(), b2.XMax()) {
+			return nil, fmt.Errorf("hbook: x binnings are not equivalent in %v / %v", num.Name(), den.Name())
+		}
+
+		x := b1.XMid()
+		y, ey, ok := divide(b1.YMean(), b1.YStdErr(), b2.YMean(), b2.YStdErr())
+		if !ok || b1.SumW() == 0 {
+			if cfg.ignoreNaN {
+				continue
+			}
+			y = cfg.replaceNaN
+			ey = 0
+		}
+
+		s2d.Fill(Point2D{X: x, Y: y, ErrX: Range{Min: x - b1.XMin(), Max: b1.XMax() - x}, ErrY: Range{Min: ey, Max: ey}})
+	}
+	return s2d, nil
+}
+
+// MultiplyP1D multiplies the mean values of 2 1D-profiles and returns a 2D scatter.
+// The error on the mean value of each bin is its standard error.
+// Points for bins that are empty in any of the profiles are NaN.
+// MultiplyP1D returns an error if the binning of the 1D profiles are not compatible.
+func MultiplyP1D(p1, p2 *P1D) (*S2D, error) {
+	s2d := NewS2D()
+
+	bins1 := p1.bng.bins
+	bins2 := p2.bng.bins
+	if len(bins1) != len(bins2) {
+		return nil, fmt.Errorf("hbook: x binnings are not equivalent in %v * %v", p1.Name(), p2.Name())
+	}
+
+	for i := range bins1 {
+		b1 := &bins1[i]
+		b2 := &bins2[i]
+
+		if !fuzzyEq(b1.XMin(), b2.XMin()) || !fuzzyEq(b1.XMax(), b2.XMax()) {
+			return nil, fmt.Errorf("hbook: x binnings are not equivalent in %v * %v", p1.Name(), p2.Name())
+		}
+
+		x := b1.XMid()
+		y, ey := math.NaN(), math.NaN()
+		if b1.SumW() != 0 && b2.SumW() != 0 {
+			y, ey = multiply(b1.YMean(), b1.YStdErr(), b2.YMean(), b2.YStdErr())
+		}
+
+		s2d.Fill(Point2D{X: x, Y: y, ErrX: Range{Min: x - b1.XMin(), Max: b1.XMax() - x}, ErrY: Range{Min: ey, Max: ey}})
+	}
+	return s2d, nil
+}
+
+// divide returns v1/v2 and its error, assuming the uncertainties e1 and e2
+// on v1 and v2 are uncorrelated.
+// divide returns false if the ratio is not defined.
+func divide(v1, e1, v2, e2 float64) (v, e float64, ok bool) {
+	if v2 == 0 || math.IsNaN(v1) || math.IsNaN(v2) {
+		return 0, 0, false
+	}
+	v = v1 / v2
+	e = math.Hypot(e1/v2, v*e2/v2)
+	return v, e, true
+}
+
+// multiply returns v1*v2 and its error, assuming the uncertainties e1 and e2
+// on v1 and v2 are uncorrelated.
+func multiply(v1, e1, v2, e2 float64) (v, e float64) {
+	v = v1 * v2
+	e = math.Hypot(e1*v2, v1*e2)
+	return v, e
+}
+
+// equivBins2D returns whether the 2 bins have equivalent edges.
+func equivBins2D(b1, b2 *Bin2D) bool {
+	return fuzzyEq(b1.XMin(), b2.XMin()) && fuzzyEq(b1.XMax(), b2.XMax()) &&
+		fuzzyEq(b1.YMin(), b2.YMin()) && fuzzyEq(b1.YMax(), b2.YMax())
+}
+
+// newPoint3DFromBin2D returns a point located at the middle of the
+// provided bin, with the given z value and symmetric error.
+func newPoint3DFromBin2D(bin *Bin2D, z, ez float64) Point3D {
+	x := bin.XMid()
+	y := bin.YMid()
+	return Point3D{
+		X: x, Y: y, Z: z,
+		ErrX: Range{Min: x - bin.XMin(), Max: bin.XMax() - x},
+		ErrY: Range{Min: y - bin.YMin(), Max: bin.YMax() - y},
+		ErrZ: Range{Min: ez, Max: ez},
+	}
+}
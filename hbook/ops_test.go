@@ -7,6 +7,7 @@ package hbook
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"reflect"
 	"testing"
 
@@ -327,3 +328,317 @@ END YODA_HISTO1D_V2
 		)
 	}
 }
+
+func TestAddH2D(t *testing.T) {
+	var (
+		h1  = NewH2D(3, 0, 3, 2, 0, 2)
+		h2  = NewH2D(3, 0, 3, 2, 0, 2)
+		ref = NewH2D(3, 0, 3, 2, 0, 2)
+	)
+	for i, v := range [][3]float64{
+		{-1, 0.5, 1}, {0.5, 0.5, 2}, {1.5, 1.5, 1}, {2.5, 0.2, 3},
+		{2.5, 2.5, 1}, {1.2, -1, 2}, {0.1, 1.1, 1}, {4, 4, 0.5},
+	} {
+		h := h1
+		if i%2 == 1 {
+			h = h2
+		}
+		h.Fill(v[0], v[1], v[2])
+		ref.Fill(v[0], v[1], v[2])
+	}
+
+	cmpYODA(t, AddH2D(h1, h2), ref)
+
+	sub := SubH2D(ref, h2)
+	for i := range sub.Binning.Bins {
+		var (
+			got   = &sub.Binning.Bins[i]
+			want  = &h1.Binning.Bins[i]
+			sumw2 = ref.Binning.Bins[i].SumW2() + h2.Binning.Bins[i].SumW2()
+		)
+		if math.Abs(got.SumW()-want.SumW()) > 1e-12 {
+			t.Fatalf("invalid sumw for bin %d: got=%v, want=%v", i, got.SumW(), want.SumW())
+		}
+		if got.SumW2() != sumw2 {
+			t.Fatalf("invalid sumw2 for bin %d: got=%v, want=%v", i, got.SumW2(), sumw2)
+		}
+	}
+	if got, want := sub.SumW(), h1.SumW(); math.Abs(got-want) > 1e-12 {
+		t.Fatalf("invalid sumw: got=%v, want=%v", got, want)
+	}
+	if got, want := sub.Binning.Outflows[BngNE-1].SumW(), 0.0; got != want {
+		t.Fatalf("invalid NE outflow sumw: got=%v, want=%v", got, want)
+	}
+	if got, want := sub.Binning.Outflows[BngNE-1].SumW2(), 0.5; got != want {
+		t.Fatalf("invalid NE outflow sumw2: got=%v, want=%v", got, want)
+	}
+
+	for _, tc := range []struct {
+		h1, h2 *H2D
+		panics string
+	}{
+		{
+			h1:     NewH2D(3, 0, 3, 2, 0, 2),
+			h2:     NewH2D(3, 0, 3, 3, 0, 2),
+			panics: "hbook: h1 and h2 have different number of bins",
+		},
+		{
+			h1:     NewH2D(3, 0, 3, 2, 0, 2),
+			h2:     NewH2D(3, 0, 3, 2, 0, 3),
+			panics: "hbook: h1 and h2 have different range",
+		},
+	} {
+		func() {
+			defer func() {
+				err := recover()
+				if err == nil {
+					t.Fatalf("expected a panic")
+				}
+				if got, want := err.(error).Error(), tc.panics; got != want {
+					t.Fatalf("invalid panic message.\ngot= %v\nwant=%v", got, want)
+				}
+			}()
+			_ = AddH2D(tc.h1, tc.h2)
+		}()
+	}
+}
+
+func TestAddP1D(t *testing.T) {
+	var (
+		p1  = NewP1D(4, 0, 4)
+		p2  = NewP1D(4, 0, 4)
+		ref = NewP1D(4, 0, 4)
+	)
+	for i := 0; i < 20; i++ {
+		var (
+			x = float64(i)*0.25 - 0.5
+			y = 2*x + float64(i%3)
+			w = float64(i%4 + 1)
+		)
+		p := p1
+		if i%3 == 0 {
+			p = p2
+		}
+		p.Fill(x, y, w)
+		ref.Fill(x, y, w)
+	}
+
+	cmpYODA(t, AddP1D(p1, p2), ref)
+
+	sub := SubP1D(ref, p2)
+	for i, bin := range sub.Binning().Bins() {
+		want := p1.Binning().Bins()[i]
+		if got, want := bin.SumW(), want.SumW(); math.Abs(got-want) > 1e-12 {
+			t.Fatalf("invalid sumw for bin %d: got=%v, want=%v", i, got, want)
+		}
+		if got, want := bin.YMean(), want.YMean(); math.Abs(got-want) > 1e-12 {
+			t.Fatalf("invalid y-mean for bin %d: got=%v, want=%v", i, got, want)
+		}
+	}
+
+	func() {
+		defer func() {
+			err := recover()
+			if err == nil {
+				t.Fatalf("expected a panic")
+			}
+			if got, want := err.(error).Error(), "hbook: p1 and p2 have different range"; got != want {
+				t.Fatalf("invalid panic message.\ngot= %v\nwant=%v", got, want)
+			}
+		}()
+		_ = AddP1D(NewP1D(4, 0, 4), NewP1D(4, 0, 5))
+	}()
+}
+
+func TestDivideMultiplyH2D(t *testing.T) {
+	h1 := NewH2D(2, 0, 2, 1, 0, 2)
+	h2 := NewH2D(2, 0, 2, 1, 0, 2)
+	h1.Fill(0.5, 1, 4)
+	h1.Fill(1.5, 1, 1)
+	h2.Fill(0.5, 1, 2)
+
+	div, err := DivideH2D(h1, h2)
+	if err != nil {
+		t.Fatalf("could not divide: %+v", err)
+	}
+	if got, want := div.Len(), 2; got != want {
+		t.Fatalf("invalid number of points: got=%d, want=%d", got, want)
+	}
+	if got, want := div.Point(0), (Point3D{
+		X: 0.5, Y: 1, Z: 2,
+		ErrX: Range{Min: 0.5, Max: 0.5},
+		ErrY: Range{Min: 1, Max: 1},
+		ErrZ: Range{Min: 2 * math.Sqrt(2), Max: 2 * math.Sqrt(2)},
+	}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid point:\ngot= %+v\nwant=%+v", got, want)
+	}
+	if z := div.Point(1).Z; !math.IsNaN(z) {
+		t.Fatalf("invalid NaN point: got=%v", z)
+	}
+
+	div, err = DivideH2D(h1, h2, DivIgnoreNaNs())
+	if err != nil {
+		t.Fatalf("could not divide: %+v", err)
+	}
+	if got, want := div.Len(), 1; got != want {
+		t.Fatalf("invalid number of points: got=%d, want=%d", got, want)
+	}
+
+	div, err = DivideH2D(h1, h2, DivReplaceNaNs(-1))
+	if err != nil {
+		t.Fatalf("could not divide: %+v", err)
+	}
+	if got, want := div.Point(1).Z, -1.0; got != want {
+		t.Fatalf("invalid replaced NaN point: got=%v, want=%v", got, want)
+	}
+
+	mul, err := MultiplyH2D(h1, h2)
+	if err != nil {
+		t.Fatalf("could not multiply: %+v", err)
+	}
+	if got, want := mul.Point(0).Z, 2.0; got != want {
+		t.Fatalf("invalid product: got=%v, want=%v", got, want)
+	}
+	if got, want := mul.Point(0).ErrZ.Min, math.Hypot(2*1, 2*1); math.Abs(got-want) > 1e-12 {
+		t.Fatalf("invalid product error: got=%v, want=%v", got, want)
+	}
+	if got, want := mul.Point(1).Z, 0.0; got != want {
+		t.Fatalf("invalid product: got=%v, want=%v", got, want)
+	}
+
+	_, err = DivideH2D(h1, NewH2D(2, 0, 2, 1, 0, 3))
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	_, err = MultiplyH2D(h1, NewH2D(1, 0, 2, 2, 0, 2))
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestMultiplyH1D(t *testing.T) {
+	h1 := NewH1D(2, 0, 4)
+	h2 := NewH1D(2, 0, 4)
+	h1.Fill(1, 4)
+	h2.Fill(1, 3)
+	h2.Fill(3, 1)
+
+	s, err := MultiplyH1D(h1, h2)
+	if err != nil {
+		t.Fatalf("could not multiply: %+v", err)
+	}
+	want := NewS2D(
+		Point2D{X: 1, Y: 3, ErrX: Range{Min: 1, Max: 1}, ErrY: Range{Min: 3 * math.Sqrt(2), Max: 3 * math.Sqrt(2)}},
+		Point2D{X: 3, Y: 0, ErrX: Range{Min: 1, Max: 1}, ErrY: Range{Min: 0, Max: 0}},
+	)
+	if got := s.Points(); !reflect.DeepEqual(got, want.Points()) {
+		t.Fatalf("invalid product:\ngot= %+v\nwant=%+v", got, want.Points())
+	}
+
+	_, err = MultiplyH1D(h1, NewH1D(2, 0, 3))
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestDivideMultiplyP1D(t *testing.T) {
+	p1 := NewP1D(3, 0, 3)
+	p2 := NewP1D(3, 0, 3)
+	p1.Fill(0.5, 4, 1)
+	p1.Fill(0.5, 6, 1)
+	p1.Fill(1.5, 3, 1)
+	p2.Fill(0.5, 1, 1)
+	p2.Fill(0.5, 3, 1)
+	p2.Fill(2.5, 3, 1)
+
+	var (
+		b1 = p1.Binning().Bins()[0]
+		b2 = p2.Binning().Bins()[0]
+		e1 = b1.YStdErr()
+		e2 = b2.YStdErr()
+	)
+
+	div, err := DivideP1D(p1, p2)
+	if err != nil {
+		t.Fatalf("could not divide: %+v", err)
+	}
+	if got, want := div.Len(), 3; got != want {
+		t.Fatalf("invalid number of points: got=%d, want=%d", got, want)
+	}
+	if got, want := div.Point(0).Y, 2.5; got != want {
+		t.Fatalf("invalid ratio: got=%v, want=%v", got, want)
+	}
+	if got, want := div.Point(0).ErrY.Max, math.Hypot(e1/2, 2.5*e2/2); math.Abs(got-want) > 1e-12 {
+		t.Fatalf("invalid ratio error: got=%v, want=%v", got, want)
+	}
+	for _, i := range []int{1, 2} {
+		if y := div.Point(i).Y; !math.IsNaN(y) {
+			t.Fatalf("invalid NaN point %d: got=%v", i, y)
+		}
+	}
+
+	div, err = DivideP1D(p1, p2, DivIgnoreNaNs())
+	if err != nil {
+		t.Fatalf("could not divide: %+v", err)
+	}
+	if got, want := div.Len(), 1; got != want {
+		t.Fatalf("invalid number of points: got=%d, want=%d", got, want)
+	}
+
+	mul, err := MultiplyP1D(p1, p2)
+	if err != nil {
+		t.Fatalf("could not multiply: %+v", err)
+	}
+	if got, want := mul.Point(0).Y, 10.0; got != want {
+		t.Fatalf("invalid product: got=%v, want=%v", got, want)
+	}
+	if got, want := mul.Point(0).ErrY.Min, math.Hypot(e1*2, 5*e2); math.Abs(got-want) > 1e-12 {
+		t.Fatalf("invalid product error: got=%v, want=%v", got, want)
+	}
+	if y := mul.Point(1).Y; !math.IsNaN(y) {
+		t.Fatalf("invalid NaN point: got=%v", y)
+	}
+
+	_, err = DivideP1D(p1, NewP1D(3, 0, 4))
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestOpsAnnotation(t *testing.T) {
+	var (
+		h1 = NewH1D(2, 0, 2)
+		h2 = NewH2D(2, 0, 2, 2, 0, 2)
+		p1 = NewP1D(2, 0, 2)
+	)
+	h1.Fill(0.5, 1)
+	h2.Fill(0.5, 0.5, 1)
+	p1.Fill(0.5, 1, 1)
+
+	type annotater interface {
+		Annotation() Annotation
+		Name() string
+	}
+
+	for _, tc := range []struct {
+		name string
+		op   func() (annotater, error)
+	}{
+		{"MultiplyH1D", func() (annotater, error) { return MultiplyH1D(h1, h1) }},
+		{"DivideH2D", func() (annotater, error) { return DivideH2D(h2, h2) }},
+		{"MultiplyH2D", func() (annotater, error) { return MultiplyH2D(h2, h2) }},
+		{"DivideP1D", func() (annotater, error) { return DivideP1D(p1, p1) }},
+		{"MultiplyP1D", func() (annotater, error) { return MultiplyP1D(p1, p1) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := tc.op()
+			if err != nil {
+				t.Fatalf("could not apply operation: %+v", err)
+			}
+			s.Annotation()["name"] = tc.name
+			if got, want := s.Name(), tc.name; got != want {
+				t.Fatalf("invalid name: got=%q, want=%q", got, want)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 )
 
@@ -46,6 +47,14 @@ func NewP1DFromH1D(h *H1D) *P1D {
 	}
 }
 
+// Clone returns a deep copy of this profile histogram.
+func (p *P1D) Clone() *P1D {
+	return &P1D{
+		bng: p.bng.clone(),
+		ann: p.ann.clone(),
+	}
+}
+
 // Name returns the name of this profile histogram, if any
 func (p *P1D) Name() string {
 	v, ok := p.ann["name"]
@@ -146,6 +155,32 @@ func (p *P1D) Scale(factor float64) {
 	p.bng.scaleW(factor)
 }
 
+// Rebin returns a new profile histogram where each group of n consecutive
+// bins has been merged into a single bin.
+// The receiver is left unmodified.
+// Rebin panics if n is not a positive divisor of the number of bins.
+func (p *P1D) Rebin(n int) *P1D {
+	return &P1D{
+		bng: p.bng.rebin(n),
+		ann: p.ann.clone(),
+	}
+}
+
+// RebinEdges returns a new profile histogram with the provided bin edges.
+// The receiver is left unmodified.
+//
+// Each edge must match an edge of the bins of the profile histogram.
+// Bins below (above) the first (last) edge are merged into the
+// underflow (overflow) bin.
+// RebinEdges panics if the edges are not sorted, if there are
+// duplicate edge values or if an edge does not match the current binning.
+func (p *P1D) RebinEdges(edges []float64) *P1D {
+	return &P1D{
+		bng: p.bng.rebinEdges(edges),
+		ann: p.ann.clone(),
+	}
+}
+
 // check various interfaces
 var _ Object = (*P1D)(nil)
 var _ Histogram = (*P1D)(nil)
@@ -425,6 +460,7 @@ scanLoop:
 	p.bng.dist = dist
 	p.bng.bins = bins
 	p.bng.outflows = oflows
+	p.bng.setStep()
 	return err
 }
 
@@ -554,6 +590,7 @@ scanLoop:
 	p.bng.dist = dist
 	p.bng.bins = bins
 	p.bng.outflows = oflows
+	p.bng.setStep()
 	return err
 }
 
@@ -588,6 +625,55 @@ func newBinningP1D(n int, xmin, xmax float64) binningP1D {
 	return bng
 }
 
+func newBinningP1DFromEdges(edges []float64) binningP1D {
+	if len(edges) <= 1 {
+		panic(errShortXAxis)
+	}
+	if !sort.IsSorted(sort.Float64Slice(edges)) {
+		panic(errNotSortedXAxis)
+	}
+	n := len(edges) - 1
+	bng := binningP1D{
+		bins:   make([]BinP1D, n),
+		xrange: Range{Min: edges[0], Max: edges[n]},
+	}
+	for i := range bng.bins {
+		bin := &bng.bins[i]
+		xmin := edges[i]
+		xmax := edges[i+1]
+		if xmin == xmax {
+			panic(errDupEdgesXAxis)
+		}
+		bin.xrange.Min = xmin
+		bin.xrange.Max = xmax
+	}
+	bng.setStep()
+
+	return bng
+}
+
+// setStep sets the step used to locate bins from their bin edges.
+// The step is zero for variable-size bins.
+func (bng *binningP1D) setStep() {
+	var (
+		n = len(bng.bins)
+		w = bng.xrange.Width() / float64(n)
+	)
+	bng.xstep = float64(n) / bng.xrange.Width()
+	for i := range bng.bins {
+		if !fuzzyEq(bng.bins[i].XWidth(), w) {
+			bng.xstep = 0
+			return
+		}
+	}
+}
+
+func (bng *binningP1D) clone() binningP1D {
+	o := *bng
+	o.bins = append([]BinP1D(nil), bng.bins...)
+	return o
+}
+
 func (bng *binningP1D) entries() int64 {
 	return bng.dist.Entries()
 }
@@ -620,6 +706,11 @@ func (bng *binningP1D) fill(x, y, w float64) {
 func (bng *binningP1D) coordToIndex(x float64) int {
 	switch {
 	default:
+		if bng.xstep == 0 {
+			return sort.Search(len(bng.bins), func(i int) bool {
+				return x < bng.bins[i].xrange.Max
+			})
+		}
 		i := int((x - bng.xrange.Min) * bng.xstep)
 		return i
 	case x < bng.xrange.Min:
@@ -639,6 +730,42 @@ func (bng *binningP1D) scaleW(f float64) {
 	}
 }
 
+// rebin returns a new binning where each group of n consecutive bins
+// has been merged into a single bin.
+func (bng *binningP1D) rebin(n int) binningP1D {
+	if n <= 0 || len(bng.bins)%n != 0 {
+		panic(errInvalidRebin)
+	}
+	edges := make([]float64, 0, len(bng.bins)/n+1)
+	for i := 0; i < len(bng.bins); i += n {
+		edges = append(edges, bng.bins[i].xrange.Min)
+	}
+	edges = append(edges, bng.bins[len(bng.bins)-1].xrange.Max)
+	return bng.rebinEdges(edges)
+}
+
+// rebinEdges returns a new binning with the provided edges.
+// Bins outside of the new edges are merged into the under/over flows.
+func (bng *binningP1D) rebinEdges(edges []float64) binningP1D {
+	idx := rebinIndices(edges, len(bng.bins), func(i int) Range {
+		return bng.bins[i].xrange
+	})
+
+	o := newBinningP1DFromEdges(edges)
+	o.dist = bng.dist
+	o.outflows = bng.outflows
+	for i, bin := range bng.bins {
+		switch j := idx[i]; {
+		case j < 0:
+			o.outflows[-j-1].addScaled(1, 1, bin.dist)
+		default:
+			o.bins[j].dist.addScaled(1, 1, bin.dist)
+		}
+	}
+
+	return o
+}
+
 // Bins returns the slice of bins for this binning.
 func (bng *binningP1D) Bins() []BinP1D {
 	return bng.bins
@@ -661,6 +788,10 @@ func (b *BinP1D) fill(x, y, w float64) {
 	b.dist.fill(x, y, w)
 }
 
+func (b *BinP1D) addScaled(a, a2 float64, o BinP1D) {
+	b.dist.addScaled(a, a2, o.dist)
+}
+
 // Entries returns the number of entries in this bin.
 func (b *BinP1D) Entries() int64 {
 	return b.dist.Entries()
@@ -740,3 +871,28 @@ func (b *BinP1D) XStdErr() float64 {
 func (b *BinP1D) XRMS() float64 {
 	return b.dist.xRMS()
 }
+
+// YMean returns the mean Y.
+func (b *BinP1D) YMean() float64 {
+	return b.dist.yMean()
+}
+
+// YVariance returns the variance in Y.
+func (b *BinP1D) YVariance() float64 {
+	return b.dist.yVariance()
+}
+
+// YStdDev returns the standard deviation in Y.
+func (b *BinP1D) YStdDev() float64 {
+	return b.dist.yStdDev()
+}
+
+// YStdErr returns the standard error in Y.
+func (b *BinP1D) YStdErr() float64 {
+	return b.dist.yStdErr()
+}
+
+// YRMS returns the RMS in Y.
+func (b *BinP1D) YRMS() float64 {
+	return b.dist.yRMS()
+}
@@ -211,3 +211,85 @@ func TestP1DSerialization(t *testing.T) {
 		}
 	}
 }
+
+func TestP1DRebin(t *testing.T) {
+	fill := func(p *P1D) *P1D {
+		p.Annotation()["name"] = "p1d"
+		for i := -2; i < 24; i++ {
+			x := float64(i) * 0.5
+			p.Fill(x, 2*x+1, float64(i%3+1))
+		}
+		return p
+	}
+
+	for _, tc := range []struct {
+		name string
+		got  func(p *P1D) *P1D
+		want *P1D
+	}{
+		{
+			name: "rebin-2",
+			got:  func(p *P1D) *P1D { return p.Rebin(2) },
+			want: NewP1D(5, 0, 10),
+		},
+		{
+			name: "rebin-5",
+			got:  func(p *P1D) *P1D { return p.Rebin(5) },
+			want: NewP1D(2, 0, 10),
+		},
+		{
+			name: "edges",
+			got:  func(p *P1D) *P1D { return p.RebinEdges([]float64{1, 2, 5, 9}) },
+			want: &P1D{
+				bng: newBinningP1DFromEdges([]float64{1, 2, 5, 9}),
+				ann: make(Annotation),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := fill(NewP1D(10, 0, 10))
+			orig, err := p.MarshalYODA()
+			if err != nil {
+				t.Fatalf("could not marshal original: %+v", err)
+			}
+
+			got, err := tc.got(p).MarshalYODA()
+			if err != nil {
+				t.Fatalf("could not marshal rebinned profile: %+v", err)
+			}
+
+			want, err := fill(tc.want).MarshalYODA()
+			if err != nil {
+				t.Fatalf("could not marshal reference: %+v", err)
+			}
+
+			if !bytes.Equal(got, want) {
+				t.Fatalf("invalid rebinned profile:\n%s", cmp.Diff(string(want), string(got)))
+			}
+
+			chk, err := p.MarshalYODA()
+			if err != nil {
+				t.Fatalf("could not marshal original: %+v", err)
+			}
+			if !bytes.Equal(chk, orig) {
+				t.Fatalf("original profile modified:\n%s", cmp.Diff(string(orig), string(chk)))
+			}
+		})
+	}
+
+	p := NewP1D(10, 0, 10)
+	for _, n := range []int{-1, 0, 3} {
+		func() {
+			defer func() {
+				err := recover()
+				if err == nil {
+					t.Fatalf("expected a panic (n=%d)", n)
+				}
+				if got, want := err.(error), errInvalidRebin; got != want {
+					t.Fatalf("invalid panic: got=%v, want=%v", got, want)
+				}
+			}()
+			p.Rebin(n)
+		}()
+	}
+}
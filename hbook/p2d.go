@@ -0,0 +1,712 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hbook
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// P2D is a 2-dim profile histogram.
+type P2D struct {
+	bng binningP2D
+	ann Annotation
+}
+
+// NewP2D returns a 2-dim profile histogram with nx bins between xmin and xmax
+// and ny bins between ymin and ymax.
+func NewP2D(nx int, xmin, xmax float64, ny int, ymin, ymax float64) *P2D {
+	return &P2D{
+		bng: newBinningP2D(nx, xmin, xmax, ny, ymin, ymax),
+		ann: make(Annotation),
+	}
+}
+
+// NewP2DFromH2D creates a 2-dim profile histogram from a 2-dim histogram's binning.
+func NewP2DFromH2D(h *H2D) *P2D {
+	return &P2D{
+		bng: newBinningP2D(h.Binning.Nx, h.XMin(), h.XMax(), h.Binning.Ny, h.YMin(), h.YMax()),
+		ann: make(Annotation),
+	}
+}
+
+// Name returns the name of this profile histogram, if any
+func (p *P2D) Name() string {
+	v, ok := p.ann["name"]
+	if !ok {
+		return ""
+	}
+	n, ok := v.(string)
+	if !ok {
+		return ""
+	}
+	return n
+}
+
+// Annotation returns the annotations attached to this profile histogram
+func (p *P2D) Annotation() Annotation {
+	return p.ann
+}
+
+// Rank returns the number of dimensions for this profile histogram
+func (p *P2D) Rank() int {
+	return 2
+}
+
+// Entries returns the number of entries in this profile histogram
+func (p *P2D) Entries() int64 {
+	return p.bng.entries()
+}
+
+// EffEntries returns the number of effective entries in this profile histogram
+func (p *P2D) EffEntries() float64 {
+	return p.bng.effEntries()
+}
+
+// Binning returns the binning of this profile histogram
+func (p *P2D) Binning() *binningP2D {
+	return &p.bng
+}
+
+// SumW returns the sum of weights in this profile histogram.
+// Overflows are included in the computation.
+func (p *P2D) SumW() float64 {
+	return p.bng.dist.SumW()
+}
+
+// SumW2 returns the sum of squared weights in this profile histogram.
+// Overflows are included in the computation.
+func (p *P2D) SumW2() float64 {
+	return p.bng.dist.SumW2()
+}
+
+// XMean returns the mean X.
+// Overflows are included in the computation.
+func (p *P2D) XMean() float64 {
+	return p.bng.dist.xMean()
+}
+
+// YMean returns the mean Y.
+// Overflows are included in the computation.
+func (p *P2D) YMean() float64 {
+	return p.bng.dist.yMean()
+}
+
+// XVariance returns the variance in X.
+// Overflows are included in the computation.
+func (p *P2D) XVariance() float64 {
+	return p.bng.dist.xVariance()
+}
+
+// YVariance returns the variance in Y.
+// Overflows are included in the computation.
+func (p *P2D) YVariance() float64 {
+	return p.bng.dist.yVariance()
+}
+
+// XStdDev returns the standard deviation in X.
+// Overflows are included in the computation.
+func (p *P2D) XStdDev() float64 {
+	return p.bng.dist.xStdDev()
+}
+
+// YStdDev returns the standard deviation in Y.
+// Overflows are included in the computation.
+func (p *P2D) YStdDev() float64 {
+	return p.bng.dist.yStdDev()
+}
+
+// XStdErr returns the standard error in X.
+// Overflows are included in the computation.
+func (p *P2D) XStdErr() float64 {
+	return p.bng.dist.xStdErr()
+}
+
+// YStdErr returns the standard error in Y.
+// Overflows are included in the computation.
+func (p *P2D) YStdErr() float64 {
+	return p.bng.dist.yStdErr()
+}
+
+// XRMS returns the RMS in X.
+// Overflows are included in the computation.
+func (p *P2D) XRMS() float64 {
+	return p.bng.dist.xRMS()
+}
+
+// YRMS returns the RMS in Y.
+// Overflows are included in the computation.
+func (p *P2D) YRMS() float64 {
+	return p.bng.dist.yRMS()
+}
+
+// Fill fills this profile histogram with (x,y), the profiled value z
+// and weight w.
+func (p *P2D) Fill(x, y, z, w float64) {
+	p.bng.fill(x, y, z, w)
+}
+
+// XMin returns the low edge of the X-axis of this profile histogram.
+func (p *P2D) XMin() float64 {
+	return p.bng.xMin()
+}
+
+// XMax returns the high edge of the X-axis of this profile histogram.
+func (p *P2D) XMax() float64 {
+	return p.bng.xMax()
+}
+
+// YMin returns the low edge of the Y-axis of this profile histogram.
+func (p *P2D) YMin() float64 {
+	return p.bng.yMin()
+}
+
+// YMax returns the high edge of the Y-axis of this profile histogram.
+func (p *P2D) YMax() float64 {
+	return p.bng.yMax()
+}
+
+// Scale scales the content of each bin by the given factor.
+func (p *P2D) Scale(factor float64) {
+	p.bng.scaleW(factor)
+}
+
+// check various interfaces
+var _ Object = (*P2D)(nil)
+var _ Histogram = (*P2D)(nil)
+
+// annToYODA creates a new Annotation with fields compatible with YODA
+func (p *P2D) annToYODA() Annotation {
+	ann := make(Annotation, len(p.ann))
+	ann["Type"] = "Profile2D"
+	ann["Path"] = "/" + p.Name()
+	ann["Title"] = ""
+	for k, v := range p.ann {
+		if k == "name" {
+			continue
+		}
+		if k == "title" {
+			ann["Title"] = v
+			continue
+		}
+		ann[k] = v
+	}
+	return ann
+}
+
+// annFromYODA creates a new Annotation from YODA compatible fields
+func (p *P2D) annFromYODA(ann Annotation) {
+	if len(p.ann) == 0 {
+		p.ann = make(Annotation, len(ann))
+	}
+	for k, v := range ann {
+		switch k {
+		case "Type":
+			// noop
+		case "Path":
+			name := v.(string)
+			name = strings.TrimPrefix(name, "/")
+			p.ann["name"] = name
+		case "Title":
+			p.ann["title"] = v
+		default:
+			p.ann[k] = v
+		}
+	}
+}
+
+// MarshalYODA implements the YODAMarshaler interface.
+func (p *P2D) MarshalYODA() ([]byte, error) {
+	return p.marshalYODAv2()
+}
+
+func (p *P2D) marshalYODAv1() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	ann := p.annToYODA()
+	fmt.Fprintf(buf, "BEGIN YODA_PROFILE2D %s\n", ann["Path"])
+	data, err := ann.marshalYODAv1()
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(data)
+	p.writeYODA(buf, func(n int64) string { return fmt.Sprintf("%d", n) })
+	fmt.Fprintf(buf, "END YODA_PROFILE2D\n\n")
+	return buf.Bytes(), err
+}
+
+func (p *P2D) marshalYODAv2() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	ann := p.annToYODA()
+	fmt.Fprintf(buf, "BEGIN YODA_PROFILE2D_V2 %s\n", ann["Path"])
+	data, err := ann.marshalYODAv2()
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(data)
+	buf.Write([]byte("---\n"))
+	p.writeYODA(buf, func(n int64) string { return fmt.Sprintf("%e", float64(n)) })
+	fmt.Fprintf(buf, "END YODA_PROFILE2D_V2\n\n")
+	return buf.Bytes(), err
+}
+
+// writeYODA writes the distributions of the profile in the YODA format.
+// entries formats the number of entries of a distribution.
+//
+// As YODA does, the sumwxz and sumwyz moments are not persisted.
+func (p *P2D) writeYODA(buf *bytes.Buffer, entries func(n int64) string) {
+	fmt.Fprintf(buf, "# Mean: (%e, %e)\n", p.XMean(), p.YMean())
+	fmt.Fprintf(buf, "# ID\t ID\t sumw\t sumw2\t sumwx\t sumwx2\t sumwy\t sumwy2\t sumwz\t sumwz2\t sumwxy\t numEntries\n")
+	d := p.bng.dist
+	fmt.Fprintf(
+		buf,
+		"Total   \tTotal   \t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%s\n",
+		d.SumW(), d.SumW2(), d.SumWX(), d.SumWX2(), d.SumWY(), d.SumWY2(), d.SumWZ(), d.SumWZ2(),
+		d.SumWXY(), entries(d.Entries()),
+	)
+
+	// outflows
+	fmt.Fprintf(buf, "# 2D outflow persistency not currently supported until API is stable\n")
+
+	// bins
+	fmt.Fprintf(buf, "# xlow\t xhigh\t ylow\t yhigh\t sumw\t sumw2\t sumwx\t sumwx2\t sumwy\t sumwy2\t sumwz\t sumwz2\t sumwxy\t numEntries\n")
+	for ix := 0; ix < p.bng.nx; ix++ {
+		for iy := 0; iy < p.bng.ny; iy++ {
+			bin := p.bng.bins[iy*p.bng.nx+ix]
+			d := bin.dist
+			fmt.Fprintf(
+				buf,
+				"%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%s\n",
+				bin.xrange.Min, bin.xrange.Max, bin.yrange.Min, bin.yrange.Max,
+				d.SumW(), d.SumW2(), d.SumWX(), d.SumWX2(), d.SumWY(), d.SumWY2(), d.SumWZ(), d.SumWZ2(),
+				d.SumWXY(), entries(d.Entries()),
+			)
+		}
+	}
+}
+
+// UnmarshalYODA implements the YODAUnmarshaler interface.
+func (p *P2D) UnmarshalYODA(data []byte) error {
+	r := newRBuffer(data)
+	_, vers, err := readYODAHeader(r, "BEGIN YODA_PROFILE2D")
+	if err != nil {
+		return err
+	}
+	ann := make(Annotation)
+
+	// pos of end of annotations
+	pos := bytes.Index(r.Bytes(), []byte("\n# Mean:"))
+	if pos < 0 {
+		return fmt.Errorf("hbook: invalid P2D-YODA data")
+	}
+	switch vers {
+	case 1:
+		err = ann.unmarshalYODAv1(r.Bytes()[:pos+1])
+	case 2:
+		err = ann.unmarshalYODAv2(r.Bytes()[:pos+1])
+	default:
+		return fmt.Errorf("hbook: invalid YODA version %v", vers)
+	}
+	if err != nil {
+		return fmt.Errorf("hbook: %q\nhbook: %w", string(r.Bytes()[:pos+1]), err)
+	}
+	p.annFromYODA(ann)
+	r.next(pos)
+
+	var ctx struct {
+		total bool
+		bins  bool
+	}
+
+	// sets of xlow and ylow values, to infer number of bins in X and Y.
+	xset := make(map[float64]int)
+	yset := make(map[float64]int)
+
+	var (
+		dist Dist3D
+		bins []BinP2D
+		xmin = math.Inf(+1)
+		xmax = math.Inf(-1)
+		ymin = math.Inf(+1)
+		ymax = math.Inf(-1)
+	)
+	s := bufio.NewScanner(r)
+scanLoop:
+	for s.Scan() {
+		buf := s.Bytes()
+		if len(buf) == 0 || buf[0] == '#' {
+			continue
+		}
+		rbuf := bytes.NewReader(buf)
+		switch {
+		case bytes.HasPrefix(buf, []byte("END YODA_PROFILE2D")):
+			break scanLoop
+		case !ctx.total && bytes.HasPrefix(buf, []byte("Total   \t")):
+			ctx.total = true
+			var n float64
+			d := &dist
+			_, err = fmt.Fscanf(
+				rbuf,
+				"Total   \tTotal   \t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\n",
+				&d.X.Dist.SumW, &d.X.Dist.SumW2,
+				&d.X.Stats.SumWX, &d.X.Stats.SumWX2,
+				&d.Y.Stats.SumWX, &d.Y.Stats.SumWX2,
+				&d.Z.Stats.SumWX, &d.Z.Stats.SumWX2,
+				&d.Stats.SumWXY,
+				&n,
+			)
+			if err != nil {
+				return fmt.Errorf("hbook: %q\nhbook: %w", string(buf), err)
+			}
+			d.X.Dist.N = int64(n)
+			d.Y.Dist = d.X.Dist
+			d.Z.Dist = d.X.Dist
+			ctx.bins = true
+		case ctx.bins:
+			var (
+				bin BinP2D
+				n   float64
+			)
+			d := &bin.dist
+			_, err = fmt.Fscanf(
+				rbuf,
+				"%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\n",
+				&bin.xrange.Min, &bin.xrange.Max, &bin.yrange.Min, &bin.yrange.Max,
+				&d.X.Dist.SumW, &d.X.Dist.SumW2,
+				&d.X.Stats.SumWX, &d.X.Stats.SumWX2,
+				&d.Y.Stats.SumWX, &d.Y.Stats.SumWX2,
+				&d.Z.Stats.SumWX, &d.Z.Stats.SumWX2,
+				&d.Stats.SumWXY,
+				&n,
+			)
+			if err != nil {
+				return fmt.Errorf("hbook: %q\nhbook: %w", string(buf), err)
+			}
+			d.X.Dist.N = int64(n)
+			d.Y.Dist = d.X.Dist
+			d.Z.Dist = d.X.Dist
+			xset[bin.xrange.Min] = 1
+			yset[bin.yrange.Min] = 1
+			xmin = math.Min(xmin, bin.xrange.Min)
+			xmax = math.Max(xmax, bin.xrange.Max)
+			ymin = math.Min(ymin, bin.yrange.Min)
+			ymax = math.Max(ymax, bin.yrange.Max)
+			bins = append(bins, bin)
+
+		default:
+			return fmt.Errorf("hbook: invalid P2D-YODA data: %q", string(buf))
+		}
+	}
+	p.bng = newBinningP2D(len(xset), xmin, xmax, len(yset), ymin, ymax)
+	p.bng.dist = dist
+	if len(bins) != p.bng.nx*p.bng.ny {
+		return fmt.Errorf("hbook: invalid P2D-YODA data: got %d bins, want %d", len(bins), p.bng.nx*p.bng.ny)
+	}
+	// YODA bins are transposed wrt ours
+	for ix := 0; ix < p.bng.nx; ix++ {
+		for iy := 0; iy < p.bng.ny; iy++ {
+			p.bng.bins[iy*p.bng.nx+ix] = bins[ix*p.bng.ny+iy]
+		}
+	}
+	return err
+}
+
+// binningP2D is a 2-dim binning for 2-dim profile histograms.
+type binningP2D struct {
+	bins     []BinP2D
+	dist     Dist3D
+	outflows [8]Dist3D
+	xrange   Range
+	yrange   Range
+	nx       int
+	ny       int
+	xstep    float64
+	ystep    float64
+}
+
+func newBinningP2D(nx int, xmin, xmax float64, ny int, ymin, ymax float64) binningP2D {
+	if xmin >= xmax {
+		panic(errInvalidXAxis)
+	}
+	if ymin >= ymax {
+		panic(errInvalidYAxis)
+	}
+	if nx <= 0 {
+		panic(errEmptyXAxis)
+	}
+	if ny <= 0 {
+		panic(errEmptyYAxis)
+	}
+	bng := binningP2D{
+		bins:   make([]BinP2D, nx*ny),
+		xrange: Range{Min: xmin, Max: xmax},
+		yrange: Range{Min: ymin, Max: ymax},
+		nx:     nx,
+		ny:     ny,
+	}
+	bng.xstep = float64(nx) / bng.xrange.Width()
+	bng.ystep = float64(ny) / bng.yrange.Width()
+	xwidth := bng.xrange.Width() / float64(nx)
+	ywidth := bng.yrange.Width() / float64(ny)
+	for iy := 0; iy < ny; iy++ {
+		for ix := 0; ix < nx; ix++ {
+			bin := &bng.bins[iy*nx+ix]
+			bin.xrange.Min = xmin + float64(ix)*xwidth
+			bin.xrange.Max = xmin + float64(ix+1)*xwidth
+			bin.yrange.Min = ymin + float64(iy)*ywidth
+			bin.yrange.Max = ymin + float64(iy+1)*ywidth
+		}
+	}
+
+	return bng
+}
+
+func (bng *binningP2D) entries() int64 {
+	return bng.dist.Entries()
+}
+
+func (bng *binningP2D) effEntries() float64 {
+	return bng.dist.EffEntries()
+}
+
+// xMin returns the low edge of the X-axis
+func (bng *binningP2D) xMin() float64 {
+	return bng.xrange.Min
+}
+
+// xMax returns the high edge of the X-axis
+func (bng *binningP2D) xMax() float64 {
+	return bng.xrange.Max
+}
+
+// yMin returns the low edge of the Y-axis
+func (bng *binningP2D) yMin() float64 {
+	return bng.yrange.Min
+}
+
+// yMax returns the high edge of the Y-axis
+func (bng *binningP2D) yMax() float64 {
+	return bng.yrange.Max
+}
+
+func (bng *binningP2D) fill(x, y, z, w float64) {
+	idx := bng.coordToIndex(x, y)
+	bng.dist.fill(x, y, z, w)
+	if idx < 0 {
+		bng.outflows[-idx-1].fill(x, y, z, w)
+		return
+	}
+	bng.bins[idx].fill(x, y, z, w)
+}
+
+// coordToIndex returns the bin index corresponding to the coordinates (x,y).
+// Outflows are returned as negative indices (see BngNW, ..., BngW.)
+func (bng *binningP2D) coordToIndex(x, y float64) int {
+	ix := bng.axisIndex(x, bng.xrange, bng.xstep, bng.nx)
+	iy := bng.axisIndex(y, bng.yrange, bng.ystep, bng.ny)
+
+	switch {
+	case ix == OverflowBin1D && iy == OverflowBin1D:
+		return -BngNE
+	case ix == OverflowBin1D && iy == UnderflowBin1D:
+		return -BngSE
+	case ix == UnderflowBin1D && iy == UnderflowBin1D:
+		return -BngSW
+	case ix == UnderflowBin1D && iy == OverflowBin1D:
+		return -BngNW
+	case ix == OverflowBin1D:
+		return -BngE
+	case ix == UnderflowBin1D:
+		return -BngW
+	case iy == OverflowBin1D:
+		return -BngN
+	case iy == UnderflowBin1D:
+		return -BngS
+	}
+	return iy*bng.nx + ix
+}
+
+func (bng *binningP2D) axisIndex(v float64, rng Range, step float64, n int) int {
+	switch {
+	case v < rng.Min:
+		return UnderflowBin1D
+	case v >= rng.Max:
+		return OverflowBin1D
+	}
+	i := int((v - rng.Min) * step)
+	if i >= n {
+		// protect against rounding errors near the upper edge.
+		i = n - 1
+	}
+	return i
+}
+
+func (bng *binningP2D) scaleW(f float64) {
+	bng.dist.scaleW(f)
+	for i := range bng.outflows {
+		bng.outflows[i].scaleW(f)
+	}
+	for i := range bng.bins {
+		bin := &bng.bins[i]
+		bin.scaleW(f)
+	}
+}
+
+// Bins returns the slice of bins for this binning.
+func (bng *binningP2D) Bins() []BinP2D {
+	return bng.bins
+}
+
+// Dist returns the distribution of all the entries of this binning,
+// including outflows.
+// The returned value may be used to modify the distribution.
+func (bng *binningP2D) Dist() *Dist3D {
+	return &bng.dist
+}
+
+// Outflows returns the distributions of the outflows of this binning,
+// indexed by BngNW-1, ..., BngW-1.
+// The returned slice may be used to modify the distributions.
+func (bng *binningP2D) Outflows() []Dist3D {
+	return bng.outflows[:]
+}
+
+// Nx returns the number of bins along the X-axis.
+func (bng *binningP2D) Nx() int {
+	return bng.nx
+}
+
+// Ny returns the number of bins along the Y-axis.
+func (bng *binningP2D) Ny() int {
+	return bng.ny
+}
+
+// BinP2D models a bin in a 2-dim space.
+type BinP2D struct {
+	xrange Range
+	yrange Range
+	dist   Dist3D
+}
+
+// Rank returns the number of dimensions for this bin.
+func (BinP2D) Rank() int { return 2 }
+
+func (b *BinP2D) scaleW(f float64) {
+	b.dist.scaleW(f)
+}
+
+func (b *BinP2D) fill(x, y, z, w float64) {
+	b.dist.fill(x, y, z, w)
+}
+
+// Dist returns the distribution of the entries of this bin.
+// The returned value may be used to modify the distribution.
+func (b *BinP2D) Dist() *Dist3D {
+	return &b.dist
+}
+
+// Entries returns the number of entries in this bin.
+func (b *BinP2D) Entries() int64 {
+	return b.dist.Entries()
+}
+
+// EffEntries returns the effective number of entries \f$ = (\sum w)^2 / \sum w^2 \f$
+func (b *BinP2D) EffEntries() float64 {
+	return b.dist.EffEntries()
+}
+
+// SumW returns the sum of weights in this bin.
+func (b *BinP2D) SumW() float64 {
+	return b.dist.SumW()
+}
+
+// SumW2 returns the sum of squared weights in this bin.
+func (b *BinP2D) SumW2() float64 {
+	return b.dist.SumW2()
+}
+
+// XEdges returns the [low,high] edges of this bin along X.
+func (b *BinP2D) XEdges() Range {
+	return b.xrange
+}
+
+// YEdges returns the [low,high] edges of this bin along Y.
+func (b *BinP2D) YEdges() Range {
+	return b.yrange
+}
+
+// XMin returns the lower limit of the bin along X (inclusive).
+func (b *BinP2D) XMin() float64 {
+	return b.xrange.Min
+}
+
+// XMax returns the upper limit of the bin along X (exclusive).
+func (b *BinP2D) XMax() float64 {
+	return b.xrange.Max
+}
+
+// YMin returns the lower limit of the bin along Y (inclusive).
+func (b *BinP2D) YMin() float64 {
+	return b.yrange.Min
+}
+
+// YMax returns the upper limit of the bin along Y (exclusive).
+func (b *BinP2D) YMax() float64 {
+	return b.yrange.Max
+}
+
+// XMid returns the geometric center of the bin along X.
+// i.e.: 0.5*(high+low)
+func (b *BinP2D) XMid() float64 {
+	return 0.5 * (b.xrange.Min + b.xrange.Max)
+}
+
+// YMid returns the geometric center of the bin along Y.
+// i.e.: 0.5*(high+low)
+func (b *BinP2D) YMid() float64 {
+	return 0.5 * (b.yrange.Min + b.yrange.Max)
+}
+
+// XWidth returns the (signed) width of the bin along X.
+func (b *BinP2D) XWidth() float64 {
+	return b.xrange.Max - b.xrange.Min
+}
+
+// YWidth returns the (signed) width of the bin along Y.
+func (b *BinP2D) YWidth() float64 {
+	return b.yrange.Max - b.yrange.Min
+}
+
+// XMean returns the mean X.
+func (b *BinP2D) XMean() float64 {
+	return b.dist.xMean()
+}
+
+// YMean returns the mean Y.
+func (b *BinP2D) YMean() float64 {
+	return b.dist.yMean()
+}
+
+// ZMean returns the mean of the profiled value.
+func (b *BinP2D) ZMean() float64 {
+	return b.dist.zMean()
+}
+
+// ZStdDev returns the standard deviation of the profiled value.
+func (b *BinP2D) ZStdDev() float64 {
+	return b.dist.zStdDev()
+}
+
+// ZStdErr returns the standard error of the profiled value.
+func (b *BinP2D) ZStdErr() float64 {
+	return b.dist.zStdErr()
+}
+
+// ZRMS returns the RMS of the profiled value.
+func (b *BinP2D) ZRMS() float64 {
+	return b.dist.zRMS()
+}
@@ -0,0 +1,186 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hbook
+
+import (
+	"bytes"
+	"encoding/gob"
+	"math"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestP2D(t *testing.T) {
+	p := NewP2D(2, 0, 2, 2, 0, 2)
+	if got, want := p.Rank(), 2; got != want {
+		t.Fatalf("invalid rank: got=%d, want=%d", got, want)
+	}
+
+	p.Fill(0.5, 0.5, 10, 1)
+	p.Fill(0.5, 0.5, 20, 1)
+	p.Fill(1.5, 0.5, 5, 2)
+	p.Fill(1.5, 1.5, 1, 1)
+	p.Fill(1.5, 1.5, 3, 1)
+	p.Fill(3, 3, 100, 1) // NE outflow
+
+	if got, want := p.Entries(), int64(6); got != want {
+		t.Fatalf("invalid entries: got=%d, want=%d", got, want)
+	}
+	if got, want := p.SumW(), 7.0; got != want {
+		t.Fatalf("invalid sumw: got=%v, want=%v", got, want)
+	}
+	if got, want := p.Binning().outflows[BngNE-1].SumW(), 1.0; got != want {
+		t.Fatalf("invalid NE outflow: got=%v, want=%v", got, want)
+	}
+
+	bins := p.Binning().Bins()
+	for _, tc := range []struct {
+		ix, iy int
+		n      int64
+		mean   float64
+		stddev float64
+	}{
+		{0, 0, 2, 15, math.Sqrt(50)},
+		{1, 0, 1, 5, math.NaN()},
+		{0, 1, 0, math.NaN(), math.NaN()},
+		{1, 1, 2, 2, math.Sqrt2},
+	} {
+		bin := bins[tc.iy*p.Binning().Nx()+tc.ix]
+		if got := bin.Entries(); got != tc.n {
+			t.Fatalf("bin(%d,%d): invalid entries: got=%d, want=%d", tc.ix, tc.iy, got, tc.n)
+		}
+		if tc.n == 0 {
+			continue
+		}
+		if got := bin.ZMean(); got != tc.mean {
+			t.Fatalf("bin(%d,%d): invalid mean: got=%v, want=%v", tc.ix, tc.iy, got, tc.mean)
+		}
+		if got := bin.ZStdDev(); tc.n > 1 && math.Abs(got-tc.stddev) > 1e-12 {
+			t.Fatalf("bin(%d,%d): invalid stddev: got=%v, want=%v", tc.ix, tc.iy, got, tc.stddev)
+		}
+	}
+
+	p.Scale(2)
+	if got, want := p.SumW(), 14.0; got != want {
+		t.Fatalf("invalid scaled sumw: got=%v, want=%v", got, want)
+	}
+	if got, want := bins[0].ZMean(), 15.0; got != want {
+		t.Fatalf("invalid scaled mean: got=%v, want=%v", got, want)
+	}
+}
+
+func newTestP2D() *P2D {
+	p := NewP2D(2, -1, 1, 3, -2, +2)
+	for i := 0; i < 10; i++ {
+		v := float64(i)
+		p.Fill(v/10-0.5, v/5-1, v*2, 1)
+	}
+	p.Fill(-10, 0, 10, 1)
+	return p
+}
+
+func TestP2DWriteYODA(t *testing.T) {
+	p := newTestP2D()
+
+	chk, err := p.MarshalYODA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref, err := os.ReadFile("testdata/p2d_v2_golden.yoda")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(chk, ref) {
+		t.Fatalf("p2d file differ:\n%s\n",
+			cmp.Diff(
+				string(ref),
+				string(chk),
+			),
+		)
+	}
+}
+
+func TestP2DReadYODAv1(t *testing.T) {
+	ref, err := os.ReadFile("testdata/p2d_v1_golden.yoda")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var p P2D
+	err = p.UnmarshalYODA(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chk, err := p.marshalYODAv1()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(chk, ref) {
+		t.Fatalf("p2d file differ:\n%s\n",
+			cmp.Diff(
+				string(ref),
+				string(chk),
+			),
+		)
+	}
+}
+
+func TestP2DReadYODAv2(t *testing.T) {
+	ref, err := os.ReadFile("testdata/p2d_v2_golden.yoda")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var p P2D
+	err = p.UnmarshalYODA(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chk, err := p.marshalYODAv2()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(chk, ref) {
+		t.Fatalf("p2d file differ:\n%s\n",
+			cmp.Diff(
+				string(ref),
+				string(chk),
+			),
+		)
+	}
+}
+
+func TestP2DSerialization(t *testing.T) {
+	pref := newTestP2D()
+	pref.Annotation()["title"] = "p2d title"
+	pref.Annotation()["name"] = "p2d-name"
+
+	buf := new(bytes.Buffer)
+	enc := gob.NewEncoder(buf)
+	err := enc.Encode(pref)
+	if err != nil {
+		t.Fatalf("could not serialize p2d: %v\n", err)
+	}
+
+	var pnew P2D
+	dec := gob.NewDecoder(buf)
+	err = dec.Decode(&pnew)
+	if err != nil {
+		t.Fatalf("could not deserialize p2d: %v\n", err)
+	}
+
+	if !reflect.DeepEqual(pref, &pnew) {
+		t.Fatalf("ref=%v\nnew=%v\n", pref, &pnew)
+	}
+}
@@ -80,3 +80,109 @@ func (p points2D) Less(i, j int) bool {
 	return false
 }
 func (p points2D) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+
+// Point3D is a position in a 3-dim space
+type Point3D struct {
+	X    float64 // x-position
+	Y    float64 // y-position
+	Z    float64 // z-position
+	ErrX Range   // error on x-position
+	ErrY Range   // error on y-position
+	ErrZ Range   // error on z-position
+}
+
+// XMin returns the X value minus negative X-error
+func (p Point3D) XMin() float64 {
+	return p.X - p.ErrX.Min
+}
+
+// XMax returns the X value plus positive X-error
+func (p Point3D) XMax() float64 {
+	return p.X + p.ErrX.Max
+}
+
+// YMin returns the Y value minus negative Y-error
+func (p Point3D) YMin() float64 {
+	return p.Y - p.ErrY.Min
+}
+
+// YMax returns the Y value plus positive Y-error
+func (p Point3D) YMax() float64 {
+	return p.Y + p.ErrY.Max
+}
+
+// ZMin returns the Z value minus negative Z-error
+func (p Point3D) ZMin() float64 {
+	return p.Z - p.ErrZ.Min
+}
+
+// ZMax returns the Z value plus positive Z-error
+func (p Point3D) ZMax() float64 {
+	return p.Z + p.ErrZ.Max
+}
+
+// ScaleX rescales the X value by a factor f.
+func (p *Point3D) ScaleX(f float64) {
+	p.X *= f
+	p.ErrX.Min *= f
+	p.ErrX.Max *= f
+}
+
+// ScaleY rescales the Y value by a factor f.
+func (p *Point3D) ScaleY(f float64) {
+	p.Y *= f
+	p.ErrY.Min *= f
+	p.ErrY.Max *= f
+}
+
+// ScaleZ rescales the Z value by a factor f.
+func (p *Point3D) ScaleZ(f float64) {
+	p.Z *= f
+	p.ErrZ.Min *= f
+	p.ErrZ.Max *= f
+}
+
+// ScaleXYZ rescales the X, Y and Z values by a factor f.
+func (p *Point3D) ScaleXYZ(f float64) {
+	p.ScaleX(f)
+	p.ScaleY(f)
+	p.ScaleZ(f)
+}
+
+// points3D implements sort.Interface
+type points3D []Point3D
+
+func (p points3D) Len() int { return len(p) }
+func (p points3D) Less(i, j int) bool {
+	pi := p[i]
+	pj := p[j]
+	if pi.X != pj.X {
+		return pi.X < pj.X
+	}
+	if pi.ErrX.Min != pj.ErrX.Min {
+		return pi.ErrX.Min < pj.ErrX.Min
+	}
+	if pi.ErrX.Max != pj.ErrX.Max {
+		return pi.ErrX.Max < pj.ErrX.Max
+	}
+	if pi.Y != pj.Y {
+		return pi.Y < pj.Y
+	}
+	if pi.ErrY.Min != pj.ErrY.Min {
+		return pi.ErrY.Min < pj.ErrY.Min
+	}
+	if pi.ErrY.Max != pj.ErrY.Max {
+		return pi.ErrY.Max < pj.ErrY.Max
+	}
+	if pi.Z != pj.Z {
+		return pi.Z < pj.Z
+	}
+	if pi.ErrZ.Min != pj.ErrZ.Min {
+		return pi.ErrZ.Min < pj.ErrZ.Min
+	}
+	if pi.ErrZ.Max != pj.ErrZ.Max {
+		return pi.ErrZ.Max < pj.ErrZ.Max
+	}
+	return false
+}
+func (p points3D) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
@@ -28,6 +28,20 @@ func H2D(h2 rhist.H2) *hbook.H2D {
 	return h2.(h2der).AsH2D()
 }
 
+type h3der interface {
+	AsH3D() *hbook.H3D
+}
+
+// H3D creates a new H3D from a TH3x.
+func H3D(h3 rhist.H3) *hbook.H3D {
+	return h3.(h3der).AsH3D()
+}
+
+// P2D creates a new P2D from a TProfile2D.
+func P2D(p *rhist.Profile2D) *hbook.P2D {
+	return p.AsP2D()
+}
+
 // S2D creates a new S2D from a TGraph, TGraphErrors or TGraphAsymmErrors.
 func S2D(g rhist.Graph) *hbook.S2D {
 	pts := make([]hbook.Point2D, g.Len())
@@ -52,6 +66,46 @@ func S2D(g rhist.Graph) *hbook.S2D {
 	return s2d
 }
 
+// S3D creates a new S3D from a TGraph2D or TGraph2DErrors.
+func S3D(g rhist.Graph2D) *hbook.S3D {
+	pts := make([]hbook.Point3D, g.Len())
+	for i := range pts {
+		x, y, z := g.XYZ(i)
+		pts[i].X = x
+		pts[i].Y = y
+		pts[i].Z = z
+	}
+
+	if g, ok := g.(rhist.Graph2DErrors); ok {
+		for i := range pts {
+			xlo, xhi := g.XError(i)
+			ylo, yhi := g.YError(i)
+			zlo, zhi := g.ZError(i)
+			pt := &pts[i]
+			pt.ErrX = hbook.Range{Min: xlo, Max: xhi}
+			pt.ErrY = hbook.Range{Min: ylo, Max: yhi}
+			pt.ErrZ = hbook.Range{Min: zlo, Max: zhi}
+		}
+	}
+	s3d := hbook.NewS3D(pts...)
+	s3d.Annotation()["name"] = g.Name()
+	s3d.Annotation()["title"] = g.Title()
+	return s3d
+}
+
+// Efficiency creates a new S2D from a TEfficiency, computing the bin-by-bin
+// efficiency of its passed and total histograms.
+// If no EffOptions is passed, binomial errors are computed.
+func Efficiency(e *rhist.Efficiency, opts ...hbook.EffOptions) (*hbook.S2D, error) {
+	s2d, err := hbook.EfficiencyH1D(H1D(e.Passed()), H1D(e.Total()), opts...)
+	if err != nil {
+		return nil, err
+	}
+	s2d.Annotation()["name"] = e.Name()
+	s2d.Annotation()["title"] = e.Title()
+	return s2d, nil
+}
+
 // FromH1D creates a new ROOT TH1D from a 1-dim hbook histogram.
 func FromH1D(h1 *hbook.H1D) *rhist.H1D {
 	return rhist.NewH1DFrom(h1)
@@ -62,7 +116,28 @@ func FromH2D(h2 *hbook.H2D) *rhist.H2D {
 	return rhist.NewH2DFrom(h2)
 }
 
+// FromH3D creates a new ROOT TH3D from a 3-dim hbook histogram.
+func FromH3D(h3 *hbook.H3D) *rhist.H3D {
+	return rhist.NewH3DFrom(h3)
+}
+
+// FromP2D creates a new ROOT TProfile2D from a 2-dim hbook profile histogram.
+func FromP2D(p2 *hbook.P2D) *rhist.Profile2D {
+	return rhist.NewProfile2DFrom(p2)
+}
+
 // FromS2D creates a new ROOT TGraphAsymmErrors from 2-dim hbook data points.
 func FromS2D(s2 *hbook.S2D) rhist.GraphErrors {
 	return rhist.NewGraphAsymmErrorsFrom(s2)
 }
+
+// FromS3D creates a new ROOT TGraph2DErrors from 3-dim hbook data points.
+func FromS3D(s3 *hbook.S3D) rhist.Graph2DErrors {
+	return rhist.NewGraph2DErrorsFrom(s3)
+}
+
+// FromEfficiency creates a new ROOT TEfficiency from the 1-dim hbook
+// histograms of passed and total events.
+func FromEfficiency(name, title string, passed, total *hbook.H1D) (*rhist.Efficiency, error) {
+	return rhist.NewEfficiencyFrom(name, title, passed, total)
+}
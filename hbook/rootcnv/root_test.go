@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"fmt"
 	"log"
+	"math"
 	"reflect"
 	"testing"
 
@@ -586,3 +587,237 @@ func TestFromS2D(t *testing.T) {
 		)
 	}
 }
+
+func TestFromS3D(t *testing.T) {
+	hg := hbook.NewS3D(
+		hbook.Point3D{X: 1, Y: 1, Z: 2, ErrX: hbook.Range{Min: 1, Max: 1}, ErrY: hbook.Range{Min: 3, Max: 3}, ErrZ: hbook.Range{Min: 2, Max: 2}},
+		hbook.Point3D{X: 2, Y: 1.5, Z: 3, ErrX: hbook.Range{Min: 1, Max: 1}, ErrY: hbook.Range{Min: 3, Max: 3}, ErrZ: hbook.Range{Min: 2, Max: 2}},
+		hbook.Point3D{X: -1, Y: +2, Z: 4, ErrX: hbook.Range{Min: 1, Max: 1}, ErrY: hbook.Range{Min: 3, Max: 3}, ErrZ: hbook.Range{Min: 2, Max: 2}},
+	)
+	hg.Annotation()["name"] = "s3d"
+	hg.Annotation()["title"] = "my title"
+
+	rg := rootcnv.FromS3D(hg)
+
+	hr := rootcnv.S3D(rg)
+
+	want, err := hg.MarshalYODA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := hr.MarshalYODA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Fatalf("invalid s3d:\n%s",
+			cmp.Diff(
+				string(want),
+				string(got),
+			),
+		)
+	}
+}
+
+func TestFromH3D(t *testing.T) {
+	var (
+		rnd = rand.New(rand.NewSource(1234))
+		h3  = hbook.NewH3D(10, -4, +4, 5, -3, +3, 4, -2, +2)
+	)
+	h3.Annotation()["name"] = "h3"
+	for i := 0; i < 1000; i++ {
+		h3.Fill(rnd.NormFloat64(), rnd.NormFloat64(), rnd.NormFloat64(), 1)
+	}
+
+	r3 := rootcnv.FromH3D(h3)
+	if got, want := r3.SumW(), h3.SumW(); got != want {
+		t.Fatalf("sumw: got=%v, want=%v", got, want)
+	}
+	if got, want := r3.SumWZ(), h3.SumWZ(); got != want {
+		t.Fatalf("sumwz: got=%v, want=%v", got, want)
+	}
+
+	h := rootcnv.H3D(r3)
+	if got, want := h.Name(), h3.Name(); got != want {
+		t.Fatalf("invalid name: got=%q, want=%q", got, want)
+	}
+	if got, want := len(h.Binning.Bins), len(h3.Binning.Bins); got != want {
+		t.Fatalf("invalid number of bins: got=%d, want=%d", got, want)
+	}
+	for i := range h3.Binning.Bins {
+		var (
+			got  = &h.Binning.Bins[i]
+			want = &h3.Binning.Bins[i]
+		)
+		if got.XMin() != want.XMin() || got.YMin() != want.YMin() || got.ZMin() != want.ZMin() {
+			t.Fatalf("invalid bin %d edges", i)
+		}
+		if got.SumW() != want.SumW() || got.SumW2() != want.SumW2() {
+			t.Fatalf(
+				"invalid bin %d content: got=(%v, %v), want=(%v, %v)",
+				i, got.SumW(), got.SumW2(), want.SumW(), want.SumW2(),
+			)
+		}
+	}
+}
+
+func TestFromEfficiency(t *testing.T) {
+	var (
+		pass = hbook.NewH1D(4, 0, 4)
+		tot  = hbook.NewH1D(4, 0, 4)
+	)
+	for i := 0; i < 4; i++ {
+		x := float64(i) + 0.5
+		tot.Fill(x, 4)
+		pass.Fill(x, float64(i))
+	}
+
+	eff, err := rootcnv.FromEfficiency("eff", "my title", pass, tot)
+	if err != nil {
+		t.Fatalf("could not create efficiency: %+v", err)
+	}
+
+	if got, want := eff.Passed().Name(), "eff_passed"; got != want {
+		t.Fatalf("invalid passed name: got=%q, want=%q", got, want)
+	}
+	if got, want := eff.Total().Name(), "eff_total"; got != want {
+		t.Fatalf("invalid total name: got=%q, want=%q", got, want)
+	}
+
+	s2, err := rootcnv.Efficiency(eff)
+	if err != nil {
+		t.Fatalf("could not convert efficiency: %+v", err)
+	}
+
+	if got, want := s2.Name(), "eff"; got != want {
+		t.Fatalf("invalid name: got=%q, want=%q", got, want)
+	}
+	if got, want := s2.Len(), 4; got != want {
+		t.Fatalf("invalid number of points: got=%d, want=%d", got, want)
+	}
+	for i, want := range []float64{0, 0.25, 0.5, 0.75} {
+		if got := s2.Point(i).Y; got != want {
+			t.Fatalf("invalid efficiency[%d]: got=%v, want=%v", i, got, want)
+		}
+	}
+
+	_, err = rootcnv.FromEfficiency("eff", "", tot, pass)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	_, err = rootcnv.FromEfficiency("eff", "", pass, hbook.NewH1D(5, 0, 4))
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestP2D(t *testing.T) {
+	f, err := groot.Open("../../groot/testdata/tprofile.root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	obj, err := f.Get("p2d")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		root = obj.(*rhist.Profile2D)
+		p    = rootcnv.P2D(root)
+	)
+
+	if got, want := p.Name(), "p2d"; got != want {
+		t.Fatalf("invalid name: got=%q, want=%q", got, want)
+	}
+
+	bng := p.Binning()
+	if got, want := bng.Nx(), 40; got != want {
+		t.Fatalf("invalid nx: got=%d, want=%d", got, want)
+	}
+	if got, want := bng.Ny(), 40; got != want {
+		t.Fatalf("invalid ny: got=%d, want=%d", got, want)
+	}
+
+	if got, want := p.XMin(), -4.0; got != want {
+		t.Fatalf("invalid x-min: got=%v, want=%v", got, want)
+	}
+	if got, want := p.YMax(), +4.0; got != want {
+		t.Fatalf("invalid y-max: got=%v, want=%v", got, want)
+	}
+
+	sumw := 0.0
+	for _, bin := range bng.Bins() {
+		sumw += bin.SumW()
+	}
+	// ROOT does not account for under/overflows in its totals.
+	if got, want := sumw, p.SumW(); math.Abs(got-want) > 1e-9*want {
+		t.Fatalf("invalid sumw: got=%v, want=%v", got, want)
+	}
+}
+
+func TestFromP2D(t *testing.T) {
+	var (
+		rnd = rand.New(rand.NewSource(1234))
+		p2  = hbook.NewP2D(10, -4, +4, 5, -3, +3)
+	)
+	p2.Annotation()["name"] = "p2d"
+	p2.Annotation()["title"] = "my title"
+
+	for i := 0; i < 1000; i++ {
+		var (
+			x = 10*rnd.Float64() - 5
+			y = 8*rnd.Float64() - 4
+			z = x + y + rnd.NormFloat64()
+		)
+		p2.Fill(x, y, z, 1)
+	}
+
+	var (
+		rp = rootcnv.FromP2D(p2)
+		hp = rootcnv.P2D(rp)
+	)
+
+	if got, want := rp.Name(), "p2d"; got != want {
+		t.Fatalf("invalid name: got=%q, want=%q", got, want)
+	}
+	if got, want := rp.Title(), "my title"; got != want {
+		t.Fatalf("invalid title: got=%q, want=%q", got, want)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"sumw", hp.SumW(), p2.SumW()},
+		{"sumw2", hp.SumW2(), p2.SumW2()},
+		{"x-mean", hp.XMean(), p2.XMean()},
+		{"y-mean", hp.YMean(), p2.YMean()},
+		{"x-stddev", hp.XStdDev(), p2.XStdDev()},
+		{"y-stddev", hp.YStdDev(), p2.YStdDev()},
+	} {
+		if math.Abs(tc.got-tc.want) > 1e-9 {
+			t.Fatalf("invalid %s: got=%v, want=%v", tc.name, tc.got, tc.want)
+		}
+	}
+
+	var (
+		gbins = hp.Binning().Bins()
+		wbins = p2.Binning().Bins()
+	)
+	for i := range wbins {
+		if got, want := gbins[i].SumW(), wbins[i].SumW(); got != want {
+			t.Fatalf("invalid sumw for bin %d: got=%v, want=%v", i, got, want)
+		}
+		if wbins[i].SumW() == 0 {
+			continue
+		}
+		if got, want := gbins[i].ZMean(), wbins[i].ZMean(); math.Abs(got-want) > 1e-9 {
+			t.Fatalf("invalid z-mean for bin %d: got=%v, want=%v", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,439 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hbook
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// S3D is a collection of 3-dim data points with errors.
+type S3D struct {
+	pts []Point3D
+	ann Annotation
+}
+
+// NewS3D creates a new 3-dim scatter with pts as an optional
+// initial set of data points.
+func NewS3D(pts ...Point3D) *S3D {
+	s := &S3D{
+		pts: make([]Point3D, len(pts)),
+		ann: make(Annotation),
+	}
+	copy(s.pts, pts)
+	return s
+}
+
+// NewS3DFrom creates a new 3-dim scatter with x,y,z data slices.
+//
+// It panics if the lengths of the 3 slices don't match.
+func NewS3DFrom(x, y, z []float64) *S3D {
+	if len(x) != len(y) || len(x) != len(z) {
+		panic("hbook: len differ")
+	}
+
+	s := &S3D{
+		pts: make([]Point3D, len(x)),
+		ann: make(Annotation),
+	}
+	for i := range s.pts {
+		pt := &s.pts[i]
+		pt.X = x[i]
+		pt.Y = y[i]
+		pt.Z = z[i]
+	}
+	return s
+}
+
+// S3DOpts controls how S3D scatters are created from H2D and P2D.
+type S3DOpts struct {
+	UseFocus  bool
+	UseStdDev bool
+}
+
+// NewS3DFromH2D creates a new 3-dim scatter from the given H2D.
+// NewS3DFromH2D optionally takes a S3DOpts slice:
+// only the first element is considered.
+//
+// The Z value of each point is the density of its bin,
+// i.e. the sum of weights divided by the area of the bin.
+func NewS3DFromH2D(h *H2D, opts ...S3DOpts) *S3D {
+	s := NewS3D()
+	for k, v := range h.Ann {
+		s.ann[k] = v
+	}
+	var opt S3DOpts
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	for ix := 0; ix < h.Binning.Nx; ix++ {
+		for iy := 0; iy < h.Binning.Ny; iy++ {
+			bin := &h.Binning.Bins[iy*h.Binning.Nx+ix]
+			var x, y float64
+			if opt.UseFocus {
+				x, y = bin.XFocus(), bin.YFocus()
+			} else {
+				x, y = bin.XMid(), bin.YMid()
+			}
+			var z, ez float64
+			if area := bin.XWidth() * bin.YWidth(); area != 0 {
+				z = bin.SumW() / area
+				ez = math.Sqrt(bin.SumW2()) / area
+			} else {
+				z = math.NaN()
+				ez = math.NaN()
+			}
+			s.Fill(Point3D{
+				X: x, Y: y, Z: z,
+				ErrX: Range{x - bin.XMin(), bin.XMax() - x},
+				ErrY: Range{y - bin.YMin(), bin.YMax() - y},
+				ErrZ: Range{ez, ez},
+			})
+		}
+	}
+	return s
+}
+
+// NewS3DFromP2D creates a new 3-dim scatter from the given P2D.
+// NewS3DFromP2D optionally takes a S3DOpts slice:
+// only the first element is considered.
+//
+// The Z value of each point is the mean of the profiled value in its bin.
+func NewS3DFromP2D(p *P2D, opts ...S3DOpts) *S3D {
+	s := NewS3D()
+	for k, v := range p.ann {
+		s.ann[k] = v
+	}
+	var opt S3DOpts
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	for ix := 0; ix < p.bng.nx; ix++ {
+		for iy := 0; iy < p.bng.ny; iy++ {
+			bin := &p.bng.bins[iy*p.bng.nx+ix]
+			var x, y float64
+			if opt.UseFocus && bin.SumW() != 0 {
+				x, y = bin.XMean(), bin.YMean()
+			} else {
+				x, y = bin.XMid(), bin.YMid()
+			}
+			var z, ez float64
+			if bin.SumW() != 0 {
+				z = bin.ZMean()
+				if opt.UseStdDev {
+					ez = bin.ZStdDev()
+				} else {
+					ez = bin.ZStdErr()
+				}
+			} else {
+				z = math.NaN()
+				ez = math.NaN()
+			}
+			s.Fill(Point3D{
+				X: x, Y: y, Z: z,
+				ErrX: Range{x - bin.XMin(), bin.XMax() - x},
+				ErrY: Range{y - bin.YMin(), bin.YMax() - y},
+				ErrZ: Range{ez, ez},
+			})
+		}
+	}
+	return s
+}
+
+// Annotation returns the annotations attached to the
+// scatter. (e.g. name, title, ...)
+func (s *S3D) Annotation() Annotation {
+	return s.ann
+}
+
+// Name returns the name of this scatter
+func (s *S3D) Name() string {
+	v, ok := s.ann["name"]
+	if !ok {
+		return ""
+	}
+	n, ok := v.(string)
+	if !ok {
+		return ""
+	}
+	return n
+}
+
+// Rank returns the number of dimensions of this scatter.
+func (*S3D) Rank() int {
+	return 3
+}
+
+// Entries returns the number of entries of this scatter.
+func (s *S3D) Entries() int64 {
+	return int64(len(s.pts))
+}
+
+// Fill adds new points to the scatter.
+func (s *S3D) Fill(pts ...Point3D) {
+	if len(pts) == 0 {
+		return
+	}
+
+	i := len(s.pts)
+	s.pts = append(s.pts, make([]Point3D, len(pts))...)
+	copy(s.pts[i:], pts)
+}
+
+// Sort sorts the data points by x,y,z and x-err,y-err,z-err.
+func (s *S3D) Sort() {
+	sort.Sort(points3D(s.pts))
+}
+
+// Points returns the points of the scatter.
+//
+// Users may not modify the returned slice.
+// Users may not rely on the stability of the indices as the slice of points
+// may be re-sorted at any point in time.
+func (s *S3D) Points() []Point3D {
+	return s.pts
+}
+
+// Point returns the point at index i.
+//
+// Point panics if i is out of bounds.
+func (s *S3D) Point(i int) Point3D {
+	return s.pts[i]
+}
+
+// ScaleX rescales the X values by a factor f.
+func (s *S3D) ScaleX(f float64) {
+	for i := range s.pts {
+		p := &s.pts[i]
+		p.ScaleX(f)
+	}
+}
+
+// ScaleY rescales the Y values by a factor f.
+func (s *S3D) ScaleY(f float64) {
+	for i := range s.pts {
+		p := &s.pts[i]
+		p.ScaleY(f)
+	}
+}
+
+// ScaleZ rescales the Z values by a factor f.
+func (s *S3D) ScaleZ(f float64) {
+	for i := range s.pts {
+		p := &s.pts[i]
+		p.ScaleZ(f)
+	}
+}
+
+// ScaleXYZ rescales the X, Y and Z values by a factor f.
+func (s *S3D) ScaleXYZ(f float64) {
+	for i := range s.pts {
+		p := &s.pts[i]
+		p.ScaleXYZ(f)
+	}
+}
+
+// Len returns the number of points in the scatter.
+func (s *S3D) Len() int {
+	return len(s.pts)
+}
+
+// XYZ returns the x, y, z triple at index i.
+//
+// XYZ panics if i is out of bounds.
+// XYZ implements the gonum/plot/plotter.XYZer interface.
+func (s *S3D) XYZ(i int) (x, y, z float64) {
+	pt := s.pts[i]
+	x = pt.X
+	y = pt.Y
+	z = pt.Z
+	return
+}
+
+// XY returns the x, y pair at index i.
+//
+// XY panics if i is out of bounds.
+// XY implements the gonum/plot/plotter.XYZer interface.
+func (s *S3D) XY(i int) (x, y float64) {
+	pt := s.pts[i]
+	x = pt.X
+	y = pt.Y
+	return
+}
+
+// DataRange returns the minimum and maximum
+// x, y and z values.
+func (s *S3D) DataRange() (xmin, xmax, ymin, ymax, zmin, zmax float64) {
+	xmin = math.Inf(+1)
+	ymin = math.Inf(+1)
+	zmin = math.Inf(+1)
+	xmax = math.Inf(-1)
+	ymax = math.Inf(-1)
+	zmax = math.Inf(-1)
+	for _, p := range s.pts {
+		xmin = math.Min(p.XMin(), xmin)
+		xmax = math.Max(p.XMax(), xmax)
+		ymin = math.Min(p.YMin(), ymin)
+		ymax = math.Max(p.YMax(), ymax)
+		zmin = math.Min(p.ZMin(), zmin)
+		zmax = math.Max(p.ZMax(), zmax)
+	}
+	return
+}
+
+// annToYODA creates a new Annotation with fields compatible with YODA
+func (s *S3D) annToYODA() Annotation {
+	ann := make(Annotation, len(s.ann))
+	ann["Type"] = "Scatter3D"
+	ann["Path"] = "/" + s.Name()
+	ann["Title"] = ""
+	for k, v := range s.ann {
+		if k == "name" {
+			continue
+		}
+		if k == "title" {
+			ann["Title"] = v
+			continue
+		}
+		ann[k] = v
+	}
+	return ann
+}
+
+// annFromYODA creates a new Annotation from YODA compatible fields
+func (s *S3D) annFromYODA(ann Annotation) {
+	if len(s.ann) == 0 {
+		s.ann = make(Annotation, len(ann))
+	}
+	for k, v := range ann {
+		switch k {
+		case "Type":
+			// noop
+		case "Path":
+			name := v.(string)
+			name = strings.TrimPrefix(name, "/")
+			s.ann["name"] = name
+		case "Title":
+			s.ann["title"] = v
+		default:
+			s.ann[k] = v
+		}
+	}
+}
+
+// MarshalYODA implements the YODAMarshaler interface.
+func (s *S3D) MarshalYODA() ([]byte, error) {
+	return s.marshalYODAv2()
+}
+
+func (s *S3D) marshalYODAv1() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	ann := s.annToYODA()
+	fmt.Fprintf(buf, "BEGIN YODA_SCATTER3D %s\n", ann["Path"])
+	data, err := ann.marshalYODAv1()
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(data)
+	s.writeYODA(buf)
+	fmt.Fprintf(buf, "END YODA_SCATTER3D\n\n")
+	return buf.Bytes(), err
+}
+
+func (s *S3D) marshalYODAv2() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	ann := s.annToYODA()
+	fmt.Fprintf(buf, "BEGIN YODA_SCATTER3D_V2 %s\n", ann["Path"])
+	data, err := ann.marshalYODAv2()
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(data)
+	buf.Write([]byte("---\n"))
+	s.writeYODA(buf)
+	fmt.Fprintf(buf, "END YODA_SCATTER3D_V2\n\n")
+	return buf.Bytes(), err
+}
+
+func (s *S3D) writeYODA(buf *bytes.Buffer) {
+	fmt.Fprintf(buf, "# xval\t xerr-\t xerr+\t yval\t yerr-\t yerr+\t zval\t zerr-\t zerr+\n")
+	s.Sort()
+	for _, pt := range s.pts {
+		fmt.Fprintf(
+			buf,
+			"%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\n",
+			pt.X, pt.ErrX.Min, pt.ErrX.Max,
+			pt.Y, pt.ErrY.Min, pt.ErrY.Max,
+			pt.Z, pt.ErrZ.Min, pt.ErrZ.Max,
+		)
+	}
+}
+
+// UnmarshalYODA implements the YODAUnmarshaler interface.
+func (s *S3D) UnmarshalYODA(data []byte) error {
+	r := newRBuffer(data)
+	_, vers, err := readYODAHeader(r, "BEGIN YODA_SCATTER3D")
+	if err != nil {
+		return err
+	}
+	ann := make(Annotation)
+
+	// pos of end of annotations
+	pos := bytes.Index(r.Bytes(), []byte("\n# xval\t xerr-\t"))
+	if pos < 0 {
+		return fmt.Errorf("hbook: invalid Scatter3D-YODA data")
+	}
+	switch vers {
+	case 1:
+		err = ann.unmarshalYODAv1(r.Bytes()[:pos+1])
+	case 2:
+		err = ann.unmarshalYODAv2(r.Bytes()[:pos+1])
+	default:
+		return fmt.Errorf("hbook: invalid YODA version %v", vers)
+	}
+	if err != nil {
+		return fmt.Errorf("hbook: %q\nhbook: %w", string(r.Bytes()[:pos+1]), err)
+	}
+	s.annFromYODA(ann)
+	r.next(pos)
+
+	sc := bufio.NewScanner(r)
+scanLoop:
+	for sc.Scan() {
+		buf := sc.Bytes()
+		if len(buf) == 0 || buf[0] == '#' {
+			continue
+		}
+		rbuf := bytes.NewReader(buf)
+		switch {
+		case bytes.HasPrefix(buf, []byte("END YODA_SCATTER3D")):
+			break scanLoop
+		default:
+			var pt Point3D
+			_, err = fmt.Fscanf(
+				rbuf,
+				"%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\t%e\n",
+				&pt.X, &pt.ErrX.Min, &pt.ErrX.Max,
+				&pt.Y, &pt.ErrY.Min, &pt.ErrY.Max,
+				&pt.Z, &pt.ErrZ.Min, &pt.ErrZ.Max,
+			)
+			if err != nil {
+				return fmt.Errorf("hbook: %q\nhbook: %w", string(buf), err)
+			}
+			s.Fill(pt)
+		}
+	}
+	err = sc.Err()
+	if err == io.EOF {
+		err = nil
+	}
+	s.Sort()
+	return err
+}
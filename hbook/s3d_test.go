@@ -0,0 +1,188 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hbook
+
+import (
+	"bytes"
+	"encoding/gob"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestS3D(t *testing.T) {
+	s := NewS3D(Point3D{X: 1, Y: 1, Z: 1}, Point3D{X: 2, Y: 1.5, Z: 3}, Point3D{X: -1, Y: +2, Z: -3})
+	if s == nil {
+		t.Fatal("nil pointer to S3D")
+	}
+
+	if got, want := s.Len(), 3; got != want {
+		t.Errorf("got len=%d. want=%d\n", got, want)
+	}
+
+	pt := Point3D{X: 10, Y: -10, Z: 5, ErrX: Range{Min: 5, Max: 5}, ErrY: Range{Min: 6, Max: 6}, ErrZ: Range{Min: 1, Max: 2}}
+	s.Fill(pt)
+
+	if got, want := s.Len(), 4; got != want {
+		t.Errorf("got len=%d. want=%d\n", got, want)
+	}
+
+	if got, want := s.Point(3), pt; got != want {
+		t.Errorf("invalid pt[%d]:\ngot= %+v\nwant=%+v\n", 3, got, want)
+	}
+
+	xmin, xmax, ymin, ymax, zmin, zmax := s.DataRange()
+	if got, want := [6]float64{xmin, xmax, ymin, ymax, zmin, zmax}, [6]float64{-1, 15, -16, 2, -3, 7}; got != want {
+		t.Errorf("invalid data range: got=%v, want=%v", got, want)
+	}
+
+	s.ScaleZ(2)
+	if _, _, z := s.XYZ(3); z != 10 {
+		t.Errorf("invalid scaled z: got=%v, want=%v", z, 10)
+	}
+}
+
+func TestS3DFromH2D(t *testing.T) {
+	h := NewH2D(2, 0, 2, 2, 0, 4)
+	h.Fill(0.5, 0.5, 2)
+	h.Fill(1.5, 3.5, 1)
+	h.Fill(1.5, 3.5, 1)
+
+	s := NewS3DFromH2D(h)
+	if got, want := s.Len(), 4; got != want {
+		t.Fatalf("invalid len: got=%d, want=%d", got, want)
+	}
+	for _, tc := range []struct {
+		i       int
+		x, y, z float64
+	}{
+		{0, 0.5, 1, 1},
+		{1, 0.5, 3, 0},
+		{2, 1.5, 1, 0},
+		{3, 1.5, 3, 1},
+	} {
+		x, y, z := s.XYZ(tc.i)
+		if x != tc.x || y != tc.y || z != tc.z {
+			t.Fatalf("invalid point %d: got=(%v,%v,%v), want=(%v,%v,%v)", tc.i, x, y, z, tc.x, tc.y, tc.z)
+		}
+	}
+}
+
+func newTestS3D() *S3D {
+	s := NewS3D()
+	for i := 0; i < 5; i++ {
+		v := float64(i)
+		s.Fill(Point3D{
+			X: v, Y: -v, Z: v * v,
+			ErrX: Range{Min: 0.5, Max: 0.5},
+			ErrY: Range{Min: 0.5, Max: 0.5},
+			ErrZ: Range{Min: v, Max: 2 * v},
+		})
+	}
+	return s
+}
+
+func TestS3DWriteYODA(t *testing.T) {
+	s := newTestS3D()
+
+	chk, err := s.MarshalYODA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref, err := os.ReadFile("testdata/s3d_v2_golden.yoda")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(chk, ref) {
+		t.Fatalf("s3d file differ:\n%s\n",
+			cmp.Diff(
+				string(ref),
+				string(chk),
+			),
+		)
+	}
+}
+
+func TestS3DReadYODAv1(t *testing.T) {
+	ref, err := os.ReadFile("testdata/s3d_v1_golden.yoda")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s S3D
+	err = s.UnmarshalYODA(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chk, err := s.marshalYODAv1()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(chk, ref) {
+		t.Fatalf("s3d file differ:\n%s\n",
+			cmp.Diff(
+				string(ref),
+				string(chk),
+			),
+		)
+	}
+}
+
+func TestS3DReadYODAv2(t *testing.T) {
+	ref, err := os.ReadFile("testdata/s3d_v2_golden.yoda")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s S3D
+	err = s.UnmarshalYODA(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chk, err := s.marshalYODAv2()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(chk, ref) {
+		t.Fatalf("s3d file differ:\n%s\n",
+			cmp.Diff(
+				string(ref),
+				string(chk),
+			),
+		)
+	}
+}
+
+func TestS3DSerialization(t *testing.T) {
+	sref := newTestS3D()
+	sref.Annotation()["title"] = "s3d title"
+	sref.Annotation()["name"] = "s3d-name"
+
+	buf := new(bytes.Buffer)
+	enc := gob.NewEncoder(buf)
+	err := enc.Encode(sref)
+	if err != nil {
+		t.Fatalf("could not serialize s3d: %v\n", err)
+	}
+
+	var snew S3D
+	dec := gob.NewDecoder(buf)
+	err = dec.Decode(&snew)
+	if err != nil {
+		t.Fatalf("could not deserialize s3d: %v\n", err)
+	}
+
+	if !reflect.DeepEqual(sref, &snew) {
+		t.Fatalf("ref=%v\nnew=%v\n", sref, &snew)
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hbook
+
+import (
+	"fmt"
+)
+
+// ShardedH1D is a 1-dim histogram that can be filled concurrently from
+// multiple goroutines, without locks.
+//
+// Each goroutine fills its own shard, as returned by Shard.
+// Once all the fills are done, Merge sums the shards in shard-index order,
+// so the final histogram does not depend on the scheduling of the goroutines.
+type ShardedH1D struct {
+	h      *H1D
+	shards []*H1D
+}
+
+// NewShardedH1D returns a new sharded 1-dim histogram with n shards.
+// Shards share the binning and annotations of h, but start empty.
+// The content of h is preserved and is part of the merged histogram.
+func NewShardedH1D(n int, h *H1D) *ShardedH1D {
+	if n <= 0 {
+		panic(fmt.Errorf("hbook: invalid number of shards (%d)", n))
+	}
+	s := &ShardedH1D{
+		h:      h.Clone(),
+		shards: make([]*H1D, n),
+	}
+	for i := range s.shards {
+		o := h.Clone()
+		o.Binning.reset()
+		s.shards[i] = o
+	}
+	return s
+}
+
+// NumShards returns the number of shards of this histogram.
+func (s *ShardedH1D) NumShards() int {
+	return len(s.shards)
+}
+
+// Shard returns the i-th shard of this histogram.
+// A shard must only be filled by one goroutine at a time.
+func (s *ShardedH1D) Shard(i int) *H1D {
+	return s.shards[i]
+}
+
+// Merge returns the histogram resulting from the sum of all the shards.
+// Merge must not be called concurrently with fills.
+func (s *ShardedH1D) Merge() *H1D {
+	o := s.h.Clone()
+	for _, h := range s.shards {
+		o.Binning.add(&h.Binning)
+	}
+	return o
+}
+
+// ShardedH2D is a 2-dim histogram that can be filled concurrently from
+// multiple goroutines, without locks.
+//
+// Each goroutine fills its own shard, as returned by Shard.
+// Once all the fills are done, Merge sums the shards in shard-index order,
+// so the final histogram does not depend on the scheduling of the goroutines.
+type ShardedH2D struct {
+	h      *H2D
+	shards []*H2D
+}
+
+// NewShardedH2D returns a new sharded 2-dim histogram with n shards.
+// Shards share the binning and annotations of h, but start empty.
+// The content of h is preserved and is part of the merged histogram.
+func NewShardedH2D(n int, h *H2D) *ShardedH2D {
+	if n <= 0 {
+		panic(fmt.Errorf("hbook: invalid number of shards (%d)", n))
+	}
+	s := &ShardedH2D{
+		h:      h.Clone(),
+		shards: make([]*H2D, n),
+	}
+	for i := range s.shards {
+		o := h.Clone()
+		o.Binning.reset()
+		s.shards[i] = o
+	}
+	return s
+}
+
+// NumShards returns the number of shards of this histogram.
+func (s *ShardedH2D) NumShards() int {
+	return len(s.shards)
+}
+
+// Shard returns the i-th shard of this histogram.
+// A shard must only be filled by one goroutine at a time.
+func (s *ShardedH2D) Shard(i int) *H2D {
+	return s.shards[i]
+}
+
+// Merge returns the histogram resulting from the sum of all the shards.
+// Merge must not be called concurrently with fills.
+func (s *ShardedH2D) Merge() *H2D {
+	o := s.h.Clone()
+	for _, h := range s.shards {
+		o.Binning.add(&h.Binning)
+	}
+	return o
+}
+
+// ShardedP1D is a 1-dim profile histogram that can be filled concurrently
+// from multiple goroutines, without locks.
+//
+// Each goroutine fills its own shard, as returned by Shard.
+// Once all the fills are done, Merge sums the shards in shard-index order,
+// so the final profile does not depend on the scheduling of the goroutines.
+type ShardedP1D struct {
+	p      *P1D
+	shards []*P1D
+}
+
+// NewShardedP1D returns a new sharded 1-dim profile histogram with n shards.
+// Shards share the binning and annotations of p, but start empty.
+// The content of p is preserved and is part of the merged profile.
+func NewShardedP1D(n int, p *P1D) *ShardedP1D {
+	if n <= 0 {
+		panic(fmt.Errorf("hbook: invalid number of shards (%d)", n))
+	}
+	s := &ShardedP1D{
+		p:      p.Clone(),
+		shards: make([]*P1D, n),
+	}
+	for i := range s.shards {
+		o := p.Clone()
+		o.bng.reset()
+		s.shards[i] = o
+	}
+	return s
+}
+
+// NumShards returns the number of shards of this profile.
+func (s *ShardedP1D) NumShards() int {
+	return len(s.shards)
+}
+
+// Shard returns the i-th shard of this profile.
+// A shard must only be filled by one goroutine at a time.
+func (s *ShardedP1D) Shard(i int) *P1D {
+	return s.shards[i]
+}
+
+// Merge returns the profile resulting from the sum of all the shards.
+// Merge must not be called concurrently with fills.
+func (s *ShardedP1D) Merge() *P1D {
+	o := s.p.Clone()
+	for _, p := range s.shards {
+		o.bng.add(&p.bng)
+	}
+	return o
+}
+
+func (bng *Binning1D) reset() {
+	bng.Dist = Dist1D{}
+	bng.Outflows = [2]Dist1D{}
+	for i := range bng.Bins {
+		bng.Bins[i].Dist = Dist1D{}
+	}
+}
+
+func (bng *Binning1D) add(o *Binning1D) {
+	bng.Dist.addScaled(1, 1, o.Dist)
+	for i := range bng.Outflows {
+		bng.Outflows[i].addScaled(1, 1, o.Outflows[i])
+	}
+	for i := range bng.Bins {
+		bng.Bins[i].addScaled(1, 1, o.Bins[i])
+	}
+}
+
+func (bng *Binning2D) reset() {
+	bng.Dist = Dist2D{}
+	bng.Outflows = [8]Dist2D{}
+	for i := range bng.Bins {
+		bng.Bins[i].Dist = Dist2D{}
+	}
+}
+
+func (bng *Binning2D) add(o *Binning2D) {
+	bng.Dist.addScaled(1, 1, o.Dist)
+	for i := range bng.Outflows {
+		bng.Outflows[i].addScaled(1, 1, o.Outflows[i])
+	}
+	for i := range bng.Bins {
+		bng.Bins[i].addScaled(1, 1, o.Bins[i])
+	}
+}
+
+func (bng *binningP1D) reset() {
+	bng.dist = Dist2D{}
+	bng.outflows = [2]Dist2D{}
+	for i := range bng.bins {
+		bng.bins[i].dist = Dist2D{}
+	}
+}
+
+func (bng *binningP1D) add(o *binningP1D) {
+	bng.dist.addScaled(1, 1, o.dist)
+	for i := range bng.outflows {
+		bng.outflows[i].addScaled(1, 1, o.outflows[i])
+	}
+	for i := range bng.bins {
+		bng.bins[i].addScaled(1, 1, o.bins[i])
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hbook
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestShardedHistos(t *testing.T) {
+	const (
+		nshards = 4
+		nevts   = 10000
+	)
+
+	// values and weights are chosen so that sums are exact, whatever
+	// the order of the fills.
+	xval := func(i int) float64 { return float64(i%25) - 2 }
+	yval := func(i int) float64 { return float64(i%7) - 1 }
+	wval := func(i int) float64 { return float64(i%3) + 1 }
+
+	for _, tc := range []struct {
+		name string
+		want func() yodaMarshaler
+		got  func() []yodaMarshaler
+	}{
+		{
+			name: "h1d",
+			want: func() yodaMarshaler {
+				h := NewH1D(20, 0, 20)
+				h.Annotation()["name"] = "h1"
+				for i := 0; i < nevts; i++ {
+					h.Fill(xval(i), wval(i))
+				}
+				return h
+			},
+			got: func() []yodaMarshaler {
+				h := NewH1D(20, 0, 20)
+				h.Annotation()["name"] = "h1"
+				s := NewShardedH1D(nshards, h)
+				fill(s.NumShards(), nevts, func(shard, i int) {
+					s.Shard(shard).Fill(xval(i), wval(i))
+				})
+				return []yodaMarshaler{s.Merge(), s.Merge()}
+			},
+		},
+		{
+			name: "h2d",
+			want: func() yodaMarshaler {
+				h := NewH2D(20, 0, 20, 5, 0, 5)
+				h.Annotation()["name"] = "h2"
+				for i := 0; i < nevts; i++ {
+					h.Fill(xval(i), yval(i), wval(i))
+				}
+				return h
+			},
+			got: func() []yodaMarshaler {
+				h := NewH2D(20, 0, 20, 5, 0, 5)
+				h.Annotation()["name"] = "h2"
+				s := NewShardedH2D(nshards, h)
+				fill(s.NumShards(), nevts, func(shard, i int) {
+					s.Shard(shard).Fill(xval(i), yval(i), wval(i))
+				})
+				return []yodaMarshaler{s.Merge(), s.Merge()}
+			},
+		},
+		{
+			name: "p1d",
+			want: func() yodaMarshaler {
+				p := NewP1D(20, 0, 20)
+				p.Annotation()["name"] = "p1"
+				for i := 0; i < nevts; i++ {
+					p.Fill(xval(i), yval(i), wval(i))
+				}
+				return p
+			},
+			got: func() []yodaMarshaler {
+				p := NewP1D(20, 0, 20)
+				p.Annotation()["name"] = "p1"
+				s := NewShardedP1D(nshards, p)
+				fill(s.NumShards(), nevts, func(shard, i int) {
+					s.Shard(shard).Fill(xval(i), yval(i), wval(i))
+				})
+				return []yodaMarshaler{s.Merge(), s.Merge()}
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			want, err := tc.want().MarshalYODA()
+			if err != nil {
+				t.Fatalf("could not marshal reference: %+v", err)
+			}
+
+			for i, h := range tc.got() {
+				got, err := h.MarshalYODA()
+				if err != nil {
+					t.Fatalf("could not marshal merged histogram: %+v", err)
+				}
+
+				if !bytes.Equal(got, want) {
+					t.Fatalf("invalid merged histogram (merge #%d):\n%s",
+						i, cmp.Diff(string(want), string(got)),
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestShardedH1DKeepsContent(t *testing.T) {
+	h := NewH1D(10, 0, 10)
+	h.Fill(1, 1)
+	h.Fill(-1, 2)
+
+	s := NewShardedH1D(2, h)
+	s.Shard(0).Fill(1, 1)
+	s.Shard(1).Fill(11, 3)
+
+	o := s.Merge()
+	if got, want := o.Entries(), int64(4); got != want {
+		t.Fatalf("invalid entries: got=%d, want=%d", got, want)
+	}
+	if got, want := o.SumW(), 7.0; got != want {
+		t.Fatalf("invalid sumw: got=%v, want=%v", got, want)
+	}
+	if got, want := o.Binning.Bins[1].SumW(), 2.0; got != want {
+		t.Fatalf("invalid bin sumw: got=%v, want=%v", got, want)
+	}
+	if got, want := o.Binning.Underflow().SumW(), 2.0; got != want {
+		t.Fatalf("invalid underflow: got=%v, want=%v", got, want)
+	}
+	if got, want := o.Binning.Overflow().SumW(), 3.0; got != want {
+		t.Fatalf("invalid overflow: got=%v, want=%v", got, want)
+	}
+
+	// filling shards must not modify the original histogram.
+	if got, want := h.Entries(), int64(2); got != want {
+		t.Fatalf("invalid original entries: got=%d, want=%d", got, want)
+	}
+}
+
+type yodaMarshaler interface {
+	MarshalYODA() ([]byte, error)
+}
+
+// fill runs nevts fills over nshards concurrent goroutines.
+func fill(nshards, nevts int, f func(shard, i int)) {
+	var wg sync.WaitGroup
+	wg.Add(nshards)
+	for shard := 0; shard < nshards; shard++ {
+		go func(shard int) {
+			defer wg.Done()
+			for i := shard; i < nevts; i += nshards {
+				f(shard, i)
+			}
+		}(shard)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,160 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"fmt"
+	"math"
+
+	"go-hep.org/x/hep/hbook"
+	"gonum.org/v1/gonum/mat"
+)
+
+// AndersonDarlingH1D performs a 2-sample Anderson-Darling test between the
+// 2 provided 1-dim histograms, as TH1::AndersonDarlingTest does.
+//
+// The statistic is the standardized k-sample Anderson-Darling statistic for
+// binned data (Scholz and Stephens, 1987), where the contents of the bins
+// are considered as (possibly non-integer) counts.
+// The p-value is interpolated from the table of critical values of the
+// standardized statistic: p-values outside of [0.001, 0.25] are extrapolated
+// and should only be considered as indicative.
+//
+// Under- and overflows are not considered.
+func AndersonDarlingH1D(h1, h2 *hbook.H1D) (Result, error) {
+	b1, b2, err := bins1D(h1, h2, false, false)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var (
+		n1 float64
+		n2 float64
+	)
+	for i := range b1 {
+		if b1[i].sumw < 0 || b2[i].sumw < 0 {
+			return Result{}, fmt.Errorf("hbook/stat: bin %d has a negative content", i)
+		}
+		n1 += b1[i].sumw
+		n2 += b2[i].sumw
+	}
+	if n1 == 0 || n2 == 0 {
+		return Result{}, fmt.Errorf("hbook/stat: one of the histograms is empty")
+	}
+
+	n := n1 + n2
+	if n <= 3 {
+		return Result{}, fmt.Errorf("hbook/stat: not enough entries (n=%v)", n)
+	}
+
+	var (
+		a2  float64
+		b   float64    // number of entries before the current bin
+		m   [2]float64 // number of entries of each sample before the current bin
+		ns  = [2]float64{n1, n2}
+		bns = [2][]bin{b1, b2}
+	)
+	for j := range b1 {
+		var (
+			f  = [2]float64{b1[j].sumw, b2[j].sumw}
+			l  = f[0] + f[1]
+			bj = b + 0.5*l
+		)
+		if l > 0 {
+			den := bj*(n-bj) - 0.25*n*l
+			if den > 0 {
+				for i := range bns {
+					mij := m[i] + 0.5*f[i]
+					v := n*mij - bj*ns[i]
+					a2 += l / n * v * v / den / ns[i]
+				}
+			}
+		}
+		b += l
+		m[0] += f[0]
+		m[1] += f[1]
+	}
+	a2 *= (n - 1) / n
+
+	const k = 2
+	var (
+		sig = adVariance(n, ns[:])
+		ta  = (a2 - (k - 1)) / math.Sqrt(sig)
+	)
+
+	res := Result{
+		Stat:   ta,
+		PValue: adProb(ta),
+	}
+	return res, nil
+}
+
+// adVariance returns the variance of the k-sample Anderson-Darling statistic
+// for n entries split into samples of sizes ns.
+func adVariance(n float64, ns []float64) float64 {
+	var (
+		k  = float64(len(ns))
+		nn = int(math.Round(n))
+		hh float64 // sum of 1/ns[i]
+		h  float64 // sum of 1/i, i=1..n-1
+		g  float64 // sum of 1/((n-i)*j), i=1..n-2, j=i+1..n-1
+	)
+	for _, v := range ns {
+		hh += 1 / v
+	}
+	for i := 1; i < nn; i++ {
+		h += 1 / float64(i)
+	}
+	var cs float64 // sum of 1/j, j=i+1..n-1
+	for i := nn - 2; i >= 1; i-- {
+		cs += 1 / float64(i+1)
+		g += cs / float64(nn-i)
+	}
+
+	var (
+		a = (4*g-6)*(k-1) + (10-6*g)*hh
+		b = (2*g-4)*k*k + 8*h*k + (2*g-14*h-4)*hh - 8*h + 4*g - 6
+		c = (6*h+2*g-2)*k*k + (4*h-4*g+6)*k + (2*h-6)*hh + 4*h
+		d = (2*h+6)*k*k - 4*h*k
+	)
+	return (((a*n+b)*n+c)*n + d) / ((n - 1) * (n - 2) * (n - 3))
+}
+
+// adCoeffs holds the coefficients of the quadratic fit of the logarithm of
+// the significance levels as a function of the critical values of the
+// standardized 2-sample Anderson-Darling statistic.
+var adCoeffs = func() [3]float64 {
+	var (
+		b0  = []float64{0.675, 1.281, 1.645, 1.96, 2.326, 2.573, 3.085}
+		b1  = []float64{-0.245, 0.25, 0.678, 1.149, 1.822, 2.364, 3.615}
+		b2  = []float64{-0.105, -0.305, -0.362, -0.391, -0.396, -0.345, -0.154}
+		sig = []float64{0.25, 0.1, 0.05, 0.025, 0.01, 0.005, 0.001}
+
+		x = mat.NewDense(len(sig), 3, nil)
+		y = mat.NewVecDense(len(sig), nil)
+	)
+	const m = 1 // number of samples - 1
+	for i := range sig {
+		crit := b0[i] + b1[i]/math.Sqrt(m) + b2[i]/m
+		x.Set(i, 0, 1)
+		x.Set(i, 1, crit)
+		x.Set(i, 2, crit*crit)
+		y.SetVec(i, math.Log(sig[i]))
+	}
+
+	var c mat.VecDense
+	err := c.SolveVec(x, y)
+	if err != nil {
+		panic(fmt.Errorf("hbook/stat: could not fit Anderson-Darling critical values: %w", err))
+	}
+	return [3]float64{c.AtVec(0), c.AtVec(1), c.AtVec(2)}
+}()
+
+// adProb returns the p-value associated with the standardized 2-sample
+// Anderson-Darling statistic t.
+func adProb(t float64) float64 {
+	p := math.Exp(adCoeffs[0] + t*(adCoeffs[1]+t*adCoeffs[2]))
+	return math.Max(0, math.Min(1, p))
+}
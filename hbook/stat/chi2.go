@@ -0,0 +1,226 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"fmt"
+	"math"
+
+	"go-hep.org/x/hep/hbook"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Chi2Mode describes whether the histograms compared with a chi2 test
+// hold weighted or unweighted entries.
+type Chi2Mode uint8
+
+const (
+	UU Chi2Mode = iota // comparison of 2 unweighted histograms
+	UW                 // comparison of an unweighted histogram with a weighted one
+	WW                 // comparison of 2 weighted histograms
+)
+
+func (m Chi2Mode) String() string {
+	switch m {
+	case UU:
+		return "UU"
+	case UW:
+		return "UW"
+	case WW:
+		return "WW"
+	}
+	return fmt.Sprintf("Chi2Mode(%d)", uint8(m))
+}
+
+// Chi2Opts controls how the chi2 test between 2 histograms is performed.
+type Chi2Opts struct {
+	Mode Chi2Mode // comparison mode (default: UU)
+
+	// Neyman configures the UU comparison to estimate the variance of the
+	// bin contents from the observed contents (Neyman's chi2), instead of
+	// the expected ones (Pearson's chi2).
+	Neyman bool
+
+	Underflow bool // whether to include the underflow bins
+	Overflow  bool // whether to include the overflow bins
+}
+
+// Chi2H1D performs a chi2 test of homogeneity between the 2 provided
+// 1-dim histograms, as TH1::Chi2Test does.
+//
+// In the UW mode, h1 is the unweighted histogram and h2 the weighted one.
+// Chi2H1D optionally takes a Chi2Opts slice: only the first element is considered.
+func Chi2H1D(h1, h2 *hbook.H1D, opts ...Chi2Opts) (Result, error) {
+	var opt Chi2Opts
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	b1, b2, err := bins1D(h1, h2, opt.Underflow, opt.Overflow)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return chi2(b1, b2, opt)
+}
+
+// Chi2H2D performs a chi2 test of homogeneity between the 2 provided
+// 2-dim histograms, as TH2::Chi2Test does.
+//
+// The outflow regions of the histograms are compared as additional bins
+// when the Underflow and/or Overflow options are set: a region is included
+// when all its under- and overflowing dimensions are requested.
+// In the UW mode, h1 is the unweighted histogram and h2 the weighted one.
+// Chi2H2D optionally takes a Chi2Opts slice: only the first element is considered.
+func Chi2H2D(h1, h2 *hbook.H2D, opts ...Chi2Opts) (Result, error) {
+	var opt Chi2Opts
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	b1, b2, err := bins2D(h1, h2)
+	if err != nil {
+		return Result{}, err
+	}
+	o1, o2 := outflows2D(h1, h2, opt.Underflow, opt.Overflow)
+	b1 = append(b1, o1...)
+	b2 = append(b2, o2...)
+
+	return chi2(b1, b2, opt)
+}
+
+func chi2(b1, b2 []bin, opt Chi2Opts) (Result, error) {
+	var (
+		sum1  float64
+		sum2  float64
+		sumw1 float64 // sum of squared weights of h1
+		sumw2 float64 // sum of squared weights of h2
+	)
+	for i := range b1 {
+		sum1 += b1[i].sumw
+		sum2 += b2[i].sumw
+		sumw1 += b1[i].sumw2
+		sumw2 += b2[i].sumw2
+	}
+	if sum1 == 0 || sum2 == 0 {
+		return Result{}, fmt.Errorf("hbook/stat: one of the histograms is empty")
+	}
+	if opt.Mode == WW && sumw1 <= 0 && sumw2 <= 0 {
+		return Result{}, fmt.Errorf("hbook/stat: both histograms have zero errors")
+	}
+
+	var (
+		ndf = len(b1) - 1
+		chi = 0.0
+	)
+	switch opt.Mode {
+	case UU:
+		for i := range b1 {
+			var (
+				cnt1 = b1[i].sumw
+				cnt2 = b2[i].sumw
+			)
+			if cnt1*cnt1 == 0 && cnt2*cnt2 == 0 {
+				ndf--
+				continue
+			}
+			delta := sum2*cnt1 - sum1*cnt2
+			switch {
+			case opt.Neyman:
+				chi += delta * delta / (sum2*sum2*cnt1 + sum1*sum1*cnt2)
+			default:
+				chi += delta * delta / (cnt1 + cnt2)
+			}
+		}
+		if !opt.Neyman {
+			chi /= sum1 * sum2
+		}
+
+	case UW:
+		for i := range b1 {
+			var (
+				cnt1 = b1[i].sumw
+				cnt2 = b2[i].sumw
+				e2sq = b2[i].sumw2
+			)
+			if cnt1*cnt1 == 0 && cnt2*cnt2 == 0 {
+				ndf--
+				continue
+			}
+			if cnt2*cnt2 == 0 && e2sq == 0 {
+				if sumw2 <= 0 {
+					return Result{}, fmt.Errorf("hbook/stat: weighted histogram has bin %d with zero content and error", i)
+				}
+				// approximate the error from the total sum of weights and
+				// sum of squared weights.
+				e2sq = sumw2 / sum2
+			}
+
+			var (
+				var1 = sum2*cnt2 - sum1*e2sq
+				var2 = var1*var1 + 4*sum2*sum2*cnt1*e2sq
+			)
+			// as TH1::Chi2TestX does, an empty unweighted bin is
+			// approximated by adding 1 to its content. The total of the
+			// unweighted histogram is updated as well, and that update
+			// carries over to the following bins.
+			for var1*var1+cnt1 == 0 || var1+var2 == 0 {
+				sum1++
+				cnt1++
+				var1 = sum2*cnt2 - sum1*e2sq
+				var2 = var1*var1 + 4*sum2*sum2*cnt1*e2sq
+			}
+			var2 = math.Sqrt(var2)
+
+			var (
+				probb  = (var1 + var2) / (2 * sum2 * sum2)
+				nexp1  = probb * sum1
+				nexp2  = probb * sum2
+				delta1 = cnt1 - nexp1
+				delta2 = cnt2 - nexp2
+			)
+			chi += delta1 * delta1 / nexp1
+			if e2sq > 0 {
+				chi += delta2 * delta2 / e2sq
+			}
+		}
+
+	case WW:
+		for i := range b1 {
+			var (
+				cnt1 = b1[i].sumw
+				cnt2 = b2[i].sumw
+				e1sq = b1[i].sumw2
+				e2sq = b2[i].sumw2
+			)
+			if cnt1*cnt1 == 0 && cnt2*cnt2 == 0 {
+				ndf--
+				continue
+			}
+			if e1sq == 0 && e2sq == 0 {
+				return Result{}, fmt.Errorf("hbook/stat: both histograms have bin %d with zero errors", i)
+			}
+			var (
+				sigma = sum1*sum1*e2sq + sum2*sum2*e1sq
+				delta = sum2*cnt1 - sum1*cnt2
+			)
+			chi += delta * delta / sigma
+		}
+
+	default:
+		return Result{}, fmt.Errorf("hbook/stat: invalid chi2 mode %v", opt.Mode)
+	}
+
+	if ndf <= 0 {
+		return Result{}, fmt.Errorf("hbook/stat: invalid number of degrees of freedom (ndf=%d)", ndf)
+	}
+
+	res := Result{
+		Stat:   chi,
+		NDF:    ndf,
+		PValue: distuv.ChiSquared{K: float64(ndf)}.Survival(chi),
+	}
+	return res, nil
+}
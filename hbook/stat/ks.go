@@ -0,0 +1,195 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"fmt"
+	"math"
+
+	"go-hep.org/x/hep/hbook"
+)
+
+// KSOpts controls how the Kolmogorov-Smirnov test between 2 histograms
+// is performed.
+type KSOpts struct {
+	Underflow bool // whether to include the underflow bins (1-dim histograms only)
+	Overflow  bool // whether to include the overflow bins (1-dim histograms only)
+}
+
+// KolmogorovH1D performs a Kolmogorov-Smirnov test between the 2 provided
+// 1-dim histograms, as TH1::KolmogorovTest does.
+//
+// The returned statistic is the maximum distance between the cumulative
+// distributions of the histograms, and the p-value is computed from the
+// effective number of entries of the histograms.
+// KolmogorovH1D optionally takes a KSOpts slice: only the first element is considered.
+func KolmogorovH1D(h1, h2 *hbook.H1D, opts ...KSOpts) (Result, error) {
+	var opt KSOpts
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	b1, b2, err := bins1D(h1, h2, opt.Underflow, opt.Overflow)
+	if err != nil {
+		return Result{}, err
+	}
+
+	ks, err := newKSTest(b1, b2)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var (
+		s1    = 1 / ks.sum1
+		s2    = 1 / ks.sum2
+		rsum1 float64
+		rsum2 float64
+		dmax  float64
+	)
+	for i := range b1 {
+		rsum1 += s1 * b1[i].sumw
+		rsum2 += s2 * b2[i].sumw
+		dmax = math.Max(dmax, math.Abs(rsum1-rsum2))
+	}
+
+	res := Result{
+		Stat:   dmax,
+		PValue: kolmogorovProb(dmax * ks.norm),
+	}
+	return res, nil
+}
+
+// KolmogorovH2D performs a Kolmogorov-Smirnov test between the 2 provided
+// 2-dim histograms, as TH2::KolmogorovTest does.
+//
+// The cumulative distributions of the histograms are computed twice,
+// integrating along x then y, and along y then x.
+// The returned statistic is the largest of the 2 maximum distances, and the
+// p-value is the average of the 2 corresponding probabilities.
+// Under- and overflows are not considered.
+func KolmogorovH2D(h1, h2 *hbook.H2D) (Result, error) {
+	b1, b2, err := bins2D(h1, h2)
+	if err != nil {
+		return Result{}, err
+	}
+
+	ks, err := newKSTest(b1, b2)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var (
+		nx = h1.Binning.Nx
+		ny = h1.Binning.Ny
+		s1 = 1 / ks.sum1
+		s2 = 1 / ks.sum2
+	)
+
+	dmax := func(outer, inner int, index func(i, j int) int) float64 {
+		var rsum1, rsum2, dmax float64
+		for i := 0; i < outer; i++ {
+			for j := 0; j < inner; j++ {
+				k := index(i, j)
+				rsum1 += s1 * b1[k].sumw
+				rsum2 += s2 * b2[k].sumw
+				dmax = math.Max(dmax, math.Abs(rsum1-rsum2))
+			}
+		}
+		return dmax
+	}
+
+	var (
+		d1 = dmax(nx, ny, func(ix, iy int) int { return iy*nx + ix })
+		d2 = dmax(ny, nx, func(iy, ix int) int { return iy*nx + ix })
+	)
+
+	res := Result{
+		Stat:   math.Max(d1, d2),
+		PValue: 0.5 * (kolmogorovProb(d1*ks.norm) + kolmogorovProb(d2*ks.norm)),
+	}
+	return res, nil
+}
+
+type ksTest struct {
+	sum1 float64 // sum of weights of h1
+	sum2 float64 // sum of weights of h2
+	norm float64 // normalization factor of the maximum distance
+}
+
+func newKSTest(b1, b2 []bin) (ksTest, error) {
+	var (
+		ks ksTest
+		w1 float64
+		w2 float64
+	)
+	for i := range b1 {
+		ks.sum1 += b1[i].sumw
+		ks.sum2 += b2[i].sumw
+		w1 += b1[i].sumw2
+		w2 += b2[i].sumw2
+	}
+	if ks.sum1 == 0 || ks.sum2 == 0 {
+		return ks, fmt.Errorf("hbook/stat: one of the histograms is empty")
+	}
+
+	// histograms without errors are treated as functions.
+	var (
+		afunc1 = w1 <= 0
+		afunc2 = w2 <= 0
+		esum1  = ks.sum1
+		esum2  = ks.sum2
+	)
+	if !afunc1 {
+		esum1 = ks.sum1 * ks.sum1 / w1
+	}
+	if !afunc2 {
+		esum2 = ks.sum2 * ks.sum2 / w2
+	}
+
+	switch {
+	case afunc1 && afunc2:
+		return ks, fmt.Errorf("hbook/stat: both histograms have no errors")
+	case afunc1:
+		ks.norm = math.Sqrt(esum2)
+	case afunc2:
+		ks.norm = math.Sqrt(esum1)
+	default:
+		ks.norm = math.Sqrt(esum1 * esum2 / (esum1 + esum2))
+	}
+	return ks, nil
+}
+
+// kolmogorovProb returns the probability of the Kolmogorov distribution
+// to exceed z, as TMath::KolmogorovProb does.
+func kolmogorovProb(z float64) float64 {
+	const (
+		w  = 2.50662827
+		c1 = -1.2337005501361697 // -pi²/8
+		c2 = 9 * c1
+		c3 = 25 * c1
+	)
+
+	u := math.Abs(z)
+	switch {
+	case u < 0.2:
+		return 1
+	case u < 0.755:
+		v := 1 / (u * u)
+		return 1 - w*(math.Exp(c1*v)+math.Exp(c2*v)+math.Exp(c3*v))/u
+	case u < 6.8116:
+		var (
+			fj   = [4]float64{-2, -8, -18, -32}
+			r    [4]float64
+			v    = u * u
+			maxj = int(math.Max(1, math.Round(3/u)))
+		)
+		for j := 0; j < maxj && j < len(r); j++ {
+			r[j] = math.Exp(fj[j] * v)
+		}
+		return 2 * (r[0] - r[1] + r[2] - r[3])
+	default:
+		return 0
+	}
+}
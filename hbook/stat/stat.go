@@ -0,0 +1,134 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package stat provides statistical tests to compare histograms.
+//
+// The Kolmogorov-Smirnov, chi2 and Anderson-Darling tests follow the
+// semantics of their ROOT counterparts (TH1::KolmogorovTest,
+// TH1::Chi2Test and TH1::AndersonDarlingTest.)
+package stat // import "go-hep.org/x/hep/hbook/stat"
+
+import (
+	"fmt"
+	"math"
+
+	"go-hep.org/x/hep/hbook"
+)
+
+// Result holds the outcome of a statistical test.
+type Result struct {
+	Stat   float64 // test statistic
+	NDF    int     // number of degrees of freedom, if any
+	PValue float64 // p-value of the test
+}
+
+// bin is the content of a bin of a histogram.
+type bin struct {
+	sumw  float64
+	sumw2 float64
+}
+
+// bins1D returns the contents of the bins of the 1-dim histograms.
+// The underflow and overflow bins are prepended and appended, if requested.
+func bins1D(h1, h2 *hbook.H1D, uflow, oflow bool) (b1, b2 []bin, err error) {
+	var (
+		bins1 = h1.Binning.Bins
+		bins2 = h2.Binning.Bins
+	)
+	if len(bins1) != len(bins2) {
+		return nil, nil, fmt.Errorf("hbook/stat: histograms have different number of bins (%d != %d)", len(bins1), len(bins2))
+	}
+	for i := range bins1 {
+		if !fuzzyEq(bins1[i].XMin(), bins2[i].XMin()) || !fuzzyEq(bins1[i].XMax(), bins2[i].XMax()) {
+			return nil, nil, fmt.Errorf("hbook/stat: histograms have different binning (bin %d)", i)
+		}
+	}
+
+	n := len(bins1) + 2
+	b1 = make([]bin, 0, n)
+	b2 = make([]bin, 0, n)
+	if uflow {
+		b1 = append(b1, bin1D(h1.Binning.Underflow()))
+		b2 = append(b2, bin1D(h2.Binning.Underflow()))
+	}
+	for i := range bins1 {
+		b1 = append(b1, bin1D(&bins1[i].Dist))
+		b2 = append(b2, bin1D(&bins2[i].Dist))
+	}
+	if oflow {
+		b1 = append(b1, bin1D(h1.Binning.Overflow()))
+		b2 = append(b2, bin1D(h2.Binning.Overflow()))
+	}
+	return b1, b2, nil
+}
+
+// bins2D returns the contents of the in-range bins of the 2-dim histograms,
+// in the order of hbook.Binning2D.Bins.
+func bins2D(h1, h2 *hbook.H2D) (b1, b2 []bin, err error) {
+	var (
+		bng1 = &h1.Binning
+		bng2 = &h2.Binning
+	)
+	if bng1.Nx != bng2.Nx || bng1.Ny != bng2.Ny {
+		return nil, nil, fmt.Errorf(
+			"hbook/stat: histograms have different number of bins (%dx%d != %dx%d)",
+			bng1.Nx, bng1.Ny, bng2.Nx, bng2.Ny,
+		)
+	}
+	b1 = make([]bin, len(bng1.Bins))
+	b2 = make([]bin, len(bng2.Bins))
+	for i := range bng1.Bins {
+		var (
+			x1 = &bng1.Bins[i]
+			x2 = &bng2.Bins[i]
+		)
+		if !fuzzyEq(x1.XMin(), x2.XMin()) || !fuzzyEq(x1.XMax(), x2.XMax()) ||
+			!fuzzyEq(x1.YMin(), x2.YMin()) || !fuzzyEq(x1.YMax(), x2.YMax()) {
+			return nil, nil, fmt.Errorf("hbook/stat: histograms have different binning (bin %d)", i)
+		}
+		b1[i] = bin{sumw: x1.SumW(), sumw2: x1.SumW2()}
+		b2[i] = bin{sumw: x2.SumW(), sumw2: x2.SumW2()}
+	}
+	return b1, b2, nil
+}
+
+// outflows2D returns the contents of the outflow regions of the 2-dim
+// histograms selected by the provided underflow and overflow flags.
+func outflows2D(h1, h2 *hbook.H2D, uflow, oflow bool) (b1, b2 []bin) {
+	for i, v := range [...]struct{ under, over bool }{
+		hbook.BngNW - 1: {under: true, over: true},
+		hbook.BngN - 1:  {over: true},
+		hbook.BngNE - 1: {over: true},
+		hbook.BngE - 1:  {over: true},
+		hbook.BngSE - 1: {under: true, over: true},
+		hbook.BngS - 1:  {under: true},
+		hbook.BngSW - 1: {under: true},
+		hbook.BngW - 1:  {under: true},
+	} {
+		if (v.under && !uflow) || (v.over && !oflow) {
+			continue
+		}
+		b1 = append(b1, bin2D(&h1.Binning.Outflows[i]))
+		b2 = append(b2, bin2D(&h2.Binning.Outflows[i]))
+	}
+	return b1, b2
+}
+
+func bin1D(d *hbook.Dist1D) bin {
+	return bin{sumw: d.SumW(), sumw2: d.SumW2()}
+}
+
+func bin2D(d *hbook.Dist2D) bin {
+	return bin{sumw: d.SumW(), sumw2: d.SumW2()}
+}
+
+// fuzzyEq returns true if a and b are equal with a degree of fuzziness
+func fuzzyEq(a, b float64) bool {
+	const tol = 1e-5
+	aa := math.Abs(a)
+	bb := math.Abs(b)
+	absavg := 0.5 * (aa + bb)
+	absdiff := math.Abs(a - b)
+	return (aa < 1e-8 && bb < 1e-8) || absdiff < tol*absavg
+}
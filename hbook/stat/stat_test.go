@@ -0,0 +1,350 @@
+// Copyright ©2022 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"testing"
+
+	"go-hep.org/x/hep/hbook"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func newH1D(counts ...int) *hbook.H1D {
+	h := hbook.NewH1D(len(counts), 0, float64(len(counts)))
+	for i, n := range counts {
+		for j := 0; j < n; j++ {
+			h.Fill(float64(i)+0.5, 1)
+		}
+	}
+	return h
+}
+
+func newH2D(counts [][]int) *hbook.H2D {
+	var (
+		ny = len(counts)
+		nx = len(counts[0])
+		h  = hbook.NewH2D(nx, 0, float64(nx), ny, 0, float64(ny))
+	)
+	for iy, row := range counts {
+		for ix, n := range row {
+			for j := 0; j < n; j++ {
+				h.Fill(float64(ix)+0.5, float64(iy)+0.5, 1)
+			}
+		}
+	}
+	return h
+}
+
+func TestChi2H1D(t *testing.T) {
+	var (
+		h1 = newH1D(10, 20, 30)
+		h2 = newH1D(20, 20, 20)
+	)
+
+	for _, tc := range []struct {
+		name string
+		h1   *hbook.H1D
+		h2   *hbook.H1D
+		opt  Chi2Opts
+		want Result
+	}{
+		{
+			name: "uu",
+			h1:   h1, h2: h2,
+			want: Result{Stat: 16.0 / 3, NDF: 2, PValue: math.Exp(-8.0 / 3)},
+		},
+		{
+			name: "uu-neyman",
+			h1:   h1, h2: h2,
+			opt:  Chi2Opts{Neyman: true},
+			want: Result{Stat: 16.0 / 3, NDF: 2, PValue: math.Exp(-8.0 / 3)},
+		},
+		{
+			name: "ww",
+			h1:   h1, h2: h2,
+			opt:  Chi2Opts{Mode: WW},
+			want: Result{Stat: 16.0 / 3, NDF: 2, PValue: math.Exp(-8.0 / 3)},
+		},
+		{
+			name: "uw-same",
+			h1:   h1, h2: h1,
+			opt:  Chi2Opts{Mode: UW},
+			want: Result{Stat: 0, NDF: 2, PValue: 1},
+		},
+		{
+			// h1 has an empty bin with sum1 == sum2: that bin is corrected,
+			// and the total of h1 is raised from 2 to 3 for the next bins,
+			// as TH1::Chi2TestX does.
+			name: "uw-empty-bin",
+			h1:   newH1D(0, 2),
+			h2:   newH1D(1, 1),
+			opt:  Chi2Opts{Mode: UW},
+			want: Result{
+				Stat:   0.13512141841003064,
+				NDF:    1,
+				PValue: distuv.ChiSquared{K: 1}.Survival(0.13512141841003064),
+			},
+		},
+		{
+			name: "uu-empty-bins",
+			h1:   newH1D(10, 0, 20, 30),
+			h2:   newH1D(20, 0, 20, 20),
+			want: Result{Stat: 16.0 / 3, NDF: 2, PValue: math.Exp(-8.0 / 3)},
+		},
+		{
+			name: "uu-outflows",
+			h1: func() *hbook.H1D {
+				h := newH1D(10, 20, 30)
+				h.Fill(-1, 1)
+				h.Fill(+4, 1)
+				return h
+			}(),
+			h2: func() *hbook.H1D {
+				h := newH1D(10, 20, 30)
+				h.Fill(-1, 1)
+				h.Fill(+4, 1)
+				return h
+			}(),
+			opt:  Chi2Opts{Underflow: true, Overflow: true},
+			want: Result{Stat: 0, NDF: 4, PValue: 1},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Chi2H1D(tc.h1, tc.h2, tc.opt)
+			if err != nil {
+				t.Fatalf("could not run chi2 test: %+v", err)
+			}
+			if !cmpResult(got, tc.want) {
+				t.Fatalf("invalid result:\ngot= %+v\nwant=%+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestChi2H2D(t *testing.T) {
+	var (
+		h1 = newH2D([][]int{{10, 20}, {30, 0}})
+		h2 = newH2D([][]int{{20, 20}, {20, 0}})
+	)
+	got, err := Chi2H2D(h1, h2)
+	if err != nil {
+		t.Fatalf("could not run chi2 test: %+v", err)
+	}
+	want := Result{Stat: 16.0 / 3, NDF: 2, PValue: math.Exp(-8.0 / 3)}
+	if !cmpResult(got, want) {
+		t.Fatalf("invalid result:\ngot= %+v\nwant=%+v", got, want)
+	}
+
+	h1.Fill(-1, -1, 1)
+	h2.Fill(-1, -1, 1)
+	h1.Fill(+3, +3, 1)
+	h2.Fill(+3, +3, 1)
+
+	got, err = Chi2H2D(h1, h2, Chi2Opts{Underflow: true})
+	if err != nil {
+		t.Fatalf("could not run chi2 test: %+v", err)
+	}
+	want.NDF = 3 // SW region
+	want.PValue = distuv.ChiSquared{K: 3}.Survival(want.Stat)
+	if !cmpResult(got, want) {
+		t.Fatalf("invalid result:\ngot= %+v\nwant=%+v", got, want)
+	}
+}
+
+func TestChi2Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		h1   *hbook.H1D
+		h2   *hbook.H1D
+		opt  Chi2Opts
+	}{
+		{
+			name: "nbins",
+			h1:   newH1D(1, 2, 3),
+			h2:   newH1D(1, 2),
+		},
+		{
+			name: "binning",
+			h1:   newH1D(1, 2),
+			h2:   hbook.NewH1D(2, 0, 4),
+		},
+		{
+			name: "empty",
+			h1:   newH1D(1, 2),
+			h2:   newH1D(0, 0),
+		},
+		{
+			name: "ww-no-errors",
+			h1: func() *hbook.H1D {
+				h := newH1D(1, 2)
+				h.Binning.Bins[0].Dist.Dist.SumW2 = 0
+				h.Binning.Bins[1].Dist.Dist.SumW2 = 0
+				return h
+			}(),
+			h2: func() *hbook.H1D {
+				h := newH1D(1, 2)
+				h.Binning.Bins[0].Dist.Dist.SumW2 = 0
+				h.Binning.Bins[1].Dist.Dist.SumW2 = 0
+				return h
+			}(),
+			opt: Chi2Opts{Mode: WW},
+		},
+		{
+			name: "invalid-mode",
+			h1:   newH1D(1, 2),
+			h2:   newH1D(1, 2),
+			opt:  Chi2Opts{Mode: 42},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Chi2H1D(tc.h1, tc.h2, tc.opt)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
+
+func TestKolmogorovH1D(t *testing.T) {
+	var (
+		h1 = newH1D(10, 20, 30)
+		h2 = newH1D(20, 20, 20)
+	)
+
+	got, err := KolmogorovH1D(h1, h2)
+	if err != nil {
+		t.Fatalf("could not run KS test: %+v", err)
+	}
+	want := Result{Stat: 1.0 / 6, PValue: 0.37520654987708507}
+	if !cmpResult(got, want) {
+		t.Fatalf("invalid result:\ngot= %+v\nwant=%+v", got, want)
+	}
+
+	got, err = KolmogorovH1D(h1, h1)
+	if err != nil {
+		t.Fatalf("could not run KS test: %+v", err)
+	}
+	want = Result{Stat: 0, PValue: 1}
+	if !cmpResult(got, want) {
+		t.Fatalf("invalid result:\ngot= %+v\nwant=%+v", got, want)
+	}
+
+	// h2 is considered as a function: only the entries of h1 matter.
+	h3 := hbook.NewH1D(3, 0, 3)
+	for i, w := range []float64{1, 1, 1} {
+		h3.Fill(float64(i)+0.5, w)
+	}
+	h3.Binning.Bins[0].Dist.Dist.SumW2 = 0
+	h3.Binning.Bins[1].Dist.Dist.SumW2 = 0
+	h3.Binning.Bins[2].Dist.Dist.SumW2 = 0
+
+	got, err = KolmogorovH1D(h1, h3)
+	if err != nil {
+		t.Fatalf("could not run KS test: %+v", err)
+	}
+	want = Result{Stat: 1.0 / 6, PValue: kolmogorovProb(math.Sqrt(60) / 6)}
+	if !cmpResult(got, want) {
+		t.Fatalf("invalid result:\ngot= %+v\nwant=%+v", got, want)
+	}
+
+	_, err = KolmogorovH1D(h3, h3)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestKolmogorovH2D(t *testing.T) {
+	var (
+		h1 = newH2D([][]int{{10, 20}, {30, 0}})
+		h2 = newH2D([][]int{{20, 20}, {20, 0}})
+	)
+
+	got, err := KolmogorovH2D(h1, h2)
+	if err != nil {
+		t.Fatalf("could not run KS test: %+v", err)
+	}
+	// x-then-y: cumulative 1/6, 1/2, 1, 1 vs 1/3, 2/3, 1, 1
+	// y-then-x: cumulative 1/6, 2/3, 1, 1 vs 1/3, 2/3, 1, 1
+	want := Result{
+		Stat:   1.0 / 6,
+		PValue: kolmogorovProb(math.Sqrt(30) / 6),
+	}
+	if !cmpResult(got, want) {
+		t.Fatalf("invalid result:\ngot= %+v\nwant=%+v", got, want)
+	}
+}
+
+func TestKolmogorovProb(t *testing.T) {
+	for _, tc := range []struct {
+		z    float64
+		want float64
+	}{
+		{0, 1},
+		{0.1, 1},
+		{0.5, 0.9639452436648751},
+		{1, 0.26999967167735456},
+		{1.3580986393225505, 0.05},
+		{2, 0.0006709252557796953},
+		{7, 0},
+	} {
+		got := kolmogorovProb(tc.z)
+		if !scalar.EqualWithinAbs(got, tc.want, 1e-6) {
+			t.Errorf("invalid K(%v): got=%v, want=%v", tc.z, got, tc.want)
+		}
+	}
+}
+
+func TestAndersonDarlingH1D(t *testing.T) {
+	var (
+		h1 = newH1D(10, 20, 30, 40, 30, 20, 10)
+		h2 = newH1D(11, 19, 31, 39, 29, 21, 10)
+		h3 = newH1D(40, 30, 20, 10, 10, 20, 30)
+	)
+
+	same, err := AndersonDarlingH1D(h1, h2)
+	if err != nil {
+		t.Fatalf("could not run AD test: %+v", err)
+	}
+	if same.Stat > 0 || same.PValue < 0.25 {
+		t.Fatalf("invalid result for compatible histograms: %+v", same)
+	}
+
+	diff, err := AndersonDarlingH1D(h1, h3)
+	if err != nil {
+		t.Fatalf("could not run AD test: %+v", err)
+	}
+	if diff.Stat < 6.546 || diff.PValue > 0.001 {
+		t.Fatalf("invalid result for incompatible histograms: %+v", diff)
+	}
+
+	// check the interpolation of the p-value at the tabulated critical values.
+	for _, tc := range []struct {
+		t float64
+		p float64
+	}{
+		{0.325, 0.25},
+		{1.960, 0.05},
+		{4.592, 0.005},
+	} {
+		got := adProb(tc.t)
+		if !scalar.EqualWithinRel(got, tc.p, 0.1) {
+			t.Errorf("invalid p-value for t=%v: got=%v, want=%v", tc.t, got, tc.p)
+		}
+	}
+
+	_, err = AndersonDarlingH1D(newH1D(1, 1), newH1D(0, 1))
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func cmpResult(a, b Result) bool {
+	const tol = 1e-9
+	return scalar.EqualWithinAbsOrRel(a.Stat, b.Stat, tol, tol) &&
+		a.NDF == b.NDF &&
+		scalar.EqualWithinAbsOrRel(a.PValue, b.PValue, tol, tol)
+}
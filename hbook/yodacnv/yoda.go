@@ -112,16 +112,18 @@ func splitHeader(raw []byte) (reflect.Type, error) {
 		rt = reflect.TypeOf((*hbook.H1D)(nil)).Elem()
 	case "HISTO2D", "HISTO2D_V2":
 		rt = reflect.TypeOf((*hbook.H2D)(nil)).Elem()
+	case "HISTO3D", "HISTO3D_V2":
+		rt = reflect.TypeOf((*hbook.H3D)(nil)).Elem()
 	case "PROFILE1D", "PROFILE1D_V2":
 		rt = reflect.TypeOf((*hbook.P1D)(nil)).Elem()
 	case "PROFILE2D", "PROFILE2D_V2":
-		return nil, errIgnore
+		rt = reflect.TypeOf((*hbook.P2D)(nil)).Elem()
 	case "SCATTER1D", "SCATTER1D_V2":
 		return nil, errIgnore
 	case "SCATTER2D", "SCATTER2D_V2":
 		rt = reflect.TypeOf((*hbook.S2D)(nil)).Elem()
 	case "SCATTER3D", "SCATTER3D_V2":
-		return nil, errIgnore
+		rt = reflect.TypeOf((*hbook.S3D)(nil)).Elem()
 	case "COUNTER", "COUNTER_V2":
 		return nil, errIgnore
 	default:
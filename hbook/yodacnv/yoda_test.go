@@ -19,6 +19,9 @@ var (
 	h2    *hbook.H2D
 	p1    *hbook.P1D
 	s2    *hbook.S2D
+	h3    *hbook.H3D
+	p2    *hbook.P2D
+	s3    *hbook.S3D
 )
 
 func TestReadWrite(t *testing.T) {
@@ -81,6 +84,68 @@ END YODA_SCATTER1D
 	}
 }
 
+func TestReadProfile2DScatter3D(t *testing.T) {
+	r := bytes.NewReader([]byte(`BEGIN YODA_PROFILE2D /p2
+Path=/p2
+Title=
+Type=Profile2D
+# Mean: (2.500000e-01, 5.000000e-01)
+# ID	 ID	 sumw	 sumw2	 sumwx	 sumwx2	 sumwy	 sumwy2	 sumwz	 sumwz2	 sumwxy	 numEntries
+Total   	Total   	2.000000e+00	2.000000e+00	5.000000e-01	2.500000e-01	1.000000e+00	1.000000e+00	3.000000e+00	5.000000e+00	2.500000e-01	2
+# 2D outflow persistency not currently supported until API is stable
+# xlow	 xhigh	 ylow	 yhigh	 sumw	 sumw2	 sumwx	 sumwx2	 sumwy	 sumwy2	 sumwz	 sumwz2	 sumwxy	 numEntries
+0.000000e+00	5.000000e-01	0.000000e+00	1.000000e+00	1.000000e+00	1.000000e+00	2.500000e-01	6.250000e-02	5.000000e-01	2.500000e-01	1.000000e+00	1.000000e+00	1.250000e-01	1
+0.000000e+00	5.000000e-01	1.000000e+00	2.000000e+00	0.000000e+00	0.000000e+00	0.000000e+00	0.000000e+00	0.000000e+00	0.000000e+00	0.000000e+00	0.000000e+00	0.000000e+00	0
+5.000000e-01	1.000000e+00	0.000000e+00	1.000000e+00	1.000000e+00	1.000000e+00	2.500000e-01	6.250000e-02	5.000000e-01	2.500000e-01	2.000000e+00	4.000000e+00	1.250000e-01	1
+5.000000e-01	1.000000e+00	1.000000e+00	2.000000e+00	0.000000e+00	0.000000e+00	0.000000e+00	0.000000e+00	0.000000e+00	0.000000e+00	0.000000e+00	0.000000e+00	0.000000e+00	0
+END YODA_PROFILE2D
+
+BEGIN YODA_SCATTER3D /s3
+Path=/s3
+Title=
+Type=Scatter3D
+# xval	 xerr-	 xerr+	 yval	 yerr-	 yerr+	 zval	 zerr-	 zerr+
+1.000000e+00	5.000000e-01	5.000000e-01	2.000000e+00	5.000000e-01	5.000000e-01	3.000000e+00	1.000000e-01	1.000000e-01
+END YODA_SCATTER3D
+`))
+
+	objs, err := yodacnv.Read(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(objs) != 2 {
+		t.Fatalf("got %d values. want %d", len(objs), 2)
+	}
+
+	p, ok := objs[0].(*hbook.P2D)
+	if !ok {
+		t.Fatalf("invalid type: got=%T, want=*hbook.P2D", objs[0])
+	}
+	if got, want := p.Entries(), int64(2); got != want {
+		t.Fatalf("invalid P2D entries: got=%d, want=%d", got, want)
+	}
+	if got, want := len(p.Binning().Bins()), 4; got != want {
+		t.Fatalf("invalid P2D bins: got=%d, want=%d", got, want)
+	}
+	if got, want := p.Binning().Bins()[1].ZMean(), 2.0; got != want {
+		t.Fatalf("invalid P2D bin: got=%v, want=%v", got, want)
+	}
+
+	s, ok := objs[1].(*hbook.S3D)
+	if !ok {
+		t.Fatalf("invalid type: got=%T, want=*hbook.S3D", objs[1])
+	}
+	if got, want := s.Point(0), (hbook.Point3D{
+		X: 1, Y: 2, Z: 3,
+		ErrX: hbook.Range{Min: 0.5, Max: 0.5},
+		ErrY: hbook.Range{Min: 0.5, Max: 0.5},
+		ErrZ: hbook.Range{Min: 0.1, Max: 0.1},
+	}); got != want {
+		t.Fatalf("invalid S3D point: got=%#v, want=%#v", got, want)
+	}
+}
+
 func init() {
 
 	add := func(o yodacnv.Marshaler) {
@@ -123,4 +188,27 @@ func init() {
 
 	s2 = hbook.NewS2DFromH1D(h1)
 	add(s2)
+
+	h3 = hbook.NewH3D(2, -1, 1, 3, -2, +2, 2, 0, 10)
+	h3.Annotation()["name"] = "histo-3d"
+	h3.Fill(+0.5, +1, 2, 1)
+	h3.Fill(-0.5, +1, 8, 2)
+	h3.Fill(+0.0, -1, 5, 1)
+	h3.Fill(+0.0, -1, 50, 1)
+
+	add(h3)
+
+	p2 = hbook.NewP2D(2, -1, 1, 3, -2, +2)
+	p2.Annotation()["name"] = "profile-2d"
+	for i := 0; i < 10; i++ {
+		v := float64(i)
+		p2.Fill(v/10-0.5, v/5-1, v*2, 1)
+	}
+	p2.Fill(-10, 0, 10, 1)
+
+	add(p2)
+
+	s3 = hbook.NewS3DFromP2D(p2)
+	s3.Annotation()["name"] = "scatter-3d"
+	add(s3)
 }